@@ -0,0 +1,35 @@
+package arxml
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Decode reads an ARXML file from the given reader.
+func Decode(r io.Reader) (*AUTOSAR, error) {
+	root := new(AUTOSAR)
+	if err := xml.NewDecoder(r).Decode(root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// Encode writes the given file as an indented ARXML file
+// into the given writer. The namespace of the schema is always set.
+func Encode(w io.Writer, root *AUTOSAR) error {
+	tmp := *root
+	tmp.Xmlns = Namespace
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&tmp); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,102 @@
+package arxml
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeTestFile(assert *assert.Assertions) *AUTOSAR {
+	file, err := os.Open("testdata/sample.arxml")
+	assert.NoError(err)
+	defer file.Close()
+
+	root, err := Decode(file)
+	assert.NoError(err)
+
+	return root
+}
+
+func Test_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	root := decodeTestFile(assert)
+	assert.Len(root.Packages, 1)
+
+	networkPkg := root.Packages[0]
+	assert.Equal("Network", networkPkg.ShortName)
+	assert.Len(networkPkg.Packages, 10)
+
+	clusterPkg := networkPkg.Packages[0]
+	assert.Len(clusterPkg.Elements.CANClusters, 1)
+	assert.Len(clusterPkg.Elements.Others, 1)
+	assert.Equal("LIN-CLUSTER", clusterPkg.Elements.Others[0].XMLName.Local)
+	assert.Equal("body", clusterPkg.Elements.Others[0].ShortName)
+
+	cluster := clusterPkg.Elements.CANClusters[0]
+	assert.Equal("Powertrain bus", cluster.Desc.String())
+	assert.Equal(500_000, cluster.Variants[0].Baudrate)
+
+	channel := cluster.Variants[0].PhysicalChannels[0]
+	assert.Len(channel.CommConnectors, 3)
+	assert.Len(channel.FrameTriggerings, 3)
+
+	triggering := channel.FrameTriggerings[1]
+	assert.Equal(AddressingModeExtended, triggering.AddressingMode)
+	assert.Equal(uint32(0x1ABCDEF), triggering.Identifier)
+	assert.Equal("CAN-FRAME", triggering.FrameRef.Dest)
+	assert.Equal("/Network/Frames/diagnostic", triggering.FrameRef.Path)
+
+	pduPkg := networkPkg.Packages[3]
+	engine := pduPkg.Elements.ISignalIPDUs[0]
+	assert.Len(engine.SignalMappings, 5)
+	assert.Equal(0.01, engine.Timings[0].Mode.TrueTiming.Cyclic.TimePeriod.Value)
+	assert.Equal(0.002, *engine.Timings[0].MinimumDelay)
+
+	mux := pduPkg.Elements.MultiplexedIPDUs[0]
+	assert.Equal(2, mux.SelectorFieldLength)
+	assert.Len(mux.DynamicPart.Alternatives, 2)
+	assert.True(mux.DynamicPart.Alternatives[0].Initial)
+	assert.Equal(1, mux.DynamicPart.Alternatives[1].SelectorFieldCode)
+	assert.Len(mux.StaticParts, 1)
+}
+
+func Test_Decode_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Decode(strings.NewReader(`<AUTOSAR><AR-PACKAGES>`))
+	assert.Error(err)
+
+	_, err = Decode(strings.NewReader(`<AUTOSAR><AR-PACKAGES><AR-PACKAGE><ELEMENTS><CAN-FRAME><FRAME-LENGTH>x</FRAME-LENGTH></CAN-FRAME></ELEMENTS></AR-PACKAGE></AR-PACKAGES></AUTOSAR>`))
+	assert.Error(err)
+}
+
+func Test_Encode(t *testing.T) {
+	assert := assert.New(t)
+
+	root := decodeTestFile(assert)
+
+	// the unknown elements are not written
+	for _, pkg := range root.Packages[0].Packages {
+		pkg.Elements.Others = nil
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(Encode(buf, root))
+
+	content := buf.String()
+	assert.True(strings.HasPrefix(content, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(content, `<AUTOSAR xmlns="http://autosar.org/schema/r4.0">`)
+	assert.Contains(content, `<FRAME-REF DEST="CAN-FRAME">/Network/Frames/engine</FRAME-REF>`)
+	assert.Contains(content, `<LOWER-LIMIT INTERVAL-TYPE="CLOSED">0</LOWER-LIMIT>`)
+
+	// should decode the same file
+	decRoot, err := Decode(buf)
+	assert.NoError(err)
+	decRoot.XMLName = root.XMLName
+	decRoot.Xmlns = root.Xmlns
+	assert.Equal(root, decRoot)
+}
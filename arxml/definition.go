@@ -0,0 +1,552 @@
+package arxml
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// Namespace is the XML namespace of the AUTOSAR 4.x files.
+const Namespace = "http://autosar.org/schema/r4.0"
+
+// AUTOSAR is the root element of an ARXML file.
+type AUTOSAR struct {
+	XMLName xml.Name `xml:"AUTOSAR"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+
+	Packages []*Package `xml:"AR-PACKAGES>AR-PACKAGE"`
+}
+
+// Package is an AR-PACKAGE, which holds elements and other packages.
+type Package struct {
+	ShortName string     `xml:"SHORT-NAME"`
+	Elements  *Elements  `xml:"ELEMENTS"`
+	Packages  []*Package `xml:"AR-PACKAGES>AR-PACKAGE"`
+}
+
+// Elements holds the elements of a [Package].
+type Elements struct {
+	CANClusters      []*CANCluster      `xml:"CAN-CLUSTER"`
+	CANFrames        []*CANFrame        `xml:"CAN-FRAME"`
+	ISignalIPDUs     []*ISignalIPDU     `xml:"I-SIGNAL-I-PDU"`
+	MultiplexedIPDUs []*MultiplexedIPDU `xml:"MULTIPLEXED-I-PDU"`
+	ISignals         []*ISignal         `xml:"I-SIGNAL"`
+	SystemSignals    []*SystemSignal    `xml:"SYSTEM-SIGNAL"`
+	CompuMethods     []*CompuMethod     `xml:"COMPU-METHOD"`
+	Units            []*Unit            `xml:"UNIT"`
+	SWBaseTypes      []*SWBaseType      `xml:"SW-BASE-TYPE"`
+	DataConstrs      []*DataConstr      `xml:"DATA-CONSTR"`
+	ECUInstances     []*ECUInstance     `xml:"ECU-INSTANCE"`
+
+	// Others holds the elements that are not supported.
+	Others []*UnknownElement `xml:",any"`
+}
+
+// UnknownElement is an element that is not supported.
+// Only its tag and its short name are decoded.
+type UnknownElement struct {
+	XMLName   xml.Name
+	ShortName string `xml:"SHORT-NAME"`
+}
+
+// Ref is a reference to another element by its AUTOSAR path.
+type Ref struct {
+	// Dest is the tag of the referenced element, like "CAN-FRAME".
+	Dest string `xml:"DEST,attr"`
+	Path string `xml:",chardata"`
+}
+
+// NewRef returns a reference to the element with the given tag and path.
+func NewRef(dest, path string) *Ref {
+	return &Ref{Dest: dest, Path: path}
+}
+
+// Desc is a multi-language description.
+type Desc struct {
+	Texts []*LText `xml:"L-2"`
+}
+
+// NewDesc returns an english description with the given text,
+// or nil if the text is empty.
+func NewDesc(text string) *Desc {
+	if text == "" {
+		return nil
+	}
+	return &Desc{Texts: []*LText{{Lang: "EN", Text: text}}}
+}
+
+// String returns the english text of the description, or the first one.
+func (d *Desc) String() string {
+	if d == nil || len(d.Texts) == 0 {
+		return ""
+	}
+
+	for _, text := range d.Texts {
+		if text.Lang == "EN" {
+			return text.Text
+		}
+	}
+
+	return d.Texts[0].Text
+}
+
+// LText is the text of a [Desc] in a language.
+type LText struct {
+	Lang string `xml:"L,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+// TimeValue is a time expressed in seconds.
+type TimeValue struct {
+	Value float64 `xml:"VALUE"`
+}
+
+// ---------- //
+// Topology   //
+// ---------- //
+
+// CANCluster is a CAN bus, which has one or more physical channels.
+type CANCluster struct {
+	ShortName string                   `xml:"SHORT-NAME"`
+	Desc      *Desc                    `xml:"DESC"`
+	Variants  []*CANClusterConditional `xml:"CAN-CLUSTER-VARIANTS>CAN-CLUSTER-CONDITIONAL"`
+}
+
+// CANClusterConditional holds the properties of a [CANCluster].
+type CANClusterConditional struct {
+	Baudrate         int                   `xml:"BAUDRATE,omitempty"`
+	PhysicalChannels []*CANPhysicalChannel `xml:"PHYSICAL-CHANNELS>CAN-PHYSICAL-CHANNEL"`
+	ProtocolName     string                `xml:"PROTOCOL-NAME,omitempty"`
+	CANFDBaudrate    int                   `xml:"CAN-FD-BAUDRATE,omitempty"`
+}
+
+// CANPhysicalChannel is a channel of a [CANCluster], where the frames,
+// the PDUs and the signals are triggered.
+type CANPhysicalChannel struct {
+	ShortName          string                         `xml:"SHORT-NAME"`
+	CommConnectors     []*CommConnectorRefConditional `xml:"COMM-CONNECTORS>COMMUNICATION-CONNECTOR-REF-CONDITIONAL"`
+	FrameTriggerings   []*CANFrameTriggering          `xml:"FRAME-TRIGGERINGS>CAN-FRAME-TRIGGERING"`
+	ISignalTriggerings []*ISignalTriggering           `xml:"I-SIGNAL-TRIGGERINGS>I-SIGNAL-TRIGGERING"`
+	PDUTriggerings     []*PDUTriggering               `xml:"PDU-TRIGGERINGS>PDU-TRIGGERING"`
+}
+
+// CommConnectorRefConditional refers to a connector of an ECU attached to a channel.
+type CommConnectorRefConditional struct {
+	Ref *Ref `xml:"COMMUNICATION-CONNECTOR-REF"`
+}
+
+// Addressing modes of a [CANFrameTriggering].
+const (
+	AddressingModeStandard = "STANDARD"
+	AddressingModeExtended = "EXTENDED"
+)
+
+// Frame behaviors of a [CANFrameTriggering].
+const (
+	FrameBehaviorCAN20 = "CAN-20"
+	FrameBehaviorCANFD = "CAN-FD"
+)
+
+// CANFrameTriggering is the transmission of a [CANFrame] on a channel with a CAN-ID.
+// The frame ports define the ECUs that send (out) and receive (in) the frame.
+type CANFrameTriggering struct {
+	ShortName         string                         `xml:"SHORT-NAME"`
+	FramePortRefs     []*Ref                         `xml:"FRAME-PORT-REFS>FRAME-PORT-REF"`
+	FrameRef          *Ref                           `xml:"FRAME-REF"`
+	PDUTriggeringRefs []*PDUTriggeringRefConditional `xml:"PDU-TRIGGERINGS>PDU-TRIGGERING-REF-CONDITIONAL"`
+	AddressingMode    string                         `xml:"CAN-ADDRESSING-MODE,omitempty"`
+	FrameRxBehavior   string                         `xml:"CAN-FRAME-RX-BEHAVIOR,omitempty"`
+	FrameTxBehavior   string                         `xml:"CAN-FRAME-TX-BEHAVIOR,omitempty"`
+	Identifier        uint32                         `xml:"IDENTIFIER"`
+}
+
+// PDUTriggeringRefConditional refers to a [PDUTriggering].
+type PDUTriggeringRefConditional struct {
+	Ref *Ref `xml:"PDU-TRIGGERING-REF"`
+}
+
+// PDUTriggering is the transmission of an I-PDU on a channel.
+type PDUTriggering struct {
+	ShortName          string                             `xml:"SHORT-NAME"`
+	IPDUPortRefs       []*Ref                             `xml:"I-PDU-PORT-REFS>I-PDU-PORT-REF"`
+	IPDURef            *Ref                               `xml:"I-PDU-REF"`
+	ISignalTriggerings []*ISignalTriggeringRefConditional `xml:"I-SIGNAL-TRIGGERINGS>I-SIGNAL-TRIGGERING-REF-CONDITIONAL"`
+}
+
+// ISignalTriggeringRefConditional refers to an [ISignalTriggering].
+type ISignalTriggeringRefConditional struct {
+	Ref *Ref `xml:"I-SIGNAL-TRIGGERING-REF"`
+}
+
+// ISignalTriggering is the transmission of an [ISignal] on a channel.
+type ISignalTriggering struct {
+	ShortName       string `xml:"SHORT-NAME"`
+	ISignalPortRefs []*Ref `xml:"I-SIGNAL-PORT-REFS>I-SIGNAL-PORT-REF"`
+	ISignalRef      *Ref   `xml:"I-SIGNAL-REF"`
+}
+
+// Communication directions of a port.
+const (
+	DirectionIn  = "IN"
+	DirectionOut = "OUT"
+)
+
+// ECUInstance is an ECU connected to one or more channels through its connectors.
+type ECUInstance struct {
+	ShortName  string                       `xml:"SHORT-NAME"`
+	Desc       *Desc                        `xml:"DESC"`
+	Connectors []*CANCommunicationConnector `xml:"CONNECTORS>CAN-COMMUNICATION-CONNECTOR"`
+}
+
+// CANCommunicationConnector connects an [ECUInstance] to a channel.
+type CANCommunicationConnector struct {
+	ShortName    string  `xml:"SHORT-NAME"`
+	FramePorts   []*Port `xml:"ECU-COMM-PORT-INSTANCES>FRAME-PORT"`
+	IPDUPorts    []*Port `xml:"ECU-COMM-PORT-INSTANCES>I-PDU-PORT"`
+	ISignalPorts []*Port `xml:"ECU-COMM-PORT-INSTANCES>I-SIGNAL-PORT"`
+}
+
+// Port is a frame, I-PDU or I-Signal port of a [CANCommunicationConnector].
+type Port struct {
+	ShortName string `xml:"SHORT-NAME"`
+	Direction string `xml:"COMMUNICATION-DIRECTION"`
+}
+
+// ---------- //
+// Frames     //
+// ---------- //
+
+// Byte orders of a signal or of a segment.
+const (
+	// ByteOrderLittleEndian is the little endian (Intel) byte order.
+	ByteOrderLittleEndian = "MOST-SIGNIFICANT-BYTE-LAST"
+	// ByteOrderBigEndian is the big endian (Motorola) byte order.
+	ByteOrderBigEndian = "MOST-SIGNIFICANT-BYTE-FIRST"
+	// ByteOrderOpaque is the byte order of the opaque data.
+	ByteOrderOpaque = "OPAQUE"
+)
+
+// CANFrame is a CAN frame, which carries one or more PDUs.
+type CANFrame struct {
+	ShortName   string               `xml:"SHORT-NAME"`
+	Desc        *Desc                `xml:"DESC"`
+	FrameLength int                  `xml:"FRAME-LENGTH"`
+	PDUMappings []*PDUToFrameMapping `xml:"PDU-TO-FRAME-MAPPINGS>PDU-TO-FRAME-MAPPING"`
+}
+
+// PDUToFrameMapping places an I-PDU into a [CANFrame].
+type PDUToFrameMapping struct {
+	ShortName     string `xml:"SHORT-NAME"`
+	ByteOrder     string `xml:"PACKING-BYTE-ORDER,omitempty"`
+	PDURef        *Ref   `xml:"PDU-REF"`
+	StartPosition int    `xml:"START-POSITION"`
+}
+
+// ISignalIPDU is an I-PDU that carries I-Signals.
+type ISignalIPDU struct {
+	ShortName        string                  `xml:"SHORT-NAME"`
+	Desc             *Desc                   `xml:"DESC"`
+	Length           int                     `xml:"LENGTH"`
+	Timings          []*IPDUTiming           `xml:"I-PDU-TIMING-SPECIFICATIONS>I-PDU-TIMING"`
+	SignalMappings   []*ISignalToIPDUMapping `xml:"I-SIGNAL-TO-PDU-MAPPINGS>I-SIGNAL-TO-I-PDU-MAPPING"`
+	UnusedBitPattern *int                    `xml:"UNUSED-BIT-PATTERN"`
+}
+
+// IPDUTiming describes when an I-PDU is sent.
+type IPDUTiming struct {
+	// MinimumDelay is the minimum time between two transmissions in seconds.
+	MinimumDelay *float64                     `xml:"MINIMUM-DELAY"`
+	Mode         *TransmissionModeDeclaration `xml:"TRANSMISSION-MODE-DECLARATION"`
+}
+
+// TransmissionModeDeclaration holds the timing used when the transmission mode is true.
+type TransmissionModeDeclaration struct {
+	TrueTiming *TransmissionModeTiming `xml:"TRANSMISSION-MODE-TRUE-TIMING"`
+}
+
+// TransmissionModeTiming defines a cyclic and/or an event controlled timing.
+type TransmissionModeTiming struct {
+	Cyclic *CyclicTiming          `xml:"CYCLIC-TIMING"`
+	Event  *EventControlledTiming `xml:"EVENT-CONTROLLED-TIMING"`
+}
+
+// CyclicTiming is the timing of a cyclic transmission.
+type CyclicTiming struct {
+	TimeOffset *TimeValue `xml:"TIME-OFFSET"`
+	TimePeriod *TimeValue `xml:"TIME-PERIOD"`
+}
+
+// EventControlledTiming is the timing of a transmission triggered by an event.
+type EventControlledTiming struct {
+	NumberOfRepetitions int        `xml:"NUMBER-OF-REPETITIONS"`
+	RepetitionPeriod    *TimeValue `xml:"REPETITION-PERIOD"`
+}
+
+// Transfer properties of an [ISignalToIPDUMapping].
+const (
+	TransferPropertyPending           = "PENDING"
+	TransferPropertyTriggered         = "TRIGGERED"
+	TransferPropertyTriggeredOnChange = "TRIGGERED-ON-CHANGE"
+)
+
+// ISignalToIPDUMapping places an [ISignal] into an [ISignalIPDU].
+//
+// The start position is the position of the least significant bit
+// for the little endian signals, and of the most significant bit for
+// the big endian ones. The bits are numbered like in a DBC file.
+type ISignalToIPDUMapping struct {
+	ShortName        string `xml:"SHORT-NAME"`
+	ISignalRef       *Ref   `xml:"I-SIGNAL-REF"`
+	ISignalGroupRef  *Ref   `xml:"I-SIGNAL-GROUP-REF"`
+	ByteOrder        string `xml:"PACKING-BYTE-ORDER,omitempty"`
+	StartPosition    int    `xml:"START-POSITION"`
+	TransferProperty string `xml:"TRANSFER-PROPERTY,omitempty"`
+}
+
+// MultiplexedIPDU is an I-PDU whose content is selected by a selector field.
+// The static part is always sent, while only one of the alternatives
+// of the dynamic part is sent according to the selector field.
+type MultiplexedIPDU struct {
+	ShortName                  string        `xml:"SHORT-NAME"`
+	Desc                       *Desc         `xml:"DESC"`
+	Length                     int           `xml:"LENGTH"`
+	DynamicPart                *DynamicPart  `xml:"DYNAMIC-PART"`
+	SelectorFieldByteOrder     string        `xml:"SELECTOR-FIELD-BYTE-ORDER,omitempty"`
+	SelectorFieldLength        int           `xml:"SELECTOR-FIELD-LENGTH"`
+	SelectorFieldStartPosition int           `xml:"SELECTOR-FIELD-START-POSITION"`
+	StaticParts                []*StaticPart `xml:"STATIC-PARTS>STATIC-PART"`
+	TriggerMode                string        `xml:"TRIGGER-MODE,omitempty"`
+}
+
+// DynamicPart holds the alternatives of a [MultiplexedIPDU].
+type DynamicPart struct {
+	SegmentPositions []*SegmentPosition        `xml:"SEGMENT-POSITIONS>SEGMENT-POSITION"`
+	Alternatives     []*DynamicPartAlternative `xml:"DYNAMIC-PART-ALTERNATIVES>DYNAMIC-PART-ALTERNATIVE"`
+}
+
+// SegmentPosition is a segment of the payload of a [MultiplexedIPDU].
+type SegmentPosition struct {
+	ByteOrder string `xml:"SEGMENT-BYTE-ORDER,omitempty"`
+	Length    int    `xml:"SEGMENT-LENGTH"`
+	Position  int    `xml:"SEGMENT-POSITION"`
+}
+
+// DynamicPartAlternative is the I-PDU sent when the selector field
+// is equal to the selector field code.
+type DynamicPartAlternative struct {
+	IPDURef           *Ref `xml:"I-PDU-REF"`
+	Initial           bool `xml:"INITIAL-DYNAMIC-PART"`
+	SelectorFieldCode int  `xml:"SELECTOR-FIELD-CODE"`
+}
+
+// StaticPart is the I-PDU always sent within a [MultiplexedIPDU].
+type StaticPart struct {
+	IPDURef *Ref `xml:"I-PDU-REF"`
+}
+
+// ---------- //
+// Signals    //
+// ---------- //
+
+// ISignal is a signal sent on the network. It refers to a [SystemSignal].
+type ISignal struct {
+	ShortName                  string          `xml:"SHORT-NAME"`
+	Desc                       *Desc           `xml:"DESC"`
+	DataTypePolicy             string          `xml:"DATA-TYPE-POLICY,omitempty"`
+	InitValue                  *InitValue      `xml:"INIT-VALUE"`
+	Length                     int             `xml:"LENGTH"`
+	NetworkRepresentationProps *SWDataDefProps `xml:"NETWORK-REPRESENTATION-PROPS"`
+	SystemSignalRef            *Ref            `xml:"SYSTEM-SIGNAL-REF"`
+}
+
+// InitValue is the initial value of an [ISignal].
+type InitValue struct {
+	Numerical *NumericalValueSpecification `xml:"NUMERICAL-VALUE-SPECIFICATION"`
+}
+
+// NumericalValueSpecification is a numerical value.
+type NumericalValueSpecification struct {
+	Value float64 `xml:"VALUE"`
+}
+
+// SystemSignal is the logical signal of the system, with its physical properties.
+type SystemSignal struct {
+	ShortName     string          `xml:"SHORT-NAME"`
+	Desc          *Desc           `xml:"DESC"`
+	DynamicLength bool            `xml:"DYNAMIC-LENGTH,omitempty"`
+	PhysicalProps *SWDataDefProps `xml:"PHYSICAL-PROPS"`
+}
+
+// SWDataDefProps holds the data definition properties of a signal.
+type SWDataDefProps struct {
+	Variants []*SWDataDefPropsConditional `xml:"SW-DATA-DEF-PROPS-VARIANTS>SW-DATA-DEF-PROPS-CONDITIONAL"`
+}
+
+// Conditional returns the first variant of the properties, or nil.
+func (p *SWDataDefProps) Conditional() *SWDataDefPropsConditional {
+	if p == nil || len(p.Variants) == 0 {
+		return nil
+	}
+	return p.Variants[0]
+}
+
+// SWDataDefPropsConditional refers to the base type, the CompuMethod,
+// the data constraint and the unit of a signal.
+type SWDataDefPropsConditional struct {
+	BaseTypeRef    *Ref `xml:"BASE-TYPE-REF"`
+	CompuMethodRef *Ref `xml:"COMPU-METHOD-REF"`
+	DataConstrRef  *Ref `xml:"DATA-CONSTR-REF"`
+	UnitRef        *Ref `xml:"UNIT-REF"`
+}
+
+// Base type encodings of a [SWBaseType].
+const (
+	BaseTypeEncodingNone           = "NONE"
+	BaseTypeEncodingTwosComplement = "2C"
+	BaseTypeEncodingIEEE754        = "IEEE754"
+)
+
+// SWBaseType is the base type of a signal, which defines its size and encoding.
+type SWBaseType struct {
+	ShortName string `xml:"SHORT-NAME"`
+	Category  string `xml:"CATEGORY,omitempty"`
+	Size      int    `xml:"BASE-TYPE-SIZE"`
+	Encoding  string `xml:"BASE-TYPE-ENCODING,omitempty"`
+}
+
+// Categories of a [CompuMethod].
+const (
+	CompuMethodIdentical               = "IDENTICAL"
+	CompuMethodLinear                  = "LINEAR"
+	CompuMethodScaleLinear             = "SCALE_LINEAR"
+	CompuMethodTextTable               = "TEXTTABLE"
+	CompuMethodScaleLinearAndTextTable = "SCALE_LINEAR_AND_TEXTTABLE"
+)
+
+// CompuMethod converts the internal value of a signal to the physical one.
+type CompuMethod struct {
+	ShortName      string       `xml:"SHORT-NAME"`
+	Desc           *Desc        `xml:"DESC"`
+	Category       string       `xml:"CATEGORY"`
+	UnitRef        *Ref         `xml:"UNIT-REF"`
+	InternalToPhys *CompuScales `xml:"COMPU-INTERNAL-TO-PHYS"`
+}
+
+// CompuScales holds the scales of a [CompuMethod].
+type CompuScales struct {
+	Scales []*CompuScale `xml:"COMPU-SCALES>COMPU-SCALE"`
+}
+
+// CompuScale converts the internal values within its limits,
+// with a rational function or with a constant text.
+type CompuScale struct {
+	ShortLabel     string               `xml:"SHORT-LABEL,omitempty"`
+	Desc           *Desc                `xml:"DESC"`
+	LowerLimit     *Limit               `xml:"LOWER-LIMIT"`
+	UpperLimit     *Limit               `xml:"UPPER-LIMIT"`
+	Const          *CompuConst          `xml:"COMPU-CONST"`
+	RationalCoeffs *CompuRationalCoeffs `xml:"COMPU-RATIONAL-COEFFS"`
+}
+
+// IsText states whether the scale converts the values to a text.
+func (cs *CompuScale) IsText() bool {
+	return cs.Const != nil
+}
+
+// Interval types of a [Limit].
+const (
+	IntervalTypeClosed   = "CLOSED"
+	IntervalTypeOpen     = "OPEN"
+	IntervalTypeInfinite = "INFINITE"
+)
+
+// Limit is the lower or upper limit of an interval.
+type Limit struct {
+	IntervalType string `xml:"INTERVAL-TYPE,attr,omitempty"`
+	Value        string `xml:",chardata"`
+}
+
+// NewLimit returns a closed limit with the given value.
+func NewLimit(value float64) *Limit {
+	return &Limit{
+		IntervalType: IntervalTypeClosed,
+		Value:        strconv.FormatFloat(value, 'g', -1, 64),
+	}
+}
+
+// Float returns the value of the limit. It returns false
+// if the limit is infinite or it is not a number.
+func (l *Limit) Float() (float64, bool) {
+	if l == nil || l.IntervalType == IntervalTypeInfinite {
+		return 0, false
+	}
+
+	val, err := strconv.ParseFloat(strings.TrimSpace(l.Value), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return val, true
+}
+
+// CompuConst is the constant text of a [CompuScale].
+type CompuConst struct {
+	VT string `xml:"VT"`
+}
+
+// CompuRationalCoeffs are the coefficients of the rational function
+// of a [CompuScale]. For a linear conversion, the numerator holds
+// the offset and the factor, and the denominator holds the divisor.
+type CompuRationalCoeffs struct {
+	Numerator   []float64 `xml:"COMPU-NUMERATOR>V"`
+	Denominator []float64 `xml:"COMPU-DENOMINATOR>V"`
+}
+
+// Linear returns the factor and the offset of a linear conversion.
+// It returns false if the coefficients are not the ones of a linear function.
+func (c *CompuRationalCoeffs) Linear() (factor, offset float64, ok bool) {
+	if c == nil || len(c.Numerator) == 0 || len(c.Numerator) > 2 || len(c.Denominator) > 1 {
+		return 0, 0, false
+	}
+
+	offset = c.Numerator[0]
+	factor = 0
+	if len(c.Numerator) == 2 {
+		factor = c.Numerator[1]
+	}
+
+	if len(c.Denominator) == 1 {
+		div := c.Denominator[0]
+		if div == 0 {
+			return 0, 0, false
+		}
+		offset /= div
+		factor /= div
+	}
+
+	return factor, offset, true
+}
+
+// Unit is a physical unit.
+type Unit struct {
+	ShortName   string `xml:"SHORT-NAME"`
+	DisplayName string `xml:"DISPLAY-NAME,omitempty"`
+}
+
+// DataConstr defines the limits of the values of a signal.
+type DataConstr struct {
+	ShortName string            `xml:"SHORT-NAME"`
+	Rules     []*DataConstrRule `xml:"DATA-CONSTR-RULES>DATA-CONSTR-RULE"`
+}
+
+// DataConstrRule holds the physical and the internal limits of a [DataConstr].
+type DataConstrRule struct {
+	PhysConstrs     *Constrs `xml:"PHYS-CONSTRS"`
+	InternalConstrs *Constrs `xml:"INTERNAL-CONSTRS"`
+}
+
+// Constrs are the lower and upper limits of a [DataConstrRule].
+type Constrs struct {
+	LowerLimit *Limit `xml:"LOWER-LIMIT"`
+	UpperLimit *Limit `xml:"UPPER-LIMIT"`
+}
@@ -0,0 +1,13 @@
+// Package arxml reads and writes the AUTOSAR (4.x) system descriptions (.arxml)
+// that describe the CAN communication of a vehicle.
+//
+// Only the elements needed to describe a CAN network are represented:
+// the CAN clusters with their physical channels and triggerings, the frames,
+// the I-PDUs (also multiplexed), the I-Signals, the system signals,
+// the CompuMethods, the units, the base types, the data constraints and
+// the ECU instances. The other elements of a package are collected
+// into [Elements.Others], so the caller can report them.
+//
+// The elements refer to each other by their AUTOSAR path, like "/Package/Frame",
+// which is resolved by an [Index].
+package arxml
@@ -0,0 +1,31 @@
+package arxml
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when a reference points to a missing element.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidDest is returned when a reference points to an element of another type.
+	ErrInvalidDest = errors.New("invalid destination")
+	// ErrMissingRef is returned when a required reference is not defined.
+	ErrMissingRef = errors.New("missing reference")
+	// ErrDuplicatedPath is returned when two elements have the same path.
+	ErrDuplicatedPath = errors.New("duplicated path")
+)
+
+// ReferenceError is an error that occurred while resolving a reference.
+type ReferenceError struct {
+	// Path is the AUTOSAR path of the reference.
+	Path string
+	// Err is the resolution error.
+	Err error
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("reference error; path:%q : %v", e.Path, e.Err)
+}
+
+func (e *ReferenceError) Unwrap() error { return e.Err }
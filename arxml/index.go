@@ -0,0 +1,210 @@
+package arxml
+
+import "strings"
+
+// Index resolves the AUTOSAR paths of the elements of a file.
+type Index struct {
+	elements map[string]any
+
+	// owners maps the path of a connector or of a port to its ECU.
+	owners map[string]*ECUInstance
+}
+
+// NewIndex indexes all the elements of the given file.
+//
+// It returns a [ReferenceError] with [ErrDuplicatedPath]
+// if two elements have the same path.
+func NewIndex(root *AUTOSAR) (*Index, error) {
+	idx := &Index{
+		elements: make(map[string]any),
+		owners:   make(map[string]*ECUInstance),
+	}
+
+	for _, pkg := range root.Packages {
+		if err := idx.addPackage("", pkg); err != nil {
+			return nil, err
+		}
+	}
+
+	return idx, nil
+}
+
+func (idx *Index) add(path string, elem any) error {
+	if _, ok := idx.elements[path]; ok {
+		return &ReferenceError{Path: path, Err: ErrDuplicatedPath}
+	}
+	idx.elements[path] = elem
+	return nil
+}
+
+func (idx *Index) addPackage(parentPath string, pkg *Package) error {
+	path := parentPath + "/" + pkg.ShortName
+
+	if elems := pkg.Elements; elems != nil {
+		if err := idx.addElements(path, elems); err != nil {
+			return err
+		}
+	}
+
+	for _, subPkg := range pkg.Packages {
+		if err := idx.addPackage(path, subPkg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addAll[T any](idx *Index, pkgPath string, elems []T, getName func(T) string) error {
+	for _, elem := range elems {
+		if err := idx.add(pkgPath+"/"+getName(elem), elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idx *Index) addElements(pkgPath string, elems *Elements) error {
+	for _, cluster := range elems.CANClusters {
+		if err := idx.addCANCluster(pkgPath, cluster); err != nil {
+			return err
+		}
+	}
+
+	for _, ecu := range elems.ECUInstances {
+		if err := idx.addECUInstance(pkgPath, ecu); err != nil {
+			return err
+		}
+	}
+
+	if err := addAll(idx, pkgPath, elems.CANFrames, func(e *CANFrame) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.ISignalIPDUs, func(e *ISignalIPDU) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.MultiplexedIPDUs, func(e *MultiplexedIPDU) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.ISignals, func(e *ISignal) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.SystemSignals, func(e *SystemSignal) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.CompuMethods, func(e *CompuMethod) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.Units, func(e *Unit) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.SWBaseTypes, func(e *SWBaseType) string { return e.ShortName }); err != nil {
+		return err
+	}
+	if err := addAll(idx, pkgPath, elems.DataConstrs, func(e *DataConstr) string { return e.ShortName }); err != nil {
+		return err
+	}
+
+	for _, other := range elems.Others {
+		if other.ShortName == "" {
+			continue
+		}
+		if err := idx.add(pkgPath+"/"+other.ShortName, other); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (idx *Index) addCANCluster(pkgPath string, cluster *CANCluster) error {
+	path := pkgPath + "/" + cluster.ShortName
+	if err := idx.add(path, cluster); err != nil {
+		return err
+	}
+
+	for _, variant := range cluster.Variants {
+		for _, channel := range variant.PhysicalChannels {
+			channelPath := path + "/" + channel.ShortName
+			if err := idx.add(channelPath, channel); err != nil {
+				return err
+			}
+
+			if err := addAll(idx, channelPath, channel.FrameTriggerings, func(e *CANFrameTriggering) string { return e.ShortName }); err != nil {
+				return err
+			}
+			if err := addAll(idx, channelPath, channel.PDUTriggerings, func(e *PDUTriggering) string { return e.ShortName }); err != nil {
+				return err
+			}
+			if err := addAll(idx, channelPath, channel.ISignalTriggerings, func(e *ISignalTriggering) string { return e.ShortName }); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (idx *Index) addECUInstance(pkgPath string, ecu *ECUInstance) error {
+	path := pkgPath + "/" + ecu.ShortName
+	if err := idx.add(path, ecu); err != nil {
+		return err
+	}
+
+	for _, conn := range ecu.Connectors {
+		connPath := path + "/" + conn.ShortName
+		if err := idx.add(connPath, conn); err != nil {
+			return err
+		}
+		idx.owners[connPath] = ecu
+
+		ports := append(append(append([]*Port{}, conn.FramePorts...), conn.IPDUPorts...), conn.ISignalPorts...)
+		for _, port := range ports {
+			portPath := connPath + "/" + port.ShortName
+			if err := idx.add(portPath, port); err != nil {
+				return err
+			}
+			idx.owners[portPath] = ecu
+		}
+	}
+
+	return nil
+}
+
+// Get returns the element with the given path.
+func (idx *Index) Get(path string) (any, bool) {
+	elem, ok := idx.elements[strings.TrimSpace(path)]
+	return elem, ok
+}
+
+// Owner returns the ECU that owns the connector or the port with the given path.
+func (idx *Index) Owner(path string) (*ECUInstance, bool) {
+	ecu, ok := idx.owners[strings.TrimSpace(path)]
+	return ecu, ok
+}
+
+// Resolve returns the element referenced by the given reference.
+//
+// It returns a [ReferenceError] with:
+//   - [ErrMissingRef] if the reference is nil.
+//   - [ErrNotFound] if the element does not exist.
+//   - [ErrInvalidDest] if the element is not of type T.
+func Resolve[T any](idx *Index, ref *Ref) (T, error) {
+	var zero T
+
+	if ref == nil {
+		return zero, &ReferenceError{Err: ErrMissingRef}
+	}
+
+	elem, ok := idx.Get(ref.Path)
+	if !ok {
+		return zero, &ReferenceError{Path: ref.Path, Err: ErrNotFound}
+	}
+
+	typed, ok := elem.(T)
+	if !ok {
+		return zero, &ReferenceError{Path: ref.Path, Err: ErrInvalidDest}
+	}
+
+	return typed, nil
+}
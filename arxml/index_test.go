@@ -0,0 +1,103 @@
+package arxml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Index(t *testing.T) {
+	assert := assert.New(t)
+
+	idx, err := NewIndex(decodeTestFile(assert))
+	assert.NoError(err)
+
+	// should resolve the elements of the packages
+	frame, err := Resolve[*CANFrame](idx, NewRef("CAN-FRAME", "/Network/Frames/engine"))
+	assert.NoError(err)
+	assert.Equal("engine", frame.ShortName)
+
+	// should resolve the nested elements
+	triggering, err := Resolve[*CANFrameTriggering](idx, NewRef("CAN-FRAME-TRIGGERING", "/Network/Clusters/powertrain/powertrain_channel/engine_triggering"))
+	assert.NoError(err)
+	assert.Equal(uint32(0x100), triggering.Identifier)
+
+	port, err := Resolve[*Port](idx, triggering.FramePortRefs[0])
+	assert.NoError(err)
+	assert.Equal(DirectionOut, port.Direction)
+
+	// should return the owner of the ports and of the connectors
+	ecu, ok := idx.Owner(triggering.FramePortRefs[0].Path)
+	assert.True(ok)
+	assert.Equal("ecu", ecu.ShortName)
+	ecu, ok = idx.Owner("/Network/ECUs/dashboard/dashboard_connector")
+	assert.True(ok)
+	assert.Equal("dashboard", ecu.ShortName)
+	_, ok = idx.Owner("/Network/Frames/engine")
+	assert.False(ok)
+
+	// should index the unknown elements
+	elem, ok := idx.Get("/Network/PDUs/container_pdu")
+	assert.True(ok)
+	assert.IsType(&UnknownElement{}, elem)
+
+	// should return ErrMissingRef
+	_, err = Resolve[*CANFrame](idx, nil)
+	assert.ErrorIs(err, ErrMissingRef)
+
+	// should return ErrNotFound
+	_, err = Resolve[*CANFrame](idx, NewRef("CAN-FRAME", "/Network/Frames/missing"))
+	assert.ErrorIs(err, ErrNotFound)
+
+	// should return ErrInvalidDest
+	_, err = Resolve[*ISignal](idx, NewRef("I-SIGNAL", "/Network/Frames/engine"))
+	assert.ErrorIs(err, ErrInvalidDest)
+}
+
+func Test_NewIndex_Duplicated(t *testing.T) {
+	assert := assert.New(t)
+
+	root := &AUTOSAR{
+		Packages: []*Package{
+			{
+				ShortName: "P",
+				Elements: &Elements{
+					Units:       []*Unit{{ShortName: "u"}},
+					SWBaseTypes: []*SWBaseType{{ShortName: "u"}},
+				},
+			},
+		},
+	}
+
+	_, err := NewIndex(root)
+	assert.ErrorIs(err, ErrDuplicatedPath)
+}
+
+func Test_CompuRationalCoeffs_Linear(t *testing.T) {
+	assert := assert.New(t)
+
+	factor, offset, ok := (&CompuRationalCoeffs{Numerator: []float64{-40, 1}, Denominator: []float64{2}}).Linear()
+	assert.True(ok)
+	assert.Equal(0.5, factor)
+	assert.Equal(-20.0, offset)
+
+	_, _, ok = (&CompuRationalCoeffs{Numerator: []float64{0, 1, 2}}).Linear()
+	assert.False(ok)
+
+	_, _, ok = (&CompuRationalCoeffs{Numerator: []float64{0, 1}, Denominator: []float64{0}}).Linear()
+	assert.False(ok)
+}
+
+func Test_Limit_Float(t *testing.T) {
+	assert := assert.New(t)
+
+	val, ok := NewLimit(2.5).Float()
+	assert.True(ok)
+	assert.Equal(2.5, val)
+
+	_, ok = (&Limit{IntervalType: IntervalTypeInfinite}).Float()
+	assert.False(ok)
+
+	_, ok = (*Limit)(nil).Float()
+	assert.False(ok)
+}
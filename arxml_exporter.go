@@ -0,0 +1,837 @@
+package acmelib
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/squadracorsepolito/acmelib/arxml"
+	"github.com/squadracorsepolito/acmelib/dbc"
+)
+
+// ExportARXML exports the given [Network] to an AUTOSAR (4.x) system description.
+// It writes the content of the result ARXML file into the [io.Writer].
+//
+// Every [Bus] becomes a CAN cluster with a single physical channel, and every [Node]
+// becomes an ECU instance with a connector for every bus it is connected to.
+// Every [Message] becomes a frame triggered on the channel of its bus, with a frame port
+// for the sender and for every receiver. The placeholder node used for the messages
+// without a sender is skipped.
+// The signals become I-Signals and system signals, whose CompuMethods are derived
+// from the [SignalType] or from the [SignalEnum] of the signals.
+//
+// A message with a [MultiplexedLayer] is written as a multiplexed I-PDU, named after
+// the message with the "_pdu" suffix, where the static part holds the signals
+// outside the layer and every layout becomes an alternative.
+//
+// The names are converted to valid AUTOSAR short names, and all the elements are
+// written in a stable order, so exporting the same network always gives the same file.
+//
+// It returns an [ErrInvalidType] wrapped by a [NameError] if a message
+// has more than one multiplexed layer or a nested one, because a multiplexed I-PDU
+// has a single selector. In this case nothing is written.
+func ExportARXML(w io.Writer, network *Network) error {
+	exp := newARXMLExporter()
+
+	autosar, err := exp.exportNetwork(network)
+	if err != nil {
+		return err
+	}
+
+	return arxml.Encode(w, autosar)
+}
+
+// arxmlShortName converts the given name to a valid AUTOSAR short name,
+// which starts with a letter and contains only letters, digits and underscores.
+func arxmlShortName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+
+	if name == "" || !((name[0] >= 'a' && name[0] <= 'z') || (name[0] >= 'A' && name[0] <= 'Z')) {
+		name = "x" + name
+	}
+
+	return name
+}
+
+// arxmlNames assigns unique short names within the same scope.
+type arxmlNames map[string]bool
+
+func (n arxmlNames) get(name string) string {
+	name = arxmlShortName(name)
+	if !n[name] {
+		n[name] = true
+		return name
+	}
+
+	for suffix := 1; ; suffix++ {
+		candidate := fmt.Sprintf("%s_%d", name, suffix)
+		if !n[candidate] {
+			n[candidate] = true
+			return candidate
+		}
+	}
+}
+
+// arxmlPaths holds the path of the element shared by the signals,
+// like a CompuMethod or a unit, assigned to an entity.
+type arxmlPaths struct {
+	names arxmlNames
+	paths map[EntityID]string
+}
+
+func newARXMLPaths() *arxmlPaths {
+	return &arxmlPaths{
+		names: make(arxmlNames),
+		paths: make(map[EntityID]string),
+	}
+}
+
+type arxmlExporter struct {
+	rootPath string
+
+	ecus      []*arxml.ECUInstance
+	ecuPaths  map[EntityID]string
+	ecuConns  map[*NodeInterface]*arxml.CANCommunicationConnector
+	connPaths map[*NodeInterface]string
+
+	clusters []*arxml.CANCluster
+
+	framePkgs     []*arxml.Package
+	pduPkgs       []*arxml.Package
+	iSignalPkgs   []*arxml.Package
+	systemSigPkgs []*arxml.Package
+
+	compuMethods []*arxml.CompuMethod
+	units        []*arxml.Unit
+	baseTypes    []*arxml.SWBaseType
+	dataConstrs  []*arxml.DataConstr
+
+	compuMethodPaths *arxmlPaths
+	unitPaths        *arxmlPaths
+	dataConstrPaths  *arxmlPaths
+	baseTypePaths    map[string]string
+}
+
+func newARXMLExporter() *arxmlExporter {
+	return &arxmlExporter{
+		ecuPaths:  make(map[EntityID]string),
+		ecuConns:  make(map[*NodeInterface]*arxml.CANCommunicationConnector),
+		connPaths: make(map[*NodeInterface]string),
+
+		compuMethodPaths: newARXMLPaths(),
+		unitPaths:        newARXMLPaths(),
+		dataConstrPaths:  newARXMLPaths(),
+		baseTypePaths:    make(map[string]string),
+	}
+}
+
+func (e *arxmlExporter) pkgPath(pkgName string) string {
+	return e.rootPath + "/" + pkgName
+}
+
+func (e *arxmlExporter) exportNetwork(net *Network) (*arxml.AUTOSAR, error) {
+	rootName := arxmlShortName(net.name)
+	e.rootPath = "/" + rootName
+
+	buses := net.Buses()
+
+	busNames := make(arxmlNames)
+	busShortNames := make([]string, len(buses))
+	for idx, bus := range buses {
+		busShortNames[idx] = busNames.get(bus.name)
+	}
+
+	e.exportNodes(buses, busShortNames)
+
+	for idx, bus := range buses {
+		if err := e.exportBus(busShortNames[idx], bus); err != nil {
+			return nil, err
+		}
+	}
+
+	rootPkg := &arxml.Package{ShortName: rootName}
+
+	addPkg := func(name string, elems *arxml.Elements, subPkgs []*arxml.Package) {
+		if elems == nil && len(subPkgs) == 0 {
+			return
+		}
+		rootPkg.Packages = append(rootPkg.Packages, &arxml.Package{
+			ShortName: name,
+			Elements:  elems,
+			Packages:  subPkgs,
+		})
+	}
+
+	sortByName(e.compuMethods, func(cm *arxml.CompuMethod) string { return cm.ShortName })
+	sortByName(e.units, func(u *arxml.Unit) string { return u.ShortName })
+	sortByName(e.baseTypes, func(bt *arxml.SWBaseType) string { return bt.ShortName })
+	sortByName(e.dataConstrs, func(dc *arxml.DataConstr) string { return dc.ShortName })
+
+	if len(e.clusters) > 0 {
+		addPkg("Clusters", &arxml.Elements{CANClusters: e.clusters}, nil)
+	}
+	if len(e.ecus) > 0 {
+		addPkg("ECUs", &arxml.Elements{ECUInstances: e.ecus}, nil)
+	}
+	addPkg("Frames", nil, e.framePkgs)
+	addPkg("PDUs", nil, e.pduPkgs)
+	addPkg("ISignals", nil, e.iSignalPkgs)
+	addPkg("SystemSignals", nil, e.systemSigPkgs)
+	if len(e.compuMethods) > 0 {
+		addPkg("CompuMethods", &arxml.Elements{CompuMethods: e.compuMethods}, nil)
+	}
+	if len(e.units) > 0 {
+		addPkg("Units", &arxml.Elements{Units: e.units}, nil)
+	}
+	if len(e.baseTypes) > 0 {
+		addPkg("BaseTypes", &arxml.Elements{SWBaseTypes: e.baseTypes}, nil)
+	}
+	if len(e.dataConstrs) > 0 {
+		addPkg("DataConstrs", &arxml.Elements{DataConstrs: e.dataConstrs}, nil)
+	}
+
+	return &arxml.AUTOSAR{Packages: []*arxml.Package{rootPkg}}, nil
+}
+
+func sortByName[T any](elems []T, getName func(T) string) {
+	slices.SortStableFunc(elems, func(a, b T) int {
+		return strings.Compare(getName(a), getName(b))
+	})
+}
+
+func (e *arxmlExporter) exportNodes(buses []*Bus, busShortNames []string) {
+	nodes := []*Node{}
+	for _, bus := range buses {
+		for _, nodeInt := range bus.NodeInterfaces() {
+			node := nodeInt.node
+			if node.name == dbc.DummyNode || slices.Contains(nodes, node) {
+				continue
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	slices.SortStableFunc(nodes, func(a, b *Node) int {
+		return cmp.Or(cmp.Compare(a.id, b.id), strings.Compare(a.name, b.name))
+	})
+
+	ecuNames := make(arxmlNames)
+	ecus := make(map[EntityID]*arxml.ECUInstance)
+
+	for _, node := range nodes {
+		ecu := &arxml.ECUInstance{
+			ShortName: ecuNames.get(node.name),
+			Desc:      arxml.NewDesc(node.desc),
+		}
+
+		e.ecus = append(e.ecus, ecu)
+		ecus[node.entityID] = ecu
+		e.ecuPaths[node.entityID] = e.pkgPath("ECUs") + "/" + ecu.ShortName
+	}
+
+	// the connectors follow the order of the buses
+	for idx, bus := range buses {
+		for _, nodeInt := range bus.NodeInterfaces() {
+			ecu, ok := ecus[nodeInt.node.entityID]
+			if !ok {
+				continue
+			}
+
+			conn := &arxml.CANCommunicationConnector{
+				ShortName: busShortNames[idx] + "_connector",
+			}
+			ecu.Connectors = append(ecu.Connectors, conn)
+
+			e.ecuConns[nodeInt] = conn
+			e.connPaths[nodeInt] = e.ecuPaths[nodeInt.node.entityID] + "/" + conn.ShortName
+		}
+	}
+}
+
+// addFramePort adds a frame port to the connector of the given node interface,
+// and it returns the reference to the port.
+func (e *arxmlExporter) addFramePort(nodeInt *NodeInterface, frameName, direction string) (*arxml.Ref, bool) {
+	conn, ok := e.ecuConns[nodeInt]
+	if !ok {
+		return nil, false
+	}
+
+	suffix := "_in"
+	if direction == arxml.DirectionOut {
+		suffix = "_out"
+	}
+
+	port := &arxml.Port{
+		ShortName: frameName + suffix,
+		Direction: direction,
+	}
+	conn.FramePorts = append(conn.FramePorts, port)
+
+	return arxml.NewRef("FRAME-PORT", e.connPaths[nodeInt]+"/"+port.ShortName), true
+}
+
+func (e *arxmlExporter) exportBus(busName string, bus *Bus) error {
+	variant := &arxml.CANClusterConditional{
+		Baudrate:     bus.baudrate,
+		ProtocolName: "CAN",
+	}
+
+	frameBehavior := arxml.FrameBehaviorCAN20
+	if bus.typ == BusTypeCANFD {
+		frameBehavior = arxml.FrameBehaviorCANFD
+		variant.CANFDBaudrate = bus.dataBaudrate
+	}
+
+	channel := &arxml.CANPhysicalChannel{ShortName: busName + "_channel"}
+	variant.PhysicalChannels = []*arxml.CANPhysicalChannel{channel}
+
+	e.clusters = append(e.clusters, &arxml.CANCluster{
+		ShortName: busName,
+		Desc:      arxml.NewDesc(bus.desc),
+		Variants:  []*arxml.CANClusterConditional{variant},
+	})
+
+	nodeInts := bus.NodeInterfaces()
+	for _, nodeInt := range nodeInts {
+		if _, ok := e.ecuConns[nodeInt]; !ok {
+			continue
+		}
+		channel.CommConnectors = append(channel.CommConnectors, &arxml.CommConnectorRefConditional{
+			Ref: arxml.NewRef("CAN-COMMUNICATION-CONNECTOR", e.connPaths[nodeInt]),
+		})
+	}
+
+	msgs := []*Message{}
+	for _, nodeInt := range nodeInts {
+		msgs = append(msgs, nodeInt.SentMessages()...)
+	}
+	slices.SortStableFunc(msgs, func(a, b *Message) int {
+		return cmp.Compare(a.GetCANID(), b.GetCANID())
+	})
+
+	framePkg := &arxml.Package{ShortName: busName, Elements: &arxml.Elements{}}
+	pduPkg := &arxml.Package{ShortName: busName, Elements: &arxml.Elements{}}
+	iSignalPkg := &arxml.Package{ShortName: busName}
+	systemSigPkg := &arxml.Package{ShortName: busName}
+
+	msgNames := make(arxmlNames)
+	for _, msg := range msgs {
+		frameName := msgNames.get(msg.name)
+
+		msgExp := &arxmlMessageExporter{
+			arxmlExporter: e,
+
+			pduPkgPath:       e.pkgPath("PDUs") + "/" + busName,
+			iSignalPkgPath:   e.pkgPath("ISignals") + "/" + busName + "/" + frameName,
+			systemSigPkgPath: e.pkgPath("SystemSignals") + "/" + busName + "/" + frameName,
+
+			pduElems:       pduPkg.Elements,
+			iSignalElems:   &arxml.Elements{},
+			systemSigElems: &arxml.Elements{},
+
+			signalNames:    make(arxmlNames),
+			iSignalNames:   make(map[EntityID]string),
+			pduNamePrefix:  frameName,
+			sizeByte:       msg.sizeByte,
+			messageTimings: e.exportMessageTiming(msg),
+		}
+
+		pduRef, err := msgExp.exportMessage(msg)
+		if err != nil {
+			return err
+		}
+
+		framePkg.Elements.CANFrames = append(framePkg.Elements.CANFrames, &arxml.CANFrame{
+			ShortName:   frameName,
+			Desc:        arxml.NewDesc(msg.desc),
+			FrameLength: msg.sizeByte,
+			PDUMappings: []*arxml.PDUToFrameMapping{
+				{
+					ShortName:     frameName + "_mapping",
+					ByteOrder:     arxml.ByteOrderLittleEndian,
+					PDURef:        pduRef,
+					StartPosition: 0,
+				},
+			},
+		})
+
+		if len(msgExp.iSignalElems.ISignals) > 0 {
+			iSignalPkg.Packages = append(iSignalPkg.Packages, &arxml.Package{ShortName: frameName, Elements: msgExp.iSignalElems})
+			systemSigPkg.Packages = append(systemSigPkg.Packages, &arxml.Package{ShortName: frameName, Elements: msgExp.systemSigElems})
+		}
+
+		triggering := &arxml.CANFrameTriggering{
+			ShortName:       frameName + "_triggering",
+			FrameRef:        arxml.NewRef("CAN-FRAME", e.pkgPath("Frames")+"/"+busName+"/"+frameName),
+			AddressingMode:  arxml.AddressingModeStandard,
+			FrameRxBehavior: frameBehavior,
+			FrameTxBehavior: frameBehavior,
+			Identifier:      uint32(msg.GetCANID()),
+		}
+
+		if msg.IsExtended() {
+			triggering.AddressingMode = arxml.AddressingModeExtended
+		}
+
+		if portRef, ok := e.addFramePort(msg.senderNodeInt, frameName, arxml.DirectionOut); ok {
+			triggering.FramePortRefs = append(triggering.FramePortRefs, portRef)
+		}
+
+		for _, rec := range msg.Receivers() {
+			if portRef, ok := e.addFramePort(rec, frameName, arxml.DirectionIn); ok {
+				triggering.FramePortRefs = append(triggering.FramePortRefs, portRef)
+			}
+		}
+
+		channel.FrameTriggerings = append(channel.FrameTriggerings, triggering)
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	e.framePkgs = append(e.framePkgs, framePkg)
+	e.pduPkgs = append(e.pduPkgs, pduPkg)
+
+	if len(iSignalPkg.Packages) > 0 {
+		e.iSignalPkgs = append(e.iSignalPkgs, iSignalPkg)
+		e.systemSigPkgs = append(e.systemSigPkgs, systemSigPkg)
+	}
+
+	return nil
+}
+
+func millisToSeconds(millis int) float64 {
+	return float64(millis) / 1000
+}
+
+// exportMessageTiming returns the timings of the PDUs of the given message.
+func (e *arxmlExporter) exportMessageTiming(msg *Message) []*arxml.IPDUTiming {
+	timing := &arxml.IPDUTiming{}
+	hasTiming := false
+
+	if msg.delayTime > 0 {
+		minDelay := millisToSeconds(msg.delayTime)
+		timing.MinimumDelay = &minDelay
+		hasTiming = true
+	}
+
+	trueTiming := &arxml.TransmissionModeTiming{}
+
+	if msg.cycleTime > 0 {
+		trueTiming.Cyclic = &arxml.CyclicTiming{
+			TimePeriod: &arxml.TimeValue{Value: millisToSeconds(msg.cycleTime)},
+		}
+
+		if msg.startDelayTime > 0 {
+			trueTiming.Cyclic.TimeOffset = &arxml.TimeValue{Value: millisToSeconds(msg.startDelayTime)}
+		}
+	}
+
+	switch msg.sendType {
+	case MessageSendTypeCyclicAndTriggered, MessageSendTypeCyclicIfActiveAndTriggered:
+		trueTiming.Event = &arxml.EventControlledTiming{NumberOfRepetitions: 0}
+	}
+
+	if trueTiming.Cyclic != nil || trueTiming.Event != nil {
+		timing.Mode = &arxml.TransmissionModeDeclaration{TrueTiming: trueTiming}
+		hasTiming = true
+	}
+
+	if !hasTiming {
+		return nil
+	}
+
+	return []*arxml.IPDUTiming{timing}
+}
+
+// arxmlMessageExporter exports the PDUs and the signals of a single message.
+type arxmlMessageExporter struct {
+	*arxmlExporter
+
+	pduPkgPath       string
+	iSignalPkgPath   string
+	systemSigPkgPath string
+
+	pduElems       *arxml.Elements
+	iSignalElems   *arxml.Elements
+	systemSigElems *arxml.Elements
+
+	signalNames  arxmlNames
+	iSignalNames map[EntityID]string
+
+	pduNamePrefix  string
+	sizeByte       int
+	messageTimings []*arxml.IPDUTiming
+}
+
+// exportMessage exports the PDUs of the message and
+// it returns the reference to the one mapped into the frame.
+//
+// It returns an [ErrInvalidType] wrapped by a [NameError]
+// if the message has more than one multiplexed layer or a nested one.
+func (e *arxmlMessageExporter) exportMessage(msg *Message) (*arxml.Ref, error) {
+	layout := msg.layout
+	muxLayers := layout.MultiplexedLayers()
+
+	if len(muxLayers) == 0 {
+		pdu := e.exportISignalIPDU(e.pduNamePrefix+"_pdu", msg.desc, layout.Signals())
+		pdu.Timings = e.messageTimings
+		return arxml.NewRef("I-SIGNAL-I-PDU", e.pduPkgPath+"/"+pdu.ShortName), nil
+	}
+
+	// only one selector per multiplexed I-PDU is supported
+	if len(muxLayers) > 1 {
+		return nil, msg.errorf(newNameError(muxLayers[1].muxor.name, ErrInvalidType))
+	}
+
+	muxLayer := muxLayers[0]
+	muxor := muxLayer.muxor
+
+	staticSignals := []Signal{}
+	for _, sig := range layout.Signals() {
+		if sig.EntityID() == muxor.entityID {
+			continue
+		}
+		staticSignals = append(staticSignals, sig)
+	}
+
+	staticPDU := e.exportISignalIPDU(e.pduNamePrefix+"_static", "", staticSignals)
+	staticPDU.Timings = e.messageTimings
+
+	muxPDU := &arxml.MultiplexedIPDU{
+		ShortName:   e.pduNamePrefix + "_pdu",
+		Desc:        arxml.NewDesc(msg.desc),
+		Length:      e.sizeByte,
+		DynamicPart: &arxml.DynamicPart{},
+
+		SelectorFieldByteOrder:     arxml.ByteOrderLittleEndian,
+		SelectorFieldLength:        muxor.Size(),
+		SelectorFieldStartPosition: muxor.StartPos(),
+
+		StaticParts: []*arxml.StaticPart{
+			{IPDURef: arxml.NewRef("I-SIGNAL-I-PDU", e.pduPkgPath+"/"+staticPDU.ShortName)},
+		},
+		TriggerMode: "DYNAMIC-PART-TRIGGER",
+	}
+
+	if muxor.Endianness() == EndiannessBigEndian {
+		muxPDU.SelectorFieldByteOrder = arxml.ByteOrderBigEndian
+		muxPDU.SelectorFieldStartPosition = StartPosFromBigEndian(muxor.StartPos())
+	}
+
+	// the segment covers the bytes of the signals of all the layouts
+	firstByte := e.sizeByte
+	lastByte := -1
+
+	for layoutID, muxLayout := range muxLayer.iterLayouts() {
+		layoutSignals := []Signal{}
+		for _, sig := range muxLayout.Signals() {
+			// the nested multiplexed layers are not supported
+			if sig.Kind() == SignalKindMuxor {
+				return nil, msg.errorf(newNameError(sig.Name(), ErrInvalidType))
+			}
+
+			layoutSignals = append(layoutSignals, sig)
+			firstByte = min(firstByte, sig.StartPos()/8)
+			lastByte = max(lastByte, (sig.StartPos()+sig.Size()-1)/8)
+		}
+
+		if len(layoutSignals) == 0 {
+			continue
+		}
+
+		altPDU := e.exportISignalIPDU(fmt.Sprintf("%s_layout_%d", e.pduNamePrefix, layoutID), "", layoutSignals)
+
+		muxPDU.DynamicPart.Alternatives = append(muxPDU.DynamicPart.Alternatives, &arxml.DynamicPartAlternative{
+			IPDURef:           arxml.NewRef("I-SIGNAL-I-PDU", e.pduPkgPath+"/"+altPDU.ShortName),
+			Initial:           len(muxPDU.DynamicPart.Alternatives) == 0,
+			SelectorFieldCode: layoutID,
+		})
+	}
+
+	if lastByte < 0 {
+		firstByte = 0
+		lastByte = e.sizeByte - 1
+	}
+
+	muxPDU.DynamicPart.SegmentPositions = []*arxml.SegmentPosition{
+		{
+			ByteOrder: arxml.ByteOrderLittleEndian,
+			Length:    (lastByte - firstByte + 1) * 8,
+			Position:  firstByte * 8,
+		},
+	}
+
+	e.pduElems.MultiplexedIPDUs = append(e.pduElems.MultiplexedIPDUs, muxPDU)
+
+	return arxml.NewRef("MULTIPLEXED-I-PDU", e.pduPkgPath+"/"+muxPDU.ShortName), nil
+}
+
+func (e *arxmlMessageExporter) exportISignalIPDU(name, desc string, signals []Signal) *arxml.ISignalIPDU {
+	pdu := &arxml.ISignalIPDU{
+		ShortName: name,
+		Desc:      arxml.NewDesc(desc),
+		Length:    e.sizeByte,
+	}
+
+	for _, sig := range signals {
+		sigName := e.exportSignal(sig)
+
+		mapping := &arxml.ISignalToIPDUMapping{
+			ShortName:        sigName + "_mapping",
+			ISignalRef:       arxml.NewRef("I-SIGNAL", e.iSignalPkgPath+"/"+sigName),
+			ByteOrder:        arxml.ByteOrderLittleEndian,
+			StartPosition:    sig.StartPos(),
+			TransferProperty: e.getTransferProperty(sig.SendType()),
+		}
+
+		if sig.Endianness() == EndiannessBigEndian {
+			mapping.ByteOrder = arxml.ByteOrderBigEndian
+			mapping.StartPosition = StartPosFromBigEndian(sig.StartPos())
+		}
+
+		pdu.SignalMappings = append(pdu.SignalMappings, mapping)
+	}
+
+	e.pduElems.ISignalIPDUs = append(e.pduElems.ISignalIPDUs, pdu)
+
+	return pdu
+}
+
+func (e *arxmlMessageExporter) getTransferProperty(sendType SignalSendType) string {
+	switch sendType {
+	case SignalSendTypeCyclic, SignalSendTypeIfActive, SignalSendTypeIfActiveWithRepetition:
+		return arxml.TransferPropertyPending
+	case SignalSendTypeOnWrite, SignalSendTypeOnWriteWithRepetition:
+		return arxml.TransferPropertyTriggered
+	case SignalSendTypeOnChange, SignalSendTypeOnChangeWithRepetition:
+		return arxml.TransferPropertyTriggeredOnChange
+	default:
+		return ""
+	}
+}
+
+// exportSignal exports the I-Signal and the system signal of the given signal
+// and it returns their short name.
+// A signal shared by more layouts is exported once.
+func (e *arxmlMessageExporter) exportSignal(sig Signal) string {
+	if sigName, ok := e.iSignalNames[sig.EntityID()]; ok {
+		return sigName
+	}
+
+	sigName := e.signalNames.get(sig.Name())
+	e.iSignalNames[sig.EntityID()] = sigName
+
+	netProps := &arxml.SWDataDefPropsConditional{}
+	var physProps *arxml.SWDataDefProps
+
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		netProps.BaseTypeRef = e.getBaseTypeRef(stdSig.typ.size, stdSig.typ.signed, stdSig.typ.kind == SignalTypeKindFloat)
+		netProps.CompuMethodRef = e.getSignalTypeCompuMethodRef(stdSig.typ)
+		netProps.DataConstrRef = e.getDataConstrRef(stdSig.typ)
+
+		if stdSig.unit != nil {
+			physProps = &arxml.SWDataDefProps{
+				Variants: []*arxml.SWDataDefPropsConditional{
+					{UnitRef: e.getUnitRef(stdSig.unit)},
+				},
+			}
+		}
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		netProps.BaseTypeRef = e.getBaseTypeRef(enumSig.Size(), false, false)
+		netProps.CompuMethodRef = e.getSignalEnumCompuMethodRef(enumSig.enum)
+	}
+
+	e.iSignalElems.ISignals = append(e.iSignalElems.ISignals, &arxml.ISignal{
+		ShortName:      sigName,
+		DataTypePolicy: "OVERRIDE",
+		InitValue: &arxml.InitValue{
+			Numerical: &arxml.NumericalValueSpecification{Value: sig.StartValue()},
+		},
+		Length: sig.Size(),
+		NetworkRepresentationProps: &arxml.SWDataDefProps{
+			Variants: []*arxml.SWDataDefPropsConditional{netProps},
+		},
+		SystemSignalRef: arxml.NewRef("SYSTEM-SIGNAL", e.systemSigPkgPath+"/"+sigName),
+	})
+
+	e.systemSigElems.SystemSignals = append(e.systemSigElems.SystemSignals, &arxml.SystemSignal{
+		ShortName:     sigName,
+		Desc:          arxml.NewDesc(sig.Desc()),
+		PhysicalProps: physProps,
+	})
+
+	return sigName
+}
+
+func (e *arxmlExporter) getBaseTypeRef(size int, signed, isFloat bool) *arxml.Ref {
+	name := fmt.Sprintf("uint%d", size)
+	encoding := arxml.BaseTypeEncodingNone
+
+	switch {
+	case isFloat:
+		name = fmt.Sprintf("float%d", size)
+		encoding = arxml.BaseTypeEncodingIEEE754
+	case signed:
+		name = fmt.Sprintf("sint%d", size)
+		encoding = arxml.BaseTypeEncodingTwosComplement
+	}
+
+	path, ok := e.baseTypePaths[name]
+	if !ok {
+		e.baseTypes = append(e.baseTypes, &arxml.SWBaseType{
+			ShortName: name,
+			Category:  "FIXED_LENGTH",
+			Size:      size,
+			Encoding:  encoding,
+		})
+
+		path = e.pkgPath("BaseTypes") + "/" + name
+		e.baseTypePaths[name] = path
+	}
+
+	return arxml.NewRef("SW-BASE-TYPE", path)
+}
+
+func (e *arxmlExporter) getSignalTypeCompuMethodRef(sigType *SignalType) *arxml.Ref {
+	if path, ok := e.compuMethodPaths.paths[sigType.entityID]; ok {
+		return arxml.NewRef("COMPU-METHOD", path)
+	}
+
+	compuMethod := &arxml.CompuMethod{
+		ShortName: e.compuMethodPaths.names.get(sigType.name),
+		Desc:      arxml.NewDesc(sigType.desc),
+		Category:  arxml.CompuMethodIdentical,
+	}
+
+	// a flag or an integer without a scale is written as identical
+	if sigType.kind != SignalTypeKindFlag && (sigType.scale != 1 || sigType.offset != 0) {
+		compuMethod.Category = arxml.CompuMethodLinear
+
+		scale := &arxml.CompuScale{
+			RationalCoeffs: &arxml.CompuRationalCoeffs{
+				Numerator:   []float64{sigType.offset, sigType.scale},
+				Denominator: []float64{1},
+			},
+		}
+
+		if sigType.scale != 0 {
+			lower := (sigType.min - sigType.offset) / sigType.scale
+			upper := (sigType.max - sigType.offset) / sigType.scale
+			if lower > upper {
+				lower, upper = upper, lower
+			}
+			scale.LowerLimit = arxml.NewLimit(lower)
+			scale.UpperLimit = arxml.NewLimit(upper)
+		}
+
+		compuMethod.InternalToPhys = &arxml.CompuScales{
+			Scales: []*arxml.CompuScale{scale},
+		}
+	}
+
+	e.compuMethods = append(e.compuMethods, compuMethod)
+
+	path := e.pkgPath("CompuMethods") + "/" + compuMethod.ShortName
+	e.compuMethodPaths.paths[sigType.entityID] = path
+
+	return arxml.NewRef("COMPU-METHOD", path)
+}
+
+func (e *arxmlExporter) getSignalEnumCompuMethodRef(sigEnum *SignalEnum) *arxml.Ref {
+	if path, ok := e.compuMethodPaths.paths[sigEnum.entityID]; ok {
+		return arxml.NewRef("COMPU-METHOD", path)
+	}
+
+	compuMethod := &arxml.CompuMethod{
+		ShortName:      e.compuMethodPaths.names.get(sigEnum.name),
+		Desc:           arxml.NewDesc(sigEnum.desc),
+		Category:       arxml.CompuMethodTextTable,
+		InternalToPhys: &arxml.CompuScales{},
+	}
+
+	for _, val := range sigEnum.values {
+		compuMethod.InternalToPhys.Scales = append(compuMethod.InternalToPhys.Scales, &arxml.CompuScale{
+			Desc:       arxml.NewDesc(val.desc),
+			LowerLimit: arxml.NewLimit(float64(val.index)),
+			UpperLimit: arxml.NewLimit(float64(val.index)),
+			Const:      &arxml.CompuConst{VT: val.name},
+		})
+	}
+
+	e.compuMethods = append(e.compuMethods, compuMethod)
+
+	path := e.pkgPath("CompuMethods") + "/" + compuMethod.ShortName
+	e.compuMethodPaths.paths[sigEnum.entityID] = path
+
+	return arxml.NewRef("COMPU-METHOD", path)
+}
+
+// getDataConstrRef returns the reference to the physical limits of the given signal type,
+// or nil if the type is a flag.
+func (e *arxmlExporter) getDataConstrRef(sigType *SignalType) *arxml.Ref {
+	if sigType.kind == SignalTypeKindFlag {
+		return nil
+	}
+
+	if path, ok := e.dataConstrPaths.paths[sigType.entityID]; ok {
+		return arxml.NewRef("DATA-CONSTR", path)
+	}
+
+	dataConstr := &arxml.DataConstr{
+		ShortName: e.dataConstrPaths.names.get(sigType.name + "_constr"),
+		Rules: []*arxml.DataConstrRule{
+			{
+				PhysConstrs: &arxml.Constrs{
+					LowerLimit: arxml.NewLimit(sigType.min),
+					UpperLimit: arxml.NewLimit(sigType.max),
+				},
+			},
+		},
+	}
+
+	e.dataConstrs = append(e.dataConstrs, dataConstr)
+
+	path := e.pkgPath("DataConstrs") + "/" + dataConstr.ShortName
+	e.dataConstrPaths.paths[sigType.entityID] = path
+
+	return arxml.NewRef("DATA-CONSTR", path)
+}
+
+func (e *arxmlExporter) getUnitRef(sigUnit *SignalUnit) *arxml.Ref {
+	if path, ok := e.unitPaths.paths[sigUnit.entityID]; ok {
+		return arxml.NewRef("UNIT", path)
+	}
+
+	unit := &arxml.Unit{
+		ShortName:   e.unitPaths.names.get(sigUnit.name),
+		DisplayName: sigUnit.symbol,
+	}
+
+	e.units = append(e.units, unit)
+
+	path := e.pkgPath("Units") + "/" + unit.ShortName
+	e.unitPaths.paths[sigUnit.entityID] = path
+
+	return arxml.NewRef("UNIT", path)
+}
@@ -0,0 +1,141 @@
+package acmelib
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportARXML(t *testing.T) {
+	assert := assert.New(t)
+
+	net, _ := importARXMLTestFile(assert)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(ExportARXML(buf, net))
+
+	content := buf.String()
+	assert.Contains(content, "<SHORT-NAME>sample_network</SHORT-NAME>")
+	assert.Contains(content, `<FRAME-PORT-REF DEST="FRAME-PORT">/sample_network/ECUs/ecu/powertrain_connector/engine_out</FRAME-PORT-REF>`)
+	assert.Contains(content, "<SHORT-NAME>diagnostic_pdu</SHORT-NAME>")
+	assert.Contains(content, "<CATEGORY>TEXTTABLE</CATEGORY>")
+	assert.NotContains(content, "Vector__XXX")
+
+	// should import the same network
+	expNet, warnings, err := ImportARXML("sample_network", bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Empty(warnings)
+
+	bus := net.Buses()[0]
+	expBus := expNet.Buses()[0]
+	assert.Equal(bus.Name(), expBus.Name())
+	assert.Equal(bus.Desc(), expBus.Desc())
+	assert.Equal(bus.Baudrate(), expBus.Baudrate())
+	assert.Equal(bus.Type(), expBus.Type())
+	assert.Len(expBus.NodeInterfaces(), len(bus.NodeInterfaces()))
+
+	for _, nodeInt := range bus.NodeInterfaces() {
+		expNodeInt, err := expBus.GetNodeInterfaceByNodeName(nodeInt.Node().Name())
+		assert.NoError(err)
+		assert.Equal(nodeInt.Node().Desc(), expNodeInt.Node().Desc())
+
+		msgs := nodeInt.SentMessages()
+		expMsgs := expNodeInt.SentMessages()
+		assert.Len(expMsgs, len(msgs))
+
+		for idx, msg := range msgs {
+			expMsg := expMsgs[idx]
+			assert.Equal(msg.Name(), expMsg.Name())
+			assert.Equal(msg.Desc(), expMsg.Desc())
+			assert.Equal(msg.GetCANID(), expMsg.GetCANID())
+			assert.Equal(msg.IsExtended(), expMsg.IsExtended())
+			assert.Equal(msg.SizeByte(), expMsg.SizeByte())
+			assert.Equal(msg.SendType(), expMsg.SendType())
+			assert.Equal(msg.CycleTime(), expMsg.CycleTime())
+			assert.Equal(msg.DelayTime(), expMsg.DelayTime())
+			assert.Equal(msg.StartDelayTime(), expMsg.StartDelayTime())
+			assert.Len(expMsg.Receivers(), len(msg.Receivers()))
+
+			assertEqualKCDLayouts(assert, msg.SignalLayout(), expMsg.SignalLayout())
+		}
+	}
+
+	engine, err := expBus.GetNodeInterfaceByNodeName("ecu")
+	assert.NoError(err)
+	rpm, err := engine.SentMessages()[0].GetSignalByName("rpm")
+	assert.NoError(err)
+	assert.Equal("Engine speed", rpm.Desc())
+	rpmStd, err := rpm.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindDecimal, rpmStd.Type().Kind())
+	assert.Equal(0.25, rpmStd.Type().Scale())
+	assert.Equal(8000.0, rpmStd.Type().Max())
+	assert.Equal("rpm", rpmStd.Unit().Symbol())
+
+	temperature, err := engine.SentMessages()[0].GetSignalByName("temperature")
+	assert.NoError(err)
+	assert.Equal(40.0, temperature.StartValue())
+
+	// should always export the same file
+	for range 5 {
+		otherBuf := new(bytes.Buffer)
+		assert.NoError(ExportARXML(otherBuf, net))
+		assert.Equal(content, otherBuf.String())
+	}
+}
+
+func Test_ExportARXML_MultiplexedLayers(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("net")
+	bus := NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := NewMessage("msg", 1, 8)
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	muxor, err := NewMuxorSignal("muxor", 2)
+	assert.NoError(err)
+	muxLayer, err := msg.SignalLayout().AddMultiplexedLayer(muxor, 0)
+	assert.NoError(err)
+	assert.NoError(ExportARXML(new(bytes.Buffer), net))
+
+	// should return an error because the nested multiplexed layer cannot be written
+	innerMuxor, err := NewMuxorSignal("inner_muxor", 2)
+	assert.NoError(err)
+	_, err = muxLayer.GetLayout(0).AddMultiplexedLayer(innerMuxor, 8)
+	assert.NoError(err)
+	assert.ErrorIs(ExportARXML(new(bytes.Buffer), net), ErrInvalidType)
+	assert.NoError(muxLayer.GetLayout(0).DeleteMultiplexedLayer(innerMuxor.EntityID()))
+
+	// should return an error because the message has two multiplexed layers
+	otherMuxor, err := NewMuxorSignal("other_muxor", 2)
+	assert.NoError(err)
+	_, err = msg.SignalLayout().AddMultiplexedLayer(otherMuxor, 16)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+	err = ExportARXML(buf, net)
+	assert.ErrorIs(err, ErrInvalidType)
+	var nameErr *NameError
+	assert.ErrorAs(err, &nameErr)
+	assert.Equal("other_muxor", nameErr.Name)
+	assert.Zero(buf.Len())
+}
+
+func Test_arxmlShortName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("engine_speed", arxmlShortName("engine speed"))
+	assert.Equal("temp__C_", arxmlShortName("temp [C]"))
+	assert.Equal("x0_pressure", arxmlShortName("0_pressure"))
+	assert.Equal("x", arxmlShortName(""))
+
+	names := make(arxmlNames)
+	assert.Equal("node", names.get("node"))
+	assert.Equal("node_1", names.get("node"))
+	assert.Equal("node_2", names.get("node"))
+}
@@ -0,0 +1,939 @@
+package acmelib
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/squadracorsepolito/acmelib/arxml"
+	"github.com/squadracorsepolito/acmelib/dbc"
+)
+
+// ARXMLWarning describes an element of an ARXML file that is not supported,
+// so it is skipped or it is only partially imported.
+type ARXMLWarning struct {
+	// Path is the AUTOSAR path of the element, like "/Package/Frame".
+	Path string
+	// Element is the tag of the element, like "CONTAINER-I-PDU".
+	Element string
+	// Reason describes what is not supported.
+	Reason string
+}
+
+func (w *ARXMLWarning) String() string {
+	return fmt.Sprintf("%s (%s): %s", w.Path, w.Element, w.Reason)
+}
+
+// ImportARXML imports an AUTOSAR (4.x) system description passed as [io.Reader]
+// and converts it to a [Network] named after the given filename.
+//
+// Every physical channel of a CAN cluster becomes a [Bus], named after the cluster
+// or, if the cluster has more channels, after the channel.
+// Every ECU instance becomes a [Node] with an interface for every bus it is connected to.
+// Every frame triggering becomes a [Message] sent by the ECU with the out frame port
+// and received by the ECUs with the in frame ports. The messages without a sender
+// are sent by a placeholder node with the same name used by the DBC files.
+// The I-Signals of the PDUs become [StandardSignal] or, when their CompuMethod is
+// a text table, [EnumSignal]. A multiplexed I-PDU becomes a [MultiplexedLayer]
+// whose muxor is named after the PDU with the "_selector" suffix.
+//
+// The elements that are not supported are skipped and described by
+// the returned slice of [ARXMLWarning].
+// It returns an error if the file is malformed, if a reference cannot be resolved,
+// or if the elements cannot be represented by a valid model.
+func ImportARXML(filename string, r io.Reader) (*Network, []*ARXMLWarning, error) {
+	root, err := arxml.Decode(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	idx, err := arxml.NewIndex(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	importer := newARXMLImporter(idx)
+	net, err := importer.importFile(filename, root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return net, importer.warnings, nil
+}
+
+type arxmlImporter struct {
+	idx *arxml.Index
+	net *Network
+
+	warnings []*ARXMLWarning
+
+	nodes      map[*arxml.ECUInstance]*Node
+	nextNodeID NodeID
+	dummyNode  *Node
+
+	flagSigType *SignalType
+	signalTypes map[string]*SignalType
+	signalUnits map[string]*SignalUnit
+	signalEnums map[string]*SignalEnum
+}
+
+func newARXMLImporter(idx *arxml.Index) *arxmlImporter {
+	return &arxmlImporter{
+		idx: idx,
+
+		warnings: []*ARXMLWarning{},
+
+		nodes: make(map[*arxml.ECUInstance]*Node),
+
+		flagSigType: NewFlagSignalType("flag"),
+		signalTypes: make(map[string]*SignalType),
+		signalUnits: make(map[string]*SignalUnit),
+		signalEnums: make(map[string]*SignalEnum),
+	}
+}
+
+func (i *arxmlImporter) errorf(path string, err error) error {
+	return fmt.Errorf("%s : %w", path, err)
+}
+
+func (i *arxmlImporter) warn(path, element, format string, args ...any) {
+	i.warnings = append(i.warnings, &ARXMLWarning{
+		Path:    path,
+		Element: element,
+		Reason:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (i *arxmlImporter) importFile(filename string, root *arxml.AUTOSAR) (*Network, error) {
+	i.net = NewNetwork(filename)
+
+	clusters := []*arxml.CANCluster{}
+	clusterPaths := []string{}
+
+	var walk func(parentPath string, pkgs []*arxml.Package)
+	walk = func(parentPath string, pkgs []*arxml.Package) {
+		for _, pkg := range pkgs {
+			pkgPath := parentPath + "/" + pkg.ShortName
+
+			if elems := pkg.Elements; elems != nil {
+				for _, other := range elems.Others {
+					i.warn(pkgPath+"/"+other.ShortName, other.XMLName.Local, "element not supported")
+				}
+
+				for _, ecu := range elems.ECUInstances {
+					node := NewNode(ecu.ShortName, i.getNextNodeID(), 0)
+					node.SetDesc(ecu.Desc.String())
+					i.nodes[ecu] = node
+				}
+
+				for _, cluster := range elems.CANClusters {
+					clusters = append(clusters, cluster)
+					clusterPaths = append(clusterPaths, pkgPath+"/"+cluster.ShortName)
+				}
+			}
+
+			walk(pkgPath, pkg.Packages)
+		}
+	}
+	walk("", root.Packages)
+
+	for idx, cluster := range clusters {
+		if err := i.importCANCluster(clusterPaths[idx], cluster); err != nil {
+			return nil, err
+		}
+	}
+
+	return i.net, nil
+}
+
+func (i *arxmlImporter) getNextNodeID() NodeID {
+	nodeID := i.nextNodeID
+	i.nextNodeID++
+	return nodeID
+}
+
+func (i *arxmlImporter) connectNode(bus *Bus, node *Node) (*NodeInterface, error) {
+	for _, nodeInt := range node.interfaces {
+		if nodeInt.parentBus == bus {
+			return nodeInt, nil
+		}
+	}
+
+	node.AddInterface()
+	nodeInt := node.interfaces[len(node.interfaces)-1]
+
+	if err := bus.AddNodeInterface(nodeInt); err != nil {
+		return nil, i.errorf(bus.name, err)
+	}
+
+	return nodeInt, nil
+}
+
+// connectOwner returns the interface connected to the given bus of the node
+// that owns the connector or the port with the given path.
+func (i *arxmlImporter) connectOwner(bus *Bus, path string) (*NodeInterface, error) {
+	ecu, ok := i.idx.Owner(path)
+	if !ok {
+		return nil, &arxml.ReferenceError{Path: path, Err: arxml.ErrNotFound}
+	}
+
+	return i.connectNode(bus, i.nodes[ecu])
+}
+
+func (i *arxmlImporter) importCANCluster(path string, cluster *arxml.CANCluster) error {
+	if len(cluster.Variants) == 0 {
+		return nil
+	}
+
+	for _, variant := range cluster.Variants[1:] {
+		i.warn(path, "CAN-CLUSTER-CONDITIONAL", "variant with %d channels skipped, only the first variant is imported", len(variant.PhysicalChannels))
+	}
+
+	variant := cluster.Variants[0]
+	for _, channel := range variant.PhysicalChannels {
+		busName := cluster.ShortName
+		if len(variant.PhysicalChannels) > 1 {
+			busName = channel.ShortName
+		}
+
+		if err := i.importCANPhysicalChannel(path+"/"+channel.ShortName, busName, cluster, variant, channel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *arxmlImporter) importCANPhysicalChannel(path, busName string, cluster *arxml.CANCluster, variant *arxml.CANClusterConditional, channel *arxml.CANPhysicalChannel) error {
+	bus := NewBus(busName)
+	bus.SetDesc(cluster.Desc.String())
+	bus.SetBaudrate(variant.Baudrate)
+
+	if variant.CANFDBaudrate > 0 {
+		bus.SetType(BusTypeCANFD)
+		bus.SetDataBaudrate(variant.CANFDBaudrate)
+	}
+
+	// the type of the bus limits the size and the format of the messages
+	for _, triggering := range channel.FrameTriggerings {
+		isFD := triggering.FrameTxBehavior == arxml.FrameBehaviorCANFD || triggering.FrameRxBehavior == arxml.FrameBehaviorCANFD
+		if frame, err := arxml.Resolve[*arxml.CANFrame](i.idx, triggering.FrameRef); err == nil && frame.FrameLength > 8 {
+			isFD = true
+		}
+
+		if isFD {
+			bus.SetType(BusTypeCANFD)
+		} else if triggering.AddressingMode == arxml.AddressingModeExtended && bus.typ == BusTypeCAN2A {
+			bus.SetType(BusTypeCAN2B)
+		}
+	}
+
+	if err := i.net.AddBus(bus); err != nil {
+		return i.errorf(path, err)
+	}
+
+	for _, connRef := range channel.CommConnectors {
+		if connRef.Ref == nil {
+			continue
+		}
+
+		if _, err := i.connectOwner(bus, connRef.Ref.Path); err != nil {
+			return i.errorf(path, err)
+		}
+	}
+
+	for _, triggering := range channel.FrameTriggerings {
+		if err := i.importCANFrameTriggering(path+"/"+triggering.ShortName, bus, triggering); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *arxmlImporter) importCANFrameTriggering(path string, bus *Bus, triggering *arxml.CANFrameTriggering) error {
+	frame, err := arxml.Resolve[*arxml.CANFrame](i.idx, triggering.FrameRef)
+	if err != nil {
+		return i.errorf(path, err)
+	}
+	framePath := triggering.FrameRef.Path
+
+	canID := CANID(triggering.Identifier)
+	msg := NewMessage(frame.ShortName, MessageID(canID), frame.FrameLength)
+	msg.SetDesc(frame.Desc.String())
+
+	if triggering.AddressingMode == arxml.AddressingModeExtended {
+		if err := msg.SetCANIDFormat(CANIDFormatExtended); err != nil {
+			return i.errorf(path, err)
+		}
+	}
+
+	if err := msg.SetStaticCANID(canID); err != nil {
+		return i.errorf(path, err)
+	}
+
+	if err := i.importFramePDUs(framePath, msg, frame); err != nil {
+		return err
+	}
+
+	// the sender and the receivers
+	var sender *NodeInterface
+	receivers := []*NodeInterface{}
+
+	for _, portRef := range triggering.FramePortRefs {
+		port, err := arxml.Resolve[*arxml.Port](i.idx, portRef)
+		if err != nil {
+			return i.errorf(path, err)
+		}
+
+		nodeInt, err := i.connectOwner(bus, portRef.Path)
+		if err != nil {
+			return i.errorf(path, err)
+		}
+
+		if port.Direction != arxml.DirectionOut {
+			receivers = append(receivers, nodeInt)
+			continue
+		}
+
+		if sender != nil {
+			i.warn(portRef.Path, "FRAME-PORT", "frame sent by more ECUs, only the first one is imported")
+			continue
+		}
+		sender = nodeInt
+	}
+
+	if sender == nil {
+		if i.dummyNode == nil {
+			i.dummyNode = NewNode(dbc.DummyNode, i.getNextNodeID(), 0)
+		}
+
+		nodeInt, err := i.connectNode(bus, i.dummyNode)
+		if err != nil {
+			return err
+		}
+		sender = nodeInt
+	}
+
+	for _, rec := range receivers {
+		if rec == sender {
+			continue
+		}
+
+		if err := msg.AddReceiver(rec); err != nil {
+			return i.errorf(path, err)
+		}
+	}
+
+	if err := sender.AddSentMessage(msg); err != nil {
+		return i.errorf(path, err)
+	}
+
+	return nil
+}
+
+// importFramePDUs imports the signals and the timing of the PDUs carried by the frame.
+func (i *arxmlImporter) importFramePDUs(framePath string, msg *Message, frame *arxml.CANFrame) error {
+	hasTiming := false
+
+	for _, mapping := range frame.PDUMappings {
+		if mapping.PDURef == nil {
+			continue
+		}
+
+		pduPath := mapping.PDURef.Path
+		elem, ok := i.idx.Get(pduPath)
+		if !ok {
+			return i.errorf(framePath, &arxml.ReferenceError{Path: pduPath, Err: arxml.ErrNotFound})
+		}
+
+		switch pdu := elem.(type) {
+		case *arxml.ISignalIPDU:
+			if mapping.StartPosition%8 != 0 {
+				i.warn(pduPath, "I-SIGNAL-I-PDU", "start position %d in the frame is not byte aligned", mapping.StartPosition)
+				continue
+			}
+
+			if err := i.importISignalIPDU(pduPath, pdu, mapping.StartPosition, msg.InsertSignal); err != nil {
+				return err
+			}
+
+			if msg.desc == "" {
+				msg.SetDesc(pdu.Desc.String())
+			}
+
+			if !hasTiming {
+				hasTiming = i.importIPDUTiming(msg, pdu)
+			}
+
+		case *arxml.MultiplexedIPDU:
+			if mapping.StartPosition != 0 {
+				i.warn(pduPath, "MULTIPLEXED-I-PDU", "start position %d in the frame is not supported", mapping.StartPosition)
+				continue
+			}
+
+			pduHasTiming, err := i.importMultiplexedIPDU(pduPath, msg, pdu)
+			if err != nil {
+				return err
+			}
+
+			if msg.desc == "" {
+				msg.SetDesc(pdu.Desc.String())
+			}
+
+			if !hasTiming {
+				hasTiming = pduHasTiming
+			}
+
+		case *arxml.UnknownElement:
+			i.warn(pduPath, pdu.XMLName.Local, "PDU not supported, its content is skipped")
+
+		default:
+			return i.errorf(framePath, &arxml.ReferenceError{Path: pduPath, Err: arxml.ErrInvalidDest})
+		}
+	}
+
+	return nil
+}
+
+// importIPDUTiming sets the timing of the message from the one of the PDU.
+// It returns true if the PDU has a timing.
+func (i *arxmlImporter) importIPDUTiming(msg *Message, pdu *arxml.ISignalIPDU) bool {
+	if len(pdu.Timings) == 0 {
+		return false
+	}
+
+	timing := pdu.Timings[0]
+
+	if timing.MinimumDelay != nil {
+		msg.SetDelayTime(secondsToMillis(*timing.MinimumDelay))
+	}
+
+	if timing.Mode == nil || timing.Mode.TrueTiming == nil {
+		return true
+	}
+
+	trueTiming := timing.Mode.TrueTiming
+	if cyclic := trueTiming.Cyclic; cyclic != nil && cyclic.TimePeriod != nil {
+		msg.SetCycleTime(secondsToMillis(cyclic.TimePeriod.Value))
+
+		if cyclic.TimeOffset != nil {
+			msg.SetStartDelayTime(secondsToMillis(cyclic.TimeOffset.Value))
+		}
+
+		if trueTiming.Event != nil {
+			msg.SetSendType(MessageSendTypeCyclicAndTriggered)
+		} else {
+			msg.SetSendType(MessageSendTypeCyclic)
+		}
+	}
+
+	return true
+}
+
+func secondsToMillis(seconds float64) int {
+	return int(math.Round(seconds * 1000))
+}
+
+// arxmlSignalInserter inserts a signal into a layout.
+type arxmlSignalInserter func(sig Signal, startPos int) error
+
+func (i *arxmlImporter) getSignalStartPos(mapping *arxml.ISignalToIPDUMapping, offset int) int {
+	startPos := mapping.StartPosition + offset
+	if mapping.ByteOrder == arxml.ByteOrderBigEndian {
+		return StartPosFromBigEndian(startPos)
+	}
+	return startPos
+}
+
+func (i *arxmlImporter) importISignalIPDU(pduPath string, pdu *arxml.ISignalIPDU, offset int, insert arxmlSignalInserter) error {
+	for _, mapping := range pdu.SignalMappings {
+		mappingPath := pduPath + "/" + mapping.ShortName
+
+		if mapping.ISignalRef == nil {
+			if mapping.ISignalGroupRef != nil {
+				i.warn(mappingPath, "I-SIGNAL-TO-I-PDU-MAPPING", "signal group %q not supported", mapping.ISignalGroupRef.Path)
+			}
+			continue
+		}
+
+		sig, err := i.importISignalMapping(mappingPath, mapping)
+		if err != nil {
+			return err
+		}
+
+		if err := insert(sig, i.getSignalStartPos(mapping, offset)); err != nil {
+			return i.errorf(mappingPath, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *arxmlImporter) importISignalMapping(mappingPath string, mapping *arxml.ISignalToIPDUMapping) (Signal, error) {
+	isig, err := arxml.Resolve[*arxml.ISignal](i.idx, mapping.ISignalRef)
+	if err != nil {
+		return nil, i.errorf(mappingPath, err)
+	}
+
+	sig, err := i.importISignal(mapping.ISignalRef.Path, isig)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mapping.ByteOrder {
+	case arxml.ByteOrderBigEndian:
+		sig.SetEndianness(EndiannessBigEndian)
+	case arxml.ByteOrderOpaque:
+		i.warn(mappingPath, "I-SIGNAL-TO-I-PDU-MAPPING", "opaque byte order imported as little endian")
+	}
+
+	return sig, nil
+}
+
+func (i *arxmlImporter) importMultiplexedIPDU(pduPath string, msg *Message, pdu *arxml.MultiplexedIPDU) (bool, error) {
+	hasTiming := false
+
+	muxor, err := NewMuxorSignal(pdu.ShortName+"_selector", getValueFromSize(pdu.SelectorFieldLength))
+	if err != nil {
+		return false, i.errorf(pduPath, err)
+	}
+
+	muxorStartPos := pdu.SelectorFieldStartPosition
+	if pdu.SelectorFieldByteOrder == arxml.ByteOrderBigEndian {
+		muxor.SetEndianness(EndiannessBigEndian)
+		muxorStartPos = StartPosFromBigEndian(muxorStartPos)
+	}
+
+	muxLayer, err := msg.layout.AddMultiplexedLayer(muxor, muxorStartPos)
+	if err != nil {
+		return false, i.errorf(pduPath, err)
+	}
+
+	for _, staticPart := range pdu.StaticParts {
+		staticPDU, err := arxml.Resolve[*arxml.ISignalIPDU](i.idx, staticPart.IPDURef)
+		if err != nil {
+			return false, i.errorf(pduPath, err)
+		}
+
+		if err := i.importISignalIPDU(staticPart.IPDURef.Path, staticPDU, 0, msg.InsertSignal); err != nil {
+			return false, err
+		}
+
+		if !hasTiming {
+			hasTiming = i.importIPDUTiming(msg, staticPDU)
+		}
+	}
+
+	if pdu.DynamicPart == nil {
+		return hasTiming, nil
+	}
+
+	// the same I-Signal in more alternatives is inserted once
+	type muxedSignal struct {
+		mappingPath string
+		mapping     *arxml.ISignalToIPDUMapping
+		layoutIDs   []int
+	}
+
+	muxedSignals := []*muxedSignal{}
+	muxedSignalPaths := make(map[string]*muxedSignal)
+
+	for _, alt := range pdu.DynamicPart.Alternatives {
+		altPDU, err := arxml.Resolve[*arxml.ISignalIPDU](i.idx, alt.IPDURef)
+		if err != nil {
+			return false, i.errorf(pduPath, err)
+		}
+		altPath := alt.IPDURef.Path
+
+		if !hasTiming {
+			hasTiming = i.importIPDUTiming(msg, altPDU)
+		}
+
+		for _, mapping := range altPDU.SignalMappings {
+			mappingPath := altPath + "/" + mapping.ShortName
+
+			if mapping.ISignalRef == nil {
+				if mapping.ISignalGroupRef != nil {
+					i.warn(mappingPath, "I-SIGNAL-TO-I-PDU-MAPPING", "signal group %q not supported", mapping.ISignalGroupRef.Path)
+				}
+				continue
+			}
+
+			sigPath := mapping.ISignalRef.Path
+			if muxedSig, ok := muxedSignalPaths[sigPath]; ok {
+				muxedSig.layoutIDs = append(muxedSig.layoutIDs, alt.SelectorFieldCode)
+				continue
+			}
+
+			muxedSig := &muxedSignal{
+				mappingPath: mappingPath,
+				mapping:     mapping,
+				layoutIDs:   []int{alt.SelectorFieldCode},
+			}
+			muxedSignals = append(muxedSignals, muxedSig)
+			muxedSignalPaths[sigPath] = muxedSig
+		}
+	}
+
+	for _, muxedSig := range muxedSignals {
+		sig, err := i.importISignalMapping(muxedSig.mappingPath, muxedSig.mapping)
+		if err != nil {
+			return false, err
+		}
+
+		if err := muxLayer.InsertSignal(sig, i.getSignalStartPos(muxedSig.mapping, 0), muxedSig.layoutIDs...); err != nil {
+			return false, i.errorf(muxedSig.mappingPath, err)
+		}
+	}
+
+	return hasTiming, nil
+}
+
+// arxmlSignalProps holds the properties of an I-Signal,
+// merged from the ones of the I-Signal and of its system signal.
+type arxmlSignalProps struct {
+	baseTypeRef    *arxml.Ref
+	compuMethodRef *arxml.Ref
+	dataConstrRef  *arxml.Ref
+	unitRef        *arxml.Ref
+}
+
+func (p *arxmlSignalProps) merge(cond *arxml.SWDataDefPropsConditional) {
+	if cond == nil {
+		return
+	}
+
+	if p.baseTypeRef == nil {
+		p.baseTypeRef = cond.BaseTypeRef
+	}
+	if p.compuMethodRef == nil {
+		p.compuMethodRef = cond.CompuMethodRef
+	}
+	if p.dataConstrRef == nil {
+		p.dataConstrRef = cond.DataConstrRef
+	}
+	if p.unitRef == nil {
+		p.unitRef = cond.UnitRef
+	}
+}
+
+func (i *arxmlImporter) importISignal(path string, isig *arxml.ISignal) (Signal, error) {
+	props := &arxmlSignalProps{}
+	desc := isig.Desc.String()
+
+	// the network representation of the I-Signal has the priority
+	props.merge(isig.NetworkRepresentationProps.Conditional())
+
+	if isig.SystemSignalRef != nil {
+		sysSig, err := arxml.Resolve[*arxml.SystemSignal](i.idx, isig.SystemSignalRef)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+
+		props.merge(sysSig.PhysicalProps.Conditional())
+
+		if sysDesc := sysSig.Desc.String(); sysDesc != "" {
+			desc = sysDesc
+		}
+	}
+
+	var compuMethod *arxml.CompuMethod
+	if props.compuMethodRef != nil {
+		cm, err := arxml.Resolve[*arxml.CompuMethod](i.idx, props.compuMethodRef)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		compuMethod = cm
+
+		if props.unitRef == nil {
+			props.unitRef = cm.UnitRef
+		}
+	}
+
+	var sig Signal
+	if compuMethod != nil && compuMethod.Category == arxml.CompuMethodTextTable {
+		enumSig, err := i.importEnumSignal(path, isig, props.compuMethodRef.Path, compuMethod)
+		if err != nil {
+			return nil, err
+		}
+		sig = enumSig
+	} else {
+		stdSig, err := i.importStandardSignal(path, isig, props, compuMethod)
+		if err != nil {
+			return nil, err
+		}
+		sig = stdSig
+	}
+
+	sig.SetDesc(desc)
+
+	if isig.InitValue != nil && isig.InitValue.Numerical != nil {
+		sig.SetStartValue(isig.InitValue.Numerical.Value)
+	}
+
+	return sig, nil
+}
+
+func (i *arxmlImporter) importEnumSignal(path string, isig *arxml.ISignal, compuMethodPath string, compuMethod *arxml.CompuMethod) (*EnumSignal, error) {
+	enumKey := fmt.Sprintf("%s_%d", compuMethodPath, isig.Length)
+
+	sigEnum, ok := i.signalEnums[enumKey]
+	if !ok {
+		sigEnum = NewSignalEnum(compuMethod.ShortName)
+
+		if compuMethod.InternalToPhys != nil {
+			for _, scale := range compuMethod.InternalToPhys.Scales {
+				lower, hasLower := scale.LowerLimit.Float()
+				if !scale.IsText() || !hasLower {
+					i.warn(compuMethodPath, "COMPU-SCALE", "scale without a text or a lower limit skipped")
+					continue
+				}
+
+				if upper, hasUpper := scale.UpperLimit.Float(); hasUpper && upper != lower {
+					i.warn(compuMethodPath, "COMPU-SCALE", "range %g-%g of %q reduced to the value %g", lower, upper, scale.Const.VT, lower)
+				}
+
+				if _, err := sigEnum.AddValue(int(lower), scale.Const.VT); err != nil {
+					return nil, i.errorf(compuMethodPath, err)
+				}
+			}
+		}
+
+		// the enum must be as big as the signal
+		if sigEnum.Size() < isig.Length {
+			sigEnum.SetFixedSize(true)
+			if err := sigEnum.UpdateSize(isig.Length); err != nil {
+				return nil, i.errorf(compuMethodPath, err)
+			}
+		}
+
+		i.signalEnums[enumKey] = sigEnum
+	}
+
+	enumSig, err := NewEnumSignal(isig.ShortName, sigEnum)
+	if err != nil {
+		return nil, i.errorf(path, err)
+	}
+
+	if enumSig.Size() > isig.Length {
+		return nil, i.errorf(path, newSizeError(isig.Length, ErrTooSmall))
+	}
+
+	return enumSig, nil
+}
+
+// getLinearScale returns the factor, the offset and the limits of the internal values
+// of the given CompuMethod.
+func (i *arxmlImporter) getLinearScale(path string, compuMethod *arxml.CompuMethod) (factor, offset float64, scale *arxml.CompuScale) {
+	factor = 1
+	offset = 0
+
+	if compuMethod == nil {
+		return factor, offset, nil
+	}
+
+	switch compuMethod.Category {
+	case "", arxml.CompuMethodIdentical:
+		return factor, offset, nil
+
+	case arxml.CompuMethodLinear, arxml.CompuMethodScaleLinear, arxml.CompuMethodScaleLinearAndTextTable:
+
+	default:
+		i.warn(path, "COMPU-METHOD", "category %s not supported, imported as identical", compuMethod.Category)
+		return factor, offset, nil
+	}
+
+	if compuMethod.InternalToPhys == nil {
+		return factor, offset, nil
+	}
+
+	found := false
+	for _, cs := range compuMethod.InternalToPhys.Scales {
+		if cs.IsText() {
+			i.warn(path, "COMPU-SCALE", "text %q of a linear CompuMethod skipped", cs.Const.VT)
+			continue
+		}
+
+		csFactor, csOffset, ok := cs.RationalCoeffs.Linear()
+		if !ok {
+			i.warn(path, "COMPU-SCALE", "scale without linear coefficients skipped")
+			continue
+		}
+
+		if found {
+			i.warn(path, "COMPU-SCALE", "more linear scales, only the first one is imported")
+			continue
+		}
+
+		found = true
+		factor = csFactor
+		offset = csOffset
+		scale = cs
+	}
+
+	return factor, offset, scale
+}
+
+func (i *arxmlImporter) importStandardSignal(path string, isig *arxml.ISignal, props *arxmlSignalProps, compuMethod *arxml.CompuMethod) (*StandardSignal, error) {
+	compuMethodPath := path
+	if props.compuMethodRef != nil {
+		compuMethodPath = props.compuMethodRef.Path
+	}
+	factor, offset, scale := i.getLinearScale(compuMethodPath, compuMethod)
+
+	size := isig.Length
+	signed := false
+	isFloat := false
+
+	if props.baseTypeRef != nil {
+		baseType, err := arxml.Resolve[*arxml.SWBaseType](i.idx, props.baseTypeRef)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+
+		switch baseType.Encoding {
+		case arxml.BaseTypeEncodingTwosComplement:
+			signed = true
+		case arxml.BaseTypeEncodingIEEE754:
+			if size == 32 || size == 64 {
+				isFloat = true
+			} else {
+				i.warn(props.baseTypeRef.Path, "SW-BASE-TYPE", "IEEE754 encoding with %d bits imported as an integer", size)
+			}
+		}
+	}
+
+	// the limits of the physical values
+	var min, max *float64
+	if props.dataConstrRef != nil {
+		dataConstr, err := arxml.Resolve[*arxml.DataConstr](i.idx, props.dataConstrRef)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+
+		if len(dataConstr.Rules) > 0 && dataConstr.Rules[0].PhysConstrs != nil {
+			constrs := dataConstr.Rules[0].PhysConstrs
+			if lower, ok := constrs.LowerLimit.Float(); ok {
+				min = &lower
+			}
+			if upper, ok := constrs.UpperLimit.Float(); ok {
+				max = &upper
+			}
+		}
+	}
+
+	if scale != nil && min == nil && max == nil {
+		lower, hasLower := scale.LowerLimit.Float()
+		upper, hasUpper := scale.UpperLimit.Float()
+		if hasLower && hasUpper {
+			physLower := lower*factor + offset
+			physUpper := upper*factor + offset
+			if physLower > physUpper {
+				physLower, physUpper = physUpper, physLower
+			}
+			min = &physLower
+			max = &physUpper
+		}
+	}
+
+	sigType, err := i.getSignalType(path, size, signed, isFloat, factor, offset, min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	stdSig, err := NewStandardSignal(isig.ShortName, sigType)
+	if err != nil {
+		return nil, i.errorf(path, err)
+	}
+
+	if props.unitRef != nil {
+		sigUnit, err := i.getSignalUnit(props.unitRef)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		stdSig.SetUnit(sigUnit)
+	}
+
+	return stdSig, nil
+}
+
+func (i *arxmlImporter) getSignalType(path string, size int, signed, isFloat bool, factor, offset float64, min, max *float64) (*SignalType, error) {
+	if size == 1 && !signed && !isFloat && factor == 1 && offset == 0 && min == nil && max == nil {
+		return i.flagSigType, nil
+	}
+
+	signStr := "u"
+	if isFloat {
+		signStr = "f"
+	} else if signed {
+		signStr = "s"
+	}
+	sigTypeKey := fmt.Sprintf("%s%d_%g_%g", signStr, size, factor, offset)
+	if min != nil {
+		sigTypeKey += fmt.Sprintf("_min%g", *min)
+	}
+	if max != nil {
+		sigTypeKey += fmt.Sprintf("_max%g", *max)
+	}
+
+	if sigType, ok := i.signalTypes[sigTypeKey]; ok {
+		return sigType, nil
+	}
+
+	var sigType *SignalType
+	if isFloat {
+		floatSigType, err := NewFloatSignalType(sigTypeKey, size)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		sigType = floatSigType
+	} else if isDecimal(factor) || isDecimal(offset) {
+		decSigType, err := NewDecimalSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		sigType = decSigType
+	} else {
+		intSigType, err := NewIntegerSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		sigType = intSigType
+	}
+
+	sigType.SetScale(factor)
+	sigType.SetOffset(offset)
+
+	if min != nil {
+		sigType.SetMin(*min)
+	}
+	if max != nil {
+		sigType.SetMax(*max)
+	}
+
+	i.signalTypes[sigTypeKey] = sigType
+
+	return sigType, nil
+}
+
+func (i *arxmlImporter) getSignalUnit(unitRef *arxml.Ref) (*SignalUnit, error) {
+	if sigUnit, ok := i.signalUnits[unitRef.Path]; ok {
+		return sigUnit, nil
+	}
+
+	unit, err := arxml.Resolve[*arxml.Unit](i.idx, unitRef)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol := unit.DisplayName
+	if symbol == "" {
+		symbol = unit.ShortName
+	}
+
+	sigUnit := NewSignalUnit(unit.ShortName, SignalUnitKindCustom, symbol)
+	i.signalUnits[unitRef.Path] = sigUnit
+
+	return sigUnit, nil
+}
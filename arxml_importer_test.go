@@ -0,0 +1,186 @@
+package acmelib
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib/arxml"
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/stretchr/testify/assert"
+)
+
+const arxmlTestFile = "testdata/sample.arxml"
+
+func importARXMLTestFile(assert *assert.Assertions) (*Network, []*ARXMLWarning) {
+	file, err := os.Open(arxmlTestFile)
+	assert.NoError(err)
+	defer file.Close()
+
+	net, warnings, err := ImportARXML("sample_network", file)
+	assert.NoError(err)
+
+	return net, warnings
+}
+
+func Test_ImportARXML(t *testing.T) {
+	assert := assert.New(t)
+
+	net, warnings := importARXMLTestFile(assert)
+	assert.Equal("sample_network", net.Name())
+
+	buses := net.Buses()
+	assert.Len(buses, 1)
+	bus := buses[0]
+	assert.Equal("powertrain", bus.Name())
+	assert.Equal("Powertrain bus", bus.Desc())
+	assert.Equal(500_000, bus.Baudrate())
+	assert.Equal(BusTypeCAN2B, bus.Type())
+
+	// should connect the ECUs referenced by the channel
+	assert.Len(bus.NodeInterfaces(), 4)
+
+	ecuInt, err := bus.GetNodeInterfaceByNodeName("ecu")
+	assert.NoError(err)
+	assert.Equal(NodeID(0), ecuInt.Node().ID())
+	assert.Equal("Engine control unit", ecuInt.Node().Desc())
+
+	// engine message
+	assert.Len(ecuInt.SentMessages(), 1)
+	engine := ecuInt.SentMessages()[0]
+	assert.Equal("engine", engine.Name())
+	assert.Equal("Engine status", engine.Desc())
+	assert.Equal(CANID(0x100), engine.GetCANID())
+	assert.Equal(8, engine.SizeByte())
+	assert.Equal(10, engine.CycleTime())
+	assert.Equal(2, engine.DelayTime())
+	assert.Equal(5, engine.StartDelayTime())
+	assert.Equal(MessageSendTypeCyclic, engine.SendType())
+
+	receivers := engine.Receivers()
+	assert.Len(receivers, 1)
+	assert.Equal("dashboard", receivers[0].Node().Name())
+
+	rpm, err := engine.GetSignalByName("rpm")
+	assert.NoError(err)
+	assert.Equal("Engine speed", rpm.Desc())
+	rpmStd, err := rpm.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindDecimal, rpmStd.Type().Kind())
+	assert.Equal(0.25, rpmStd.Type().Scale())
+	assert.Equal(0.0, rpmStd.Type().Min())
+	assert.Equal(8000.0, rpmStd.Type().Max())
+	assert.Equal("rpm", rpmStd.Unit().Symbol())
+
+	temperature, err := engine.GetSignalByName("temperature")
+	assert.NoError(err)
+	assert.Equal(40.0, temperature.StartValue())
+	temperatureStd, err := temperature.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindInteger, temperatureStd.Type().Kind())
+	assert.Equal(-40.0, temperatureStd.Type().Offset())
+	assert.Nil(temperatureStd.Unit())
+
+	gear, err := engine.GetSignalByName("gear")
+	assert.NoError(err)
+	gearEnum, err := gear.ToEnum()
+	assert.NoError(err)
+	assert.Equal(3, gearEnum.Size())
+	assert.Len(gearEnum.Enum().Values(), 3)
+
+	pressure, err := engine.GetSignalByName("pressure")
+	assert.NoError(err)
+	assert.Equal(EndiannessBigEndian, pressure.Endianness())
+	assert.Equal(32, pressure.StartPos())
+
+	// diagnostic message
+	dashboardInt, err := bus.GetNodeInterfaceByNodeName("dashboard")
+	assert.NoError(err)
+	assert.Len(dashboardInt.SentMessages(), 1)
+	diag := dashboardInt.SentMessages()[0]
+	assert.True(diag.IsExtended())
+	assert.Equal(CANID(0x1ABCDEF), diag.GetCANID())
+	assert.Equal(100, diag.CycleTime())
+	assert.Equal(MessageSendTypeCyclicAndTriggered, diag.SendType())
+
+	fault, err := diag.GetSignalByName("fault")
+	assert.NoError(err)
+	faultStd, err := fault.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFlag, faultStd.Type().Kind())
+	assert.Equal(63, fault.StartPos())
+
+	muxLayers := diag.SignalLayout().MultiplexedLayers()
+	assert.Len(muxLayers, 1)
+	muxLayer := muxLayers[0]
+	assert.Equal("diagnostic_pdu_selector", muxLayer.Muxor().Name())
+	assert.Equal(4, muxLayer.GetLayoutCount())
+	assert.Equal(1, muxLayer.GetLayout(0).SignalCount())
+	assert.Equal(1, muxLayer.GetLayout(1).SignalCount())
+
+	current, err := muxLayer.GetSignalByName("current")
+	assert.NoError(err)
+	currentStd, err := current.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFloat, currentStd.Type().Kind())
+	assert.Equal("A", currentStd.Unit().Symbol())
+
+	// should send the frames without an out port with the placeholder node
+	dummyInt, err := bus.GetNodeInterfaceByNodeName(dbc.DummyNode)
+	assert.NoError(err)
+	assert.Len(dummyInt.SentMessages(), 1)
+	assert.Equal("container", dummyInt.SentMessages()[0].Name())
+
+	warningStrs := []string{}
+	for _, warning := range warnings {
+		warningStrs = append(warningStrs, warning.String())
+	}
+	assert.ElementsMatch([]string{
+		"/Network/Clusters/body (LIN-CLUSTER): element not supported",
+		"/Network/PDUs/container_pdu (CONTAINER-I-PDU): element not supported",
+		"/Network/Signals/status_group (I-SIGNAL-GROUP): element not supported",
+		`/Network/PDUs/engine_pdu/status_group_mapping (I-SIGNAL-TO-I-PDU-MAPPING): signal group "/Network/Signals/status_group" not supported`,
+		`/Network/CompuMethods/gear_method (COMPU-SCALE): range 2-5 of "other" reduced to the value 2`,
+		"/Network/CompuMethods/voltage_method (COMPU-METHOD): category RAT_FUNC not supported, imported as identical",
+		"/Network/PDUs/container_pdu (CONTAINER-I-PDU): PDU not supported, its content is skipped",
+	}, warningStrs)
+}
+
+func Test_ImportARXML_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	importARXML := func(elements string) error {
+		content := `<AUTOSAR><AR-PACKAGES><AR-PACKAGE><SHORT-NAME>P</SHORT-NAME><ELEMENTS>` + elements + `</ELEMENTS></AR-PACKAGE></AR-PACKAGES></AUTOSAR>`
+		_, _, err := ImportARXML("test", strings.NewReader(content))
+		return err
+	}
+
+	channel := func(triggering string) string {
+		return `<CAN-CLUSTER><SHORT-NAME>c</SHORT-NAME><CAN-CLUSTER-VARIANTS><CAN-CLUSTER-CONDITIONAL><PHYSICAL-CHANNELS><CAN-PHYSICAL-CHANNEL>
+			<SHORT-NAME>ch</SHORT-NAME><FRAME-TRIGGERINGS>` + triggering + `</FRAME-TRIGGERINGS>
+			</CAN-PHYSICAL-CHANNEL></PHYSICAL-CHANNELS></CAN-CLUSTER-CONDITIONAL></CAN-CLUSTER-VARIANTS></CAN-CLUSTER>`
+	}
+
+	// should return ErrNotFound for a missing frame
+	err := importARXML(channel(`<CAN-FRAME-TRIGGERING><SHORT-NAME>t</SHORT-NAME><FRAME-REF DEST="CAN-FRAME">/P/missing</FRAME-REF><IDENTIFIER>1</IDENTIFIER></CAN-FRAME-TRIGGERING>`))
+	assert.ErrorIs(err, arxml.ErrNotFound)
+
+	// should return ErrInvalidDest for a reference to another element
+	err = importARXML(channel(`<CAN-FRAME-TRIGGERING><SHORT-NAME>t</SHORT-NAME><FRAME-REF DEST="CAN-FRAME">/P/c</FRAME-REF><IDENTIFIER>1</IDENTIFIER></CAN-FRAME-TRIGGERING>`))
+	assert.ErrorIs(err, arxml.ErrInvalidDest)
+
+	// should return ErrDuplicatedPath for two elements with the same path
+	err = importARXML(`<UNIT><SHORT-NAME>u</SHORT-NAME></UNIT><UNIT><SHORT-NAME>u</SHORT-NAME></UNIT>`)
+	assert.ErrorIs(err, arxml.ErrDuplicatedPath)
+
+	// should return an error for overlapping signals
+	err = importARXML(channel(`<CAN-FRAME-TRIGGERING><SHORT-NAME>t</SHORT-NAME><FRAME-REF DEST="CAN-FRAME">/P/f</FRAME-REF><IDENTIFIER>1</IDENTIFIER></CAN-FRAME-TRIGGERING>`) +
+		`<CAN-FRAME><SHORT-NAME>f</SHORT-NAME><FRAME-LENGTH>1</FRAME-LENGTH><PDU-TO-FRAME-MAPPINGS><PDU-TO-FRAME-MAPPING><SHORT-NAME>m</SHORT-NAME><PDU-REF DEST="I-SIGNAL-I-PDU">/P/p</PDU-REF><START-POSITION>0</START-POSITION></PDU-TO-FRAME-MAPPING></PDU-TO-FRAME-MAPPINGS></CAN-FRAME>
+		<I-SIGNAL-I-PDU><SHORT-NAME>p</SHORT-NAME><LENGTH>1</LENGTH><I-SIGNAL-TO-PDU-MAPPINGS>
+		<I-SIGNAL-TO-I-PDU-MAPPING><SHORT-NAME>m0</SHORT-NAME><I-SIGNAL-REF DEST="I-SIGNAL">/P/s0</I-SIGNAL-REF><START-POSITION>0</START-POSITION></I-SIGNAL-TO-I-PDU-MAPPING>
+		<I-SIGNAL-TO-I-PDU-MAPPING><SHORT-NAME>m1</SHORT-NAME><I-SIGNAL-REF DEST="I-SIGNAL">/P/s1</I-SIGNAL-REF><START-POSITION>2</START-POSITION></I-SIGNAL-TO-I-PDU-MAPPING>
+		</I-SIGNAL-TO-PDU-MAPPINGS></I-SIGNAL-I-PDU>
+		<I-SIGNAL><SHORT-NAME>s0</SHORT-NAME><LENGTH>4</LENGTH></I-SIGNAL><I-SIGNAL><SHORT-NAME>s1</SHORT-NAME><LENGTH>4</LENGTH></I-SIGNAL>`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "/P/p/m1")
+}
@@ -0,0 +1,9 @@
+// Package asc reads and writes the ASCII traces (.asc) of the Vector tools,
+// like CANalyzer and CANoe.
+//
+// The reader supports the absolute and the relative timestamps, the hex and
+// the decimal bases, the CAN 2.0 and the CAN FD frames with their channel
+// and direction, the remote frames, the error frames and the comments.
+// The frames are represented by [acmelib.Frame], where the channel is the
+// number of the ASC channel, so they can be decoded by [acmelib.ChannelDecoder].
+package asc
@@ -0,0 +1,563 @@
+package asc
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+const (
+	// dateLayout is the layout of the dates in the header.
+	dateLayout = "Mon Jan 2 03:04:05.000 pm 2006"
+
+	// fdFlagEDL is the flag of the CAN FD lines that marks a CAN FD frame.
+	fdFlagEDL = 0x1000
+	// fdFlagBRS is the flag of the CAN FD lines that marks the bit rate switch.
+	fdFlagBRS = 0x2000
+	// fdFlagESI is the flag of the CAN FD lines that marks the error state indicator.
+	fdFlagESI = 0x4000
+)
+
+// dateLayouts are the layouts accepted for the dates in the header.
+var dateLayouts = []string{
+	dateLayout,
+	"Mon Jan 2 03:04:05 pm 2006",
+	"Mon Jan 2 15:04:05.000 2006",
+	"Mon Jan 2 15:04:05 2006",
+}
+
+// EventKind is the kind of an [Event].
+type EventKind int
+
+const (
+	// EventKindFrame defines an event that carries a frame, error frames included.
+	EventKindFrame EventKind = iota
+	// EventKindComment defines a comment within the trigger block.
+	EventKindComment
+	// EventKindOther defines any other event, like the start of the measurement.
+	EventKindOther
+)
+
+func (ek EventKind) String() string {
+	switch ek {
+	case EventKindFrame:
+		return "frame"
+	case EventKindComment:
+		return "comment"
+	case EventKindOther:
+		return "other"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is an event of a trace.
+type Event struct {
+	// Kind is the kind of the event.
+	Kind EventKind
+	// Timestamp is the time of the event. It is zero for comments.
+	Timestamp time.Time
+	// Frame is the frame carried by the event, only for [EventKindFrame].
+	Frame *acmelib.Frame
+	// Text is the text of the comments and of the other events.
+	Text string
+}
+
+// Reader reads the events of an ASC trace.
+// The header lines (date, base and timestamps mode) are handled by the reader,
+// so they are not returned as events.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+
+	startTime  time.Time
+	base       int
+	isRelative bool
+	lastOffset time.Duration
+
+	inTriggerBlock bool
+}
+
+// NewReader creates a new [Reader] that reads from the given reader.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		scanner: bufio.NewScanner(r),
+		line:    0,
+
+		startTime:  time.Unix(0, 0),
+		base:       16,
+		isRelative: false,
+		lastOffset: 0,
+
+		inTriggerBlock: false,
+	}
+}
+
+// StartTime returns the start time of the trace, which is
+// the date of the header or of the last trigger block read.
+// If the trace does not have a date, the Unix epoch is returned.
+func (r *Reader) StartTime() time.Time {
+	return r.startTime
+}
+
+// Read returns the next event of the trace.
+// It returns [io.EOF] when there are no more events.
+//
+// It returns a [LineError] that wraps:
+//   - [ErrInvalidLine] if the line does not have the expected fields.
+//   - [ErrInvalidTimestamp] if the timestamp or the date is malformed.
+//   - [ErrInvalidCANID] if the CAN-ID is malformed.
+//   - [ErrInvalidData] if the data is malformed.
+func (r *Reader) Read() (*Event, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := strings.TrimSpace(r.scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		event, err := r.parseLine(line)
+		if err != nil {
+			return nil, &LineError{Line: r.line, Err: err}
+		}
+
+		if event != nil {
+			return event, nil
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// Events returns an iterator over the events of the trace.
+// The lines that cannot be parsed are yielded as a [LineError]
+// and the iteration continues, while it stops on a read error.
+func (r *Reader) Events() iter.Seq2[*Event, error] {
+	return func(yield func(*Event, error) bool) {
+		for {
+			event, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+
+				lineErr := &LineError{}
+				if errors.As(err, &lineErr) {
+					continue
+				}
+				return
+			}
+
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Frames returns an iterator over the frames of the trace, error frames included.
+// The other events are skipped. See [Reader.Events] for the handling of the errors.
+func (r *Reader) Frames() iter.Seq2[*acmelib.Frame, error] {
+	return func(yield func(*acmelib.Frame, error) bool) {
+		for event, err := range r.Events() {
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+
+			if event.Kind != EventKindFrame {
+				continue
+			}
+
+			if !yield(event.Frame, nil) {
+				return
+			}
+		}
+	}
+}
+
+// parseDate parses a date of the header.
+func parseDate(dateStr string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		date, err := time.ParseInLocation(layout, dateStr, time.Local)
+		if err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, ErrInvalidTimestamp
+}
+
+// parseHeaderLine handles the lines of the header and the trigger block delimiters.
+// It returns false if the line is not a header line.
+func (r *Reader) parseHeaderLine(line string) (bool, error) {
+	fields := strings.Fields(line)
+	keyword := strings.ToLower(fields[0])
+
+	switch {
+	case keyword == "date":
+		date, err := parseDate(strings.Join(fields[1:], " "))
+		if err != nil {
+			return true, err
+		}
+		r.startTime = date
+
+	case keyword == "base":
+		// the line is made of key-value pairs: base hex timestamps absolute
+		for idx := 0; idx < len(fields)-1; idx += 2 {
+			value := strings.ToLower(fields[idx+1])
+
+			switch strings.ToLower(fields[idx]) {
+			case "base":
+				if value == "dec" {
+					r.base = 10
+				} else {
+					r.base = 16
+				}
+			case "timestamps":
+				r.isRelative = value == "relative"
+			}
+		}
+
+	case keyword == "begin" && len(fields) > 1 && strings.ToLower(fields[1]) == "triggerblock":
+		if len(fields) > 2 {
+			date, err := parseDate(strings.Join(fields[2:], " "))
+			if err != nil {
+				return true, err
+			}
+			r.startTime = date
+		}
+		r.inTriggerBlock = true
+		r.lastOffset = 0
+
+	case keyword == "end" && len(fields) > 1 && strings.ToLower(fields[1]) == "triggerblock":
+		r.inTriggerBlock = false
+
+	case strings.HasSuffix(line, "internal events logged"):
+
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// parseOffset parses a timestamp in seconds with a decimal fraction.
+func parseOffset(field string) (time.Duration, error) {
+	secStr, fracStr, _ := strings.Cut(field, ".")
+
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil || sec < 0 || len(fracStr) > 9 {
+		return 0, ErrInvalidTimestamp
+	}
+
+	nsec := int64(0)
+	if len(fracStr) > 0 {
+		frac, err := strconv.ParseInt(fracStr, 10, 64)
+		if err != nil {
+			return 0, ErrInvalidTimestamp
+		}
+
+		nsec = frac
+		for range 9 - len(fracStr) {
+			nsec *= 10
+		}
+	}
+
+	return time.Duration(sec)*time.Second + time.Duration(nsec), nil
+}
+
+func (r *Reader) parseLine(line string) (*Event, error) {
+	if strings.HasPrefix(line, "//") {
+		if !r.inTriggerBlock {
+			return nil, nil
+		}
+
+		return &Event{
+			Kind: EventKindComment,
+			Text: strings.TrimSpace(strings.TrimPrefix(line, "//")),
+		}, nil
+	}
+
+	isHeader, err := r.parseHeaderLine(line)
+	if isHeader {
+		return nil, err
+	}
+
+	timestampStr, rest, _ := strings.Cut(line, " ")
+	offset, err := parseOffset(timestampStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.isRelative {
+		offset += r.lastOffset
+	}
+	r.lastOffset = offset
+
+	timestamp := r.startTime.Add(offset)
+	rest = strings.TrimSpace(rest)
+	fields := strings.Fields(rest)
+
+	var frame *acmelib.Frame
+	switch {
+	case len(fields) > 0 && fields[0] == "CANFD":
+		frame, err = r.parseFDFrame(fields[1:])
+
+	case len(fields) > 1 && isChannel(fields[0]):
+		frame, err = r.parseFrame(fields)
+
+	default:
+		return &Event{Kind: EventKindOther, Timestamp: timestamp, Text: rest}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the line is another event of a channel, like the statistics
+	if frame == nil {
+		return &Event{Kind: EventKindOther, Timestamp: timestamp, Text: rest}, nil
+	}
+
+	frame.Timestamp = timestamp
+
+	return &Event{Kind: EventKindFrame, Timestamp: timestamp, Frame: frame}, nil
+}
+
+func isChannel(field string) bool {
+	ch, err := strconv.Atoi(field)
+	return err == nil && ch > 0
+}
+
+// isCANID reports whether the field looks like a CAN-ID,
+// so it tells the frames apart from the other events of a channel, like the statistics.
+func isCANID(field string) bool {
+	idStr, _ := strings.CutSuffix(strings.ToLower(field), "x")
+	if idStr == "" {
+		return false
+	}
+
+	for _, c := range idStr {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isErrorFrame(field string) bool {
+	return strings.EqualFold(field, "ErrorFrame")
+}
+
+func parseDirection(frame *acmelib.Frame, field string) error {
+	switch strings.ToLower(field) {
+	case "rx":
+		frame.Direction = acmelib.FrameDirectionRx
+	case "tx", "txrq":
+		frame.Direction = acmelib.FrameDirectionTx
+	default:
+		return ErrInvalidLine
+	}
+	return nil
+}
+
+// parseCANID parses a CAN-ID in the base of the trace.
+// The extended CAN-IDs end with an "x".
+func (r *Reader) parseCANID(frame *acmelib.Frame, field string) error {
+	idStr, isExtended := strings.CutSuffix(strings.ToLower(field), "x")
+
+	id, err := strconv.ParseUint(idStr, r.base, 32)
+	if err != nil {
+		return ErrInvalidCANID
+	}
+
+	if isExtended {
+		if id > 0x1FFFFFFF {
+			return ErrInvalidCANID
+		}
+	} else if id > 0x7FF {
+		return ErrInvalidCANID
+	}
+
+	frame.CANID = acmelib.CANID(id)
+	frame.IsExtended = isExtended
+
+	return nil
+}
+
+func (r *Reader) parseData(fields []string, length int) ([]byte, error) {
+	if len(fields) < length {
+		return nil, ErrInvalidData
+	}
+
+	data := make([]byte, length)
+	for idx := range length {
+		b, err := strconv.ParseUint(fields[idx], r.base, 8)
+		if err != nil {
+			return nil, ErrInvalidData
+		}
+		data[idx] = byte(b)
+	}
+
+	return data, nil
+}
+
+// parseFrame parses a CAN 2.0 line:
+//   - <channel> <id> <dir> d <dlc> <data...> for data frames.
+//   - <channel> <id> <dir> r [<dlc>] for remote frames.
+//   - <channel> ErrorFrame for error frames.
+//
+// It returns a nil frame if the line is another event of the channel.
+func (r *Reader) parseFrame(fields []string) (*acmelib.Frame, error) {
+	frame := &acmelib.Frame{Channel: fields[0]}
+
+	if isErrorFrame(fields[1]) {
+		frame.IsError = true
+		return frame, nil
+	}
+
+	if len(fields) < 4 || !isCANID(fields[1]) {
+		return nil, nil
+	}
+
+	if err := r.parseCANID(frame, fields[1]); err != nil {
+		return nil, err
+	}
+
+	if err := parseDirection(frame, fields[2]); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(fields[3]) {
+	case "r":
+		frame.IsRemote = true
+
+		if len(fields) > 4 {
+			dlc, err := strconv.ParseUint(fields[4], 16, 8)
+			if err != nil || dlc > 8 {
+				return nil, ErrInvalidData
+			}
+			frame.RemoteLength = int(dlc)
+		}
+
+		return frame, nil
+
+	case "d":
+		if len(fields) < 5 {
+			return nil, ErrInvalidLine
+		}
+
+		dlc, err := strconv.ParseUint(fields[4], 16, 8)
+		if err != nil || dlc > 15 {
+			return nil, ErrInvalidData
+		}
+
+		data, err := r.parseData(fields[5:], min(int(dlc), 8))
+		if err != nil {
+			return nil, err
+		}
+		frame.Data = data
+
+		return frame, nil
+	}
+
+	return nil, ErrInvalidLine
+}
+
+// parseFDFrame parses a CAN FD line (after the CANFD keyword):
+// <channel> <dir> <id> [<name>] <brs> <esi> <dlc> <length> <data...> [<duration> <bits> <flags> <other fields>...],
+// or <channel> <dir> ErrorFrame for error frames.
+// When the flags are present, they tell whether the frame is a CAN FD one,
+// since the CAN FD lines can also carry classic frames.
+func (r *Reader) parseFDFrame(fields []string) (*acmelib.Frame, error) {
+	if len(fields) < 3 || !isChannel(fields[0]) {
+		return nil, ErrInvalidLine
+	}
+
+	frame := &acmelib.Frame{Channel: fields[0], IsFD: true}
+
+	if err := parseDirection(frame, fields[1]); err != nil {
+		return nil, err
+	}
+
+	if isErrorFrame(fields[2]) {
+		frame.IsError = true
+		return frame, nil
+	}
+
+	if err := r.parseCANID(frame, fields[2]); err != nil {
+		return nil, err
+	}
+
+	fields = fields[3:]
+
+	// skip the symbolic name of the message
+	if len(fields) > 0 && fields[0] != "0" && fields[0] != "1" {
+		fields = fields[1:]
+	}
+
+	if len(fields) < 4 {
+		return nil, ErrInvalidLine
+	}
+
+	frame.BitRateSwitch = fields[0] == "1"
+	frame.ErrorStateIndicator = fields[1] == "1"
+
+	dlc, err := strconv.ParseUint(fields[2], 16, 8)
+	if err != nil || dlc > 15 {
+		return nil, ErrInvalidData
+	}
+
+	length, err := strconv.Atoi(fields[3])
+	if err != nil || length < 0 || length > 64 {
+		return nil, ErrInvalidData
+	}
+
+	if dlcLength, err := acmelib.DLCToSizeByte(int(dlc)); err != nil || dlcLength != length {
+		return nil, ErrInvalidData
+	}
+
+	data, err := r.parseData(fields[4:], length)
+	if err != nil {
+		return nil, err
+	}
+	frame.Data = data
+
+	fields = fields[4+length:]
+	if len(fields) < 3 {
+		return frame, nil
+	}
+
+	flags, err := strconv.ParseUint(fields[2], 16, 32)
+	if err != nil {
+		return nil, ErrInvalidLine
+	}
+
+	frame.IsFD = flags&fdFlagEDL != 0
+	frame.BitRateSwitch = flags&fdFlagBRS != 0
+	frame.ErrorStateIndicator = flags&fdFlagESI != 0
+
+	if !frame.IsFD && length > 8 {
+		return nil, ErrInvalidData
+	}
+
+	return frame, nil
+}
@@ -0,0 +1,221 @@
+package asc
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func readEvents(assert *assert.Assertions, path string) ([]*Event, time.Time) {
+	file, err := os.Open(path)
+	assert.NoError(err)
+	defer file.Close()
+
+	r := NewReader(file)
+
+	events := []*Event{}
+	for event, err := range r.Events() {
+		assert.NoError(err)
+		events = append(events, event)
+	}
+
+	return events, r.StartTime()
+}
+
+func Test_Reader_Read(t *testing.T) {
+	assert := assert.New(t)
+
+	events, startTime := readEvents(assert, "testdata/absolute.asc")
+	assert.Len(events, 8)
+
+	// should parse the date of the header
+	expectedStart := time.Date(2024, time.July, 10, 14, 15, 30, 250_000_000, time.Local)
+	assert.Equal(expectedStart, startTime)
+
+	// should return the start of the measurement and the comments within the trigger block
+	assert.Equal(EventKindOther, events[0].Kind)
+	assert.Equal("Start of measurement", events[0].Text)
+	assert.Equal(EventKindComment, events[1].Kind)
+	assert.Equal("engine warm up", events[1].Text)
+
+	// should parse a standard frame
+	frame := events[2].Frame
+	assert.Equal(EventKindFrame, events[2].Kind)
+	assert.Equal(expectedStart.Add(10*time.Millisecond), frame.Timestamp)
+	assert.Equal("1", frame.Channel)
+	assert.Equal(acmelib.FrameDirectionRx, frame.Direction)
+	assert.Equal(acmelib.CANID(0x10), frame.CANID)
+	assert.False(frame.IsExtended)
+	assert.Equal([]byte{1, 2, 3, 4, 5, 6, 7, 8}, frame.Data)
+
+	// should parse an extended frame
+	frame = events[3].Frame
+	assert.Equal(expectedStart.Add(20500*time.Microsecond), frame.Timestamp)
+	assert.Equal("2", frame.Channel)
+	assert.Equal(acmelib.FrameDirectionTx, frame.Direction)
+	assert.Equal(acmelib.CANID(0x18FEF100), frame.CANID)
+	assert.True(frame.IsExtended)
+	assert.Equal([]byte{0xAA, 0xBB, 0xCC}, frame.Data)
+
+	// should parse a remote frame
+	frame = events[4].Frame
+	assert.True(frame.IsRemote)
+	assert.Equal(4, frame.RemoteLength)
+	assert.Empty(frame.Data)
+
+	// should parse an error frame
+	assert.True(events[5].Frame.IsError)
+	assert.Equal("1", events[5].Frame.Channel)
+
+	// should parse a CAN FD frame
+	frame = events[6].Frame
+	assert.True(frame.IsFD)
+	assert.True(frame.BitRateSwitch)
+	assert.False(frame.ErrorStateIndicator)
+	assert.Equal(acmelib.CANID(0x200), frame.CANID)
+	assert.Len(frame.Data, 12)
+	assert.Equal(byte(0x0B), frame.Data[11])
+
+	// should parse a CAN FD error frame
+	frame = events[7].Frame
+	assert.True(frame.IsFD)
+	assert.True(frame.IsError)
+	assert.Equal("2", frame.Channel)
+
+	// should return io.EOF at the end
+	r := NewReader(strings.NewReader("\n   1.5 1  7FF  Rx d 0\n\n"))
+	event, err := r.Read()
+	assert.NoError(err)
+	assert.Equal(time.Unix(1, 500_000_000), event.Timestamp)
+	assert.Empty(event.Frame.Data)
+	_, err = r.Read()
+	assert.ErrorIs(err, io.EOF)
+
+	// should parse the flags of the CAN FD lines
+	r = NewReader(strings.NewReader(strings.Join([]string{
+		"1.0 CANFD 1 Rx 10 0 0 2 2 01 02 0 0 0 0 0 0 0 0",
+		"2.0 CANFD 1 Rx 10 0 1 2 2 01 02 0 0 5000 0 0 0 0 0",
+		"3.0 1 Statistic: D 0 R 0 XD 0 XR 0 E 0 O 0 B 0.00%",
+	}, "\n")))
+	event, err = r.Read()
+	assert.NoError(err)
+	assert.False(event.Frame.IsFD)
+	assert.Equal([]byte{1, 2}, event.Frame.Data)
+	event, err = r.Read()
+	assert.NoError(err)
+	assert.True(event.Frame.IsFD)
+	assert.False(event.Frame.BitRateSwitch)
+	assert.True(event.Frame.ErrorStateIndicator)
+
+	// should return the statistics as another event of the channel
+	event, err = r.Read()
+	assert.NoError(err)
+	assert.Equal(EventKindOther, event.Kind)
+	assert.Nil(event.Frame)
+}
+
+func Test_Reader_Frames(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/relative.asc")
+	assert.NoError(err)
+	defer file.Close()
+
+	r := NewReader(file)
+
+	frames := []*acmelib.Frame{}
+	errs := []error{}
+	for frame, err := range r.Frames() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		frames = append(frames, frame)
+	}
+
+	assert.Len(frames, 3)
+
+	// should accumulate the relative timestamps
+	startTime := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.Local)
+	assert.Equal(startTime.Add(100*time.Millisecond), frames[0].Timestamp)
+	assert.Equal(startTime.Add(150*time.Millisecond), frames[1].Timestamp)
+	assert.Equal(startTime.Add(175*time.Millisecond), frames[2].Timestamp)
+
+	// should parse the decimal base
+	assert.Equal(acmelib.CANID(16), frames[0].CANID)
+	assert.Equal([]byte{1, 255}, frames[0].Data)
+	assert.Equal(acmelib.CANID(800), frames[2].CANID)
+
+	// should yield the invalid lines and continue
+	assert.Len(errs, 1)
+	lineErr := &LineError{}
+	assert.ErrorAs(errs[0], &lineErr)
+	assert.Equal(9, lineErr.Line)
+	assert.ErrorIs(errs[0], ErrInvalidData)
+
+	// should return the parsing errors
+	testdata := []struct {
+		line string
+		err  error
+	}{
+		{"date Tue 5 2024", ErrInvalidTimestamp},
+		{"1.x 1  10  Rx d 1 00", ErrInvalidTimestamp},
+		{"1.0 1  10  Up d 1 00", ErrInvalidLine},
+		{"1.0 1  10  Rx d 2 00", ErrInvalidData},
+		{"1.0 1  800  Rx d 1 00", ErrInvalidCANID},
+		{"1.0 CANFD 1 Rx 10 0 0 9 12 00 00 00 00 00 00 00 00 00 00 00 00 0 0 0 0 0 0 0 0", ErrInvalidData},
+		{"1.0 CANFD 1 Rx 10x0 0 0 1 1 00", ErrInvalidCANID},
+		{"1.0 CANFD 1 Rx 10 0 0 9 10 00", ErrInvalidData},
+	}
+
+	for _, td := range testdata {
+		_, err := NewReader(strings.NewReader(td.line)).Read()
+		assert.ErrorIs(err, td.err, td.line)
+	}
+}
+
+func Test_Reader_decode(t *testing.T) {
+	assert := assert.New(t)
+
+	net := acmelib.NewNetwork("net")
+	bus := acmelib.NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	node := acmelib.NewNode("node", 1, 1)
+	nodeInt := node.GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := acmelib.NewMessage("msg", 1, 8)
+	assert.NoError(msg.SetStaticCANID(0x10))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	typ, err := acmelib.NewIntegerSignalType("uint8", 8, false)
+	assert.NoError(err)
+	sig, err := acmelib.NewStandardSignal("sig", typ)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(sig, 8))
+
+	chDec, err := net.NewChannelDecoder(map[string]string{"1": "bus"})
+	assert.NoError(err)
+
+	file, err := os.Open("testdata/absolute.asc")
+	assert.NoError(err)
+	defer file.Close()
+
+	// should decode only the frames of the mapped channel
+	decodings := []*acmelib.FrameDecoding{}
+	for frameDec, err := range chDec.DecodeFrames(NewReader(file).Frames()) {
+		assert.NoError(err)
+		decodings = append(decodings, frameDec)
+	}
+
+	assert.Len(decodings, 1)
+	assert.Equal(msg.EntityID(), decodings[0].Message.EntityID())
+	assert.Len(decodings[0].Signals, 1)
+	assert.Equal(uint64(2), decodings[0].Signals[0].RawValue)
+}
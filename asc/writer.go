@@ -0,0 +1,262 @@
+package asc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// WriterOptions are the options of a [Writer].
+type WriterOptions struct {
+	// StartTime is the start time of the trace written in the header.
+	// If it is zero, the timestamp of the first frame is used.
+	StartTime time.Time
+	// Relative reports whether the timestamps are written relative
+	// to the previous event instead of the start of the trace.
+	Relative bool
+}
+
+// Writer writes frames as events of an ASC trace, always with the hex base.
+// The header is written before the first event and the trace is terminated
+// by [Writer.Close], which must be called at the end.
+//
+// The numeric channels of the frames are kept, while the other channels
+// (like the names of the interfaces) are mapped to the first free channel number,
+// in order of appearance.
+type Writer struct {
+	w    *bufio.Writer
+	opts WriterOptions
+
+	headerWritten bool
+	lastOffset    time.Duration
+
+	channels    map[string]int
+	usedChannel map[int]bool
+}
+
+// NewWriter creates a new [Writer] that writes to the given writer
+// with the given options. The options can be nil.
+func NewWriter(w io.Writer, opts *WriterOptions) *Writer {
+	if opts == nil {
+		opts = &WriterOptions{}
+	}
+
+	return &Writer{
+		w:    bufio.NewWriter(w),
+		opts: *opts,
+
+		headerWritten: false,
+		lastOffset:    0,
+
+		channels:    make(map[string]int),
+		usedChannel: make(map[int]bool),
+	}
+}
+
+func formatOffset(offset time.Duration) string {
+	if offset < 0 {
+		offset = 0
+	}
+
+	sec := offset / time.Second
+	usec := (offset % time.Second) / time.Microsecond
+
+	return fmt.Sprintf("%11s", fmt.Sprintf("%d.%06d", sec, usec))
+}
+
+func (w *Writer) writeHeader(startTime time.Time) error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+
+	if w.opts.StartTime.IsZero() {
+		w.opts.StartTime = startTime
+	}
+	date := w.opts.StartTime.Format(dateLayout)
+
+	timestamps := "absolute"
+	if w.opts.Relative {
+		timestamps = "relative"
+	}
+
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "date %s\n", date)
+	fmt.Fprintf(b, "base hex  timestamps %s\n", timestamps)
+	b.WriteString("internal events logged\n")
+	fmt.Fprintf(b, "Begin Triggerblock %s\n", date)
+	fmt.Fprintf(b, "%s Start of measurement\n", formatOffset(0))
+
+	_, err := w.w.WriteString(b.String())
+	return err
+}
+
+// getOffset returns the offset of the timestamp to write.
+func (w *Writer) getOffset(timestamp time.Time) string {
+	offset := time.Duration(0)
+	if !timestamp.IsZero() {
+		offset = timestamp.Sub(w.opts.StartTime)
+	}
+
+	if offset < w.lastOffset {
+		offset = w.lastOffset
+	}
+
+	res := offset
+	if w.opts.Relative {
+		res = offset - w.lastOffset
+	}
+	w.lastOffset = offset
+
+	return formatOffset(res)
+}
+
+// getChannel returns the number of the given channel.
+func (w *Writer) getChannel(channel string) int {
+	if ch, ok := w.channels[channel]; ok {
+		return ch
+	}
+
+	ch, err := strconv.Atoi(channel)
+	if err != nil || ch <= 0 {
+		ch = 1
+		for w.usedChannel[ch] {
+			ch++
+		}
+	}
+
+	w.channels[channel] = ch
+	w.usedChannel[ch] = true
+
+	return ch
+}
+
+// WriteFrame writes the given frame as an event of the trace.
+func (w *Writer) WriteFrame(frame *acmelib.Frame) error {
+	if err := w.writeHeader(frame.Timestamp); err != nil {
+		return err
+	}
+
+	offset := w.getOffset(frame.Timestamp)
+	channel := w.getChannel(frame.Channel)
+
+	var line string
+	if frame.IsFD {
+		line = formatFDFrame(frame, offset, channel)
+	} else {
+		line = formatFrame(frame, offset, channel)
+	}
+
+	_, err := w.w.WriteString(line + "\n")
+	return err
+}
+
+// WriteComment writes the given text as a comment of the trace.
+func (w *Writer) WriteComment(text string) error {
+	if err := w.writeHeader(time.Now()); err != nil {
+		return err
+	}
+
+	_, err := w.w.WriteString("// " + text + "\n")
+	return err
+}
+
+// Close terminates the trace and flushes any buffered data
+// to the underlying writer. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.writeHeader(time.Now()); err != nil {
+		return err
+	}
+
+	if _, err := w.w.WriteString("End TriggerBlock\n"); err != nil {
+		return err
+	}
+
+	return w.w.Flush()
+}
+
+func formatCANID(frame *acmelib.Frame) string {
+	if frame.IsExtended {
+		return fmt.Sprintf("%Xx", uint32(frame.CANID))
+	}
+	return fmt.Sprintf("%X", uint32(frame.CANID))
+}
+
+func formatDirection(frame *acmelib.Frame) string {
+	if frame.Direction == acmelib.FrameDirectionTx {
+		return "Tx"
+	}
+	return "Rx"
+}
+
+func formatData(data []byte) string {
+	b := new(strings.Builder)
+	for idx, d := range data {
+		if idx > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(b, "%02X", d)
+	}
+	return b.String()
+}
+
+func formatFrame(frame *acmelib.Frame, offset string, channel int) string {
+	if frame.IsError {
+		return fmt.Sprintf("%s %-2d ErrorFrame", offset, channel)
+	}
+
+	prefix := fmt.Sprintf("%s %-2d %-15s %s", offset, channel, formatCANID(frame), formatDirection(frame))
+
+	if frame.IsRemote {
+		if frame.RemoteLength > 0 {
+			return fmt.Sprintf("%s   r %X", prefix, frame.RemoteLength)
+		}
+		return prefix + "   r"
+	}
+
+	line := fmt.Sprintf("%s   d %d", prefix, len(frame.Data))
+	if len(frame.Data) > 0 {
+		line += " " + formatData(frame.Data)
+	}
+
+	return line
+}
+
+func formatFDFrame(frame *acmelib.Frame, offset string, channel int) string {
+	if frame.IsError {
+		return fmt.Sprintf("%s CANFD %3d %s ErrorFrame", offset, channel, formatDirection(frame))
+	}
+
+	brs := 0
+	esi := 0
+	flags := fdFlagEDL
+	if frame.BitRateSwitch {
+		brs = 1
+		flags |= fdFlagBRS
+	}
+	if frame.ErrorStateIndicator {
+		esi = 1
+		flags |= fdFlagESI
+	}
+
+	// the length of a valid frame is always a valid size
+	dlc, _ := acmelib.SizeByteToDLC(len(frame.Data))
+
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "%s CANFD %3d %s %8s %d %d %x %2d", offset, channel, formatDirection(frame), formatCANID(frame), brs, esi, dlc, len(frame.Data))
+
+	if len(frame.Data) > 0 {
+		b.WriteString(" ")
+		b.WriteString(formatData(frame.Data))
+	}
+
+	// message duration, message length, flags, crc and bit timings are not known
+	fmt.Fprintf(b, " %8d %4d %8x %8d %8d %8d %8d %8d", 0, 0, flags, 0, 0, 0, 0, 0)
+
+	return b.String()
+}
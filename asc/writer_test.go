@@ -0,0 +1,85 @@
+package asc
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeEvents(assert *assert.Assertions, w *Writer, events []*Event) {
+	for _, event := range events {
+		switch event.Kind {
+		case EventKindFrame:
+			assert.NoError(w.WriteFrame(event.Frame))
+		case EventKindComment:
+			assert.NoError(w.WriteComment(event.Text))
+		}
+	}
+	assert.NoError(w.Close())
+}
+
+func Test_Writer_WriteFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	expected, err := os.ReadFile("testdata/absolute.asc")
+	assert.NoError(err)
+
+	// the comments before the trigger block are not events
+	expectedStr := strings.Replace(string(expected), "// version 13.0.0\n", "", 1)
+
+	// should write the same trace that has been read
+	events, startTime := readEvents(assert, "testdata/absolute.asc")
+
+	buf := new(bytes.Buffer)
+	writeEvents(assert, NewWriter(buf, &WriterOptions{StartTime: startTime}), events)
+
+	assert.Equal(expectedStr, buf.String())
+
+	// should read the same frames from a relative trace
+	buf.Reset()
+	writeEvents(assert, NewWriter(buf, &WriterOptions{StartTime: startTime, Relative: true}), events)
+
+	relEvents := []*Event{}
+	for event, err := range NewReader(buf).Events() {
+		assert.NoError(err)
+		relEvents = append(relEvents, event)
+	}
+
+	assert.Equal(events, relEvents)
+}
+
+func Test_Writer_channels(t *testing.T) {
+	assert := assert.New(t)
+
+	startTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local)
+	frames := []*acmelib.Frame{
+		{Timestamp: startTime.Add(time.Second), Channel: "2", CANID: 0x1},
+		{Timestamp: startTime.Add(2 * time.Second), Channel: "vcan0", CANID: 0x2},
+		{Timestamp: startTime.Add(3 * time.Second), Channel: "vcan1", CANID: 0x3},
+		{Timestamp: startTime.Add(4 * time.Second), Channel: "vcan0", CANID: 0x4},
+	}
+
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf, nil)
+	for _, frame := range frames {
+		assert.NoError(w.WriteFrame(frame))
+	}
+	assert.NoError(w.Close())
+
+	// should use the timestamp of the first frame as the start time
+	r := NewReader(buf)
+	channels := []string{}
+	for frame, err := range r.Frames() {
+		assert.NoError(err)
+		channels = append(channels, frame.Channel)
+	}
+	assert.Equal(startTime.Add(time.Second), r.StartTime())
+
+	// should keep the numeric channels and map the others
+	assert.Equal([]string{"2", "1", "3", "1"}, channels)
+}
@@ -0,0 +1,14 @@
+// Package blf reads and writes the binary logging files (.blf) of the Vector tools,
+// like CANalyzer and CANoe.
+//
+// A BLF file is made of a file header followed by a sequence of objects, which are
+// usually grouped in log containers compressed with zlib. The reader streams the
+// objects without loading the whole file, and it supports the CAN_MESSAGE,
+// CAN_MESSAGE2, CAN_FD_MESSAGE and CAN_FD_MESSAGE64 objects, together with
+// the CAN_ERROR and CAN_ERROR_EXT ones for the error frames.
+// The other objects are skipped.
+//
+// The frames are represented by [acmelib.Frame], where the channel is the number
+// of the BLF channel, so they can be decoded by [acmelib.Decoder.DecodeFrames]
+// or by [acmelib.ChannelDecoder].
+package blf
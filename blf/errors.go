@@ -0,0 +1,40 @@
+package blf
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of the file
+	// or of an object is not the expected one.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidHeader is returned when the header of the file is malformed,
+	// or when the header of an object declares a size that is too big.
+	ErrInvalidHeader = errors.New("invalid header")
+	// ErrInvalidObject is returned when the size of an object
+	// is too small for its header or for its type.
+	ErrInvalidObject = errors.New("invalid object")
+	// ErrUnsupportedCompression is returned when a log container
+	// uses a compression method different from zlib.
+	ErrUnsupportedCompression = errors.New("unsupported compression")
+	// ErrInvalidData is returned when the data of a frame is malformed.
+	ErrInvalidData = errors.New("invalid data")
+)
+
+// ObjectError is an error that occurred while parsing an object of a file.
+type ObjectError struct {
+	// Index is the index of the object, starting from 1.
+	// The log containers are not counted.
+	Index int
+	// Type is the type of the object.
+	Type uint32
+	// Err is the parsing error.
+	Err error
+}
+
+func (e *ObjectError) Error() string {
+	return fmt.Sprintf("object error; index:%d, type:%d : %v", e.Index, e.Type, e.Err)
+}
+
+func (e *ObjectError) Unwrap() error { return e.Err }
@@ -0,0 +1,189 @@
+package blf
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const (
+	// fileSignature is the signature of the file header.
+	fileSignature = "LOGG"
+	// objectSignature is the signature of each object.
+	objectSignature = "LOBJ"
+
+	// fileHeaderSize is the size of the file header.
+	fileHeaderSize = 144
+	// objectHeaderBaseSize is the size of the header shared by all the objects.
+	objectHeaderBaseSize = 16
+	// objectHeaderSize is the size of the header (version 1 and 2)
+	// of the objects that carry a timestamp.
+	objectHeaderSize = 32
+	// containerHeaderSize is the size of the header of a log container,
+	// which follows the base header.
+	containerHeaderSize = 16
+
+	// maxContainerSize is the uncompressed size of the log containers written.
+	maxContainerSize = 128 * 1024
+	// maxObjectSize is the maximum size of an object accepted by the reader,
+	// uncompressed log containers included. It is far bigger than the containers
+	// written by the common tools, and it limits the memory used by malformed files.
+	maxObjectSize = 16 * 1024 * 1024
+)
+
+// Types of the objects.
+const (
+	objectTypeCANMessage     uint32 = 1
+	objectTypeCANError       uint32 = 2
+	objectTypeLogContainer   uint32 = 10
+	objectTypeCANErrorExt    uint32 = 73
+	objectTypeCANMessage2    uint32 = 86
+	objectTypeCANFDMessage   uint32 = 100
+	objectTypeCANFDMessage64 uint32 = 101
+)
+
+// Compression methods of the log containers.
+const (
+	compressionNone uint16 = 0
+	compressionZlib uint16 = 2
+)
+
+// Flags of the object headers that define the unit of the timestamp.
+const (
+	timeFlagTenMicros uint32 = 1
+	timeFlagOneNanos  uint32 = 2
+)
+
+// Flags of the CAN objects.
+const (
+	// msgFlagTx marks a transmitted frame (CAN_MESSAGE and CAN_FD_MESSAGE).
+	msgFlagTx = 0x01
+	// msgFlagRemote marks a remote frame (CAN_MESSAGE and CAN_FD_MESSAGE).
+	msgFlagRemote = 0x80
+
+	// fdFlagEDL marks a CAN FD frame (CAN_FD_MESSAGE).
+	fdFlagEDL = 0x01
+	// fdFlagBRS marks the bit rate switch (CAN_FD_MESSAGE).
+	fdFlagBRS = 0x02
+	// fdFlagESI marks the error state indicator (CAN_FD_MESSAGE).
+	fdFlagESI = 0x04
+
+	// fd64FlagRemote marks a remote frame (CAN_FD_MESSAGE64).
+	fd64FlagRemote = 0x0010
+	// fd64FlagEDL marks a CAN FD frame (CAN_FD_MESSAGE64).
+	fd64FlagEDL = 0x1000
+	// fd64FlagBRS marks the bit rate switch (CAN_FD_MESSAGE64).
+	fd64FlagBRS = 0x2000
+	// fd64FlagESI marks the error state indicator (CAN_FD_MESSAGE64).
+	fd64FlagESI = 0x4000
+
+	// extendedIDFlag is the flag of the CAN-ID of an extended frame.
+	extendedIDFlag = 0x80000000
+)
+
+// Sizes of the bodies of the CAN objects, after the object header.
+const (
+	canMessageSize     = 16
+	canMessage2Size    = 24
+	canFDMessageSize   = 84
+	canFDMessage64Size = 40
+	canErrorSize       = 4
+	canErrorExtSize    = 32
+)
+
+var le = binary.LittleEndian
+
+// fileHeader is the header of a BLF file.
+type fileHeader struct {
+	applicationID    uint8
+	fileSize         uint64
+	uncompressedSize uint64
+	objectCount      uint32
+	startTime        time.Time
+	stopTime         time.Time
+}
+
+// decodeSystemTime decodes a SYSTEMTIME structure of the Windows API.
+// It returns the Unix epoch if the structure is empty.
+func decodeSystemTime(buf []byte) time.Time {
+	year := int(le.Uint16(buf[0:]))
+	if year == 0 {
+		return time.Unix(0, 0)
+	}
+
+	month := time.Month(le.Uint16(buf[2:]))
+	// the day of the week is skipped
+	day := int(le.Uint16(buf[6:]))
+	hour := int(le.Uint16(buf[8:]))
+	minute := int(le.Uint16(buf[10:]))
+	second := int(le.Uint16(buf[12:]))
+	millis := int(le.Uint16(buf[14:]))
+
+	return time.Date(year, month, day, hour, minute, second, millis*int(time.Millisecond), time.Local)
+}
+
+// encodeSystemTime encodes a SYSTEMTIME structure of the Windows API.
+func encodeSystemTime(buf []byte, t time.Time) {
+	t = t.In(time.Local)
+
+	le.PutUint16(buf[0:], uint16(t.Year()))
+	le.PutUint16(buf[2:], uint16(t.Month()))
+	le.PutUint16(buf[4:], uint16(t.Weekday()))
+	le.PutUint16(buf[6:], uint16(t.Day()))
+	le.PutUint16(buf[8:], uint16(t.Hour()))
+	le.PutUint16(buf[10:], uint16(t.Minute()))
+	le.PutUint16(buf[12:], uint16(t.Second()))
+	le.PutUint16(buf[14:], uint16(t.Nanosecond()/int(time.Millisecond)))
+}
+
+// decodeFileHeader decodes the fixed part of the file header
+// and it returns the full size of the header.
+func decodeFileHeader(buf []byte) (*fileHeader, int, error) {
+	if string(buf[0:4]) != fileSignature {
+		return nil, 0, ErrInvalidSignature
+	}
+
+	headerSize := int(le.Uint32(buf[4:]))
+	if headerSize < 72 {
+		return nil, 0, ErrInvalidHeader
+	}
+
+	return &fileHeader{
+		applicationID:    buf[8],
+		fileSize:         le.Uint64(buf[16:]),
+		uncompressedSize: le.Uint64(buf[24:]),
+		objectCount:      le.Uint32(buf[32:]),
+		startTime:        decodeSystemTime(buf[40:56]),
+		stopTime:         decodeSystemTime(buf[56:72]),
+	}, headerSize, nil
+}
+
+// encode returns the bytes of the file header.
+func (fh *fileHeader) encode() []byte {
+	buf := make([]byte, fileHeaderSize)
+
+	copy(buf[0:], fileSignature)
+	le.PutUint32(buf[4:], fileHeaderSize)
+	buf[8] = fh.applicationID
+
+	// version of the binary log format
+	buf[12] = 4
+	buf[13] = 6
+	buf[14] = 1
+
+	le.PutUint64(buf[16:], fh.fileSize)
+	le.PutUint64(buf[24:], fh.uncompressedSize)
+	le.PutUint32(buf[32:], fh.objectCount)
+	encodeSystemTime(buf[40:56], fh.startTime)
+	encodeSystemTime(buf[56:72], fh.stopTime)
+
+	return buf
+}
+
+// getObjectPadding returns the number of padding bytes that follows an object.
+// The CAN_FD_MESSAGE64 objects are not padded.
+func getObjectPadding(objType uint32, objSize int) int {
+	if objType == objectTypeCANFDMessage64 {
+		return 0
+	}
+	return objSize % 4
+}
@@ -0,0 +1,498 @@
+package blf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// Reader reads the frames of a BLF file.
+// The objects are read one at a time, and the log containers are decompressed
+// only when their objects are needed, so the memory used by the reader
+// does not depend on the size of the file.
+type Reader struct {
+	r      *bufio.Reader
+	header *fileHeader
+
+	// buf holds the bytes of the objects that have not been parsed yet,
+	// which may come from more log containers
+	buf   []byte
+	isEOF bool
+
+	index int
+}
+
+// NewReader creates a new [Reader] that reads from the given reader.
+// It reads the file header immediately.
+//
+// It returns:
+//   - [ErrInvalidSignature] if the file does not start with the BLF signature.
+//   - [ErrInvalidHeader] if the file header is malformed.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	headerBuf := make([]byte, 72)
+	if _, err := io.ReadFull(br, headerBuf); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrInvalidHeader
+		}
+		return nil, err
+	}
+
+	header, headerSize, err := decodeFileHeader(headerBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	// skip the rest of the header
+	if _, err := br.Discard(headerSize - len(headerBuf)); err != nil {
+		return nil, ErrInvalidHeader
+	}
+
+	return &Reader{
+		r:      br,
+		header: header,
+
+		buf:   []byte{},
+		isEOF: false,
+
+		index: 0,
+	}, nil
+}
+
+// StartTime returns the start time of the measurement written in the file header.
+// The timestamps of the frames are relative to it.
+func (r *Reader) StartTime() time.Time {
+	return r.header.startTime
+}
+
+// StopTime returns the stop time of the measurement written in the file header.
+func (r *Reader) StopTime() time.Time {
+	return r.header.stopTime
+}
+
+// ObjectCount returns the number of objects declared in the file header.
+func (r *Reader) ObjectCount() int {
+	return int(r.header.objectCount)
+}
+
+// readOuterObject reads the next object of the file.
+// The content of a log container is appended to the buffer,
+// while the other objects are appended as they are.
+func (r *Reader) readOuterObject() error {
+	base := make([]byte, objectHeaderBaseSize)
+	if _, err := io.ReadFull(r.r, base); err != nil {
+		if errors.Is(err, io.EOF) {
+			r.isEOF = true
+			return nil
+		}
+		return err
+	}
+
+	if string(base[0:4]) != objectSignature {
+		return ErrInvalidSignature
+	}
+
+	objSize := int(le.Uint32(base[8:]))
+	objType := le.Uint32(base[12:])
+
+	if objSize < objectHeaderBaseSize {
+		return ErrInvalidObject
+	}
+
+	if objSize > maxObjectSize {
+		return ErrInvalidHeader
+	}
+
+	content := make([]byte, objSize-objectHeaderBaseSize)
+	if _, err := io.ReadFull(r.r, content); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+
+	// the padding may be missing at the end of the file
+	padding := getObjectPadding(objType, objSize)
+	if _, err := r.r.Discard(padding); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if objType != objectTypeLogContainer {
+		r.buf = append(r.buf, base...)
+		r.buf = append(r.buf, content...)
+		r.buf = append(r.buf, make([]byte, padding)...)
+		return nil
+	}
+
+	if len(content) < containerHeaderSize {
+		return ErrInvalidObject
+	}
+
+	method := le.Uint16(content[0:])
+	uncompressedSize := int(le.Uint32(content[8:]))
+	data := content[containerHeaderSize:]
+
+	if uncompressedSize > maxObjectSize {
+		return ErrInvalidHeader
+	}
+
+	switch method {
+	case compressionNone:
+		r.buf = append(r.buf, data...)
+
+	case compressionZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+
+		// the content cannot exceed the declared uncompressed size
+		uncompressed := bytes.NewBuffer(make([]byte, 0, uncompressedSize))
+		n, err := io.Copy(uncompressed, io.LimitReader(zr, int64(uncompressedSize)+1))
+		if err != nil {
+			return err
+		}
+
+		if n > int64(uncompressedSize) {
+			return ErrInvalidHeader
+		}
+
+		r.buf = append(r.buf, uncompressed.Bytes()...)
+
+	default:
+		return ErrUnsupportedCompression
+	}
+
+	return nil
+}
+
+// fill reads the file until the buffer holds at least size bytes.
+// It returns false if the end of the file is reached before.
+func (r *Reader) fill(size int) (bool, error) {
+	for len(r.buf) < size {
+		if r.isEOF {
+			return false, nil
+		}
+
+		if err := r.readOuterObject(); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// discard removes the given number of bytes from the buffer.
+func (r *Reader) discard(size int) {
+	size = min(size, len(r.buf))
+	r.buf = r.buf[size:]
+
+	// release the memory of the parsed containers
+	if len(r.buf) == 0 {
+		r.buf = nil
+	}
+}
+
+// seekObject moves the buffer to the start of the next object.
+// It returns false if there are no more objects.
+func (r *Reader) seekObject() (bool, error) {
+	for {
+		ok, err := r.fill(objectHeaderBaseSize)
+		if err != nil {
+			return false, err
+		}
+
+		if !ok {
+			// the end of the file may be padded with zeros
+			if len(bytes.Trim(r.buf, "\x00")) == 0 {
+				return false, nil
+			}
+			return false, io.ErrUnexpectedEOF
+		}
+
+		if string(r.buf[0:4]) == objectSignature {
+			return true, nil
+		}
+
+		// some writers add more padding bytes than expected,
+		// so the next object is searched like other tools do
+		idx := bytes.Index(r.buf, []byte(objectSignature))
+		if idx < 0 {
+			// keep the bytes that may be the start of a signature
+			r.discard(len(r.buf) - len(objectSignature) + 1)
+			continue
+		}
+
+		r.discard(idx)
+	}
+}
+
+// Read returns the next frame of the file. The objects
+// that do not represent a frame are skipped.
+// It returns [io.EOF] when there are no more frames.
+//
+// It returns an [ObjectError] that wraps:
+//   - [ErrInvalidObject] if an object is too small for its type.
+//   - [ErrInvalidData] if the data of a frame is malformed.
+//
+// It returns [ErrInvalidSignature], [ErrInvalidObject] or [ErrUnsupportedCompression]
+// if the structure of the file is malformed, since the following objects cannot be found.
+func (r *Reader) Read() (*acmelib.Frame, error) {
+	for {
+		ok, err := r.seekObject()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, io.EOF
+		}
+
+		headerSize := int(le.Uint16(r.buf[4:]))
+		objSize := int(le.Uint32(r.buf[8:]))
+		objType := le.Uint32(r.buf[12:])
+
+		if headerSize < objectHeaderBaseSize || objSize < headerSize {
+			return nil, ErrInvalidObject
+		}
+
+		ok, err = r.fill(objSize)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		obj := r.buf[:objSize]
+
+		// the padding may be missing at the end of the file
+		padding := getObjectPadding(objType, objSize)
+		if _, err := r.fill(objSize + padding); err != nil {
+			return nil, err
+		}
+
+		r.index++
+
+		frame, err := r.parseObject(obj, headerSize, objType)
+		r.discard(objSize + padding)
+
+		if err != nil {
+			return nil, &ObjectError{Index: r.index, Type: objType, Err: err}
+		}
+
+		if frame != nil {
+			return frame, nil
+		}
+	}
+}
+
+// Frames returns an iterator over the frames of the file.
+// The objects that cannot be parsed are yielded as an [ObjectError]
+// and the iteration continues, while it stops on any other error.
+func (r *Reader) Frames() iter.Seq2[*acmelib.Frame, error] {
+	return func(yield func(*acmelib.Frame, error) bool) {
+		for {
+			frame, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+
+				objErr := &ObjectError{}
+				if errors.As(err, &objErr) {
+					continue
+				}
+				return
+			}
+
+			if !yield(frame, nil) {
+				return
+			}
+		}
+	}
+}
+
+// parseObject parses an object into a frame.
+// It returns a nil frame if the object type is not supported.
+func (r *Reader) parseObject(obj []byte, headerSize int, objType uint32) (*acmelib.Frame, error) {
+	var bodySize int
+	var parse func(frame *acmelib.Frame, body []byte) error
+
+	switch objType {
+	case objectTypeCANMessage:
+		bodySize = canMessageSize
+		parse = parseCANMessage
+	case objectTypeCANMessage2:
+		bodySize = canMessage2Size
+		parse = parseCANMessage
+	case objectTypeCANFDMessage:
+		bodySize = canFDMessageSize
+		parse = parseCANFDMessage
+	case objectTypeCANFDMessage64:
+		bodySize = canFDMessage64Size
+		parse = parseCANFDMessage64
+	case objectTypeCANError:
+		bodySize = canErrorSize
+		parse = parseCANError
+	case objectTypeCANErrorExt:
+		bodySize = canErrorExtSize
+		parse = parseCANError
+	default:
+		return nil, nil
+	}
+
+	if headerSize < objectHeaderSize || len(obj) < headerSize+bodySize {
+		return nil, ErrInvalidObject
+	}
+
+	// the flags and the timestamp have the same position in both
+	// the version 1 and 2 of the object header
+	flags := le.Uint32(obj[16:])
+	timestamp := le.Uint64(obj[24:])
+
+	offset := time.Duration(timestamp)
+	if flags&timeFlagTenMicros != 0 {
+		offset *= 10 * time.Microsecond
+	}
+
+	frame := &acmelib.Frame{
+		Timestamp: r.header.startTime.Add(offset),
+	}
+
+	if err := parse(frame, obj[headerSize:]); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+func parseDirection(frame *acmelib.Frame, isTx bool) {
+	if isTx {
+		frame.Direction = acmelib.FrameDirectionTx
+	} else {
+		frame.Direction = acmelib.FrameDirectionRx
+	}
+}
+
+func parseCANID(frame *acmelib.Frame, id uint32) {
+	frame.IsExtended = id&extendedIDFlag != 0
+	frame.CANID = acmelib.CANID(id &^ extendedIDFlag)
+}
+
+// parseCANMessage parses the body of a CAN_MESSAGE or CAN_MESSAGE2 object:
+// channel (u16), flags (u8), dlc (u8), id (u32), data (8 bytes).
+func parseCANMessage(frame *acmelib.Frame, body []byte) error {
+	frame.Channel = strconv.Itoa(int(le.Uint16(body[0:])))
+
+	flags := body[2]
+	dlc := int(body[3])
+	parseDirection(frame, flags&msgFlagTx != 0)
+	parseCANID(frame, le.Uint32(body[4:]))
+
+	if dlc > 15 {
+		return ErrInvalidData
+	}
+
+	if flags&msgFlagRemote != 0 {
+		frame.IsRemote = true
+		frame.RemoteLength = min(dlc, 8)
+		return nil
+	}
+
+	frame.Data = bytes.Clone(body[8 : 8+min(dlc, 8)])
+
+	return nil
+}
+
+// parseCANFDMessage parses the body of a CAN_FD_MESSAGE object:
+// channel (u16), flags (u8), dlc (u8), id (u32), frame length (u32), bit count (u8),
+// FD flags (u8), valid data bytes (u8), reserved (5 bytes), data (64 bytes).
+func parseCANFDMessage(frame *acmelib.Frame, body []byte) error {
+	frame.Channel = strconv.Itoa(int(le.Uint16(body[0:])))
+
+	flags := body[2]
+	dlc := int(body[3])
+	parseDirection(frame, flags&msgFlagTx != 0)
+	parseCANID(frame, le.Uint32(body[4:]))
+
+	fdFlags := body[13]
+	length := int(body[14])
+
+	if length > 64 || dlc > 15 {
+		return ErrInvalidData
+	}
+
+	frame.IsFD = fdFlags&fdFlagEDL != 0
+	frame.BitRateSwitch = fdFlags&fdFlagBRS != 0
+	frame.ErrorStateIndicator = fdFlags&fdFlagESI != 0
+
+	if flags&msgFlagRemote != 0 {
+		frame.IsRemote = true
+		frame.RemoteLength = min(dlc, 8)
+		return nil
+	}
+
+	if !frame.IsFD && length > 8 {
+		return ErrInvalidData
+	}
+
+	frame.Data = bytes.Clone(body[20 : 20+length])
+
+	return nil
+}
+
+// parseCANFDMessage64 parses the body of a CAN_FD_MESSAGE64 object:
+// channel (u8), dlc (u8), valid data bytes (u8), tx count (u8), id (u32),
+// frame length (u32), flags (u32), bit timings and offsets (5 x u32),
+// bit count (u16), direction (u8), extended data offset (u8), crc (u32),
+// followed by the data.
+func parseCANFDMessage64(frame *acmelib.Frame, body []byte) error {
+	frame.Channel = strconv.Itoa(int(body[0]))
+
+	dlc := int(body[1])
+	length := int(body[2])
+	parseCANID(frame, le.Uint32(body[4:]))
+
+	flags := le.Uint32(body[12:])
+	parseDirection(frame, body[34] == 1)
+
+	if length > 64 || dlc > 15 || len(body) < canFDMessage64Size+length {
+		return ErrInvalidData
+	}
+
+	frame.IsFD = flags&fd64FlagEDL != 0
+	frame.BitRateSwitch = flags&fd64FlagBRS != 0
+	frame.ErrorStateIndicator = flags&fd64FlagESI != 0
+
+	if flags&fd64FlagRemote != 0 {
+		frame.IsRemote = true
+		frame.RemoteLength = min(dlc, 8)
+		return nil
+	}
+
+	if !frame.IsFD && length > 8 {
+		return ErrInvalidData
+	}
+
+	frame.Data = bytes.Clone(body[canFDMessage64Size : canFDMessage64Size+length])
+
+	return nil
+}
+
+// parseCANError parses the body of a CAN_ERROR or CAN_ERROR_EXT object,
+// which both start with the channel (u16).
+func parseCANError(frame *acmelib.Frame, body []byte) error {
+	frame.Channel = strconv.Itoa(int(le.Uint16(body[0:])))
+	frame.IsError = true
+	return nil
+}
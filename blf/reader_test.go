@@ -0,0 +1,209 @@
+package blf
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func readFrames(assert *assert.Assertions, r io.Reader) ([]*acmelib.Frame, []error, *Reader) {
+	reader, err := NewReader(r)
+	assert.NoError(err)
+
+	frames := []*acmelib.Frame{}
+	errs := []error{}
+	for frame, err := range reader.Frames() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames, errs, reader
+}
+
+func Test_Reader_Read(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/session.blf")
+	assert.NoError(err)
+	defer file.Close()
+
+	frames, errs, r := readFrames(assert, file)
+	assert.Len(frames, 7)
+
+	// should parse the file header
+	startTime := time.Date(2024, time.July, 10, 14, 15, 30, 250_000_000, time.Local)
+	assert.Equal(startTime, r.StartTime())
+	assert.Equal(startTime.Add(100*time.Millisecond), r.StopTime())
+	assert.Equal(9, r.ObjectCount())
+
+	// should parse a CAN_MESSAGE object
+	frame := frames[0]
+	assert.Equal(startTime.Add(10*time.Millisecond), frame.Timestamp)
+	assert.Equal("1", frame.Channel)
+	assert.Equal(acmelib.FrameDirectionRx, frame.Direction)
+	assert.Equal(acmelib.CANID(0x10), frame.CANID)
+	assert.False(frame.IsExtended)
+	assert.Equal([]byte{1, 2, 3, 4, 5, 6, 7, 8}, frame.Data)
+
+	// should parse a CAN_MESSAGE2 object with the version 2 of the header
+	frame = frames[1]
+	assert.Equal(startTime.Add(20500*time.Microsecond), frame.Timestamp)
+	assert.Equal("2", frame.Channel)
+	assert.Equal(acmelib.FrameDirectionTx, frame.Direction)
+	assert.Equal(acmelib.CANID(0x18FEF100), frame.CANID)
+	assert.True(frame.IsExtended)
+	assert.Equal([]byte{0xAA, 0xBB, 0xCC}, frame.Data)
+
+	// should parse a CAN_FD_MESSAGE object split between two containers
+	frame = frames[2]
+	assert.Equal(startTime.Add(50*time.Millisecond), frame.Timestamp)
+	assert.True(frame.IsFD)
+	assert.True(frame.BitRateSwitch)
+	assert.False(frame.ErrorStateIndicator)
+	assert.Equal(acmelib.CANID(0x200), frame.CANID)
+	assert.Len(frame.Data, 12)
+
+	// should parse a CAN_FD_MESSAGE64 object
+	frame = frames[3]
+	assert.Equal(startTime.Add(60*time.Millisecond), frame.Timestamp)
+	assert.Equal("2", frame.Channel)
+	assert.Equal(acmelib.FrameDirectionTx, frame.Direction)
+	assert.True(frame.IsFD)
+	assert.False(frame.BitRateSwitch)
+	assert.True(frame.ErrorStateIndicator)
+	assert.Equal(acmelib.CANID(0x123), frame.CANID)
+	assert.Len(frame.Data, 20)
+	assert.Equal(byte(39), frame.Data[19])
+
+	// should parse a remote frame
+	frame = frames[4]
+	assert.True(frame.IsRemote)
+	assert.Equal(4, frame.RemoteLength)
+	assert.Empty(frame.Data)
+
+	// should parse an error frame
+	frame = frames[5]
+	assert.True(frame.IsError)
+	assert.Equal("1", frame.Channel)
+	assert.Equal(startTime.Add(80*time.Millisecond), frame.Timestamp)
+
+	// should yield the invalid objects and continue
+	assert.Len(errs, 1)
+	objErr := &ObjectError{}
+	assert.ErrorAs(errs[0], &objErr)
+	assert.Equal(8, objErr.Index)
+	assert.Equal(objectTypeCANMessage, objErr.Type)
+	assert.ErrorIs(errs[0], ErrInvalidData)
+
+	assert.Equal([]byte{7, 8}, frames[6].Data)
+
+	// should return io.EOF at the end
+	_, err = r.Read()
+	assert.ErrorIs(err, io.EOF)
+}
+
+func Test_Reader_errors(t *testing.T) {
+	assert := assert.New(t)
+
+	content, err := os.ReadFile("testdata/session.blf")
+	assert.NoError(err)
+
+	// should return an error if the file signature is invalid
+	_, err = NewReader(bytes.NewReader([]byte("NOPE" + string(content[4:]))))
+	assert.ErrorIs(err, ErrInvalidSignature)
+
+	// should return an error if the file header is truncated
+	_, err = NewReader(bytes.NewReader(content[:40]))
+	assert.ErrorIs(err, ErrInvalidHeader)
+
+	// should stop when the file is truncated
+	_, errs, _ := readFrames(assert, bytes.NewReader(content[:len(content)-30]))
+	assert.Len(errs, 1)
+	assert.ErrorIs(errs[0], io.ErrUnexpectedEOF)
+
+	// should stop when the signature of a container is invalid
+	invalid := bytes.Clone(content)
+	copy(invalid[fileHeaderSize:], "NOPE")
+	frames, errs, _ := readFrames(assert, bytes.NewReader(invalid))
+	assert.Empty(frames)
+	assert.Len(errs, 1)
+	assert.ErrorIs(errs[0], ErrInvalidSignature)
+
+	// should stop when the size of a container is too big
+	invalid = bytes.Clone(content)
+	le.PutUint32(invalid[fileHeaderSize+8:], 0xFFFFFFF0)
+	frames, errs, _ = readFrames(assert, bytes.NewReader(invalid))
+	assert.Empty(frames)
+	assert.Len(errs, 1)
+	assert.ErrorIs(errs[0], ErrInvalidHeader)
+
+	// should stop when a compressed container exceeds its uncompressed size
+	expected, _, r := readFrames(assert, bytes.NewReader(content))
+	path := filepath.Join(t.TempDir(), "compressed.blf")
+	writeFrames(assert, path, expected, &WriterOptions{StartTime: r.StartTime()})
+	compressed, err := os.ReadFile(path)
+	assert.NoError(err)
+
+	le.PutUint32(compressed[fileHeaderSize+objectHeaderBaseSize+8:], 10)
+	frames, errs, _ = readFrames(assert, bytes.NewReader(compressed))
+	assert.Empty(frames)
+	assert.Len(errs, 1)
+	assert.ErrorIs(errs[0], ErrInvalidHeader)
+}
+
+func Test_Reader_decode(t *testing.T) {
+	assert := assert.New(t)
+
+	net := acmelib.NewNetwork("net")
+	bus := acmelib.NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	node := acmelib.NewNode("node", 1, 1)
+	nodeInt := node.GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := acmelib.NewMessage("msg", 1, 2)
+	assert.NoError(msg.SetStaticCANID(0x10))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	typ, err := acmelib.NewIntegerSignalType("uint8", 8, false)
+	assert.NoError(err)
+	sig, err := acmelib.NewStandardSignal("sig", typ)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(sig, 8))
+
+	dec, err := bus.NewDecoder()
+	assert.NoError(err)
+
+	file, err := os.Open("testdata/session.blf")
+	assert.NoError(err)
+	defer file.Close()
+
+	r, err := NewReader(file)
+	assert.NoError(err)
+
+	// should decode the frames of the message, while the first one is too big
+	rawValues := []uint64{}
+	errCount := 0
+	for frameDec, err := range dec.DecodeFrames(r.Frames()) {
+		if err != nil {
+			errCount++
+		}
+		if frameDec != nil {
+			assert.Equal(msg.EntityID(), frameDec.Message.EntityID())
+			rawValues = append(rawValues, frameDec.Signals[0].RawValue)
+		}
+	}
+
+	assert.Equal([]uint64{2, 8}, rawValues)
+	assert.Equal(2, errCount)
+}
@@ -0,0 +1,313 @@
+package blf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// applicationID is the id of the application written in the file header.
+// It is the one of the files not written by the Vector tools.
+const applicationID = 5
+
+// WriterOptions are the options of a [Writer].
+type WriterOptions struct {
+	// StartTime is the start time of the measurement written in the file header,
+	// which has the precision of a millisecond.
+	// If it is zero, the timestamp of the first frame is used.
+	StartTime time.Time
+	// Uncompressed reports whether the log containers are written without compression.
+	Uncompressed bool
+}
+
+// Writer writes frames as objects of a BLF file.
+// The CAN 2.0 frames are written as CAN_MESSAGE objects, the CAN FD frames
+// as CAN_FD_MESSAGE objects and the error frames as CAN_ERROR_EXT objects.
+// The objects are grouped in log containers, which are compressed with zlib
+// unless the options say otherwise.
+//
+// The file header holds the size of the file and the number of objects,
+// so it is written again by [Writer.Close], which must be called at the end.
+//
+// The numeric channels of the frames are kept, while the other channels
+// (like the names of the interfaces) are mapped to the first free channel number,
+// in order of appearance.
+type Writer struct {
+	w    io.WriteSeeker
+	opts WriterOptions
+
+	header *fileHeader
+	buf    *bytes.Buffer
+
+	lastOffset time.Duration
+
+	channels    map[string]int
+	usedChannel map[int]bool
+}
+
+// NewWriter creates a new [Writer] that writes to the given writer
+// with the given options. The options can be nil.
+// It writes a placeholder of the file header immediately.
+func NewWriter(w io.WriteSeeker, opts *WriterOptions) (*Writer, error) {
+	if opts == nil {
+		opts = &WriterOptions{}
+	}
+
+	header := &fileHeader{
+		applicationID:    applicationID,
+		fileSize:         fileHeaderSize,
+		uncompressedSize: fileHeaderSize,
+		objectCount:      0,
+	}
+
+	if _, err := w.Write(header.encode()); err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		w:    w,
+		opts: *opts,
+
+		header: header,
+		buf:    new(bytes.Buffer),
+
+		lastOffset: 0,
+
+		channels:    make(map[string]int),
+		usedChannel: make(map[int]bool),
+	}, nil
+}
+
+// getChannel returns the number of the given channel.
+func (w *Writer) getChannel(channel string) int {
+	if ch, ok := w.channels[channel]; ok {
+		return ch
+	}
+
+	ch, err := strconv.Atoi(channel)
+	if err != nil || ch <= 0 {
+		ch = 1
+		for w.usedChannel[ch] {
+			ch++
+		}
+	}
+
+	w.channels[channel] = ch
+	w.usedChannel[ch] = true
+
+	return ch
+}
+
+// getOffset returns the offset of the given timestamp from the start time.
+func (w *Writer) getOffset(timestamp time.Time) time.Duration {
+	if w.opts.StartTime.IsZero() {
+		w.opts.StartTime = timestamp
+		if timestamp.IsZero() {
+			w.opts.StartTime = time.Now()
+		}
+
+		// the start time is stored with the precision of a millisecond
+		w.opts.StartTime = w.opts.StartTime.Truncate(time.Millisecond)
+	}
+
+	if timestamp.IsZero() {
+		return w.lastOffset
+	}
+
+	offset := max(timestamp.Sub(w.opts.StartTime), 0)
+	w.lastOffset = max(w.lastOffset, offset)
+
+	return offset
+}
+
+// WriteFrame writes the given frame as an object of the file.
+func (w *Writer) WriteFrame(frame *acmelib.Frame) error {
+	offset := w.getOffset(frame.Timestamp)
+	channel := w.getChannel(frame.Channel)
+
+	var objType uint32
+	var body []byte
+
+	switch {
+	case frame.IsError:
+		objType = objectTypeCANErrorExt
+		body = encodeCANError(channel)
+	case frame.IsFD:
+		objType = objectTypeCANFDMessage
+		body = encodeCANFDMessage(frame, channel)
+	default:
+		objType = objectTypeCANMessage
+		body = encodeCANMessage(frame, channel)
+	}
+
+	objSize := objectHeaderSize + len(body)
+
+	header := make([]byte, objectHeaderSize)
+	copy(header[0:], objectSignature)
+	le.PutUint16(header[4:], objectHeaderSize)
+	le.PutUint16(header[6:], 1)
+	le.PutUint32(header[8:], uint32(objSize))
+	le.PutUint32(header[12:], objType)
+	le.PutUint32(header[16:], timeFlagOneNanos)
+	le.PutUint64(header[24:], uint64(offset))
+
+	w.buf.Write(header)
+	w.buf.Write(body)
+	w.buf.Write(make([]byte, getObjectPadding(objType, objSize)))
+
+	w.header.objectCount++
+
+	for w.buf.Len() >= maxContainerSize {
+		if err := w.writeContainer(w.buf.Next(maxContainerSize)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeContainer writes the given data as a log container.
+func (w *Writer) writeContainer(data []byte) error {
+	method := compressionZlib
+	content := data
+
+	if w.opts.Uncompressed {
+		method = compressionNone
+	} else {
+		compressed := new(bytes.Buffer)
+		zw := zlib.NewWriter(compressed)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		content = compressed.Bytes()
+	}
+
+	objSize := objectHeaderBaseSize + containerHeaderSize + len(content)
+
+	header := make([]byte, objectHeaderBaseSize+containerHeaderSize)
+	copy(header[0:], objectSignature)
+	le.PutUint16(header[4:], objectHeaderBaseSize)
+	le.PutUint16(header[6:], 1)
+	le.PutUint32(header[8:], uint32(objSize))
+	le.PutUint32(header[12:], objectTypeLogContainer)
+	le.PutUint16(header[16:], method)
+	le.PutUint32(header[24:], uint32(len(data)))
+
+	padding := make([]byte, getObjectPadding(objectTypeLogContainer, objSize))
+
+	for _, chunk := range [][]byte{header, content, padding} {
+		if _, err := w.w.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	w.header.fileSize += uint64(objSize + len(padding))
+	w.header.uncompressedSize += uint64(len(header) + len(data))
+
+	return nil
+}
+
+// Close writes the pending objects and the final file header.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.buf.Len() > 0 {
+		if err := w.writeContainer(w.buf.Bytes()); err != nil {
+			return err
+		}
+		w.buf.Reset()
+	}
+
+	if w.opts.StartTime.IsZero() {
+		w.opts.StartTime = time.Now().Truncate(time.Millisecond)
+	}
+	w.header.startTime = w.opts.StartTime
+	w.header.stopTime = w.opts.StartTime.Add(w.lastOffset)
+
+	if _, err := w.w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if _, err := w.w.Write(w.header.encode()); err != nil {
+		return err
+	}
+
+	_, err := w.w.Seek(0, io.SeekEnd)
+	return err
+}
+
+func encodeCANID(frame *acmelib.Frame) uint32 {
+	id := uint32(frame.CANID)
+	if frame.IsExtended {
+		id |= extendedIDFlag
+	}
+	return id
+}
+
+func encodeMessageFlags(frame *acmelib.Frame) uint8 {
+	flags := uint8(0)
+	if frame.Direction == acmelib.FrameDirectionTx {
+		flags |= msgFlagTx
+	}
+	if frame.IsRemote {
+		flags |= msgFlagRemote
+	}
+	return flags
+}
+
+func encodeCANMessage(frame *acmelib.Frame, channel int) []byte {
+	body := make([]byte, canMessageSize)
+
+	dlc := len(frame.Data)
+	if frame.IsRemote {
+		dlc = frame.RemoteLength
+	}
+
+	le.PutUint16(body[0:], uint16(channel))
+	body[2] = encodeMessageFlags(frame)
+	body[3] = uint8(dlc)
+	le.PutUint32(body[4:], encodeCANID(frame))
+	copy(body[8:16], frame.Data)
+
+	return body
+}
+
+func encodeCANFDMessage(frame *acmelib.Frame, channel int) []byte {
+	body := make([]byte, canFDMessageSize)
+
+	fdFlags := uint8(fdFlagEDL)
+	if frame.BitRateSwitch {
+		fdFlags |= fdFlagBRS
+	}
+	if frame.ErrorStateIndicator {
+		fdFlags |= fdFlagESI
+	}
+
+	// the length of a valid frame is always a valid size
+	dlc, _ := acmelib.SizeByteToDLC(len(frame.Data))
+	if frame.IsRemote {
+		dlc = frame.RemoteLength
+	}
+
+	le.PutUint16(body[0:], uint16(channel))
+	body[2] = encodeMessageFlags(frame)
+	body[3] = uint8(dlc)
+	le.PutUint32(body[4:], encodeCANID(frame))
+	body[13] = fdFlags
+	body[14] = uint8(len(frame.Data))
+	copy(body[20:84], frame.Data)
+
+	return body
+}
+
+func encodeCANError(channel int) []byte {
+	body := make([]byte, canErrorExtSize)
+	le.PutUint16(body[0:], uint16(channel))
+	return body
+}
@@ -0,0 +1,90 @@
+package blf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFrames(assert *assert.Assertions, path string, frames []*acmelib.Frame, opts *WriterOptions) {
+	file, err := os.Create(path)
+	assert.NoError(err)
+	defer file.Close()
+
+	w, err := NewWriter(file, opts)
+	assert.NoError(err)
+
+	for _, frame := range frames {
+		assert.NoError(w.WriteFrame(frame))
+	}
+	assert.NoError(w.Close())
+}
+
+func Test_Writer_WriteFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/session.blf")
+	assert.NoError(err)
+	defer file.Close()
+
+	expected, _, r := readFrames(assert, file)
+
+	// should read the same frames that have been written
+	for _, uncompressed := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "session.blf")
+		writeFrames(assert, path, expected, &WriterOptions{StartTime: r.StartTime(), Uncompressed: uncompressed})
+
+		written, err := os.Open(path)
+		assert.NoError(err)
+		defer written.Close()
+
+		frames, errs, writtenReader := readFrames(assert, written)
+		assert.Empty(errs)
+		assert.Equal(expected, frames)
+
+		assert.Equal(r.StartTime(), writtenReader.StartTime())
+		assert.Equal(r.StopTime(), writtenReader.StopTime())
+		assert.Equal(len(expected), writtenReader.ObjectCount())
+	}
+}
+
+func Test_Writer_containers(t *testing.T) {
+	assert := assert.New(t)
+
+	startTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local)
+
+	// the frames do not fit in a single container
+	frames := []*acmelib.Frame{}
+	for idx := range 10_000 {
+		frames = append(frames, &acmelib.Frame{
+			Timestamp: startTime.Add(time.Duration(idx) * time.Millisecond),
+			Channel:   "vcan0",
+			CANID:     acmelib.CANID(idx % 0x800),
+			Data:      []byte{byte(idx), byte(idx >> 8)},
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "containers.blf")
+	writeFrames(assert, path, frames, nil)
+
+	file, err := os.Open(path)
+	assert.NoError(err)
+	defer file.Close()
+
+	read, errs, r := readFrames(assert, file)
+	assert.Empty(errs)
+	assert.Len(read, len(frames))
+
+	// should use the timestamp of the first frame as the start time
+	assert.Equal(startTime, r.StartTime())
+	assert.Equal(startTime.Add(9999*time.Millisecond), r.StopTime())
+
+	// should map the channels that are not numeric
+	assert.Equal("1", read[0].Channel)
+	assert.Equal(frames[9999].Timestamp, read[9999].Timestamp)
+	assert.Equal(frames[9999].Data, read[9999].Data)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/squadracorsepolito/acmelib/internal/collection"
 	"github.com/squadracorsepolito/acmelib/internal/stringer"
+	"github.com/squadracorsepolito/acmelib/isotp"
 )
 
 // BusType is the type of a [Bus].
@@ -14,17 +15,73 @@ type BusType int
 const (
 	// BusTypeCAN2A represents a CAN 2.0A bus.
 	BusTypeCAN2A BusType = iota
+	// BusTypeCANFD represents a CAN FD bus.
+	BusTypeCANFD
+	// BusTypeCAN2B represents a CAN 2.0B bus,
+	// which supports both standard (11 bits) and extended (29 bits) CAN-IDs.
+	BusTypeCAN2B
 )
 
 func (bt BusType) String() string {
 	switch bt {
 	case BusTypeCAN2A:
 		return "CAN_2.0A"
+	case BusTypeCANFD:
+		return "CAN_FD"
+	case BusTypeCAN2B:
+		return "CAN_2.0B"
 	default:
 		return "unknown"
 	}
 }
 
+// dlcSizeBytes maps a DLC code (index) to the payload size in bytes.
+// Codes from 9 to 15 are only valid for CAN FD.
+var dlcSizeBytes = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 12, 16, 20, 24, 32, 48, 64}
+
+// SizeByteToDLC returns the DLC code of the given payload size in bytes.
+// The size must be between 0 and 8, or one of the CAN FD payload sizes
+// (12, 16, 20, 24, 32, 48, 64).
+//
+// It returns a [SizeError] if the size is not a valid payload size.
+func SizeByteToDLC(sizeByte int) (int, error) {
+	if sizeByte < 0 {
+		return 0, newSizeError(sizeByte, ErrIsNegative)
+	}
+
+	if sizeByte <= 8 {
+		return sizeByte, nil
+	}
+
+	for dlc := 9; dlc < len(dlcSizeBytes); dlc++ {
+		if dlcSizeBytes[dlc] == sizeByte {
+			return dlc, nil
+		}
+	}
+
+	if sizeByte > dlcSizeBytes[len(dlcSizeBytes)-1] {
+		return 0, newSizeError(sizeByte, ErrTooBig)
+	}
+
+	return 0, newSizeError(sizeByte, ErrInvalidValue)
+}
+
+// DLCToSizeByte returns the payload size in bytes of the given DLC code.
+// For CAN 2.0 frames, codes from 9 to 15 must be interpreted as 8 bytes.
+//
+// It returns an [ArgError] if the DLC code is not between 0 and 15.
+func DLCToSizeByte(dlc int) (int, error) {
+	if dlc < 0 {
+		return 0, newArgError("dlc", ErrIsNegative)
+	}
+
+	if dlc >= len(dlcSizeBytes) {
+		return 0, newArgError("dlc", ErrOutOfBounds)
+	}
+
+	return dlcSizeBytes[dlc], nil
+}
+
 // Bus is the virtual representation of physical CAN bus cable.
 // It holds a list of nodes that are connected to it.
 type Bus struct {
@@ -42,8 +99,10 @@ type Bus struct {
 
 	messageStaticCANIDs *collection.Map[CANID, EntityID]
 
-	baudrate int
-	typ      BusType
+	baudrate      int
+	dataBaudrate  int
+	bitRateSwitch bool
+	typ           BusType
 }
 
 func newBusFromEntity(ent *entity) *Bus {
@@ -61,8 +120,10 @@ func newBusFromEntity(ent *entity) *Bus {
 
 		messageStaticCANIDs: collection.NewMap[CANID, EntityID](),
 
-		baudrate: 0,
-		typ:      BusTypeCAN2A,
+		baudrate:      0,
+		dataBaudrate:  0,
+		bitRateSwitch: false,
+		typ:           BusTypeCAN2A,
 	}
 
 	builder := newDefaultCANIDBuilder()
@@ -122,21 +183,73 @@ func (b *Bus) verifyStaticCANID(staticCANID CANID) error {
 	return nil
 }
 
-func (b *Bus) verifyMessageSize(sizeByte int) error {
+// verifyMessageSize checks if the bus supports a message of the given size
+// sent with the given [MessageTransport].
+// The transport protocols lift the limit of the data length of a single frame.
+//
+// It returns:
+//   - [SizeError] if the size is not supported.
+//   - [ErrInvalidType] if the J1939 transport protocol is used within a CAN 2.0A bus,
+//     since it needs the extended CAN-IDs.
+func (b *Bus) verifyMessageSize(sizeByte int, transport MessageTransport) error {
+	switch transport {
+	case MessageTransportJ1939TP:
+		if b.typ == BusTypeCAN2A {
+			return ErrInvalidType
+		}
+
+		if sizeByte > J1939MaxTPSizeByte {
+			return newSizeError(sizeByte, ErrTooBig)
+		}
+		return nil
+
+	case MessageTransportISOTP:
+		if sizeByte > isotp.MaxSizeByte {
+			return newSizeError(sizeByte, ErrTooBig)
+		}
+		return nil
+	}
+
 	switch b.typ {
-	case BusTypeCAN2A:
+	case BusTypeCAN2A, BusTypeCAN2B:
 		if sizeByte <= 8 {
 			return nil
 		}
+
+	case BusTypeCANFD:
+		_, err := SizeByteToDLC(sizeByte)
+		return err
 	}
 
 	return newSizeError(sizeByte, ErrTooBig)
 }
 
+// verifyCANIDFormat checks if the bus supports the given [CANIDFormat].
+// Only CAN 2.0A buses do not support extended CAN-IDs.
+//
+// It returns an [ErrInvalidType] if the format is not supported.
+func (b *Bus) verifyCANIDFormat(format CANIDFormat) error {
+	if b.typ == BusTypeCAN2A && format == CANIDFormatExtended {
+		return ErrInvalidType
+	}
+	return nil
+}
+
+// verifyCANIDBuilderFormat checks if the CAN-IDs calculated
+// by the [CANIDBuilder] of the bus fit the given [CANIDFormat].
+//
+// It returns a [CANIDError] if they do not fit the format.
+func (b *Bus) verifyCANIDBuilderFormat(format CANIDFormat) error {
+	return b.canIDBuilder.VerifyFormat(format)
+}
+
 func (b *Bus) stringify(s *stringer.Stringer) {
 	b.entity.stringify(s)
 
 	s.Write("baudrate: %d\n", b.baudrate)
+	if b.typ == BusTypeCANFD {
+		s.Write("data_baudrate: %d; bit_rate_switch: %t\n", b.dataBaudrate, b.bitRateSwitch)
+	}
 
 	b.canIDBuilder.stringify(s)
 
@@ -193,7 +306,9 @@ func (b *Bus) ParentNetwork() *Network {
 //   - [NameError] if the node name is invalid.
 //   - [NodeIDError] if the node id is invalid.
 //   - [MessageSizeError] if one of the size of a message sent by the node is invalid.
-//   - [CANIDError] if one of the static CAN-ID of a message sent by the node is invalid.
+//   - [CANIDError] if one of the static CAN-ID of a message sent by the node is invalid,
+//     or if the CAN-IDs calculated by the CAN-ID builder do not fit the format of a message.
+//   - [ErrInvalidType] if the bus does not support the CAN-ID format of a message sent by the node.
 func (b *Bus) AddNodeInterface(nodeInterface *NodeInterface) error {
 	if nodeInterface == nil {
 		return &ArgError{
@@ -214,7 +329,11 @@ func (b *Bus) AddNodeInterface(nodeInterface *NodeInterface) error {
 
 	msgStaticCANIDs := make(map[CANID]EntityID)
 	for tmpMsg := range nodeInterface.sentMessages.Values() {
-		if err := b.verifyMessageSize(tmpMsg.sizeByte); err != nil {
+		if err := b.verifyMessageSize(tmpMsg.sizeByte, tmpMsg.transport); err != nil {
+			return b.errorf(err)
+		}
+
+		if err := b.verifyCANIDFormat(tmpMsg.canIDFormat); err != nil {
 			return b.errorf(err)
 		}
 
@@ -225,6 +344,8 @@ func (b *Bus) AddNodeInterface(nodeInterface *NodeInterface) error {
 			}
 
 			msgStaticCANIDs[tmpMsg.staticCANID] = tmpMsg.entityID
+		} else if err := b.verifyCANIDBuilderFormat(tmpMsg.canIDFormat); err != nil {
+			return b.errorf(err)
 		}
 	}
 	for canID, entID := range msgStaticCANIDs {
@@ -311,11 +432,37 @@ func (b *Bus) SetBaudrate(baudrate int) {
 }
 
 // Baudrate returns the baudrate of the [Bus].
+// For CAN FD buses, it is the baudrate of the arbitration phase.
 func (b *Bus) Baudrate() int {
 	return b.baudrate
 }
 
+// SetDataBaudrate sets the baudrate of the data phase of the [Bus].
+// It is only used by CAN FD buses when the bit rate switch is enabled.
+func (b *Bus) SetDataBaudrate(dataBaudrate int) {
+	b.dataBaudrate = dataBaudrate
+}
+
+// DataBaudrate returns the baudrate of the data phase of the [Bus].
+func (b *Bus) DataBaudrate() int {
+	return b.dataBaudrate
+}
+
+// SetBitRateSwitch sets whether the messages of the [Bus] are sent
+// with the bit rate switch (BRS) enabled.
+// It is only used by CAN FD buses.
+func (b *Bus) SetBitRateSwitch(bitRateSwitch bool) {
+	b.bitRateSwitch = bitRateSwitch
+}
+
+// BitRateSwitch returns whether the bit rate switch (BRS) is enabled.
+func (b *Bus) BitRateSwitch() bool {
+	return b.bitRateSwitch
+}
+
 // SetCANIDBuilder sets the [CANIDBuilder] of the [Bus].
+// It does not verify the messages already sent within the bus,
+// call [Bus.Validate] to check them against the new builder.
 func (b *Bus) SetCANIDBuilder(canIDBuilder *CANIDBuilder) {
 	if b.canIDBuilder != nil {
 		b.canIDBuilder.removeRef(b.entityID)
@@ -331,6 +478,8 @@ func (b *Bus) CANIDBuilder() *CANIDBuilder {
 }
 
 // SetType sets the type of the [Bus].
+// It does not verify the messages already sent within the bus,
+// call [Bus.Validate] to check them against the new type.
 func (b *Bus) SetType(typ BusType) {
 	b.typ = typ
 }
@@ -340,6 +489,38 @@ func (b *Bus) Type() BusType {
 	return b.typ
 }
 
+// Validate checks whether the messages sent by the node interfaces
+// of the [Bus] are supported by its type and by its [CANIDBuilder].
+//
+// It returns:
+//   - [MessageSizeError] if the size of a message is not supported by the type.
+//   - [ErrInvalidType] if the CAN-ID format of a message is not supported by the type.
+//   - [CANIDError] if the CAN-IDs calculated by the CAN-ID builder do not fit
+//     the format of a message without a static CAN-ID.
+func (b *Bus) Validate() error {
+	for tmpInt := range b.nodeInts.Values() {
+		for tmpMsg := range tmpInt.sentMessages.Values() {
+			if err := b.verifyMessageSize(tmpMsg.sizeByte, tmpMsg.transport); err != nil {
+				return tmpMsg.errorf(err)
+			}
+
+			if err := b.verifyCANIDFormat(tmpMsg.canIDFormat); err != nil {
+				return tmpMsg.errorf(err)
+			}
+
+			if tmpMsg.hasStaticCANID {
+				continue
+			}
+
+			if err := b.verifyCANIDBuilderFormat(tmpMsg.canIDFormat); err != nil {
+				return tmpMsg.errorf(err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // AssignAttribute assigns the given attribute/value pair to the [Bus].
 //
 // It returns an [ArgError] if the attribute is nil,
@@ -379,6 +560,19 @@ func (b *Bus) ToBus() (*Bus, error) {
 	return b, nil
 }
 
+// NewDecoder returns a [Decoder] for all the messages sent in the bus.
+// The CAN-IDs are the ones returned by [Message.GetCANID], so they take into account
+// the [CANIDBuilder] of the bus and the static CAN-IDs.
+//
+// It returns a [CANIDError] if two messages have the same CAN-ID and format.
+func (b *Bus) NewDecoder() (*Decoder, error) {
+	dec := newDecoder()
+	if err := dec.addBus(b); err != nil {
+		return nil, err
+	}
+	return dec, nil
+}
+
 // BusLoadMessage is a struct that represents the load caused by a [Message] in a [Bus].
 type BusLoadMessage struct {
 	// Message is the examined message.
@@ -389,11 +583,128 @@ type BusLoadMessage struct {
 	Percentage float64
 }
 
+// estimateFrameBits returns the number of bits of a frame carrying
+// the given number of bytes with the given CAN-ID format in the worst case scenario.
+// For CAN FD buses with the bit rate switch enabled, the bits of the data phase
+// are scaled to the baudrate of the arbitration phase.
+func (b *Bus) estimateFrameBits(sizeByte int, format CANIDFormat) float64 {
+	dataBits := sizeByte * 8
+	isExtended := format == CANIDFormatExtended
+
+	if b.typ == BusTypeCANFD {
+		// start of frame + id + rrs + ide + fdf + res + brs
+		arbitrationBits := 17
+		if isExtended {
+			// start of frame + base id + srr + ide + extended id + rrs + fdf + res + brs
+			arbitrationBits = 36
+		}
+		arbitrationStuffingBits := (arbitrationBits - 1) / 4
+
+		// esi + dlc
+		controlBits := 5
+		// the dynamic stuffing ends with the data field
+		dataStuffingBits := (controlBits + dataBits - 1) / 4
+
+		// stuff count (gray code + parity) and crc
+		stuffCountBits := 4
+		crcBits := 17
+		if sizeByte > 16 {
+			crcBits = 21
+		}
+		// a fixed stuff bit is placed before the stuff count and then every 4 bits
+		fixedStuffingBits := 1 + (stuffCountBits+crcBits)/4
+
+		// the data phase ends with the crc delimiter
+		dataPhaseBits := controlBits + dataBits + dataStuffingBits + stuffCountBits + crcBits + fixedStuffingBits + 1
+
+		// slot ack + delim ack + eof
+		trailerBits := 9
+
+		nominalBits := float64(arbitrationBits + arbitrationStuffingBits + trailerBits)
+		if b.bitRateSwitch && b.dataBaudrate > 0 {
+			return nominalBits + float64(dataPhaseBits)*float64(b.baudrate)/float64(b.dataBaudrate)
+		}
+
+		return nominalBits + float64(dataPhaseBits)
+	}
+
+	// start of frame + id + rtr + ide + r0 + dlc
+	headerBits := 19
+	// crc + delim crc + slot ack + delim ack + eof
+	trailerBits := 25
+	// from bit stuffing section of wikipedia (https://en.wikipedia.org/wiki/CAN_bus#Bit_stuffing)
+	headerStuffingBits := 34
+
+	if isExtended {
+		// start of frame + base id + srr + ide + extended id + rtr + r1 + r0 + dlc
+		headerBits = 39
+		headerStuffingBits = 54
+	}
+
+	stuffingBits := (headerStuffingBits + dataBits - 1) / 4
+	return float64(dataBits + headerBits + trailerBits + stuffingBits)
+}
+
+// estimateMessageBits returns the number of bits needed to send the message
+// in the worst case scenario, including the frames of the transport protocol.
+func (b *Bus) estimateMessageBits(msg *Message) float64 {
+	switch msg.transport {
+	case MessageTransportJ1939TP:
+		if msg.sizeByte > 8 {
+			// a connection management frame followed by
+			// data transfer frames carrying 7 bytes each
+			packets := (msg.sizeByte + 6) / 7
+			return float64(1+packets) * b.estimateFrameBits(8, msg.canIDFormat)
+		}
+
+	case MessageTransportISOTP:
+		frameSize := isotp.ClassicFrameSize
+		if b.typ == BusTypeCANFD {
+			frameSize = isotp.MaxFDFrameSize
+		}
+
+		frames := isotp.FrameCount(msg.sizeByte, frameSize)
+		if frames > 1 {
+			// all the frames are padded and the receiver sends a flow control frame
+			return float64(frames)*b.estimateFrameBits(frameSize, msg.canIDFormat) + b.estimateFrameBits(isotp.ClassicFrameSize, msg.canIDFormat)
+		}
+	}
+
+	return b.estimateFrameBits(msg.sizeByte, msg.canIDFormat)
+}
+
+// estimateMaxFrameBits returns the number of bits of the longest frame
+// sent for the message in the worst case scenario.
+// It differs from the bits of the whole message only for the messages
+// split into more frames by the transport protocol.
+func (b *Bus) estimateMaxFrameBits(msg *Message) float64 {
+	switch msg.transport {
+	case MessageTransportJ1939TP:
+		if msg.sizeByte > 8 {
+			return b.estimateFrameBits(8, msg.canIDFormat)
+		}
+
+	case MessageTransportISOTP:
+		frameSize := isotp.ClassicFrameSize
+		if b.typ == BusTypeCANFD {
+			frameSize = isotp.MaxFDFrameSize
+		}
+
+		if isotp.FrameCount(msg.sizeByte, frameSize) > 1 {
+			return b.estimateFrameBits(frameSize, msg.canIDFormat)
+		}
+	}
+
+	return b.estimateFrameBits(msg.sizeByte, msg.canIDFormat)
+}
+
 // EstimateLoad estimates the load of the bus in the worst case scenario.
 // It returns the load percentage and a slice of [BusLoadMessage] structs sorted from the message
 // that causes the most load to the message that causes the least load.
 // The default cycle time is used when a message within the bus does not have one set.
 // If the bus does not have the baudrate set, it returns 0.
+// For CAN FD buses, the load is relative to the baudrate of the arbitration phase,
+// and the data phase is sent at the data baudrate when the bit rate switch is enabled.
 //
 // It returns an [ArgError] if the given default cycle time is invalid.
 func (b *Bus) EstimateLoad(defCycleTime int) (float64, []*BusLoadMessage, error) {
@@ -411,31 +722,17 @@ func (b *Bus) EstimateLoad(defCycleTime int) (float64, []*BusLoadMessage, error)
 		return 0, msgLoads, nil
 	}
 
-	var headerBits int
-	var trailerBits int
-	var headerStuffingBits int
-	switch b.typ {
-	case BusTypeCAN2A:
-		// start of frame + id + rtr + ide + r0 + dlc
-		headerBits = 19
-		// crc + delim crc + slot ack + delim ack + eof
-		trailerBits = 25
-		// from bit stuffing section of wikipedia (https://en.wikipedia.org/wiki/CAN_bus#Bit_stuffing)
-		headerStuffingBits = 34
-	}
-
 	totConsumedBitsPerSec := float64(0)
 	for tmpInt := range b.nodeInts.Values() {
 		for tmpMsg := range tmpInt.sentMessages.Values() {
-			stuffingBits := (headerStuffingBits + tmpMsg.sizeByte*8 - 1) / 4
-			msgBits := tmpMsg.sizeByte*8 + headerBits + trailerBits + stuffingBits
+			msgBits := b.estimateMessageBits(tmpMsg)
 
 			cycleTime := tmpMsg.cycleTime
 			if cycleTime == 0 {
 				cycleTime = defCycleTime
 			}
 
-			msgBitsPerSec := float64(msgBits) / float64(cycleTime) * 1000
+			msgBitsPerSec := msgBits / float64(cycleTime) * 1000
 			totConsumedBitsPerSec += msgBitsPerSec
 
 			msgLoads = append(msgLoads, &BusLoadMessage{
@@ -156,3 +156,135 @@ func Test_Bus_EstimateLoad(t *testing.T) {
 	assert.ErrorAs(err, &argErr)
 	assert.ErrorAs(err, &ErrIsNegative)
 }
+
+func Test_SizeByteToDLC(t *testing.T) {
+	assert := assert.New(t)
+
+	sizes := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 12, 16, 20, 24, 32, 48, 64}
+	for dlc, sizeByte := range sizes {
+		resDLC, err := SizeByteToDLC(sizeByte)
+		assert.NoError(err)
+		assert.Equal(dlc, resDLC)
+
+		resSizeByte, err := DLCToSizeByte(dlc)
+		assert.NoError(err)
+		assert.Equal(sizeByte, resSizeByte)
+	}
+
+	_, err := SizeByteToDLC(10)
+	assert.ErrorIs(err, ErrInvalidValue)
+
+	_, err = SizeByteToDLC(65)
+	assert.ErrorIs(err, ErrTooBig)
+
+	_, err = SizeByteToDLC(-1)
+	assert.ErrorIs(err, ErrIsNegative)
+
+	_, err = DLCToSizeByte(16)
+	assert.ErrorIs(err, ErrOutOfBounds)
+}
+
+func Test_Bus_CANFD(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCANFD)
+	bus.SetBaudrate(500_000)
+	bus.SetDataBaudrate(2_000_000)
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	// should add a message with a valid CAN FD size
+	msg := NewMessage("msg", 1, 64)
+	msg.SetCycleTime(10)
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	// should return an error because the size is not a valid CAN FD size
+	assert.ErrorIs(nodeInt.AddSentMessage(NewMessage("msg_10", 2, 10)), ErrInvalidValue)
+	assert.NoError(nodeInt.AddSentMessage(NewMessage("msg_12", 3, 12)))
+
+	// should not use the bit rate switch
+	_, msgLoads, err := bus.EstimateLoad(10)
+	assert.NoError(err)
+	assert.Equal(70900.0, msgLoads[0].BitsPerSec)
+
+	// should use the bit rate switch
+	bus.SetBitRateSwitch(true)
+	_, msgLoads, err = bus.EstimateLoad(10)
+	assert.NoError(err)
+	assert.Equal(19975.0, msgLoads[0].BitsPerSec)
+
+	// should return an error because a CAN 2.0A bus supports up to 8 bytes
+	canBus := NewBus("can_bus")
+	canNodeInt := NewNode("can_node", 2, 1).GetInterface(0)
+	assert.NoError(canBus.AddNodeInterface(canNodeInt))
+	assert.ErrorIs(canNodeInt.AddSentMessage(NewMessage("msg_12", 1, 12)), ErrTooBig)
+}
+
+func Test_Bus_Validate(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCANFD)
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+	assert.NoError(nodeInt.AddSentMessage(NewMessage("msg_fd", 1, 64)))
+
+	assert.NoError(bus.Validate())
+
+	// should return an error because a CAN 2.0B bus supports up to 8 bytes
+	bus.SetType(BusTypeCAN2B)
+	assert.ErrorIs(bus.Validate(), ErrTooBig)
+
+	bus.SetType(BusTypeCANFD)
+	nodeInt.RemoveAllSentMessages()
+
+	extMsg := NewMessage("msg_ext", 2, 8)
+	assert.NoError(extMsg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(nodeInt.AddSentMessage(extMsg))
+
+	// should return an error because a CAN 2.0A bus does not support extended CAN-IDs
+	bus.SetType(BusTypeCAN2A)
+	assert.ErrorIs(bus.Validate(), ErrInvalidType)
+
+	bus.SetType(BusTypeCAN2B)
+	assert.NoError(bus.Validate())
+}
+
+func Test_Bus_Validate_CANIDBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	stdMsg := NewMessage("std_msg", 1, 8)
+	assert.NoError(nodeInt.AddSentMessage(stdMsg))
+
+	// should return an error because the J1939 CAN-IDs do not fit the standard format
+	j1939Builder := NewJ1939CANIDBuilder("j1939_builder")
+	bus.SetCANIDBuilder(j1939Builder)
+	assert.ErrorIs(bus.Validate(), ErrOutOfBounds)
+
+	// should be valid because the message has a static CAN-ID
+	assert.NoError(stdMsg.SetStaticCANID(0x10))
+	assert.NoError(bus.Validate())
+
+	// should return an error because the message would use the builder
+	assert.ErrorIs(stdMsg.UpdateID(2), ErrOutOfBounds)
+
+	// should return an error because the message is added with the standard format
+	assert.ErrorIs(nodeInt.AddSentMessage(NewMessage("other_std_msg", 3, 8)), ErrOutOfBounds)
+
+	extMsg := NewMessage("ext_msg", 4, 8)
+	assert.NoError(extMsg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(nodeInt.AddSentMessage(extMsg))
+
+	// should return an error because the format is changed to standard
+	assert.ErrorIs(extMsg.SetCANIDFormat(CANIDFormatStandard), ErrOutOfBounds)
+	assert.Equal(CANIDFormatExtended, extMsg.CANIDFormat())
+}
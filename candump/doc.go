@@ -0,0 +1,10 @@
+// Package candump reads and writes the CAN logs produced by the candump tool
+// of the Linux can-utils, both in the log format (candump -l) and in the ASCII format
+// printed on the terminal (optionally with the absolute timestamps of candump -ta).
+//
+// Both formats support the CAN 2.0 frames with standard and extended CAN-IDs,
+// the remote frames, the error frames and the CAN FD frames
+// (written with the "##" separator in the log format).
+// The frames are represented by [acmelib.Frame], so they can be decoded
+// by [acmelib.Decoder.DecodeFrames].
+package candump
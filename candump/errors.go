@@ -0,0 +1,32 @@
+package candump
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidLine is returned when a line does not have the expected fields.
+	ErrInvalidLine = errors.New("invalid line")
+	// ErrInvalidTimestamp is returned when the timestamp of a line is malformed.
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	// ErrInvalidCANID is returned when the CAN-ID of a frame is malformed.
+	ErrInvalidCANID = errors.New("invalid CAN-ID")
+	// ErrInvalidData is returned when the data of a frame is malformed
+	// or it does not match the declared length.
+	ErrInvalidData = errors.New("invalid data")
+)
+
+// LineError is an error that occurred while parsing a line of a log.
+type LineError struct {
+	// Line is the number of the line, starting from 1.
+	Line int
+	// Err is the parsing error.
+	Err error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line error; line:%d : %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
@@ -0,0 +1,330 @@
+package candump
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+const (
+	// errorFlag is the flag of the CAN-ID of an error frame.
+	errorFlag = 0x20000000
+	// errorMask is the mask of the error class of an error frame.
+	errorMask = 0x1FFFFFFF
+
+	// fdFlagBRS is the bit rate switch flag of a CAN FD frame.
+	fdFlagBRS = 0x01
+	// fdFlagESI is the error state indicator flag of a CAN FD frame.
+	fdFlagESI = 0x02
+
+	// standardIDLen is the number of hex digits of a standard CAN-ID.
+	standardIDLen = 3
+	// extendedIDLen is the number of hex digits of an extended CAN-ID.
+	extendedIDLen = 8
+)
+
+// Reader reads the frames of a candump log.
+// Each line can be either in the log or in the ASCII format,
+// and the empty lines are skipped.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReader creates a new [Reader] that reads from the given reader.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		scanner: bufio.NewScanner(r),
+		line:    0,
+	}
+}
+
+// Read returns the next frame of the log.
+// It returns [io.EOF] when there are no more frames.
+//
+// It returns a [LineError] that wraps:
+//   - [ErrInvalidLine] if the line does not have the expected fields.
+//   - [ErrInvalidTimestamp] if the timestamp is malformed.
+//   - [ErrInvalidCANID] if the CAN-ID is malformed.
+//   - [ErrInvalidData] if the data is malformed.
+func (r *Reader) Read() (*acmelib.Frame, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := strings.TrimSpace(r.scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		frame, err := ParseLine(line)
+		if err != nil {
+			return nil, &LineError{Line: r.line, Err: err}
+		}
+
+		return frame, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// Frames returns an iterator over the frames of the log.
+// The lines that cannot be parsed are yielded as a [LineError]
+// and the iteration continues, while it stops on a read error.
+func (r *Reader) Frames() iter.Seq2[*acmelib.Frame, error] {
+	return func(yield func(*acmelib.Frame, error) bool) {
+		for {
+			frame, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+
+				lineErr := &LineError{}
+				if errors.As(err, &lineErr) {
+					continue
+				}
+				return
+			}
+
+			if !yield(frame, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ParseLine parses a single line of a candump log,
+// either in the log format or in the ASCII format.
+func ParseLine(line string) (*acmelib.Frame, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, ErrInvalidLine
+	}
+
+	frame := &acmelib.Frame{}
+
+	if strings.HasPrefix(fields[0], "(") {
+		timestamp, err := parseTimestamp(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		frame.Timestamp = timestamp
+		fields = fields[1:]
+	}
+
+	if len(fields) < 2 {
+		return nil, ErrInvalidLine
+	}
+
+	frame.Channel = fields[0]
+
+	// log format: <can_id>#<data>
+	if strings.Contains(fields[1], "#") {
+		if err := parseLogFrame(frame, fields[1]); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+
+	if err := parseASCIIFrame(frame, fields[1:]); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+// parseTimestamp parses a timestamp in the form (seconds.fraction).
+func parseTimestamp(field string) (time.Time, error) {
+	if !strings.HasPrefix(field, "(") || !strings.HasSuffix(field, ")") {
+		return time.Time{}, ErrInvalidTimestamp
+	}
+
+	secStr, fracStr, _ := strings.Cut(field[1:len(field)-1], ".")
+
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidTimestamp
+	}
+
+	if len(fracStr) > 9 {
+		return time.Time{}, ErrInvalidTimestamp
+	}
+
+	nsec := int64(0)
+	if len(fracStr) > 0 {
+		frac, err := strconv.ParseInt(fracStr, 10, 64)
+		if err != nil {
+			return time.Time{}, ErrInvalidTimestamp
+		}
+
+		nsec = frac
+		for range 9 - len(fracStr) {
+			nsec *= 10
+		}
+	}
+
+	return time.Unix(sec, nsec), nil
+}
+
+// parseCANID parses a CAN-ID made of 3 (standard) or 8 (extended) hex digits.
+// The extended CAN-IDs with the error flag are error frames.
+func parseCANID(frame *acmelib.Frame, idStr string) error {
+	if len(idStr) != standardIDLen && len(idStr) != extendedIDLen {
+		return ErrInvalidCANID
+	}
+
+	id, err := strconv.ParseUint(idStr, 16, 32)
+	if err != nil {
+		return ErrInvalidCANID
+	}
+
+	if len(idStr) == standardIDLen {
+		if id > 0x7FF {
+			return ErrInvalidCANID
+		}
+
+		frame.CANID = acmelib.CANID(id)
+		return nil
+	}
+
+	if id&errorFlag != 0 {
+		frame.IsError = true
+		frame.CANID = acmelib.CANID(id & errorMask)
+		return nil
+	}
+
+	if id > errorMask {
+		return ErrInvalidCANID
+	}
+
+	frame.IsExtended = true
+	frame.CANID = acmelib.CANID(id)
+
+	return nil
+}
+
+// parseLogFrame parses a frame in the log format:
+//   - <can_id>#<data> for CAN 2.0 frames.
+//   - <can_id>#R[<len>] for remote frames.
+//   - <can_id>##<flags><data> for CAN FD frames.
+func parseLogFrame(frame *acmelib.Frame, field string) error {
+	idStr, dataStr, _ := strings.Cut(field, "#")
+
+	if err := parseCANID(frame, idStr); err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasPrefix(dataStr, "#"):
+		if len(dataStr) < 2 {
+			return ErrInvalidData
+		}
+
+		flags, err := strconv.ParseUint(dataStr[1:2], 16, 8)
+		if err != nil {
+			return ErrInvalidData
+		}
+
+		frame.IsFD = true
+		frame.BitRateSwitch = flags&fdFlagBRS != 0
+		frame.ErrorStateIndicator = flags&fdFlagESI != 0
+		dataStr = dataStr[2:]
+
+	case strings.HasPrefix(dataStr, "R"):
+		frame.IsRemote = true
+
+		if len(dataStr) > 1 {
+			length, err := strconv.Atoi(dataStr[1:])
+			if err != nil || length > 8 {
+				return ErrInvalidData
+			}
+			frame.RemoteLength = length
+		}
+
+		return nil
+	}
+
+	// the bytes may be separated by dots
+	data, err := hex.DecodeString(strings.ReplaceAll(dataStr, ".", ""))
+	if err != nil {
+		return ErrInvalidData
+	}
+
+	if (!frame.IsFD && len(data) > 8) || len(data) > 64 {
+		return ErrInvalidData
+	}
+
+	frame.Data = data
+
+	return nil
+}
+
+// parseASCIIFrame parses a frame in the ASCII format:
+// <can_id> [<len>] <bytes...>, where the length has 2 digits for CAN FD frames.
+// The remote frames are followed by "remote request" instead of the bytes.
+func parseASCIIFrame(frame *acmelib.Frame, fields []string) error {
+	if len(fields) < 2 {
+		return ErrInvalidLine
+	}
+
+	if err := parseCANID(frame, fields[0]); err != nil {
+		return err
+	}
+
+	lenStr := fields[1]
+	if !strings.HasPrefix(lenStr, "[") || !strings.HasSuffix(lenStr, "]") {
+		return ErrInvalidLine
+	}
+	lenStr = lenStr[1 : len(lenStr)-1]
+
+	length, err := strconv.Atoi(lenStr)
+	if err != nil || length < 0 || length > 64 {
+		return ErrInvalidData
+	}
+
+	frame.IsFD = len(lenStr) == 2
+
+	fields = fields[2:]
+
+	if len(fields) >= 2 && fields[0] == "remote" && fields[1] == "request" {
+		frame.IsRemote = true
+		frame.RemoteLength = length
+		return nil
+	}
+
+	if len(fields) < length {
+		return ErrInvalidData
+	}
+
+	data := make([]byte, length)
+	for idx := range length {
+		b, err := strconv.ParseUint(fields[idx], 16, 8)
+		if err != nil || len(fields[idx]) != 2 {
+			return ErrInvalidData
+		}
+		data[idx] = byte(b)
+	}
+
+	if !frame.IsFD && length > 8 {
+		return ErrInvalidData
+	}
+
+	frame.Data = data
+
+	return nil
+}
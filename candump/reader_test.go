@@ -0,0 +1,150 @@
+package candump
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func readFrames(assert *assert.Assertions, path string) []*acmelib.Frame {
+	file, err := os.Open(path)
+	assert.NoError(err)
+	defer file.Close()
+
+	frames := []*acmelib.Frame{}
+	for frame, err := range NewReader(file).Frames() {
+		assert.NoError(err)
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+func Test_Reader_Read(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, path := range []string{"testdata/session.log", "testdata/session.txt"} {
+		frames := readFrames(assert, path)
+		assert.Len(frames, 7)
+
+		// should parse a standard frame
+		assert.Equal(time.Unix(1436509052, 249713000), frames[0].Timestamp)
+		assert.Equal("can0", frames[0].Channel)
+		assert.Equal(acmelib.CANID(0x123), frames[0].CANID)
+		assert.False(frames[0].IsExtended)
+		assert.Equal([]byte{0xE8, 0x03, 0x50, 0x0A}, frames[0].Data)
+
+		// should parse an extended frame
+		assert.Equal(acmelib.CANID(0x18FF1234), frames[2].CANID)
+		assert.True(frames[2].IsExtended)
+		assert.Len(frames[2].Data, 8)
+
+		// should parse a CAN FD frame
+		assert.Equal("can1", frames[3].Channel)
+		assert.True(frames[3].IsFD)
+		assert.Len(frames[3].Data, 16)
+
+		// should parse a remote frame
+		assert.True(frames[4].IsRemote)
+		assert.Empty(frames[4].Data)
+
+		// should parse an error frame
+		assert.True(frames[5].IsError)
+		assert.Equal(acmelib.CANID(0x04), frames[5].CANID)
+	}
+
+	// should parse the flags of a CAN FD frame only in the log format
+	frames := readFrames(assert, "testdata/session.log")
+	assert.True(frames[3].BitRateSwitch)
+	assert.False(frames[3].ErrorStateIndicator)
+
+	// should return io.EOF at the end
+	r := NewReader(strings.NewReader("\n(1.5) can0 123#\n\n"))
+	frame, err := r.Read()
+	assert.NoError(err)
+	assert.Equal(time.Unix(1, 500_000_000), frame.Timestamp)
+	assert.Empty(frame.Data)
+	_, err = r.Read()
+	assert.ErrorIs(err, io.EOF)
+}
+
+func Test_Reader_Frames(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/invalid.log")
+	assert.NoError(err)
+	defer file.Close()
+
+	// should continue after the invalid lines
+	validCount := 0
+	errs := []error{}
+	for frame, err := range NewReader(file).Frames() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		assert.NotNil(frame)
+		validCount++
+	}
+
+	assert.Equal(2, validCount)
+	assert.Len(errs, 4)
+
+	expectedErrs := []error{ErrInvalidCANID, ErrInvalidTimestamp, ErrInvalidData, ErrInvalidLine}
+	expectedLines := []int{2, 3, 4, 5}
+	for idx, err := range errs {
+		lineErr := &LineError{}
+		assert.ErrorAs(err, &lineErr)
+		assert.Equal(expectedLines[idx], lineErr.Line)
+		assert.ErrorIs(err, expectedErrs[idx])
+	}
+}
+
+func Test_Reader_DecodeFrames(t *testing.T) {
+	assert := assert.New(t)
+
+	net := acmelib.NewNetwork("network")
+	bus := acmelib.NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := acmelib.NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	uint16Type, err := acmelib.NewIntegerSignalType("uint16_t", 16, false)
+	assert.NoError(err)
+
+	msg := acmelib.NewMessage("engine", 0x123, 4)
+	assert.NoError(nodeInt.AddSentMessage(msg))
+	rpmSig, err := acmelib.NewStandardSignal("rpm", uint16Type)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(rpmSig, 0))
+	tempSig, err := acmelib.NewStandardSignal("temperature", uint16Type)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(tempSig, 16))
+
+	dec, err := net.NewDecoder()
+	assert.NoError(err)
+
+	file, err := os.Open("testdata/session.log")
+	assert.NoError(err)
+	defer file.Close()
+
+	// should decode only the frames of the engine message
+	expectedRPMs := []uint64{1000, 2000, 3000}
+	expectedTemps := []uint64{2640, 2641, 2642}
+	idx := 0
+	for frameDec, err := range dec.DecodeFrames(NewReader(file).Frames()) {
+		assert.NoError(err)
+		assert.Equal("engine", frameDec.Message.Name())
+		assert.Len(frameDec.Signals, 2)
+		assert.Equal(expectedRPMs[idx], frameDec.Signals[0].ValueAsUint())
+		assert.Equal(expectedTemps[idx], frameDec.Signals[1].ValueAsUint())
+		idx++
+	}
+	assert.Equal(3, idx)
+}
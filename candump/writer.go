@@ -0,0 +1,170 @@
+package candump
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// Format is the format of the lines written by a [Writer].
+type Format int
+
+const (
+	// FormatLog defines the log format of candump -l:
+	// (1436509052.249713) can0 123#DEADBEEF
+	FormatLog Format = iota
+	// FormatASCII defines the ASCII format printed by candump -ta:
+	// (1436509052.249713)  can0  123   [4]  DE AD BE EF
+	FormatASCII
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatLog:
+		return "log"
+	case FormatASCII:
+		return "ascii"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultChannel is the channel written for the frames without a channel.
+const DefaultChannel = "can0"
+
+var _ acmelib.FrameSink = (*Writer)(nil)
+
+// Writer writes frames as lines of a candump log.
+// It implements [acmelib.FrameSink].
+// The output is buffered, so [Writer.Flush] must be called at the end.
+type Writer struct {
+	w      *bufio.Writer
+	format Format
+}
+
+// NewWriter creates a new [Writer] that writes to the given writer
+// with the given [Format].
+func NewWriter(w io.Writer, format Format) *Writer {
+	return &Writer{
+		w:      bufio.NewWriter(w),
+		format: format,
+	}
+}
+
+// WriteFrame writes the given frame as a line.
+// In the ASCII format, the timestamp is omitted if it is zero,
+// while the log format always requires it.
+func (w *Writer) WriteFrame(frame *acmelib.Frame) error {
+	_, err := w.w.WriteString(FormatLine(frame, w.format) + "\n")
+	return err
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// FormatLine returns the line that represents the given frame
+// in the given [Format], without the trailing new line.
+func FormatLine(frame *acmelib.Frame, format Format) string {
+	channel := frame.Channel
+	if len(channel) == 0 {
+		channel = DefaultChannel
+	}
+
+	if format == FormatASCII {
+		return formatASCIILine(frame, channel)
+	}
+
+	return fmt.Sprintf("%s %s %s", formatTimestamp(frame), channel, formatLogFrame(frame))
+}
+
+func formatTimestamp(frame *acmelib.Frame) string {
+	ts := frame.Timestamp
+	if ts.IsZero() {
+		return "(0.000000)"
+	}
+	return fmt.Sprintf("(%d.%06d)", ts.Unix(), ts.Nanosecond()/1000)
+}
+
+func formatCANID(frame *acmelib.Frame) string {
+	switch {
+	case frame.IsError:
+		return fmt.Sprintf("%08X", uint32(frame.CANID)&errorMask|errorFlag)
+	case frame.IsExtended:
+		return fmt.Sprintf("%08X", uint32(frame.CANID)&errorMask)
+	default:
+		return fmt.Sprintf("%03X", uint32(frame.CANID)&0x7FF)
+	}
+}
+
+func formatLogFrame(frame *acmelib.Frame) string {
+	canID := formatCANID(frame)
+	data := strings.ToUpper(hex.EncodeToString(frame.Data))
+
+	if frame.IsFD {
+		flags := 0
+		if frame.BitRateSwitch {
+			flags |= fdFlagBRS
+		}
+		if frame.ErrorStateIndicator {
+			flags |= fdFlagESI
+		}
+		return fmt.Sprintf("%s##%X%s", canID, flags, data)
+	}
+
+	if frame.IsRemote {
+		if frame.RemoteLength > 0 {
+			return fmt.Sprintf("%s#R%d", canID, frame.RemoteLength)
+		}
+		return canID + "#R"
+	}
+
+	return canID + "#" + data
+}
+
+func formatASCIILine(frame *acmelib.Frame, channel string) string {
+	b := new(strings.Builder)
+
+	if !frame.Timestamp.IsZero() {
+		b.WriteString(formatTimestamp(frame))
+		b.WriteString("  ")
+	}
+
+	b.WriteString(channel)
+	b.WriteString("  ")
+	b.WriteString(formatCANID(frame))
+
+	length := len(frame.Data)
+	if frame.IsRemote {
+		length = frame.RemoteLength
+	}
+
+	if frame.IsFD {
+		fmt.Fprintf(b, "  [%02d]", length)
+	} else {
+		fmt.Fprintf(b, "   [%d]", length)
+	}
+
+	if frame.IsRemote {
+		b.WriteString("  remote request")
+		return b.String()
+	}
+
+	if length > 0 {
+		b.WriteString(" ")
+	}
+	for _, d := range frame.Data {
+		fmt.Fprintf(b, " %02X", d)
+	}
+
+	if frame.IsError {
+		b.WriteString("   ERRORFRAME")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package candump
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Writer_WriteFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	// should write the same lines that have been read
+	testdata := map[Format]string{
+		FormatLog:   "testdata/session.log",
+		FormatASCII: "testdata/session.txt",
+	}
+
+	for format, path := range testdata {
+		expected, err := os.ReadFile(path)
+		assert.NoError(err)
+
+		buf := new(bytes.Buffer)
+		w := NewWriter(buf, format)
+		for _, frame := range readFrames(assert, path) {
+			assert.NoError(w.WriteFrame(frame))
+		}
+		assert.NoError(w.Flush())
+
+		assert.Equal(string(expected), buf.String())
+	}
+}
+
+func Test_FormatLine(t *testing.T) {
+	assert := assert.New(t)
+
+	frame := &acmelib.Frame{
+		Timestamp:           time.Unix(10, 1000),
+		CANID:               0x1234567,
+		IsExtended:          true,
+		IsFD:                true,
+		ErrorStateIndicator: true,
+		Data:                []byte{0x01, 0x02},
+	}
+
+	assert.Equal("(10.000001) can0 01234567##20102", FormatLine(frame, FormatLog))
+	assert.Equal("(10.000001)  can0  01234567  [02]  01 02", FormatLine(frame, FormatASCII))
+
+	// should omit the timestamp in the ASCII format
+	frame = &acmelib.Frame{
+		Channel:      "vcan0",
+		CANID:        0x10,
+		IsRemote:     true,
+		RemoteLength: 2,
+	}
+
+	assert.Equal("(0.000000) vcan0 010#R2", FormatLine(frame, FormatLog))
+	assert.Equal("vcan0  010   [2]  remote request", FormatLine(frame, FormatASCII))
+}
@@ -10,10 +10,65 @@ func newDefaultCANIDBuilder() *CANIDBuilder {
 	return NewCANIDBuilder("default_CANID_builder").UseMessageID(0, 11).UseCAN2A()
 }
 
+// NewJ1939CANIDBuilder creates a new [CANIDBuilder] with the given name
+// that calculates the CAN-IDs following the SAE J1939 layout:
+// priority (bits 26-28), extended data page (bit 25), data page (bit 24),
+// PDU format (bits 16-23), PDU specific (bits 8-15) and source address (bits 0-7).
+// The priority is taken from the [MessagePriority] (from 0 to 7),
+// the PGN from the [MessageID] (see [J1939PGN.ToMessageID]),
+// and the source address from the [NodeID].
+func NewJ1939CANIDBuilder(name string) *CANIDBuilder {
+	return NewCANIDBuilder(name).UseJ1939Priority().UseJ1939PGN().UseJ1939SourceAddress().UseCAN2B()
+}
+
 // CANID is the CAN-ID of a [Message] within a [Bus].
 // Every message should have a different CAN-ID.
 type CANID uint32
 
+// CANIDFormat is the format of a [CANID].
+type CANIDFormat int
+
+const (
+	// CANIDFormatStandard defines a standard 11 bits CAN-ID (CAN 2.0A).
+	CANIDFormatStandard CANIDFormat = iota
+	// CANIDFormatExtended defines an extended 29 bits CAN-ID (CAN 2.0B).
+	CANIDFormatExtended
+)
+
+func (f CANIDFormat) String() string {
+	switch f {
+	case CANIDFormatStandard:
+		return "standard"
+	case CANIDFormatExtended:
+		return "extended"
+	default:
+		return "unknown"
+	}
+}
+
+// BitSize returns the number of bits of a CAN-ID with the format.
+func (f CANIDFormat) BitSize() int {
+	if f == CANIDFormatExtended {
+		return 29
+	}
+	return 11
+}
+
+// MaxCANID returns the biggest CAN-ID that can be represented with the format.
+func (f CANIDFormat) MaxCANID() CANID {
+	return CANID(1<<f.BitSize() - 1)
+}
+
+// verifyCANID checks if the given CAN-ID fits the format.
+//
+// It returns a [CANIDError] if the CAN-ID is too big.
+func (f CANIDFormat) verifyCANID(canID CANID) error {
+	if canID > f.MaxCANID() {
+		return newCANIDError(canID, ErrOutOfBounds)
+	}
+	return nil
+}
+
 // CANIDBuilderOpKind is the kind of an operation
 // perfomed by the [CANIDBuilder].
 type CANIDBuilderOpKind int
@@ -216,6 +271,60 @@ func (b *CANIDBuilder) UseCAN2A() *CANIDBuilder {
 	return b
 }
 
+// UseCAN2B adds a bit mask from 0 with a length of 29,
+// which makes the calculated CAN-ID conformed to the CAN 2.0B.
+func (b *CANIDBuilder) UseCAN2B() *CANIDBuilder {
+	b.operations = append(b.operations, newCANIDBuilderOp(CANIDBuilderOpKindBitMask, 0, 29))
+	return b
+}
+
+// UseJ1939Priority adds an operation that involves the message priority
+// placed as the 3 bits priority of a J1939 CAN-ID (bits 26-28).
+func (b *CANIDBuilder) UseJ1939Priority() *CANIDBuilder {
+	b.operations = append(b.operations, newCANIDBuilderOp(CANIDBuilderOpKindMessagePriority, 26, 3))
+	return b
+}
+
+// UseJ1939PGN adds an operation that involves the message id
+// placed as the extended data page, data page, PDU format and PDU specific
+// of a J1939 CAN-ID (bits 8-25).
+func (b *CANIDBuilder) UseJ1939PGN() *CANIDBuilder {
+	b.operations = append(b.operations, newCANIDBuilderOp(CANIDBuilderOpKindMessageID, 8, 18))
+	return b
+}
+
+// UseJ1939SourceAddress adds an operation that involves the node id
+// placed as the source address of a J1939 CAN-ID (bits 0-7).
+func (b *CANIDBuilder) UseJ1939SourceAddress() *CANIDBuilder {
+	b.operations = append(b.operations, newCANIDBuilderOp(CANIDBuilderOpKindNodeID, 0, 8))
+	return b
+}
+
+// isJ1939 reports whether the [CANIDBuilder] places the message id
+// as the PGN of a J1939 CAN-ID (see [CANIDBuilder.UseJ1939PGN]).
+func (b *CANIDBuilder) isJ1939() bool {
+	for _, op := range b.operations {
+		if op.kind == CANIDBuilderOpKindMessageID && op.from == 8 && op.len == 18 {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyFormat checks if all the CAN-IDs calculated by the [CANIDBuilder]
+// fit the width of the given [CANIDFormat].
+// It takes into account the bit mask operations,
+// so a builder that ends with [CANIDBuilder.UseCAN2A] is always valid
+// for the standard format.
+//
+// It returns a [CANIDError] that holds the widest CAN-ID
+// that can be calculated if it does not fit the format.
+func (b *CANIDBuilder) VerifyFormat(format CANIDFormat) error {
+	// setting all the bits of the inputs gives the widest CAN-ID
+	maxCANID := b.Calculate(MessagePriority(0xFFFFFFFF), MessageID(0xFFFFFFFF), NodeID(0xFFFFFFFF))
+	return format.verifyCANID(maxCANID)
+}
+
 // UseBitMask adds a bit mask operation from the given index and length.
 func (b *CANIDBuilder) UseBitMask(from, len int) *CANIDBuilder {
 	b.operations = append(b.operations, newCANIDBuilderOp(CANIDBuilderOpKindBitMask, from, len))
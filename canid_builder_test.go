@@ -99,3 +99,28 @@ func Test_CANIDBuilder_InsertOperation(t *testing.T) {
 	assert.Error(b.InsertOperation(CANIDBuilderOpKindBitMask, 0, 1, -1))
 	assert.Error(b.InsertOperation(CANIDBuilderOpKindBitMask, 0, 1, 4))
 }
+
+func Test_CANIDBuilder_VerifyFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	// should be valid for both formats because of the CAN 2.0A mask
+	assert.NoError(newDefaultCANIDBuilder().VerifyFormat(CANIDFormatStandard))
+	assert.NoError(newDefaultCANIDBuilder().VerifyFormat(CANIDFormatExtended))
+
+	// should return an error because the node id exceeds 11 bits
+	b := NewCANIDBuilder("builder").UseMessagePriority(27).UseMessageID(8, 19).UseNodeID(0, 8)
+	err := b.VerifyFormat(CANIDFormatStandard)
+	assert.ErrorIs(err, ErrOutOfBounds)
+	var canIDErr *CANIDError
+	assert.ErrorAs(err, &canIDErr)
+	assert.Equal(CANID(0x1FFFFFFF), canIDErr.CANID)
+	assert.NoError(b.VerifyFormat(CANIDFormatExtended))
+
+	// should return an error because the priority exceeds 29 bits
+	b.InsertOperation(CANIDBuilderOpKindMessagePriority, 28, 2, 0)
+	assert.ErrorIs(b.VerifyFormat(CANIDFormatExtended), ErrOutOfBounds)
+
+	// should be valid because of the CAN 2.0B mask
+	b.UseCAN2B()
+	assert.NoError(b.VerifyFormat(CANIDFormatExtended))
+}
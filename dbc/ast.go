@@ -217,6 +217,20 @@ type Message struct {
 	Signals     []*Signal
 }
 
+// MessageExtendedIDFlag is the bit of the message ID
+// that marks the message as extended (29 bits CAN-ID).
+const MessageExtendedIDFlag uint32 = 1 << 31
+
+// IsExtended returns whether the message ID has the extended flag set.
+func (m *Message) IsExtended() bool {
+	return m.ID&MessageExtendedIDFlag != 0
+}
+
+// CANID returns the CAN-ID of the message, without the extended flag.
+func (m *Message) CANID() uint32 {
+	return m.ID &^ MessageExtendedIDFlag
+}
+
 // SignalByteOrder defines the order of a [Signal].
 type SignalByteOrder uint
 
@@ -345,7 +359,7 @@ const (
 //
 // Signals with value types 'float' and 'double' have additional entries in the signal_valtype_list section.
 //
-// signal_extended_value_type_list = 'SIG_VALTYPE_' message_id signal_name signal_extended_value_type ';' ;
+// signal_extended_value_type_list = 'SIG_VALTYPE_' message_id signal_name [':'] signal_extended_value_type ';' ;
 //
 // signal_extended_value_type = '0' | '1' | '2' | '3' ;
 // (* 0=signed or unsigned integer, 1=32-bit IEEE-float, 2=64-bit IEEE-double *)
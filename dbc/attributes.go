@@ -64,3 +64,68 @@ var (
 		"IfActiveWithRepetition",
 	}
 )
+
+var (
+	// MsgFrameFormatName is the name of the well known attribute for message frame format.
+	MsgFrameFormatName = "VFrameFormat"
+	// MsgFrameFormatValues are the value of the well known attribute for message frame format.
+	// The values between the CAN 2.0 and the CAN FD ones are reserved,
+	// except for the J1939 parameter group one.
+	MsgFrameFormatValues = []string{
+		"StandardCAN",
+		"ExtendedCAN",
+		"reserved",
+		"J1939PG",
+		"reserved",
+		"reserved",
+		"reserved",
+		"reserved",
+		"reserved",
+		"reserved",
+		"reserved",
+		"reserved",
+		"reserved",
+		"reserved",
+		"StandardCAN_FD",
+		"ExtendedCAN_FD",
+	}
+)
+
+var (
+	// MsgBitRateSwitchName is the name of the well known attribute for the
+	// bit rate switch of CAN FD messages.
+	MsgBitRateSwitchName = "CANFD_BRS"
+	// MsgBitRateSwitchValues are the value of the well known attribute for the
+	// bit rate switch of CAN FD messages.
+	MsgBitRateSwitchValues = []string{"0", "1"}
+)
+
+const (
+	// BusTypeName is the name of the well known attribute for the bus type.
+	BusTypeName = "BusType"
+	// BusTypeCAN is the value of the well known attribute for a CAN bus.
+	BusTypeCAN = "CAN"
+	// BusTypeCANFD is the value of the well known attribute for a CAN FD bus.
+	BusTypeCANFD = "CAN FD"
+)
+
+const (
+	// BusBaudrateName is the name of the well known attribute for the bus baudrate.
+	BusBaudrateName = "Baudrate"
+	// BusBaudrateMin is the min value of the well known attribute for the bus baudrate.
+	BusBaudrateMin = 0
+	// BusBaudrateMax is the max value of the well known attribute for the bus baudrate.
+	BusBaudrateMax = 1000000
+)
+
+const (
+	// BusDataBaudrateName is the name of the well known attribute for the
+	// data phase baudrate of CAN FD buses.
+	BusDataBaudrateName = "BaudrateCANFD"
+	// BusDataBaudrateMin is the min value of the well known attribute for the
+	// data phase baudrate of CAN FD buses.
+	BusDataBaudrateMin = 0
+	// BusDataBaudrateMax is the max value of the well known attribute for the
+	// data phase baudrate of CAN FD buses.
+	BusDataBaudrateMax = 16000000
+)
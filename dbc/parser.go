@@ -1636,7 +1636,12 @@ func (p *parser) parseSignalExtValueType() (*SignalExtValueType, error) {
 	}
 	valType.SignalName = sigName
 
+	// The colon is optional since not all the tools write it
 	t := p.scan()
+	if t.isPunct(punctColon) {
+		t = p.scan()
+	}
+
 	if !t.isNumber() {
 		return nil, p.errorf("expected signal extended value type")
 	}
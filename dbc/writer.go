@@ -435,7 +435,7 @@ func (w *writer) writeSignalGroup(sigGroup *SignalGroup) {
 }
 
 func (w *writer) writeSignalExtValueType(sigExtValTyp *SignalExtValueType) {
-	w.print("%s %s %s ",
+	w.print("%s %s %s : ",
 		getKeyword(keywordSignalValueType),
 		w.formatUint(sigExtValTyp.MessageID),
 		sigExtValTyp.SignalName,
@@ -60,6 +60,7 @@ func ExportDBCBus(w io.Writer, bus *Bus) {
 
 type dbcExporter struct {
 	dbcFile *dbc.File
+	bus     *Bus
 
 	attNames     map[string]bool
 	nodeAttNames map[string]bool
@@ -243,6 +244,8 @@ func (e *dbcExporter) exportAttribute(att Attribute, dbcAtt *dbc.Attribute) {
 }
 
 func (e *dbcExporter) exportBus(bus *Bus) *dbc.File {
+	e.bus = bus
+
 	if bus.desc != "" {
 		e.addDBCComment(&dbc.Comment{
 			Kind: dbc.CommentGeneral,
@@ -250,7 +253,19 @@ func (e *dbcExporter) exportBus(bus *Bus) *dbc.File {
 		})
 	}
 
-	for _, attVal := range bus.AttributeAssignments() {
+	// Handle bus attributes
+	attAssignments := bus.AttributeAssignments()
+	if bus.baudrate != 0 {
+		attAssignments = append(attAssignments, newAttributeAssignment(busBaudrateAtt, bus, bus.baudrate))
+	}
+	if bus.typ == BusTypeCANFD {
+		attAssignments = append(attAssignments, newAttributeAssignment(busTypeAtt, bus, dbc.BusTypeCANFD))
+
+		if bus.dataBaudrate != 0 {
+			attAssignments = append(attAssignments, newAttributeAssignment(busDataBaudrateAtt, bus, bus.dataBaudrate))
+		}
+	}
+	for _, attVal := range attAssignments {
 		dbcAttVal := new(dbc.AttributeValue)
 		e.exportAttributeAssignment(attVal, dbc.AttributeGeneral, dbcAttVal)
 		e.dbcFile.AttributeValues = append(e.dbcFile.AttributeValues, dbcAttVal)
@@ -300,6 +315,9 @@ func (e *dbcExporter) exportMessage(msg *Message) {
 	dbcMsg := new(dbc.Message)
 
 	msgID := uint32(msg.GetCANID())
+	if msg.canIDFormat == CANIDFormatExtended {
+		msgID |= dbc.MessageExtendedIDFlag
+	}
 	dbcMsg.ID = msgID
 
 	// Handle message description
@@ -325,6 +343,23 @@ func (e *dbcExporter) exportMessage(msg *Message) {
 	if msg.sendType != MessageSendTypeUnset {
 		attAssignments = append(attAssignments, newAttributeAssignment(msgSendTypeAtt, msg, messageSendTypeToDBC(msg.sendType)))
 	}
+	if e.bus.typ == BusTypeCANFD {
+		brs := dbc.MsgBitRateSwitchValues[0]
+		if e.bus.bitRateSwitch {
+			brs = dbc.MsgBitRateSwitchValues[1]
+		}
+		attAssignments = append(attAssignments, newAttributeAssignment(msgBitRateSwitchAtt, msg, brs))
+
+		if msg.canIDFormat == CANIDFormatExtended {
+			e.exportMessageFrameFormat(msgID, "ExtendedCAN_FD")
+		} else {
+			e.exportMessageFrameFormat(msgID, "StandardCAN_FD")
+		}
+	} else if msg.transport == MessageTransportJ1939TP {
+		e.exportMessageFrameFormat(msgID, "J1939PG")
+	} else if msg.canIDFormat == CANIDFormatExtended {
+		e.exportMessageFrameFormat(msgID, "ExtendedCAN")
+	}
 	for _, attAss := range attAssignments {
 		dbcAttVal := new(dbc.AttributeValue)
 		dbcAttVal.MessageID = dbcMsg.ID
@@ -352,6 +387,38 @@ func (e *dbcExporter) exportMessage(msg *Message) {
 	e.dbcFile.Messages = append(e.dbcFile.Messages, dbcMsg)
 }
 
+// exportMessageFrameFormat exports the frame format attribute of a message.
+// It is not handled as a normal enum attribute because the values of the
+// attribute contain many reserved (duplicated) values.
+func (e *dbcExporter) exportMessageFrameFormat(dbcMsgID uint32, frameFormat string) {
+	attName := dbc.MsgFrameFormatName
+
+	if _, ok := e.msgAttNames[attName]; !ok {
+		e.msgAttNames[attName] = true
+
+		e.dbcFile.Attributes = append(e.dbcFile.Attributes, &dbc.Attribute{
+			Name:       attName,
+			Kind:       dbc.AttributeMessage,
+			Type:       dbc.AttributeEnum,
+			EnumValues: dbc.MsgFrameFormatValues,
+		})
+
+		e.dbcFile.AttributeDefaults = append(e.dbcFile.AttributeDefaults, &dbc.AttributeDefault{
+			AttributeName: attName,
+			Type:          dbc.AttributeDefaultString,
+			ValueString:   dbc.MsgFrameFormatValues[0],
+		})
+	}
+
+	e.dbcFile.AttributeValues = append(e.dbcFile.AttributeValues, &dbc.AttributeValue{
+		AttributeName: attName,
+		AttributeKind: dbc.AttributeMessage,
+		MessageID:     dbcMsgID,
+		Type:          dbc.AttributeValueInt,
+		ValueInt:      slices.Index(dbc.MsgFrameFormatValues, frameFormat),
+	})
+}
+
 func (e *dbcExporter) getSignalStartBit(sig Signal) (uint32, dbc.SignalByteOrder) {
 	startPos := sig.StartPos()
 
@@ -403,7 +470,7 @@ func (e *dbcExporter) exportSignal(sig Signal, dbcSig *dbc.Signal, dbcMsg *dbc.M
 		if err != nil {
 			panic(err)
 		}
-		e.exportStandardSignal(stdSig, dbcSig)
+		e.exportStandardSignal(stdSig, dbcMsg.ID, dbcSig)
 
 	case SignalKindEnum:
 		enumSig, err := sig.ToEnum()
@@ -423,13 +490,27 @@ func (e *dbcExporter) exportSignal(sig Signal, dbcSig *dbc.Signal, dbcMsg *dbc.M
 	dbcMsg.Signals = append(dbcMsg.Signals, dbcSig)
 }
 
-func (e *dbcExporter) exportStandardSignal(stdSig *StandardSignal, dbcSig *dbc.Signal) {
+func (e *dbcExporter) exportStandardSignal(stdSig *StandardSignal, dbcMsgID uint32, dbcSig *dbc.Signal) {
 	if stdSig.typ.signed {
 		dbcSig.ValueType = dbc.SignalSigned
 	} else {
 		dbcSig.ValueType = dbc.SignalUnsigned
 	}
 
+	// IEEE floats need the extended value type (SIG_VALTYPE_)
+	if stdSig.typ.kind == SignalTypeKindFloat {
+		extValType := dbc.SignalExtValueTypeFloat
+		if stdSig.typ.size == 64 {
+			extValType = dbc.SignalExtValueTypeDouble
+		}
+
+		e.dbcFile.SignalExtValueTypes = append(e.dbcFile.SignalExtValueTypes, &dbc.SignalExtValueType{
+			MessageID:    dbcMsgID,
+			SignalName:   dbcSig.Name,
+			ExtValueType: extValType,
+		})
+	}
+
 	dbcSig.Min = stdSig.typ.min
 	dbcSig.Max = stdSig.typ.max
 	dbcSig.Offset = stdSig.typ.offset
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"slices"
+	"strings"
 
 	"github.com/squadracorsepolito/acmelib/dbc"
 	"github.com/squadracorsepolito/acmelib/internal/collection"
@@ -46,6 +47,11 @@ type dbcImporter struct {
 	signalEnums        map[string]*SignalEnum
 
 	dbcExtMuxes map[string]*dbc.ExtendedMux
+
+	dbcSigExtValTypes map[string]dbc.SignalExtValueTypeType
+
+	defFrameFormat  string
+	msgFrameFormats map[MessageID]string
 }
 
 func newDBCImporter() *dbcImporter {
@@ -69,6 +75,11 @@ func newDBCImporter() *dbcImporter {
 		signalEnums:        make(map[string]*SignalEnum),
 
 		dbcExtMuxes: make(map[string]*dbc.ExtendedMux),
+
+		dbcSigExtValTypes: make(map[string]dbc.SignalExtValueTypeType),
+
+		defFrameFormat:  "",
+		msgFrameFormats: make(map[MessageID]string),
 	}
 }
 
@@ -80,9 +91,11 @@ func (i *dbcImporter) getSignalKey(dbcMsgID uint32, sigName string) string {
 	return fmt.Sprintf("%d_%s", dbcMsgID, sigName)
 }
 
-func (i *dbcImporter) getSignalTypeKey(dbcSig *dbc.Signal) string {
+func (i *dbcImporter) getSignalTypeKey(dbcSig *dbc.Signal, isFloat bool) string {
 	signStr := "u"
-	if dbcSig.ValueType == dbc.SignalSigned {
+	if isFloat {
+		signStr = "f"
+	} else if dbcSig.ValueType == dbc.SignalSigned {
 		signStr = "s"
 	}
 	return fmt.Sprintf("%s%d_%g-%g_%g_%g", signStr, dbcSig.Size, dbcSig.Min, dbcSig.Max, dbcSig.Factor, dbcSig.Offset)
@@ -107,6 +120,10 @@ func (i *dbcImporter) importFile(dbcFile *dbc.File) (*Bus, error) {
 	}
 
 	i.importExtMuxes(dbcFile.ExtendedMuxes)
+	i.importSignalExtValueTypes(dbcFile.SignalExtValueTypes)
+
+	i.importFrameFormats(dbcFile.Attributes, dbcFile.AttributeDefaults, dbcFile.AttributeValues)
+	i.importBusType(dbcFile.Messages, dbcFile.AttributeValues)
 
 	if err := i.importNodes(dbcFile.Nodes); err != nil {
 		return nil, err
@@ -155,6 +172,81 @@ func (i *dbcImporter) importComments(dbcComments []*dbc.Comment) {
 	}
 }
 
+// importFrameFormats stores the default frame format and the frame format of each message.
+// The frame format values are taken from the file because
+// the attribute enum has many reserved (duplicated) values.
+func (i *dbcImporter) importFrameFormats(dbcAtts []*dbc.Attribute, dbcAttDefs []*dbc.AttributeDefault, dbcAttVals []*dbc.AttributeValue) {
+	frameFormats := []string{}
+	for _, dbcAtt := range dbcAtts {
+		if dbcAtt.Name == dbc.MsgFrameFormatName && dbcAtt.Type == dbc.AttributeEnum {
+			frameFormats = dbcAtt.EnumValues
+			break
+		}
+	}
+
+	for _, dbcAttDef := range dbcAttDefs {
+		if dbcAttDef.AttributeName == dbc.MsgFrameFormatName {
+			i.defFrameFormat = dbcAttDef.ValueString
+			break
+		}
+	}
+
+	for _, dbcAttVal := range dbcAttVals {
+		if dbcAttVal.AttributeName != dbc.MsgFrameFormatName {
+			continue
+		}
+
+		idx := dbcAttVal.ValueInt
+		if idx >= 0 && idx < len(frameFormats) {
+			i.msgFrameFormats[MessageID(dbcAttVal.MessageID)] = frameFormats[idx]
+		}
+	}
+}
+
+// getFrameFormat returns the frame format of the message with the given id.
+func (i *dbcImporter) getFrameFormat(msgID MessageID) string {
+	if frameFormat, ok := i.msgFrameFormats[msgID]; ok {
+		return frameFormat
+	}
+	return i.defFrameFormat
+}
+
+// importBusType sets the type of the bus by looking at the bus type attribute,
+// at the frame format of the messages and at the extended flag of the message IDs.
+// It must be called before importing the messages because
+// the type of the bus limits the size and the CAN-ID format of the messages.
+func (i *dbcImporter) importBusType(dbcMsgs []*dbc.Message, dbcAttVals []*dbc.AttributeValue) {
+	isFDFrameFormat := func(frameFormat string) bool {
+		return strings.HasSuffix(frameFormat, "_FD")
+	}
+
+	for _, dbcAttVal := range dbcAttVals {
+		if dbcAttVal.AttributeName == dbc.BusTypeName && dbcAttVal.ValueString == dbc.BusTypeCANFD {
+			i.bus.SetType(BusTypeCANFD)
+			return
+		}
+	}
+
+	if isFDFrameFormat(i.defFrameFormat) {
+		i.bus.SetType(BusTypeCANFD)
+		return
+	}
+
+	for _, frameFormat := range i.msgFrameFormats {
+		if isFDFrameFormat(frameFormat) {
+			i.bus.SetType(BusTypeCANFD)
+			return
+		}
+	}
+
+	for _, dbcMsg := range dbcMsgs {
+		if dbcMsg.IsExtended() {
+			i.bus.SetType(BusTypeCAN2B)
+			return
+		}
+	}
+}
+
 func (i *dbcImporter) importAttributes(dbcAtts []*dbc.Attribute, dbcAttDefs []*dbc.AttributeDefault, dbcAttVals []*dbc.AttributeValue) error {
 	dbcAttDefMap := make(map[string]*dbc.AttributeDefault)
 	for _, dbcAttDef := range dbcAttDefs {
@@ -251,6 +343,24 @@ func (i *dbcImporter) importAttributes(dbcAtts []*dbc.Attribute, dbcAttDefs []*d
 
 		switch dbcAttVal.AttributeKind {
 		case dbc.AttributeGeneral:
+			attType, ok := specialAttributeTypes[attName]
+			if ok {
+				switch attType {
+				case specialAttributeBusBaudrate:
+					if baudrate, ok := value.(int); ok {
+						i.bus.SetBaudrate(baudrate)
+					}
+
+				case specialAttributeBusDataBaudrate:
+					if dataBaudrate, ok := value.(int); ok {
+						i.bus.SetDataBaudrate(dataBaudrate)
+					}
+				}
+
+				// The bus type has already been imported
+				break
+			}
+
 			if err := i.bus.AssignAttribute(att, value); err != nil {
 				return i.errorf(dbcAttVal, err)
 			}
@@ -278,8 +388,15 @@ func (i *dbcImporter) importAttributes(dbcAtts []*dbc.Attribute, dbcAttDefs []*d
 
 					case specialAttributeMsgSendType:
 						msg.SetSendType(messageSendTypeFromDBC(value.(string)))
+
+					case specialAttributeMsgBitRateSwitch:
+						if value == dbc.MsgBitRateSwitchValues[1] {
+							i.bus.SetBitRateSwitch(true)
+						}
 					}
 
+					// The frame format has already been imported with the bus type
+
 					break
 				}
 
@@ -390,6 +507,13 @@ func (i *dbcImporter) importExtMuxes(dbcExtMuxes []*dbc.ExtendedMux) {
 	}
 }
 
+func (i *dbcImporter) importSignalExtValueTypes(dbcSigExtValTypes []*dbc.SignalExtValueType) {
+	for _, tmpExtValType := range dbcSigExtValTypes {
+		key := i.getSignalKey(tmpExtValType.MessageID, tmpExtValType.SignalName)
+		i.dbcSigExtValTypes[key] = tmpExtValType.ExtValueType
+	}
+}
+
 func (i *dbcImporter) importNodes(dbcNodes *dbc.Nodes) error {
 	for idx, nodeName := range dbcNodes.Names {
 		if nodeName == dbc.DummyNode {
@@ -419,11 +543,27 @@ func (i *dbcImporter) importNodes(dbcNodes *dbc.Nodes) error {
 }
 
 func (i *dbcImporter) importMessage(dbcMsg *dbc.Message) error {
+	// The message ID keeps the extended flag because
+	// it is used as a reference by the other sections of the file
 	msgID := MessageID(dbcMsg.ID)
-	msg := NewMessage(dbcMsg.Name, msgID, int(dbcMsg.Size))
+	canID := CANID(dbcMsg.CANID())
+	msg := NewMessage(dbcMsg.Name, MessageID(canID), int(dbcMsg.Size))
+
+	if dbcMsg.IsExtended() {
+		if err := msg.SetCANIDFormat(CANIDFormatExtended); err != nil {
+			return i.errorf(dbcMsg, err)
+		}
+	}
+
+	// J1939 parameter groups bigger than a frame are sent with the transport protocol
+	if i.getFrameFormat(msgID) == "J1939PG" && dbcMsg.Size > 8 {
+		if err := msg.SetTransport(MessageTransportJ1939TP); err != nil {
+			return i.errorf(dbcMsg, err)
+		}
+	}
 
 	// Set the message ID as static
-	if err := msg.SetStaticCANID(CANID(msgID)); err != nil {
+	if err := msg.SetStaticCANID(canID); err != nil {
 		return i.errorf(dbcMsg, err)
 	}
 
@@ -688,7 +828,7 @@ func (i *dbcImporter) importSignal(dbcSig *dbc.Signal, dbcMsgID uint32) (Signal,
 		sig = enumSig
 
 	} else {
-		sigType, err := i.importSignalType(dbcSig)
+		sigType, err := i.importSignalType(dbcSig, sigKey)
 		if err != nil {
 			return nil, err
 		}
@@ -717,24 +857,36 @@ func (i *dbcImporter) importSignal(dbcSig *dbc.Signal, dbcMsgID uint32) (Signal,
 	return sig, nil
 }
 
-func (i *dbcImporter) importSignalType(dbcSig *dbc.Signal) (*SignalType, error) {
+func (i *dbcImporter) importSignalType(dbcSig *dbc.Signal, sigKey string) (*SignalType, error) {
 	signed := false
 	if dbcSig.ValueType == dbc.SignalSigned {
 		signed = true
 	}
 
+	// Check if the signal is an IEEE float/double (SIG_VALTYPE_)
+	isFloat := false
+	if extValType, ok := i.dbcSigExtValTypes[sigKey]; ok {
+		isFloat = extValType == dbc.SignalExtValueTypeFloat || extValType == dbc.SignalExtValueTypeDouble
+	}
+
 	sigSize := int(dbcSig.Size)
-	if sigSize == 1 && !signed {
+	if sigSize == 1 && !signed && !isFloat {
 		return i.flagSigType, nil
 	}
 
-	sigTypeKey := i.getSignalTypeKey(dbcSig)
+	sigTypeKey := i.getSignalTypeKey(dbcSig, isFloat)
 	if sigType, ok := i.signalTypes[sigTypeKey]; ok {
 		return sigType, nil
 	}
 
 	sigType := new(SignalType)
-	if isDecimal(dbcSig.Factor) || isDecimal(dbcSig.Max) || isDecimal(dbcSig.Min) || isDecimal(dbcSig.Offset) {
+	if isFloat {
+		floatSigType, err := NewFloatSignalType(sigTypeKey, sigSize)
+		if err != nil {
+			return nil, i.errorf(dbcSig, err)
+		}
+		sigType = floatSigType
+	} else if isDecimal(dbcSig.Factor) || isDecimal(dbcSig.Max) || isDecimal(dbcSig.Min) || isDecimal(dbcSig.Offset) {
 		decSigType, err := NewDecimalSignalType(sigTypeKey, sigSize, signed)
 		if err != nil {
 			return nil, i.errorf(dbcSig, err)
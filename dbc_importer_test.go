@@ -2,6 +2,7 @@ package acmelib
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,3 +20,141 @@ func Test_ImportDBCFile(t *testing.T) {
 	_, err = ImportDBCFile(dbcTestFile, dbcFile)
 	assert.NoError(err)
 }
+
+func Test_ImportDBCFile_SignalExtValueTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	dbcContent := `VERSION ""
+
+BU_: node_0
+
+BO_ 1 float_message : 8 node_0
+ SG_ float32_signal : 0|32@1- (1,0) [0|0] "" Vector__XXX
+ SG_ int_signal : 32|32@1- (1,0) [0|0] "" Vector__XXX
+
+BO_ 2 double_message : 8 node_0
+ SG_ float64_signal : 0|64@1- (0.5,0) [0|0] "" Vector__XXX
+
+SIG_VALTYPE_ 1 float32_signal : 1;
+SIG_VALTYPE_ 2 float64_signal 2;
+`
+
+	bus, err := ImportDBCFile("float.dbc", strings.NewReader(dbcContent))
+	assert.NoError(err)
+
+	nodeInt, err := bus.GetNodeInterfaceByNodeName("node_0")
+	assert.NoError(err)
+
+	checkSignalType := func(msgIdx int, sigName string, expectedKind SignalTypeKind, expectedSize int) {
+		msg := nodeInt.SentMessages()[msgIdx]
+		sig, err := msg.GetSignalByName(sigName)
+		assert.NoError(err)
+		stdSig, err := sig.ToStandard()
+		assert.NoError(err)
+		assert.Equal(expectedKind, stdSig.Type().Kind())
+		assert.Equal(expectedSize, stdSig.Type().Size())
+	}
+
+	checkSignalType(0, "float32_signal", SignalTypeKindFloat, 32)
+	checkSignalType(0, "int_signal", SignalTypeKindInteger, 32)
+	checkSignalType(1, "float64_signal", SignalTypeKindFloat, 64)
+
+	// should export the extended value types
+	dbcRes := new(strings.Builder)
+	ExportDBCBus(dbcRes, bus)
+	assert.Contains(dbcRes.String(), "SIG_VALTYPE_ 1 float32_signal : 1;")
+	assert.Contains(dbcRes.String(), "SIG_VALTYPE_ 2 float64_signal : 2;")
+	assert.NotContains(dbcRes.String(), "int_signal : 0;")
+}
+
+func Test_ImportDBCFile_CANFD(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCANFD)
+	bus.SetBaudrate(500_000)
+	bus.SetDataBaudrate(2_000_000)
+	bus.SetBitRateSwitch(true)
+
+	nodeInt := NewNode("node_0", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+	assert.NoError(nodeInt.AddSentMessage(NewMessage("fd_message", 1, 64)))
+
+	// should export the CAN FD attributes
+	dbcRes := new(strings.Builder)
+	ExportDBCBus(dbcRes, bus)
+	assert.Contains(dbcRes.String(), `BA_ "BusType" "CAN FD";`)
+	assert.Contains(dbcRes.String(), `BA_ "BaudrateCANFD" 2000000;`)
+	assert.Contains(dbcRes.String(), `BA_ "VFrameFormat" BO_ 1 14;`)
+	assert.Contains(dbcRes.String(), `BA_ "CANFD_BRS" BO_ 1 1;`)
+
+	// should import the CAN FD bus
+	impBus, err := ImportDBCFile("fd.dbc", strings.NewReader(dbcRes.String()))
+	assert.NoError(err)
+	assert.Equal(BusTypeCANFD, impBus.Type())
+	assert.Equal(500_000, impBus.Baudrate())
+	assert.Equal(2_000_000, impBus.DataBaudrate())
+	assert.True(impBus.BitRateSwitch())
+
+	impNodeInt, err := impBus.GetNodeInterfaceByNodeName("node_0")
+	assert.NoError(err)
+	assert.Equal(64, impNodeInt.SentMessages()[0].SizeByte())
+
+	// should detect a CAN FD bus only by the frame format of the messages
+	dbcContent := `VERSION ""
+
+BU_: node_0
+
+BO_ 1 fd_message : 12 node_0
+
+BA_DEF_ BO_ "VFrameFormat" ENUM "StandardCAN","ExtendedCAN","StandardCAN_FD","ExtendedCAN_FD";
+BA_DEF_DEF_ "VFrameFormat" "StandardCAN";
+BA_ "VFrameFormat" BO_ 1 2;
+`
+	impBus, err = ImportDBCFile("fd.dbc", strings.NewReader(dbcContent))
+	assert.NoError(err)
+	assert.Equal(BusTypeCANFD, impBus.Type())
+	assert.False(impBus.BitRateSwitch())
+}
+
+func Test_ImportDBCFile_ExtendedID(t *testing.T) {
+	assert := assert.New(t)
+
+	dbcContent := `VERSION ""
+
+BU_: node_0
+
+BO_ 2566844926 extended_message : 8 node_0
+ SG_ signal : 0|8@1+ (1,0) [0|255] "" Vector__XXX
+
+BO_ 256 standard_message : 8 node_0
+
+CM_ BO_ 2566844926 "extended message";
+`
+
+	bus, err := ImportDBCFile("extended.dbc", strings.NewReader(dbcContent))
+	assert.NoError(err)
+	assert.Equal(BusTypeCAN2B, bus.Type())
+
+	nodeInt, err := bus.GetNodeInterfaceByNodeName("node_0")
+	assert.NoError(err)
+
+	extMsg, err := nodeInt.GetSentMessageByName("extended_message")
+	assert.NoError(err)
+	assert.Equal(CANIDFormatExtended, extMsg.CANIDFormat())
+	assert.Equal(CANID(0x18FEF1FE), extMsg.GetCANID())
+	assert.Equal("extended message", extMsg.Desc())
+
+	stdMsg, err := nodeInt.GetSentMessageByName("standard_message")
+	assert.NoError(err)
+	assert.Equal(CANIDFormatStandard, stdMsg.CANIDFormat())
+	assert.Equal(CANID(256), stdMsg.GetCANID())
+
+	// should set the extended flag again
+	dbcRes := new(strings.Builder)
+	ExportDBCBus(dbcRes, bus)
+	assert.Contains(dbcRes.String(), "BO_ 2566844926 extended_message : 8 node_0")
+	assert.Contains(dbcRes.String(), `CM_ BO_ 2566844926 "extended message";`)
+	assert.Contains(dbcRes.String(), `BA_ "VFrameFormat" BO_ 2566844926 1;`)
+	assert.Contains(dbcRes.String(), "BO_ 256 standard_message : 8 node_0")
+}
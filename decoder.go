@@ -0,0 +1,208 @@
+package acmelib
+
+import (
+	"github.com/squadracorsepolito/acmelib/internal/collection"
+)
+
+// Decoder decodes raw CAN frames into the [Message] that owns the CAN-ID of the frame.
+// It is built by [Bus.NewDecoder] or [Network.NewDecoder] and it holds an index
+// of the CAN-IDs of all the sent messages at the time of creation.
+// A standard and an extended CAN-ID with the same value belong to different messages.
+// Therefore, it must be recreated when a message is added, removed,
+// or when its CAN-ID changes.
+//
+// A Decoder does not modify the model, so it can be used concurrently.
+type Decoder struct {
+	messages    *collection.Map[decoderKey, *Message]
+	pgnMessages *collection.Map[J1939PGN, []*Message]
+}
+
+func newDecoder() *Decoder {
+	return &Decoder{
+		messages:    collection.NewMap[decoderKey, *Message](),
+		pgnMessages: collection.NewMap[J1939PGN, []*Message](),
+	}
+}
+
+// decoderKey is the key of the messages indexed by a [Decoder].
+type decoderKey struct {
+	canID  CANID
+	format CANIDFormat
+}
+
+// addBus indexes all the messages sent by the node interfaces of the bus.
+// If the bus uses a J1939 [CANIDBuilder], the messages with an extended CAN-ID
+// are also indexed by their J1939 PGN. Many messages can share the same PGN
+// when they are sent by different source addresses.
+//
+// It returns a [CANIDError] if two messages have the same CAN-ID and format.
+func (d *Decoder) addBus(bus *Bus) error {
+	isJ1939 := bus.canIDBuilder != nil && bus.canIDBuilder.isJ1939()
+
+	for _, nodeInt := range bus.NodeInterfaces() {
+		for _, msg := range nodeInt.SentMessages() {
+			canID := msg.GetCANID()
+			key := decoderKey{canID: canID, format: msg.canIDFormat}
+
+			if d.messages.Has(key) {
+				return msg.errorf(newCANIDError(canID, ErrIsDuplicated))
+			}
+
+			d.messages.Set(key, msg)
+
+			if isJ1939 && msg.canIDFormat == CANIDFormatExtended {
+				pgn := ParseJ1939CANID(canID).PGN
+				pgnMsgs, _ := d.pgnMessages.Get(pgn)
+				d.pgnMessages.Set(pgn, append(pgnMsgs, msg))
+			}
+		}
+	}
+
+	return nil
+}
+
+// MessageCount returns the number of messages indexed by the decoder.
+func (d *Decoder) MessageCount() int {
+	return d.messages.Size()
+}
+
+// GetMessage returns the [Message] with the given CAN-ID and [CANIDFormat].
+//
+// It returns an [ErrNotFound] wrapped by a [CANIDError]
+// if the CAN-ID does not match any message.
+func (d *Decoder) GetMessage(canID CANID, format CANIDFormat) (*Message, error) {
+	msg, ok := d.messages.Get(decoderKey{canID: canID, format: format})
+	if !ok {
+		return nil, newCANIDError(canID, ErrNotFound)
+	}
+	return msg, nil
+}
+
+// GetMessageByPGN returns the [Message] with the given J1939 PGN.
+// Only the messages with an extended CAN-ID sent within a bus
+// with a J1939 [CANIDBuilder] are taken into account.
+//
+// It returns:
+//   - [ErrNotFound] if the PGN does not match any message.
+//   - [ErrIsDuplicated] wrapped by a [J1939PGNError] if the PGN is sent
+//     by more than one message.
+func (d *Decoder) GetMessageByPGN(pgn J1939PGN) (*Message, error) {
+	msgs, ok := d.pgnMessages.Get(pgn)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if len(msgs) > 1 {
+		return nil, newJ1939PGNError(pgn, ErrIsDuplicated)
+	}
+
+	return msgs[0], nil
+}
+
+// getMessageByJ1939ID returns the [Message] with the given J1939 PGN.
+// When more messages have the same PGN, the one with the given source address is returned.
+func (d *Decoder) getMessageByJ1939ID(pgn J1939PGN, srcAddr uint8) (*Message, error) {
+	msgs, ok := d.pgnMessages.Get(pgn)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if len(msgs) == 1 {
+		return msgs[0], nil
+	}
+
+	var res *Message
+	for _, msg := range msgs {
+		if ParseJ1939CANID(msg.GetCANID()).SourceAddress != srcAddr {
+			continue
+		}
+
+		if res != nil {
+			return nil, newJ1939PGNError(pgn, ErrIsDuplicated)
+		}
+		res = msg
+	}
+
+	if res == nil {
+		return nil, newJ1939PGNError(pgn, ErrIsDuplicated)
+	}
+
+	return res, nil
+}
+
+// DecodeFrame decodes the data of the frame with the given CAN-ID and [CANIDFormat].
+// It returns the [Message] that owns the CAN-ID and the decoded signals.
+//
+// When the length of the data is different from the size of the message,
+// the message and the signals that fit completely in the data are returned anyway,
+// together with the error.
+//
+// It returns:
+//   - [CANIDError] if the CAN-ID does not match any message.
+//   - [SizeError] if the length of the data is different from the size of the message.
+func (d *Decoder) DecodeFrame(canID CANID, format CANIDFormat, data []byte) (*Message, []*SignalDecoding, error) {
+	msg, err := d.GetMessage(canID, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return d.decode(msg, data)
+}
+
+// DecodeJ1939Frame decodes the data of the J1939 frame with the given extended CAN-ID.
+// The message is looked up by CAN-ID first, and then by PGN,
+// so the frames sent with a different priority, source address
+// or destination address (PDU1) are decoded as well.
+// When more messages have the same PGN, the one with the source address
+// of the CAN-ID is chosen.
+// See [Decoder.DecodeFrame] for the handling of the data length.
+//
+// It returns:
+//   - [ErrNotFound] if the CAN-ID and the PGN do not match any message.
+//   - [ErrIsDuplicated] wrapped by a [J1939PGNError] if the PGN is sent by more
+//     than one message and none of them matches the source address.
+//   - [SizeError] if the length of the data is different from the size of the message.
+func (d *Decoder) DecodeJ1939Frame(canID CANID, data []byte) (*Message, []*SignalDecoding, error) {
+	msg, ok := d.messages.Get(decoderKey{canID: canID, format: CANIDFormatExtended})
+	if !ok {
+		id := ParseJ1939CANID(canID)
+		tmpMsg, err := d.getMessageByJ1939ID(id.PGN, id.SourceAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+		msg = tmpMsg
+	}
+
+	return d.decode(msg, data)
+}
+
+// DecodeJ1939TPMessage decodes a multi-packet message
+// reassembled by a [J1939TPReassembler].
+// See [Decoder.DecodeFrame] for the handling of the data length.
+//
+// It returns:
+//   - [ErrNotFound] if the PGN does not match any message.
+//   - [SizeError] if the length of the data is different from the size of the message.
+func (d *Decoder) DecodeJ1939TPMessage(tpMsg *J1939TPMessage) (*Message, []*SignalDecoding, error) {
+	msg, err := d.getMessageByJ1939ID(tpMsg.PGN, tpMsg.SourceAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return d.decode(msg, tpMsg.Data)
+}
+
+func (d *Decoder) decode(msg *Message, data []byte) (*Message, []*SignalDecoding, error) {
+	decodings := msg.layout.Decode(data)
+
+	dataLen := len(data)
+	if dataLen < msg.sizeByte {
+		return msg, decodings, msg.errorf(newSizeError(dataLen, ErrTooSmall))
+	}
+
+	if dataLen > msg.sizeByte {
+		return msg, decodings, msg.errorf(newSizeError(dataLen, ErrTooBig))
+	}
+
+	return msg, decodings, nil
+}
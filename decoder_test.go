@@ -0,0 +1,316 @@
+package acmelib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Decoder_DecodeFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	tdNet := initNetwork(assert)
+
+	dec, err := tdNet.bus.NewDecoder()
+	assert.NoError(err)
+	assert.Equal(6, dec.MessageCount())
+
+	basicData := []byte{
+		0b00000001,
+		0b00011100,
+		0b11000000,
+		0b00000001,
+		0b00011100,
+		0b11000000,
+		0b00000000,
+		0b00000000,
+	}
+
+	// should decode the basic message
+	basicMsg := tdNet.messages.basic.message
+	msg, decodings, err := dec.DecodeFrame(basicMsg.GetCANID(), CANIDFormatStandard, basicData)
+	assert.NoError(err)
+	assert.Equal(basicMsg.EntityID(), msg.EntityID())
+	assert.Len(decodings, 4)
+	for _, sigDec := range decodings {
+		assert.Equal(uint64(3073), sigDec.RawValue)
+	}
+
+	// should return an error because the CAN-ID is unknown
+	msg, decodings, err = dec.DecodeFrame(1024, CANIDFormatStandard, basicData)
+	assert.ErrorIs(err, ErrNotFound)
+	var canIDErr *CANIDError
+	assert.ErrorAs(err, &canIDErr)
+	assert.Equal(CANID(1024), canIDErr.CANID)
+	assert.Nil(msg)
+	assert.Nil(decodings)
+
+	// should return the message with an error because the data is too short
+	msg, decodings, err = dec.DecodeFrame(basicMsg.GetCANID(), CANIDFormatStandard, basicData[:3])
+	assert.ErrorIs(err, ErrTooSmall)
+	assert.Equal(basicMsg.EntityID(), msg.EntityID())
+	assert.Len(decodings, 2)
+
+	// should return the message with an error because the data is too long
+	typedMsg := tdNet.messages.typed.message
+	msg, decodings, err = dec.DecodeFrame(typedMsg.GetCANID(), CANIDFormatStandard, basicData)
+	assert.ErrorIs(err, ErrTooBig)
+	assert.Equal(typedMsg.EntityID(), msg.EntityID())
+	assert.Len(decodings, 5)
+
+	// should use the static CAN-ID
+	assert.NoError(basicMsg.SetStaticCANID(1024))
+	dec, err = tdNet.net.NewDecoder()
+	assert.NoError(err)
+	msg, _, err = dec.DecodeFrame(1024, CANIDFormatStandard, basicData)
+	assert.NoError(err)
+	assert.Equal(basicMsg.EntityID(), msg.EntityID())
+	_, err = dec.GetMessage(1, CANIDFormatStandard)
+	assert.ErrorIs(err, ErrNotFound)
+
+	// should use the CAN-ID builder of the bus
+	builder := NewCANIDBuilder("builder").UseNodeID(0, 4).UseMessageID(4, 7)
+	tdNet.bus.SetCANIDBuilder(builder)
+	dec, err = tdNet.bus.NewDecoder()
+	assert.NoError(err)
+	msg, err = dec.GetMessage(typedMsg.GetCANID(), CANIDFormatStandard)
+	assert.NoError(err)
+	assert.Equal(typedMsg.EntityID(), msg.EntityID())
+	assert.Equal(CANID(2<<4), typedMsg.GetCANID())
+
+	// should return an error because the CAN-IDs are duplicated between buses
+	otherBus := NewBus("other_bus")
+	assert.NoError(tdNet.net.AddBus(otherBus))
+	otherNodeInt := NewNode("other_node", 0, 1).GetInterface(0)
+	assert.NoError(otherBus.AddNodeInterface(otherNodeInt))
+	assert.NoError(otherNodeInt.AddSentMessage(NewMessage("other_message", 1, 8)))
+	assert.NoError(otherNodeInt.AddSentMessage(NewMessage("other_message_dup", 2<<4, 8)))
+
+	_, err = tdNet.net.NewDecoder()
+	assert.ErrorIs(err, ErrIsDuplicated)
+
+	_, err = otherBus.NewDecoder()
+	assert.NoError(err)
+}
+
+func Test_Decoder_DecodeJ1939(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+	bus.SetCANIDBuilder(NewJ1939CANIDBuilder("j1939_builder"))
+
+	nodeInt := NewNode("engine", 0x00, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	sigType, err := NewIntegerSignalType("uint8_t", 8, false)
+	assert.NoError(err)
+
+	eec1 := NewJ1939PGN(false, false, 0xF0, 0x04)
+	eec1Msg := NewMessage("eec1", eec1.ToMessageID(0), 8)
+	eec1Msg.SetPriority(3)
+	assert.NoError(eec1Msg.SetCANIDFormat(CANIDFormatExtended))
+	eec1Sig, err := NewStandardSignal("eec1_signal", sigType)
+	assert.NoError(err)
+	assert.NoError(eec1Msg.InsertSignal(eec1Sig, 0))
+	assert.NoError(nodeInt.AddSentMessage(eec1Msg))
+
+	tp := NewJ1939PGN(false, false, 0xFE, 0xCA)
+	tpMsg := NewMessage("tp_message", tp.ToMessageID(0), 20)
+	tpMsg.SetPriority(6)
+	assert.NoError(tpMsg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(tpMsg.SetTransport(MessageTransportJ1939TP))
+	tpSig, err := NewStandardSignal("tp_signal", sigType)
+	assert.NoError(err)
+	assert.NoError(tpMsg.InsertSignal(tpSig, 152))
+	assert.NoError(nodeInt.AddSentMessage(tpMsg))
+
+	dec, err := bus.NewDecoder()
+	assert.NoError(err)
+
+	// should decode by CAN-ID
+	msg, decodings, err := dec.DecodeJ1939Frame(0x0CF00400, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	assert.NoError(err)
+	assert.Equal(eec1Msg.EntityID(), msg.EntityID())
+	assert.Equal(uint64(1), decodings[0].RawValue)
+
+	// should decode by PGN with a different priority and source address
+	msg, _, err = dec.DecodeJ1939Frame(0x18F00417, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	assert.NoError(err)
+	assert.Equal(eec1Msg.EntityID(), msg.EntityID())
+
+	// should return an error because the PGN is unknown
+	_, _, err = dec.DecodeJ1939Frame(0x18FEF100, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	assert.ErrorIs(err, ErrNotFound)
+
+	// should decode a reassembled message
+	payload := make([]byte, 20)
+	payload[19] = 42
+	r := NewJ1939TPReassembler()
+	canIDs, frames := getJ1939TPFrames(0x00, J1939GlobalAddress, tp, payload)
+	var reassembled *J1939TPMessage
+	for idx := range canIDs {
+		reassembled, err = r.Feed(canIDs[idx], frames[idx])
+		assert.NoError(err)
+	}
+	msg, decodings, err = dec.DecodeJ1939TPMessage(reassembled)
+	assert.NoError(err)
+	assert.Equal(tpMsg.EntityID(), msg.EntityID())
+	assert.Equal(uint64(42), decodings[0].RawValue)
+
+	// should decode by source address because the PGN is sent by another node
+	otherNodeInt := NewNode("other_engine", 0x01, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(otherNodeInt))
+	otherMsg := NewMessage("other_eec1", eec1.ToMessageID(0), 8)
+	assert.NoError(otherMsg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(otherNodeInt.AddSentMessage(otherMsg))
+
+	dec, err = bus.NewDecoder()
+	assert.NoError(err)
+
+	msg, _, err = dec.DecodeJ1939Frame(0x18F00400, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	assert.NoError(err)
+	assert.Equal(eec1Msg.EntityID(), msg.EntityID())
+
+	msg, _, err = dec.DecodeJ1939Frame(0x18F00401, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	assert.NoError(err)
+	assert.Equal(otherMsg.EntityID(), msg.EntityID())
+
+	// should return an error because the source address does not match any of the messages
+	_, _, err = dec.DecodeJ1939Frame(0x18F00417, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	assert.ErrorIs(err, ErrIsDuplicated)
+	var pgnErr *J1939PGNError
+	assert.ErrorAs(err, &pgnErr)
+	assert.Equal(eec1, pgnErr.PGN)
+
+	_, err = dec.GetMessageByPGN(eec1)
+	assert.ErrorIs(err, ErrIsDuplicated)
+}
+
+func Test_Decoder_ExtendedCANIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("net")
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	// the CAN-IDs have the same J1939 PGN, but the bus does not use the J1939 builder
+	msg0 := NewMessage("msg_0", 1, 8)
+	assert.NoError(msg0.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(msg0.SetStaticCANID(0x18FF0001))
+	assert.NoError(nodeInt.AddSentMessage(msg0))
+
+	msg1 := NewMessage("msg_1", 2, 8)
+	assert.NoError(msg1.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(msg1.SetStaticCANID(0x18FF0002))
+	assert.NoError(nodeInt.AddSentMessage(msg1))
+
+	// should create the decoders without errors
+	dec, err := bus.NewDecoder()
+	assert.NoError(err)
+	assert.Equal(2, dec.MessageCount())
+
+	_, err = net.NewDecoder()
+	assert.NoError(err)
+
+	_, err = net.NewChannelDecoder(map[string]string{"can0": "bus"})
+	assert.NoError(err)
+
+	// should decode by CAN-ID only
+	msg, _, err := dec.DecodeFrame(0x18FF0002, CANIDFormatExtended, make([]byte, 8))
+	assert.NoError(err)
+	assert.Equal(msg1.EntityID(), msg.EntityID())
+
+	_, err = dec.GetMessageByPGN(ParseJ1939CANID(0x18FF0001).PGN)
+	assert.ErrorIs(err, ErrNotFound)
+}
+
+func Test_Decoder_DecodeFrame_ShortData(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("net")
+	bus := NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	node := NewNode("node", 1, 1)
+	assert.NoError(bus.AddNodeInterface(node.GetInterface(0)))
+
+	msg := NewMessage("msg", 1, 8)
+	assert.NoError(node.GetInterface(0).AddSentMessage(msg))
+
+	u8, err := NewIntegerSignalType("u8_t", 8, false)
+	assert.NoError(err)
+
+	// the signals are inserted so that the ones that do not fit
+	// are mixed with the ones that fit
+	for _, tmp := range []struct {
+		name     string
+		startPos int
+	}{
+		{"after", 40},
+		{"first", 0},
+		{"cut", 20},
+		{"second", 8},
+		{"last", 56},
+	} {
+		sig, err := NewStandardSignal(tmp.name, u8)
+		assert.NoError(err)
+		assert.NoError(msg.InsertSignal(sig, tmp.startPos))
+	}
+
+	dec, err := bus.NewDecoder()
+	assert.NoError(err)
+
+	// should decode only the signals that fit completely in the data
+	_, decodings, err := dec.DecodeFrame(msg.GetCANID(), CANIDFormatStandard, []byte{0x11, 0x22, 0x33})
+	assert.ErrorIs(err, ErrTooSmall)
+	assert.Len(decodings, 2)
+
+	decoded := make(map[string]uint64)
+	for _, sigDec := range decodings {
+		decoded[sigDec.Signal.Name()] = sigDec.RawValue
+	}
+	assert.Equal(map[string]uint64{"first": 0x11, "second": 0x22}, decoded)
+}
+
+func Test_Decoder_CANIDFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+
+	stdNodeInt := NewNode("std_node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(stdNodeInt))
+	extNodeInt := NewNode("ext_node", 2, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(extNodeInt))
+
+	stdMsg := NewMessage("std_msg", 0x100, 8)
+	assert.NoError(stdNodeInt.AddSentMessage(stdMsg))
+
+	extMsg := NewMessage("ext_msg", 0x100, 8)
+	assert.NoError(extMsg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(extNodeInt.AddSentMessage(extMsg))
+
+	// should index the same CAN-ID with different formats
+	dec, err := bus.NewDecoder()
+	assert.NoError(err)
+	assert.Equal(2, dec.MessageCount())
+
+	msg, _, err := dec.DecodeFrame(0x100, CANIDFormatStandard, make([]byte, 8))
+	assert.NoError(err)
+	assert.Equal(stdMsg.EntityID(), msg.EntityID())
+
+	msg, _, err = dec.DecodeFrame(0x100, CANIDFormatExtended, make([]byte, 8))
+	assert.NoError(err)
+	assert.Equal(extMsg.EntityID(), msg.EntityID())
+
+	// should return an error because the CAN-ID and the format are duplicated
+	assert.NoError(extNodeInt.AddSentMessage(NewMessage("std_msg_dup", 0x101, 8)))
+	assert.NoError(stdNodeInt.AddSentMessage(NewMessage("std_msg_2", 0x101, 8)))
+	_, err = bus.NewDecoder()
+	assert.ErrorIs(err, ErrIsDuplicated)
+}
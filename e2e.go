@@ -0,0 +1,804 @@
+package acmelib
+
+import (
+	"math/bits"
+
+	"github.com/squadracorsepolito/acmelib/internal/stringer"
+)
+
+// E2EProfile is the AUTOSAR end-to-end protection profile
+// used to compute the counter and the CRC of a [Message].
+type E2EProfile int
+
+const (
+	// E2EProfile1 defines the AUTOSAR profile 1: CRC-8 SAE J1850 (start value 0x00)
+	// computed over the data id and the payload, 4 bits counter from 0 to 14.
+	E2EProfile1 E2EProfile = iota
+	// E2EProfile2 defines the AUTOSAR profile 2: CRC-8H2F computed over the payload
+	// and the data id selected by the counter from a list of 16, 4 bits counter.
+	E2EProfile2
+	// E2EProfile4 defines the AUTOSAR profile 4: CRC-32P4 computed over the payload,
+	// 16 bits counter. The length and the data id are sent within the payload.
+	E2EProfile4
+	// E2EProfile5 defines the AUTOSAR profile 5: CRC-16 CCITT computed over
+	// the payload and the data id, 8 bits counter.
+	E2EProfile5
+	// E2EProfile11 defines the AUTOSAR profile 11: CRC-8 SAE J1850
+	// computed over the data id and the payload, 4 bits counter from 0 to 14.
+	E2EProfile11
+	// E2EProfileCustom defines a profile with a custom CRC (see [E2ECRCParams])
+	// computed over the payload and the data id. The counter can have any size.
+	E2EProfileCustom
+)
+
+func (p E2EProfile) String() string {
+	switch p {
+	case E2EProfile1:
+		return "profile_1"
+	case E2EProfile2:
+		return "profile_2"
+	case E2EProfile4:
+		return "profile_4"
+	case E2EProfile5:
+		return "profile_5"
+	case E2EProfile11:
+		return "profile_11"
+	case E2EProfileCustom:
+		return "custom"
+	default:
+		return "unknown"
+	}
+}
+
+// E2EDataIDMode defines how the 16 bits data id is included in the CRC
+// by the profiles 1 and 11.
+type E2EDataIDMode int
+
+const (
+	// E2EDataIDModeBoth defines that both the bytes of the data id are included in the CRC.
+	E2EDataIDModeBoth E2EDataIDMode = iota
+	// E2EDataIDModeAlt defines that the low byte of the data id is included in the CRC
+	// when the counter is even, otherwise the high byte is included.
+	E2EDataIDModeAlt
+	// E2EDataIDModeLow defines that only the low byte of the data id is included in the CRC.
+	E2EDataIDModeLow
+	// E2EDataIDModeNibble defines that the low byte of the data id is included in the CRC,
+	// while the low nibble of the high byte is sent within the payload by the data id signal.
+	E2EDataIDModeNibble
+)
+
+func (m E2EDataIDMode) String() string {
+	switch m {
+	case E2EDataIDModeBoth:
+		return "both"
+	case E2EDataIDModeAlt:
+		return "alt"
+	case E2EDataIDModeLow:
+		return "low"
+	case E2EDataIDModeNibble:
+		return "nibble"
+	default:
+		return "unknown"
+	}
+}
+
+// E2ECRCParams are the parameters of a CRC algorithm.
+type E2ECRCParams struct {
+	// Width is the size of the CRC in bits.
+	// It must be a multiple of 8 between 8 and 64.
+	Width int
+	// Polynomial is the generator polynomial, without the most significant bit.
+	Polynomial uint64
+	// Init is the initial value of the CRC register.
+	Init uint64
+	// XorOut is the value xored with the final CRC.
+	XorOut uint64
+	// RefIn reports whether the bits of each input byte are reflected.
+	RefIn bool
+	// RefOut reports whether the bits of the final CRC are reflected.
+	RefOut bool
+}
+
+var (
+	// e2eCRC8 is the CRC-8 SAE J1850 with start value 0x00, used by the profile 1.
+	e2eCRC8 = &E2ECRCParams{Width: 8, Polynomial: 0x1D, Init: 0x00, XorOut: 0x00}
+	// e2eCRC8SAEJ1850 is the CRC-8 SAE J1850, used by the profile 11.
+	e2eCRC8SAEJ1850 = &E2ECRCParams{Width: 8, Polynomial: 0x1D, Init: 0xFF, XorOut: 0xFF}
+	// e2eCRC8H2F is the CRC-8H2F, used by the profile 2.
+	e2eCRC8H2F = &E2ECRCParams{Width: 8, Polynomial: 0x2F, Init: 0xFF, XorOut: 0xFF}
+	// e2eCRC16 is the CRC-16 CCITT, used by the profile 5.
+	e2eCRC16 = &E2ECRCParams{Width: 16, Polynomial: 0x1021, Init: 0xFFFF, XorOut: 0x0000}
+	// e2eCRC32P4 is the CRC-32P4, used by the profile 4.
+	e2eCRC32P4 = &E2ECRCParams{Width: 32, Polynomial: 0xF4ACFB13, Init: 0xFFFFFFFF, XorOut: 0xFFFFFFFF, RefIn: true, RefOut: true}
+)
+
+func (p *E2ECRCParams) verify() error {
+	if p.Width < 8 || p.Width > 64 || p.Width%8 != 0 {
+		return newSizeError(p.Width, ErrInvalidValue)
+	}
+	return nil
+}
+
+// Compute returns the CRC of the given data.
+func (p *E2ECRCParams) Compute(data []byte) uint64 {
+	width := p.Width
+	topBit := uint64(1) << (width - 1)
+	mask := uint64(1)<<width - 1
+
+	crc := p.Init & mask
+	for _, b := range data {
+		if p.RefIn {
+			b = bits.Reverse8(b)
+		}
+
+		crc ^= uint64(b) << (width - 8)
+		for range 8 {
+			if crc&topBit != 0 {
+				crc = crc<<1 ^ p.Polynomial
+			} else {
+				crc <<= 1
+			}
+		}
+		crc &= mask
+	}
+
+	if p.RefOut {
+		crc = bits.Reverse64(crc) >> (64 - width)
+	}
+
+	return (crc ^ p.XorOut) & mask
+}
+
+// E2EProtection holds the settings of the AUTOSAR end-to-end protection of a [Message].
+// The CRC and the counter are standard signals of the message,
+// which are filled by [Message.EncodeValues] and checked by an [E2EReceiver].
+//
+// The settings are verified when the protection is set to a message
+// by calling [Message.SetE2EProtection], so they should not be modified after.
+type E2EProtection struct {
+	profile E2EProfile
+
+	crcSignal     *StandardSignal
+	counterSignal *StandardSignal
+
+	dataID       uint32
+	dataIDMode   E2EDataIDMode
+	dataIDList   []uint8
+	dataIDSignal *StandardSignal
+	lengthSignal *StandardSignal
+
+	maxDeltaCounter uint64
+
+	customCRC *E2ECRCParams
+}
+
+// NewE2EProtection creates a new [E2EProtection] with the given profile,
+// CRC signal and counter signal. By default the data id is 0,
+// the [E2EDataIDMode] is [E2EDataIDModeBoth] and the max delta counter is 1.
+func NewE2EProtection(profile E2EProfile, crcSignal, counterSignal *StandardSignal) *E2EProtection {
+	return &E2EProtection{
+		profile: profile,
+
+		crcSignal:     crcSignal,
+		counterSignal: counterSignal,
+
+		dataID:       0,
+		dataIDMode:   E2EDataIDModeBoth,
+		dataIDList:   nil,
+		dataIDSignal: nil,
+		lengthSignal: nil,
+
+		maxDeltaCounter: 1,
+
+		customCRC: nil,
+	}
+}
+
+func (p *E2EProtection) stringify(s *stringer.Stringer) {
+	s.Write("profile: %s\n", p.profile)
+	s.Write("crc_signal: %s\n", p.crcSignal.name)
+	s.Write("counter_signal: %s\n", p.counterSignal.name)
+
+	if p.profile != E2EProfile2 {
+		s.Write("data_id: %d\n", p.dataID)
+	}
+
+	if p.dataIDSignal != nil {
+		s.Write("data_id_signal: %s\n", p.dataIDSignal.name)
+	}
+
+	if p.lengthSignal != nil {
+		s.Write("length_signal: %s\n", p.lengthSignal.name)
+	}
+}
+
+// Profile returns the [E2EProfile] of the protection.
+func (p *E2EProtection) Profile() E2EProfile {
+	return p.profile
+}
+
+// CRCSignal returns the signal that holds the CRC.
+func (p *E2EProtection) CRCSignal() *StandardSignal {
+	return p.crcSignal
+}
+
+// CounterSignal returns the signal that holds the counter.
+func (p *E2EProtection) CounterSignal() *StandardSignal {
+	return p.counterSignal
+}
+
+// SetDataID sets the data id of the protection.
+// It is 16 bits long, except for the profile 4 that uses 32 bits.
+// The profile 2 uses the data id list instead.
+func (p *E2EProtection) SetDataID(dataID uint32) {
+	p.dataID = dataID
+}
+
+// DataID returns the data id of the protection.
+func (p *E2EProtection) DataID() uint32 {
+	return p.dataID
+}
+
+// SetDataIDMode sets the [E2EDataIDMode] used by the profiles 1 and 11.
+func (p *E2EProtection) SetDataIDMode(mode E2EDataIDMode) {
+	p.dataIDMode = mode
+}
+
+// DataIDMode returns the [E2EDataIDMode] of the protection.
+func (p *E2EProtection) DataIDMode() E2EDataIDMode {
+	return p.dataIDMode
+}
+
+// SetDataIDList sets the list of 16 data ids used by the profile 2.
+// The data id included in the CRC is selected by the counter.
+func (p *E2EProtection) SetDataIDList(dataIDList []uint8) {
+	p.dataIDList = dataIDList
+}
+
+// DataIDList returns the list of data ids used by the profile 2.
+func (p *E2EProtection) DataIDList() []uint8 {
+	return p.dataIDList
+}
+
+// SetDataIDSignal sets the signal that carries the data id within the payload.
+// It is required by the profiles 1 and 11 with the [E2EDataIDModeNibble] (4 bits),
+// and by the profile 4 (32 bits).
+func (p *E2EProtection) SetDataIDSignal(dataIDSignal *StandardSignal) {
+	p.dataIDSignal = dataIDSignal
+}
+
+// DataIDSignal returns the signal that carries the data id, or nil if it is not set.
+func (p *E2EProtection) DataIDSignal() *StandardSignal {
+	return p.dataIDSignal
+}
+
+// SetLengthSignal sets the signal that carries the length in bytes of the payload.
+// It is used by the profile 4.
+func (p *E2EProtection) SetLengthSignal(lengthSignal *StandardSignal) {
+	p.lengthSignal = lengthSignal
+}
+
+// LengthSignal returns the signal that carries the length, or nil if it is not set.
+func (p *E2EProtection) LengthSignal() *StandardSignal {
+	return p.lengthSignal
+}
+
+// SetMaxDeltaCounter sets the maximum allowed gap between the counters
+// of two consecutive messages checked by an [E2EReceiver].
+func (p *E2EProtection) SetMaxDeltaCounter(maxDeltaCounter uint64) {
+	p.maxDeltaCounter = maxDeltaCounter
+}
+
+// MaxDeltaCounter returns the maximum allowed gap between two counters.
+func (p *E2EProtection) MaxDeltaCounter() uint64 {
+	return p.maxDeltaCounter
+}
+
+// SetCustomCRC sets the parameters of the CRC used by the [E2EProfileCustom].
+func (p *E2EProtection) SetCustomCRC(params *E2ECRCParams) {
+	p.customCRC = params
+}
+
+// CustomCRC returns the parameters of the custom CRC, or nil if they are not set.
+func (p *E2EProtection) CustomCRC() *E2ECRCParams {
+	return p.customCRC
+}
+
+// crcParams returns the parameters of the CRC used by the profile.
+func (p *E2EProtection) crcParams() *E2ECRCParams {
+	switch p.profile {
+	case E2EProfile1:
+		return e2eCRC8
+	case E2EProfile2:
+		return e2eCRC8H2F
+	case E2EProfile4:
+		return e2eCRC32P4
+	case E2EProfile5:
+		return e2eCRC16
+	case E2EProfile11:
+		return e2eCRC8SAEJ1850
+	}
+	return p.customCRC
+}
+
+// counterSize returns the size in bits of the counter required by the profile.
+// It returns 0 if the profile accepts any size.
+func (p *E2EProtection) counterSize() int {
+	switch p.profile {
+	case E2EProfile1, E2EProfile2, E2EProfile11:
+		return 4
+	case E2EProfile4:
+		return 16
+	case E2EProfile5:
+		return 8
+	}
+	return 0
+}
+
+// maxCounter returns the maximum value of the counter, after which it wraps to 0.
+func (p *E2EProtection) maxCounter() uint64 {
+	if p.profile == E2EProfile1 || p.profile == E2EProfile11 {
+		return 14
+	}
+	return 1<<p.counterSignal.Size() - 1
+}
+
+// dataIDSignalValue returns the raw value of the data id signal.
+func (p *E2EProtection) dataIDSignalValue() uint64 {
+	if p.dataIDMode == E2EDataIDModeNibble && (p.profile == E2EProfile1 || p.profile == E2EProfile11) {
+		return uint64(p.dataID>>8) & 0x0F
+	}
+	return uint64(p.dataID)
+}
+
+func (p *E2EProtection) verifySignal(msg *Message, argName string, sig *StandardSignal, size int) error {
+	if sig == nil {
+		return newArgError(argName, ErrIsNil)
+	}
+
+	// the signal must be in the top level layout of the message
+	if sig.layout != msg.layout {
+		return newArgError(argName, ErrNotFound)
+	}
+
+	if size > 0 && sig.Size() != size {
+		return newArgError(argName, newSizeError(sig.Size(), ErrInvalidValue))
+	}
+
+	return nil
+}
+
+// verify checks whether the protection can be applied to the given message.
+func (p *E2EProtection) verify(msg *Message) error {
+	if p.profile == E2EProfileCustom {
+		if p.customCRC == nil {
+			return newArgError("custom_crc", ErrIsNil)
+		}
+
+		if err := p.customCRC.verify(); err != nil {
+			return newArgError("custom_crc", err)
+		}
+	}
+
+	crcParams := p.crcParams()
+	if err := p.verifySignal(msg, "crc_signal", p.crcSignal, crcParams.Width); err != nil {
+		return err
+	}
+
+	// the bytes of the CRC are excluded from the computation,
+	// so the signal must fill them completely
+	start, end, _ := msg.layout.getSignalFilters(p.crcSignal)
+	for i := start; i <= end; i++ {
+		if msg.layout.filters[i].mask != 0xFF {
+			return newArgError("crc_signal", newStartPosError(p.crcSignal.StartPos(), ErrInvalidValue))
+		}
+	}
+
+	if err := p.verifySignal(msg, "counter_signal", p.counterSignal, p.counterSize()); err != nil {
+		return err
+	}
+
+	if p.counterSignal.Size() > 32 {
+		return newArgError("counter_signal", newSizeError(p.counterSignal.Size(), ErrTooBig))
+	}
+
+	if p.profile != E2EProfile4 && p.dataID > 0xFFFF {
+		return newArgError("data_id", ErrOutOfBounds)
+	}
+
+	switch p.profile {
+	case E2EProfile1, E2EProfile11:
+		if p.dataIDMode == E2EDataIDModeNibble {
+			if err := p.verifySignal(msg, "data_id_signal", p.dataIDSignal, 4); err != nil {
+				return err
+			}
+		}
+
+	case E2EProfile2:
+		if len(p.dataIDList) != 16 {
+			return newArgError("data_id_list", newSizeError(len(p.dataIDList), ErrInvalidValue))
+		}
+
+	case E2EProfile4:
+		// the data id is not part of the CRC, so it must be sent within the payload
+		if err := p.verifySignal(msg, "data_id_signal", p.dataIDSignal, 32); err != nil {
+			return err
+		}
+	}
+
+	if p.lengthSignal != nil {
+		lengthSize := 0
+		if p.profile == E2EProfile4 {
+			lengthSize = 16
+		}
+
+		if err := p.verifySignal(msg, "length_signal", p.lengthSignal, lengthSize); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// usesSignal returns whether the given signal is used by the protection.
+func (p *E2EProtection) usesSignal(sig Signal) bool {
+	entID := sig.EntityID()
+
+	for _, protSig := range []*StandardSignal{p.crcSignal, p.counterSignal, p.dataIDSignal, p.lengthSignal} {
+		if protSig != nil && protSig.entityID == entID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// computeCRC returns the CRC of the given payload.
+// The bytes of the CRC signal are excluded from the computation.
+func (p *E2EProtection) computeCRC(layout *SignalLayout, data []byte, counter uint64) uint64 {
+	crcStart, crcEnd, _ := layout.getSignalFilters(p.crcSignal)
+
+	isCRCByte := func(byteIdx int) bool {
+		for i := crcStart; i <= crcEnd; i++ {
+			if layout.filters[i].byteIdx == byteIdx {
+				return true
+			}
+		}
+		return false
+	}
+
+	input := make([]byte, 0, len(data)+2)
+
+	dataIDLow := uint8(p.dataID)
+	dataIDHigh := uint8(p.dataID >> 8)
+
+	// profiles 1 and 11 put the data id before the payload
+	if p.profile == E2EProfile1 || p.profile == E2EProfile11 {
+		switch p.dataIDMode {
+		case E2EDataIDModeBoth:
+			input = append(input, dataIDLow, dataIDHigh)
+		case E2EDataIDModeAlt:
+			if counter%2 == 0 {
+				input = append(input, dataIDLow)
+			} else {
+				input = append(input, dataIDHigh)
+			}
+		case E2EDataIDModeLow:
+			input = append(input, dataIDLow)
+		case E2EDataIDModeNibble:
+			input = append(input, dataIDLow, 0x00)
+		}
+	}
+
+	for idx, b := range data {
+		if !isCRCByte(idx) {
+			input = append(input, b)
+		}
+	}
+
+	switch p.profile {
+	case E2EProfile2:
+		input = append(input, p.dataIDList[counter%16])
+	case E2EProfile5, E2EProfileCustom:
+		input = append(input, dataIDLow, dataIDHigh)
+	}
+
+	return p.crcParams().Compute(input)
+}
+
+// encodeValues encodes the given physical values into the data buffer,
+// like [SignalLayout.EncodeValues], and it fills the signals of the protection.
+// If the given counter is not nil, it is used instead of the value of the counter signal,
+// which otherwise defaults to 0 when it is missing.
+func (p *E2EProtection) encodeValues(layout *SignalLayout, data []byte, values map[string]any, counter *uint64) error {
+	getValueRawValue := layout.valuesRawValueGetter(values)
+
+	var counterRawValue uint64
+	if counter != nil {
+		counterRawValue = *counter
+	} else if _, ok := values[p.counterSignal.name]; ok {
+		rawValue, err := getValueRawValue(p.counterSignal)
+		if err != nil {
+			return err
+		}
+		counterRawValue = rawValue
+	}
+
+	return p.encodeRawValues(layout, data, getValueRawValue, counterRawValue)
+}
+
+// encodeRawValues encodes the raw values returned by the getter into the data buffer
+// with the given counter, and it fills the other signals of the protection.
+func (p *E2EProtection) encodeRawValues(layout *SignalLayout, data []byte, getRawValue signalRawValueGetter, counter uint64) error {
+	if len(data) < layout.sizeByte {
+		return newSizeError(len(data), ErrTooSmall)
+	}
+
+	if counter > p.maxCounter() {
+		return newSignalValueError(p.counterSignal.name, ErrOutOfBounds)
+	}
+
+	encData := data[:layout.sizeByte]
+	clear(encData)
+
+	err := layout.encodeSignals(encData, func(sig Signal) (uint64, error) {
+		switch sig.EntityID() {
+		case p.crcSignal.entityID:
+			// computed after all the other signals
+			return 0, nil
+
+		case p.counterSignal.entityID:
+			return counter, nil
+		}
+
+		if p.dataIDSignal != nil && sig.EntityID() == p.dataIDSignal.entityID {
+			return p.dataIDSignalValue(), nil
+		}
+
+		if p.lengthSignal != nil && sig.EntityID() == p.lengthSignal.entityID {
+			return uint64(layout.sizeByte), nil
+		}
+
+		return getRawValue(sig)
+	})
+	if err != nil {
+		return err
+	}
+
+	layout.encodeRawValue(encData, p.crcSignal, p.computeCRC(layout, encData, counter))
+
+	return nil
+}
+
+// E2EStatus is the result of the check of a message protected by an [E2EProtection].
+type E2EStatus int
+
+const (
+	// E2EStatusOK defines a message with a valid CRC and the expected counter.
+	E2EStatusOK E2EStatus = iota
+	// E2EStatusInitial defines the first message with a valid CRC
+	// received after the creation or the reset of the receiver.
+	E2EStatusInitial
+	// E2EStatusRepeated defines a message with a valid CRC
+	// and the same counter of the previous one.
+	E2EStatusRepeated
+	// E2EStatusOKSomeLost defines a message with a valid CRC and a counter
+	// that jumped within the max delta counter, so some messages were lost.
+	E2EStatusOKSomeLost
+	// E2EStatusWrongSequence defines a message with a valid CRC and a counter
+	// that jumped beyond the max delta counter.
+	E2EStatusWrongSequence
+	// E2EStatusWrongDataID defines a message with a valid CRC
+	// that carries a data id different from the expected one.
+	E2EStatusWrongDataID
+	// E2EStatusWrongCRC defines a message with an invalid CRC.
+	E2EStatusWrongCRC
+)
+
+func (s E2EStatus) String() string {
+	switch s {
+	case E2EStatusOK:
+		return "ok"
+	case E2EStatusInitial:
+		return "initial"
+	case E2EStatusRepeated:
+		return "repeated"
+	case E2EStatusOKSomeLost:
+		return "ok_some_lost"
+	case E2EStatusWrongSequence:
+		return "wrong_sequence"
+	case E2EStatusWrongDataID:
+		return "wrong_data_id"
+	case E2EStatusWrongCRC:
+		return "wrong_crc"
+	default:
+		return "unknown"
+	}
+}
+
+// IsValid returns whether the data of the message can be used,
+// which happens when the CRC and the data id are valid
+// and the counter did not jump beyond the max delta counter.
+func (s E2EStatus) IsValid() bool {
+	switch s {
+	case E2EStatusOK, E2EStatusInitial, E2EStatusOKSomeLost:
+		return true
+	default:
+		return false
+	}
+}
+
+// E2ECheck is the result of the check of a message
+// protected by an [E2EProtection], returned by an [E2EReceiver].
+type E2ECheck struct {
+	// Status is the status of the check.
+	Status E2EStatus
+	// Counter is the received counter.
+	Counter uint64
+	// DeltaCounter is the difference between the received counter and the previous one.
+	// It is 0 for the first message.
+	DeltaCounter uint64
+	// ReceivedCRC is the CRC carried by the message.
+	ReceivedCRC uint64
+	// ComputedCRC is the CRC computed over the received payload.
+	ComputedCRC uint64
+}
+
+// E2ESender encodes the payloads of a message protected by an [E2EProtection],
+// by incrementing the counter after each encoding.
+//
+// An E2ESender is not safe for concurrent use.
+type E2ESender struct {
+	msg  *Message
+	prot *E2EProtection
+
+	counter uint64
+}
+
+// NewE2ESender creates a new [E2ESender] for the [Message].
+//
+// It returns an [ErrIsNil] if the message does not have an [E2EProtection].
+func (m *Message) NewE2ESender() (*E2ESender, error) {
+	if m.e2eProtection == nil {
+		return nil, m.errorf(ErrIsNil)
+	}
+
+	return &E2ESender{
+		msg:  m,
+		prot: m.e2eProtection,
+
+		counter: 0,
+	}, nil
+}
+
+// Counter returns the counter that will be used by the next encoding.
+func (s *E2ESender) Counter() uint64 {
+	return s.counter
+}
+
+// Reset resets the counter to 0.
+func (s *E2ESender) Reset() {
+	s.counter = 0
+}
+
+// EncodeValues encodes the given physical values like [Message.EncodeValues],
+// but the counter signal is filled with the counter of the sender,
+// which is incremented (and wrapped) after a successful encoding.
+func (s *E2ESender) EncodeValues(data []byte, values map[string]any) error {
+	if err := s.prot.encodeValues(s.msg.layout, data, values, &s.counter); err != nil {
+		return s.msg.errorf(err)
+	}
+
+	if s.counter >= s.prot.maxCounter() {
+		s.counter = 0
+	} else {
+		s.counter++
+	}
+
+	return nil
+}
+
+// E2EReceiver checks the payloads of a message protected by an [E2EProtection].
+// It keeps track of the last received counter to detect the counter jumps.
+//
+// An E2EReceiver is not safe for concurrent use.
+type E2EReceiver struct {
+	msg  *Message
+	prot *E2EProtection
+
+	hasLastCounter bool
+	lastCounter    uint64
+}
+
+// NewE2EReceiver creates a new [E2EReceiver] for the [Message].
+//
+// It returns an [ErrIsNil] if the message does not have an [E2EProtection].
+func (m *Message) NewE2EReceiver() (*E2EReceiver, error) {
+	if m.e2eProtection == nil {
+		return nil, m.errorf(ErrIsNil)
+	}
+
+	return &E2EReceiver{
+		msg:  m,
+		prot: m.e2eProtection,
+
+		hasLastCounter: false,
+		lastCounter:    0,
+	}, nil
+}
+
+// Reset forgets the last received counter,
+// so the next valid message will have the [E2EStatusInitial].
+func (r *E2EReceiver) Reset() {
+	r.hasLastCounter = false
+	r.lastCounter = 0
+}
+
+// Check checks the given payload and it returns the [E2ECheck].
+// The last received counter is updated by every message with a valid CRC and data id,
+// so the receiver synchronizes again after a wrong sequence.
+func (r *E2EReceiver) Check(data []byte) *E2ECheck {
+	layout := r.msg.layout
+	prot := r.prot
+
+	if len(data) < layout.sizeByte {
+		return &E2ECheck{Status: E2EStatusWrongCRC}
+	}
+	data = data[:layout.sizeByte]
+
+	counter := layout.decodeRawValue(data, prot.counterSignal)
+
+	check := &E2ECheck{
+		Counter:     counter,
+		ReceivedCRC: layout.decodeRawValue(data, prot.crcSignal),
+		ComputedCRC: prot.computeCRC(layout, data, counter),
+	}
+
+	if check.ReceivedCRC != check.ComputedCRC {
+		check.Status = E2EStatusWrongCRC
+		return check
+	}
+
+	if prot.dataIDSignal != nil && layout.decodeRawValue(data, prot.dataIDSignal) != prot.dataIDSignalValue() {
+		check.Status = E2EStatusWrongDataID
+		return check
+	}
+
+	maxCounter := prot.maxCounter()
+	if counter > maxCounter {
+		check.Status = E2EStatusWrongSequence
+		return check
+	}
+
+	if !r.hasLastCounter {
+		check.Status = E2EStatusInitial
+	} else {
+		if counter >= r.lastCounter {
+			check.DeltaCounter = counter - r.lastCounter
+		} else {
+			check.DeltaCounter = maxCounter + 1 + counter - r.lastCounter
+		}
+
+		switch {
+		case check.DeltaCounter == 0:
+			check.Status = E2EStatusRepeated
+		case check.DeltaCounter == 1:
+			check.Status = E2EStatusOK
+		case check.DeltaCounter <= prot.maxDeltaCounter:
+			check.Status = E2EStatusOKSomeLost
+		default:
+			check.Status = E2EStatusWrongSequence
+		}
+	}
+
+	r.hasLastCounter = true
+	r.lastCounter = counter
+
+	return check
+}
+
+// Decode checks the given payload like [E2EReceiver.Check],
+// and it decodes it like [SignalLayout.Decode].
+func (r *E2EReceiver) Decode(data []byte) ([]*SignalDecoding, *E2ECheck) {
+	return r.msg.layout.Decode(data), r.Check(data)
+}
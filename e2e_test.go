@@ -0,0 +1,260 @@
+package acmelib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_E2ECRCParams_Compute(t *testing.T) {
+	assert := assert.New(t)
+
+	// check values computed over the string "123456789"
+	data := []byte("123456789")
+
+	assert.Equal(uint64(0x37), e2eCRC8.Compute(data))
+	assert.Equal(uint64(0x4B), e2eCRC8SAEJ1850.Compute(data))
+	assert.Equal(uint64(0xDF), e2eCRC8H2F.Compute(data))
+	assert.Equal(uint64(0x29B1), e2eCRC16.Compute(data))
+	assert.Equal(uint64(0x1697D06A), e2eCRC32P4.Compute(data))
+
+	// CRC-64/XZ
+	crc64 := &E2ECRCParams{
+		Width:      64,
+		Polynomial: 0x42F0E1EBA9EA3693,
+		Init:       0xFFFFFFFFFFFFFFFF,
+		XorOut:     0xFFFFFFFFFFFFFFFF,
+		RefIn:      true,
+		RefOut:     true,
+	}
+	assert.Equal(uint64(0x995DC9BBDF1939FA), crc64.Compute(data))
+}
+
+func initE2EMessage(assert *assert.Assertions, crcSize, counterSize int) (*Message, *StandardSignal, *StandardSignal) {
+	msg := NewMessage("e2e_message", 1, 8)
+
+	crcType, err := NewIntegerSignalType("crc_t", crcSize, false)
+	assert.NoError(err)
+	crcSig, err := NewStandardSignal("crc", crcType)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(crcSig, 0))
+
+	counterType, err := NewIntegerSignalType("counter_t", counterSize, false)
+	assert.NoError(err)
+	counterSig, err := NewStandardSignal("counter", counterType)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(counterSig, crcSize))
+
+	valueType, err := NewIntegerSignalType("value_t", 16, false)
+	assert.NoError(err)
+	valueSig, err := NewStandardSignal("value", valueType)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(valueSig, 48))
+
+	return msg, crcSig, counterSig
+}
+
+func Test_Message_SetE2EProtection(t *testing.T) {
+	assert := assert.New(t)
+
+	msg, crcSig, counterSig := initE2EMessage(assert, 8, 4)
+
+	assert.NoError(msg.SetE2EProtection(NewE2EProtection(E2EProfile1, crcSig, counterSig)))
+	assert.Equal(E2EProfile1, msg.E2EProtection().Profile())
+
+	// should return an error because the CRC of profile 5 is 16 bits long
+	assert.ErrorIs(msg.SetE2EProtection(NewE2EProtection(E2EProfile5, crcSig, counterSig)), ErrInvalidValue)
+
+	// should return an error because the counter is missing
+	assert.ErrorIs(msg.SetE2EProtection(NewE2EProtection(E2EProfile1, crcSig, nil)), ErrIsNil)
+
+	// should return an error because the signal is not in the message
+	otherSig, err := NewStandardSignal("other", counterSig.Type())
+	assert.NoError(err)
+	assert.ErrorIs(msg.SetE2EProtection(NewE2EProtection(E2EProfile1, crcSig, otherSig)), ErrNotFound)
+
+	// should return an error because the profile 2 requires 16 data ids
+	p02 := NewE2EProtection(E2EProfile2, crcSig, counterSig)
+	assert.ErrorIs(msg.SetE2EProtection(p02), ErrInvalidValue)
+	p02.SetDataIDList(make([]uint8, 16))
+	assert.NoError(msg.SetE2EProtection(p02))
+
+	// should return an error because the data id is too big
+	p01 := NewE2EProtection(E2EProfile1, crcSig, counterSig)
+	p01.SetDataID(0x10000)
+	assert.ErrorIs(msg.SetE2EProtection(p01), ErrOutOfBounds)
+
+	// should return an error because the custom CRC is missing or invalid
+	custom := NewE2EProtection(E2EProfileCustom, crcSig, counterSig)
+	assert.ErrorIs(msg.SetE2EProtection(custom), ErrIsNil)
+	custom.SetCustomCRC(&E2ECRCParams{Width: 12, Polynomial: 0x80F})
+	assert.ErrorIs(msg.SetE2EProtection(custom), ErrInvalidValue)
+	custom.SetCustomCRC(&E2ECRCParams{Width: 8, Polynomial: 0x07})
+	assert.NoError(msg.SetE2EProtection(custom))
+
+	// should return an error because the CRC is not byte aligned
+	msg, crcSig, counterSig = initE2EMessage(assert, 8, 4)
+	assert.NoError(crcSig.UpdateStartPos(20))
+	assert.ErrorIs(msg.SetE2EProtection(NewE2EProtection(E2EProfile1, crcSig, counterSig)), ErrInvalidValue)
+
+	// should remove the protection when a signal is deleted
+	msg, crcSig, counterSig = initE2EMessage(assert, 8, 4)
+	assert.NoError(msg.SetE2EProtection(NewE2EProtection(E2EProfile1, crcSig, counterSig)))
+	assert.NoError(msg.DeleteSignal(counterSig.EntityID()))
+	assert.Nil(msg.E2EProtection())
+}
+
+func Test_E2EReceiver_Check(t *testing.T) {
+	assert := assert.New(t)
+
+	msg, crcSig, counterSig := initE2EMessage(assert, 8, 4)
+
+	prot := NewE2EProtection(E2EProfile1, crcSig, counterSig)
+	prot.SetDataID(0x1234)
+	prot.SetMaxDeltaCounter(2)
+	assert.NoError(msg.SetE2EProtection(prot))
+
+	sender, err := msg.NewE2ESender()
+	assert.NoError(err)
+	receiver, err := msg.NewE2EReceiver()
+	assert.NoError(err)
+
+	values := map[string]any{"value": 1000}
+	data := make([]byte, 8)
+
+	// should fill the CRC over the data id and the payload
+	assert.NoError(sender.EncodeValues(data, values))
+	assert.Equal(uint64(1), sender.Counter())
+	expectedCRC := e2eCRC8.Compute([]byte{0x34, 0x12, data[1], data[2], data[3], data[4], data[5], data[6], data[7]})
+	assert.Equal(expectedCRC, uint64(data[0]))
+
+	decodings, check := receiver.Decode(data)
+	assert.Len(decodings, 3)
+	assert.Equal(E2EStatusInitial, check.Status)
+	assert.Equal(check.ComputedCRC, check.ReceivedCRC)
+
+	// should detect a repetition
+	assert.Equal(E2EStatusRepeated, receiver.Check(data).Status)
+
+	assert.NoError(sender.EncodeValues(data, values))
+	check = receiver.Check(data)
+	assert.Equal(E2EStatusOK, check.Status)
+	assert.Equal(uint64(1), check.Counter)
+	assert.Equal(uint64(1), check.DeltaCounter)
+
+	// should detect a lost message
+	assert.NoError(sender.EncodeValues(data, values))
+	assert.NoError(sender.EncodeValues(data, values))
+	check = receiver.Check(data)
+	assert.Equal(E2EStatusOKSomeLost, check.Status)
+	assert.Equal(uint64(2), check.DeltaCounter)
+
+	// should detect a counter jump beyond the max delta
+	for range 3 {
+		assert.NoError(sender.EncodeValues(data, values))
+	}
+	assert.Equal(E2EStatusWrongSequence, receiver.Check(data).Status)
+
+	// should detect a corrupted payload
+	assert.NoError(sender.EncodeValues(data, values))
+	data[7] ^= 0x01
+	check = receiver.Check(data)
+	assert.Equal(E2EStatusWrongCRC, check.Status)
+	assert.False(check.Status.IsValid())
+
+	// should wrap the counter after 14
+	for range 20 {
+		assert.NoError(sender.EncodeValues(data, values))
+		assert.LessOrEqual(sender.Counter(), uint64(14))
+		assert.True(receiver.Check(data).Status.IsValid())
+	}
+
+	// should encode the counter value when not using the sender
+	assert.NoError(msg.EncodeValues(data, map[string]any{"value": 1000, "counter": 5}))
+	assert.Equal(uint64(5), receiver.Check(data).Counter)
+	assert.ErrorIs(msg.EncodeValues(data, map[string]any{"value": 1000, "counter": 15}), ErrOutOfBounds)
+}
+
+func Test_E2EReceiver_Check_Profile4(t *testing.T) {
+	assert := assert.New(t)
+
+	msg := NewMessage("e2e_message", 1, 16)
+
+	newSig := func(name string, size, startPos int) *StandardSignal {
+		sigType, err := NewIntegerSignalType(name+"_t", size, false)
+		assert.NoError(err)
+		sig, err := NewStandardSignal(name, sigType)
+		assert.NoError(err)
+		sig.SetEndianness(EndiannessBigEndian)
+		assert.NoError(msg.InsertSignal(sig, startPos))
+		return sig
+	}
+
+	lengthSig := newSig("length", 16, 0)
+	counterSig := newSig("counter", 16, 16)
+	dataIDSig := newSig("data_id", 32, 32)
+	crcSig := newSig("crc", 32, 64)
+	newSig("value", 32, 96)
+
+	prot := NewE2EProtection(E2EProfile4, crcSig, counterSig)
+	prot.SetDataID(0x0A0B0C0D)
+	prot.SetLengthSignal(lengthSig)
+
+	// should return an error because the data id would not be protected
+	err := msg.SetE2EProtection(prot)
+	assert.ErrorIs(err, ErrIsNil)
+	var argErr *ArgError
+	assert.ErrorAs(err, &argErr)
+	assert.Equal("data_id_signal", argErr.Name)
+
+	prot.SetDataIDSignal(dataIDSig)
+	assert.NoError(msg.SetE2EProtection(prot))
+
+	data := make([]byte, 16)
+	assert.NoError(msg.EncodeValues(data, map[string]any{"value": 42, "counter": 300}))
+
+	// should fill the length and the data id
+	assert.Equal([]byte{0x00, 0x10, 0x01, 0x2C, 0x0A, 0x0B, 0x0C, 0x0D}, data[:8])
+
+	receiver, err := msg.NewE2EReceiver()
+	assert.NoError(err)
+
+	check := receiver.Check(data)
+	assert.Equal(E2EStatusInitial, check.Status)
+	assert.Equal(uint64(300), check.Counter)
+
+	// should detect a wrong data id
+	prot.SetDataID(0x0A0B0C0E)
+	assert.Equal(E2EStatusWrongDataID, receiver.Check(data).Status)
+}
+
+func Test_E2EReceiver_Check_Profile2(t *testing.T) {
+	assert := assert.New(t)
+
+	msg, crcSig, counterSig := initE2EMessage(assert, 8, 4)
+
+	dataIDList := make([]uint8, 16)
+	for idx := range dataIDList {
+		dataIDList[idx] = uint8(idx * 3)
+	}
+
+	prot := NewE2EProtection(E2EProfile2, crcSig, counterSig)
+	prot.SetDataIDList(dataIDList)
+	assert.NoError(msg.SetE2EProtection(prot))
+
+	sender, err := msg.NewE2ESender()
+	assert.NoError(err)
+	receiver, err := msg.NewE2EReceiver()
+	assert.NoError(err)
+
+	data := make([]byte, 8)
+
+	// should wrap the counter after 15
+	for idx := range 20 {
+		assert.NoError(sender.EncodeValues(data, map[string]any{"value": idx}))
+
+		check := receiver.Check(data)
+		assert.True(check.Status.IsValid())
+		assert.Equal(uint64(idx%16), check.Counter)
+	}
+}
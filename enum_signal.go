@@ -113,6 +113,34 @@ func (es *EnumSignal) UpdateEncodedValue(value int) error {
 	return nil
 }
 
+// encodeValue returns the raw value of the given enum value.
+// The value can be the name of a [SignalEnumValue] or its index.
+//
+// It returns an [ErrInvalidType] if the value is neither a string nor an integer,
+// or an [ErrNotFound] if the value does not match any enum value.
+func (es *EnumSignal) encodeValue(value any) (uint64, error) {
+	if name, ok := value.(string); ok {
+		for _, enumVal := range es.enum.values {
+			if enumVal.name == name {
+				return uint64(enumVal.index), nil
+			}
+		}
+
+		return 0, ErrNotFound
+	}
+
+	index, ok := valueToInt(value)
+	if !ok {
+		return 0, ErrInvalidType
+	}
+
+	if !es.enum.valueIndexes.Has(index) {
+		return 0, ErrNotFound
+	}
+
+	return uint64(index), nil
+}
+
 // ToSignal returns the signal itself.
 func (es *EnumSignal) ToSignal() (Signal, error) {
 	return es, nil
@@ -44,6 +44,12 @@ var ErrNotClear = errors.New("not clear")
 // ErrIsDifferent is returned when a value is different.
 var ErrIsDifferent = errors.New("is different")
 
+// ErrInvalidValue is returned when a value is not one of the allowed ones.
+var ErrInvalidValue = errors.New("invalid value")
+
+// ErrIsMissing is returned when a value is missing.
+var ErrIsMissing = errors.New("is missing")
+
 // ErrInvalidOneof is returned when a oneof field does not match
 // a kind/type field.
 type ErrInvalidOneof struct {
@@ -341,3 +347,55 @@ func (e *MessageIDError) Error() string {
 }
 
 func (e *MessageIDError) Unwrap() error { return e.Err }
+
+// SignalValueError is returned when the value of a signal is missing or invalid.
+// The Name field is the name of the signal and the Err field is the cause.
+type SignalValueError struct {
+	Name string
+	Err  error
+}
+
+func newSignalValueError(name string, err error) *SignalValueError {
+	return &SignalValueError{Name: name, Err: err}
+}
+
+func (e *SignalValueError) Error() string {
+	return fmt.Sprintf("signal value error; name:%q : %v", e.Name, e.Err)
+}
+
+func (e *SignalValueError) Unwrap() error { return e.Err }
+
+// J1939TPError is returned when a J1939 transport protocol session fails.
+// The SourceAddress and DestinationAddress fields identify the session and the Err field is the cause.
+type J1939TPError struct {
+	SourceAddress      uint8
+	DestinationAddress uint8
+	Err                error
+}
+
+func newJ1939TPError(srcAddr, destAddr uint8, err error) *J1939TPError {
+	return &J1939TPError{SourceAddress: srcAddr, DestinationAddress: destAddr, Err: err}
+}
+
+func (e *J1939TPError) Error() string {
+	return fmt.Sprintf("j1939 tp error; source_address:%d; destination_address:%d : %v", e.SourceAddress, e.DestinationAddress, e.Err)
+}
+
+func (e *J1939TPError) Unwrap() error { return e.Err }
+
+// J1939PGNError is returned when a [J1939PGN] is invalid.
+// The PGN field is the parameter group number and the Err field is the cause.
+type J1939PGNError struct {
+	PGN J1939PGN
+	Err error
+}
+
+func newJ1939PGNError(pgn J1939PGN, err error) *J1939PGNError {
+	return &J1939PGNError{PGN: pgn, Err: err}
+}
+
+func (e *J1939PGNError) Error() string {
+	return fmt.Sprintf("j1939 pgn error; pgn:%d : %v", e.PGN, e.Err)
+}
+
+func (e *J1939PGNError) Unwrap() error { return e.Err }
@@ -0,0 +1,245 @@
+package acmelib
+
+import (
+	"io"
+	"iter"
+	"time"
+)
+
+// FrameDirection is the direction of a [Frame]
+// relative to the interface that received or sent it.
+type FrameDirection int
+
+const (
+	// FrameDirectionRx defines a received frame.
+	FrameDirectionRx FrameDirection = iota
+	// FrameDirectionTx defines a transmitted frame.
+	FrameDirectionTx
+)
+
+func (fd FrameDirection) String() string {
+	switch fd {
+	case FrameDirectionRx:
+		return "rx"
+	case FrameDirectionTx:
+		return "tx"
+	default:
+		return "unknown"
+	}
+}
+
+// Frame is a CAN frame exchanged on a bus, as read from a log or
+// as received from (or sent to) an interface.
+type Frame struct {
+	// Timestamp is the time at which the frame was received or sent.
+	// It may be zero when the source does not provide it.
+	Timestamp time.Time
+	// Channel is the name of the interface or of the channel of the frame.
+	Channel string
+	// Direction is the direction of the frame.
+	Direction FrameDirection
+
+	// CANID is the CAN-ID of the frame, without any flag.
+	// For error frames it holds the error class.
+	CANID CANID
+	// IsExtended reports whether the frame uses an extended (29 bits) CAN-ID.
+	IsExtended bool
+	// IsRemote reports whether the frame is a remote transmission request.
+	IsRemote bool
+	// IsError reports whether the frame is an error frame.
+	IsError bool
+
+	// IsFD reports whether the frame is a CAN FD frame.
+	IsFD bool
+	// BitRateSwitch reports whether the data phase of a CAN FD frame
+	// is sent at the data baudrate.
+	BitRateSwitch bool
+	// ErrorStateIndicator reports whether the sender of a CAN FD frame
+	// is in the error passive state.
+	ErrorStateIndicator bool
+
+	// Data is the payload of the frame.
+	// For remote frames it is empty and its length is given by RemoteLength.
+	Data []byte
+	// RemoteLength is the requested length of a remote frame.
+	RemoteLength int
+}
+
+// CANIDFormat returns the [CANIDFormat] of the frame.
+func (f *Frame) CANIDFormat() CANIDFormat {
+	if f.IsExtended {
+		return CANIDFormatExtended
+	}
+	return CANIDFormatStandard
+}
+
+// FrameSource is a source of frames, like a log or an interface.
+type FrameSource interface {
+	// Read returns the next frame of the source.
+	// It returns [io.EOF] when there are no more frames.
+	Read() (*Frame, error)
+}
+
+// FrameSink is a destination of frames, like a log or an interface.
+type FrameSink interface {
+	// WriteFrame writes the given frame to the sink.
+	WriteFrame(frame *Frame) error
+}
+
+// ReadFrames returns an iterator over the frames read from the given source,
+// which stops at [io.EOF] or after the first error, which is yielded.
+// It can be passed to [Decoder.DecodeFrames] to decode the frames of an interface.
+func ReadFrames(src FrameSource) iter.Seq2[*Frame, error] {
+	return func(yield func(*Frame, error) bool) {
+		for {
+			frame, err := src.Read()
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(frame, nil) {
+				return
+			}
+		}
+	}
+}
+
+// WriteFrames writes the frames of the given iterator to the given sink.
+// It stops at the first error, either of the iterator or of the sink.
+func WriteFrames(sink FrameSink, frames iter.Seq2[*Frame, error]) error {
+	for frame, err := range frames {
+		if err != nil {
+			return err
+		}
+
+		if err := sink.WriteFrame(frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FrameDecoding is a [Frame] decoded by [Decoder.DecodeFrames].
+type FrameDecoding struct {
+	// Frame is the decoded frame, which holds the timestamp.
+	Frame *Frame
+	// Message is the message that owns the CAN-ID of the frame.
+	Message *Message
+	// Signals are the decoded signals of the message.
+	Signals []*SignalDecoding
+}
+
+// DecodeFrames returns an iterator that decodes the frames of the given iterator,
+// like a log or an interface. It yields a [FrameDecoding] for each data frame
+// that matches the CAN-ID and the format of a message of the decoder,
+// while the remote frames, the error frames
+// and the frames with an unknown CAN-ID are skipped.
+//
+// The errors of the given iterator are yielded with a nil decoding.
+// When the length of the data is different from the size of the message,
+// both the decoding and a [SizeError] are yielded (see [Decoder.DecodeFrame]).
+func (d *Decoder) DecodeFrames(frames iter.Seq2[*Frame, error]) iter.Seq2[*FrameDecoding, error] {
+	return decodeFrames(frames, func(_ *Frame) *Decoder { return d })
+}
+
+// decodeFrames decodes the given frames with the decoder returned by the getter.
+// The frames without a decoder are skipped.
+func decodeFrames(frames iter.Seq2[*Frame, error], getDecoder func(frame *Frame) *Decoder) iter.Seq2[*FrameDecoding, error] {
+	return func(yield func(*FrameDecoding, error) bool) {
+		for frame, err := range frames {
+			if err != nil {
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+
+			if frame.IsError || frame.IsRemote {
+				continue
+			}
+
+			d := getDecoder(frame)
+			if d == nil {
+				continue
+			}
+
+			msg, ok := d.messages.Get(decoderKey{canID: frame.CANID, format: frame.CANIDFormat()})
+			if !ok {
+				continue
+			}
+
+			_, decodings, err := d.decode(msg, frame.Data)
+			if !yield(&FrameDecoding{Frame: frame, Message: msg, Signals: decodings}, err) {
+				return
+			}
+		}
+	}
+}
+
+// ChannelDecoder decodes the frames of more buses, like the ones of a multi-channel log,
+// by using the [Decoder] of the [Bus] mapped to the channel of each frame.
+// It is built by [Network.NewChannelDecoder].
+//
+// A ChannelDecoder does not modify the model, so it can be used concurrently.
+type ChannelDecoder struct {
+	decoders map[string]*Decoder
+}
+
+// NewChannelDecoder returns a [ChannelDecoder] for the buses of the network.
+// The given map associates the channel of the frames to the name of a bus.
+//
+// It returns:
+//   - [NameError] if a bus name does not match any bus of the network.
+//   - [CANIDError] if two messages of the same bus have the same CAN-ID.
+func (n *Network) NewChannelDecoder(channels map[string]string) (*ChannelDecoder, error) {
+	decoders := make(map[string]*Decoder, len(channels))
+
+	for channel, busName := range channels {
+		busEntID, ok := n.busNames.Get(busName)
+		if !ok {
+			return nil, n.errorf(newNameError(busName, ErrNotFound))
+		}
+
+		bus, ok := n.buses.Get(busEntID)
+		if !ok {
+			return nil, n.errorf(newNameError(busName, ErrNotFound))
+		}
+
+		dec, err := bus.NewDecoder()
+		if err != nil {
+			return nil, err
+		}
+
+		decoders[channel] = dec
+	}
+
+	return &ChannelDecoder{
+		decoders: decoders,
+	}, nil
+}
+
+// GetDecoder returns the [Decoder] of the bus mapped to the given channel.
+//
+// It returns an [ErrNotFound] if the channel is not mapped to any bus.
+func (cd *ChannelDecoder) GetDecoder(channel string) (*Decoder, error) {
+	dec, ok := cd.decoders[channel]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return dec, nil
+}
+
+// DecodeFrames returns an iterator that decodes the frames of the given iterator
+// with the decoder of their channel. The frames of the channels that are not mapped
+// to any bus are skipped. See [Decoder.DecodeFrames] for the other rules.
+func (cd *ChannelDecoder) DecodeFrames(frames iter.Seq2[*Frame, error]) iter.Seq2[*FrameDecoding, error] {
+	return decodeFrames(frames, func(frame *Frame) *Decoder {
+		return cd.decoders[frame.Channel]
+	})
+}
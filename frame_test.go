@@ -0,0 +1,169 @@
+package acmelib
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Decoder_DecodeFrames(t *testing.T) {
+	assert := assert.New(t)
+
+	tdNet := initNetwork(assert)
+
+	dec, err := tdNet.bus.NewDecoder()
+	assert.NoError(err)
+
+	basicMsg := tdNet.messages.basic.message
+	canID := basicMsg.GetCANID()
+	srcErr := errors.New("source error")
+
+	frames := []*Frame{
+		{CANID: canID, Data: make([]byte, 8)},
+		{CANID: canID, IsRemote: true},
+		{CANID: canID, IsError: true, Data: make([]byte, 8)},
+		{CANID: 0x7FF, Data: make([]byte, 8)},
+		{CANID: canID, IsExtended: true, Data: make([]byte, 8)},
+		nil,
+		{CANID: canID, Data: make([]byte, 4)},
+	}
+
+	source := func(yield func(*Frame, error) bool) {
+		for _, frame := range frames {
+			var err error
+			if frame == nil {
+				err = srcErr
+			}
+
+			if !yield(frame, err) {
+				return
+			}
+		}
+	}
+
+	// should skip the remote, error and unknown frames,
+	// including the extended frame with the CAN-ID of a standard message
+	decodings := []*FrameDecoding{}
+	errs := []error{}
+	for frameDec, err := range dec.DecodeFrames(source) {
+		decodings = append(decodings, frameDec)
+		errs = append(errs, err)
+	}
+
+	assert.Len(decodings, 3)
+
+	assert.NoError(errs[0])
+	assert.Equal(basicMsg.EntityID(), decodings[0].Message.EntityID())
+	assert.Equal(frames[0], decodings[0].Frame)
+	assert.Len(decodings[0].Signals, 4)
+
+	// should yield the errors of the source
+	assert.Nil(decodings[1])
+	assert.ErrorIs(errs[1], srcErr)
+
+	// should yield the decoding together with the size error
+	assert.NotNil(decodings[2])
+	assert.ErrorIs(errs[2], ErrTooSmall)
+
+	// should stop when the consumer breaks
+	count := 0
+	for range dec.DecodeFrames(source) {
+		count++
+		break
+	}
+	assert.Equal(1, count)
+}
+
+func Test_Network_NewChannelDecoder(t *testing.T) {
+	assert := assert.New(t)
+
+	tdNet := initNetwork(assert)
+
+	basicMsg := tdNet.messages.basic.message
+	canID := basicMsg.GetCANID()
+
+	chDec, err := tdNet.net.NewChannelDecoder(map[string]string{"1": tdNet.bus.Name()})
+	assert.NoError(err)
+
+	dec, err := chDec.GetDecoder("1")
+	assert.NoError(err)
+	assert.NotNil(dec)
+
+	// should return an error if the channel is not mapped
+	_, err = chDec.GetDecoder("2")
+	assert.ErrorIs(err, ErrNotFound)
+
+	// should skip the frames of the channels that are not mapped
+	frames := []*Frame{
+		{Channel: "1", CANID: canID, Data: make([]byte, 8)},
+		{Channel: "2", CANID: canID, Data: make([]byte, 8)},
+	}
+
+	source := func(yield func(*Frame, error) bool) {
+		for _, frame := range frames {
+			if !yield(frame, nil) {
+				return
+			}
+		}
+	}
+
+	decodings := []*FrameDecoding{}
+	for frameDec, err := range chDec.DecodeFrames(source) {
+		assert.NoError(err)
+		decodings = append(decodings, frameDec)
+	}
+
+	assert.Len(decodings, 1)
+	assert.Equal(frames[0], decodings[0].Frame)
+	assert.Equal(basicMsg.EntityID(), decodings[0].Message.EntityID())
+
+	// should return an error if the bus does not exist
+	_, err = tdNet.net.NewChannelDecoder(map[string]string{"1": "unknown_bus"})
+	assert.ErrorIs(err, ErrNotFound)
+}
+
+type testFrameSource struct {
+	frames []*Frame
+}
+
+func (s *testFrameSource) Read() (*Frame, error) {
+	if len(s.frames) == 0 {
+		return nil, io.EOF
+	}
+
+	frame := s.frames[0]
+	s.frames = s.frames[1:]
+	if frame == nil {
+		return nil, errors.New("source error")
+	}
+
+	return frame, nil
+}
+
+type testFrameSink struct {
+	frames []*Frame
+}
+
+func (s *testFrameSink) WriteFrame(frame *Frame) error {
+	s.frames = append(s.frames, frame)
+	return nil
+}
+
+func Test_ReadFrames(t *testing.T) {
+	assert := assert.New(t)
+
+	frames := []*Frame{{CANID: 1}, {CANID: 2}}
+
+	// should read the frames until the end of the source
+	sink := &testFrameSink{}
+	assert.NoError(WriteFrames(sink, ReadFrames(&testFrameSource{frames: frames})))
+	assert.Equal(frames, sink.frames)
+
+	// should stop at the first error of the source
+	sink = &testFrameSink{}
+	src := &testFrameSource{frames: []*Frame{{CANID: 1}, nil, {CANID: 2}}}
+	assert.Error(WriteFrames(sink, ReadFrames(src)))
+	assert.Len(sink.frames, 1)
+}
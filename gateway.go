@@ -0,0 +1,784 @@
+package acmelib
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// GatewaySignalMapping maps a signal of the source message of a [GatewayRoute]
+// to a signal of the target message.
+type GatewaySignalMapping struct {
+	sourceSignal Signal
+	targetSignal Signal
+}
+
+// SourceSignal returns the signal read from the source message.
+func (gsm *GatewaySignalMapping) SourceSignal() Signal {
+	return gsm.sourceSignal
+}
+
+// TargetSignal returns the signal written into the target message.
+func (gsm *GatewaySignalMapping) TargetSignal() Signal {
+	return gsm.targetSignal
+}
+
+// GatewayRoute describes how a gateway [Node] forwards a [Message]
+// received on a [Bus] as another message sent on a different bus.
+//
+// A route without signal mappings is a frame route: the payload of the source
+// message is copied as it is, so the two messages must have the same layout.
+// Otherwise, it is a signal route: the physical value of every mapped source signal
+// is written into the target signal, while the other signals of the target message
+// keep their start value. Many signal routes can fill the same target message,
+// as long as they map different target signals.
+//
+// The settings are verified when the route is added to a node by calling
+// [Node.AddGatewayRoute], so they should not be modified after.
+type GatewayRoute struct {
+	sourceMessage *Message
+	targetMessage *Message
+
+	signalMappings []*GatewaySignalMapping
+
+	processingTime time.Duration
+}
+
+// NewGatewayRoute creates a new [GatewayRoute] that forwards
+// the source message as the target message.
+func NewGatewayRoute(sourceMessage, targetMessage *Message) *GatewayRoute {
+	return &GatewayRoute{
+		sourceMessage: sourceMessage,
+		targetMessage: targetMessage,
+
+		signalMappings: []*GatewaySignalMapping{},
+	}
+}
+
+// SourceMessage returns the message received by the gateway.
+func (gr *GatewayRoute) SourceMessage() *Message {
+	return gr.sourceMessage
+}
+
+// TargetMessage returns the message sent by the gateway.
+func (gr *GatewayRoute) TargetMessage() *Message {
+	return gr.targetMessage
+}
+
+// AddSignalMapping maps the given signal of the source message
+// to the given signal of the target message.
+func (gr *GatewayRoute) AddSignalMapping(sourceSignal, targetSignal Signal) {
+	gr.signalMappings = append(gr.signalMappings, &GatewaySignalMapping{
+		sourceSignal: sourceSignal,
+		targetSignal: targetSignal,
+	})
+}
+
+// SignalMappings returns the signal mappings of the route.
+func (gr *GatewayRoute) SignalMappings() []*GatewaySignalMapping {
+	return gr.signalMappings
+}
+
+// IsFrameRoute returns whether the route copies the whole payload,
+// because it does not have any signal mapping.
+func (gr *GatewayRoute) IsFrameRoute() bool {
+	return len(gr.signalMappings) == 0
+}
+
+// SetProcessingTime sets the time the gateway needs to forward the message.
+func (gr *GatewayRoute) SetProcessingTime(processingTime time.Duration) {
+	gr.processingTime = processingTime
+}
+
+// ProcessingTime returns the time the gateway needs to forward the message.
+func (gr *GatewayRoute) ProcessingTime() time.Duration {
+	return gr.processingTime
+}
+
+// getMessageBus returns the bus where the given message is sent, or nil.
+func getMessageBus(msg *Message) *Bus {
+	if !msg.hasSenderNodeInt() || !msg.senderNodeInt.hasParentBus() {
+		return nil
+	}
+	return msg.senderNodeInt.parentBus
+}
+
+// verify checks whether the route can be added to the given node.
+func (gr *GatewayRoute) verify(node *Node) error {
+	if gr.sourceMessage == nil {
+		return newArgError("source_message", ErrIsNil)
+	}
+
+	if gr.targetMessage == nil {
+		return newArgError("target_message", ErrIsNil)
+	}
+
+	if gr.processingTime < 0 {
+		return newArgError("processing_time", ErrIsNegative)
+	}
+
+	// the node must receive the source message from its interface on the source bus
+	srcBus := getMessageBus(gr.sourceMessage)
+	if srcBus == nil {
+		return newArgError("source_message", ErrNotFound)
+	}
+
+	receiver, ok := gr.sourceMessage.receivers.Get(node.entityID)
+	if !ok || receiver.parentBus != srcBus {
+		return newArgError("source_message", ErrNotFound)
+	}
+
+	// the target message must be sent by the node on another bus
+	tgtBus := getMessageBus(gr.targetMessage)
+	if tgtBus == nil || gr.targetMessage.senderNodeInt.node != node {
+		return newArgError("target_message", ErrNotFound)
+	}
+
+	if srcBus == tgtBus {
+		return newArgError("target_message", ErrInvalidValue)
+	}
+
+	tgtSignals := make(map[EntityID]bool)
+	for _, route := range node.gatewayRoutes {
+		if route.targetMessage != gr.targetMessage {
+			continue
+		}
+
+		if route.sourceMessage == gr.sourceMessage {
+			return newArgError("route", ErrIsDuplicated)
+		}
+
+		// a frame route owns the whole payload of the target message
+		if route.IsFrameRoute() || gr.IsFrameRoute() {
+			return newArgError("target_message", ErrIsDuplicated)
+		}
+
+		for _, mapping := range route.signalMappings {
+			tgtSignals[mapping.targetSignal.EntityID()] = true
+		}
+	}
+
+	if gr.IsFrameRoute() {
+		if err := verifyGatewayLayouts(gr.sourceMessage.layout, gr.targetMessage.layout); err != nil {
+			return newArgError("target_message", err)
+		}
+		return nil
+	}
+
+	for _, mapping := range gr.signalMappings {
+		if err := gr.verifySignalMapping(mapping); err != nil {
+			return err
+		}
+
+		tgtSigID := mapping.targetSignal.EntityID()
+		if tgtSignals[tgtSigID] {
+			return newArgError("target_signal", newNameError(mapping.targetSignal.Name(), ErrIsDuplicated))
+		}
+		tgtSignals[tgtSigID] = true
+	}
+
+	return nil
+}
+
+// verifySignalMapping checks whether the source signal of the mapping
+// can be written into the target signal.
+func (gr *GatewayRoute) verifySignalMapping(mapping *GatewaySignalMapping) error {
+	srcSig := mapping.sourceSignal
+	tgtSig := mapping.targetSignal
+
+	if srcSig == nil {
+		return newArgError("source_signal", ErrIsNil)
+	}
+
+	if tgtSig == nil {
+		return newArgError("target_signal", ErrIsNil)
+	}
+
+	// the signals must be in the top level layout of the messages
+	if _, ok := gr.sourceMessage.signals.Get(srcSig.EntityID()); !ok {
+		return newArgError("source_signal", newNameError(srcSig.Name(), ErrNotFound))
+	}
+
+	if _, ok := gr.targetMessage.signals.Get(tgtSig.EntityID()); !ok {
+		return newArgError("target_signal", newNameError(tgtSig.Name(), ErrNotFound))
+	}
+
+	if srcSig.Kind() != tgtSig.Kind() || srcSig.Kind() == SignalKindMuxor {
+		return newArgError("target_signal", newNameError(tgtSig.Name(), ErrInvalidType))
+	}
+
+	switch srcSig.Kind() {
+	case SignalKindStandard:
+		srcStdSig, err := srcSig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		tgtStdSig, err := tgtSig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		srcType := srcStdSig.typ
+		tgtType := tgtStdSig.typ
+
+		isSrcFlag := srcType.kind == SignalTypeKindFlag
+		isTgtFlag := tgtType.kind == SignalTypeKindFlag
+		if isSrcFlag != isTgtFlag {
+			return newArgError("target_signal", newNameError(tgtSig.Name(), ErrInvalidType))
+		}
+
+		// every value of the source signal must be representable by the target one
+		if srcType.min < tgtType.min || srcType.max > tgtType.max {
+			return newArgError("target_signal", newNameError(tgtSig.Name(), ErrOutOfBounds))
+		}
+
+	case SignalKindEnum:
+		srcEnumSig, err := srcSig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		tgtEnumSig, err := tgtSig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		// the values are mapped by name
+		for _, srcVal := range srcEnumSig.enum.values {
+			found := false
+			for _, tgtVal := range tgtEnumSig.enum.values {
+				if tgtVal.name == srcVal.name {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return newArgError("target_signal", newNameError(tgtSig.Name(), ErrInvalidValue))
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyGatewayLayouts checks whether the payload of the source layout
+// can be read with the target layout: they must have the same size
+// and the same signals, with the same types and enums,
+// including the ones of the multiplexed layers.
+func verifyGatewayLayouts(srcLayout, tgtLayout *SignalLayout) error {
+	if srcLayout.sizeByte != tgtLayout.sizeByte {
+		return newSizeError(tgtLayout.sizeByte, ErrIsDifferent)
+	}
+
+	srcSignals := srcLayout.Signals()
+	tgtSignals := tgtLayout.Signals()
+	if len(srcSignals) != len(tgtSignals) {
+		return ErrIsDifferent
+	}
+
+	for idx, srcSig := range srcSignals {
+		tgtSig := tgtSignals[idx]
+
+		if srcSig.Name() != tgtSig.Name() || srcSig.StartPos() != tgtSig.StartPos() ||
+			srcSig.Size() != tgtSig.Size() || srcSig.Endianness() != tgtSig.Endianness() ||
+			srcSig.Kind() != tgtSig.Kind() || !hasSameGatewayEncoding(srcSig, tgtSig) {
+			return newNameError(tgtSig.Name(), ErrIsDifferent)
+		}
+	}
+
+	srcMuxLayers := srcLayout.MultiplexedLayers()
+	tgtMuxLayers := tgtLayout.MultiplexedLayers()
+	if len(srcMuxLayers) != len(tgtMuxLayers) {
+		return ErrIsDifferent
+	}
+
+	for idx, srcMuxLayer := range srcMuxLayers {
+		tgtMuxLayer := tgtMuxLayers[idx]
+
+		if srcMuxLayer.GetLayoutCount() != tgtMuxLayer.GetLayoutCount() {
+			return newNameError(tgtMuxLayer.muxor.Name(), ErrIsDifferent)
+		}
+
+		for layoutID, srcMuxLayout := range srcMuxLayer.iterLayouts() {
+			if err := verifyGatewayLayouts(srcMuxLayout, tgtMuxLayer.GetLayout(layoutID)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasSameGatewayEncoding returns whether the raw values of the given signals,
+// which must be of the same kind, have the same meaning: the standard signals
+// must have the same type and the enum signals the same values.
+func hasSameGatewayEncoding(srcSig, tgtSig Signal) bool {
+	switch srcSig.Kind() {
+	case SignalKindStandard:
+		srcStdSig, err := srcSig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		tgtStdSig, err := tgtSig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		srcType := srcStdSig.typ
+		tgtType := tgtStdSig.typ
+
+		return srcType.kind == tgtType.kind && srcType.signed == tgtType.signed &&
+			srcType.scale == tgtType.scale && srcType.offset == tgtType.offset
+
+	case SignalKindEnum:
+		srcEnumSig, err := srcSig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		tgtEnumSig, err := tgtSig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		srcValues := srcEnumSig.enum.values
+		tgtValues := tgtEnumSig.enum.values
+		if len(srcValues) != len(tgtValues) {
+			return false
+		}
+
+		for idx, srcVal := range srcValues {
+			tgtVal := tgtValues[idx]
+			if srcVal.index != tgtVal.index || srcVal.name != tgtVal.name {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// AddGatewayRoute adds the given [GatewayRoute] to the [Node].
+// The node must be a receiver of the source message through its interface
+// on the source bus, and it must send the target message on a different bus.
+//
+// It returns:
+//   - [ArgError] if the route is nil or if it is not valid.
+//   - [ErrIsDuplicated] wrapped by an [ArgError] if the route is already present,
+//     or if it writes a target signal already written by another route.
+//   - [ErrIsDifferent] wrapped by an [ArgError] if the route copies the payload
+//     and the layouts of the messages are not compatible.
+//   - [ErrInvalidType], [ErrOutOfBounds] or [ErrInvalidValue] wrapped by an [ArgError]
+//     if a source signal cannot be written into its target signal.
+func (n *Node) AddGatewayRoute(route *GatewayRoute) error {
+	if route == nil {
+		return n.errorf(newArgError("route", ErrIsNil))
+	}
+
+	if err := route.verify(n); err != nil {
+		return n.errorf(err)
+	}
+
+	n.gatewayRoutes = append(n.gatewayRoutes, route)
+
+	return nil
+}
+
+// RemoveGatewayRoute removes the [GatewayRoute] between the messages
+// with the given entity ids from the [Node].
+//
+// It returns an [ErrNotFound] if the route is not found.
+func (n *Node) RemoveGatewayRoute(sourceMessageEntityID, targetMessageEntityID EntityID) error {
+	for idx, route := range n.gatewayRoutes {
+		if route.sourceMessage.entityID == sourceMessageEntityID && route.targetMessage.entityID == targetMessageEntityID {
+			n.gatewayRoutes = slices.Delete(n.gatewayRoutes, idx, idx+1)
+			return nil
+		}
+	}
+
+	return n.errorf(ErrNotFound)
+}
+
+// GatewayRoutes returns the gateway routes of the [Node].
+func (n *Node) GatewayRoutes() []*GatewayRoute {
+	return n.gatewayRoutes
+}
+
+// gatewayRouterKey identifies a received frame.
+type gatewayRouterKey struct {
+	busEntityID EntityID
+	canID       CANID
+	isExtended  bool
+}
+
+// gatewayRouterRoute is a route applied by a [GatewayRouter].
+type gatewayRouterRoute struct {
+	route   *GatewayRoute
+	channel string
+	values  *messageValues
+}
+
+// GatewayRouter applies the gateway routes of a [Node] to a stream of frames.
+// It is built by [Node.NewGatewayRouter] and it holds an index of the routes
+// at the time of creation. Therefore, it must be recreated when a route is changed,
+// or when the CAN-ID of a source message changes.
+//
+// A GatewayRouter can be used concurrently.
+type GatewayRouter struct {
+	busIDs map[string]EntityID
+	routes map[gatewayRouterKey][]*gatewayRouterRoute
+
+	mux sync.Mutex
+}
+
+// NewGatewayRouter creates a new [GatewayRouter] for the routes of the [Node].
+// The channels map binds the channel of the frames to the name of a bus
+// of the node. The routes that involve a bus without a channel are ignored.
+//
+// It returns:
+//   - [ErrNotFound] wrapped by a [NameError] if a bus name does not match any bus of the node.
+//   - [SizeError] if a target message does not fit a single frame, like the ones
+//     bigger than 8 bytes sent with a [MessageTransport] (see [Message.EncodeFrame]).
+func (n *Node) NewGatewayRouter(channels map[string]string) (*GatewayRouter, error) {
+	buses := make(map[string]*Bus)
+	for _, nodeInt := range n.interfaces {
+		if nodeInt.hasParentBus() {
+			buses[nodeInt.parentBus.name] = nodeInt.parentBus
+		}
+	}
+
+	gr := &GatewayRouter{
+		busIDs: make(map[string]EntityID),
+		routes: make(map[gatewayRouterKey][]*gatewayRouterRoute),
+	}
+
+	busChannels := make(map[EntityID]string)
+	for channel, busName := range channels {
+		bus, ok := buses[busName]
+		if !ok {
+			return nil, n.errorf(newNameError(busName, ErrNotFound))
+		}
+
+		gr.busIDs[channel] = bus.entityID
+		busChannels[bus.entityID] = channel
+	}
+
+	// the signal routes that fill the same target message share its values
+	values := make(map[EntityID]*messageValues)
+
+	for _, route := range n.gatewayRoutes {
+		srcBus := getMessageBus(route.sourceMessage)
+		tgtBus := getMessageBus(route.targetMessage)
+		if srcBus == nil || tgtBus == nil {
+			continue
+		}
+
+		if _, ok := busChannels[srcBus.entityID]; !ok {
+			continue
+		}
+
+		tgtChannel, ok := busChannels[tgtBus.entityID]
+		if !ok {
+			continue
+		}
+
+		// the transport protocols are not routed,
+		// so every target message must fit a single frame
+		tgtMsg := route.targetMessage
+		if err := tgtMsg.verifySingleFrame(); err != nil {
+			return nil, n.errorf(err)
+		}
+
+		tgtValues, ok := values[tgtMsg.entityID]
+		if !ok {
+			tgtValues = newMessageValues(tgtMsg)
+			values[tgtMsg.entityID] = tgtValues
+		}
+
+		key := gatewayRouterKey{
+			busEntityID: srcBus.entityID,
+			canID:       route.sourceMessage.GetCANID(),
+			isExtended:  route.sourceMessage.IsExtended(),
+		}
+
+		gr.routes[key] = append(gr.routes[key], &gatewayRouterRoute{
+			route:   route,
+			channel: tgtChannel,
+			values:  tgtValues,
+		})
+	}
+
+	return gr, nil
+}
+
+// Route applies the routes to the given received frame, and it returns
+// the frames to send. Their timestamp is the one of the received frame
+// plus the processing time of the route. The frames that do not match
+// any route, the remote frames and the error frames are discarded.
+//
+// It returns:
+//   - [ArgError] if the frame is nil.
+//   - [SizeError] if the payload is smaller than the source message.
+//   - [SignalValueError] if a value cannot be written into its target signal.
+func (gr *GatewayRouter) Route(frame *Frame) ([]*Frame, error) {
+	if frame == nil {
+		return nil, newArgError("frame", ErrIsNil)
+	}
+
+	if frame.IsRemote || frame.IsError {
+		return nil, nil
+	}
+
+	busID, ok := gr.busIDs[frame.Channel]
+	if !ok {
+		return nil, nil
+	}
+
+	routes := gr.routes[gatewayRouterKey{
+		busEntityID: busID,
+		canID:       frame.CANID,
+		isExtended:  frame.IsExtended,
+	}]
+
+	gr.mux.Lock()
+	defer gr.mux.Unlock()
+
+	frames := make([]*Frame, 0, len(routes))
+	for _, rr := range routes {
+		outFrame, err := rr.apply(frame)
+		if err != nil {
+			return nil, err
+		}
+
+		outFrame.Timestamp = frame.Timestamp.Add(rr.route.processingTime)
+		outFrame.Channel = rr.channel
+
+		frames = append(frames, outFrame)
+	}
+
+	return frames, nil
+}
+
+// apply returns the frame of the target message from the given received frame.
+func (rr *gatewayRouterRoute) apply(frame *Frame) (*Frame, error) {
+	srcMsg := rr.route.sourceMessage
+	tgtMsg := rr.route.targetMessage
+
+	if len(frame.Data) < srcMsg.sizeByte {
+		return nil, srcMsg.errorf(newSizeError(len(frame.Data), ErrTooSmall))
+	}
+
+	if rr.route.IsFrameRoute() {
+		outFrame, err := tgtMsg.newFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		copy(outFrame.Data, frame.Data[:srcMsg.sizeByte])
+
+		return outFrame, nil
+	}
+
+	for _, mapping := range rr.route.signalMappings {
+		srcSig := mapping.sourceSignal
+		tgtSig := mapping.targetSignal
+
+		rawValue := srcMsg.layout.decodeRawValue(frame.Data, srcSig)
+		dec := srcMsg.layout.decodeSignal(srcSig, rawValue)
+
+		tgtRawValue, err := tgtMsg.layout.encodeSignalValue(tgtSig, dec.Value)
+		if err != nil {
+			return nil, tgtMsg.errorf(newSignalValueError(tgtSig.Name(), err))
+		}
+
+		rr.values.rawValues[tgtSig.EntityID()] = tgtRawValue
+	}
+
+	return rr.values.encode()
+}
+
+// RouteFrames returns an iterator over the frames to send,
+// obtained by applying [GatewayRouter.Route] to the given received frames.
+// It stops after yielding the first error.
+func (gr *GatewayRouter) RouteFrames(frames iter.Seq2[*Frame, error]) iter.Seq2[*Frame, error] {
+	return func(yield func(*Frame, error) bool) {
+		for frame, err := range frames {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			outFrames, err := gr.Route(frame)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, outFrame := range outFrames {
+				if !yield(outFrame, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// GatewayLatencyHop is a transmission of a message on a bus,
+// part of the path estimated by [Network.EstimateGatewayLatency].
+type GatewayLatencyHop struct {
+	// Message is the message sent on the bus.
+	Message *Message
+	// Bus is the bus where the message is sent.
+	Bus *Bus
+	// Gateway is the node that forwards the message of the previous hop
+	// as the message of this hop. It is nil for the first hop.
+	Gateway *Node
+
+	// ProcessingTime is the time the gateway needs to forward the message.
+	ProcessingTime time.Duration
+	// TransmissionTime is the time needed to send the message in the worst case scenario.
+	TransmissionTime time.Duration
+	// ResponseTime is the worst case response time of the message in the bus.
+	ResponseTime time.Duration
+}
+
+// GatewayLatency is the latency estimated by [Network.EstimateGatewayLatency].
+type GatewayLatency struct {
+	// Hops are the transmissions from the source to the target message.
+	Hops []*GatewayLatencyHop
+	// BestCase is the sum of the transmission and processing times,
+	// when the messages never wait for the bus.
+	BestCase time.Duration
+	// WorstCase is the sum of the response and processing times.
+	WorstCase time.Duration
+}
+
+// EstimateGatewayLatency estimates the latency between the transmission
+// of the source message and the one of the target message, following
+// the shortest path of gateway routes of the nodes of the [Network].
+//
+// The times of every hop are given by the response time analysis
+// of its bus (see [Bus.AnalyzeResponseTimes]) with the given options,
+// which can be nil. The hops on a bus without the baudrate set have zero times.
+//
+// It returns:
+//   - [ErrNotFound] wrapped by an [ArgError] if the source message is not sent
+//     in a bus of the network, or if the target message cannot be reached.
+//   - [ArgError] if the options are invalid.
+func (n *Network) EstimateGatewayLatency(sourceMessage, targetMessage *Message, opts *ResponseTimeOptions) (*GatewayLatency, error) {
+	if sourceMessage == nil {
+		return nil, n.errorf(newArgError("sourceMessage", ErrIsNil))
+	}
+
+	if targetMessage == nil {
+		return nil, n.errorf(newArgError("targetMessage", ErrIsNil))
+	}
+
+	srcBus := getMessageBus(sourceMessage)
+	if srcBus == nil || srcBus.parentNetwork != n {
+		return nil, n.errorf(newArgError("sourceMessage", ErrNotFound))
+	}
+
+	type routeEdge struct {
+		node  *Node
+		route *GatewayRoute
+	}
+
+	// collect the routes of all the nodes, in a stable order
+	edges := make(map[EntityID][]*routeEdge)
+	visitedNodes := make(map[EntityID]bool)
+	for _, bus := range n.Buses() {
+		for _, nodeInt := range bus.NodeInterfaces() {
+			node := nodeInt.node
+			if visitedNodes[node.entityID] {
+				continue
+			}
+			visitedNodes[node.entityID] = true
+
+			for _, route := range node.gatewayRoutes {
+				srcID := route.sourceMessage.entityID
+				edges[srcID] = append(edges[srcID], &routeEdge{node: node, route: route})
+			}
+		}
+	}
+
+	// breadth first search of the shortest path
+	prevEdges := make(map[EntityID]*routeEdge)
+	visitedMsgs := map[EntityID]bool{sourceMessage.entityID: true}
+	queue := []*Message{sourceMessage}
+
+	for len(queue) > 0 && !visitedMsgs[targetMessage.entityID] {
+		msg := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range edges[msg.entityID] {
+			tgtMsg := edge.route.targetMessage
+			if visitedMsgs[tgtMsg.entityID] || getMessageBus(tgtMsg) == nil {
+				continue
+			}
+
+			visitedMsgs[tgtMsg.entityID] = true
+			prevEdges[tgtMsg.entityID] = edge
+			queue = append(queue, tgtMsg)
+		}
+	}
+
+	if !visitedMsgs[targetMessage.entityID] {
+		return nil, n.errorf(newArgError("targetMessage", ErrNotFound))
+	}
+
+	hops := []*GatewayLatencyHop{}
+	for msg := targetMessage; ; {
+		hop := &GatewayLatencyHop{
+			Message: msg,
+			Bus:     getMessageBus(msg),
+		}
+		hops = append(hops, hop)
+
+		edge, ok := prevEdges[msg.entityID]
+		if !ok {
+			break
+		}
+
+		hop.Gateway = edge.node
+		hop.ProcessingTime = edge.route.processingTime
+
+		msg = edge.route.sourceMessage
+	}
+	slices.Reverse(hops)
+
+	latency := &GatewayLatency{
+		Hops: hops,
+	}
+
+	busResults := make(map[EntityID]map[EntityID]*BusResponseTimeMessage)
+	for _, hop := range hops {
+		results, ok := busResults[hop.Bus.entityID]
+		if !ok {
+			resSlice, err := hop.Bus.AnalyzeResponseTimes(opts)
+			if err != nil {
+				return nil, hop.Bus.errorf(err)
+			}
+
+			results = make(map[EntityID]*BusResponseTimeMessage, len(resSlice))
+			for _, res := range resSlice {
+				results[res.Message.entityID] = res
+			}
+			busResults[hop.Bus.entityID] = results
+		}
+
+		if res, ok := results[hop.Message.entityID]; ok {
+			hop.TransmissionTime = res.TransmissionTime
+			hop.ResponseTime = res.ResponseTime
+		}
+
+		latency.BestCase += hop.TransmissionTime + hop.ProcessingTime
+		latency.WorstCase += hop.ResponseTime + hop.ProcessingTime
+	}
+
+	return latency, nil
+}
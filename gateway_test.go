@@ -0,0 +1,379 @@
+package acmelib
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tdGateway struct {
+	net     *Network
+	busA    *Bus
+	busB    *Bus
+	gateway *Node
+
+	frameSrcMsg *Message
+	frameTgtMsg *Message
+	sigSrcMsg   *Message
+	sigTgtMsg   *Message
+}
+
+// initGatewayLayout inserts two 8 bits signals with the given prefix into the message.
+func initGatewayLayout(assert *assert.Assertions, msg *Message, prefix string) {
+	uint8Type, err := NewIntegerSignalType("uint8", 8, false)
+	assert.NoError(err)
+
+	sigA, err := NewStandardSignal(prefix+"_a", uint8Type)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(sigA, 0))
+
+	sigB, err := NewStandardSignal(prefix+"_b", uint8Type)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(sigB, 8))
+}
+
+func initGateway(assert *assert.Assertions) *tdGateway {
+	net := NewNetwork("network")
+
+	busA := NewBus("bus_a")
+	busA.SetBaudrate(500_000)
+	assert.NoError(net.AddBus(busA))
+
+	busB := NewBus("bus_b")
+	busB.SetBaudrate(500_000)
+	assert.NoError(net.AddBus(busB))
+
+	sender := NewNode("sender", 1, 1)
+	assert.NoError(busA.AddNodeInterface(sender.GetInterface(0)))
+
+	gateway := NewNode("gateway", 2, 2)
+	assert.NoError(busA.AddNodeInterface(gateway.GetInterface(0)))
+	assert.NoError(busB.AddNodeInterface(gateway.GetInterface(1)))
+
+	// messages with the same layout forwarded as they are
+	frameSrcMsg := NewMessage("frame_src", 1, 2)
+	frameSrcMsg.SetCycleTime(10)
+	initGatewayLayout(assert, frameSrcMsg, "sig")
+	assert.NoError(sender.GetInterface(0).AddSentMessage(frameSrcMsg))
+	assert.NoError(frameSrcMsg.AddReceiver(gateway.GetInterface(0)))
+
+	frameTgtMsg := NewMessage("frame_tgt", 1, 2)
+	frameTgtMsg.SetCycleTime(10)
+	initGatewayLayout(assert, frameTgtMsg, "sig")
+	assert.NoError(gateway.GetInterface(1).AddSentMessage(frameTgtMsg))
+
+	// messages with different layouts forwarded signal by signal
+	sigSrcMsg := NewMessage("sig_src", 2, 2)
+	sigSrcMsg.SetCycleTime(20)
+	initGatewayLayout(assert, sigSrcMsg, "src")
+	assert.NoError(sender.GetInterface(0).AddSentMessage(sigSrcMsg))
+	assert.NoError(sigSrcMsg.AddReceiver(gateway.GetInterface(0)))
+
+	sigTgtMsg := NewMessage("sig_tgt", 2, 4)
+	sigTgtMsg.SetCycleTime(20)
+	uint16Type, err := NewIntegerSignalType("uint16", 16, false)
+	assert.NoError(err)
+	tgtSig, err := NewStandardSignal("tgt_a", uint16Type)
+	assert.NoError(err)
+	assert.NoError(sigTgtMsg.InsertSignal(tgtSig, 0))
+	otherSig, err := NewStandardSignal("tgt_other", uint16Type)
+	assert.NoError(err)
+	otherSig.SetStartValue(3)
+	assert.NoError(sigTgtMsg.InsertSignal(otherSig, 16))
+	assert.NoError(gateway.GetInterface(1).AddSentMessage(sigTgtMsg))
+
+	return &tdGateway{
+		net:     net,
+		busA:    busA,
+		busB:    busB,
+		gateway: gateway,
+
+		frameSrcMsg: frameSrcMsg,
+		frameTgtMsg: frameTgtMsg,
+		sigSrcMsg:   sigSrcMsg,
+		sigTgtMsg:   sigTgtMsg,
+	}
+}
+
+// addSignalRoute adds the route that maps src_a into tgt_a.
+func (td *tdGateway) addSignalRoute(assert *assert.Assertions) *GatewayRoute {
+	srcSig, err := td.sigSrcMsg.GetSignalByName("src_a")
+	assert.NoError(err)
+	tgtSig, err := td.sigTgtMsg.GetSignalByName("tgt_a")
+	assert.NoError(err)
+
+	route := NewGatewayRoute(td.sigSrcMsg, td.sigTgtMsg)
+	route.AddSignalMapping(srcSig, tgtSig)
+	route.SetProcessingTime(time.Millisecond)
+	assert.NoError(td.gateway.AddGatewayRoute(route))
+
+	return route
+}
+
+func Test_Node_AddGatewayRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	tdGw := initGateway(assert)
+	gateway := tdGw.gateway
+
+	// should return an error because the route is nil
+	assert.ErrorIs(gateway.AddGatewayRoute(nil), ErrIsNil)
+
+	// should return an error because the source message is not received by the gateway
+	assert.ErrorIs(gateway.AddGatewayRoute(NewGatewayRoute(NewMessage("msg", 10, 2), tdGw.frameTgtMsg)), ErrNotFound)
+
+	// should return an error because the gateway is not a receiver of the source message
+	assert.NoError(tdGw.frameSrcMsg.RemoveReceiver(gateway.EntityID()))
+	assert.ErrorIs(gateway.AddGatewayRoute(NewGatewayRoute(tdGw.frameSrcMsg, tdGw.frameTgtMsg)), ErrNotFound)
+	assert.NoError(tdGw.frameSrcMsg.AddReceiver(gateway.GetInterface(0)))
+
+	// should return an error because the target message is not sent by the gateway
+	assert.ErrorIs(gateway.AddGatewayRoute(NewGatewayRoute(tdGw.frameSrcMsg, tdGw.sigSrcMsg)), ErrNotFound)
+
+	// should add a frame route
+	assert.NoError(gateway.AddGatewayRoute(NewGatewayRoute(tdGw.frameSrcMsg, tdGw.frameTgtMsg)))
+	assert.Len(gateway.GatewayRoutes(), 1)
+	assert.True(gateway.GatewayRoutes()[0].IsFrameRoute())
+
+	// should return an error because the route is duplicated
+	assert.ErrorIs(gateway.AddGatewayRoute(NewGatewayRoute(tdGw.frameSrcMsg, tdGw.frameTgtMsg)), ErrIsDuplicated)
+
+	// should return an error because the target of a frame route cannot be shared
+	srcSig, err := tdGw.sigSrcMsg.GetSignalByName("src_a")
+	assert.NoError(err)
+	frameTgtSig, err := tdGw.frameTgtMsg.GetSignalByName("sig_a")
+	assert.NoError(err)
+	route := NewGatewayRoute(tdGw.sigSrcMsg, tdGw.frameTgtMsg)
+	route.AddSignalMapping(srcSig, frameTgtSig)
+	assert.ErrorIs(gateway.AddGatewayRoute(route), ErrIsDuplicated)
+
+	// should return an error because the layouts are different
+	assert.ErrorIs(gateway.AddGatewayRoute(NewGatewayRoute(tdGw.sigSrcMsg, tdGw.sigTgtMsg)), ErrIsDifferent)
+
+	// should return an error because the signal types of the layouts are different
+	sender := tdGw.frameSrcMsg.SenderNodeInterface()
+	scaledSrcMsg := NewMessage("scaled_src", 3, 1)
+	scaledTgtMsg := NewMessage("scaled_tgt", 3, 1)
+	for _, scale := range []float64{1, 0.5} {
+		scaledType, err := NewDecimalSignalType("scaled", 8, false)
+		assert.NoError(err)
+		scaledType.SetScale(scale)
+		scaledSig, err := NewStandardSignal("scaled_sig", scaledType)
+		assert.NoError(err)
+
+		msg := scaledSrcMsg
+		if scale != 1 {
+			msg = scaledTgtMsg
+		}
+		assert.NoError(msg.InsertSignal(scaledSig, 0))
+	}
+	assert.NoError(sender.AddSentMessage(scaledSrcMsg))
+	assert.NoError(scaledSrcMsg.AddReceiver(gateway.GetInterface(0)))
+	assert.NoError(gateway.GetInterface(1).AddSentMessage(scaledTgtMsg))
+	assert.ErrorIs(gateway.AddGatewayRoute(NewGatewayRoute(scaledSrcMsg, scaledTgtMsg)), ErrIsDifferent)
+
+	// should return an error because the enums of the layouts are different
+	enumSrcMsg := NewMessage("enum_src", 4, 1)
+	enumTgtMsg := NewMessage("enum_tgt", 4, 1)
+	for idx, valName := range []string{"on", "enabled"} {
+		enum := NewSignalEnum("enum")
+		_, err := enum.AddValue(0, "off")
+		assert.NoError(err)
+		_, err = enum.AddValue(1, valName)
+		assert.NoError(err)
+		enumSig, err := NewEnumSignal("enum_sig", enum)
+		assert.NoError(err)
+
+		msg := enumSrcMsg
+		if idx > 0 {
+			msg = enumTgtMsg
+		}
+		assert.NoError(msg.InsertSignal(enumSig, 0))
+	}
+	assert.NoError(sender.AddSentMessage(enumSrcMsg))
+	assert.NoError(enumSrcMsg.AddReceiver(gateway.GetInterface(0)))
+	assert.NoError(gateway.GetInterface(1).AddSentMessage(enumTgtMsg))
+	assert.ErrorIs(gateway.AddGatewayRoute(NewGatewayRoute(enumSrcMsg, enumTgtMsg)), ErrIsDifferent)
+
+	// should return an error because the target signal cannot hold the source values
+	tgtSig, err := tdGw.sigTgtMsg.GetSignalByName("tgt_a")
+	assert.NoError(err)
+	uint16Type, err := NewIntegerSignalType("uint16_src", 16, true)
+	assert.NoError(err)
+	wideSig, err := NewStandardSignal("src_wide", uint16Type)
+	assert.NoError(err)
+	assert.NoError(tdGw.sigSrcMsg.UpdateSizeByte(5))
+	assert.NoError(tdGw.sigSrcMsg.InsertSignal(wideSig, 16))
+	route = NewGatewayRoute(tdGw.sigSrcMsg, tdGw.sigTgtMsg)
+	route.AddSignalMapping(wideSig, tgtSig)
+	assert.ErrorIs(gateway.AddGatewayRoute(route), ErrOutOfBounds)
+
+	// should return an error because a flag cannot be mapped to a number
+	flagSig, err := NewStandardSignal("src_flag", NewFlagSignalType("flag"))
+	assert.NoError(err)
+	assert.NoError(tdGw.sigSrcMsg.InsertSignal(flagSig, 32))
+	route = NewGatewayRoute(tdGw.sigSrcMsg, tdGw.sigTgtMsg)
+	route.AddSignalMapping(flagSig, tgtSig)
+	assert.ErrorIs(gateway.AddGatewayRoute(route), ErrInvalidType)
+
+	// should add a signal route
+	tdGw.addSignalRoute(assert)
+	assert.Len(gateway.GatewayRoutes(), 2)
+
+	// should remove the routes
+	assert.NoError(gateway.RemoveGatewayRoute(tdGw.frameSrcMsg.EntityID(), tdGw.frameTgtMsg.EntityID()))
+	assert.ErrorIs(gateway.RemoveGatewayRoute(tdGw.frameSrcMsg.EntityID(), tdGw.frameTgtMsg.EntityID()), ErrNotFound)
+	assert.Len(gateway.GatewayRoutes(), 1)
+}
+
+func Test_GatewayRouter_Route(t *testing.T) {
+	assert := assert.New(t)
+
+	tdGw := initGateway(assert)
+	gateway := tdGw.gateway
+
+	frameRoute := NewGatewayRoute(tdGw.frameSrcMsg, tdGw.frameTgtMsg)
+	frameRoute.SetProcessingTime(2 * time.Millisecond)
+	assert.NoError(gateway.AddGatewayRoute(frameRoute))
+	tdGw.addSignalRoute(assert)
+
+	// should return an error because the bus is not connected to the gateway
+	_, err := gateway.NewGatewayRouter(map[string]string{"can0": "bus_c"})
+	assert.ErrorIs(err, ErrNotFound)
+
+	router, err := gateway.NewGatewayRouter(map[string]string{"can0": "bus_a", "can1": "bus_b"})
+	assert.NoError(err)
+
+	timestamp := time.Unix(1000, 0)
+
+	// should copy the payload of the frame route
+	frames, err := router.Route(&Frame{Timestamp: timestamp, Channel: "can0", CANID: tdGw.frameSrcMsg.GetCANID(), Data: []byte{1, 2}})
+	assert.NoError(err)
+	assert.Len(frames, 1)
+	assert.Equal(tdGw.frameTgtMsg.GetCANID(), frames[0].CANID)
+	assert.Equal("can1", frames[0].Channel)
+	assert.Equal(FrameDirectionTx, frames[0].Direction)
+	assert.Equal(timestamp.Add(2*time.Millisecond), frames[0].Timestamp)
+	assert.Equal([]byte{1, 2}, frames[0].Data)
+
+	// should map the signals of the signal route
+	frames, err = router.Route(&Frame{Timestamp: timestamp, Channel: "can0", CANID: tdGw.sigSrcMsg.GetCANID(), Data: []byte{200, 9}})
+	assert.NoError(err)
+	assert.Len(frames, 1)
+	assert.Equal(timestamp.Add(time.Millisecond), frames[0].Timestamp)
+	assert.Equal([]byte{200, 0, 3, 0}, frames[0].Data)
+
+	// should discard the frames without a route
+	frames, err = router.Route(&Frame{Channel: "can1", CANID: tdGw.sigSrcMsg.GetCANID(), Data: []byte{1, 2}})
+	assert.NoError(err)
+	assert.Empty(frames)
+
+	// should return an error because the payload is too small
+	_, err = router.Route(&Frame{Channel: "can0", CANID: tdGw.sigSrcMsg.GetCANID(), Data: []byte{1}})
+	assert.ErrorIs(err, ErrTooSmall)
+
+	// should route a stream of frames
+	src := &testFrameSource{frames: []*Frame{
+		{Channel: "can0", CANID: tdGw.frameSrcMsg.GetCANID(), Data: []byte{3, 4}},
+		{Channel: "can0", CANID: 0x7FF, Data: []byte{}},
+		{Channel: "can0", CANID: tdGw.sigSrcMsg.GetCANID(), Data: []byte{5, 6}},
+	}}
+	sink := &testFrameSink{}
+	assert.NoError(WriteFrames(sink, router.RouteFrames(ReadFrames(src))))
+	assert.Len(sink.frames, 2)
+	assert.Equal([]byte{3, 4}, sink.frames[0].Data)
+	assert.Equal([]byte{5, 0, 3, 0}, sink.frames[1].Data)
+
+	// should return an error because the target message needs the transport protocol
+	assert.NoError(tdGw.frameTgtMsg.SetTransport(MessageTransportISOTP))
+	assert.NoError(tdGw.frameTgtMsg.UpdateSizeByte(20))
+	_, err = gateway.NewGatewayRouter(map[string]string{"can0": "bus_a", "can1": "bus_b"})
+	assert.ErrorIs(err, ErrTooBig)
+	var sizeErr *SizeError
+	assert.ErrorAs(err, &sizeErr)
+}
+
+func Test_Network_EstimateGatewayLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	tdGw := initGateway(assert)
+
+	frameRoute := NewGatewayRoute(tdGw.frameSrcMsg, tdGw.frameTgtMsg)
+	frameRoute.SetProcessingTime(2 * time.Millisecond)
+	assert.NoError(tdGw.gateway.AddGatewayRoute(frameRoute))
+
+	// should return an error because there is no path
+	_, err := tdGw.net.EstimateGatewayLatency(tdGw.sigSrcMsg, tdGw.sigTgtMsg, nil)
+	assert.ErrorIs(err, ErrNotFound)
+
+	latency, err := tdGw.net.EstimateGatewayLatency(tdGw.frameSrcMsg, tdGw.frameTgtMsg, nil)
+	assert.NoError(err)
+	assert.Len(latency.Hops, 2)
+
+	srcHop := latency.Hops[0]
+	assert.Equal(tdGw.frameSrcMsg, srcHop.Message)
+	assert.Equal(tdGw.busA, srcHop.Bus)
+	assert.Nil(srcHop.Gateway)
+
+	tgtHop := latency.Hops[1]
+	assert.Equal(tdGw.frameTgtMsg, tgtHop.Message)
+	assert.Equal(tdGw.busB, tgtHop.Bus)
+	assert.Equal(tdGw.gateway, tgtHop.Gateway)
+	assert.Equal(2*time.Millisecond, tgtHop.ProcessingTime)
+
+	assert.Positive(srcHop.TransmissionTime)
+	assert.GreaterOrEqual(srcHop.ResponseTime, srcHop.TransmissionTime)
+	assert.Equal(srcHop.TransmissionTime+tgtHop.TransmissionTime+2*time.Millisecond, latency.BestCase)
+	assert.Equal(srcHop.ResponseTime+tgtHop.ResponseTime+2*time.Millisecond, latency.WorstCase)
+}
+
+func Test_LoadNetwork_GatewayRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	tdGw := initGateway(assert)
+	assert.NoError(tdGw.gateway.AddGatewayRoute(NewGatewayRoute(tdGw.frameSrcMsg, tdGw.frameTgtMsg)))
+	tdGw.addSignalRoute(assert)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(SaveNetwork(tdGw.net, &SaveNetworkOptions{
+		WireWriter: buf,
+	}))
+
+	loadNet, err := LoadNetwork(buf, SaveEncodingWire)
+	assert.NoError(err)
+
+	loadGateway := loadNet.Buses()[1].NodeInterfaces()[0].node
+	routes := loadGateway.GatewayRoutes()
+	assert.Len(routes, 2)
+
+	assert.Equal(tdGw.frameSrcMsg.EntityID(), routes[0].SourceMessage().EntityID())
+	assert.True(routes[0].IsFrameRoute())
+
+	assert.Equal(tdGw.sigTgtMsg.EntityID(), routes[1].TargetMessage().EntityID())
+	assert.Equal(time.Millisecond, routes[1].ProcessingTime())
+	assert.Len(routes[1].SignalMappings(), 1)
+	assert.Equal("src_a", routes[1].SignalMappings()[0].SourceSignal().Name())
+	assert.Equal("tgt_a", routes[1].SignalMappings()[0].TargetSignal().Name())
+}
+
+func Test_SaveNetwork_GatewayRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	tdGw := initGateway(assert)
+	tdGw.addSignalRoute(assert)
+
+	// should return an error because the target message of the route has been removed
+	assert.NoError(tdGw.gateway.GetInterface(1).RemoveSentMessage(tdGw.sigTgtMsg.EntityID()))
+	err := SaveNetwork(tdGw.net, &SaveNetworkOptions{
+		WireWriter: new(bytes.Buffer),
+	})
+	assert.ErrorIs(err, ErrNotFound)
+
+	// should save the network after the route is removed
+	assert.NoError(tdGw.gateway.RemoveGatewayRoute(tdGw.sigSrcMsg.EntityID(), tdGw.sigTgtMsg.EntityID()))
+	assert.NoError(SaveNetwork(tdGw.net, &SaveNetworkOptions{
+		WireWriter: new(bytes.Buffer),
+	}))
+}
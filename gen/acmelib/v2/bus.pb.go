@@ -26,6 +26,8 @@ type BusType int32
 const (
 	BusType_BUS_TYPE_UNSPECIFIED BusType = 0
 	BusType_BUS_TYPE_CAN_2A      BusType = 1
+	BusType_BUS_TYPE_CAN_FD      BusType = 2
+	BusType_BUS_TYPE_CAN_2B      BusType = 3
 )
 
 // Enum value maps for BusType.
@@ -33,10 +35,14 @@ var (
 	BusType_name = map[int32]string{
 		0: "BUS_TYPE_UNSPECIFIED",
 		1: "BUS_TYPE_CAN_2A",
+		2: "BUS_TYPE_CAN_FD",
+		3: "BUS_TYPE_CAN_2B",
 	}
 	BusType_value = map[string]int32{
 		"BUS_TYPE_UNSPECIFIED": 0,
 		"BUS_TYPE_CAN_2A":      1,
+		"BUS_TYPE_CAN_FD":      2,
+		"BUS_TYPE_CAN_2B":      3,
 	}
 )
 
@@ -75,6 +81,8 @@ type Bus struct {
 	Type                 BusType                `protobuf:"varint,4,opt,name=type,proto3,enum=acmelib.v2.BusType" json:"type,omitempty"`
 	CanidBuilderEntityId string                 `protobuf:"bytes,5,opt,name=canid_builder_entity_id,json=canidBuilderEntityId,proto3" json:"canid_builder_entity_id,omitempty"`
 	AttributeAssignments []*AttributeAssignment `protobuf:"bytes,6,rep,name=attribute_assignments,json=attributeAssignments,proto3" json:"attribute_assignments,omitempty"`
+	DataBaudrate         uint32                 `protobuf:"varint,7,opt,name=data_baudrate,json=dataBaudrate,proto3" json:"data_baudrate,omitempty"`
+	BitRateSwitch        bool                   `protobuf:"varint,8,opt,name=bit_rate_switch,json=bitRateSwitch,proto3" json:"bit_rate_switch,omitempty"`
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -151,22 +159,40 @@ func (x *Bus) GetAttributeAssignments() []*AttributeAssignment {
 	return nil
 }
 
+func (x *Bus) GetDataBaudrate() uint32 {
+	if x != nil {
+		return x.DataBaudrate
+	}
+	return 0
+}
+
+func (x *Bus) GetBitRateSwitch() bool {
+	if x != nil {
+		return x.BitRateSwitch
+	}
+	return false
+}
+
 var File_acmelib_v2_bus_proto protoreflect.FileDescriptor
 
 const file_acmelib_v2_bus_proto_rawDesc = "" +
 	"\n" +
 	"\x14acmelib/v2/bus.proto\x12\n" +
-	"acmelib.v2\x1a\x17acmelib/v2/entity.proto\x1a\x15acmelib/v2/node.proto\x1a\x1aacmelib/v2/attribute.proto\"\xc7\x02\n" +
+	"acmelib.v2\x1a\x17acmelib/v2/entity.proto\x1a\x15acmelib/v2/node.proto\x1a\x1aacmelib/v2/attribute.proto\"\x94\x03\n" +
 	"\x03Bus\x12*\n" +
 	"\x06entity\x18\x01 \x01(\v2\x12.acmelib.v2.EntityR\x06entity\x12B\n" +
 	"\x0fnode_interfaces\x18\x02 \x03(\v2\x19.acmelib.v2.NodeInterfaceR\x0enodeInterfaces\x12\x1a\n" +
 	"\bbaudrate\x18\x03 \x01(\rR\bbaudrate\x12'\n" +
 	"\x04type\x18\x04 \x01(\x0e2\x13.acmelib.v2.BusTypeR\x04type\x125\n" +
 	"\x17canid_builder_entity_id\x18\x05 \x01(\tR\x14canidBuilderEntityId\x12T\n" +
-	"\x15attribute_assignments\x18\x06 \x03(\v2\x1f.acmelib.v2.AttributeAssignmentR\x14attributeAssignments*8\n" +
+	"\x15attribute_assignments\x18\x06 \x03(\v2\x1f.acmelib.v2.AttributeAssignmentR\x14attributeAssignments\x12#\n" +
+	"\rdata_baudrate\x18\a \x01(\rR\fdataBaudrate\x12&\n" +
+	"\x0fbit_rate_switch\x18\b \x01(\bR\rbitRateSwitch*b\n" +
 	"\aBusType\x12\x18\n" +
 	"\x14BUS_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
-	"\x0fBUS_TYPE_CAN_2A\x10\x01By\n" +
+	"\x0fBUS_TYPE_CAN_2A\x10\x01\x12\x13\n" +
+	"\x0fBUS_TYPE_CAN_FD\x10\x02\x12\x13\n" +
+	"\x0fBUS_TYPE_CAN_2B\x10\x03By\n" +
 	"\x0ecom.acmelib.v2B\bBusProtoP\x01Z\x14acmelib/v2;acmelibv2\xa2\x02\x03AXX\xaa\x02\n" +
 	"Acmelib.V2\xca\x02\n" +
 	"Acmelib\\V2\xe2\x02\x16Acmelib\\V2\\GPBMetadata\xea\x02\vAcmelib::V2b\x06proto3"
@@ -29,6 +29,11 @@ const (
 	MessagePriority_MESSAGE_PRIORITY_HIGH        MessagePriority = 2
 	MessagePriority_MESSAGE_PRIORITY_MEDIUM      MessagePriority = 3
 	MessagePriority_MESSAGE_PRIORITY_LOW         MessagePriority = 4
+	// the priorities from 4 to 7 are used by J1939
+	MessagePriority_MESSAGE_PRIORITY_4 MessagePriority = 5
+	MessagePriority_MESSAGE_PRIORITY_5 MessagePriority = 6
+	MessagePriority_MESSAGE_PRIORITY_6 MessagePriority = 7
+	MessagePriority_MESSAGE_PRIORITY_7 MessagePriority = 8
 )
 
 // Enum value maps for MessagePriority.
@@ -39,6 +44,10 @@ var (
 		2: "MESSAGE_PRIORITY_HIGH",
 		3: "MESSAGE_PRIORITY_MEDIUM",
 		4: "MESSAGE_PRIORITY_LOW",
+		5: "MESSAGE_PRIORITY_4",
+		6: "MESSAGE_PRIORITY_5",
+		7: "MESSAGE_PRIORITY_6",
+		8: "MESSAGE_PRIORITY_7",
 	}
 	MessagePriority_value = map[string]int32{
 		"MESSAGE_PRIORITY_UNSPECIFIED": 0,
@@ -46,6 +55,10 @@ var (
 		"MESSAGE_PRIORITY_HIGH":        2,
 		"MESSAGE_PRIORITY_MEDIUM":      3,
 		"MESSAGE_PRIORITY_LOW":         4,
+		"MESSAGE_PRIORITY_4":           5,
+		"MESSAGE_PRIORITY_5":           6,
+		"MESSAGE_PRIORITY_6":           7,
+		"MESSAGE_PRIORITY_7":           8,
 	}
 )
 
@@ -131,6 +144,220 @@ func (MessageSendType) EnumDescriptor() ([]byte, []int) {
 	return file_acmelib_v2_message_proto_rawDescGZIP(), []int{1}
 }
 
+type MessageTransport int32
+
+const (
+	MessageTransport_MESSAGE_TRANSPORT_UNSPECIFIED MessageTransport = 0
+	MessageTransport_MESSAGE_TRANSPORT_J1939_TP    MessageTransport = 1
+	MessageTransport_MESSAGE_TRANSPORT_ISO_TP      MessageTransport = 2
+)
+
+// Enum value maps for MessageTransport.
+var (
+	MessageTransport_name = map[int32]string{
+		0: "MESSAGE_TRANSPORT_UNSPECIFIED",
+		1: "MESSAGE_TRANSPORT_J1939_TP",
+		2: "MESSAGE_TRANSPORT_ISO_TP",
+	}
+	MessageTransport_value = map[string]int32{
+		"MESSAGE_TRANSPORT_UNSPECIFIED": 0,
+		"MESSAGE_TRANSPORT_J1939_TP":    1,
+		"MESSAGE_TRANSPORT_ISO_TP":      2,
+	}
+)
+
+func (x MessageTransport) Enum() *MessageTransport {
+	p := new(MessageTransport)
+	*p = x
+	return p
+}
+
+func (x MessageTransport) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageTransport) Descriptor() protoreflect.EnumDescriptor {
+	return file_acmelib_v2_message_proto_enumTypes[2].Descriptor()
+}
+
+func (MessageTransport) Type() protoreflect.EnumType {
+	return &file_acmelib_v2_message_proto_enumTypes[2]
+}
+
+func (x MessageTransport) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageTransport.Descriptor instead.
+func (MessageTransport) EnumDescriptor() ([]byte, []int) {
+	return file_acmelib_v2_message_proto_rawDescGZIP(), []int{2}
+}
+
+type CANIDFormat int32
+
+const (
+	CANIDFormat_CANID_FORMAT_UNSPECIFIED CANIDFormat = 0
+	CANIDFormat_CANID_FORMAT_STANDARD    CANIDFormat = 1
+	CANIDFormat_CANID_FORMAT_EXTENDED    CANIDFormat = 2
+)
+
+// Enum value maps for CANIDFormat.
+var (
+	CANIDFormat_name = map[int32]string{
+		0: "CANID_FORMAT_UNSPECIFIED",
+		1: "CANID_FORMAT_STANDARD",
+		2: "CANID_FORMAT_EXTENDED",
+	}
+	CANIDFormat_value = map[string]int32{
+		"CANID_FORMAT_UNSPECIFIED": 0,
+		"CANID_FORMAT_STANDARD":    1,
+		"CANID_FORMAT_EXTENDED":    2,
+	}
+)
+
+func (x CANIDFormat) Enum() *CANIDFormat {
+	p := new(CANIDFormat)
+	*p = x
+	return p
+}
+
+func (x CANIDFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CANIDFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_acmelib_v2_message_proto_enumTypes[3].Descriptor()
+}
+
+func (CANIDFormat) Type() protoreflect.EnumType {
+	return &file_acmelib_v2_message_proto_enumTypes[3]
+}
+
+func (x CANIDFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CANIDFormat.Descriptor instead.
+func (CANIDFormat) EnumDescriptor() ([]byte, []int) {
+	return file_acmelib_v2_message_proto_rawDescGZIP(), []int{3}
+}
+
+type E2EProfile int32
+
+const (
+	E2EProfile_E2E_PROFILE_UNSPECIFIED E2EProfile = 0
+	E2EProfile_E2E_PROFILE_1           E2EProfile = 1
+	E2EProfile_E2E_PROFILE_2           E2EProfile = 2
+	E2EProfile_E2E_PROFILE_4           E2EProfile = 3
+	E2EProfile_E2E_PROFILE_5           E2EProfile = 4
+	E2EProfile_E2E_PROFILE_11          E2EProfile = 5
+	E2EProfile_E2E_PROFILE_CUSTOM      E2EProfile = 6
+)
+
+// Enum value maps for E2EProfile.
+var (
+	E2EProfile_name = map[int32]string{
+		0: "E2E_PROFILE_UNSPECIFIED",
+		1: "E2E_PROFILE_1",
+		2: "E2E_PROFILE_2",
+		3: "E2E_PROFILE_4",
+		4: "E2E_PROFILE_5",
+		5: "E2E_PROFILE_11",
+		6: "E2E_PROFILE_CUSTOM",
+	}
+	E2EProfile_value = map[string]int32{
+		"E2E_PROFILE_UNSPECIFIED": 0,
+		"E2E_PROFILE_1":           1,
+		"E2E_PROFILE_2":           2,
+		"E2E_PROFILE_4":           3,
+		"E2E_PROFILE_5":           4,
+		"E2E_PROFILE_11":          5,
+		"E2E_PROFILE_CUSTOM":      6,
+	}
+)
+
+func (x E2EProfile) Enum() *E2EProfile {
+	p := new(E2EProfile)
+	*p = x
+	return p
+}
+
+func (x E2EProfile) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (E2EProfile) Descriptor() protoreflect.EnumDescriptor {
+	return file_acmelib_v2_message_proto_enumTypes[4].Descriptor()
+}
+
+func (E2EProfile) Type() protoreflect.EnumType {
+	return &file_acmelib_v2_message_proto_enumTypes[4]
+}
+
+func (x E2EProfile) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use E2EProfile.Descriptor instead.
+func (E2EProfile) EnumDescriptor() ([]byte, []int) {
+	return file_acmelib_v2_message_proto_rawDescGZIP(), []int{4}
+}
+
+type E2EDataIDMode int32
+
+const (
+	E2EDataIDMode_E2E_DATA_ID_MODE_UNSPECIFIED E2EDataIDMode = 0
+	E2EDataIDMode_E2E_DATA_ID_MODE_BOTH        E2EDataIDMode = 1
+	E2EDataIDMode_E2E_DATA_ID_MODE_ALT         E2EDataIDMode = 2
+	E2EDataIDMode_E2E_DATA_ID_MODE_LOW         E2EDataIDMode = 3
+	E2EDataIDMode_E2E_DATA_ID_MODE_NIBBLE      E2EDataIDMode = 4
+)
+
+// Enum value maps for E2EDataIDMode.
+var (
+	E2EDataIDMode_name = map[int32]string{
+		0: "E2E_DATA_ID_MODE_UNSPECIFIED",
+		1: "E2E_DATA_ID_MODE_BOTH",
+		2: "E2E_DATA_ID_MODE_ALT",
+		3: "E2E_DATA_ID_MODE_LOW",
+		4: "E2E_DATA_ID_MODE_NIBBLE",
+	}
+	E2EDataIDMode_value = map[string]int32{
+		"E2E_DATA_ID_MODE_UNSPECIFIED": 0,
+		"E2E_DATA_ID_MODE_BOTH":        1,
+		"E2E_DATA_ID_MODE_ALT":         2,
+		"E2E_DATA_ID_MODE_LOW":         3,
+		"E2E_DATA_ID_MODE_NIBBLE":      4,
+	}
+)
+
+func (x E2EDataIDMode) Enum() *E2EDataIDMode {
+	p := new(E2EDataIDMode)
+	*p = x
+	return p
+}
+
+func (x E2EDataIDMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (E2EDataIDMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_acmelib_v2_message_proto_enumTypes[5].Descriptor()
+}
+
+func (E2EDataIDMode) Type() protoreflect.EnumType {
+	return &file_acmelib_v2_message_proto_enumTypes[5]
+}
+
+func (x E2EDataIDMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use E2EDataIDMode.Descriptor instead.
+func (E2EDataIDMode) EnumDescriptor() ([]byte, []int) {
+	return file_acmelib_v2_message_proto_rawDescGZIP(), []int{5}
+}
+
 type Message struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
 	Entity               *Entity                `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
@@ -146,6 +373,9 @@ type Message struct {
 	StartDelayTime       uint32                 `protobuf:"varint,11,opt,name=start_delay_time,json=startDelayTime,proto3" json:"start_delay_time,omitempty"`
 	Receivers            []*MessageReceiver     `protobuf:"bytes,12,rep,name=receivers,proto3" json:"receivers,omitempty"`
 	AttributeAssignments []*AttributeAssignment `protobuf:"bytes,13,rep,name=attribute_assignments,json=attributeAssignments,proto3" json:"attribute_assignments,omitempty"`
+	CanIdFormat          CANIDFormat            `protobuf:"varint,14,opt,name=can_id_format,json=canIdFormat,proto3,enum=acmelib.v2.CANIDFormat" json:"can_id_format,omitempty"`
+	Transport            MessageTransport       `protobuf:"varint,15,opt,name=transport,proto3,enum=acmelib.v2.MessageTransport" json:"transport,omitempty"`
+	E2EProtection        *E2EProtection         `protobuf:"bytes,16,opt,name=e2e_protection,json=e2eProtection,proto3" json:"e2e_protection,omitempty"`
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -271,6 +501,27 @@ func (x *Message) GetAttributeAssignments() []*AttributeAssignment {
 	return nil
 }
 
+func (x *Message) GetCanIdFormat() CANIDFormat {
+	if x != nil {
+		return x.CanIdFormat
+	}
+	return CANIDFormat_CANID_FORMAT_UNSPECIFIED
+}
+
+func (x *Message) GetTransport() MessageTransport {
+	if x != nil {
+		return x.Transport
+	}
+	return MessageTransport_MESSAGE_TRANSPORT_UNSPECIFIED
+}
+
+func (x *Message) GetE2EProtection() *E2EProtection {
+	if x != nil {
+		return x.E2EProtection
+	}
+	return nil
+}
+
 type MessageReceiver struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
 	NodeEntityId        string                 `protobuf:"bytes,1,opt,name=node_entity_id,json=nodeEntityId,proto3" json:"node_entity_id,omitempty"`
@@ -323,12 +574,212 @@ func (x *MessageReceiver) GetNodeInterfaceNumber() uint32 {
 	return 0
 }
 
+type E2EProtection struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Profile               E2EProfile             `protobuf:"varint,1,opt,name=profile,proto3,enum=acmelib.v2.E2EProfile" json:"profile,omitempty"`
+	CrcSignalEntityId     string                 `protobuf:"bytes,2,opt,name=crc_signal_entity_id,json=crcSignalEntityId,proto3" json:"crc_signal_entity_id,omitempty"`
+	CounterSignalEntityId string                 `protobuf:"bytes,3,opt,name=counter_signal_entity_id,json=counterSignalEntityId,proto3" json:"counter_signal_entity_id,omitempty"`
+	DataId                uint32                 `protobuf:"varint,4,opt,name=data_id,json=dataId,proto3" json:"data_id,omitempty"`
+	DataIdMode            E2EDataIDMode          `protobuf:"varint,5,opt,name=data_id_mode,json=dataIdMode,proto3,enum=acmelib.v2.E2EDataIDMode" json:"data_id_mode,omitempty"`
+	DataIdList            []byte                 `protobuf:"bytes,6,opt,name=data_id_list,json=dataIdList,proto3" json:"data_id_list,omitempty"`
+	DataIdSignalEntityId  string                 `protobuf:"bytes,7,opt,name=data_id_signal_entity_id,json=dataIdSignalEntityId,proto3" json:"data_id_signal_entity_id,omitempty"`
+	LengthSignalEntityId  string                 `protobuf:"bytes,8,opt,name=length_signal_entity_id,json=lengthSignalEntityId,proto3" json:"length_signal_entity_id,omitempty"`
+	MaxDeltaCounter       uint64                 `protobuf:"varint,9,opt,name=max_delta_counter,json=maxDeltaCounter,proto3" json:"max_delta_counter,omitempty"`
+	CustomCrc             *E2ECRCParams          `protobuf:"bytes,10,opt,name=custom_crc,json=customCrc,proto3" json:"custom_crc,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *E2EProtection) Reset() {
+	*x = E2EProtection{}
+	mi := &file_acmelib_v2_message_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *E2EProtection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*E2EProtection) ProtoMessage() {}
+
+func (x *E2EProtection) ProtoReflect() protoreflect.Message {
+	mi := &file_acmelib_v2_message_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use E2EProtection.ProtoReflect.Descriptor instead.
+func (*E2EProtection) Descriptor() ([]byte, []int) {
+	return file_acmelib_v2_message_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *E2EProtection) GetProfile() E2EProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return E2EProfile_E2E_PROFILE_UNSPECIFIED
+}
+
+func (x *E2EProtection) GetCrcSignalEntityId() string {
+	if x != nil {
+		return x.CrcSignalEntityId
+	}
+	return ""
+}
+
+func (x *E2EProtection) GetCounterSignalEntityId() string {
+	if x != nil {
+		return x.CounterSignalEntityId
+	}
+	return ""
+}
+
+func (x *E2EProtection) GetDataId() uint32 {
+	if x != nil {
+		return x.DataId
+	}
+	return 0
+}
+
+func (x *E2EProtection) GetDataIdMode() E2EDataIDMode {
+	if x != nil {
+		return x.DataIdMode
+	}
+	return E2EDataIDMode_E2E_DATA_ID_MODE_UNSPECIFIED
+}
+
+func (x *E2EProtection) GetDataIdList() []byte {
+	if x != nil {
+		return x.DataIdList
+	}
+	return nil
+}
+
+func (x *E2EProtection) GetDataIdSignalEntityId() string {
+	if x != nil {
+		return x.DataIdSignalEntityId
+	}
+	return ""
+}
+
+func (x *E2EProtection) GetLengthSignalEntityId() string {
+	if x != nil {
+		return x.LengthSignalEntityId
+	}
+	return ""
+}
+
+func (x *E2EProtection) GetMaxDeltaCounter() uint64 {
+	if x != nil {
+		return x.MaxDeltaCounter
+	}
+	return 0
+}
+
+func (x *E2EProtection) GetCustomCrc() *E2ECRCParams {
+	if x != nil {
+		return x.CustomCrc
+	}
+	return nil
+}
+
+type E2ECRCParams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Width         uint32                 `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Polynomial    uint64                 `protobuf:"varint,2,opt,name=polynomial,proto3" json:"polynomial,omitempty"`
+	Init          uint64                 `protobuf:"varint,3,opt,name=init,proto3" json:"init,omitempty"`
+	XorOut        uint64                 `protobuf:"varint,4,opt,name=xor_out,json=xorOut,proto3" json:"xor_out,omitempty"`
+	RefIn         bool                   `protobuf:"varint,5,opt,name=ref_in,json=refIn,proto3" json:"ref_in,omitempty"`
+	RefOut        bool                   `protobuf:"varint,6,opt,name=ref_out,json=refOut,proto3" json:"ref_out,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *E2ECRCParams) Reset() {
+	*x = E2ECRCParams{}
+	mi := &file_acmelib_v2_message_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *E2ECRCParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*E2ECRCParams) ProtoMessage() {}
+
+func (x *E2ECRCParams) ProtoReflect() protoreflect.Message {
+	mi := &file_acmelib_v2_message_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use E2ECRCParams.ProtoReflect.Descriptor instead.
+func (*E2ECRCParams) Descriptor() ([]byte, []int) {
+	return file_acmelib_v2_message_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *E2ECRCParams) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *E2ECRCParams) GetPolynomial() uint64 {
+	if x != nil {
+		return x.Polynomial
+	}
+	return 0
+}
+
+func (x *E2ECRCParams) GetInit() uint64 {
+	if x != nil {
+		return x.Init
+	}
+	return 0
+}
+
+func (x *E2ECRCParams) GetXorOut() uint64 {
+	if x != nil {
+		return x.XorOut
+	}
+	return 0
+}
+
+func (x *E2ECRCParams) GetRefIn() bool {
+	if x != nil {
+		return x.RefIn
+	}
+	return false
+}
+
+func (x *E2ECRCParams) GetRefOut() bool {
+	if x != nil {
+		return x.RefOut
+	}
+	return false
+}
+
 var File_acmelib_v2_message_proto protoreflect.FileDescriptor
 
 const file_acmelib_v2_message_proto_rawDesc = "" +
 	"\n" +
 	"\x18acmelib/v2/message.proto\x12\n" +
-	"acmelib.v2\x1a\x17acmelib/v2/entity.proto\x1a\x17acmelib/v2/signal.proto\x1a\x1aacmelib/v2/attribute.proto\"\xde\x04\n" +
+	"acmelib.v2\x1a\x17acmelib/v2/entity.proto\x1a\x17acmelib/v2/signal.proto\x1a\x1aacmelib/v2/attribute.proto\"\x99\x06\n" +
 	"\aMessage\x12*\n" +
 	"\x06entity\x18\x01 \x01(\v2\x12.acmelib.v2.EntityR\x06entity\x120\n" +
 	"\x06layout\x18\x02 \x01(\v2\x18.acmelib.v2.SignalLayoutR\x06layout\x12\x1b\n" +
@@ -346,22 +797,76 @@ const file_acmelib_v2_message_proto_rawDesc = "" +
 	" \x01(\rR\tdelayTime\x12(\n" +
 	"\x10start_delay_time\x18\v \x01(\rR\x0estartDelayTime\x129\n" +
 	"\treceivers\x18\f \x03(\v2\x1b.acmelib.v2.MessageReceiverR\treceivers\x12T\n" +
-	"\x15attribute_assignments\x18\r \x03(\v2\x1f.acmelib.v2.AttributeAssignmentR\x14attributeAssignments\"k\n" +
+	"\x15attribute_assignments\x18\r \x03(\v2\x1f.acmelib.v2.AttributeAssignmentR\x14attributeAssignments\x12;\n" +
+	"\rcan_id_format\x18\x0e \x01(\x0e2\x17.acmelib.v2.CANIDFormatR\vcanIdFormat\x12:\n" +
+	"\ttransport\x18\x0f \x01(\x0e2\x1c.acmelib.v2.MessageTransportR\ttransport\x12@\n" +
+	"\x0ee2e_protection\x18\x10 \x01(\v2\x19.acmelib.v2.E2EProtectionR\re2eProtection\"k\n" +
 	"\x0fMessageReceiver\x12$\n" +
 	"\x0enode_entity_id\x18\x01 \x01(\tR\fnodeEntityId\x122\n" +
-	"\x15node_interface_number\x18\x02 \x01(\rR\x13nodeInterfaceNumber*\xa5\x01\n" +
+	"\x15node_interface_number\x18\x02 \x01(\rR\x13nodeInterfaceNumber\"\xf7\x03\n" +
+	"\rE2EProtection\x120\n" +
+	"\aprofile\x18\x01 \x01(\x0e2\x16.acmelib.v2.E2EProfileR\aprofile\x12/\n" +
+	"\x14crc_signal_entity_id\x18\x02 \x01(\tR\x11crcSignalEntityId\x127\n" +
+	"\x18counter_signal_entity_id\x18\x03 \x01(\tR\x15counterSignalEntityId\x12\x17\n" +
+	"\adata_id\x18\x04 \x01(\rR\x06dataId\x12;\n" +
+	"\fdata_id_mode\x18\x05 \x01(\x0e2\x19.acmelib.v2.E2EDataIDModeR\n" +
+	"dataIdMode\x12 \n" +
+	"\fdata_id_list\x18\x06 \x01(\fR\n" +
+	"dataIdList\x126\n" +
+	"\x18data_id_signal_entity_id\x18\a \x01(\tR\x14dataIdSignalEntityId\x125\n" +
+	"\x17length_signal_entity_id\x18\b \x01(\tR\x14lengthSignalEntityId\x12*\n" +
+	"\x11max_delta_counter\x18\t \x01(\x04R\x0fmaxDeltaCounter\x127\n" +
+	"\n" +
+	"custom_crc\x18\n" +
+	" \x01(\v2\x18.acmelib.v2.E2ECRCParamsR\tcustomCrc\"\xa1\x01\n" +
+	"\fE2ECRCParams\x12\x14\n" +
+	"\x05width\x18\x01 \x01(\rR\x05width\x12\x1e\n" +
+	"\n" +
+	"polynomial\x18\x02 \x01(\x04R\n" +
+	"polynomial\x12\x12\n" +
+	"\x04init\x18\x03 \x01(\x04R\x04init\x12\x17\n" +
+	"\axor_out\x18\x04 \x01(\x04R\x06xorOut\x12\x15\n" +
+	"\x06ref_in\x18\x05 \x01(\bR\x05refIn\x12\x17\n" +
+	"\aref_out\x18\x06 \x01(\bR\x06refOut*\x85\x02\n" +
 	"\x0fMessagePriority\x12 \n" +
 	"\x1cMESSAGE_PRIORITY_UNSPECIFIED\x10\x00\x12\x1e\n" +
 	"\x1aMESSAGE_PRIORITY_VERY_HIGH\x10\x01\x12\x19\n" +
 	"\x15MESSAGE_PRIORITY_HIGH\x10\x02\x12\x1b\n" +
 	"\x17MESSAGE_PRIORITY_MEDIUM\x10\x03\x12\x18\n" +
-	"\x14MESSAGE_PRIORITY_LOW\x10\x04*\xdc\x01\n" +
+	"\x14MESSAGE_PRIORITY_LOW\x10\x04\x12\x16\n" +
+	"\x12MESSAGE_PRIORITY_4\x10\x05\x12\x16\n" +
+	"\x12MESSAGE_PRIORITY_5\x10\x06\x12\x16\n" +
+	"\x12MESSAGE_PRIORITY_6\x10\a\x12\x16\n" +
+	"\x12MESSAGE_PRIORITY_7\x10\b*\xdc\x01\n" +
 	"\x0fMessageSendType\x12!\n" +
 	"\x1dMESSAGE_SEND_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
 	"\x18MESSAGE_SEND_TYPE_CYCLIC\x10\x01\x12&\n" +
 	"\"MESSAGE_SEND_TYPE_CYCLIC_IF_ACTIVE\x10\x02\x12*\n" +
 	"&MESSAGE_SEND_TYPE_CYCLIC_AND_TRIGGERED\x10\x03\x124\n" +
-	"0MESSAGE_SEND_TYPE_CYCLIC_IF_ACTIVE_AND_TRIGGERED\x10\x04B}\n" +
+	"0MESSAGE_SEND_TYPE_CYCLIC_IF_ACTIVE_AND_TRIGGERED\x10\x04*s\n" +
+	"\x10MessageTransport\x12!\n" +
+	"\x1dMESSAGE_TRANSPORT_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aMESSAGE_TRANSPORT_J1939_TP\x10\x01\x12\x1c\n" +
+	"\x18MESSAGE_TRANSPORT_ISO_TP\x10\x02*a\n" +
+	"\vCANIDFormat\x12\x1c\n" +
+	"\x18CANID_FORMAT_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15CANID_FORMAT_STANDARD\x10\x01\x12\x19\n" +
+	"\x15CANID_FORMAT_EXTENDED\x10\x02*\xa1\x01\n" +
+	"\n" +
+	"E2EProfile\x12\x1b\n" +
+	"\x17E2E_PROFILE_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rE2E_PROFILE_1\x10\x01\x12\x11\n" +
+	"\rE2E_PROFILE_2\x10\x02\x12\x11\n" +
+	"\rE2E_PROFILE_4\x10\x03\x12\x11\n" +
+	"\rE2E_PROFILE_5\x10\x04\x12\x12\n" +
+	"\x0eE2E_PROFILE_11\x10\x05\x12\x16\n" +
+	"\x12E2E_PROFILE_CUSTOM\x10\x06*\x9d\x01\n" +
+	"\rE2EDataIDMode\x12 \n" +
+	"\x1cE2E_DATA_ID_MODE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15E2E_DATA_ID_MODE_BOTH\x10\x01\x12\x18\n" +
+	"\x14E2E_DATA_ID_MODE_ALT\x10\x02\x12\x18\n" +
+	"\x14E2E_DATA_ID_MODE_LOW\x10\x03\x12\x1b\n" +
+	"\x17E2E_DATA_ID_MODE_NIBBLE\x10\x04B}\n" +
 	"\x0ecom.acmelib.v2B\fMessageProtoP\x01Z\x14acmelib/v2;acmelibv2\xa2\x02\x03AXX\xaa\x02\n" +
 	"Acmelib.V2\xca\x02\n" +
 	"Acmelib\\V2\xe2\x02\x16Acmelib\\V2\\GPBMetadata\xea\x02\vAcmelib::V2b\x06proto3"
@@ -378,29 +883,41 @@ func file_acmelib_v2_message_proto_rawDescGZIP() []byte {
 	return file_acmelib_v2_message_proto_rawDescData
 }
 
-var file_acmelib_v2_message_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_acmelib_v2_message_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_acmelib_v2_message_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_acmelib_v2_message_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_acmelib_v2_message_proto_goTypes = []any{
 	(MessagePriority)(0),        // 0: acmelib.v2.MessagePriority
 	(MessageSendType)(0),        // 1: acmelib.v2.MessageSendType
-	(*Message)(nil),             // 2: acmelib.v2.Message
-	(*MessageReceiver)(nil),     // 3: acmelib.v2.MessageReceiver
-	(*Entity)(nil),              // 4: acmelib.v2.Entity
-	(*SignalLayout)(nil),        // 5: acmelib.v2.SignalLayout
-	(*AttributeAssignment)(nil), // 6: acmelib.v2.AttributeAssignment
+	(MessageTransport)(0),       // 2: acmelib.v2.MessageTransport
+	(CANIDFormat)(0),            // 3: acmelib.v2.CANIDFormat
+	(E2EProfile)(0),             // 4: acmelib.v2.E2EProfile
+	(E2EDataIDMode)(0),          // 5: acmelib.v2.E2EDataIDMode
+	(*Message)(nil),             // 6: acmelib.v2.Message
+	(*MessageReceiver)(nil),     // 7: acmelib.v2.MessageReceiver
+	(*E2EProtection)(nil),       // 8: acmelib.v2.E2EProtection
+	(*E2ECRCParams)(nil),        // 9: acmelib.v2.E2ECRCParams
+	(*Entity)(nil),              // 10: acmelib.v2.Entity
+	(*SignalLayout)(nil),        // 11: acmelib.v2.SignalLayout
+	(*AttributeAssignment)(nil), // 12: acmelib.v2.AttributeAssignment
 }
 var file_acmelib_v2_message_proto_depIdxs = []int32{
-	4, // 0: acmelib.v2.Message.entity:type_name -> acmelib.v2.Entity
-	5, // 1: acmelib.v2.Message.layout:type_name -> acmelib.v2.SignalLayout
-	0, // 2: acmelib.v2.Message.priority:type_name -> acmelib.v2.MessagePriority
-	1, // 3: acmelib.v2.Message.send_type:type_name -> acmelib.v2.MessageSendType
-	3, // 4: acmelib.v2.Message.receivers:type_name -> acmelib.v2.MessageReceiver
-	6, // 5: acmelib.v2.Message.attribute_assignments:type_name -> acmelib.v2.AttributeAssignment
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	10, // 0: acmelib.v2.Message.entity:type_name -> acmelib.v2.Entity
+	11, // 1: acmelib.v2.Message.layout:type_name -> acmelib.v2.SignalLayout
+	0,  // 2: acmelib.v2.Message.priority:type_name -> acmelib.v2.MessagePriority
+	1,  // 3: acmelib.v2.Message.send_type:type_name -> acmelib.v2.MessageSendType
+	7,  // 4: acmelib.v2.Message.receivers:type_name -> acmelib.v2.MessageReceiver
+	12, // 5: acmelib.v2.Message.attribute_assignments:type_name -> acmelib.v2.AttributeAssignment
+	3,  // 6: acmelib.v2.Message.can_id_format:type_name -> acmelib.v2.CANIDFormat
+	2,  // 7: acmelib.v2.Message.transport:type_name -> acmelib.v2.MessageTransport
+	8,  // 8: acmelib.v2.Message.e2e_protection:type_name -> acmelib.v2.E2EProtection
+	4,  // 9: acmelib.v2.E2EProtection.profile:type_name -> acmelib.v2.E2EProfile
+	5,  // 10: acmelib.v2.E2EProtection.data_id_mode:type_name -> acmelib.v2.E2EDataIDMode
+	9,  // 11: acmelib.v2.E2EProtection.custom_crc:type_name -> acmelib.v2.E2ECRCParams
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_acmelib_v2_message_proto_init() }
@@ -416,8 +933,8 @@ func file_acmelib_v2_message_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_acmelib_v2_message_proto_rawDesc), len(file_acmelib_v2_message_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   2,
+			NumEnums:      6,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
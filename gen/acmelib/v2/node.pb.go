@@ -9,6 +9,7 @@ package acmelibv2
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -27,6 +28,7 @@ type Node struct {
 	NodeId               uint32                 `protobuf:"varint,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 	InterfaceCount       uint32                 `protobuf:"varint,3,opt,name=interface_count,json=interfaceCount,proto3" json:"interface_count,omitempty"`
 	AttributeAssignments []*AttributeAssignment `protobuf:"bytes,4,rep,name=attribute_assignments,json=attributeAssignments,proto3" json:"attribute_assignments,omitempty"`
+	GatewayRoutes        []*GatewayRoute        `protobuf:"bytes,5,rep,name=gateway_routes,json=gatewayRoutes,proto3" json:"gateway_routes,omitempty"`
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -89,6 +91,13 @@ func (x *Node) GetAttributeAssignments() []*AttributeAssignment {
 	return nil
 }
 
+func (x *Node) GetGatewayRoutes() []*GatewayRoute {
+	if x != nil {
+		return x.GatewayRoutes
+	}
+	return nil
+}
+
 type NodeInterface struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
@@ -149,21 +158,150 @@ func (x *NodeInterface) GetMessages() []*Message {
 	return nil
 }
 
+type GatewayRoute struct {
+	state                 protoimpl.MessageState  `protogen:"open.v1"`
+	SourceMessageEntityId string                  `protobuf:"bytes,1,opt,name=source_message_entity_id,json=sourceMessageEntityId,proto3" json:"source_message_entity_id,omitempty"`
+	TargetMessageEntityId string                  `protobuf:"bytes,2,opt,name=target_message_entity_id,json=targetMessageEntityId,proto3" json:"target_message_entity_id,omitempty"`
+	SignalMappings        []*GatewaySignalMapping `protobuf:"bytes,3,rep,name=signal_mappings,json=signalMappings,proto3" json:"signal_mappings,omitempty"`
+	ProcessingTime        *durationpb.Duration    `protobuf:"bytes,4,opt,name=processing_time,json=processingTime,proto3" json:"processing_time,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *GatewayRoute) Reset() {
+	*x = GatewayRoute{}
+	mi := &file_acmelib_v2_node_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GatewayRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayRoute) ProtoMessage() {}
+
+func (x *GatewayRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_acmelib_v2_node_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayRoute.ProtoReflect.Descriptor instead.
+func (*GatewayRoute) Descriptor() ([]byte, []int) {
+	return file_acmelib_v2_node_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GatewayRoute) GetSourceMessageEntityId() string {
+	if x != nil {
+		return x.SourceMessageEntityId
+	}
+	return ""
+}
+
+func (x *GatewayRoute) GetTargetMessageEntityId() string {
+	if x != nil {
+		return x.TargetMessageEntityId
+	}
+	return ""
+}
+
+func (x *GatewayRoute) GetSignalMappings() []*GatewaySignalMapping {
+	if x != nil {
+		return x.SignalMappings
+	}
+	return nil
+}
+
+func (x *GatewayRoute) GetProcessingTime() *durationpb.Duration {
+	if x != nil {
+		return x.ProcessingTime
+	}
+	return nil
+}
+
+type GatewaySignalMapping struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	SourceSignalEntityId string                 `protobuf:"bytes,1,opt,name=source_signal_entity_id,json=sourceSignalEntityId,proto3" json:"source_signal_entity_id,omitempty"`
+	TargetSignalEntityId string                 `protobuf:"bytes,2,opt,name=target_signal_entity_id,json=targetSignalEntityId,proto3" json:"target_signal_entity_id,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *GatewaySignalMapping) Reset() {
+	*x = GatewaySignalMapping{}
+	mi := &file_acmelib_v2_node_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GatewaySignalMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewaySignalMapping) ProtoMessage() {}
+
+func (x *GatewaySignalMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_acmelib_v2_node_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewaySignalMapping.ProtoReflect.Descriptor instead.
+func (*GatewaySignalMapping) Descriptor() ([]byte, []int) {
+	return file_acmelib_v2_node_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GatewaySignalMapping) GetSourceSignalEntityId() string {
+	if x != nil {
+		return x.SourceSignalEntityId
+	}
+	return ""
+}
+
+func (x *GatewaySignalMapping) GetTargetSignalEntityId() string {
+	if x != nil {
+		return x.TargetSignalEntityId
+	}
+	return ""
+}
+
 var File_acmelib_v2_node_proto protoreflect.FileDescriptor
 
 const file_acmelib_v2_node_proto_rawDesc = "" +
 	"\n" +
 	"\x15acmelib/v2/node.proto\x12\n" +
-	"acmelib.v2\x1a\x17acmelib/v2/entity.proto\x1a\x1aacmelib/v2/attribute.proto\x1a\x18acmelib/v2/message.proto\"\xca\x01\n" +
+	"acmelib.v2\x1a\x1egoogle/protobuf/duration.proto\x1a\x17acmelib/v2/entity.proto\x1a\x1aacmelib/v2/attribute.proto\x1a\x18acmelib/v2/message.proto\"\x8b\x02\n" +
 	"\x04Node\x12*\n" +
 	"\x06entity\x18\x01 \x01(\v2\x12.acmelib.v2.EntityR\x06entity\x12\x17\n" +
 	"\anode_id\x18\x02 \x01(\rR\x06nodeId\x12'\n" +
 	"\x0finterface_count\x18\x03 \x01(\rR\x0einterfaceCount\x12T\n" +
-	"\x15attribute_assignments\x18\x04 \x03(\v2\x1f.acmelib.v2.AttributeAssignmentR\x14attributeAssignments\"~\n" +
+	"\x15attribute_assignments\x18\x04 \x03(\v2\x1f.acmelib.v2.AttributeAssignmentR\x14attributeAssignments\x12?\n" +
+	"\x0egateway_routes\x18\x05 \x03(\v2\x18.acmelib.v2.GatewayRouteR\rgatewayRoutes\"~\n" +
 	"\rNodeInterface\x12\x16\n" +
 	"\x06number\x18\x01 \x01(\x05R\x06number\x12$\n" +
 	"\x0enode_entity_id\x18\x02 \x01(\tR\fnodeEntityId\x12/\n" +
-	"\bmessages\x18\x03 \x03(\v2\x13.acmelib.v2.MessageR\bmessagesBz\n" +
+	"\bmessages\x18\x03 \x03(\v2\x13.acmelib.v2.MessageR\bmessages\"\x8f\x02\n" +
+	"\fGatewayRoute\x127\n" +
+	"\x18source_message_entity_id\x18\x01 \x01(\tR\x15sourceMessageEntityId\x127\n" +
+	"\x18target_message_entity_id\x18\x02 \x01(\tR\x15targetMessageEntityId\x12I\n" +
+	"\x0fsignal_mappings\x18\x03 \x03(\v2 .acmelib.v2.GatewaySignalMappingR\x0esignalMappings\x12B\n" +
+	"\x0fprocessing_time\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x0eprocessingTime\"\x84\x01\n" +
+	"\x14GatewaySignalMapping\x125\n" +
+	"\x17source_signal_entity_id\x18\x01 \x01(\tR\x14sourceSignalEntityId\x125\n" +
+	"\x17target_signal_entity_id\x18\x02 \x01(\tR\x14targetSignalEntityIdBz\n" +
 	"\x0ecom.acmelib.v2B\tNodeProtoP\x01Z\x14acmelib/v2;acmelibv2\xa2\x02\x03AXX\xaa\x02\n" +
 	"Acmelib.V2\xca\x02\n" +
 	"Acmelib\\V2\xe2\x02\x16Acmelib\\V2\\GPBMetadata\xea\x02\vAcmelib::V2b\x06proto3"
@@ -180,23 +318,29 @@ func file_acmelib_v2_node_proto_rawDescGZIP() []byte {
 	return file_acmelib_v2_node_proto_rawDescData
 }
 
-var file_acmelib_v2_node_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_acmelib_v2_node_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_acmelib_v2_node_proto_goTypes = []any{
-	(*Node)(nil),                // 0: acmelib.v2.Node
-	(*NodeInterface)(nil),       // 1: acmelib.v2.NodeInterface
-	(*Entity)(nil),              // 2: acmelib.v2.Entity
-	(*AttributeAssignment)(nil), // 3: acmelib.v2.AttributeAssignment
-	(*Message)(nil),             // 4: acmelib.v2.Message
+	(*Node)(nil),                 // 0: acmelib.v2.Node
+	(*NodeInterface)(nil),        // 1: acmelib.v2.NodeInterface
+	(*GatewayRoute)(nil),         // 2: acmelib.v2.GatewayRoute
+	(*GatewaySignalMapping)(nil), // 3: acmelib.v2.GatewaySignalMapping
+	(*Entity)(nil),               // 4: acmelib.v2.Entity
+	(*AttributeAssignment)(nil),  // 5: acmelib.v2.AttributeAssignment
+	(*Message)(nil),              // 6: acmelib.v2.Message
+	(*durationpb.Duration)(nil),  // 7: google.protobuf.Duration
 }
 var file_acmelib_v2_node_proto_depIdxs = []int32{
-	2, // 0: acmelib.v2.Node.entity:type_name -> acmelib.v2.Entity
-	3, // 1: acmelib.v2.Node.attribute_assignments:type_name -> acmelib.v2.AttributeAssignment
-	4, // 2: acmelib.v2.NodeInterface.messages:type_name -> acmelib.v2.Message
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	4, // 0: acmelib.v2.Node.entity:type_name -> acmelib.v2.Entity
+	5, // 1: acmelib.v2.Node.attribute_assignments:type_name -> acmelib.v2.AttributeAssignment
+	2, // 2: acmelib.v2.Node.gateway_routes:type_name -> acmelib.v2.GatewayRoute
+	6, // 3: acmelib.v2.NodeInterface.messages:type_name -> acmelib.v2.Message
+	3, // 4: acmelib.v2.GatewayRoute.signal_mappings:type_name -> acmelib.v2.GatewaySignalMapping
+	7, // 5: acmelib.v2.GatewayRoute.processing_time:type_name -> google.protobuf.Duration
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_acmelib_v2_node_proto_init() }
@@ -213,7 +357,7 @@ func file_acmelib_v2_node_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_acmelib_v2_node_proto_rawDesc), len(file_acmelib_v2_node_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
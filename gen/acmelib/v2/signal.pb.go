@@ -193,6 +193,7 @@ const (
 	SignalTypeKind_SIGNAL_TYPE_KIND_FLAG        SignalTypeKind = 1
 	SignalTypeKind_SIGNAL_TYPE_KIND_INTEGER     SignalTypeKind = 2
 	SignalTypeKind_SIGNAL_TYPE_KIND_DECIMAL     SignalTypeKind = 3
+	SignalTypeKind_SIGNAL_TYPE_KIND_FLOAT       SignalTypeKind = 4
 )
 
 // Enum value maps for SignalTypeKind.
@@ -202,12 +203,14 @@ var (
 		1: "SIGNAL_TYPE_KIND_FLAG",
 		2: "SIGNAL_TYPE_KIND_INTEGER",
 		3: "SIGNAL_TYPE_KIND_DECIMAL",
+		4: "SIGNAL_TYPE_KIND_FLOAT",
 	}
 	SignalTypeKind_value = map[string]int32{
 		"SIGNAL_TYPE_KIND_UNSPECIFIED": 0,
 		"SIGNAL_TYPE_KIND_FLAG":        1,
 		"SIGNAL_TYPE_KIND_INTEGER":     2,
 		"SIGNAL_TYPE_KIND_DECIMAL":     3,
+		"SIGNAL_TYPE_KIND_FLOAT":       4,
 	}
 )
 
@@ -1078,12 +1081,13 @@ const file_acmelib_v2_signal_proto_rawDesc = "" +
 	"\x1aSIGNAL_SEND_TYPE_ON_CHANGE\x10\x04\x12.\n" +
 	"*SIGNAL_SEND_TYPE_ON_CHANGE_WITH_REPETITION\x10\x05\x12\x1e\n" +
 	"\x1aSIGNAL_SEND_TYPE_IF_ACTIVE\x10\x06\x12.\n" +
-	"*SIGNAL_SEND_TYPE_IF_ACTIVE_WITH_REPETITION\x10\a*\x89\x01\n" +
+	"*SIGNAL_SEND_TYPE_IF_ACTIVE_WITH_REPETITION\x10\a*\xa5\x01\n" +
 	"\x0eSignalTypeKind\x12 \n" +
 	"\x1cSIGNAL_TYPE_KIND_UNSPECIFIED\x10\x00\x12\x19\n" +
 	"\x15SIGNAL_TYPE_KIND_FLAG\x10\x01\x12\x1c\n" +
 	"\x18SIGNAL_TYPE_KIND_INTEGER\x10\x02\x12\x1c\n" +
-	"\x18SIGNAL_TYPE_KIND_DECIMAL\x10\x03*\xae\x01\n" +
+	"\x18SIGNAL_TYPE_KIND_DECIMAL\x10\x03\x12\x1a\n" +
+	"\x16SIGNAL_TYPE_KIND_FLOAT\x10\x04*\xae\x01\n" +
 	"\x0eSignalUnitKind\x12 \n" +
 	"\x1cSIGNAL_UNIT_KIND_UNSPECIFIED\x10\x00\x12\x1b\n" +
 	"\x17SIGNAL_UNIT_KIND_CUSTOM\x10\x01\x12 \n" +
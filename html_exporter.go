@@ -0,0 +1,611 @@
+package acmelib
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ExportHTMLNetwork exports the given [Network] to a static HTML site.
+// It will create a directory with the given base path and network name.
+// Into the directory, it will create an index page with a searchable list
+// of all the buses, nodes, messages and signals, and a page for each [Bus],
+// [Node] and [Message] of the network.
+//
+// The pages do not load any external resource, so the site can be browsed offline.
+// The page of a message embeds the bit grid of its [SignalLayout]
+// as rendered by [SignalLayout.RenderSVG].
+func ExportHTMLNetwork(network *Network, basePath string) error {
+	netName := clearSpaces(network.name)
+	dirPath := netName
+	if len(basePath) > 0 {
+		dirPath = filepath.Join(basePath, netName)
+	}
+
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return err
+	}
+
+	exp := newHTMLExporter()
+	exp.collect(network)
+
+	for _, page := range exp.exportNetwork(network) {
+		if err := exp.writePage(filepath.Join(dirPath, page.filename), page); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type htmlLink struct {
+	Name string
+	Href string
+}
+
+type htmlPage struct {
+	filename string
+	template string
+
+	Title  string
+	NetRef htmlLink
+	Data   any
+}
+
+type htmlSearchEntry struct {
+	Kind string
+	Link htmlLink
+	Bus  string
+	Info string
+}
+
+type htmlIndexData struct {
+	Desc    string
+	Buses   []*htmlBusRow
+	Entries []*htmlSearchEntry
+}
+
+type htmlBusRow struct {
+	Link         htmlLink
+	Type         string
+	Baudrate     string
+	NodeCount    int
+	MessageCount int
+}
+
+type htmlMessageRow struct {
+	Link      htmlLink
+	CANID     string
+	Size      int
+	CycleTime string
+	SendType  string
+	Sender    htmlLink
+}
+
+type htmlBusData struct {
+	Desc         string
+	Type         string
+	Baudrate     string
+	DataBaudrate string
+	Nodes        []*htmlNodeRow
+	Messages     []*htmlMessageRow
+}
+
+type htmlNodeRow struct {
+	Link      htmlLink
+	ID        string
+	Interface int
+}
+
+type htmlNodeData struct {
+	Desc       string
+	ID         string
+	Interfaces []*htmlNodeInterfaceData
+}
+
+type htmlNodeInterfaceData struct {
+	Number   int
+	Bus      htmlLink
+	Sent     []*htmlMessageRow
+	Received []*htmlMessageRow
+}
+
+type htmlMessageData struct {
+	Desc      string
+	Bus       htmlLink
+	Sender    htmlLink
+	Receivers []htmlLink
+	CANID     string
+	CANIDKind string
+	Format    string
+	Size      int
+	CycleTime string
+	SendType  string
+	Grid      template.HTML
+	Signals   []*htmlSignalRow
+}
+
+type htmlSignalRow struct {
+	Colour     string
+	Name       string
+	Layout     string
+	StartPos   int
+	Size       int
+	Endianness string
+	Type       string
+	Range      string
+	Unit       string
+	Enum       string
+	EnumValues string
+	Desc       string
+}
+
+type htmlExporter struct {
+	fileNames map[string]bool
+	hrefs     map[any]string
+}
+
+func newHTMLExporter() *htmlExporter {
+	return &htmlExporter{
+		fileNames: make(map[string]bool),
+		hrefs:     make(map[any]string),
+	}
+}
+
+// addFileName generates a unique file name for the page of the given entity.
+func (e *htmlExporter) addFileName(key any, prefix, name string) {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	baseName := b.String()
+	fileName := baseName
+	for suffix := 1; e.fileNames[fileName]; suffix++ {
+		fileName = fmt.Sprintf("%s_%d", baseName, suffix)
+	}
+	e.fileNames[fileName] = true
+
+	e.hrefs[key] = fileName + ".html"
+}
+
+func (e *htmlExporter) link(key any, name string) htmlLink {
+	return htmlLink{Name: name, Href: e.hrefs[key]}
+}
+
+// collect generates the file names of all the pages,
+// so the pages can link each other.
+func (e *htmlExporter) collect(net *Network) {
+	e.fileNames["index"] = true
+
+	for _, bus := range net.Buses() {
+		e.addFileName(bus, "bus_", bus.name)
+	}
+
+	for _, bus := range net.Buses() {
+		for _, nodeInt := range bus.NodeInterfaces() {
+			node := nodeInt.node
+			if _, ok := e.hrefs[node]; !ok {
+				e.addFileName(node, "node_", node.name)
+			}
+		}
+	}
+
+	for _, bus := range net.Buses() {
+		for _, msg := range e.getBusMessages(bus) {
+			e.addFileName(msg, "msg_", bus.name+"_"+msg.name)
+		}
+	}
+}
+
+func (e *htmlExporter) getBusMessages(bus *Bus) []*Message {
+	msgs := []*Message{}
+	for _, nodeInt := range bus.NodeInterfaces() {
+		msgs = append(msgs, nodeInt.SentMessages()...)
+	}
+
+	slices.SortStableFunc(msgs, func(a, b *Message) int {
+		return cmp.Compare(a.GetCANID(), b.GetCANID())
+	})
+
+	return msgs
+}
+
+func (e *htmlExporter) writePage(path string, page *htmlPage) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return e.renderPage(f, page)
+}
+
+func (e *htmlExporter) renderPage(w io.Writer, page *htmlPage) error {
+	return htmlTemplates.ExecuteTemplate(w, page.template, page)
+}
+
+func (e *htmlExporter) exportNetwork(net *Network) []*htmlPage {
+	netRef := htmlLink{Name: net.name, Href: "index.html"}
+
+	index := &htmlIndexData{Desc: net.desc}
+	pages := []*htmlPage{
+		{filename: "index.html", template: "index", Title: net.name, NetRef: netRef, Data: index},
+	}
+
+	nodePages := make(map[EntityID]*htmlNodeData)
+
+	for _, bus := range net.Buses() {
+		busLink := e.link(bus, bus.name)
+		busData := e.exportBus(bus)
+		pages = append(pages, &htmlPage{
+			filename: busLink.Href, template: "bus", Title: bus.name, NetRef: netRef, Data: busData,
+		})
+
+		index.Buses = append(index.Buses, &htmlBusRow{
+			Link:         busLink,
+			Type:         busData.Type,
+			Baudrate:     busData.Baudrate,
+			NodeCount:    len(busData.Nodes),
+			MessageCount: len(busData.Messages),
+		})
+		index.Entries = append(index.Entries, &htmlSearchEntry{Kind: "bus", Link: busLink, Bus: bus.name, Info: busData.Type})
+
+		for _, nodeInt := range bus.NodeInterfaces() {
+			node := nodeInt.node
+
+			nodeData, ok := nodePages[node.entityID]
+			if !ok {
+				nodeData = &htmlNodeData{Desc: node.desc, ID: e.formatHex(uint64(node.id))}
+				nodePages[node.entityID] = nodeData
+
+				nodeLink := e.link(node, node.name)
+				pages = append(pages, &htmlPage{
+					filename: nodeLink.Href, template: "node", Title: node.name, NetRef: netRef, Data: nodeData,
+				})
+				index.Entries = append(index.Entries, &htmlSearchEntry{Kind: "node", Link: nodeLink, Bus: bus.name, Info: "ID " + nodeData.ID})
+			}
+
+			nodeData.Interfaces = append(nodeData.Interfaces, e.exportNodeInterface(nodeInt))
+		}
+
+		for _, msg := range e.getBusMessages(bus) {
+			msgLink := e.link(msg, msg.name)
+			msgData := e.exportMessage(bus, msg)
+			pages = append(pages, &htmlPage{
+				filename: msgLink.Href, template: "message", Title: msg.name, NetRef: netRef, Data: msgData,
+			})
+
+			index.Entries = append(index.Entries, &htmlSearchEntry{Kind: "message", Link: msgLink, Bus: bus.name, Info: "CAN-ID " + msgData.CANID})
+			for _, sigRow := range msgData.Signals {
+				index.Entries = append(index.Entries, &htmlSearchEntry{
+					Kind: "signal",
+					Link: htmlLink{Name: sigRow.Name, Href: msgLink.Href},
+					Bus:  bus.name,
+					Info: msg.name,
+				})
+			}
+		}
+	}
+
+	return pages
+}
+
+func (e *htmlExporter) formatHex(val uint64) string {
+	return "0x" + strings.ToUpper(strconv.FormatUint(val, 16))
+}
+
+func (e *htmlExporter) formatBaudrate(baudrate int) string {
+	if baudrate == 0 {
+		return "-"
+	}
+	return strconv.Itoa(baudrate) + " bps"
+}
+
+func (e *htmlExporter) formatCycleTime(cycleTime int) string {
+	if cycleTime == 0 {
+		return "-"
+	}
+	return strconv.Itoa(cycleTime) + " ms"
+}
+
+func (e *htmlExporter) exportMessageRow(msg *Message) *htmlMessageRow {
+	row := &htmlMessageRow{
+		Link:      e.link(msg, msg.name),
+		CANID:     e.formatHex(uint64(msg.GetCANID())),
+		Size:      msg.sizeByte,
+		CycleTime: e.formatCycleTime(msg.cycleTime),
+		SendType:  msg.sendType.String(),
+	}
+
+	if msg.senderNodeInt != nil {
+		sender := msg.senderNodeInt.node
+		row.Sender = e.link(sender, sender.name)
+	}
+
+	return row
+}
+
+func (e *htmlExporter) exportBus(bus *Bus) *htmlBusData {
+	busData := &htmlBusData{
+		Desc:     bus.desc,
+		Type:     bus.typ.String(),
+		Baudrate: e.formatBaudrate(bus.baudrate),
+	}
+
+	if bus.typ == BusTypeCANFD {
+		busData.DataBaudrate = e.formatBaudrate(bus.dataBaudrate)
+	}
+
+	for _, nodeInt := range bus.NodeInterfaces() {
+		node := nodeInt.node
+		busData.Nodes = append(busData.Nodes, &htmlNodeRow{
+			Link:      e.link(node, node.name),
+			ID:        e.formatHex(uint64(node.id)),
+			Interface: nodeInt.number,
+		})
+	}
+
+	for _, msg := range e.getBusMessages(bus) {
+		busData.Messages = append(busData.Messages, e.exportMessageRow(msg))
+	}
+
+	return busData
+}
+
+func (e *htmlExporter) exportNodeInterface(nodeInt *NodeInterface) *htmlNodeInterfaceData {
+	bus := nodeInt.parentBus
+
+	intData := &htmlNodeInterfaceData{
+		Number: nodeInt.number,
+		Bus:    e.link(bus, bus.name),
+	}
+
+	for _, msg := range nodeInt.SentMessages() {
+		intData.Sent = append(intData.Sent, e.exportMessageRow(msg))
+	}
+
+	for _, msg := range nodeInt.ReceivedMessages() {
+		intData.Received = append(intData.Received, e.exportMessageRow(msg))
+	}
+
+	return intData
+}
+
+func (e *htmlExporter) exportMessage(bus *Bus, msg *Message) *htmlMessageData {
+	msgRow := e.exportMessageRow(msg)
+
+	msgData := &htmlMessageData{
+		Desc:      msg.desc,
+		Bus:       e.link(bus, bus.name),
+		Sender:    msgRow.Sender,
+		CANID:     msgRow.CANID,
+		CANIDKind: "generated",
+		Format:    msg.canIDFormat.String(),
+		Size:      msg.sizeByte,
+		CycleTime: msgRow.CycleTime,
+		SendType:  msgRow.SendType,
+	}
+
+	if msg.hasStaticCANID {
+		msgData.CANIDKind = "static"
+	}
+
+	for _, recInt := range msg.Receivers() {
+		msgData.Receivers = append(msgData.Receivers, e.link(recInt.node, recInt.node.name))
+	}
+
+	// the renderer of the grid gives the colours of the signals in the table
+	r := newSignalLayoutRenderer()
+
+	var grid strings.Builder
+	if err := r.writeSVG(&grid, msg.layout); err != nil {
+		panic(err)
+	}
+	msgData.Grid = template.HTML(grid.String())
+
+	e.exportSignalLayout(msgData, r, msg.layout, "")
+
+	return msgData
+}
+
+// exportSignalLayout adds the signals of the layout to the table of the message,
+// followed by the signals of the layouts of its multiplexed layers.
+func (e *htmlExporter) exportSignalLayout(msgData *htmlMessageData, r *signalLayoutRenderer, layout *SignalLayout, path string) {
+	for _, sig := range layout.Signals() {
+		msgData.Signals = append(msgData.Signals, e.exportSignal(r, sig, path))
+	}
+
+	for _, muxLayer := range layout.MultiplexedLayers() {
+		for layoutID, muxLayout := range muxLayer.iterLayouts() {
+			if muxLayout.SignalCount() == 0 {
+				continue
+			}
+
+			layoutPath := fmt.Sprintf("%s = %d", muxLayer.muxor.name, layoutID)
+			if len(path) > 0 {
+				layoutPath = path + " / " + layoutPath
+			}
+
+			e.exportSignalLayout(msgData, r, muxLayout, layoutPath)
+		}
+	}
+}
+
+func (e *htmlExporter) exportSignal(r *signalLayoutRenderer, sig Signal, path string) *htmlSignalRow {
+	_, colour := r.getLabel(sig)
+
+	sigRow := &htmlSignalRow{
+		Colour:     colour,
+		Name:       sig.Name(),
+		Layout:     path,
+		StartPos:   sig.StartPos(),
+		Size:       sig.Size(),
+		Endianness: sig.Endianness().String(),
+		Desc:       sig.Desc(),
+	}
+
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		sigType := stdSig.typ
+		sigRow.Type = sigType.name
+		sigRow.Range = fmt.Sprintf("%g .. %g", sigType.min, sigType.max)
+		if stdSig.unit != nil {
+			sigRow.Unit = stdSig.unit.symbol
+		}
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		sigEnum := enumSig.enum
+		sigRow.Type = "enum"
+		sigRow.Range = fmt.Sprintf("0 .. %d", sigEnum.maxIndex)
+
+		values := []string{}
+		for _, val := range sigEnum.values {
+			values = append(values, fmt.Sprintf("%d = %s", val.index, val.name))
+		}
+		sigRow.Enum = sigEnum.name
+		sigRow.EnumValues = strings.Join(values, "\n")
+
+	case SignalKindMuxor:
+		muxor, err := sig.ToMuxor()
+		if err != nil {
+			panic(err)
+		}
+
+		sigRow.Type = "muxor"
+		sigRow.Range = fmt.Sprintf("0 .. %d", muxor.layoutCount-1)
+	}
+
+	return sigRow
+}
+
+var htmlTemplates = template.Must(template.New("html").Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 0 auto; max-width: 1200px; padding: 0 16px; color: #222; }
+nav { padding: 12px 0; border-bottom: 1px solid #ddd; margin-bottom: 16px; }
+table { border-collapse: collapse; margin: 8px 0 24px; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+dt { font-weight: bold; float: left; clear: left; width: 140px; }
+dd { margin: 0 0 4px 150px; }
+.desc { white-space: pre-wrap; }
+.swatch { display: inline-block; width: 12px; height: 12px; border: 1px solid #555; }
+#search { width: 100%; padding: 6px; font-size: 16px; box-sizing: border-box; }
+</style>
+</head>
+<body>
+<nav><a href="{{.NetRef.Href}}">{{.NetRef.Name}}</a></nav>
+<h1>{{.Title}}</h1>
+{{end}}
+
+{{define "footer"}}<footer><p>Generated by <a href="https://github.com/squadracorsepolito/acmelib">acmelib</a></p></footer>
+</body>
+</html>
+{{end}}
+
+{{define "messages"}}<table>
+<tr><th>Message</th><th>CAN-ID</th><th>Size (bytes)</th><th>Cycle Time</th><th>Send Type</th><th>Sender</th></tr>
+{{range .}}<tr><td><a href="{{.Link.Href}}">{{.Link.Name}}</a></td><td><code>{{.CANID}}</code></td><td>{{.Size}}</td><td>{{.CycleTime}}</td><td>{{.SendType}}</td><td>{{if .Sender.Href}}<a href="{{.Sender.Href}}">{{.Sender.Name}}</a>{{else}}-{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{define "index"}}{{template "header" .}}{{with .Data}}
+{{if .Desc}}<p class="desc">{{.Desc}}</p>{{end}}
+<h2>Buses</h2>
+<table>
+<tr><th>Bus</th><th>Type</th><th>Baudrate</th><th>Nodes</th><th>Messages</th></tr>
+{{range .Buses}}<tr><td><a href="{{.Link.Href}}">{{.Link.Name}}</a></td><td>{{.Type}}</td><td>{{.Baudrate}}</td><td>{{.NodeCount}}</td><td>{{.MessageCount}}</td></tr>
+{{end}}</table>
+<h2>Search</h2>
+<input id="search" type="search" placeholder="Search buses, nodes, messages and signals">
+<table id="entries">
+<tr><th>Kind</th><th>Name</th><th>Bus</th><th>Info</th></tr>
+{{range .Entries}}<tr><td>{{.Kind}}</td><td><a href="{{.Link.Href}}">{{.Link.Name}}</a></td><td>{{.Bus}}</td><td>{{.Info}}</td></tr>
+{{end}}</table>
+<script>
+document.getElementById("search").addEventListener("input", function () {
+  var query = this.value.toLowerCase();
+  var rows = document.querySelectorAll("#entries tr");
+  for (var i = 1; i < rows.length; i++) {
+    rows[i].style.display = rows[i].textContent.toLowerCase().indexOf(query) >= 0 ? "" : "none";
+  }
+});
+</script>
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "bus"}}{{template "header" .}}{{with .Data}}
+{{if .Desc}}<p class="desc">{{.Desc}}</p>{{end}}
+<dl>
+<dt>Type</dt><dd>{{.Type}}</dd>
+<dt>Baudrate</dt><dd>{{.Baudrate}}</dd>
+{{if .DataBaudrate}}<dt>Data Baudrate</dt><dd>{{.DataBaudrate}}</dd>{{end}}
+</dl>
+<h2>Nodes</h2>
+<table>
+<tr><th>Node</th><th>ID</th><th>Interface</th></tr>
+{{range .Nodes}}<tr><td><a href="{{.Link.Href}}">{{.Link.Name}}</a></td><td><code>{{.ID}}</code></td><td>{{.Interface}}</td></tr>
+{{end}}</table>
+<h2>Messages</h2>
+{{template "messages" .Messages}}
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "node"}}{{template "header" .}}{{with .Data}}
+{{if .Desc}}<p class="desc">{{.Desc}}</p>{{end}}
+<dl>
+<dt>Node ID</dt><dd><code>{{.ID}}</code></dd>
+</dl>
+{{range .Interfaces}}<h2>Interface {{.Number}} on <a href="{{.Bus.Href}}">{{.Bus.Name}}</a></h2>
+<h3>Sent Messages</h3>
+{{if .Sent}}{{template "messages" .Sent}}{{else}}<p>-</p>{{end}}
+<h3>Received Messages</h3>
+{{if .Received}}{{template "messages" .Received}}{{else}}<p>-</p>{{end}}
+{{end}}
+{{end}}{{template "footer" .}}{{end}}
+
+{{define "message"}}{{template "header" .}}{{with .Data}}
+{{if .Desc}}<p class="desc">{{.Desc}}</p>{{end}}
+<dl>
+<dt>Bus</dt><dd><a href="{{.Bus.Href}}">{{.Bus.Name}}</a></dd>
+<dt>Sender</dt><dd>{{if .Sender.Href}}<a href="{{.Sender.Href}}">{{.Sender.Name}}</a>{{else}}-{{end}}</dd>
+<dt>Receivers</dt><dd>{{range $idx, $rec := .Receivers}}{{if $idx}}, {{end}}<a href="{{$rec.Href}}">{{$rec.Name}}</a>{{else}}-{{end}}</dd>
+<dt>CAN-ID</dt><dd><code>{{.CANID}}</code> ({{.CANIDKind}}, {{.Format}})</dd>
+<dt>Size</dt><dd>{{.Size}} bytes</dd>
+<dt>Cycle Time</dt><dd>{{.CycleTime}}</dd>
+<dt>Send Type</dt><dd>{{.SendType}}</dd>
+</dl>
+<h2>Layout</h2>
+{{.Grid}}
+<h2>Signals</h2>
+<table>
+<tr><th></th><th>Name</th><th>Layout</th><th>Start Bit</th><th>Size</th><th>Endianness</th><th>Type</th><th>Range</th><th>Unit</th><th>Enum</th><th>Description</th></tr>
+{{range .Signals}}<tr><td><span class="swatch" style="background: {{.Colour}}"></span></td><td>{{.Name}}</td><td>{{or .Layout "-"}}</td><td>{{.StartPos}}</td><td>{{.Size}}</td><td>{{.Endianness}}</td><td>{{.Type}}</td><td>{{.Range}}</td><td>{{or .Unit "-"}}</td><td>{{if .Enum}}<span title="{{.EnumValues}}">{{.Enum}}</span>{{else}}-{{end}}</td><td>{{or .Desc "-"}}</td></tr>
+{{end}}</table>
+{{end}}{{template "footer" .}}{{end}}
+`))
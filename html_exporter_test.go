@@ -0,0 +1,79 @@
+package acmelib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportHTMLNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	tdNet := initNetwork(assert)
+
+	basePath := t.TempDir()
+	assert.NoError(ExportHTMLNetwork(tdNet.net, basePath))
+
+	dirPath := filepath.Join(basePath, "network")
+	readPage := func(filename string) string {
+		content, err := os.ReadFile(filepath.Join(dirPath, filename))
+		assert.NoError(err)
+		return string(content)
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	assert.NoError(err)
+	// index, 1 bus, 2 nodes and 6 messages
+	assert.Len(entries, 10)
+
+	// index page
+	index := readPage("index.html")
+	assert.Contains(index, `<input id="search" type="search"`)
+	assert.Contains(index, `<td><a href="bus_bus.html">bus</a></td><td>CAN_2.0A</td><td>-</td><td>2</td><td>6</td>`)
+	assert.Contains(index, `<tr><td>signal</td><td><a href="msg_bus_mux_message.html">top_inner_signal_in_0</a></td><td>bus</td><td>mux_message</td></tr>`)
+	assert.NotContains(index, "http://")
+
+	// bus page
+	bus := readPage("bus_bus.html")
+	assert.Contains(bus, `<td><a href="node_rec_node_0.html">rec_node_0</a></td><td><code>0x1</code></td>`)
+	assert.Contains(bus, `<td><a href="msg_bus_basic_message.html">basic_message</a></td><td><code>0x1</code></td><td>8</td>`)
+
+	// should link the sent and the received messages
+	node := readPage("node_node_0.html")
+	assert.Contains(node, `<h2>Interface 0 on <a href="bus_bus.html">bus</a></h2>`)
+	assert.Contains(node, `<a href="msg_bus_enum_message.html">enum_message</a>`)
+	recNode := readPage("node_rec_node_0.html")
+	assert.Contains(recNode, `<a href="msg_bus_enum_message.html">enum_message</a>`)
+	assert.Contains(recNode, `<a href="node_node_0.html">node_0</a>`)
+
+	// message page
+	msg := readPage("msg_bus_mux_message.html")
+	assert.Contains(msg, `<dt>Sender</dt><dd><a href="node_node_0.html">node_0</a></dd>`)
+	assert.Contains(msg, `<dt>Receivers</dt><dd><a href="node_rec_node_0.html">rec_node_0</a></dd>`)
+	assert.Contains(msg, `<td>top_signal_in_0_2</td><td>top_muxor = 2</td><td>16</td><td>8</td>`)
+
+	// should embed the grid rendered by the signal layout
+	var grid strings.Builder
+	assert.NoError(tdNet.messages.mux.message.SignalLayout().RenderSVG(&grid))
+	assert.Contains(msg, grid.String())
+	assert.Contains(msg, `<text x="0" y="18" font-size="14" font-weight="bold">top_muxor = 1 / top_inner_muxor = 0</text>`)
+	assert.Contains(msg, `<title>top_inner_signal_in_0</title>`)
+}
+
+func Test_htmlExporter_addFileName(t *testing.T) {
+	assert := assert.New(t)
+
+	e := newHTMLExporter()
+	e.fileNames["index"] = true
+
+	e.addFileName(1, "", "index")
+	e.addFileName(2, "msg_", "engine status/1")
+	e.addFileName(3, "msg_", "engine_status_1")
+
+	assert.Equal("index_1.html", e.hrefs[1])
+	assert.Equal("msg_engine_status_1.html", e.hrefs[2])
+	assert.Equal("msg_engine_status_1_1.html", e.hrefs[3])
+}
@@ -0,0 +1,8 @@
+// Package isotp implements the segmentation and the reassembly of
+// the payloads transported with the ISO-TP protocol (ISO 15765-2),
+// both for CAN 2.0 frames (8 bytes) and for CAN FD frames (up to 64 bytes).
+//
+// It handles the single, first, consecutive and flow control frames,
+// the padding of the frames and the escape sequences used
+// by the CAN FD frames and by the payloads bigger than 4095 bytes.
+package isotp
@@ -0,0 +1,21 @@
+package isotp
+
+import "errors"
+
+var (
+	// ErrInvalidFrame is returned when a frame is malformed.
+	ErrInvalidFrame = errors.New("invalid frame")
+	// ErrUnexpectedFrame is returned when a frame is received in the wrong state,
+	// for example a consecutive frame without a first frame.
+	ErrUnexpectedFrame = errors.New("unexpected frame")
+	// ErrWrongSequence is returned when the sequence number of
+	// a consecutive frame is not the expected one.
+	ErrWrongSequence = errors.New("wrong sequence number")
+	// ErrInvalidFrameSize is returned when the frame size of the options
+	// is not a valid CAN or CAN FD payload size.
+	ErrInvalidFrameSize = errors.New("invalid frame size")
+	// ErrTooBig is returned when a payload exceeds the maximum size.
+	ErrTooBig = errors.New("too big")
+	// ErrIsEmpty is returned when a payload is empty.
+	ErrIsEmpty = errors.New("is empty")
+)
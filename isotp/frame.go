@@ -0,0 +1,271 @@
+package isotp
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// MaxSizeByte is the maximum size of a payload that can be announced
+	// by a first frame without the escape sequence.
+	MaxSizeByte = 4095
+	// MaxEscapeSizeByte is the maximum size of a payload that can be announced
+	// by a first frame with the escape sequence.
+	MaxEscapeSizeByte = 1<<32 - 1
+
+	// ClassicFrameSize is the size of a CAN 2.0 frame.
+	ClassicFrameSize = 8
+	// MaxFDFrameSize is the size of the biggest CAN FD frame.
+	MaxFDFrameSize = 64
+
+	// DefaultPaddingByte is the byte used to pad the frames by default.
+	DefaultPaddingByte byte = 0xCC
+)
+
+// fdFrameSizes are the valid sizes of a CAN FD frame bigger than 8 bytes.
+var fdFrameSizes = []int{12, 16, 20, 24, 32, 48, 64}
+
+// FrameType is the type of an ISO-TP frame, as encoded
+// in the upper nibble of the first byte (protocol control information).
+type FrameType int
+
+const (
+	// FrameTypeSingle defines a single frame, which carries the whole payload.
+	FrameTypeSingle FrameType = iota
+	// FrameTypeFirst defines the first frame of a segmented payload.
+	FrameTypeFirst
+	// FrameTypeConsecutive defines a consecutive frame of a segmented payload.
+	FrameTypeConsecutive
+	// FrameTypeFlowControl defines a flow control frame sent by the receiver.
+	FrameTypeFlowControl
+)
+
+func (ft FrameType) String() string {
+	switch ft {
+	case FrameTypeSingle:
+		return "single"
+	case FrameTypeFirst:
+		return "first"
+	case FrameTypeConsecutive:
+		return "consecutive"
+	case FrameTypeFlowControl:
+		return "flow-control"
+	default:
+		return "unknown"
+	}
+}
+
+// GetFrameType returns the [FrameType] of the given frame.
+//
+// It returns an [ErrInvalidFrame] if the frame is empty or the type is unknown.
+func GetFrameType(frame []byte) (FrameType, error) {
+	if len(frame) == 0 {
+		return 0, ErrInvalidFrame
+	}
+
+	ft := FrameType(frame[0] >> 4)
+	if ft > FrameTypeFlowControl {
+		return 0, ErrInvalidFrame
+	}
+
+	return ft, nil
+}
+
+// FlowStatus is the status carried by a flow control frame.
+type FlowStatus uint8
+
+const (
+	// FlowStatusContinueToSend defines that the sender can send the next block.
+	FlowStatusContinueToSend FlowStatus = iota
+	// FlowStatusWait defines that the sender must wait for another flow control frame.
+	FlowStatusWait
+	// FlowStatusOverflow defines that the payload is too big for the receiver,
+	// so the transmission is aborted.
+	FlowStatusOverflow
+)
+
+func (fs FlowStatus) String() string {
+	switch fs {
+	case FlowStatusContinueToSend:
+		return "continue-to-send"
+	case FlowStatusWait:
+		return "wait"
+	case FlowStatusOverflow:
+		return "overflow"
+	default:
+		return "unknown"
+	}
+}
+
+// FlowControl is the content of a flow control frame.
+type FlowControl struct {
+	// Status is the flow status.
+	Status FlowStatus
+	// BlockSize is the number of consecutive frames that can be sent
+	// before waiting for the next flow control frame.
+	// A block size of 0 means that all the frames can be sent.
+	BlockSize uint8
+	// STMin is the minimum separation time between two consecutive frames.
+	STMin time.Duration
+}
+
+// encodeSTMin returns the byte representation of the separation time.
+// The separation time is rounded up to the next representable value.
+func encodeSTMin(stMin time.Duration) byte {
+	if stMin <= 0 {
+		return 0
+	}
+
+	if stMin < time.Millisecond {
+		hundredsUs := (stMin + 100*time.Microsecond - 1) / (100 * time.Microsecond)
+		if hundredsUs < 10 {
+			return 0xF0 + byte(hundredsUs)
+		}
+	}
+
+	ms := (stMin + time.Millisecond - 1) / time.Millisecond
+	if ms > 0x7F {
+		return 0x7F
+	}
+
+	return byte(ms)
+}
+
+// decodeSTMin returns the separation time encoded by the given byte.
+// The reserved values are interpreted as the biggest separation time (127 ms).
+func decodeSTMin(b byte) time.Duration {
+	switch {
+	case b <= 0x7F:
+		return time.Duration(b) * time.Millisecond
+	case b >= 0xF1 && b <= 0xF9:
+		return time.Duration(b-0xF0) * 100 * time.Microsecond
+	default:
+		return 0x7F * time.Millisecond
+	}
+}
+
+// EncodeFlowControl returns the flow control frame that carries the given [FlowControl].
+// The frame is padded according to the given [Options],
+// but it is never bigger than a CAN 2.0 frame.
+func EncodeFlowControl(fc *FlowControl, opts *Options) []byte {
+	frame := []byte{byte(FrameTypeFlowControl)<<4 | byte(fc.Status&0x0F), fc.BlockSize, encodeSTMin(fc.STMin)}
+
+	fcOpts := *opts
+	fcOpts.FrameSize = ClassicFrameSize
+
+	return fcOpts.pad(frame)
+}
+
+// DecodeFlowControl returns the [FlowControl] carried by the given frame.
+//
+// It returns an [ErrInvalidFrame] if the frame is not a valid flow control frame.
+func DecodeFlowControl(frame []byte) (*FlowControl, error) {
+	if len(frame) < 3 || FrameType(frame[0]>>4) != FrameTypeFlowControl {
+		return nil, ErrInvalidFrame
+	}
+
+	status := FlowStatus(frame[0] & 0x0F)
+	if status > FlowStatusOverflow {
+		return nil, ErrInvalidFrame
+	}
+
+	return &FlowControl{
+		Status:    status,
+		BlockSize: frame[1],
+		STMin:     decodeSTMin(frame[2]),
+	}, nil
+}
+
+// Options are the options used to segment and reassemble the payloads.
+type Options struct {
+	// FrameSize is the size of the frames used to send the payload.
+	// It is 8 for CAN 2.0, or one of 12, 16, 20, 24, 32, 48 and 64 for CAN FD.
+	FrameSize int
+	// Padding enables the padding of all the frames to the frame size.
+	// When it is disabled, the frames bigger than 8 bytes are anyway padded
+	// to the next valid CAN FD size.
+	Padding bool
+	// PaddingByte is the byte used to pad the frames.
+	PaddingByte byte
+
+	// BlockSize is the block size sent in the flow control frames by the [Reassembler].
+	BlockSize uint8
+	// STMin is the separation time sent in the flow control frames by the [Reassembler].
+	STMin time.Duration
+	// MaxSizeByte is the maximum size of a payload accepted by the [Reassembler].
+	// If it is 0, the [MaxSizeByte] constant is used, so the payloads announced
+	// with the escape sequence are accepted only when it is set explicitly.
+	MaxSizeByte int
+}
+
+// DefaultOptions returns the default [Options]: CAN 2.0 frames
+// padded with the [DefaultPaddingByte], block size 0 and no separation time.
+func DefaultOptions() *Options {
+	return &Options{
+		FrameSize:   ClassicFrameSize,
+		Padding:     true,
+		PaddingByte: DefaultPaddingByte,
+	}
+}
+
+// FDOptions returns the default [Options] for CAN FD frames of 64 bytes.
+func FDOptions() *Options {
+	opts := DefaultOptions()
+	opts.FrameSize = MaxFDFrameSize
+	return opts
+}
+
+func (o *Options) verify() error {
+	if o.FrameSize == ClassicFrameSize || slices.Contains(fdFrameSizes, o.FrameSize) {
+		return nil
+	}
+	return ErrInvalidFrameSize
+}
+
+func (o *Options) maxSizeByte() uint64 {
+	if o.MaxSizeByte <= 0 {
+		return MaxSizeByte
+	}
+	return uint64(o.MaxSizeByte)
+}
+
+// pad pads the frame to the frame size if the padding is enabled,
+// otherwise to the next valid CAN FD size if the frame is bigger than 8 bytes.
+func (o *Options) pad(frame []byte) []byte {
+	size := len(frame)
+
+	switch {
+	case o.Padding:
+		size = max(o.FrameSize, ClassicFrameSize)
+	case size > ClassicFrameSize:
+		for _, fdSize := range fdFrameSizes {
+			if fdSize >= size {
+				size = fdSize
+				break
+			}
+		}
+	}
+
+	for len(frame) < size {
+		frame = append(frame, o.PaddingByte)
+	}
+
+	return frame
+}
+
+// FrameCount returns the number of frames needed to send
+// a payload of the given size with the given frame size,
+// flow control frames excluded.
+func FrameCount(sizeByte, frameSize int) int {
+	if sizeByte <= 7 || (frameSize > ClassicFrameSize && sizeByte <= frameSize-2) {
+		return 1
+	}
+
+	ffDataSize := frameSize - 2
+	if sizeByte > MaxSizeByte {
+		ffDataSize = frameSize - 6
+	}
+
+	cfDataSize := frameSize - 1
+	return 1 + (sizeByte-ffDataSize+cfDataSize-1)/cfDataSize
+}
@@ -0,0 +1,181 @@
+package isotp
+
+import "encoding/binary"
+
+// Reassembler reassembles the payloads received as ISO-TP frames.
+// It handles a single transfer at a time, so a reassembler
+// must be used for each sender (CAN-ID).
+//
+// A Reassembler is not safe for concurrent use.
+type Reassembler struct {
+	opts *Options
+
+	receiving  bool
+	sizeByte   uint64
+	payload    []byte
+	nextSeq    byte
+	blockCount int
+}
+
+// NewReassembler creates a new [Reassembler] with the given [Options].
+// The frame size of the options is not used, because
+// the size of the frames is given by the first frame.
+func NewReassembler(opts *Options) *Reassembler {
+	return &Reassembler{
+		opts: opts,
+	}
+}
+
+// Reset drops the transfer in progress.
+func (r *Reassembler) Reset() {
+	r.receiving = false
+	r.sizeByte = 0
+	r.payload = nil
+	r.nextSeq = 0
+	r.blockCount = 0
+}
+
+// IsReceiving returns whether a transfer is in progress.
+func (r *Reassembler) IsReceiving() bool {
+	return r.receiving
+}
+
+func (r *Reassembler) flowControl(status FlowStatus) []byte {
+	return EncodeFlowControl(&FlowControl{
+		Status:    status,
+		BlockSize: r.opts.BlockSize,
+		STMin:     r.opts.STMin,
+	}, r.opts)
+}
+
+// Feed processes a received frame.
+// When the frame completes a transfer, the payload is returned.
+// When the sender must be answered with a flow control frame
+// (after the first frame and at the end of each block),
+// the flow control frame is returned.
+// A single or first frame received during a transfer
+// drops the transfer in progress and starts a new one.
+//
+// It returns:
+//   - [ErrInvalidFrame] if the frame is malformed.
+//   - [ErrUnexpectedFrame] if a consecutive frame is received without a transfer in progress,
+//     or if a flow control frame is received.
+//   - [ErrWrongSequence] if the sequence number of a consecutive frame is wrong.
+//     In this case the transfer is dropped.
+//   - [ErrTooBig] if the announced size exceeds the maximum size of the options.
+//     In this case an overflow flow control frame is returned.
+func (r *Reassembler) Feed(frame []byte) ([]byte, []byte, error) {
+	ft, err := GetFrameType(frame)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch ft {
+	case FrameTypeSingle:
+		r.Reset()
+		payload, err := r.handleSingleFrame(frame)
+		return payload, nil, err
+
+	case FrameTypeFirst:
+		r.Reset()
+		return r.handleFirstFrame(frame)
+
+	case FrameTypeConsecutive:
+		return r.handleConsecutiveFrame(frame)
+	}
+
+	return nil, nil, ErrUnexpectedFrame
+}
+
+func (r *Reassembler) handleSingleFrame(frame []byte) ([]byte, error) {
+	size := int(frame[0] & 0x0F)
+	offset := 1
+
+	// escape sequence used by CAN FD frames
+	if size == 0 {
+		if len(frame) <= ClassicFrameSize {
+			return nil, ErrInvalidFrame
+		}
+
+		size = int(frame[1])
+		offset = 2
+	}
+
+	if size == 0 || offset+size > len(frame) {
+		return nil, ErrInvalidFrame
+	}
+
+	payload := make([]byte, size)
+	copy(payload, frame[offset:])
+
+	return payload, nil
+}
+
+func (r *Reassembler) handleFirstFrame(frame []byte) ([]byte, []byte, error) {
+	if len(frame) < ClassicFrameSize {
+		return nil, nil, ErrInvalidFrame
+	}
+
+	size := uint64(frame[0]&0x0F)<<8 | uint64(frame[1])
+	offset := 2
+
+	// escape sequence used by payloads bigger than 4095 bytes
+	if size == 0 {
+		size = uint64(binary.BigEndian.Uint32(frame[2:6]))
+		offset = 6
+	}
+
+	if size <= uint64(len(frame)-offset) {
+		return nil, nil, ErrInvalidFrame
+	}
+
+	if size > r.opts.maxSizeByte() {
+		return nil, r.flowControl(FlowStatusOverflow), ErrTooBig
+	}
+
+	r.receiving = true
+	r.sizeByte = size
+	r.payload = make([]byte, 0, size)
+	r.payload = append(r.payload, frame[offset:]...)
+	r.nextSeq = 1
+
+	return nil, r.flowControl(FlowStatusContinueToSend), nil
+}
+
+func (r *Reassembler) handleConsecutiveFrame(frame []byte) ([]byte, []byte, error) {
+	if !r.receiving {
+		return nil, nil, ErrUnexpectedFrame
+	}
+
+	seq := frame[0] & 0x0F
+	if seq != r.nextSeq {
+		r.Reset()
+		return nil, nil, ErrWrongSequence
+	}
+
+	remaining := r.sizeByte - uint64(len(r.payload))
+	data := frame[1:]
+	if uint64(len(data)) > remaining {
+		data = data[:remaining]
+	}
+	r.payload = append(r.payload, data...)
+
+	r.nextSeq = (r.nextSeq + 1) & 0x0F
+
+	if uint64(len(r.payload)) == r.sizeByte {
+		payload := r.payload
+		r.Reset()
+		return payload, nil, nil
+	}
+
+	if r.opts.BlockSize > 0 {
+		r.blockCount++
+
+		if r.blockCount == int(r.opts.BlockSize) {
+			r.blockCount = 0
+			return nil, r.flowControl(FlowStatusContinueToSend), nil
+		}
+	}
+
+	return nil, nil, nil
+}
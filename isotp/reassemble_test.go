@@ -0,0 +1,105 @@
+package isotp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Reassembler_Feed(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := DefaultOptions()
+	opts.BlockSize = 4
+	r := NewReassembler(opts)
+
+	// should reassemble a single frame
+	payload, fc, err := r.Feed([]byte{0x03, 1, 2, 3, 0xCC, 0xCC, 0xCC, 0xCC})
+	assert.NoError(err)
+	assert.Nil(fc)
+	assert.Equal([]byte{1, 2, 3}, payload)
+
+	// should reassemble a segmented payload and send the flow control frames
+	expected := getPayload(100)
+	frames, err := Segment(expected, opts)
+	assert.NoError(err)
+
+	fcCount := 0
+	for idx, frame := range frames {
+		payload, fc, err = r.Feed(frame)
+		assert.NoError(err)
+
+		if fc != nil {
+			fcCount++
+			fcRes, err := DecodeFlowControl(fc)
+			assert.NoError(err)
+			assert.Equal(FlowStatusContinueToSend, fcRes.Status)
+			assert.Equal(uint8(4), fcRes.BlockSize)
+		}
+
+		if idx < len(frames)-1 {
+			assert.Nil(payload)
+			assert.True(r.IsReceiving())
+		}
+	}
+	assert.Equal(expected, payload)
+	assert.False(r.IsReceiving())
+	// first frame + 3 full blocks of the 14 consecutive frames
+	assert.Equal(4, fcCount)
+
+	// should reassemble CAN FD frames with the escape sequences
+	escOpts := DefaultOptions()
+	escOpts.MaxSizeByte = 5000
+	for _, size := range []int{5, 30, 1000, 5000} {
+		expected = getPayload(size)
+		frames, err = Segment(expected, FDOptions())
+		assert.NoError(err)
+
+		r = NewReassembler(escOpts)
+		for _, frame := range frames {
+			payload, _, err = r.Feed(frame)
+			assert.NoError(err)
+		}
+		assert.Equal(expected, payload)
+	}
+
+	// should return an error because the sequence is wrong
+	frames, err = Segment(getPayload(30), opts)
+	assert.NoError(err)
+	_, _, err = r.Feed(frames[0])
+	assert.NoError(err)
+	_, _, err = r.Feed(frames[2])
+	assert.ErrorIs(err, ErrWrongSequence)
+	assert.False(r.IsReceiving())
+
+	// should return an error because there is not a transfer in progress
+	_, _, err = r.Feed(frames[1])
+	assert.ErrorIs(err, ErrUnexpectedFrame)
+
+	// should return an overflow flow control frame
+	// because the escape sequence exceeds the default maximum size
+	frames, err = Segment(getPayload(5000), opts)
+	assert.NoError(err)
+	r = NewReassembler(opts)
+	_, fc, err = r.Feed(frames[0])
+	assert.ErrorIs(err, ErrTooBig)
+	fcRes, err := DecodeFlowControl(fc)
+	assert.NoError(err)
+	assert.Equal(FlowStatusOverflow, fcRes.Status)
+
+	frames, err = Segment(getPayload(30), opts)
+	assert.NoError(err)
+	opts.MaxSizeByte = 20
+	r = NewReassembler(opts)
+	_, fc, err = r.Feed(frames[0])
+	assert.ErrorIs(err, ErrTooBig)
+	fcRes, err = DecodeFlowControl(fc)
+	assert.NoError(err)
+	assert.Equal(FlowStatusOverflow, fcRes.Status)
+
+	// should return an error because the single frame is malformed
+	_, _, err = r.Feed([]byte{0x05, 1, 2})
+	assert.ErrorIs(err, ErrInvalidFrame)
+	_, _, err = r.Feed([]byte{})
+	assert.ErrorIs(err, ErrInvalidFrame)
+}
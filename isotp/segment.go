@@ -0,0 +1,147 @@
+package isotp
+
+import "encoding/binary"
+
+// Segment splits the given payload into ISO-TP frames.
+// If the payload fits a single frame, only the single frame is returned.
+// Otherwise, the first frame is followed by all the consecutive frames,
+// and the sender must wait for the flow control frames of the receiver
+// between the blocks (see [Sender]).
+//
+// It returns:
+//   - [ErrInvalidFrameSize] if the frame size of the options is invalid.
+//   - [ErrIsEmpty] if the payload is empty.
+//   - [ErrTooBig] if the payload is bigger than [MaxEscapeSizeByte].
+func Segment(payload []byte, opts *Options) ([][]byte, error) {
+	if err := opts.verify(); err != nil {
+		return nil, err
+	}
+
+	size := len(payload)
+	if size == 0 {
+		return nil, ErrIsEmpty
+	}
+
+	if uint64(size) > MaxEscapeSizeByte {
+		return nil, ErrTooBig
+	}
+
+	// Single frame, only for the frames of up to 8 bytes once padded
+	if size <= 7 && (!opts.Padding || opts.FrameSize == ClassicFrameSize) {
+		frame := make([]byte, 0, opts.FrameSize)
+		frame = append(frame, byte(FrameTypeSingle)<<4|byte(size))
+		frame = append(frame, payload...)
+		return [][]byte{opts.pad(frame)}, nil
+	}
+
+	// Single frame with the escape sequence (CAN FD only),
+	// which is mandatory for the frames longer than 8 bytes
+	if opts.FrameSize > ClassicFrameSize && size <= opts.FrameSize-2 {
+		frame := make([]byte, 0, opts.FrameSize)
+		frame = append(frame, byte(FrameTypeSingle)<<4, byte(size))
+		frame = append(frame, payload...)
+		return [][]byte{opts.pad(frame)}, nil
+	}
+
+	frames := make([][]byte, 0, FrameCount(size, opts.FrameSize))
+
+	// First frame, it always fills the whole frame
+	ff := make([]byte, 0, opts.FrameSize)
+	if size <= MaxSizeByte {
+		ff = append(ff, byte(FrameTypeFirst)<<4|byte(size>>8), byte(size))
+	} else {
+		ff = append(ff, byte(FrameTypeFirst)<<4, 0)
+		ff = binary.BigEndian.AppendUint32(ff, uint32(size))
+	}
+
+	offset := opts.FrameSize - len(ff)
+	ff = append(ff, payload[:offset]...)
+	frames = append(frames, ff)
+
+	// Consecutive frames, the sequence number starts from 1 and wraps after 15
+	seq := byte(1)
+	for offset < size {
+		end := min(offset+opts.FrameSize-1, size)
+
+		cf := make([]byte, 0, opts.FrameSize)
+		cf = append(cf, byte(FrameTypeConsecutive)<<4|seq)
+		cf = append(cf, payload[offset:end]...)
+		frames = append(frames, opts.pad(cf))
+
+		offset = end
+		seq = (seq + 1) & 0x0F
+	}
+
+	return frames, nil
+}
+
+// Sender sends a segmented payload by following
+// the flow control frames of the receiver.
+type Sender struct {
+	frames [][]byte
+	next   int
+}
+
+// NewSender creates a new [Sender] for the given payload.
+//
+// It returns the same errors of [Segment].
+func NewSender(payload []byte, opts *Options) (*Sender, error) {
+	frames, err := Segment(payload, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sender{
+		frames: frames,
+		next:   1,
+	}, nil
+}
+
+// FirstFrame returns the first frame to send,
+// which is a single frame if the payload fits it.
+func (s *Sender) FirstFrame() []byte {
+	return s.frames[0]
+}
+
+// Done returns whether all the frames have been returned.
+func (s *Sender) Done() bool {
+	return s.next >= len(s.frames)
+}
+
+// HandleFlowControl processes a flow control frame sent by the receiver,
+// and it returns the next block of consecutive frames to send
+// together with the decoded [FlowControl], which holds the separation time.
+// No frames are returned if the receiver asks to wait.
+//
+// It returns:
+//   - [ErrInvalidFrame] if the frame is not a flow control frame.
+//   - [ErrUnexpectedFrame] if all the frames have already been sent.
+//   - [ErrTooBig] if the receiver reports an overflow.
+func (s *Sender) HandleFlowControl(frame []byte) ([][]byte, *FlowControl, error) {
+	fc, err := DecodeFlowControl(frame)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.Done() {
+		return nil, fc, ErrUnexpectedFrame
+	}
+
+	switch fc.Status {
+	case FlowStatusWait:
+		return nil, fc, nil
+	case FlowStatusOverflow:
+		s.next = len(s.frames)
+		return nil, fc, ErrTooBig
+	}
+
+	end := len(s.frames)
+	if fc.BlockSize > 0 {
+		end = min(s.next+int(fc.BlockSize), end)
+	}
+
+	block := s.frames[s.next:end]
+	s.next = end
+
+	return block, fc, nil
+}
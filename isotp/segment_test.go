@@ -0,0 +1,146 @@
+package isotp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getPayload(size int) []byte {
+	payload := make([]byte, size)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	return payload
+}
+
+func Test_Segment(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := DefaultOptions()
+
+	// should return a padded single frame
+	frames, err := Segment([]byte{1, 2, 3}, opts)
+	assert.NoError(err)
+	assert.Equal([][]byte{{0x03, 1, 2, 3, 0xCC, 0xCC, 0xCC, 0xCC}}, frames)
+
+	// should return a single frame without padding
+	opts.Padding = false
+	frames, err = Segment([]byte{1, 2, 3}, opts)
+	assert.NoError(err)
+	assert.Equal([][]byte{{0x03, 1, 2, 3}}, frames)
+
+	// should return a first frame and the consecutive frames
+	frames, err = Segment(getPayload(20), opts)
+	assert.NoError(err)
+	assert.Len(frames, 3)
+	assert.Equal(3, FrameCount(20, ClassicFrameSize))
+	assert.Equal([]byte{0x10, 20, 0, 1, 2, 3, 4, 5}, frames[0])
+	assert.Equal([]byte{0x21, 6, 7, 8, 9, 10, 11, 12}, frames[1])
+	assert.Equal([]byte{0x22, 13, 14, 15, 16, 17, 18, 19}, frames[2])
+
+	// should wrap the sequence number
+	frames, err = Segment(getPayload(200), opts)
+	assert.NoError(err)
+	assert.Equal(FrameCount(200, ClassicFrameSize), len(frames))
+	assert.Equal(byte(0x2F), frames[15][0])
+	assert.Equal(byte(0x20), frames[16][0])
+	assert.Equal(byte(0x21), frames[17][0])
+
+	// should use the escape sequence for big payloads
+	frames, err = Segment(getPayload(5000), opts)
+	assert.NoError(err)
+	assert.Equal([]byte{0x10, 0x00, 0x00, 0x00, 0x13, 0x88, 0, 1}, frames[0])
+	assert.Equal(FrameCount(5000, ClassicFrameSize), len(frames))
+
+	// should use the escape sequence for a CAN FD single frame
+	fdOpts := FDOptions()
+	fdOpts.Padding = false
+	frames, err = Segment(getPayload(10), fdOpts)
+	assert.NoError(err)
+	assert.Len(frames, 1)
+	assert.Len(frames[0], 12)
+	assert.Equal([]byte{0x00, 10, 0, 1}, frames[0][:4])
+
+	// should use the escape sequence for a short payload padded to a CAN FD frame
+	frames, err = Segment(getPayload(5), FDOptions())
+	assert.NoError(err)
+	assert.Len(frames, 1)
+	assert.Len(frames[0], 64)
+	assert.Equal([]byte{0x00, 5, 0, 1, 2, 3, 4, 0xCC}, frames[0][:8])
+
+	// should use the classic single frame for a short payload without padding
+	frames, err = Segment(getPayload(5), fdOpts)
+	assert.NoError(err)
+	assert.Equal([][]byte{{0x05, 0, 1, 2, 3, 4}}, frames)
+
+	// should pad the last CAN FD consecutive frame to a valid size
+	frames, err = Segment(getPayload(70), fdOpts)
+	assert.NoError(err)
+	assert.Len(frames, 2)
+	assert.Len(frames[0], 64)
+	assert.Len(frames[1], 12)
+	assert.Equal(FrameCount(70, MaxFDFrameSize), len(frames))
+
+	// should return an error because the frame size is invalid
+	_, err = Segment(getPayload(10), &Options{FrameSize: 10})
+	assert.ErrorIs(err, ErrInvalidFrameSize)
+
+	// should return an error because the payload is empty
+	_, err = Segment([]byte{}, opts)
+	assert.ErrorIs(err, ErrIsEmpty)
+}
+
+func Test_Sender_HandleFlowControl(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := DefaultOptions()
+	s, err := NewSender(getPayload(50), opts)
+	assert.NoError(err)
+	assert.Equal(byte(0x10), s.FirstFrame()[0])
+
+	// should return a block of 2 frames
+	fcFrame := EncodeFlowControl(&FlowControl{Status: FlowStatusContinueToSend, BlockSize: 2, STMin: 5 * time.Millisecond}, opts)
+	assert.Equal([]byte{0x30, 2, 5, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC}, fcFrame)
+	block, fc, err := s.HandleFlowControl(fcFrame)
+	assert.NoError(err)
+	assert.Len(block, 2)
+	assert.Equal(5*time.Millisecond, fc.STMin)
+
+	// should not return any frame because the receiver asks to wait
+	block, _, err = s.HandleFlowControl(EncodeFlowControl(&FlowControl{Status: FlowStatusWait}, opts))
+	assert.NoError(err)
+	assert.Len(block, 0)
+
+	// should return all the remaining frames
+	block, _, err = s.HandleFlowControl(EncodeFlowControl(&FlowControl{Status: FlowStatusContinueToSend}, opts))
+	assert.NoError(err)
+	assert.Len(block, 5)
+	assert.True(s.Done())
+
+	_, _, err = s.HandleFlowControl(fcFrame)
+	assert.ErrorIs(err, ErrUnexpectedFrame)
+
+	// should return an error because the receiver overflows
+	s, err = NewSender(getPayload(50), opts)
+	assert.NoError(err)
+	_, _, err = s.HandleFlowControl(EncodeFlowControl(&FlowControl{Status: FlowStatusOverflow}, opts))
+	assert.ErrorIs(err, ErrTooBig)
+	assert.True(s.Done())
+}
+
+func Test_STMin(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(byte(0x00), encodeSTMin(0))
+	assert.Equal(byte(0xF1), encodeSTMin(50*time.Microsecond))
+	assert.Equal(byte(0xF5), encodeSTMin(500*time.Microsecond))
+	assert.Equal(byte(0x01), encodeSTMin(950*time.Microsecond))
+	assert.Equal(byte(0x0A), encodeSTMin(10*time.Millisecond))
+	assert.Equal(byte(0x7F), encodeSTMin(time.Second))
+
+	assert.Equal(500*time.Microsecond, decodeSTMin(0xF5))
+	assert.Equal(10*time.Millisecond, decodeSTMin(0x0A))
+	assert.Equal(127*time.Millisecond, decodeSTMin(0xFA))
+}
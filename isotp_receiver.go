@@ -0,0 +1,88 @@
+package acmelib
+
+import (
+	"github.com/squadracorsepolito/acmelib/isotp"
+)
+
+// ISOTPReceiver decodes the frames of the messages indexed by a [Decoder],
+// by reassembling the messages transported with the ISO-TP protocol
+// before decoding them.
+// It is created by [Decoder.NewISOTPReceiver] and it keeps
+// a reassembler for each CAN-ID and format.
+// It acts as a passive listener, so it does not send the flow control frames.
+//
+// An ISOTPReceiver is not safe for concurrent use.
+type ISOTPReceiver struct {
+	decoder *Decoder
+	opts    *isotp.Options
+
+	reassemblers map[decoderKey]*isotp.Reassembler
+}
+
+// NewISOTPReceiver creates a new [ISOTPReceiver] that uses the decoder.
+// If the given options are nil, the default ones are used.
+// The maximum size of the options is capped at the size of each message.
+func (d *Decoder) NewISOTPReceiver(opts *isotp.Options) *ISOTPReceiver {
+	if opts == nil {
+		opts = isotp.DefaultOptions()
+	}
+
+	return &ISOTPReceiver{
+		decoder: d,
+		opts:    opts,
+
+		reassemblers: make(map[decoderKey]*isotp.Reassembler),
+	}
+}
+
+// Feed processes a frame with the given CAN-ID and [CANIDFormat].
+// The frames of the messages that are not transported with ISO-TP
+// are decoded as [Decoder.DecodeFrame] does.
+// Otherwise, the frame is passed to the reassembler of the CAN-ID,
+// and the message is decoded when the payload is complete.
+// While the reassembly is in progress, the message is returned
+// without signal decodings.
+//
+// It returns:
+//   - [CANIDError] if the CAN-ID does not match any message.
+//   - [SizeError] if the length of the payload is different from the size of the message.
+//   - an isotp error if the frame cannot be reassembled.
+func (r *ISOTPReceiver) Feed(canID CANID, format CANIDFormat, frame []byte) (*Message, []*SignalDecoding, error) {
+	msg, err := r.decoder.GetMessage(canID, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if msg.transport != MessageTransportISOTP {
+		return r.decoder.decode(msg, frame)
+	}
+
+	key := decoderKey{canID: canID, format: format}
+	reassembler, ok := r.reassemblers[key]
+	if !ok {
+		// the announced size cannot exceed the size of the message
+		opts := *r.opts
+		if opts.MaxSizeByte <= 0 || opts.MaxSizeByte > msg.sizeByte {
+			opts.MaxSizeByte = msg.sizeByte
+		}
+
+		reassembler = isotp.NewReassembler(&opts)
+		r.reassemblers[key] = reassembler
+	}
+
+	payload, _, err := reassembler.Feed(frame)
+	if err != nil {
+		return msg, nil, msg.errorf(err)
+	}
+
+	if payload == nil {
+		return msg, nil, nil
+	}
+
+	return r.decoder.decode(msg, payload)
+}
+
+// Reset drops all the reassemblies in progress.
+func (r *ISOTPReceiver) Reset() {
+	clear(r.reassemblers)
+}
@@ -0,0 +1,87 @@
+package acmelib
+
+import (
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib/isotp"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ISOTPReceiver_Feed(t *testing.T) {
+	assert := assert.New(t)
+
+	tdNet := initNetwork(assert)
+
+	sigType, err := NewIntegerSignalType("uint16_t", 16, false)
+	assert.NoError(err)
+
+	// should return an error because the message does not fit a frame
+	msg := NewMessage("diag_message", 100, 100)
+	assert.ErrorIs(tdNet.node0.GetInterface(0).AddSentMessage(msg), ErrTooBig)
+
+	assert.NoError(msg.SetTransport(MessageTransportISOTP))
+	assert.NoError(tdNet.node0.GetInterface(0).AddSentMessage(msg))
+	assert.ErrorIs(msg.UpdateSizeByte(isotp.MaxSizeByte+1), ErrTooBig)
+
+	firstSig, err := NewStandardSignal("first_signal", sigType)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(firstSig, 0))
+	lastSig, err := NewStandardSignal("last_signal", sigType)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(lastSig, 784))
+
+	frames, err := msg.EncodeISOTPFrames(map[string]any{
+		"first_signal": 1234,
+		"last_signal":  4321,
+	}, nil)
+	assert.NoError(err)
+	assert.Len(frames, isotp.FrameCount(100, isotp.ClassicFrameSize))
+
+	dec, err := tdNet.bus.NewDecoder()
+	assert.NoError(err)
+	rec := dec.NewISOTPReceiver(nil)
+
+	// should reassemble the message before decoding it
+	var decodings []*SignalDecoding
+	for idx, frame := range frames {
+		var resMsg *Message
+		resMsg, decodings, err = rec.Feed(msg.GetCANID(), CANIDFormatStandard, frame)
+		assert.NoError(err)
+		assert.Equal(msg.EntityID(), resMsg.EntityID())
+
+		if idx < len(frames)-1 {
+			assert.Nil(decodings)
+		}
+	}
+	assert.Len(decodings, 2)
+	assert.Equal(uint64(1234), decodings[0].RawValue)
+	assert.Equal(uint64(4321), decodings[1].RawValue)
+
+	// should decode a message that is not transported with ISO-TP
+	basicMsg := tdNet.messages.basic.message
+	_, decodings, err = rec.Feed(basicMsg.GetCANID(), CANIDFormatStandard, make([]byte, 8))
+	assert.NoError(err)
+	assert.Len(decodings, 4)
+
+	// should return an error because the sequence is wrong
+	_, _, err = rec.Feed(msg.GetCANID(), CANIDFormatStandard, frames[0])
+	assert.NoError(err)
+	_, _, err = rec.Feed(msg.GetCANID(), CANIDFormatStandard, frames[2])
+	assert.ErrorIs(err, isotp.ErrWrongSequence)
+
+	// should return an error because the announced size exceeds the size of the message
+	_, _, err = rec.Feed(msg.GetCANID(), CANIDFormatStandard, []byte{0x10, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0})
+	assert.ErrorIs(err, isotp.ErrTooBig)
+	_, _, err = rec.Feed(msg.GetCANID(), CANIDFormatStandard, []byte{0x10, 101, 0, 0, 0, 0, 0, 0})
+	assert.ErrorIs(err, isotp.ErrTooBig)
+
+	// should use CAN FD frames
+	tdNet.bus.SetType(BusTypeCANFD)
+	frames, err = msg.EncodeISOTPFrames(map[string]any{
+		"first_signal": 1234,
+		"last_signal":  4321,
+	}, nil)
+	assert.NoError(err)
+	assert.Len(frames, 2)
+	assert.Len(frames[0], isotp.MaxFDFrameSize)
+}
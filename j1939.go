@@ -0,0 +1,118 @@
+package acmelib
+
+const (
+	// J1939GlobalAddress is the destination address used to send
+	// a message to all the nodes of a J1939 network.
+	J1939GlobalAddress uint8 = 0xFF
+	// J1939NullAddress is the source address used by a node
+	// that has not claimed an address yet.
+	J1939NullAddress uint8 = 0xFE
+
+	// J1939MaxTPSizeByte is the maximum size in bytes of a payload
+	// sent with the J1939 transport protocol (255 packets of 7 bytes).
+	J1939MaxTPSizeByte = 1785
+
+	// j1939PDU2MinPF is the first PDU format of the PDU2 (broadcast) messages.
+	j1939PDU2MinPF = 240
+)
+
+// J1939PGN is the parameter group number of a J1939 message.
+// It is made of 18 bits: the extended data page (1 bit), the data page (1 bit),
+// the PDU format (8 bits) and the PDU specific (8 bits).
+// For PDU1 messages the PDU specific is the destination address,
+// so it is always 0 within the PGN.
+type J1939PGN uint32
+
+// NewJ1939PGN returns the [J1939PGN] composed by the given extended data page,
+// data page, PDU format and PDU specific.
+func NewJ1939PGN(edp, dp bool, pf, ps uint8) J1939PGN {
+	pgn := J1939PGN(pf)<<8 | J1939PGN(ps)
+
+	if edp {
+		pgn |= 1 << 17
+	}
+
+	if dp {
+		pgn |= 1 << 16
+	}
+
+	return pgn
+}
+
+// EDP returns the extended data page bit of the PGN.
+func (pgn J1939PGN) EDP() bool {
+	return pgn&(1<<17) != 0
+}
+
+// DP returns the data page bit of the PGN.
+func (pgn J1939PGN) DP() bool {
+	return pgn&(1<<16) != 0
+}
+
+// PF returns the PDU format of the PGN.
+func (pgn J1939PGN) PF() uint8 {
+	return uint8(pgn >> 8)
+}
+
+// PS returns the PDU specific of the PGN.
+func (pgn J1939PGN) PS() uint8 {
+	return uint8(pgn)
+}
+
+// IsPDU1 returns whether the PGN refers to a PDU1 (destination specific) message,
+// which happens when the PDU format is lower than 240.
+func (pgn J1939PGN) IsPDU1() bool {
+	return pgn.PF() < j1939PDU2MinPF
+}
+
+// ToMessageID returns the [MessageID] to be used with a [CANIDBuilder]
+// created by [NewJ1939CANIDBuilder].
+// For PDU1 messages, the PDU specific is replaced by the given destination address,
+// while for PDU2 messages the destination address is ignored.
+func (pgn J1939PGN) ToMessageID(destinationAddress uint8) MessageID {
+	if pgn.IsPDU1() {
+		return MessageID(pgn&^0xFF) | MessageID(destinationAddress)
+	}
+	return MessageID(pgn)
+}
+
+// J1939ID is the decomposition of a J1939 (29 bits) CAN-ID.
+type J1939ID struct {
+	// Priority is the priority of the message (0 to 7).
+	Priority uint8
+	// PGN is the parameter group number of the message.
+	PGN J1939PGN
+	// DestinationAddress is the address of the receiver for PDU1 messages,
+	// or [J1939GlobalAddress] for PDU2 messages.
+	DestinationAddress uint8
+	// SourceAddress is the address of the sender.
+	SourceAddress uint8
+}
+
+// ParseJ1939CANID decomposes the given CAN-ID into a [J1939ID].
+// For PDU1 messages, the PDU specific is returned as destination address
+// and it is cleared from the PGN.
+func ParseJ1939CANID(canID CANID) *J1939ID {
+	id := &J1939ID{
+		Priority:           uint8(canID>>26) & 0x07,
+		PGN:                J1939PGN(canID>>8) & 0x3FFFF,
+		DestinationAddress: J1939GlobalAddress,
+		SourceAddress:      uint8(canID),
+	}
+
+	if id.PGN.IsPDU1() {
+		id.DestinationAddress = id.PGN.PS()
+		id.PGN &^= 0xFF
+	}
+
+	return id
+}
+
+// CANID returns the CAN-ID composed by the fields of the [J1939ID].
+// The destination address is used only for PDU1 messages.
+func (id *J1939ID) CANID() CANID {
+	canID := CANID(id.Priority&0x07) << 26
+	canID |= CANID(id.PGN.ToMessageID(id.DestinationAddress)&0x3FFFF) << 8
+	canID |= CANID(id.SourceAddress)
+	return canID
+}
@@ -0,0 +1,81 @@
+package acmelib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_J1939PGN(t *testing.T) {
+	assert := assert.New(t)
+
+	// EEC1 (PDU2)
+	pgn := NewJ1939PGN(false, false, 0xF0, 0x04)
+	assert.Equal(J1939PGN(61444), pgn)
+	assert.False(pgn.EDP())
+	assert.False(pgn.DP())
+	assert.Equal(uint8(0xF0), pgn.PF())
+	assert.Equal(uint8(0x04), pgn.PS())
+	assert.False(pgn.IsPDU1())
+	assert.Equal(MessageID(61444), pgn.ToMessageID(0x10))
+
+	// TSC1 (PDU1)
+	pgn = NewJ1939PGN(false, false, 0x00, 0x00)
+	assert.True(pgn.IsPDU1())
+	assert.Equal(MessageID(0x10), pgn.ToMessageID(0x10))
+
+	pgn = NewJ1939PGN(true, true, 0xFE, 0xCA)
+	assert.Equal(J1939PGN(0x3FECA), pgn)
+	assert.True(pgn.EDP())
+	assert.True(pgn.DP())
+}
+
+func Test_ParseJ1939CANID(t *testing.T) {
+	assert := assert.New(t)
+
+	// EEC1 from the engine (PDU2)
+	id := ParseJ1939CANID(0x0CF00400)
+	assert.Equal(uint8(3), id.Priority)
+	assert.Equal(J1939PGN(61444), id.PGN)
+	assert.Equal(J1939GlobalAddress, id.DestinationAddress)
+	assert.Equal(uint8(0x00), id.SourceAddress)
+	assert.Equal(CANID(0x0CF00400), id.CANID())
+
+	// TSC1 from the transmission to the engine (PDU1)
+	id = ParseJ1939CANID(0x0C000003)
+	assert.Equal(uint8(3), id.Priority)
+	assert.Equal(J1939PGN(0), id.PGN)
+	assert.Equal(uint8(0x00), id.DestinationAddress)
+	assert.Equal(uint8(0x03), id.SourceAddress)
+
+	id.DestinationAddress = 0x0F
+	assert.Equal(CANID(0x0C000F03), id.CANID())
+}
+
+func Test_NewJ1939CANIDBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewJ1939CANIDBuilder("j1939_builder")
+	assert.NoError(b.VerifyFormat(CANIDFormatExtended))
+	assert.Error(b.VerifyFormat(CANIDFormatStandard))
+
+	eec1 := NewJ1939PGN(false, false, 0xF0, 0x04)
+	assert.Equal(CANID(0x0CF00400), b.Calculate(3, eec1.ToMessageID(0), 0x00))
+	assert.Equal(CANID(0x18F00417), b.Calculate(6, eec1.ToMessageID(0), 0x17))
+
+	tsc1 := NewJ1939PGN(false, false, 0x00, 0x00)
+	assert.Equal(CANID(0x0C000003), b.Calculate(3, tsc1.ToMessageID(0x00), 0x03))
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+	bus.SetCANIDBuilder(b)
+
+	nodeInt := NewNode("engine", 0x00, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := NewMessage("eec1", eec1.ToMessageID(0), 8)
+	msg.SetPriority(3)
+	assert.NoError(msg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+	assert.Equal(CANID(0x0CF00400), msg.GetCANID())
+}
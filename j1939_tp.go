@@ -0,0 +1,197 @@
+package acmelib
+
+import (
+	"encoding/binary"
+)
+
+const (
+	// j1939TPCMPGN is the PGN of the connection management frames.
+	j1939TPCMPGN J1939PGN = 0xEC00
+	// j1939TPDTPGN is the PGN of the data transfer frames.
+	j1939TPDTPGN J1939PGN = 0xEB00
+
+	j1939TPCMControlRTS   = 16
+	j1939TPCMControlCTS   = 17
+	j1939TPCMControlEOMA  = 19
+	j1939TPCMControlBAM   = 32
+	j1939TPCMControlAbort = 255
+
+	// j1939TPPacketSize is the number of payload bytes carried by a data transfer frame.
+	j1939TPPacketSize = 7
+)
+
+// J1939TPMessage is a multi-packet message reassembled by a [J1939TPReassembler].
+type J1939TPMessage struct {
+	// PGN is the parameter group number of the transported message.
+	PGN J1939PGN
+	// SourceAddress is the address of the sender.
+	SourceAddress uint8
+	// DestinationAddress is the address of the receiver,
+	// or [J1939GlobalAddress] if the message was broadcasted (BAM).
+	DestinationAddress uint8
+	// IsBroadcast reports whether the message was sent with
+	// the broadcast announce message (BAM) or with a connection (CMDT).
+	IsBroadcast bool
+	// Data is the reassembled payload.
+	Data []byte
+}
+
+type j1939TPSessionKey struct {
+	srcAddr  uint8
+	destAddr uint8
+}
+
+type j1939TPSession struct {
+	pgn         J1939PGN
+	isBroadcast bool
+	sizeByte    int
+	packets     int
+	nextSeq     int
+	data        []byte
+}
+
+// J1939TPReassembler reassembles the messages sent with the J1939 transport protocol.
+// It supports both the broadcast announce message (BAM) and
+// the connection mode data transfer (CMDT) sessions.
+// It acts as a passive listener, so it does not send any clear to send
+// or acknowledgement frame, and it does not check the timeouts of the sessions.
+//
+// A J1939TPReassembler is not safe for concurrent use.
+type J1939TPReassembler struct {
+	sessions map[j1939TPSessionKey]*j1939TPSession
+}
+
+// NewJ1939TPReassembler creates a new [J1939TPReassembler].
+func NewJ1939TPReassembler() *J1939TPReassembler {
+	return &J1939TPReassembler{
+		sessions: make(map[j1939TPSessionKey]*j1939TPSession),
+	}
+}
+
+// Reset drops all the open sessions.
+func (r *J1939TPReassembler) Reset() {
+	clear(r.sessions)
+}
+
+// SessionCount returns the number of open sessions.
+func (r *J1939TPReassembler) SessionCount() int {
+	return len(r.sessions)
+}
+
+// Feed processes a frame with the given CAN-ID and data.
+// When the frame completes a session, the reassembled [J1939TPMessage] is returned.
+// Frames that do not belong to the transport protocol are ignored,
+// as well as data transfer frames that do not belong to an open session.
+// A new announcement (RTS or BAM) replaces the open session between the same nodes.
+//
+// It returns a [J1939TPError] that wraps:
+//   - [SizeError] if the announced size is invalid.
+//   - [ErrInvalidValue] if the number of packets does not match the size,
+//     or if a data transfer frame is out of sequence. In this case the session is dropped.
+func (r *J1939TPReassembler) Feed(canID CANID, data []byte) (*J1939TPMessage, error) {
+	id := ParseJ1939CANID(canID)
+
+	switch id.PGN {
+	case j1939TPCMPGN:
+		return nil, r.handleConnectionManagement(id, data)
+	case j1939TPDTPGN:
+		return r.handleDataTransfer(id, data)
+	}
+
+	return nil, nil
+}
+
+func (r *J1939TPReassembler) handleConnectionManagement(id *J1939ID, data []byte) error {
+	if len(data) < 8 {
+		return nil
+	}
+
+	key := j1939TPSessionKey{srcAddr: id.SourceAddress, destAddr: id.DestinationAddress}
+
+	switch data[0] {
+	case j1939TPCMControlBAM, j1939TPCMControlRTS:
+		isBroadcast := data[0] == j1939TPCMControlBAM
+		if isBroadcast {
+			key.destAddr = J1939GlobalAddress
+		}
+
+		// drop the previous session between the same nodes
+		delete(r.sessions, key)
+
+		sizeByte := int(binary.LittleEndian.Uint16(data[1:3]))
+		packets := int(data[3])
+		pgn := J1939PGN(data[5]) | J1939PGN(data[6])<<8 | J1939PGN(data[7])<<16
+
+		if sizeByte <= 8 {
+			return newJ1939TPError(key.srcAddr, key.destAddr, newSizeError(sizeByte, ErrTooSmall))
+		}
+
+		if sizeByte > J1939MaxTPSizeByte {
+			return newJ1939TPError(key.srcAddr, key.destAddr, newSizeError(sizeByte, ErrTooBig))
+		}
+
+		if packets != (sizeByte+j1939TPPacketSize-1)/j1939TPPacketSize {
+			return newJ1939TPError(key.srcAddr, key.destAddr, ErrInvalidValue)
+		}
+
+		r.sessions[key] = &j1939TPSession{
+			pgn:         pgn,
+			isBroadcast: isBroadcast,
+			sizeByte:    sizeByte,
+			packets:     packets,
+			nextSeq:     1,
+			data:        make([]byte, packets*j1939TPPacketSize),
+		}
+
+	case j1939TPCMControlAbort:
+		// the abort can be sent by both the originator and the responder
+		delete(r.sessions, key)
+		delete(r.sessions, j1939TPSessionKey{srcAddr: key.destAddr, destAddr: key.srcAddr})
+
+	case j1939TPCMControlCTS, j1939TPCMControlEOMA:
+		// sent by the responder, the data transfer frames are enough
+		// to track the session
+	}
+
+	return nil
+}
+
+func (r *J1939TPReassembler) handleDataTransfer(id *J1939ID, data []byte) (*J1939TPMessage, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	key := j1939TPSessionKey{srcAddr: id.SourceAddress, destAddr: id.DestinationAddress}
+	session, ok := r.sessions[key]
+	if !ok {
+		return nil, nil
+	}
+
+	// the responder may ask to retransmit the packets already received,
+	// so only a gap in the sequence is an error
+	seq := int(data[0])
+	if seq == 0 || seq > session.nextSeq || seq > session.packets {
+		delete(r.sessions, key)
+		return nil, newJ1939TPError(key.srcAddr, key.destAddr, ErrInvalidValue)
+	}
+
+	copy(session.data[(seq-1)*j1939TPPacketSize:seq*j1939TPPacketSize], data[1:])
+
+	if seq == session.nextSeq {
+		session.nextSeq++
+	}
+
+	if session.nextSeq <= session.packets {
+		return nil, nil
+	}
+
+	delete(r.sessions, key)
+
+	return &J1939TPMessage{
+		PGN:                session.pgn,
+		SourceAddress:      key.srcAddr,
+		DestinationAddress: key.destAddr,
+		IsBroadcast:        session.isBroadcast,
+		Data:               session.data[:session.sizeByte],
+	}, nil
+}
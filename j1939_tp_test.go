@@ -0,0 +1,118 @@
+package acmelib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getJ1939TPFrames(srcAddr, destAddr uint8, pgn J1939PGN, payload []byte) ([]CANID, [][]byte) {
+	packets := (len(payload) + 6) / 7
+
+	ctrl := byte(j1939TPCMControlRTS)
+	maxPackets := byte(packets)
+	if destAddr == J1939GlobalAddress {
+		ctrl = j1939TPCMControlBAM
+		maxPackets = 0xFF
+	}
+
+	cmID := &J1939ID{Priority: 7, PGN: j1939TPCMPGN, DestinationAddress: destAddr, SourceAddress: srcAddr}
+	dtID := &J1939ID{Priority: 7, PGN: j1939TPDTPGN, DestinationAddress: destAddr, SourceAddress: srcAddr}
+
+	canIDs := []CANID{cmID.CANID()}
+	frames := [][]byte{{ctrl, byte(len(payload)), byte(len(payload) >> 8), byte(packets), maxPackets, byte(pgn), byte(pgn >> 8), byte(pgn >> 16)}}
+
+	for seq := 1; seq <= packets; seq++ {
+		frame := []byte{byte(seq), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+		copy(frame[1:], payload[(seq-1)*7:min(seq*7, len(payload))])
+
+		canIDs = append(canIDs, dtID.CANID())
+		frames = append(frames, frame)
+	}
+
+	return canIDs, frames
+}
+
+func Test_J1939TPReassembler_Feed(t *testing.T) {
+	assert := assert.New(t)
+
+	payload := make([]byte, 20)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	pgn := NewJ1939PGN(false, false, 0xFE, 0xCA)
+
+	r := NewJ1939TPReassembler()
+
+	// should ignore a frame that does not belong to the transport protocol
+	tpMsg, err := r.Feed(0x0CF00400, make([]byte, 8))
+	assert.NoError(err)
+	assert.Nil(tpMsg)
+
+	// should reassemble a BAM session
+	canIDs, frames := getJ1939TPFrames(0x00, J1939GlobalAddress, pgn, payload)
+	for idx := range canIDs {
+		tpMsg, err = r.Feed(canIDs[idx], frames[idx])
+		assert.NoError(err)
+
+		if idx < len(canIDs)-1 {
+			assert.Nil(tpMsg)
+			assert.Equal(1, r.SessionCount())
+		}
+	}
+	assert.NotNil(tpMsg)
+	assert.Equal(pgn, tpMsg.PGN)
+	assert.True(tpMsg.IsBroadcast)
+	assert.Equal(uint8(0x00), tpMsg.SourceAddress)
+	assert.Equal(J1939GlobalAddress, tpMsg.DestinationAddress)
+	assert.Equal(payload, tpMsg.Data)
+	assert.Equal(0, r.SessionCount())
+
+	// should reassemble a CMDT session with a retransmitted packet
+	canIDs, frames = getJ1939TPFrames(0x03, 0x00, pgn, payload)
+	canIDs = append(canIDs[:3], canIDs[2:]...)
+	frames = append(frames[:3], frames[2:]...)
+	for idx := range canIDs {
+		tpMsg, err = r.Feed(canIDs[idx], frames[idx])
+		assert.NoError(err)
+	}
+	assert.NotNil(tpMsg)
+	assert.False(tpMsg.IsBroadcast)
+	assert.Equal(uint8(0x03), tpMsg.SourceAddress)
+	assert.Equal(uint8(0x00), tpMsg.DestinationAddress)
+	assert.Equal(payload, tpMsg.Data)
+
+	// should reassemble the biggest payload
+	bigPayload := make([]byte, J1939MaxTPSizeByte)
+	canIDs, frames = getJ1939TPFrames(0x00, J1939GlobalAddress, pgn, bigPayload)
+	for idx := range canIDs {
+		tpMsg, err = r.Feed(canIDs[idx], frames[idx])
+		assert.NoError(err)
+	}
+	assert.Len(tpMsg.Data, J1939MaxTPSizeByte)
+
+	// should return an error because a packet is missing
+	canIDs, frames = getJ1939TPFrames(0x00, J1939GlobalAddress, pgn, payload)
+	_, err = r.Feed(canIDs[0], frames[0])
+	assert.NoError(err)
+	_, err = r.Feed(canIDs[2], frames[2])
+	assert.ErrorIs(err, ErrInvalidValue)
+	tpErr := &J1939TPError{}
+	assert.ErrorAs(err, &tpErr)
+	assert.Equal(J1939GlobalAddress, tpErr.DestinationAddress)
+	assert.Equal(0, r.SessionCount())
+
+	// should return an error because the announced size is too big
+	_, err = r.Feed(canIDs[0], []byte{j1939TPCMControlBAM, 0xFA, 0x06, 0xFF, 0xFF, 0xCA, 0xFE, 0x00})
+	assert.ErrorIs(err, ErrTooBig)
+
+	// should drop the session when aborted
+	canIDs, frames = getJ1939TPFrames(0x03, 0x00, pgn, payload)
+	_, err = r.Feed(canIDs[0], frames[0])
+	assert.NoError(err)
+	assert.Equal(1, r.SessionCount())
+	abortID := &J1939ID{Priority: 7, PGN: j1939TPCMPGN, DestinationAddress: 0x03, SourceAddress: 0x00}
+	_, err = r.Feed(abortID.CANID(), []byte{j1939TPCMControlAbort, 0x01, 0xFF, 0xFF, 0xFF, 0xCA, 0xFE, 0x00})
+	assert.NoError(err)
+	assert.Equal(0, r.SessionCount())
+}
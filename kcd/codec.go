@@ -0,0 +1,35 @@
+package kcd
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Decode reads a KCD file from the given reader.
+func Decode(r io.Reader) (*NetworkDefinition, error) {
+	def := new(NetworkDefinition)
+	if err := xml.NewDecoder(r).Decode(def); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// Encode writes the given definition as an indented KCD file
+// into the given writer. The namespace of the format is always set.
+func Encode(w io.Writer, def *NetworkDefinition) error {
+	tmp := *def
+	tmp.Xmlns = Namespace
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&tmp); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
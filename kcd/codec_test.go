@@ -0,0 +1,127 @@
+package kcd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.kcd")
+	assert.NoError(err)
+	defer file.Close()
+
+	def, err := Decode(file)
+	assert.NoError(err)
+
+	assert.Equal("sample_network", def.Document.Name)
+	assert.Equal("Sample network of the tests", def.Document.Content)
+	assert.Len(def.Nodes, 3)
+	assert.Len(def.Nodes[2].Vars, 1)
+
+	assert.Len(def.Buses, 1)
+	bus := def.Buses[0]
+	assert.Equal(1_000_000, bus.Baudrate)
+	assert.Len(bus.Messages, 3)
+
+	// should apply the defaults of the message
+	engine := bus.Messages[0]
+	assert.Equal(MessageID(0x100), engine.ID)
+	assert.Equal("8", engine.Length)
+	assert.Equal(FormatStandard, engine.Format)
+	assert.Equal(10, engine.Interval)
+	assert.Equal("1", engine.Producer.NodeRefs[0].ID)
+
+	// should apply the defaults of the signal and of the value
+	rpm := engine.Signals[0]
+	assert.Equal(EndiannessLittle, rpm.Endianness)
+	assert.Equal(ValueTypeUnsigned, rpm.Value.Type)
+	assert.Equal(0.25, rpm.Value.Slope)
+	assert.Equal(0.0, rpm.Value.Intercept)
+	assert.Equal(16383.75, *rpm.Value.Max)
+
+	temperature := engine.Signals[1]
+	assert.Equal(ValueTypeSigned, temperature.Value.Type)
+	assert.Equal(1.0, temperature.Value.Slope)
+	assert.Nil(temperature.Value.Min)
+	assert.Nil(temperature.Value.Max)
+
+	running := engine.Signals[2]
+	assert.Equal(1, running.Length)
+	assert.Nil(running.Value)
+
+	gear := engine.Signals[3]
+	assert.Len(gear.LabelSet.Labels, 2)
+	assert.Len(gear.LabelSet.LabelGroups, 1)
+
+	assert.Equal(EndiannessBig, engine.Signals[4].Endianness)
+	assert.Equal(UnitNone, engine.Signals[4].Value.Unit)
+
+	diag := bus.Messages[1]
+	assert.Equal(MessageID(0x1ABCDEF), diag.ID)
+	assert.Equal(LengthAuto, diag.Length)
+	assert.Equal(FormatExtended, diag.Format)
+	assert.True(diag.Triggered)
+	assert.Len(diag.Multiplexes, 1)
+	assert.Len(diag.Multiplexes[0].MuxGroups, 3)
+	assert.Equal(2, diag.Multiplexes[0].Length)
+
+	assert.True(bus.Messages[2].Remote)
+	assert.Nil(bus.Messages[2].Producer)
+}
+
+func Test_Decode_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	decode := func(content string) error {
+		_, err := Decode(strings.NewReader(content))
+		return err
+	}
+
+	// should return ErrInvalidID
+	assert.ErrorIs(decode(`<NetworkDefinition><Bus name="b"><Message id="zz" name="m"/></Bus></NetworkDefinition>`), ErrInvalidID)
+
+	// should return ErrInvalidFormat
+	assert.ErrorIs(decode(`<NetworkDefinition><Bus name="b"><Message id="1" name="m" format="fd"/></Bus></NetworkDefinition>`), ErrInvalidFormat)
+
+	// should return ErrInvalidEndianness
+	assert.ErrorIs(decode(`<NetworkDefinition><Bus name="b"><Message id="1" name="m"><Signal name="s" offset="0" endianess="middle"/></Message></Bus></NetworkDefinition>`), ErrInvalidEndianness)
+
+	// should return ErrInvalidValueType
+	assert.ErrorIs(decode(`<NetworkDefinition><Bus name="b"><Message id="1" name="m"><Signal name="s" offset="0"><Value type="complex"/></Signal></Message></Bus></NetworkDefinition>`), ErrInvalidValueType)
+
+	// should return an error for a malformed file
+	assert.Error(decode(`<NetworkDefinition>`))
+}
+
+func Test_Encode(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.kcd")
+	assert.NoError(err)
+	defer file.Close()
+
+	def, err := Decode(file)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(Encode(buf, def))
+
+	content := buf.String()
+	assert.True(strings.HasPrefix(content, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(content, `<NetworkDefinition xmlns="http://kayak.2codeornot2code.org/1.0">`)
+	assert.Contains(content, `<Message id="0x100" name="engine" length="8" interval="10" format="standard">`)
+	assert.Contains(content, `endianess="big"`)
+
+	// should decode the same definition
+	decDef, err := Decode(buf)
+	assert.NoError(err)
+	decDef.XMLName = def.XMLName
+	decDef.Xmlns = def.Xmlns
+	assert.Equal(def, decDef)
+}
@@ -0,0 +1,303 @@
+package kcd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+// Namespace is the XML namespace of the KCD files.
+const Namespace = "http://kayak.2codeornot2code.org/1.0"
+
+// LengthAuto is the length of a message computed from its signals.
+const LengthAuto = "auto"
+
+// NetworkDefinition is the root element of a KCD file.
+type NetworkDefinition struct {
+	XMLName xml.Name `xml:"NetworkDefinition"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+
+	Document *Document `xml:"Document"`
+	Nodes    []*Node   `xml:"Node"`
+	Buses    []*Bus    `xml:"Bus"`
+}
+
+// Document holds the information about the file.
+type Document struct {
+	Name    string `xml:"name,attr,omitempty"`
+	Version string `xml:"version,attr,omitempty"`
+	Author  string `xml:"author,attr,omitempty"`
+	Company string `xml:"company,attr,omitempty"`
+	Date    string `xml:"date,attr,omitempty"`
+
+	// Content is the description of the network.
+	Content string `xml:",chardata"`
+}
+
+// Node is an ECU connected to one or more buses.
+// Its id is referenced by the [NodeRef] elements.
+type Node struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"name,attr,omitempty"`
+
+	Vars []*Var `xml:"Var"`
+}
+
+// Var is a variable of a [Node].
+type Var struct {
+	Name  string `xml:"name,attr"`
+	Value *Value `xml:"Value"`
+}
+
+// NodeRef refers to a [Node] by its id.
+type NodeRef struct {
+	ID string `xml:"id,attr"`
+}
+
+// Bus is a CAN bus with its messages.
+type Bus struct {
+	Name     string `xml:"name,attr"`
+	Baudrate int    `xml:"baudrate,attr,omitempty"`
+
+	Messages []*Message `xml:"Message"`
+}
+
+// UnmarshalXML decodes a bus, applying the default baudrate of 500 kbit/s.
+func (b *Bus) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type bus Bus
+	tmp := bus{Baudrate: 500_000}
+	if err := d.DecodeElement(&tmp, &start); err != nil {
+		return err
+	}
+	*b = Bus(tmp)
+	return nil
+}
+
+// MessageID is the CAN-ID of a [Message], written as an hexadecimal number.
+type MessageID uint32
+
+// UnmarshalXMLAttr decodes the id from a decimal or a 0x prefixed hexadecimal number.
+func (id *MessageID) UnmarshalXMLAttr(attr xml.Attr) error {
+	val, err := strconv.ParseUint(attr.Value, 0, 32)
+	if err != nil {
+		return ErrInvalidID
+	}
+	*id = MessageID(val)
+	return nil
+}
+
+// MarshalXMLAttr encodes the id as a 0x prefixed hexadecimal number.
+func (id MessageID) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: fmt.Sprintf("0x%03X", uint32(id))}, nil
+}
+
+// Format is the format of the CAN-ID of a [Message].
+type Format string
+
+const (
+	// FormatStandard defines a standard (11 bits) CAN-ID.
+	FormatStandard Format = "standard"
+	// FormatExtended defines an extended (29 bits) CAN-ID.
+	FormatExtended Format = "extended"
+)
+
+// UnmarshalXMLAttr decodes the format, checking that it is valid.
+func (f *Format) UnmarshalXMLAttr(attr xml.Attr) error {
+	switch Format(attr.Value) {
+	case FormatStandard, FormatExtended:
+		*f = Format(attr.Value)
+		return nil
+	}
+	return ErrInvalidFormat
+}
+
+// Message is a CAN message sent on a [Bus].
+type Message struct {
+	ID   MessageID `xml:"id,attr"`
+	Name string    `xml:"name,attr"`
+
+	// Length is the size of the message in bytes, or [LengthAuto].
+	Length string `xml:"length,attr,omitempty"`
+	// Interval is the cycle time of the message in ms.
+	Interval  int    `xml:"interval,attr,omitempty"`
+	Triggered bool   `xml:"triggered,attr,omitempty"`
+	Count     int    `xml:"count,attr,omitempty"`
+	Format    Format `xml:"format,attr,omitempty"`
+	Remote    bool   `xml:"remote,attr,omitempty"`
+
+	Notes       string       `xml:"Notes,omitempty"`
+	Producer    *Producer    `xml:"Producer"`
+	Multiplexes []*Multiplex `xml:"Multiplex"`
+	Signals     []*Signal    `xml:"Signal"`
+}
+
+// UnmarshalXML decodes a message, applying the default length and format.
+func (m *Message) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type message Message
+	tmp := message{Length: LengthAuto, Format: FormatStandard}
+	if err := d.DecodeElement(&tmp, &start); err != nil {
+		return err
+	}
+	*m = Message(tmp)
+	return nil
+}
+
+// Producer holds the nodes that send a [Message].
+type Producer struct {
+	NodeRefs []*NodeRef `xml:"NodeRef"`
+}
+
+// Consumer holds the nodes that receive a [Signal].
+type Consumer struct {
+	NodeRefs []*NodeRef `xml:"NodeRef"`
+}
+
+// Endianness is the byte order of a [Signal].
+type Endianness string
+
+const (
+	// EndiannessLittle defines a little endian signal.
+	EndiannessLittle Endianness = "little"
+	// EndiannessBig defines a big endian signal.
+	EndiannessBig Endianness = "big"
+)
+
+// UnmarshalXMLAttr decodes the endianness, checking that it is valid.
+func (e *Endianness) UnmarshalXMLAttr(attr xml.Attr) error {
+	switch Endianness(attr.Value) {
+	case EndiannessLittle, EndiannessBig:
+		*e = Endianness(attr.Value)
+		return nil
+	}
+	return ErrInvalidEndianness
+}
+
+// Signal is a signal of a [Message] or of a [MuxGroup].
+//
+// The offset is the position of the least significant bit for the little
+// endian signals, and the position of the most significant bit for the big
+// endian ones, where the bits of every byte are counted from the most significant.
+type Signal struct {
+	Name       string     `xml:"name,attr"`
+	Offset     int        `xml:"offset,attr"`
+	Length     int        `xml:"length,attr,omitempty"`
+	Endianness Endianness `xml:"endianess,attr,omitempty"`
+
+	Notes    string    `xml:"Notes,omitempty"`
+	Consumer *Consumer `xml:"Consumer"`
+	Value    *Value    `xml:"Value"`
+	LabelSet *LabelSet `xml:"LabelSet"`
+}
+
+// UnmarshalXML decodes a signal, applying the default length and endianness.
+func (s *Signal) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type signal Signal
+	tmp := signal{Length: 1, Endianness: EndiannessLittle}
+	if err := d.DecodeElement(&tmp, &start); err != nil {
+		return err
+	}
+	*s = Signal(tmp)
+	return nil
+}
+
+// Multiplex is a multiplexor signal, which selects the [MuxGroup]
+// whose count is equal to its value.
+type Multiplex struct {
+	Name       string     `xml:"name,attr"`
+	Offset     int        `xml:"offset,attr"`
+	Length     int        `xml:"length,attr,omitempty"`
+	Endianness Endianness `xml:"endianess,attr,omitempty"`
+
+	Notes     string      `xml:"Notes,omitempty"`
+	Consumer  *Consumer   `xml:"Consumer"`
+	Value     *Value      `xml:"Value"`
+	LabelSet  *LabelSet   `xml:"LabelSet"`
+	MuxGroups []*MuxGroup `xml:"MuxGroup"`
+}
+
+// UnmarshalXML decodes a multiplex, applying the default length and endianness.
+func (m *Multiplex) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type multiplex Multiplex
+	tmp := multiplex{Length: 1, Endianness: EndiannessLittle}
+	if err := d.DecodeElement(&tmp, &start); err != nil {
+		return err
+	}
+	*m = Multiplex(tmp)
+	return nil
+}
+
+// MuxGroup holds the signals sent when the value of
+// the parent [Multiplex] is equal to the count.
+type MuxGroup struct {
+	Count   int       `xml:"count,attr"`
+	Signals []*Signal `xml:"Signal"`
+}
+
+// ValueType is the type of the raw value of a signal.
+type ValueType string
+
+const (
+	// ValueTypeUnsigned defines an unsigned integer.
+	ValueTypeUnsigned ValueType = "unsigned"
+	// ValueTypeSigned defines a signed integer in two's complement.
+	ValueTypeSigned ValueType = "signed"
+	// ValueTypeSingle defines an IEEE 754 single precision float.
+	ValueTypeSingle ValueType = "single"
+	// ValueTypeDouble defines an IEEE 754 double precision float.
+	ValueTypeDouble ValueType = "double"
+)
+
+// UnmarshalXMLAttr decodes the value type, checking that it is valid.
+func (vt *ValueType) UnmarshalXMLAttr(attr xml.Attr) error {
+	switch ValueType(attr.Value) {
+	case ValueTypeUnsigned, ValueTypeSigned, ValueTypeSingle, ValueTypeDouble:
+		*vt = ValueType(attr.Value)
+		return nil
+	}
+	return ErrInvalidValueType
+}
+
+// UnitNone is the unit of the dimensionless values.
+const UnitNone = "1"
+
+// Value describes how the raw value of a signal is converted to the physical one:
+// physical = raw * slope + intercept.
+// Min and Max are nil when they are not defined.
+type Value struct {
+	Type      ValueType `xml:"type,attr,omitempty"`
+	Slope     float64   `xml:"slope,attr"`
+	Intercept float64   `xml:"intercept,attr"`
+	Unit      string    `xml:"unit,attr,omitempty"`
+	Min       *float64  `xml:"min,attr"`
+	Max       *float64  `xml:"max,attr"`
+}
+
+// UnmarshalXML decodes a value, applying the default type, slope and unit.
+func (v *Value) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type value Value
+	tmp := value{Type: ValueTypeUnsigned, Slope: 1, Unit: UnitNone}
+	if err := d.DecodeElement(&tmp, &start); err != nil {
+		return err
+	}
+	*v = Value(tmp)
+	return nil
+}
+
+// LabelSet holds the names of the values of a signal.
+type LabelSet struct {
+	Labels      []*Label      `xml:"Label"`
+	LabelGroups []*LabelGroup `xml:"LabelGroup"`
+}
+
+// Label is the name of a single value.
+type Label struct {
+	Value int    `xml:"value,attr"`
+	Name  string `xml:"name,attr"`
+}
+
+// LabelGroup is the name of a range of values, both ends included.
+type LabelGroup struct {
+	From int    `xml:"from,attr"`
+	To   int    `xml:"to,attr"`
+	Name string `xml:"name,attr"`
+}
@@ -0,0 +1,7 @@
+// Package kcd reads and writes the Kayak CAN definition files (.kcd),
+// the XML format used by Kayak and SavvyCAN.
+//
+// The elements of the file are represented by [NetworkDefinition] and its children,
+// which follow the schema of the format. The attributes that are missing
+// from a file take the default value defined by the schema.
+package kcd
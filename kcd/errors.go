@@ -0,0 +1,14 @@
+package kcd
+
+import "errors"
+
+var (
+	// ErrInvalidID is returned when the identifier of a message is not a valid number.
+	ErrInvalidID = errors.New("invalid id")
+	// ErrInvalidEndianness is returned when the endianness of a signal is neither little nor big.
+	ErrInvalidEndianness = errors.New("invalid endianness")
+	// ErrInvalidValueType is returned when the type of a value is not supported.
+	ErrInvalidValueType = errors.New("invalid value type")
+	// ErrInvalidFormat is returned when the format of a message is neither standard nor extended.
+	ErrInvalidFormat = errors.New("invalid format")
+)
@@ -0,0 +1,373 @@
+package acmelib
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/squadracorsepolito/acmelib/kcd"
+)
+
+// ExportKCD exports the given [Network] to KCD.
+// It writes the content of the result KCD file into the [io.Writer].
+//
+// Every [Node] connected to a bus of the network is written once, with its id.
+// The placeholder node used for the messages without a sender is skipped.
+// The receivers of a [Message] become the consumers of all its signals.
+//
+// The information that cannot be represented by the format is skipped,
+// and it is described by the returned slice of [KCDLoss].
+func ExportKCD(w io.Writer, network *Network) ([]*KCDLoss, error) {
+	exp := newKCDExporter()
+	def := exp.exportNetwork(network)
+
+	if err := kcd.Encode(w, def); err != nil {
+		return nil, err
+	}
+
+	return exp.losses, nil
+}
+
+type kcdExporter struct {
+	def *kcd.NetworkDefinition
+
+	losses []*KCDLoss
+
+	nodeIDs map[EntityID]string
+}
+
+func newKCDExporter() *kcdExporter {
+	return &kcdExporter{
+		def: new(kcd.NetworkDefinition),
+
+		losses: []*KCDLoss{},
+
+		nodeIDs: make(map[EntityID]string),
+	}
+}
+
+func (e *kcdExporter) addLoss(path, format string, args ...any) {
+	e.losses = append(e.losses, &KCDLoss{
+		Path:   path,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (e *kcdExporter) exportNetwork(net *Network) *kcd.NetworkDefinition {
+	e.def.Document = &kcd.Document{
+		Name:    net.name,
+		Content: net.desc,
+	}
+
+	buses := net.Buses()
+
+	e.exportNodes(buses)
+
+	for _, bus := range buses {
+		e.exportBus(bus)
+	}
+
+	return e.def
+}
+
+func (e *kcdExporter) exportNodes(buses []*Bus) {
+	nodes := []*Node{}
+	for _, bus := range buses {
+		for _, nodeInt := range bus.NodeInterfaces() {
+			node := nodeInt.node
+			if node.name == dbc.DummyNode || slices.Contains(nodes, node) {
+				continue
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	slices.SortFunc(nodes, func(a, b *Node) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	// the ids of the nodes must be unique
+	nextID := NodeID(0)
+	for _, node := range nodes {
+		nextID = max(nextID, node.id+1)
+	}
+
+	exported := make(map[NodeID]bool)
+	for _, node := range nodes {
+		nodeID := node.id
+		if exported[nodeID] {
+			nodeID = nextID
+			nextID++
+			e.addLoss(node.name, "id %d already used, replaced by %d", node.id, nodeID)
+		}
+		exported[nodeID] = true
+
+		kcdID := strconv.FormatUint(uint64(nodeID), 10)
+		e.nodeIDs[node.entityID] = kcdID
+
+		if node.desc != "" {
+			e.addLoss(node.name, "description")
+		}
+
+		if len(node.AttributeAssignments()) > 0 {
+			e.addLoss(node.name, "attributes")
+		}
+
+		if len(node.gatewayRoutes) > 0 {
+			e.addLoss(node.name, "gateway routes")
+		}
+
+		e.def.Nodes = append(e.def.Nodes, &kcd.Node{
+			ID:   kcdID,
+			Name: node.name,
+		})
+	}
+}
+
+func (e *kcdExporter) exportBus(bus *Bus) {
+	kcdBus := &kcd.Bus{
+		Name:     bus.name,
+		Baudrate: bus.baudrate,
+	}
+
+	if bus.desc != "" {
+		e.addLoss(bus.name, "description")
+	}
+
+	if len(bus.AttributeAssignments()) > 0 {
+		e.addLoss(bus.name, "attributes")
+	}
+
+	if bus.typ == BusTypeCANFD {
+		e.addLoss(bus.name, "CAN FD type and data baudrate")
+	}
+
+	for _, nodeInt := range bus.NodeInterfaces() {
+		for _, msg := range nodeInt.SentMessages() {
+			kcdBus.Messages = append(kcdBus.Messages, e.exportMessage(bus.name+"/"+msg.name, msg))
+		}
+	}
+
+	slices.SortStableFunc(kcdBus.Messages, func(a, b *kcd.Message) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	e.def.Buses = append(e.def.Buses, kcdBus)
+}
+
+func (e *kcdExporter) exportMessage(path string, msg *Message) *kcd.Message {
+	kcdMsg := &kcd.Message{
+		ID:     kcd.MessageID(msg.GetCANID()),
+		Name:   msg.name,
+		Length: strconv.Itoa(msg.sizeByte),
+		Format: kcd.FormatStandard,
+		Notes:  msg.desc,
+	}
+
+	if msg.IsExtended() {
+		kcdMsg.Format = kcd.FormatExtended
+	}
+
+	if msg.cycleTime > 0 {
+		kcdMsg.Interval = msg.cycleTime
+	}
+
+	switch msg.sendType {
+	case MessageSendTypeCyclicAndTriggered:
+		kcdMsg.Triggered = true
+	case MessageSendTypeCyclicIfActive:
+		e.addLoss(path, "cyclic if active send type, written as cyclic")
+	case MessageSendTypeCyclicIfActiveAndTriggered:
+		kcdMsg.Triggered = true
+		e.addLoss(path, "cyclic if active and triggered send type, written as cyclic and triggered")
+	}
+
+	if msg.delayTime != 0 || msg.startDelayTime != 0 {
+		e.addLoss(path, "delay and start delay times")
+	}
+
+	if len(msg.AttributeAssignments()) > 0 {
+		e.addLoss(path, "attributes")
+	}
+
+	if msg.e2eProtection != nil {
+		e.addLoss(path, "E2E protection")
+	}
+
+	if msg.transport != MessageTransportNone {
+		e.addLoss(path, "%s transport", msg.transport)
+	}
+
+	if kcdID, ok := e.nodeIDs[msg.senderNodeInt.node.entityID]; ok {
+		kcdMsg.Producer = &kcd.Producer{
+			NodeRefs: []*kcd.NodeRef{{ID: kcdID}},
+		}
+	}
+
+	var consumer *kcd.Consumer
+	for _, rec := range msg.Receivers() {
+		kcdID, ok := e.nodeIDs[rec.node.entityID]
+		if !ok {
+			continue
+		}
+
+		if consumer == nil {
+			consumer = new(kcd.Consumer)
+		}
+		consumer.NodeRefs = append(consumer.NodeRefs, &kcd.NodeRef{ID: kcdID})
+	}
+
+	for sig := range msg.layout.ibst.InOrder() {
+		sigPath := path + "/" + sig.Name()
+
+		if sig.Kind() != SignalKindMuxor {
+			kcdMsg.Signals = append(kcdMsg.Signals, e.exportSignal(sigPath, sig, consumer))
+			continue
+		}
+
+		muxLayer, ok := msg.layout.muxLayers.Get(sig.EntityID())
+		if !ok {
+			continue
+		}
+		kcdMsg.Multiplexes = append(kcdMsg.Multiplexes, e.exportMultiplexedLayer(sigPath, muxLayer, consumer))
+	}
+
+	return kcdMsg
+}
+
+func (e *kcdExporter) exportMultiplexedLayer(path string, muxLayer *MultiplexedLayer, consumer *kcd.Consumer) *kcd.Multiplex {
+	muxor := muxLayer.muxor
+	e.exportSignalLosses(path, muxor)
+
+	kcdMux := &kcd.Multiplex{
+		Name:       muxor.name,
+		Offset:     muxor.StartPos(),
+		Length:     muxor.Size(),
+		Endianness: e.getEndianness(muxor),
+		Notes:      muxor.desc,
+		Consumer:   consumer,
+	}
+
+	for layoutID, layout := range muxLayer.iterLayouts() {
+		if layout.SignalCount() == 0 {
+			continue
+		}
+
+		kcdGroup := &kcd.MuxGroup{Count: layoutID}
+
+		for sig := range layout.ibst.InOrder() {
+			sigPath := path + "/" + sig.Name()
+
+			if sig.Kind() == SignalKindMuxor {
+				e.addLoss(sigPath, "nested multiplexing, the multiplexor and its signals are skipped")
+				continue
+			}
+
+			kcdGroup.Signals = append(kcdGroup.Signals, e.exportSignal(sigPath, sig, consumer))
+		}
+
+		kcdMux.MuxGroups = append(kcdMux.MuxGroups, kcdGroup)
+	}
+
+	return kcdMux
+}
+
+func (e *kcdExporter) getEndianness(sig Signal) kcd.Endianness {
+	if sig.Endianness() == EndiannessBigEndian {
+		return kcd.EndiannessBig
+	}
+	return kcd.EndiannessLittle
+}
+
+func (e *kcdExporter) exportSignalLosses(path string, sig Signal) {
+	if len(sig.AttributeAssignments()) > 0 {
+		e.addLoss(path, "attributes")
+	}
+
+	if sig.StartValue() != 0 {
+		e.addLoss(path, "start value")
+	}
+
+	if sig.SendType() != SignalSendTypeUnset {
+		e.addLoss(path, "send type")
+	}
+}
+
+func (e *kcdExporter) exportSignal(path string, sig Signal, consumer *kcd.Consumer) *kcd.Signal {
+	e.exportSignalLosses(path, sig)
+
+	kcdSig := &kcd.Signal{
+		Name:       sig.Name(),
+		Offset:     sig.StartPos(),
+		Length:     sig.Size(),
+		Endianness: e.getEndianness(sig),
+		Notes:      sig.Desc(),
+		Consumer:   consumer,
+	}
+
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+		kcdSig.Value = e.exportStandardSignalValue(stdSig)
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+		kcdSig.LabelSet = e.exportSignalEnum(enumSig.enum)
+	}
+
+	return kcdSig
+}
+
+func (e *kcdExporter) exportStandardSignalValue(stdSig *StandardSignal) *kcd.Value {
+	sigType := stdSig.typ
+
+	// a flag is described by the default value
+	if sigType.kind == SignalTypeKindFlag {
+		return nil
+	}
+
+	kcdVal := &kcd.Value{
+		Type:      kcd.ValueTypeUnsigned,
+		Slope:     sigType.scale,
+		Intercept: sigType.offset,
+		Min:       &sigType.min,
+		Max:       &sigType.max,
+	}
+
+	switch {
+	case sigType.kind == SignalTypeKindFloat && sigType.size == 64:
+		kcdVal.Type = kcd.ValueTypeDouble
+	case sigType.kind == SignalTypeKindFloat:
+		kcdVal.Type = kcd.ValueTypeSingle
+	case sigType.signed:
+		kcdVal.Type = kcd.ValueTypeSigned
+	}
+
+	if stdSig.unit != nil && stdSig.unit.symbol != "" {
+		kcdVal.Unit = stdSig.unit.symbol
+	}
+
+	return kcdVal
+}
+
+func (e *kcdExporter) exportSignalEnum(enum *SignalEnum) *kcd.LabelSet {
+	labelSet := new(kcd.LabelSet)
+
+	for _, val := range enum.values {
+		labelSet.Labels = append(labelSet.Labels, &kcd.Label{
+			Value: val.index,
+			Name:  val.name,
+		})
+	}
+
+	return labelSet
+}
@@ -0,0 +1,137 @@
+package acmelib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertEqualKCDLayouts(assert *assert.Assertions, layout, expLayout *SignalLayout) {
+	sigs := layout.Signals()
+	expSigs := expLayout.Signals()
+	assert.Len(expSigs, len(sigs))
+
+	for idx, sig := range sigs {
+		expSig := expSigs[idx]
+		assert.Equal(sig.Name(), expSig.Name())
+		assert.Equal(sig.Kind(), expSig.Kind())
+		assert.Equal(sig.StartPos(), expSig.StartPos())
+		assert.Equal(sig.Size(), expSig.Size())
+		assert.Equal(sig.Endianness(), expSig.Endianness())
+	}
+
+	muxLayers := layout.MultiplexedLayers()
+	expMuxLayers := expLayout.MultiplexedLayers()
+	assert.Len(expMuxLayers, len(muxLayers))
+
+	for idx, muxLayer := range muxLayers {
+		expMuxLayer := expMuxLayers[idx]
+		assert.Equal(muxLayer.GetLayoutCount(), expMuxLayer.GetLayoutCount())
+
+		for layoutID, muxLayout := range muxLayer.Layouts() {
+			assertEqualKCDLayouts(assert, muxLayout, expMuxLayer.GetLayout(layoutID))
+		}
+	}
+}
+
+func Test_ExportKCD(t *testing.T) {
+	assert := assert.New(t)
+
+	net, _ := importKCDTestFile(assert)
+
+	buf := new(bytes.Buffer)
+	losses, err := ExportKCD(buf, net)
+	assert.NoError(err)
+	assert.Empty(losses)
+
+	content := buf.String()
+	assert.Contains(content, `<Node id="3" name="logger"></Node>`)
+	assert.NotContains(content, "Vector__XXX")
+	assert.Contains(content, `<Message id="0x1ABCDEF" name="diagnostic" length="4" interval="100" triggered="true" format="extended">`)
+	assert.Contains(content, `<Multiplex name="page" offset="0" length="2" endianess="little">`)
+
+	// should import the same network
+	expNet, _, err := ImportKCD("exported.kcd", strings.NewReader(content))
+	assert.NoError(err)
+	assert.Equal(net.Name(), expNet.Name())
+	assert.Equal(net.Desc(), expNet.Desc())
+
+	bus := net.Buses()[0]
+	expBus := expNet.Buses()[0]
+	assert.Equal(bus.Baudrate(), expBus.Baudrate())
+	assert.Len(expBus.NodeInterfaces(), len(bus.NodeInterfaces()))
+
+	for _, nodeInt := range bus.NodeInterfaces() {
+		expNodeInt, err := expBus.GetNodeInterfaceByNodeName(nodeInt.Node().Name())
+		assert.NoError(err)
+
+		msgs := nodeInt.SentMessages()
+		expMsgs := expNodeInt.SentMessages()
+		assert.Len(expMsgs, len(msgs))
+
+		for idx, msg := range msgs {
+			expMsg := expMsgs[idx]
+			assert.Equal(msg.Name(), expMsg.Name())
+			assert.Equal(msg.GetCANID(), expMsg.GetCANID())
+			assert.Equal(msg.SizeByte(), expMsg.SizeByte())
+			assert.Equal(msg.SendType(), expMsg.SendType())
+			assert.Equal(msg.Desc(), expMsg.Desc())
+			assert.Len(expMsg.Receivers(), len(msg.Receivers()))
+			assertEqualKCDLayouts(assert, msg.SignalLayout(), expMsg.SignalLayout())
+		}
+	}
+}
+
+func Test_ExportKCD_Losses(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("net")
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCANFD)
+	assert.NoError(net.AddBus(bus))
+
+	otherBus := NewBus("other_bus")
+	assert.NoError(net.AddBus(otherBus))
+
+	node0 := NewNode("node_0", 0, 1)
+	node1 := NewNode("node_1", 0, 1)
+	assert.NoError(bus.AddNodeInterface(node0.GetInterface(0)))
+	assert.NoError(otherBus.AddNodeInterface(node1.GetInterface(0)))
+
+	msg := NewMessage("msg", 1, 8)
+	msg.SetCycleTime(10)
+	msg.SetSendType(MessageSendTypeCyclicIfActive)
+	assert.NoError(node0.GetInterface(0).AddSentMessage(msg))
+
+	sig, err := NewStandardSignal("sig", NewFlagSignalType("flag"))
+	assert.NoError(err)
+	sig.SetStartValue(1)
+	assert.NoError(msg.InsertSignal(sig, 0))
+
+	muxor, err := NewMuxorSignal("muxor", 2)
+	assert.NoError(err)
+	muxLayer, err := msg.SignalLayout().AddMultiplexedLayer(muxor, 8)
+	assert.NoError(err)
+	innerMuxor, err := NewMuxorSignal("inner_muxor", 2)
+	assert.NoError(err)
+	_, err = muxLayer.GetLayout(0).AddMultiplexedLayer(innerMuxor, 16)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+	losses, err := ExportKCD(buf, net)
+	assert.NoError(err)
+
+	lossReasons := []string{}
+	for _, loss := range losses {
+		lossReasons = append(lossReasons, loss.String())
+	}
+	assert.ElementsMatch([]string{
+		"node_1: id 0 already used, replaced by 1",
+		"bus: CAN FD type and data baudrate",
+		"bus/msg: cyclic if active send type, written as cyclic",
+		"bus/msg/sig: start value",
+		"bus/msg/muxor/inner_muxor: nested multiplexing, the multiplexor and its signals are skipped",
+	}, lossReasons)
+}
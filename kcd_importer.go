@@ -0,0 +1,638 @@
+package acmelib
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/squadracorsepolito/acmelib/kcd"
+)
+
+// KCDLoss describes a piece of information that is lost while
+// importing or exporting a KCD file, because the target cannot represent it.
+type KCDLoss struct {
+	// Path locates the element, like "bus/message/signal".
+	Path string
+	// Reason describes what is lost.
+	Reason string
+}
+
+func (l *KCDLoss) String() string {
+	return fmt.Sprintf("%s: %s", l.Path, l.Reason)
+}
+
+// ImportKCD imports a KCD file passed as [io.Reader] and converts it to a [Network].
+// The name of the network is taken from the document of the file,
+// or from the given filename if the document does not have one.
+//
+// A KCD node becomes a [Node] with an interface for every bus where it sends or
+// receives a message. The messages without a producer are sent by a placeholder
+// node with the same name used by the DBC files. The multiplex elements become
+// [MultiplexedLayer] and the signals with a label set become [EnumSignal].
+// The consumers of the signals are merged into the receivers of the message.
+//
+// The information that cannot be represented by the model is skipped,
+// and it is described by the returned slice of [KCDLoss].
+func ImportKCD(filename string, r io.Reader) (*Network, []*KCDLoss, error) {
+	def, err := kcd.Decode(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	importer := newKCDImporter()
+	net, err := importer.importDefinition(filename, def)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return net, importer.losses, nil
+}
+
+type kcdImporter struct {
+	net *Network
+
+	losses []*KCDLoss
+
+	nodes      map[string]*Node
+	nextNodeID NodeID
+	dummyNode  *Node
+
+	flagSigType *SignalType
+	signalTypes map[string]*SignalType
+	signalUnits map[string]*SignalUnit
+}
+
+func newKCDImporter() *kcdImporter {
+	return &kcdImporter{
+		losses: []*KCDLoss{},
+
+		nodes: make(map[string]*Node),
+
+		flagSigType: NewFlagSignalType("flag"),
+		signalTypes: make(map[string]*SignalType),
+		signalUnits: make(map[string]*SignalUnit),
+	}
+}
+
+func (i *kcdImporter) errorf(path string, err error) error {
+	return fmt.Errorf("%s : %w", path, err)
+}
+
+func (i *kcdImporter) addLoss(path, format string, args ...any) {
+	i.losses = append(i.losses, &KCDLoss{
+		Path:   path,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+func (i *kcdImporter) importDefinition(filename string, def *kcd.NetworkDefinition) (*Network, error) {
+	netName := filename
+
+	if doc := def.Document; doc != nil {
+		if doc.Name != "" {
+			netName = doc.Name
+		}
+
+		if doc.Version != "" || doc.Author != "" || doc.Company != "" || doc.Date != "" {
+			i.addLoss(netName, "version, author, company and date of the document")
+		}
+	}
+
+	i.net = NewNetwork(netName)
+
+	if def.Document != nil {
+		i.net.SetDesc(def.Document.Content)
+	}
+
+	if err := i.importNodes(def.Nodes); err != nil {
+		return nil, err
+	}
+
+	for _, kcdBus := range def.Buses {
+		if err := i.importBus(kcdBus); err != nil {
+			return nil, err
+		}
+	}
+
+	// the nodes without messages can only be connected when the bus is unambiguous
+	buses := i.net.Buses()
+	for _, kcdNode := range def.Nodes {
+		node := i.nodes[kcdNode.ID]
+		if node.interfaceCount > 0 {
+			continue
+		}
+
+		if len(buses) != 1 {
+			i.addLoss(node.name, "node not connected to any bus")
+			continue
+		}
+
+		if _, err := i.getNodeInterface(buses[0], kcdNode.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return i.net, nil
+}
+
+func (i *kcdImporter) importNodes(kcdNodes []*kcd.Node) error {
+	// the numeric ids are kept, the other ones are replaced
+	for _, kcdNode := range kcdNodes {
+		if id, err := strconv.ParseUint(kcdNode.ID, 10, 32); err == nil {
+			i.nextNodeID = max(i.nextNodeID, NodeID(id)+1)
+		}
+	}
+
+	for _, kcdNode := range kcdNodes {
+		if _, ok := i.nodes[kcdNode.ID]; ok {
+			return i.errorf(kcdNode.ID, ErrIsDuplicated)
+		}
+
+		name := kcdNode.Name
+		if name == "" {
+			name = "node_" + kcdNode.ID
+		}
+
+		var nodeID NodeID
+		if id, err := strconv.ParseUint(kcdNode.ID, 10, 32); err == nil {
+			nodeID = NodeID(id)
+		} else {
+			nodeID = i.getNextNodeID()
+			i.addLoss(name, "id %q replaced by %d", kcdNode.ID, nodeID)
+		}
+
+		node := NewNode(name, nodeID, 0)
+
+		for _, kcdVar := range kcdNode.Vars {
+			i.addLoss(name, "variable %q", kcdVar.Name)
+		}
+
+		i.nodes[kcdNode.ID] = node
+	}
+
+	return nil
+}
+
+func (i *kcdImporter) getNextNodeID() NodeID {
+	nodeID := i.nextNodeID
+	i.nextNodeID++
+	return nodeID
+}
+
+// getNodeInterface returns the interface of the node with the given KCD id
+// connected to the given bus, and it creates it if needed.
+func (i *kcdImporter) getNodeInterface(bus *Bus, kcdNodeID string) (*NodeInterface, error) {
+	node, ok := i.nodes[kcdNodeID]
+	if !ok {
+		return nil, i.errorf(bus.name, newNameError(kcdNodeID, ErrNotFound))
+	}
+
+	return i.connectNode(bus, node)
+}
+
+// getDummyNodeInterface returns the interface of the placeholder node
+// connected to the given bus, and it creates it if needed.
+func (i *kcdImporter) getDummyNodeInterface(bus *Bus) (*NodeInterface, error) {
+	if i.dummyNode == nil {
+		i.dummyNode = NewNode(dbc.DummyNode, i.getNextNodeID(), 0)
+	}
+
+	return i.connectNode(bus, i.dummyNode)
+}
+
+func (i *kcdImporter) connectNode(bus *Bus, node *Node) (*NodeInterface, error) {
+	for _, nodeInt := range node.interfaces {
+		if nodeInt.parentBus == bus {
+			return nodeInt, nil
+		}
+	}
+
+	node.AddInterface()
+	nodeInt := node.interfaces[len(node.interfaces)-1]
+
+	if err := bus.AddNodeInterface(nodeInt); err != nil {
+		return nil, i.errorf(bus.name, err)
+	}
+
+	return nodeInt, nil
+}
+
+func (i *kcdImporter) importBus(kcdBus *kcd.Bus) error {
+	bus := NewBus(kcdBus.Name)
+	bus.SetBaudrate(kcdBus.Baudrate)
+
+	// the type of the bus limits the size and the format of the messages
+	for _, kcdMsg := range kcdBus.Messages {
+		if kcdMsg.Format == kcd.FormatExtended && bus.typ == BusTypeCAN2A {
+			bus.SetType(BusTypeCAN2B)
+		}
+
+		if size, err := strconv.Atoi(kcdMsg.Length); err == nil && size > 8 {
+			bus.SetType(BusTypeCANFD)
+		}
+	}
+
+	if err := i.net.AddBus(bus); err != nil {
+		return i.errorf(kcdBus.Name, err)
+	}
+
+	for _, kcdMsg := range kcdBus.Messages {
+		if err := i.importMessage(bus, kcdMsg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getMessageSize returns the size of the message, computing it from
+// the signals when the length is automatic.
+func (i *kcdImporter) getMessageSize(path string, kcdMsg *kcd.Message) (int, error) {
+	if kcdMsg.Length != kcd.LengthAuto {
+		size, err := strconv.Atoi(kcdMsg.Length)
+		if err != nil {
+			return 0, i.errorf(path, newSizeError(0, ErrInvalidValue))
+		}
+		return size, nil
+	}
+
+	maxBit := 0
+	// the offset is the start position for both the endiannesses
+	updateMaxBit := func(offset, length int) {
+		maxBit = max(maxBit, offset+length)
+	}
+
+	for _, kcdSig := range kcdMsg.Signals {
+		updateMaxBit(kcdSig.Offset, kcdSig.Length)
+	}
+
+	for _, kcdMux := range kcdMsg.Multiplexes {
+		updateMaxBit(kcdMux.Offset, kcdMux.Length)
+		for _, kcdGroup := range kcdMux.MuxGroups {
+			for _, kcdSig := range kcdGroup.Signals {
+				updateMaxBit(kcdSig.Offset, kcdSig.Length)
+			}
+		}
+	}
+
+	return (maxBit + 7) / 8, nil
+}
+
+func (i *kcdImporter) importMessage(bus *Bus, kcdMsg *kcd.Message) error {
+	path := bus.name + "/" + kcdMsg.Name
+
+	size, err := i.getMessageSize(path, kcdMsg)
+	if err != nil {
+		return err
+	}
+
+	canID := CANID(kcdMsg.ID)
+	msg := NewMessage(kcdMsg.Name, MessageID(canID), size)
+	msg.SetDesc(kcdMsg.Notes)
+
+	if kcdMsg.Format == kcd.FormatExtended {
+		if err := msg.SetCANIDFormat(CANIDFormatExtended); err != nil {
+			return i.errorf(path, err)
+		}
+	}
+
+	if err := msg.SetStaticCANID(canID); err != nil {
+		return i.errorf(path, err)
+	}
+
+	if kcdMsg.Interval > 0 {
+		msg.SetCycleTime(kcdMsg.Interval)
+
+		if kcdMsg.Triggered {
+			msg.SetSendType(MessageSendTypeCyclicAndTriggered)
+		} else {
+			msg.SetSendType(MessageSendTypeCyclic)
+		}
+	}
+
+	if kcdMsg.Count > 0 {
+		i.addLoss(path, "count of %d", kcdMsg.Count)
+	}
+
+	if kcdMsg.Remote {
+		i.addLoss(path, "remote frame")
+	}
+
+	consumers := []*kcd.Consumer{}
+
+	for _, kcdSig := range kcdMsg.Signals {
+		sig, err := i.importSignal(path, kcdSig)
+		if err != nil {
+			return err
+		}
+
+		if err := msg.InsertSignal(sig, kcdSig.Offset); err != nil {
+			return i.errorf(path+"/"+kcdSig.Name, err)
+		}
+
+		consumers = append(consumers, kcdSig.Consumer)
+	}
+
+	for _, kcdMux := range kcdMsg.Multiplexes {
+		muxConsumers, err := i.importMultiplex(path, msg, kcdMux)
+		if err != nil {
+			return err
+		}
+
+		consumers = append(consumers, muxConsumers...)
+	}
+
+	// the sender
+	var sender *NodeInterface
+	if kcdMsg.Producer != nil && len(kcdMsg.Producer.NodeRefs) > 0 {
+		nodeInt, err := i.getNodeInterface(bus, kcdMsg.Producer.NodeRefs[0].ID)
+		if err != nil {
+			return err
+		}
+		sender = nodeInt
+
+		for _, nodeRef := range kcdMsg.Producer.NodeRefs[1:] {
+			i.addLoss(path, "producer %q, only the first one is kept", nodeRef.ID)
+		}
+	} else {
+		nodeInt, err := i.getDummyNodeInterface(bus)
+		if err != nil {
+			return err
+		}
+		sender = nodeInt
+	}
+
+	// the receivers
+	for _, consumer := range consumers {
+		if consumer == nil {
+			continue
+		}
+
+		for _, nodeRef := range consumer.NodeRefs {
+			nodeInt, err := i.getNodeInterface(bus, nodeRef.ID)
+			if err != nil {
+				return err
+			}
+
+			if nodeInt == sender {
+				continue
+			}
+
+			if err := msg.AddReceiver(nodeInt); err != nil {
+				return i.errorf(path, err)
+			}
+		}
+	}
+
+	if err := sender.AddSentMessage(msg); err != nil {
+		return i.errorf(path, err)
+	}
+
+	return nil
+}
+
+// importMultiplex adds a multiplexed layer to the message,
+// and it returns the consumers of its signals.
+func (i *kcdImporter) importMultiplex(msgPath string, msg *Message, kcdMux *kcd.Multiplex) ([]*kcd.Consumer, error) {
+	path := msgPath + "/" + kcdMux.Name
+
+	muxor, err := NewMuxorSignal(kcdMux.Name, getValueFromSize(kcdMux.Length))
+	if err != nil {
+		return nil, i.errorf(path, err)
+	}
+
+	muxor.SetDesc(kcdMux.Notes)
+	if kcdMux.Endianness == kcd.EndiannessBig {
+		muxor.SetEndianness(EndiannessBigEndian)
+	}
+
+	if kcdMux.Value != nil {
+		i.addLoss(path, "value of the multiplexor")
+	}
+
+	if kcdMux.LabelSet != nil {
+		i.addLoss(path, "label set of the multiplexor")
+	}
+
+	muxLayer, err := msg.layout.AddMultiplexedLayer(muxor, kcdMux.Offset)
+	if err != nil {
+		return nil, i.errorf(path, err)
+	}
+
+	consumers := []*kcd.Consumer{kcdMux.Consumer}
+
+	// the signals repeated in more groups are inserted once
+	type muxedSignal struct {
+		kcdSig    *kcd.Signal
+		layoutIDs []int
+	}
+
+	muxedSignals := []*muxedSignal{}
+	muxedSignalNames := make(map[string]*muxedSignal)
+
+	for _, kcdGroup := range kcdMux.MuxGroups {
+		for _, kcdSig := range kcdGroup.Signals {
+			consumers = append(consumers, kcdSig.Consumer)
+
+			if muxedSig, ok := muxedSignalNames[kcdSig.Name]; ok {
+				prevSig := muxedSig.kcdSig
+				if prevSig.Offset != kcdSig.Offset || prevSig.Length != kcdSig.Length || prevSig.Endianness != kcdSig.Endianness {
+					i.addLoss(path+"/"+kcdSig.Name, "definition in group %d different from the previous ones", kcdGroup.Count)
+					continue
+				}
+
+				muxedSig.layoutIDs = append(muxedSig.layoutIDs, kcdGroup.Count)
+				continue
+			}
+
+			muxedSig := &muxedSignal{kcdSig: kcdSig, layoutIDs: []int{kcdGroup.Count}}
+			muxedSignals = append(muxedSignals, muxedSig)
+			muxedSignalNames[kcdSig.Name] = muxedSig
+		}
+	}
+
+	for _, muxedSig := range muxedSignals {
+		sig, err := i.importSignal(path, muxedSig.kcdSig)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := muxLayer.InsertSignal(sig, muxedSig.kcdSig.Offset, muxedSig.layoutIDs...); err != nil {
+			return nil, i.errorf(path+"/"+muxedSig.kcdSig.Name, err)
+		}
+	}
+
+	return consumers, nil
+}
+
+func (i *kcdImporter) importSignal(msgPath string, kcdSig *kcd.Signal) (Signal, error) {
+	path := msgPath + "/" + kcdSig.Name
+
+	var sig Signal
+	if kcdSig.LabelSet != nil {
+		enumSig, err := i.importEnumSignal(path, kcdSig)
+		if err != nil {
+			return nil, err
+		}
+		sig = enumSig
+	} else {
+		stdSig, err := i.importStandardSignal(path, kcdSig)
+		if err != nil {
+			return nil, err
+		}
+		sig = stdSig
+	}
+
+	sig.SetDesc(kcdSig.Notes)
+	if kcdSig.Endianness == kcd.EndiannessBig {
+		sig.SetEndianness(EndiannessBigEndian)
+	}
+
+	return sig, nil
+}
+
+func (i *kcdImporter) importEnumSignal(path string, kcdSig *kcd.Signal) (*EnumSignal, error) {
+	if val := kcdSig.Value; val != nil {
+		if val.Slope != 1 || val.Intercept != 0 || val.Unit != kcd.UnitNone {
+			i.addLoss(path, "scaling and unit of a signal with labels")
+		}
+
+		if val.Type == kcd.ValueTypeSigned || val.Type == kcd.ValueTypeSingle || val.Type == kcd.ValueTypeDouble {
+			i.addLoss(path, "%s type of a signal with labels", val.Type)
+		}
+	}
+
+	sigEnum := NewSignalEnum(kcdSig.Name)
+
+	for _, label := range kcdSig.LabelSet.Labels {
+		if _, err := sigEnum.AddValue(label.Value, label.Name); err != nil {
+			return nil, i.errorf(path, err)
+		}
+	}
+
+	for _, labelGroup := range kcdSig.LabelSet.LabelGroups {
+		if labelGroup.From != labelGroup.To {
+			i.addLoss(path, "label group %q from %d to %d reduced to the value %d",
+				labelGroup.Name, labelGroup.From, labelGroup.To, labelGroup.From)
+		}
+
+		if _, err := sigEnum.AddValue(labelGroup.From, labelGroup.Name); err != nil {
+			return nil, i.errorf(path, err)
+		}
+	}
+
+	// the enum must be as big as the signal
+	if sigEnum.Size() < kcdSig.Length {
+		sigEnum.SetFixedSize(true)
+		if err := sigEnum.UpdateSize(kcdSig.Length); err != nil {
+			return nil, i.errorf(path, err)
+		}
+	}
+
+	enumSig, err := NewEnumSignal(kcdSig.Name, sigEnum)
+	if err != nil {
+		return nil, i.errorf(path, err)
+	}
+
+	if enumSig.Size() > kcdSig.Length {
+		return nil, i.errorf(path, newSizeError(kcdSig.Length, ErrTooSmall))
+	}
+
+	return enumSig, nil
+}
+
+func (i *kcdImporter) importStandardSignal(path string, kcdSig *kcd.Signal) (*StandardSignal, error) {
+	sigType, err := i.importSignalType(path, kcdSig)
+	if err != nil {
+		return nil, err
+	}
+
+	stdSig, err := NewStandardSignal(kcdSig.Name, sigType)
+	if err != nil {
+		return nil, i.errorf(path, err)
+	}
+
+	if kcdSig.Value != nil {
+		symbol := kcdSig.Value.Unit
+		if symbol != "" && symbol != kcd.UnitNone {
+			sigUnit, ok := i.signalUnits[symbol]
+			if !ok {
+				sigUnit = NewSignalUnit(symbol, SignalUnitKindCustom, symbol)
+				i.signalUnits[symbol] = sigUnit
+			}
+			stdSig.SetUnit(sigUnit)
+		}
+	}
+
+	return stdSig, nil
+}
+
+func (i *kcdImporter) importSignalType(path string, kcdSig *kcd.Signal) (*SignalType, error) {
+	val := kcdSig.Value
+	if val == nil {
+		val = &kcd.Value{Type: kcd.ValueTypeUnsigned, Slope: 1}
+	}
+
+	size := kcdSig.Length
+
+	isFloat := val.Type == kcd.ValueTypeSingle || val.Type == kcd.ValueTypeDouble
+	signed := val.Type == kcd.ValueTypeSigned
+
+	if size == 1 && !signed && !isFloat && val.Slope == 1 && val.Intercept == 0 {
+		return i.flagSigType, nil
+	}
+
+	signStr := "u"
+	if isFloat {
+		signStr = "f"
+	} else if signed {
+		signStr = "s"
+	}
+	sigTypeKey := fmt.Sprintf("%s%d_%g_%g", signStr, size, val.Slope, val.Intercept)
+	if val.Min != nil {
+		sigTypeKey += fmt.Sprintf("_min%g", *val.Min)
+	}
+	if val.Max != nil {
+		sigTypeKey += fmt.Sprintf("_max%g", *val.Max)
+	}
+
+	if sigType, ok := i.signalTypes[sigTypeKey]; ok {
+		return sigType, nil
+	}
+
+	var sigType *SignalType
+	if isFloat {
+		floatSigType, err := NewFloatSignalType(sigTypeKey, size)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		sigType = floatSigType
+	} else if isDecimal(val.Slope) || isDecimal(val.Intercept) {
+		decSigType, err := NewDecimalSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		sigType = decSigType
+	} else {
+		intSigType, err := NewIntegerSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(path, err)
+		}
+		sigType = intSigType
+	}
+
+	sigType.SetScale(val.Slope)
+	sigType.SetOffset(val.Intercept)
+
+	if val.Min != nil {
+		sigType.SetMin(*val.Min)
+	}
+	if val.Max != nil {
+		sigType.SetMax(*val.Max)
+	}
+
+	i.signalTypes[sigTypeKey] = sigType
+
+	return sigType, nil
+}
@@ -0,0 +1,152 @@
+package acmelib
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/stretchr/testify/assert"
+)
+
+const kcdTestFile = "testdata/sample.kcd"
+
+func importKCDTestFile(assert *assert.Assertions) (*Network, []*KCDLoss) {
+	file, err := os.Open(kcdTestFile)
+	assert.NoError(err)
+	defer file.Close()
+
+	net, losses, err := ImportKCD(kcdTestFile, file)
+	assert.NoError(err)
+
+	return net, losses
+}
+
+func Test_ImportKCD(t *testing.T) {
+	assert := assert.New(t)
+
+	net, losses := importKCDTestFile(assert)
+	assert.Equal("sample_network", net.Name())
+	assert.Equal("Sample network of the tests", net.Desc())
+
+	buses := net.Buses()
+	assert.Len(buses, 1)
+	bus := buses[0]
+	assert.Equal(1_000_000, bus.Baudrate())
+	assert.Equal(BusTypeCAN2B, bus.Type())
+
+	// should keep the numeric ids and replace the other ones
+	ecuInt, err := bus.GetNodeInterfaceByNodeName("ecu")
+	assert.NoError(err)
+	assert.Equal(NodeID(1), ecuInt.Node().ID())
+	loggerInt, err := bus.GetNodeInterfaceByNodeName("logger")
+	assert.NoError(err)
+	assert.Equal(NodeID(3), loggerInt.Node().ID())
+
+	// engine message
+	assert.Len(ecuInt.SentMessages(), 1)
+	engine := ecuInt.SentMessages()[0]
+	assert.Equal(CANID(0x100), engine.GetCANID())
+	assert.Equal(8, engine.SizeByte())
+	assert.Equal(10, engine.CycleTime())
+	assert.Equal(MessageSendTypeCyclic, engine.SendType())
+	assert.Equal("Engine status", engine.Desc())
+
+	receivers := []string{}
+	for _, rec := range engine.Receivers() {
+		receivers = append(receivers, rec.Node().Name())
+	}
+	assert.ElementsMatch([]string{"dashboard", "logger"}, receivers)
+
+	rpm, err := engine.GetSignalByName("rpm")
+	assert.NoError(err)
+	rpmStd, err := rpm.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindDecimal, rpmStd.Type().Kind())
+	assert.Equal(0.25, rpmStd.Type().Scale())
+	assert.Equal(16383.75, rpmStd.Type().Max())
+	assert.Equal("rpm", rpmStd.Unit().Symbol())
+
+	temperature, err := engine.GetSignalByName("temperature")
+	assert.NoError(err)
+	temperatureStd, err := temperature.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindInteger, temperatureStd.Type().Kind())
+	assert.True(temperatureStd.Type().Signed())
+	assert.Equal(-40.0, temperatureStd.Type().Offset())
+
+	running, err := engine.GetSignalByName("running")
+	assert.NoError(err)
+	runningStd, err := running.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFlag, runningStd.Type().Kind())
+	assert.Nil(runningStd.Unit())
+
+	gear, err := engine.GetSignalByName("gear")
+	assert.NoError(err)
+	gearEnum, err := gear.ToEnum()
+	assert.NoError(err)
+	assert.Equal(3, gearEnum.Size())
+	assert.Len(gearEnum.Enum().Values(), 3)
+
+	pressure, err := engine.GetSignalByName("pressure")
+	assert.NoError(err)
+	assert.Equal(EndiannessBigEndian, pressure.Endianness())
+	assert.Equal(47, pressure.StartPos())
+
+	// diagnostic message
+	dashboardInt, err := bus.GetNodeInterfaceByNodeName("dashboard")
+	assert.NoError(err)
+	assert.Len(dashboardInt.SentMessages(), 1)
+	diag := dashboardInt.SentMessages()[0]
+	assert.True(diag.IsExtended())
+	assert.Equal(CANID(0x1ABCDEF), diag.GetCANID())
+	assert.Equal(4, diag.SizeByte())
+	assert.Equal(MessageSendTypeCyclicAndTriggered, diag.SendType())
+
+	muxLayers := diag.SignalLayout().MultiplexedLayers()
+	assert.Len(muxLayers, 1)
+	muxLayer := muxLayers[0]
+	assert.Equal("page", muxLayer.Muxor().Name())
+	assert.Equal(4, muxLayer.GetLayoutCount())
+	assert.Equal(1, muxLayer.GetLayout(0).SignalCount())
+	assert.Equal(2, muxLayer.GetLayout(1).SignalCount())
+	assert.Equal(1, muxLayer.GetLayout(2).SignalCount())
+
+	// should send the messages without a producer with the placeholder node
+	dummyInt, err := bus.GetNodeInterfaceByNodeName(dbc.DummyNode)
+	assert.NoError(err)
+	assert.Len(dummyInt.SentMessages(), 1)
+	assert.Equal("unsent", dummyInt.SentMessages()[0].Name())
+
+	lossReasons := []string{}
+	for _, loss := range losses {
+		lossReasons = append(lossReasons, loss.String())
+	}
+	assert.ElementsMatch([]string{
+		"sample_network: version, author, company and date of the document",
+		`logger: id "logger" replaced by 3`,
+		`logger: variable "log_level"`,
+		`powertrain/engine/gear: label group "other" from 2 to 5 reduced to the value 2`,
+		"powertrain/diagnostic: count of 3",
+		"powertrain/unsent: remote frame",
+	}, lossReasons)
+}
+
+func Test_ImportKCD_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	importKCD := func(content string) error {
+		_, _, err := ImportKCD("test.kcd", strings.NewReader(content))
+		return err
+	}
+
+	// should return ErrNotFound for an unknown node
+	assert.ErrorIs(importKCD(`<NetworkDefinition><Bus name="b"><Message id="1" name="m"><Producer><NodeRef id="9"/></Producer></Message></Bus></NetworkDefinition>`), ErrNotFound)
+
+	// should return ErrIsDuplicated for a duplicated node
+	assert.ErrorIs(importKCD(`<NetworkDefinition><Node id="1" name="a"/><Node id="1" name="b"/></NetworkDefinition>`), ErrIsDuplicated)
+
+	// should return an error for overlapping signals
+	assert.Error(importKCD(`<NetworkDefinition><Bus name="b"><Message id="1" name="m"><Signal name="s0" offset="0" length="8"/><Signal name="s1" offset="4" length="8"/></Message></Bus></NetworkDefinition>`))
+}
@@ -0,0 +1,40 @@
+// Package kmatrix provides a reader and a writer for the communication matrix (K-matrix)
+// of a CAN network stored in a spreadsheet, where each row describes a signal
+// and the message that carries it.
+//
+// The spreadsheet can be a CSV file or an XLSX workbook. Only the first worksheet
+// of a workbook is used, and the XLSX files are handled with the standard library.
+// The first row of the sheet contains the headers, that are mapped to the
+// columns of a [Row] by a [Mapping]. The headers missing from the mapping are ignored.
+package kmatrix
+
+// Format is the format of the spreadsheet.
+type Format int
+
+const (
+	// FormatCSV defines a comma separated values file.
+	FormatCSV Format = iota
+	// FormatXLSX defines an Office Open XML workbook.
+	FormatXLSX
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatXLSX:
+		return "xlsx"
+	default:
+		return "unknown"
+	}
+}
+
+// FileExtension returns the extension of the files of the format.
+func (f Format) FileExtension() string {
+	switch f {
+	case FormatXLSX:
+		return ".xlsx"
+	default:
+		return ".csv"
+	}
+}
@@ -0,0 +1,356 @@
+package kmatrix
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Read reads the rows of the sheet in the given format from the [io.Reader].
+// The headers of the sheet are matched with the given mapping,
+// if it is nil the [DefaultMapping] is used.
+// The empty rows are skipped.
+//
+// The message, CAN ID, signal, start bit and length columns are required.
+func Read(filename string, r io.Reader, format Format, mapping Mapping) ([]*Row, error) {
+	var table [][]string
+	var err error
+
+	switch format {
+	case FormatCSV:
+		table, err = readCSV(r)
+	case FormatXLSX:
+		table, err = readXLSX(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s : %w", filename, err)
+	}
+
+	if mapping == nil {
+		mapping = DefaultMapping()
+	}
+
+	return newReader(filename, mapping).readTable(table)
+}
+
+// readCSV returns the records of the CSV file. Since the empty lines are skipped
+// by the CSV reader, they are added back to keep the index of the records
+// equal to their line in the file.
+func readCSV(r io.Reader) ([][]string, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+
+	table := [][]string{}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		for len(table) < line-1 {
+			table = append(table, []string{})
+		}
+		table = append(table, record)
+	}
+
+	return table, nil
+}
+
+type reader struct {
+	filename string
+	mapping  Mapping
+
+	// columns maps the columns to their index in the sheet
+	columns map[Column]int
+
+	loc   *Location
+	cells []string
+}
+
+func newReader(filename string, mapping Mapping) *reader {
+	return &reader{
+		filename: filename,
+		mapping:  mapping,
+
+		columns: make(map[Column]int),
+	}
+}
+
+func (r *reader) errorf(col Column, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	return fmt.Errorf("syntax error at %s; column %q: %s", r.loc, r.mapping[col], msg)
+}
+
+func (r *reader) readTable(table [][]string) ([]*Row, error) {
+	if len(table) == 0 {
+		return nil, fmt.Errorf("%s : missing the header row", r.filename)
+	}
+
+	headers := make(map[string]int)
+	for idx, header := range table[0] {
+		headers[r.normalizeHeader(header)] = idx
+	}
+
+	for col, header := range r.mapping {
+		if idx, ok := headers[r.normalizeHeader(header)]; ok {
+			r.columns[col] = idx
+		}
+	}
+
+	for _, col := range requiredColumns {
+		if _, ok := r.columns[col]; !ok {
+			return nil, fmt.Errorf("%s : missing column %q (%s)", r.filename, r.mapping[col], col)
+		}
+	}
+
+	rows := []*Row{}
+	for idx, cells := range table[1:] {
+		if r.isEmpty(cells) {
+			continue
+		}
+
+		r.loc = &Location{Filename: r.filename, Row: idx + 2}
+		r.cells = cells
+
+		row, err := r.readRow()
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (r *reader) normalizeHeader(header string) string {
+	return strings.ToLower(strings.TrimSpace(header))
+}
+
+func (r *reader) isEmpty(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *reader) cell(col Column) string {
+	idx, ok := r.columns[col]
+	if !ok || idx >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[idx])
+}
+
+func (r *reader) readRow() (*Row, error) {
+	row := &Row{
+		loc: r.loc,
+
+		Bus:         r.cell(ColumnBus),
+		Transmitter: r.cell(ColumnTransmitter),
+		Receivers:   r.splitList(r.cell(ColumnReceivers), ",;"),
+		Message:     r.cell(ColumnMessage),
+		Signal:      r.cell(ColumnSignal),
+		Unit:        r.cell(ColumnUnit),
+		Muxor:       r.cell(ColumnMuxor),
+		Comment:     r.cell(ColumnComment),
+	}
+
+	if row.Message == "" {
+		return nil, r.errorf(ColumnMessage, "missing message name")
+	}
+
+	if row.Signal == "" {
+		return nil, r.errorf(ColumnSignal, "missing signal name")
+	}
+
+	var err error
+
+	canID, err := r.readInt(ColumnCANID, true)
+	if err != nil {
+		return nil, err
+	}
+	if canID < 0 || canID > math.MaxUint32 {
+		return nil, r.errorf(ColumnCANID, "invalid CAN ID: %d", canID)
+	}
+	row.CANID = uint32(canID)
+
+	switch idFormat := strings.ToLower(r.cell(ColumnIDFormat)); idFormat {
+	case "":
+		row.Extended = row.CANID > 0x7ff
+	case "standard", "std":
+	case "extended", "ext", "xtd":
+		row.Extended = true
+	default:
+		return nil, r.errorf(ColumnIDFormat, "invalid ID format: %q", idFormat)
+	}
+
+	if row.MessageSize, err = r.readInt(ColumnMessageSize, false); err != nil {
+		return nil, err
+	}
+
+	if row.CycleTime, err = r.readInt(ColumnCycleTime, false); err != nil {
+		return nil, err
+	}
+
+	if row.StartBit, err = r.readInt(ColumnStartBit, true); err != nil {
+		return nil, err
+	}
+
+	if row.Length, err = r.readInt(ColumnLength, true); err != nil {
+		return nil, err
+	}
+
+	switch byteOrder := strings.ToLower(r.cell(ColumnByteOrder)); byteOrder {
+	case "", "intel", "little_endian", "little endian", "le":
+		row.ByteOrder = ByteOrderIntel
+	case "motorola", "big_endian", "big endian", "be":
+		row.ByteOrder = ByteOrderMotorola
+	default:
+		return nil, r.errorf(ColumnByteOrder, "invalid byte order: %q", byteOrder)
+	}
+
+	switch valueType := strings.ToLower(r.cell(ColumnValueType)); valueType {
+	case "", "unsigned":
+		row.ValueType = ValueTypeUnsigned
+	case "signed":
+		row.ValueType = ValueTypeSigned
+	case "float", "double":
+		row.ValueType = ValueTypeFloat
+	case "muxor", "multiplexor":
+		row.ValueType = ValueTypeMuxor
+	default:
+		return nil, r.errorf(ColumnValueType, "invalid value type: %q", valueType)
+	}
+
+	row.Factor = 1
+	if factor, err := r.readFloat(ColumnFactor); err != nil {
+		return nil, err
+	} else if factor != nil {
+		row.Factor = *factor
+	}
+
+	if offset, err := r.readFloat(ColumnOffset); err != nil {
+		return nil, err
+	} else if offset != nil {
+		row.Offset = *offset
+	}
+
+	if row.Min, err = r.readFloat(ColumnMin); err != nil {
+		return nil, err
+	}
+
+	if row.Max, err = r.readFloat(ColumnMax); err != nil {
+		return nil, err
+	}
+
+	if row.ValueTable, err = r.readValueTable(); err != nil {
+		return nil, err
+	}
+
+	for _, muxValue := range r.splitList(r.cell(ColumnMuxValues), ",;") {
+		val, err := r.parseInt(muxValue)
+		if err != nil {
+			return nil, r.errorf(ColumnMuxValues, "invalid value: %q", muxValue)
+		}
+		row.MuxValues = append(row.MuxValues, val)
+	}
+
+	return row, nil
+}
+
+func (r *reader) splitList(cell string, separators string) []string {
+	items := []string{}
+	for _, item := range strings.FieldsFunc(cell, func(r rune) bool { return strings.ContainsRune(separators, r) }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	return items
+}
+
+// parseInt parses an integer in decimal or in hexadecimal with the 0x prefix.
+// Since the spreadsheets store the numbers as floats, an integer float is also accepted.
+func (r *reader) parseInt(val string) (int, error) {
+	if intVal, err := strconv.ParseInt(val, 0, 64); err == nil {
+		return int(intVal), nil
+	}
+
+	floatVal, err := strconv.ParseFloat(val, 64)
+	if err != nil || floatVal != math.Trunc(floatVal) {
+		return 0, strconv.ErrSyntax
+	}
+
+	return int(floatVal), nil
+}
+
+func (r *reader) readInt(col Column, required bool) (int, error) {
+	cell := r.cell(col)
+	if cell == "" {
+		if required {
+			return 0, r.errorf(col, "missing value")
+		}
+		return 0, nil
+	}
+
+	val, err := r.parseInt(cell)
+	if err != nil {
+		return 0, r.errorf(col, "invalid integer: %q", cell)
+	}
+
+	return val, nil
+}
+
+func (r *reader) readFloat(col Column) (*float64, error) {
+	cell := r.cell(col)
+	if cell == "" {
+		return nil, nil
+	}
+
+	val, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return nil, r.errorf(col, "invalid number: %q", cell)
+	}
+
+	return &val, nil
+}
+
+func (r *reader) readValueTable() ([]*Value, error) {
+	values := []*Value{}
+
+	for _, item := range r.splitList(r.cell(ColumnValueTable), ";\n") {
+		indexStr, name, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, r.errorf(ColumnValueTable, "invalid value: %q", item)
+		}
+
+		index, err := r.parseInt(strings.TrimSpace(indexStr))
+		if err != nil {
+			return nil, r.errorf(ColumnValueTable, "invalid value index: %q", item)
+		}
+
+		values = append(values, &Value{Index: index, Name: strings.TrimSpace(name)})
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	return values, nil
+}
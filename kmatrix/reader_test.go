@@ -0,0 +1,102 @@
+package kmatrix
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Read(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.csv")
+	assert.NoError(err)
+	defer file.Close()
+
+	rows, err := Read("sample.csv", file, FormatCSV, nil)
+	assert.NoError(err)
+	assert.Len(rows, 9)
+
+	rpm := rows[0]
+	assert.Equal("powertrain", rpm.Bus)
+	assert.Equal("ECU", rpm.Transmitter)
+	assert.Equal([]string{"Dashboard", "Logger"}, rpm.Receivers)
+	assert.Equal("Engine", rpm.Message)
+	assert.Equal(uint32(0x100), rpm.CANID)
+	assert.False(rpm.Extended)
+	assert.Equal(8, rpm.MessageSize)
+	assert.Equal(100, rpm.CycleTime)
+	assert.Equal("rpm", rpm.Signal)
+	assert.Equal(16, rpm.Length)
+	assert.Equal(ByteOrderIntel, rpm.ByteOrder)
+	assert.Equal(ValueTypeUnsigned, rpm.ValueType)
+	assert.Equal(0.25, rpm.Factor)
+	assert.Equal(8000.0, *rpm.Max)
+	assert.Equal("rpm", rpm.Unit)
+	assert.Equal("Engine speed", rpm.Comment)
+	assert.Equal(2, rpm.Location().Row)
+
+	temperature := rows[1]
+	assert.Equal(ValueTypeSigned, temperature.ValueType)
+	assert.Equal(-40.0, temperature.Offset)
+	assert.Nil(temperature.Min)
+	assert.Nil(temperature.Max)
+
+	gear := rows[2]
+	assert.Len(gear.ValueTable, 4)
+	assert.Equal(&Value{Index: 3, Name: "Drive"}, gear.ValueTable[3])
+
+	pressure := rows[3]
+	assert.Equal(ByteOrderMotorola, pressure.ByteOrder)
+	assert.Equal(39, pressure.StartBit)
+
+	// should skip the empty row
+	page := rows[4]
+	assert.Equal(7, page.Location().Row)
+	assert.Equal(uint32(0x1ABCDEF), page.CANID)
+	assert.True(page.Extended)
+	assert.Equal(ValueTypeMuxor, page.ValueType)
+	assert.Equal(1.0, page.Factor)
+
+	errorCode := rows[8]
+	assert.Equal("page", errorCode.Muxor)
+	assert.Equal([]int{1, 2}, errorCode.MuxValues)
+}
+
+func Test_Read_mapping(t *testing.T) {
+	assert := assert.New(t)
+
+	mapping := Mapping{
+		ColumnMessage:  "Frame",
+		ColumnCANID:    "Identifier",
+		ColumnSignal:   "Signal Name",
+		ColumnStartBit: "Bit",
+		ColumnLength:   "Size",
+	}
+
+	content := "FRAME , identifier,Signal Name,Bit,Size,Other\nStatus,1000,active,0,1,ignored\n"
+	rows, err := Read("mapping.csv", strings.NewReader(content), FormatCSV, mapping)
+	assert.NoError(err)
+	assert.Len(rows, 1)
+
+	// should use the defaults for the missing columns
+	row := rows[0]
+	assert.Equal("Status", row.Message)
+	assert.Equal(uint32(1000), row.CANID)
+	assert.Equal("active", row.Signal)
+	assert.Equal(1, row.Length)
+	assert.Equal(ByteOrderIntel, row.ByteOrder)
+	assert.Equal(ValueTypeUnsigned, row.ValueType)
+	assert.Equal(1.0, row.Factor)
+
+	// should return an error if a required column is missing
+	_, err = Read("mapping.csv", strings.NewReader("Frame,Identifier\nStatus,1000\n"), FormatCSV, mapping)
+	assert.ErrorContains(err, `missing column "Signal Name"`)
+
+	// should return an error with the position of an invalid cell
+	content = "Frame,Identifier,Signal Name,Bit,Size\nStatus,1000,active,first,1\n"
+	_, err = Read("mapping.csv", strings.NewReader(content), FormatCSV, mapping)
+	assert.EqualError(err, `syntax error at mapping.csv:2; column "Bit": invalid integer: "first"`)
+}
@@ -0,0 +1,222 @@
+package kmatrix
+
+import "fmt"
+
+// Location is the position in the sheet of a row.
+type Location struct {
+	Filename string
+	Row      int
+}
+
+func (l *Location) String() string {
+	return fmt.Sprintf("%s:%d", l.Filename, l.Row)
+}
+
+// Column identifies a field of a [Row].
+type Column int
+
+const (
+	// ColumnBus is the name of the bus.
+	ColumnBus Column = iota
+	// ColumnTransmitter is the name of the node that sends the message.
+	ColumnTransmitter
+	// ColumnReceivers is the comma separated list of the nodes that receive the message.
+	ColumnReceivers
+	// ColumnMessage is the name of the message.
+	ColumnMessage
+	// ColumnCANID is the CAN-ID of the message, in decimal or in hexadecimal with the 0x prefix.
+	ColumnCANID
+	// ColumnIDFormat is the format of the CAN-ID, standard or extended.
+	ColumnIDFormat
+	// ColumnMessageSize is the size of the message in bytes.
+	ColumnMessageSize
+	// ColumnCycleTime is the cycle time of the message in milliseconds.
+	ColumnCycleTime
+	// ColumnSignal is the name of the signal.
+	ColumnSignal
+	// ColumnStartBit is the start bit of the signal. For Motorola signals it is
+	// the position of the most significant bit, like in the DBC files.
+	ColumnStartBit
+	// ColumnLength is the size of the signal in bits.
+	ColumnLength
+	// ColumnByteOrder is the byte order of the signal, Intel or Motorola.
+	ColumnByteOrder
+	// ColumnValueType is the type of the raw value of the signal.
+	ColumnValueType
+	// ColumnFactor is the factor of the physical value.
+	ColumnFactor
+	// ColumnOffset is the offset of the physical value.
+	ColumnOffset
+	// ColumnMin is the minimum physical value.
+	ColumnMin
+	// ColumnMax is the maximum physical value.
+	ColumnMax
+	// ColumnUnit is the unit of the physical value.
+	ColumnUnit
+	// ColumnValueTable is the semicolon separated list of the values of the signal, like "0=Off;1=On".
+	ColumnValueTable
+	// ColumnMuxor is the name of the multiplexor signal that selects the signal.
+	ColumnMuxor
+	// ColumnMuxValues is the comma separated list of the values of the multiplexor
+	// that select the signal.
+	ColumnMuxValues
+	// ColumnComment is the description of the signal.
+	ColumnComment
+)
+
+func (c Column) String() string {
+	switch c {
+	case ColumnBus:
+		return "bus"
+	case ColumnTransmitter:
+		return "transmitter"
+	case ColumnReceivers:
+		return "receivers"
+	case ColumnMessage:
+		return "message"
+	case ColumnCANID:
+		return "can_id"
+	case ColumnIDFormat:
+		return "id_format"
+	case ColumnMessageSize:
+		return "message_size"
+	case ColumnCycleTime:
+		return "cycle_time"
+	case ColumnSignal:
+		return "signal"
+	case ColumnStartBit:
+		return "start_bit"
+	case ColumnLength:
+		return "length"
+	case ColumnByteOrder:
+		return "byte_order"
+	case ColumnValueType:
+		return "value_type"
+	case ColumnFactor:
+		return "factor"
+	case ColumnOffset:
+		return "offset"
+	case ColumnMin:
+		return "min"
+	case ColumnMax:
+		return "max"
+	case ColumnUnit:
+		return "unit"
+	case ColumnValueTable:
+		return "value_table"
+	case ColumnMuxor:
+		return "muxor"
+	case ColumnMuxValues:
+		return "mux_values"
+	case ColumnComment:
+		return "comment"
+	default:
+		return "unknown"
+	}
+}
+
+// requiredColumns are the columns that must be mapped to read a sheet.
+var requiredColumns = []Column{ColumnMessage, ColumnCANID, ColumnSignal, ColumnStartBit, ColumnLength}
+
+// Mapping maps the columns of a [Row] to the headers of the sheet.
+// The headers are matched ignoring the case and the surrounding spaces.
+// The columns are written in the order of the [Column] constants.
+type Mapping map[Column]string
+
+// DefaultMapping returns the mapping with the default header of every column.
+func DefaultMapping() Mapping {
+	return Mapping{
+		ColumnBus:         "Bus",
+		ColumnTransmitter: "Transmitter",
+		ColumnReceivers:   "Receivers",
+		ColumnMessage:     "Message",
+		ColumnCANID:       "CAN ID",
+		ColumnIDFormat:    "ID Format",
+		ColumnMessageSize: "DLC [byte]",
+		ColumnCycleTime:   "Cycle Time [ms]",
+		ColumnSignal:      "Signal",
+		ColumnStartBit:    "Start Bit",
+		ColumnLength:      "Length [bit]",
+		ColumnByteOrder:   "Byte Order",
+		ColumnValueType:   "Value Type",
+		ColumnFactor:      "Factor",
+		ColumnOffset:      "Offset",
+		ColumnMin:         "Minimum",
+		ColumnMax:         "Maximum",
+		ColumnUnit:        "Unit",
+		ColumnValueTable:  "Value Table",
+		ColumnMuxor:       "Multiplexor",
+		ColumnMuxValues:   "Multiplex Values",
+		ColumnComment:     "Comment",
+	}
+}
+
+// ByteOrder is the byte order of a signal.
+type ByteOrder string
+
+const (
+	// ByteOrderIntel defines a little endian signal.
+	ByteOrderIntel ByteOrder = "Intel"
+	// ByteOrderMotorola defines a big endian signal.
+	ByteOrderMotorola ByteOrder = "Motorola"
+)
+
+// ValueType is the type of the raw value of a signal.
+type ValueType string
+
+const (
+	// ValueTypeUnsigned defines an unsigned integer.
+	ValueTypeUnsigned ValueType = "unsigned"
+	// ValueTypeSigned defines a signed integer in two's complement.
+	ValueTypeSigned ValueType = "signed"
+	// ValueTypeFloat defines an IEEE 754 floating point number of 32 or 64 bits.
+	ValueTypeFloat ValueType = "float"
+	// ValueTypeMuxor defines a multiplexor signal, the number of its values
+	// is given by the length of the signal.
+	ValueTypeMuxor ValueType = "muxor"
+)
+
+// Value is an entry of the value table of a signal.
+type Value struct {
+	Index int
+	Name  string
+}
+
+// Row is a row of the sheet, that describes a signal and the message that carries it.
+// The fields of the message are repeated in all the rows of its signals.
+type Row struct {
+	loc *Location
+
+	Bus         string
+	Transmitter string
+	Receivers   []string
+
+	Message     string
+	CANID       uint32
+	Extended    bool
+	MessageSize int
+	CycleTime   int
+
+	Signal     string
+	StartBit   int
+	Length     int
+	ByteOrder  ByteOrder
+	ValueType  ValueType
+	Factor     float64
+	Offset     float64
+	Min        *float64
+	Max        *float64
+	Unit       string
+	ValueTable []*Value
+
+	Muxor     string
+	MuxValues []int
+
+	Comment string
+}
+
+// Location returns the position of the row in the sheet.
+// It is nil if the row is not read from a sheet.
+func (r *Row) Location() *Location {
+	return r.loc
+}
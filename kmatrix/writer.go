@@ -0,0 +1,131 @@
+package kmatrix
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Write writes the given rows as a sheet in the given format into the [io.Writer].
+// Only the columns of the mapping are written, in the order of the [Column] constants.
+// If the mapping is nil the [DefaultMapping] is used.
+func Write(w io.Writer, rows []*Row, format Format, mapping Mapping) error {
+	if mapping == nil {
+		mapping = DefaultMapping()
+	}
+
+	columns := []Column{}
+	for col := range mapping {
+		columns = append(columns, col)
+	}
+	slices.Sort(columns)
+
+	header := make([]string, 0, len(columns))
+	for _, col := range columns {
+		header = append(header, mapping[col])
+	}
+
+	table := [][]string{header}
+	for _, row := range rows {
+		cells := make([]string, 0, len(columns))
+		for _, col := range columns {
+			cells = append(cells, formatCell(row, col))
+		}
+		table = append(table, cells)
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, table)
+	case FormatXLSX:
+		return writeXLSX(w, table)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeCSV(w io.Writer, table [][]string) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.WriteAll(table); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
+
+func formatFloat(val float64) string {
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+func formatCell(row *Row, col Column) string {
+	switch col {
+	case ColumnBus:
+		return row.Bus
+	case ColumnTransmitter:
+		return row.Transmitter
+	case ColumnReceivers:
+		return strings.Join(row.Receivers, ", ")
+	case ColumnMessage:
+		return row.Message
+	case ColumnCANID:
+		return fmt.Sprintf("0x%X", row.CANID)
+	case ColumnIDFormat:
+		if row.Extended {
+			return "extended"
+		}
+		return "standard"
+	case ColumnMessageSize:
+		return strconv.Itoa(row.MessageSize)
+	case ColumnCycleTime:
+		if row.CycleTime == 0 {
+			return ""
+		}
+		return strconv.Itoa(row.CycleTime)
+	case ColumnSignal:
+		return row.Signal
+	case ColumnStartBit:
+		return strconv.Itoa(row.StartBit)
+	case ColumnLength:
+		return strconv.Itoa(row.Length)
+	case ColumnByteOrder:
+		return string(row.ByteOrder)
+	case ColumnValueType:
+		return string(row.ValueType)
+	case ColumnFactor:
+		return formatFloat(row.Factor)
+	case ColumnOffset:
+		return formatFloat(row.Offset)
+	case ColumnMin:
+		if row.Min == nil {
+			return ""
+		}
+		return formatFloat(*row.Min)
+	case ColumnMax:
+		if row.Max == nil {
+			return ""
+		}
+		return formatFloat(*row.Max)
+	case ColumnUnit:
+		return row.Unit
+	case ColumnValueTable:
+		values := make([]string, 0, len(row.ValueTable))
+		for _, val := range row.ValueTable {
+			values = append(values, fmt.Sprintf("%d=%s", val.Index, val.Name))
+		}
+		return strings.Join(values, ";")
+	case ColumnMuxor:
+		return row.Muxor
+	case ColumnMuxValues:
+		values := make([]string, 0, len(row.MuxValues))
+		for _, val := range row.MuxValues {
+			values = append(values, strconv.Itoa(val))
+		}
+		return strings.Join(values, ",")
+	case ColumnComment:
+		return row.Comment
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,46 @@
+package kmatrix
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Write(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.csv")
+	assert.NoError(err)
+	defer file.Close()
+
+	rows, err := Read("sample.csv", file, FormatCSV, nil)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(Write(buf, rows, FormatCSV, nil))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.True(strings.HasPrefix(lines[0], "Bus,Transmitter,Receivers,Message,CAN ID,"))
+	assert.Equal(`powertrain,ECU,"Dashboard, Logger",Engine,0x100,standard,8,100,rpm,0,16,Intel,unsigned,0.25,0,0,8000,rpm,,,,Engine speed`, lines[1])
+	assert.Equal(`powertrain,ECU,Logger,Diagnostic,0x1ABCDEF,extended,8,1000,error_code,40,8,Intel,unsigned,1,0,,,,,page,"1,2",`, lines[9])
+
+	// should read the same rows
+	expRows, err := Read("sample.csv", bytes.NewReader(buf.Bytes()), FormatCSV, nil)
+	assert.NoError(err)
+	assert.Len(expRows, len(rows))
+	for idx, row := range rows {
+		expRow := expRows[idx]
+		row.loc = nil
+		expRow.loc = nil
+		assert.Equal(row, expRow)
+	}
+
+	// should write only the mapped columns
+	buf.Reset()
+	mapping := Mapping{ColumnSignal: "Name", ColumnMessage: "Frame"}
+	assert.NoError(Write(buf, rows[:1], FormatCSV, mapping))
+	assert.Equal("Frame,Name\nEngine,rpm\n", buf.String())
+}
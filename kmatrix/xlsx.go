@@ -0,0 +1,356 @@
+package kmatrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// The XLSX files are read and written with only the parts needed by a single sheet of text:
+// the workbook, its relationships, the shared strings and the first worksheet.
+
+const (
+	xlsxWorkbookPath     = "xl/workbook.xml"
+	xlsxWorkbookRelsPath = "xl/_rels/workbook.xml.rels"
+	xlsxSharedStrPath    = "xl/sharedStrings.xml"
+	xlsxSheetPath        = "xl/worksheets/sheet1.xml"
+)
+
+// The limits of a worksheet, the last cell is XFD1048576.
+const (
+	xlsxMaxColumns = 16384
+	xlsxMaxRows    = 1048576
+)
+
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (xt *xlsxText) String() string {
+	if len(xt.Runs) == 0 {
+		return xt.Text
+	}
+
+	var b strings.Builder
+	for _, run := range xt.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []*xlsxText `xml:"si"`
+}
+
+type xlsxCell struct {
+	Ref       string    `xml:"r,attr"`
+	Type      string    `xml:"t,attr"`
+	Value     string    `xml:"v"`
+	InlineStr *xlsxText `xml:"is"`
+}
+
+type xlsxRow struct {
+	Index int         `xml:"r,attr"`
+	Cells []*xlsxCell `xml:"c"`
+}
+
+type xlsxWorksheet struct {
+	Rows []*xlsxRow `xml:"sheetData>row"`
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+func readZipXML(files map[string]*zip.File, name string, v any) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("missing %q", name)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return xml.NewDecoder(f).Decode(v)
+}
+
+// readXLSX returns the cells of the first worksheet of the workbook.
+func readXLSX(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File)
+	for _, file := range zipReader.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := getXLSXFirstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedStrings := new(xlsxSharedStrings)
+	if _, ok := files[xlsxSharedStrPath]; ok {
+		if err := readZipXML(files, xlsxSharedStrPath, sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet := new(xlsxWorksheet)
+	if err := readZipXML(files, sheetPath, sheet); err != nil {
+		return nil, err
+	}
+
+	table := [][]string{}
+	for _, row := range sheet.Rows {
+		// the rows without cells can be omitted
+		rowIdx := len(table)
+		if row.Index > 0 {
+			rowIdx = row.Index - 1
+		}
+		if rowIdx >= xlsxMaxRows {
+			return nil, fmt.Errorf("invalid row index %d", rowIdx+1)
+		}
+		for len(table) <= rowIdx {
+			table = append(table, []string{})
+		}
+
+		cells := table[rowIdx]
+		for _, cell := range row.Cells {
+			colIdx := len(cells)
+			if cell.Ref != "" {
+				colIdx, err = parseXLSXColumn(cell.Ref)
+				if err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= colIdx {
+				cells = append(cells, "")
+			}
+
+			value, err := getXLSXCellValue(cell, sharedStrings)
+			if err != nil {
+				return nil, fmt.Errorf("cell %s : %w", cell.Ref, err)
+			}
+			cells[colIdx] = value
+		}
+		table[rowIdx] = cells
+	}
+
+	return table, nil
+}
+
+func getXLSXFirstSheetPath(files map[string]*zip.File) (string, error) {
+	workbook := new(xlsxWorkbook)
+	if err := readZipXML(files, xlsxWorkbookPath, workbook); err != nil {
+		return "", err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("missing worksheet")
+	}
+
+	rels := new(xlsxRelationships)
+	if err := readZipXML(files, xlsxWorkbookRelsPath, rels); err != nil {
+		return "", err
+	}
+
+	relID := workbook.Sheets[0].RelID
+	for _, rel := range rels.Relationships {
+		if rel.ID != relID {
+			continue
+		}
+
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", fmt.Errorf("missing relationship %q", relID)
+}
+
+func getXLSXCellValue(cell *xlsxCell, sharedStrings *xlsxSharedStrings) (string, error) {
+	switch cell.Type {
+	case "s":
+		idx, err := strconv.Atoi(cell.Value)
+		if err != nil || idx < 0 || idx >= len(sharedStrings.Items) {
+			return "", fmt.Errorf("invalid shared string %q", cell.Value)
+		}
+		return sharedStrings.Items[idx].String(), nil
+
+	case "inlineStr":
+		if cell.InlineStr == nil {
+			return "", nil
+		}
+		return cell.InlineStr.String(), nil
+
+	case "b":
+		if cell.Value == "1" {
+			return "true", nil
+		}
+		return "false", nil
+
+	default:
+		return cell.Value, nil
+	}
+}
+
+// parseXLSXColumn returns the index of the column of a cell reference like "AB12".
+// It returns an error if the column is after the last one of a worksheet (XFD).
+func parseXLSXColumn(ref string) (int, error) {
+	col := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		letters++
+
+		if col > xlsxMaxColumns {
+			return 0, fmt.Errorf("invalid cell reference %q", ref)
+		}
+	}
+
+	if letters == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+
+	return col - 1, nil
+}
+
+func formatXLSXColumn(idx int) string {
+	name := ""
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		name = string(rune('A'+(idx-1)%26)) + name
+	}
+	return name
+}
+
+func isXLSXNumber(val string) bool {
+	if val == "" || strings.ContainsAny(val, "xXeE") {
+		return false
+	}
+	_, err := strconv.ParseFloat(val, 64)
+	return err == nil
+}
+
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: xlsxWorkbookPath,
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="K-Matrix" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: xlsxWorkbookRelsPath,
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+// writeXLSX writes the cells into the first worksheet of a new workbook.
+// The numbers are written as numeric cells and the other values as inline strings.
+func writeXLSX(w io.Writer, table [][]string) error {
+	zipWriter := zip.NewWriter(w)
+
+	for _, part := range xlsxStaticParts {
+		f, err := zipWriter.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := zipWriter.CreateHeader(&zip.FileHeader{Name: xlsxSheetPath, Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for rowIdx, row := range table {
+		fmt.Fprintf(&b, `<row r="%d">`, rowIdx+1)
+
+		for colIdx, val := range row {
+			if val == "" {
+				continue
+			}
+
+			ref := formatXLSXColumn(colIdx) + strconv.Itoa(rowIdx+1)
+			if isXLSXNumber(val) {
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, val)
+				continue
+			}
+
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(&b, []byte(val)); err != nil {
+				return err
+			}
+			b.WriteString(`</t></is></c>`)
+		}
+
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+
+	if _, err := b.WriteTo(f); err != nil {
+		return err
+	}
+
+	return zipWriter.Close()
+}
@@ -0,0 +1,147 @@
+package kmatrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_writeXLSX(t *testing.T) {
+	assert := assert.New(t)
+
+	table := [][]string{
+		{"Name", "Value", "Note"},
+		{"a < b", "12.5", ""},
+		{"0x10", "", "  spaced  "},
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(writeXLSX(buf, table))
+
+	// should be deterministic
+	otherBuf := new(bytes.Buffer)
+	assert.NoError(writeXLSX(otherBuf, table))
+	assert.Equal(buf.Bytes(), otherBuf.Bytes())
+
+	// should read the same cells, without the trailing empty cells
+	res, err := readXLSX(bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal([][]string{
+		{"Name", "Value", "Note"},
+		{"a < b", "12.5"},
+		{"0x10", "", "  spaced  "},
+	}, res)
+}
+
+func Test_readXLSX(t *testing.T) {
+	assert := assert.New(t)
+
+	// a workbook with shared strings, rich text, booleans and missing rows
+	// like the ones saved by the spreadsheet applications
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Matrix" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/matrix.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Signal</t></si><si><r><t>Start </t></r><r><t>Bit</t></r></si><si><t>rpm</t></si></sst>`,
+		"xl/worksheets/matrix.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" t="b"><v>1</v></c><c r="C3"><v>16</v></c></row>
+</sheetData></worksheet>`,
+	}
+
+	res, err := readXLSX(newTestXLSX(assert, parts))
+	assert.NoError(err)
+	assert.Equal([][]string{
+		{"Signal", "", "Start Bit"},
+		{},
+		{"rpm", "true", "16"},
+	}, res)
+
+	// should return an error if the file is not a workbook
+	_, err = readXLSX(bytes.NewReader([]byte("Signal,Start Bit")))
+	assert.Error(err)
+
+	// should return an error if a cell is outside the worksheet
+	for _, sheetData := range []string{
+		`<row r="1"><c r="XFE1"><v>1</v></c></row>`,
+		`<row r="1"><c r="ZZZZZZZZZZZZZZZZ1"><v>1</v></c></row>`,
+		`<row r="1048577"><c r="A1048577"><v>1</v></c></row>`,
+		`<row r="9223372036854775807"><c r="A1"><v>1</v></c></row>`,
+	} {
+		parts["xl/worksheets/matrix.xml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + sheetData + `</sheetData></worksheet>`
+
+		_, err = readXLSX(newTestXLSX(assert, parts))
+		assert.Error(err)
+	}
+}
+
+// newTestXLSX returns a workbook with the given parts.
+func newTestXLSX(assert *assert.Assertions, parts map[string]string) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, name := range []string{"xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/sharedStrings.xml", "xl/worksheets/matrix.xml"} {
+		f, err := zipWriter.Create(name)
+		assert.NoError(err)
+		_, err = f.Write([]byte(parts[name]))
+		assert.NoError(err)
+	}
+	assert.NoError(zipWriter.Close())
+	return buf
+}
+
+func Test_Read_xlsx(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.csv")
+	assert.NoError(err)
+	defer file.Close()
+
+	rows, err := Read("sample.csv", file, FormatCSV, nil)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(Write(buf, rows, FormatXLSX, nil))
+
+	expRows, err := Read("sample.xlsx", buf, FormatXLSX, nil)
+	assert.NoError(err)
+	assert.Len(expRows, len(rows))
+	for idx, row := range rows {
+		expRow := expRows[idx]
+		row.loc = nil
+		expRow.loc = nil
+		assert.Equal(row, expRow)
+	}
+}
+
+func Test_parseXLSXColumn(t *testing.T) {
+	assert := assert.New(t)
+
+	for idx, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(name, formatXLSXColumn(idx))
+		col, err := parseXLSXColumn(name + "12")
+		assert.NoError(err)
+		assert.Equal(idx, col)
+	}
+
+	_, err := parseXLSXColumn("12")
+	assert.Error(err)
+
+	col, err := parseXLSXColumn("XFD1")
+	assert.NoError(err)
+	assert.Equal(xlsxMaxColumns-1, col)
+
+	_, err = parseXLSXColumn("XFE1")
+	assert.Error(err)
+}
@@ -0,0 +1,179 @@
+package acmelib
+
+import (
+	"io"
+	"slices"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/squadracorsepolito/acmelib/kmatrix"
+)
+
+// ExportKMatrix exports the given [Network] to a communication matrix (K-matrix).
+// It writes the content of the result spreadsheet into the [io.Writer].
+// If the options are nil, the file is written as CSV with the [kmatrix.DefaultMapping].
+//
+// The rows are ordered by bus, sender node, message and start position of the signals.
+// Each muxor is followed by the signals of its layouts, that refer to it
+// with the multiplex values of the layouts that contain them. The values are
+// omitted when a signal is present in all the layouts.
+// The messages sent by the placeholder node of the DBC files have no transmitter.
+func ExportKMatrix(w io.Writer, network *Network, opts *KMatrixOptions) error {
+	if opts == nil {
+		opts = new(KMatrixOptions)
+	}
+
+	exp := newKMatrixExporter()
+	return kmatrix.Write(w, exp.exportNetwork(network), opts.Format, opts.Mapping)
+}
+
+type kmatrixExporter struct {
+	rows []*kmatrix.Row
+}
+
+func newKMatrixExporter() *kmatrixExporter {
+	return &kmatrixExporter{
+		rows: []*kmatrix.Row{},
+	}
+}
+
+func (e *kmatrixExporter) exportNetwork(net *Network) []*kmatrix.Row {
+	for _, bus := range net.Buses() {
+		for _, nodeInt := range bus.NodeInterfaces() {
+			for _, msg := range nodeInt.SentMessages() {
+				e.exportMessage(bus, nodeInt, msg)
+			}
+		}
+	}
+
+	return e.rows
+}
+
+func (e *kmatrixExporter) exportMessage(bus *Bus, sender *NodeInterface, msg *Message) {
+	msgRow := &kmatrix.Row{
+		Bus:         bus.name,
+		Message:     msg.name,
+		CANID:       uint32(msg.GetCANID()),
+		Extended:    msg.canIDFormat == CANIDFormatExtended,
+		MessageSize: msg.sizeByte,
+		CycleTime:   msg.cycleTime,
+	}
+
+	if sender.node.name != dbc.DummyNode {
+		msgRow.Transmitter = sender.node.name
+	}
+
+	for _, rec := range msg.Receivers() {
+		msgRow.Receivers = append(msgRow.Receivers, rec.node.name)
+	}
+
+	for _, sig := range msg.layout.Signals() {
+		e.rows = append(e.rows, e.exportSignal(msgRow, sig))
+
+		if sig.Kind() != SignalKindMuxor {
+			continue
+		}
+
+		if muxLayer, ok := msg.layout.muxLayers.Get(sig.EntityID()); ok {
+			e.exportMultiplexedLayer(msgRow, muxLayer)
+		}
+	}
+}
+
+func (e *kmatrixExporter) exportMultiplexedLayer(msgRow *kmatrix.Row, muxLayer *MultiplexedLayer) {
+	muxor := muxLayer.muxor
+
+	// the signals present in more layouts are exported once
+	exported := make(map[EntityID]bool)
+	for layoutID, layout := range muxLayer.iterLayouts() {
+		for _, sig := range layout.Signals() {
+			if exported[sig.EntityID()] {
+				continue
+			}
+			exported[sig.EntityID()] = true
+
+			// the nested muxors are only present in the layout of their layer
+			if sig.Kind() == SignalKindMuxor {
+				if nestedLayer, ok := layout.muxLayers.Get(sig.EntityID()); ok {
+					row := e.exportSignal(msgRow, sig)
+					row.Muxor = muxor.name
+					row.MuxValues = []int{layoutID}
+					e.rows = append(e.rows, row)
+
+					e.exportMultiplexedLayer(msgRow, nestedLayer)
+					continue
+				}
+			}
+
+			row := e.exportSignal(msgRow, sig)
+			row.Muxor = muxor.name
+
+			layoutIDs, ok := muxLayer.singalLayoutIDs.Get(sig.EntityID())
+			if ok && len(layoutIDs) < muxLayer.GetLayoutCount() {
+				row.MuxValues = slices.Sorted(slices.Values(layoutIDs))
+			}
+
+			e.rows = append(e.rows, row)
+		}
+	}
+}
+
+func (e *kmatrixExporter) exportSignal(msgRow *kmatrix.Row, sig Signal) *kmatrix.Row {
+	row := *msgRow
+	row.Signal = sig.Name()
+	row.Comment = sig.Desc()
+	row.Length = sig.Size()
+	row.Factor = 1
+
+	row.StartBit = sig.StartPos()
+	row.ByteOrder = kmatrix.ByteOrderIntel
+	if sig.Endianness() == EndiannessBigEndian {
+		row.StartBit = StartPosFromBigEndian(row.StartBit)
+		row.ByteOrder = kmatrix.ByteOrderMotorola
+	}
+
+	row.ValueType = kmatrix.ValueTypeUnsigned
+
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		typ := stdSig.typ
+		if typ.kind == SignalTypeKindFloat {
+			row.ValueType = kmatrix.ValueTypeFloat
+		} else if typ.signed {
+			row.ValueType = kmatrix.ValueTypeSigned
+		}
+
+		row.Factor = typ.scale
+		row.Offset = typ.offset
+
+		// the limits of the floats are the ones of the size
+		if typ.kind != SignalTypeKindFloat {
+			min, max := typ.min, typ.max
+			row.Min = &min
+			row.Max = &max
+		}
+
+		if stdSig.unit != nil {
+			row.Unit = stdSig.unit.symbol
+		}
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		for _, val := range enumSig.enum.Values() {
+			row.ValueTable = append(row.ValueTable, &kmatrix.Value{Index: val.index, Name: val.name})
+		}
+
+	case SignalKindMuxor:
+		row.ValueType = kmatrix.ValueTypeMuxor
+	}
+
+	return &row
+}
@@ -0,0 +1,77 @@
+package acmelib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib/kmatrix"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportKMatrix(t *testing.T) {
+	assert := assert.New(t)
+
+	net := importKMatrixTestFile(assert)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(ExportKMatrix(buf, net, nil))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Len(lines, 11)
+	assert.Equal(`powertrain,ECU,"Dashboard, Logger",Engine,0x100,standard,8,100,temperature,16,8,Intel,signed,1,-40,-168,87,deg C,,,,`, lines[2])
+	assert.Equal(`powertrain,ECU,"Dashboard, Logger",Engine,0x100,standard,8,100,gear,24,4,Intel,unsigned,1,0,,,,0=Park;1=Reverse;2=Neutral;3=Drive,,,Selected gear`, lines[3])
+	assert.Equal(`powertrain,ECU,"Dashboard, Logger",Engine,0x100,standard,8,100,pressure,39,16,Motorola,unsigned,0.1,0,0,6553.5,kPa,,,,`, lines[4])
+	assert.Equal(`powertrain,ECU,Logger,Diagnostic,0x1ABCDEF,extended,8,1000,page,0,2,Intel,muxor,1,0,,,,,,,Page of the diagnostic data`, lines[5])
+
+	// should list the multiplexed signals after their muxor
+	assert.True(strings.HasPrefix(lines[6], "powertrain,ECU,Logger,Diagnostic,0x1ABCDEF,extended,8,1000,voltage,8,16,"))
+	assert.Equal(`powertrain,ECU,Logger,Diagnostic,0x1ABCDEF,extended,8,1000,current,8,32,Intel,float,1,0,,,A,,page,1,`, lines[7])
+	assert.True(strings.HasSuffix(lines[8], `,page,"1,2",`))
+	assert.True(strings.HasPrefix(lines[9], "powertrain,ECU,Logger,Diagnostic,0x1ABCDEF,extended,8,1000,counter,2,6,"))
+}
+
+func Test_ExportKMatrix_RoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	net := initNetwork(assert).net
+
+	for _, format := range []kmatrix.Format{kmatrix.FormatCSV, kmatrix.FormatXLSX} {
+		t.Run(format.String(), func(t *testing.T) {
+			opts := &KMatrixOptions{Format: format}
+
+			buf := new(bytes.Buffer)
+			assert.NoError(ExportKMatrix(buf, net, opts))
+
+			// should import the same network
+			expNet, err := ImportKMatrix("network", bytes.NewReader(buf.Bytes()), opts)
+			assert.NoError(err)
+
+			bus := net.Buses()[0]
+			expBus := expNet.Buses()[0]
+			assert.Equal(bus.Name(), expBus.Name())
+
+			nodeInts := bus.NodeInterfaces()
+			expNodeInts := expBus.NodeInterfaces()
+			assert.Len(expNodeInts, len(nodeInts))
+
+			msgs := nodeInts[0].SentMessages()
+			expMsgs := expNodeInts[0].SentMessages()
+			assert.Len(expMsgs, len(msgs))
+
+			for idx, msg := range msgs {
+				expMsg := expMsgs[idx]
+				assert.Equal(msg.Name(), expMsg.Name())
+				assert.Equal(msg.GetCANID(), expMsg.GetCANID())
+				assert.Equal(msg.SizeByte(), expMsg.SizeByte())
+				assert.Len(expMsg.Receivers(), len(msg.Receivers()))
+				assertEqualKCDLayouts(assert, msg.SignalLayout(), expMsg.SignalLayout())
+			}
+
+			// should write the same file
+			expBuf := new(bytes.Buffer)
+			assert.NoError(ExportKMatrix(expBuf, expNet, opts))
+			assert.Equal(buf.Bytes(), expBuf.Bytes())
+		})
+	}
+}
@@ -0,0 +1,440 @@
+package acmelib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/squadracorsepolito/acmelib/kmatrix"
+)
+
+// KMatrixOptions are the options used to import and export a K-matrix.
+// The zero value describes a CSV file with the [kmatrix.DefaultMapping].
+type KMatrixOptions struct {
+	// Format is the format of the spreadsheet.
+	Format kmatrix.Format
+	// Mapping maps the columns to the headers of the spreadsheet.
+	// If it is nil, the [kmatrix.DefaultMapping] is used.
+	Mapping kmatrix.Mapping
+}
+
+// ImportKMatrix imports a communication matrix (K-matrix) passed as [io.Reader]
+// and converts it to a [Network]. The given filename will be used as the name of the network,
+// and of the buses when the bus column is empty or missing.
+// If the options are nil, the file is read as CSV with the [kmatrix.DefaultMapping].
+//
+// Each row of the sheet describes a signal, the rows with the same bus and message name
+// are the signals of the same message, whose properties are taken from the first row.
+// The rows without a transmitter are sent by the placeholder node used by the DBC files.
+// A row with the muxor value type adds a [MultiplexedLayer] to the message, and the
+// rows that refer to its name are inserted into the layouts of the multiplex values,
+// or into all the layouts if the values are missing. A muxor that refers to another
+// muxor must have exactly one multiplex value, the layout that contains the nested layer.
+//
+// The errors returned by the model are wrapped with the location of the row.
+func ImportKMatrix(filename string, r io.Reader, opts *KMatrixOptions) (*Network, error) {
+	if opts == nil {
+		opts = new(KMatrixOptions)
+	}
+
+	rows, err := kmatrix.Read(filename, r, opts.Format, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	importer := newKMatrixImporter()
+	return importer.importRows(filename, rows)
+}
+
+type kmatrixMessage struct {
+	bus  *Bus
+	rows []*kmatrix.Row
+}
+
+type kmatrixImporter struct {
+	nodes      map[string]*Node
+	nextNodeID NodeID
+
+	flagSigType *SignalType
+	signalTypes map[string]*SignalType
+	signalUnits map[string]*SignalUnit
+}
+
+func newKMatrixImporter() *kmatrixImporter {
+	return &kmatrixImporter{
+		nodes: make(map[string]*Node),
+
+		flagSigType: NewFlagSignalType("flag_t"),
+		signalTypes: make(map[string]*SignalType),
+		signalUnits: make(map[string]*SignalUnit),
+	}
+}
+
+func (i *kmatrixImporter) errorf(row *kmatrix.Row, err error) error {
+	return fmt.Errorf("%s : %w", row.Location(), err)
+}
+
+func (i *kmatrixImporter) importRows(filename string, rows []*kmatrix.Row) (*Network, error) {
+	net := NewNetwork(filename)
+
+	buses := make(map[string]*Bus)
+	msgs := []*kmatrixMessage{}
+	msgKeys := make(map[string]*kmatrixMessage)
+
+	for _, row := range rows {
+		busName := row.Bus
+		if busName == "" {
+			busName = filename
+		}
+
+		bus, ok := buses[busName]
+		if !ok {
+			bus = NewBus(busName)
+			if err := net.AddBus(bus); err != nil {
+				return nil, i.errorf(row, err)
+			}
+			buses[busName] = bus
+		}
+
+		// the type of the bus limits the size and the format of the messages
+		if row.MessageSize > 8 {
+			bus.SetType(BusTypeCANFD)
+		} else if row.Extended && bus.typ == BusTypeCAN2A {
+			bus.SetType(BusTypeCAN2B)
+		}
+
+		msgKey := busName + "/" + row.Message
+		kMsg, ok := msgKeys[msgKey]
+		if !ok {
+			kMsg = &kmatrixMessage{bus: bus}
+			msgs = append(msgs, kMsg)
+			msgKeys[msgKey] = kMsg
+		}
+		kMsg.rows = append(kMsg.rows, row)
+	}
+
+	for _, kMsg := range msgs {
+		if err := i.importMessage(kMsg.bus, kMsg.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	return net, nil
+}
+
+// getNodeInterface returns the interface of the node with the given name
+// connected to the given bus, and it creates it if needed.
+func (i *kmatrixImporter) getNodeInterface(bus *Bus, name string) (*NodeInterface, error) {
+	if name == "" {
+		name = dbc.DummyNode
+	}
+
+	node, ok := i.nodes[name]
+	if !ok {
+		node = NewNode(name, i.nextNodeID, 0)
+		i.nextNodeID++
+		i.nodes[name] = node
+	}
+
+	for _, nodeInt := range node.interfaces {
+		if nodeInt.parentBus == bus {
+			return nodeInt, nil
+		}
+	}
+
+	node.AddInterface()
+	nodeInt := node.interfaces[len(node.interfaces)-1]
+
+	if err := bus.AddNodeInterface(nodeInt); err != nil {
+		return nil, err
+	}
+
+	return nodeInt, nil
+}
+
+func (i *kmatrixImporter) importMessage(bus *Bus, rows []*kmatrix.Row) error {
+	msgRow := rows[0]
+
+	sizeByte := msgRow.MessageSize
+	if sizeByte == 0 {
+		sizeByte = 8
+	}
+
+	msg := NewMessage(msgRow.Message, MessageID(msgRow.CANID), sizeByte)
+	msg.SetCycleTime(msgRow.CycleTime)
+	if msgRow.CycleTime > 0 {
+		msg.SetSendType(MessageSendTypeCyclic)
+	}
+
+	if msgRow.Extended {
+		if err := msg.SetCANIDFormat(CANIDFormatExtended); err != nil {
+			return i.errorf(msgRow, err)
+		}
+	}
+
+	if err := msg.SetStaticCANID(CANID(msgRow.CANID)); err != nil {
+		return i.errorf(msgRow, err)
+	}
+
+	receivers := []string{}
+	for _, row := range rows {
+		if row.CANID != msgRow.CANID || row.Extended != msgRow.Extended {
+			return i.errorf(row, msg.errorf(newCANIDError(CANID(row.CANID), ErrIsDifferent)))
+		}
+
+		for _, recName := range row.Receivers {
+			if !slices.Contains(receivers, recName) {
+				receivers = append(receivers, recName)
+			}
+		}
+	}
+
+	if err := i.importSignals(msg, rows); err != nil {
+		return err
+	}
+
+	sender, err := i.getNodeInterface(bus, msgRow.Transmitter)
+	if err != nil {
+		return i.errorf(msgRow, err)
+	}
+
+	for _, recName := range receivers {
+		receiver, err := i.getNodeInterface(bus, recName)
+		if err != nil {
+			return i.errorf(msgRow, err)
+		}
+
+		if receiver == sender {
+			continue
+		}
+
+		if err := msg.AddReceiver(receiver); err != nil {
+			return i.errorf(msgRow, err)
+		}
+	}
+
+	if err := sender.AddSentMessage(msg); err != nil {
+		return i.errorf(msgRow, err)
+	}
+
+	return nil
+}
+
+// importSignals inserts the signals of the rows into the message.
+// Since a multiplexed signal can be listed before its muxor, the rows
+// are inserted once the layer of the muxor they refer to is added.
+func (i *kmatrixImporter) importSignals(msg *Message, rows []*kmatrix.Row) error {
+	muxLayers := make(map[string]*MultiplexedLayer)
+
+	pending := rows
+	for len(pending) > 0 {
+		next := []*kmatrix.Row{}
+
+		for _, row := range pending {
+			if row.Muxor != "" {
+				if _, ok := muxLayers[row.Muxor]; !ok {
+					next = append(next, row)
+					continue
+				}
+			}
+
+			if err := i.importSignal(msg, row, muxLayers); err != nil {
+				return err
+			}
+		}
+
+		if len(next) == len(pending) {
+			return i.errorf(next[0], newNameError(next[0].Muxor, ErrNotFound))
+		}
+		pending = next
+	}
+
+	return nil
+}
+
+func (i *kmatrixImporter) importSignal(msg *Message, row *kmatrix.Row, muxLayers map[string]*MultiplexedLayer) error {
+	startPos := row.StartBit
+	if row.ByteOrder == kmatrix.ByteOrderMotorola {
+		startPos = StartPosFromBigEndian(startPos)
+	}
+
+	if row.ValueType == kmatrix.ValueTypeMuxor {
+		muxor, err := NewMuxorSignal(row.Signal, getValueFromSize(row.Length))
+		if err != nil {
+			return i.errorf(row, err)
+		}
+
+		i.finishSignal(muxor, row)
+
+		layout := msg.layout
+		if row.Muxor != "" {
+			if len(row.MuxValues) != 1 {
+				return i.errorf(row, errors.New("a nested muxor must have exactly one multiplex value"))
+			}
+
+			parentLayer := muxLayers[row.Muxor]
+			if err := parentLayer.verifyLayoutID(row.MuxValues[0]); err != nil {
+				return i.errorf(row, muxor.errorf(err))
+			}
+			layout = parentLayer.GetLayout(row.MuxValues[0])
+		}
+
+		muxLayer, err := layout.AddMultiplexedLayer(muxor, startPos)
+		if err != nil {
+			return i.errorf(row, err)
+		}
+		muxLayers[row.Signal] = muxLayer
+
+		return nil
+	}
+
+	sig, err := i.newSignal(row)
+	if err != nil {
+		return err
+	}
+
+	i.finishSignal(sig, row)
+
+	if row.Muxor == "" {
+		if err := msg.InsertSignal(sig, startPos); err != nil {
+			return i.errorf(row, err)
+		}
+		return nil
+	}
+
+	if err := muxLayers[row.Muxor].InsertSignal(sig, startPos, row.MuxValues...); err != nil {
+		return i.errorf(row, err)
+	}
+
+	return nil
+}
+
+func (i *kmatrixImporter) finishSignal(sig Signal, row *kmatrix.Row) {
+	sig.SetDesc(row.Comment)
+
+	if row.ByteOrder == kmatrix.ByteOrderMotorola {
+		sig.SetEndianness(EndiannessBigEndian)
+	}
+}
+
+func (i *kmatrixImporter) newSignal(row *kmatrix.Row) (Signal, error) {
+	if len(row.ValueTable) > 0 {
+		sigEnum := NewSignalEnum(fmt.Sprintf("%s_enum", row.Signal))
+		for _, val := range row.ValueTable {
+			if _, err := sigEnum.AddValue(val.Index, val.Name); err != nil {
+				return nil, i.errorf(row, err)
+			}
+		}
+
+		enumSig, err := NewEnumSignal(row.Signal, sigEnum)
+		if err != nil {
+			return nil, i.errorf(row, err)
+		}
+
+		// If the size of the signal is bigger than the size of the enum,
+		// set the size of the enum as fixed with the given size
+		if enumSig.Size() < row.Length {
+			sigEnum.SetFixedSize(true)
+			if err := sigEnum.UpdateSize(row.Length); err != nil {
+				return nil, i.errorf(row, err)
+			}
+		}
+
+		return enumSig, nil
+	}
+
+	sigType, err := i.importSignalType(row)
+	if err != nil {
+		return nil, err
+	}
+
+	stdSig, err := NewStandardSignal(row.Signal, sigType)
+	if err != nil {
+		return nil, i.errorf(row, err)
+	}
+
+	if symbol := row.Unit; symbol != "" {
+		sigUnit, ok := i.signalUnits[symbol]
+		if !ok {
+			sigUnit = NewSignalUnit(symbol, SignalUnitKindCustom, symbol)
+			i.signalUnits[symbol] = sigUnit
+		}
+		stdSig.SetUnit(sigUnit)
+	}
+
+	return stdSig, nil
+}
+
+func (i *kmatrixImporter) importSignalType(row *kmatrix.Row) (*SignalType, error) {
+	size := row.Length
+	signed := row.ValueType == kmatrix.ValueTypeSigned
+	isFloat := row.ValueType == kmatrix.ValueTypeFloat
+
+	// the limits of the physical values
+	var min, max float64
+	if isFloat {
+		min, max = getFloatMinMaxFromSize(size)
+	} else {
+		rawMin, rawMax := getMinMaxFromSize(size, signed)
+		min = rawMin*row.Factor + row.Offset
+		max = rawMax*row.Factor + row.Offset
+		if min > max {
+			min, max = max, min
+		}
+	}
+
+	if row.Min != nil {
+		min = *row.Min
+	}
+	if row.Max != nil {
+		max = *row.Max
+	}
+
+	if size == 1 && !signed && !isFloat && row.Factor == 1 && row.Offset == 0 && min == 0 && max == 1 {
+		return i.flagSigType, nil
+	}
+
+	signStr := "u"
+	if isFloat {
+		signStr = "f"
+	} else if signed {
+		signStr = "s"
+	}
+	sigTypeKey := fmt.Sprintf("%s%d_%g-%g_%g_%g", signStr, size, min, max, row.Factor, row.Offset)
+
+	if sigType, ok := i.signalTypes[sigTypeKey]; ok {
+		return sigType, nil
+	}
+
+	var sigType *SignalType
+	if isFloat {
+		floatSigType, err := NewFloatSignalType(sigTypeKey, size)
+		if err != nil {
+			return nil, i.errorf(row, err)
+		}
+		sigType = floatSigType
+	} else if isDecimal(row.Factor) || isDecimal(row.Offset) || isDecimal(min) || isDecimal(max) {
+		decSigType, err := NewDecimalSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(row, err)
+		}
+		sigType = decSigType
+	} else {
+		intSigType, err := NewIntegerSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(row, err)
+		}
+		sigType = intSigType
+	}
+
+	sigType.SetScale(row.Factor)
+	sigType.SetOffset(row.Offset)
+	sigType.SetMin(min)
+	sigType.SetMax(max)
+
+	i.signalTypes[sigTypeKey] = sigType
+
+	return sigType, nil
+}
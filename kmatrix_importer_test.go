@@ -0,0 +1,206 @@
+package acmelib
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/squadracorsepolito/acmelib/kmatrix"
+	"github.com/stretchr/testify/assert"
+)
+
+const kmatrixTestFile = "testdata/sample.csv"
+
+func importKMatrixTestFile(assert *assert.Assertions) *Network {
+	file, err := os.Open(kmatrixTestFile)
+	assert.NoError(err)
+	defer file.Close()
+
+	net, err := ImportKMatrix(kmatrixTestFile, file, nil)
+	assert.NoError(err)
+
+	return net
+}
+
+func Test_ImportKMatrix(t *testing.T) {
+	assert := assert.New(t)
+
+	net := importKMatrixTestFile(assert)
+	assert.Equal(kmatrixTestFile, net.Name())
+
+	buses := net.Buses()
+	assert.Len(buses, 1)
+	bus := buses[0]
+	assert.Equal("powertrain", bus.Name())
+	assert.Equal(BusTypeCAN2B, bus.Type())
+
+	nodeInts := bus.NodeInterfaces()
+	assert.Len(nodeInts, 3)
+	assert.Equal("ECU", nodeInts[0].Node().Name())
+	assert.Equal("Dashboard", nodeInts[1].Node().Name())
+	assert.Equal("Logger", nodeInts[2].Node().Name())
+
+	msgs := nodeInts[0].SentMessages()
+	assert.Len(msgs, 2)
+
+	engine := msgs[0]
+	assert.Equal("Engine", engine.Name())
+	assert.Equal(CANID(0x100), engine.GetCANID())
+	assert.Equal(8, engine.SizeByte())
+	assert.Equal(100, engine.CycleTime())
+	assert.Equal(MessageSendTypeCyclic, engine.SendType())
+	assert.Len(engine.Receivers(), 2)
+	assert.Len(engine.Signals(), 4)
+
+	rpm, err := engine.GetSignalByName("rpm")
+	assert.NoError(err)
+	rpmStd, err := rpm.ToStandard()
+	assert.NoError(err)
+	assert.Equal("Engine speed", rpm.Desc())
+	assert.Equal(0.25, rpmStd.Type().Scale())
+	assert.Equal(8000.0, rpmStd.Type().Max())
+	assert.Equal("rpm", rpmStd.Unit().Symbol())
+
+	temperature, err := engine.GetSignalByName("temperature")
+	assert.NoError(err)
+	temperatureStd, err := temperature.ToStandard()
+	assert.NoError(err)
+	assert.True(temperatureStd.Type().Signed())
+	assert.Equal(-168.0, temperatureStd.Type().Min())
+	assert.Equal(87.0, temperatureStd.Type().Max())
+
+	gear, err := engine.GetSignalByName("gear")
+	assert.NoError(err)
+	gearEnum, err := gear.ToEnum()
+	assert.NoError(err)
+	assert.Equal("gear_enum", gearEnum.Enum().Name())
+	assert.Equal(4, gearEnum.Size())
+	assert.Len(gearEnum.Enum().Values(), 4)
+
+	// should convert the start bit of the motorola signals
+	pressure, err := engine.GetSignalByName("pressure")
+	assert.NoError(err)
+	assert.Equal(EndiannessBigEndian, pressure.Endianness())
+	assert.Equal(StartPosFromBigEndian(39), pressure.StartPos())
+
+	diag := msgs[1]
+	assert.Equal(CANIDFormatExtended, diag.CANIDFormat())
+	assert.Equal(CANID(0x1ABCDEF), diag.GetCANID())
+	assert.Len(diag.Receivers(), 1)
+
+	counter, err := diag.GetSignalByName("counter")
+	assert.NoError(err)
+	assert.Equal(2, counter.StartPos())
+
+	muxLayers := diag.SignalLayout().MultiplexedLayers()
+	assert.Len(muxLayers, 1)
+	muxLayer := muxLayers[0]
+	assert.Equal("page", muxLayer.Muxor().Name())
+	assert.Equal("Page of the diagnostic data", muxLayer.Muxor().Desc())
+	assert.Equal(4, muxLayer.GetLayoutCount())
+
+	assert.Equal("voltage", muxLayer.GetLayout(0).Signals()[0].Name())
+	assert.Len(muxLayer.GetLayout(1).Signals(), 2)
+	assert.Equal("error_code", muxLayer.GetLayout(2).Signals()[0].Name())
+	assert.Equal(0, muxLayer.GetLayout(3).SignalCount())
+
+	current, err := muxLayer.GetSignalByName("current")
+	assert.NoError(err)
+	currentStd, err := current.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFloat, currentStd.Type().Kind())
+}
+
+func Test_ImportKMatrix_Options(t *testing.T) {
+	assert := assert.New(t)
+
+	mapping := kmatrix.Mapping{
+		kmatrix.ColumnMessage:  "Frame",
+		kmatrix.ColumnCANID:    "ID",
+		kmatrix.ColumnSignal:   "Name",
+		kmatrix.ColumnStartBit: "Bit",
+		kmatrix.ColumnLength:   "Size",
+		kmatrix.ColumnMuxor:    "Mux",
+	}
+
+	// should return an error if the muxor is missing
+	content := "Frame,ID,Name,Bit,Size,Mux\nStatus,16,active,8,1,mode\nStatus,16,mode,0,2,\n"
+	_, err := ImportKMatrix("test.csv", strings.NewReader(content), &KMatrixOptions{Mapping: mapping})
+	assert.ErrorIs(err, ErrNotFound)
+
+	// should accept the multiplexed signals listed before their muxor
+	content = "Frame,ID,Name,Bit,Size,Mux,Type\nStatus,16,active,8,1,mode,\nStatus,16,mode,0,2,,muxor\n"
+	mapping[kmatrix.ColumnValueType] = "Type"
+	net, err := ImportKMatrix("test.csv", strings.NewReader(content), &KMatrixOptions{Mapping: mapping})
+	assert.NoError(err)
+
+	// should use the file name for the bus and the placeholder node for the transmitter
+	bus := net.Buses()[0]
+	assert.Equal("test.csv", bus.Name())
+	nodeInt := bus.NodeInterfaces()[0]
+	assert.Equal(dbc.DummyNode, nodeInt.Node().Name())
+
+	// should insert the signals without multiplex values into all the layouts
+	muxLayer := nodeInt.SentMessages()[0].SignalLayout().MultiplexedLayers()[0]
+	for _, layout := range muxLayer.Layouts() {
+		assert.Equal("active", layout.Signals()[0].Name())
+	}
+}
+
+func Test_ImportKMatrix_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	header := "Message,CAN ID,Signal,Start Bit,Length [bit],Value Type,Multiplexor,Multiplex Values\n"
+
+	tests := []struct {
+		name    string
+		content string
+		err     error
+		errMsg  string
+	}{
+		{
+			name:    "invalid cell",
+			content: header + "Engine,0x100,rpm,zero,16,,,\n",
+			errMsg:  `syntax error at test.csv:2; column "Start Bit"`,
+		},
+		{
+			name:    "overlapping signals",
+			content: header + "Engine,0x100,rpm,0,16,,,\nEngine,0x100,speed,8,16,,,\n",
+			errMsg:  `test.csv:3 : message error; entity_id:`,
+		},
+		{
+			name:    "duplicated signal",
+			content: header + "Engine,0x100,rpm,0,16,,,\nEngine,0x100,rpm,16,16,,,\n",
+			err:     ErrIsDuplicated,
+			errMsg:  "test.csv:3 : ",
+		},
+		{
+			name:    "different CAN ID",
+			content: header + "Engine,0x100,rpm,0,16,,,\nEngine,0x101,speed,16,16,,,\n",
+			err:     ErrIsDifferent,
+			errMsg:  "test.csv:3 : ",
+		},
+		{
+			name:    "invalid layout",
+			content: header + "Diag,0x100,page,0,2,muxor,,\nDiag,0x100,voltage,8,16,,page,4\n",
+			err:     ErrOutOfBounds,
+			errMsg:  "test.csv:3 : ",
+		},
+		{
+			name:    "nested muxor without layout",
+			content: header + "Diag,0x100,page,0,2,muxor,,\nDiag,0x100,sub_page,8,2,muxor,page,\n",
+			errMsg:  "test.csv:3 : ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportKMatrix("test.csv", strings.NewReader(tt.content), nil)
+			assert.ErrorContains(err, tt.errMsg)
+			if tt.err != nil {
+				assert.ErrorIs(err, tt.err)
+			}
+		})
+	}
+}
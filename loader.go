@@ -76,6 +76,7 @@ func LoadNetwork(r io.Reader, encoding SaveEncoding) (*Network, error) {
 type loader struct {
 	refCANIDBuilders map[string]*CANIDBuilder
 	refNodes         map[string]*Node
+	refMessages      map[string]*Message
 	refSigTypes      map[string]*SignalType
 	refSigUnits      map[string]*SignalUnit
 	refSigEnums      map[string]*SignalEnum
@@ -86,6 +87,7 @@ func newLoader() *loader {
 	return &loader{
 		refCANIDBuilders: make(map[string]*CANIDBuilder),
 		refNodes:         make(map[string]*Node),
+		refMessages:      make(map[string]*Message),
 		refSigTypes:      make(map[string]*SignalType),
 		refSigUnits:      make(map[string]*SignalUnit),
 		refSigEnums:      make(map[string]*SignalEnum),
@@ -161,6 +163,21 @@ func (l *loader) loadNetwork(pNet *acmelibv2.Network) (*Network, error) {
 		net.AddBus(bus)
 	}
 
+	// the gateway routes must be loaded after the buses because they refer to their messages
+	for _, pNode := range pNet.Nodes {
+		node := l.refNodes[pNode.Entity.EntityId]
+		for _, pRoute := range pNode.GatewayRoutes {
+			route, err := l.loadGatewayRoute(pRoute)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := node.AddGatewayRoute(route); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return net, nil
 }
 
@@ -201,6 +218,51 @@ func (l *loader) loadNode(pNode *acmelibv2.Node) (*Node, error) {
 	return node, nil
 }
 
+func (l *loader) loadGatewayRouteMessage(messageEntityID string) (*Message, error) {
+	msg, ok := l.refMessages[messageEntityID]
+	if !ok {
+		return nil, &EntityIDError{
+			EntityID: EntityID(messageEntityID),
+			Err:      ErrNotFound,
+		}
+	}
+	return msg, nil
+}
+
+func (l *loader) loadGatewayRoute(pRoute *acmelibv2.GatewayRoute) (*GatewayRoute, error) {
+	srcMsg, err := l.loadGatewayRouteMessage(pRoute.SourceMessageEntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	tgtMsg, err := l.loadGatewayRouteMessage(pRoute.TargetMessageEntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	route := NewGatewayRoute(srcMsg, tgtMsg)
+
+	for _, pMapping := range pRoute.SignalMappings {
+		srcSig, err := srcMsg.GetSignal(EntityID(pMapping.SourceSignalEntityId))
+		if err != nil {
+			return nil, err
+		}
+
+		tgtSig, err := tgtMsg.GetSignal(EntityID(pMapping.TargetSignalEntityId))
+		if err != nil {
+			return nil, err
+		}
+
+		route.AddSignalMapping(srcSig, tgtSig)
+	}
+
+	if pRoute.ProcessingTime != nil {
+		route.SetProcessingTime(pRoute.ProcessingTime.AsDuration())
+	}
+
+	return route, nil
+}
+
 func (l *loader) loadBus(pBus *acmelibv2.Bus) (*Bus, error) {
 	bus := newBusFromEntity(l.loadEntity(pBus.Entity, EntityKindBus))
 
@@ -208,10 +270,16 @@ func (l *loader) loadBus(pBus *acmelibv2.Bus) (*Bus, error) {
 	switch pBus.Type {
 	case acmelibv2.BusType_BUS_TYPE_CAN_2A:
 		typ = BusTypeCAN2A
+	case acmelibv2.BusType_BUS_TYPE_CAN_FD:
+		typ = BusTypeCANFD
+	case acmelibv2.BusType_BUS_TYPE_CAN_2B:
+		typ = BusTypeCAN2B
 	}
 	bus.SetType(typ)
 
 	bus.SetBaudrate(int(pBus.Baudrate))
+	bus.SetDataBaudrate(int(pBus.DataBaudrate))
+	bus.SetBitRateSwitch(pBus.BitRateSwitch)
 
 	for _, pNodeInt := range pBus.NodeInterfaces {
 		nodeInt, err := l.loadNodeInterface(pNodeInt)
@@ -271,6 +339,25 @@ func (l *loader) loadNodeInterface(pNodeInt *acmelibv2.NodeInterface) (*NodeInte
 
 func (l *loader) loadMessage(pMsg *acmelibv2.Message) (*Message, error) {
 	msg := newMessageFromEntity(l.loadEntity(pMsg.Entity, EntityKindMessage), MessageID(pMsg.MessageId), int(pMsg.SizeByte))
+	l.refMessages[pMsg.Entity.EntityId] = msg
+
+	// the format must be set before the static CAN-ID because it limits its width
+	if pMsg.CanIdFormat == acmelibv2.CANIDFormat_CANID_FORMAT_EXTENDED {
+		if err := msg.SetCANIDFormat(CANIDFormatExtended); err != nil {
+			return nil, err
+		}
+	}
+
+	switch pMsg.Transport {
+	case acmelibv2.MessageTransport_MESSAGE_TRANSPORT_J1939_TP:
+		if err := msg.SetTransport(MessageTransportJ1939TP); err != nil {
+			return nil, err
+		}
+	case acmelibv2.MessageTransport_MESSAGE_TRANSPORT_ISO_TP:
+		if err := msg.SetTransport(MessageTransportISOTP); err != nil {
+			return nil, err
+		}
+	}
 
 	if pMsg.HasStaticCanId {
 		if err := msg.SetStaticCANID(CANID(pMsg.StaticCanId)); err != nil {
@@ -287,6 +374,16 @@ func (l *loader) loadMessage(pMsg *acmelibv2.Message) (*Message, error) {
 		msg.SetPriority(MessagePriorityMedium)
 	case acmelibv2.MessagePriority_MESSAGE_PRIORITY_LOW:
 		msg.SetPriority(MessagePriorityLow)
+
+	// the J1939 priorities
+	case acmelibv2.MessagePriority_MESSAGE_PRIORITY_4:
+		msg.SetPriority(4)
+	case acmelibv2.MessagePriority_MESSAGE_PRIORITY_5:
+		msg.SetPriority(5)
+	case acmelibv2.MessagePriority_MESSAGE_PRIORITY_6:
+		msg.SetPriority(6)
+	case acmelibv2.MessagePriority_MESSAGE_PRIORITY_7:
+		msg.SetPriority(7)
 	}
 
 	if pMsg.CycleTime != 0 {
@@ -339,9 +436,108 @@ func (l *loader) loadMessage(pMsg *acmelibv2.Message) (*Message, error) {
 		}
 	}
 
+	// the protection must be loaded after the layout because it refers to its signals
+	if pMsg.E2EProtection != nil {
+		prot, err := l.loadE2EProtection(msg, pMsg.E2EProtection)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := msg.SetE2EProtection(prot); err != nil {
+			return nil, err
+		}
+	}
+
 	return msg, nil
 }
 
+func (l *loader) loadE2EProtectionSignal(msg *Message, signalEntityID string) (*StandardSignal, error) {
+	sig, err := msg.GetSignal(EntityID(signalEntityID))
+	if err != nil {
+		return nil, &EntityIDError{
+			EntityID: EntityID(signalEntityID),
+			Err:      ErrNotFound,
+		}
+	}
+	return sig.ToStandard()
+}
+
+func (l *loader) loadE2EProtection(msg *Message, pProt *acmelibv2.E2EProtection) (*E2EProtection, error) {
+	var profile E2EProfile
+	switch pProt.Profile {
+	case acmelibv2.E2EProfile_E2E_PROFILE_1:
+		profile = E2EProfile1
+	case acmelibv2.E2EProfile_E2E_PROFILE_2:
+		profile = E2EProfile2
+	case acmelibv2.E2EProfile_E2E_PROFILE_4:
+		profile = E2EProfile4
+	case acmelibv2.E2EProfile_E2E_PROFILE_5:
+		profile = E2EProfile5
+	case acmelibv2.E2EProfile_E2E_PROFILE_11:
+		profile = E2EProfile11
+	case acmelibv2.E2EProfile_E2E_PROFILE_CUSTOM:
+		profile = E2EProfileCustom
+	}
+
+	crcSig, err := l.loadE2EProtectionSignal(msg, pProt.CrcSignalEntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	counterSig, err := l.loadE2EProtectionSignal(msg, pProt.CounterSignalEntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	prot := NewE2EProtection(profile, crcSig, counterSig)
+
+	prot.SetDataID(pProt.DataId)
+
+	switch pProt.DataIdMode {
+	case acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_ALT:
+		prot.SetDataIDMode(E2EDataIDModeAlt)
+	case acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_LOW:
+		prot.SetDataIDMode(E2EDataIDModeLow)
+	case acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_NIBBLE:
+		prot.SetDataIDMode(E2EDataIDModeNibble)
+	}
+
+	if len(pProt.DataIdList) > 0 {
+		prot.SetDataIDList(pProt.DataIdList)
+	}
+
+	if len(pProt.DataIdSignalEntityId) > 0 {
+		dataIDSig, err := l.loadE2EProtectionSignal(msg, pProt.DataIdSignalEntityId)
+		if err != nil {
+			return nil, err
+		}
+		prot.SetDataIDSignal(dataIDSig)
+	}
+
+	if len(pProt.LengthSignalEntityId) > 0 {
+		lengthSig, err := l.loadE2EProtectionSignal(msg, pProt.LengthSignalEntityId)
+		if err != nil {
+			return nil, err
+		}
+		prot.SetLengthSignal(lengthSig)
+	}
+
+	prot.SetMaxDeltaCounter(pProt.MaxDeltaCounter)
+
+	if pProt.CustomCrc != nil {
+		prot.SetCustomCRC(&E2ECRCParams{
+			Width:      int(pProt.CustomCrc.Width),
+			Polynomial: pProt.CustomCrc.Polynomial,
+			Init:       pProt.CustomCrc.Init,
+			XorOut:     pProt.CustomCrc.XorOut,
+			RefIn:      pProt.CustomCrc.RefIn,
+			RefOut:     pProt.CustomCrc.RefOut,
+		})
+	}
+
+	return prot, nil
+}
+
 func (l *loader) loadSignal(pSig *acmelibv2.Signal) (Signal, error) {
 	var kind SignalKind
 	switch pSig.Kind {
@@ -538,6 +734,13 @@ func (l *loader) loadSignalType(pSigType *acmelibv2.SignalType) (*SignalType, er
 		kind = SignalTypeKindInteger
 	case acmelibv2.SignalTypeKind_SIGNAL_TYPE_KIND_DECIMAL:
 		kind = SignalTypeKindDecimal
+	case acmelibv2.SignalTypeKind_SIGNAL_TYPE_KIND_FLOAT:
+		kind = SignalTypeKindFloat
+
+		// the float types can only be single or double precision
+		if pSigType.Size != 32 && pSigType.Size != 64 {
+			return nil, newArgError("size", ErrInvalidType)
+		}
 	}
 
 	ent := l.loadEntity(pSigType.Entity, EntityKindSignalType)
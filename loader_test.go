@@ -29,3 +29,185 @@ func Test_LoadNetwork(t *testing.T) {
 	ExportDBCBus(dbcRes, loadNet.Buses()[0])
 	compareDBCFiles(assert, dbcRes)
 }
+
+func Test_LoadNetwork_FloatSignalType(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("network")
+	bus := NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	floatType, err := NewFloatSignalType("float64_t", 64)
+	assert.NoError(err)
+	floatSig, err := NewStandardSignal("float_signal", floatType)
+	assert.NoError(err)
+
+	msg := NewMessage("message", 1, 8)
+	assert.NoError(msg.InsertSignal(floatSig, 0))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(SaveNetwork(net, &SaveNetworkOptions{
+		WireWriter: buf,
+	}))
+
+	loadNet, err := LoadNetwork(buf, SaveEncodingWire)
+	assert.NoError(err)
+
+	loadMsg := loadNet.Buses()[0].NodeInterfaces()[0].SentMessages()[0]
+	loadSig, err := loadMsg.GetSignalByName("float_signal")
+	assert.NoError(err)
+	loadStdSig, err := loadSig.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFloat, loadStdSig.Type().Kind())
+	assert.Equal(64, loadStdSig.Type().Size())
+
+	// should return an error because the float size is not valid
+	floatType.size = 16
+	buf.Reset()
+	assert.NoError(SaveNetwork(net, &SaveNetworkOptions{
+		WireWriter: buf,
+	}))
+
+	_, err = LoadNetwork(buf, SaveEncodingWire)
+	assert.ErrorIs(err, ErrInvalidType)
+}
+
+func Test_LoadNetwork_CANFD(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("network")
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCANFD)
+	bus.SetBaudrate(500_000)
+	bus.SetDataBaudrate(2_000_000)
+	bus.SetBitRateSwitch(true)
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+	assert.NoError(nodeInt.AddSentMessage(NewMessage("message", 1, 64)))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(SaveNetwork(net, &SaveNetworkOptions{
+		WireWriter: buf,
+	}))
+
+	loadNet, err := LoadNetwork(buf, SaveEncodingWire)
+	assert.NoError(err)
+
+	loadBus := loadNet.Buses()[0]
+	assert.Equal(BusTypeCANFD, loadBus.Type())
+	assert.Equal(500_000, loadBus.Baudrate())
+	assert.Equal(2_000_000, loadBus.DataBaudrate())
+	assert.True(loadBus.BitRateSwitch())
+	assert.Equal(64, loadBus.NodeInterfaces()[0].SentMessages()[0].SizeByte())
+}
+
+func Test_LoadNetwork_ExtendedID(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("network")
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := NewMessage("message", 1, 8)
+	assert.NoError(msg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(msg.SetStaticCANID(0x18FF1234))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(SaveNetwork(net, &SaveNetworkOptions{
+		WireWriter: buf,
+	}))
+
+	loadNet, err := LoadNetwork(buf, SaveEncodingWire)
+	assert.NoError(err)
+
+	loadBus := loadNet.Buses()[0]
+	assert.Equal(BusTypeCAN2B, loadBus.Type())
+
+	loadMsg := loadBus.NodeInterfaces()[0].SentMessages()[0]
+	assert.Equal(CANIDFormatExtended, loadMsg.CANIDFormat())
+	assert.Equal(CANID(0x18FF1234), loadMsg.GetCANID())
+}
+
+func Test_LoadNetwork_J1939Priority(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("network")
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := NewMessage("message", 1, 8)
+	msg.SetPriority(6)
+	assert.NoError(msg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(SaveNetwork(net, &SaveNetworkOptions{
+		WireWriter: buf,
+	}))
+
+	loadNet, err := LoadNetwork(buf, SaveEncodingWire)
+	assert.NoError(err)
+
+	loadMsg := loadNet.Buses()[0].NodeInterfaces()[0].SentMessages()[0]
+	assert.Equal(MessagePriority(6), loadMsg.Priority())
+}
+
+func Test_LoadNetwork_E2EProtection(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("network")
+	bus := NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg, crcSig, counterSig := initE2EMessage(assert, 8, 4)
+	prot := NewE2EProtection(E2EProfileCustom, crcSig, counterSig)
+	prot.SetDataID(0x1234)
+	prot.SetMaxDeltaCounter(3)
+	prot.SetCustomCRC(&E2ECRCParams{Width: 8, Polynomial: 0x07, Init: 0xFF, RefIn: true, RefOut: true})
+	assert.NoError(msg.SetE2EProtection(prot))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(SaveNetwork(net, &SaveNetworkOptions{
+		WireWriter: buf,
+	}))
+
+	loadNet, err := LoadNetwork(buf, SaveEncodingWire)
+	assert.NoError(err)
+
+	loadMsg := loadNet.Buses()[0].NodeInterfaces()[0].SentMessages()[0]
+	loadProt := loadMsg.E2EProtection()
+	assert.NotNil(loadProt)
+	assert.Equal(E2EProfileCustom, loadProt.Profile())
+	assert.Equal(crcSig.EntityID(), loadProt.CRCSignal().EntityID())
+	assert.Equal(counterSig.EntityID(), loadProt.CounterSignal().EntityID())
+	assert.Equal(uint32(0x1234), loadProt.DataID())
+	assert.Equal(uint64(3), loadProt.MaxDeltaCounter())
+	assert.Equal(prot.CustomCRC(), loadProt.CustomCRC())
+
+	// should encode the same payload
+	values := map[string]any{"value": 1000, "counter": 7}
+	data := make([]byte, 8)
+	assert.NoError(msg.EncodeValues(data, values))
+	loadData := make([]byte, 8)
+	assert.NoError(loadMsg.EncodeValues(loadData, values))
+	assert.Equal(data, loadData)
+}
@@ -1,378 +1,573 @@
 package acmelib
 
-// import (
-// 	"fmt"
-// 	"io"
-// 	"slices"
-// 	"strconv"
-// 	"strings"
-
-// 	md "github.com/nao1215/markdown"
-// 	"golang.org/x/exp/maps"
-// )
-
-// // ExportToMarkdown exports the given [Network] to a markdown document.
-// // It writes the markdown document to the given [io.Writer].
-// func ExportToMarkdown(network *Network, w io.Writer) error {
-// 	mdWriter := md.NewMarkdown(w)
-// 	exporter := newMDExporter(mdWriter)
-// 	exporter.exportNetwork(network)
-// 	return mdWriter.Build()
-// }
-
-// type mdExporter struct {
-// 	w *md.Markdown
-
-// 	sigTypes map[EntityID]*SignalType
-// 	sigUnits map[EntityID]*SignalUnit
-// 	sigEnums map[EntityID]*SignalEnum
-// }
-
-// func newMDExporter(mdWriter *md.Markdown) *mdExporter {
-// 	return &mdExporter{
-// 		w: mdWriter,
-
-// 		sigTypes: make(map[EntityID]*SignalType),
-// 		sigUnits: make(map[EntityID]*SignalUnit),
-// 		sigEnums: make(map[EntityID]*SignalEnum),
-// 	}
-// }
-
-// func (e *mdExporter) getHeaderLink(headerName string) string {
-// 	linkStr := "#" + strings.ReplaceAll(strings.ToLower(headerName), " ", "-")
-// 	linkStr = strings.ReplaceAll(linkStr, "`", "")
-// 	return md.Link(headerName, linkStr)
-// }
-
-// func (e *mdExporter) exportNetwork(net *Network) {
-// 	e.w.Importantf("This markdown document is generated by %s", md.Link("acmelib", "https://github.com/squadracorsepolito/acmelib")).LF()
-
-// 	e.w.H1(net.name)
-
-// 	if len(net.desc) > 0 {
-// 		e.w.PlainText(net.desc).LF()
-// 		e.w.HorizontalRule()
-// 	}
-
-// 	e.exportTOC(net)
-
-// 	for _, bus := range net.Buses() {
-// 		e.exportBus(bus)
-// 	}
-
-// 	sigTypes := maps.Values(e.sigTypes)
-// 	slices.SortFunc(sigTypes, func(a, b *SignalType) int { return a.size - b.size })
-// 	e.exportSignalTypes(sigTypes)
-
-// 	sigUnits := maps.Values(e.sigUnits)
-// 	slices.SortFunc(sigUnits, func(a, b *SignalUnit) int { return strings.Compare(a.name, b.name) })
-// 	e.exportSignalUnits(sigUnits)
-
-// 	sigEnums := maps.Values(e.sigEnums)
-// 	slices.SortFunc(sigEnums, func(a, b *SignalEnum) int { return strings.Compare(a.name, b.name) })
-// 	e.w.H2("Signal Enums")
-// 	e.w.PlainText("The list of all the signal enums used in the network.").LF()
-// 	for _, tmpSigEnum := range sigEnums {
-// 		e.exportSignalEnum(tmpSigEnum)
-// 	}
-// }
-
-// func (e *mdExporter) exportTOC(net *Network) {
-// 	for _, bus := range net.Buses() {
-// 		e.w.BulletList(e.getHeaderLink(bus.name))
-// 		for _, nodeInt := range bus.NodeInterfaces() {
-// 			e.w.PlainTextf("\t- %s", e.getHeaderLink(nodeInt.node.name))
-// 			for _, msg := range nodeInt.SentMessages() {
-// 				e.w.PlainTextf("\t\t- %s", e.getHeaderLink(msg.name))
-// 			}
-// 		}
-// 	}
-
-// 	e.w.BulletList(e.getHeaderLink("Signal Types"))
-// 	e.w.BulletList(e.getHeaderLink("Signal Units"))
-// 	e.w.BulletList(e.getHeaderLink("Signal Enums"))
-// }
-
-// func (e *mdExporter) exportBus(bus *Bus) {
-// 	e.w.H2(bus.name)
-
-// 	if len(bus.desc) > 0 {
-// 		e.w.PlainText(bus.desc).LF()
-// 		e.w.HorizontalRule()
-// 	}
-
-// 	baudrateStr := "-"
-// 	if bus.baudrate != 0 {
-// 		baudrateStr = md.Bold(strconv.Itoa(bus.baudrate))
-// 	}
-// 	e.w.PlainTextf("Baudrate: %s bps", baudrateStr).LF()
-
-// 	for _, node := range bus.NodeInterfaces() {
-// 		e.exportNode(node)
-// 	}
-// }
-
-// func (e *mdExporter) exportNode(node *NodeInterface) {
-// 	e.w.HorizontalRule()
-// 	e.w.H3(node.node.name)
-
-// 	if len(node.node.desc) > 0 {
-// 		e.w.PlainText(node.node.desc).LF()
-// 		e.w.HorizontalRule()
-// 	}
-
-// 	hexNodeIIDStr := "0x" + strconv.FormatUint(uint64(node.node.id), 16)
-// 	e.w.PlainTextf("Node ID: %s (dec), %s (hex)", md.Bold(fmt.Sprintf("%d", node.node.id)), md.Bold(hexNodeIIDStr)).LF()
-
-// 	for _, msg := range node.SentMessages() {
-// 		e.exportMessage(msg)
-// 	}
-// }
-
-// func (e *mdExporter) exportMessage(msg *Message) {
-// 	e.w.HorizontalRule()
-// 	e.w.H4(msg.name)
-
-// 	if len(msg.desc) > 0 {
-// 		e.w.PlainText(msg.desc).LF()
-// 		e.w.HorizontalRule()
-// 	}
-
-// 	var canID CANID
-// 	prefixStr := "(static)"
-// 	if msg.hasStaticCANID {
-// 		canID = msg.staticCANID
-// 	} else {
-// 		canID = msg.GetCANID()
-// 		prefixStr = "(generated)"
-// 	}
-// 	decCANIDStr := fmt.Sprintf("%d", canID)
-// 	hexCANIIDStr := "0x" + strconv.FormatUint(uint64(canID), 16)
-// 	e.w.PlainTextf("CAN-ID %s: %s (dec), %s (hex)", prefixStr, md.Bold(decCANIDStr), md.Bold(hexCANIIDStr)).LF()
-
-// 	if !msg.hasStaticCANID {
-// 		hexMsgIIDStr := "0x" + strconv.FormatUint(uint64(msg.id), 16)
-// 		e.w.PlainTextf("Message ID: %s (dec), %s (hex)", md.Bold(fmt.Sprintf("%d", msg.id)), md.Bold(hexMsgIIDStr)).LF()
-// 	}
-
-// 	e.w.PlainTextf("Size: %s bytes", md.Bold(fmt.Sprintf("%d", msg.sizeByte))).LF()
-// 	e.w.PlainTextf("Byte Order: %s", md.Bold(msg.byteOrder.String())).LF()
-
-// 	cycleTimeStr := "-"
-// 	if msg.cycleTime > 0 {
-// 		cycleTimeStr = fmt.Sprintf("%s ms", md.Bold(strconv.Itoa(msg.cycleTime)))
-// 	}
-// 	e.w.PlainTextf("Cycle Time: %s", cycleTimeStr).LF()
-
-// 	recStr := "Receivers: "
-// 	for idx, recInt := range msg.Receivers() {
-// 		recLink := e.getHeaderLink(recInt.node.name)
-
-// 		if idx == 0 {
-// 			recStr += recLink
-// 			continue
-// 		}
-
-// 		recStr += fmt.Sprintf(", %s", recLink)
-// 	}
-// 	e.w.PlainText(recStr).LF()
-
-// 	if len(msg.Signals()) == 0 {
-// 		return
-// 	}
-
-// 	sigTable := md.TableSet{
-// 		Header: []string{"Name", "Start Bit", "Size", "Type", "Min", "Max", "Unit", "Description"},
-// 		Rows:   [][]string{},
-// 	}
-// 	for _, sig := range msg.Signals() {
-// 		sigRows := e.exportSignal(sig)
-// 		for _, tmpSigRow := range sigRows {
-// 			sigTable.Rows = append(sigTable.Rows, tmpSigRow)
-// 		}
-// 	}
-// 	e.w.CustomTable(sigTable, md.TableOptions{AutoWrapText: false, AutoFormatHeaders: false})
-// }
-
-// func (e *mdExporter) exportSignal(sig Signal) (resRows [][]string) {
-// 	sigRow := []string{}
-
-// 	sigRow = append(sigRow, sig.Name())
-// 	sigRow = append(sigRow, fmt.Sprintf("%d", sig.GetStartBit()))
-// 	sigRow = append(sigRow, fmt.Sprintf("%d", sig.GetSize()))
-
-// 	switch sig.Kind() {
-// 	case SignalKindStandard:
-// 		stdSig, err := sig.ToStandard()
-// 		if err != nil {
-// 			panic(err)
-// 		}
-// 		sigRow = append(sigRow, e.exportStandardSignal(stdSig)...)
-// 		resRows = append(resRows, sigRow)
-
-// 	case SignalKindEnum:
-// 		enumSig, err := sig.ToEnum()
-// 		if err != nil {
-// 			panic(err)
-// 		}
-// 		sigRow = append(sigRow, e.exportEnumSignal(enumSig)...)
-// 		resRows = append(resRows, sigRow)
-
-// 	case SignalKindMultiplexer:
-// 		muxSig, err := sig.ToMultiplexer()
-// 		if err != nil {
-// 			panic(err)
-// 		}
-// 		muxSigRows := e.exportMultiplexerSignal(muxSig)
-// 		for _, tmpRow := range muxSigRows {
-// 			resRows = append(resRows, tmpRow)
-// 		}
-// 	}
-
-// 	return resRows
-// }
-
-// func (e *mdExporter) exportStandardSignal(stdSig *StandardSignal) (resRow []string) {
-// 	sigType := stdSig.typ
-// 	e.sigTypes[sigType.entityID] = sigType
-// 	resRow = append(resRow, md.Link(md.Code(sigType.name), "#signal-types"))
-
-// 	resRow = append(resRow, fmt.Sprintf("%g", stdSig.typ.min))
-// 	resRow = append(resRow, fmt.Sprintf("%g", stdSig.typ.max))
-
-// 	unitSymbol := "-"
-// 	if stdSig.unit != nil {
-// 		sigUnit := stdSig.unit
-// 		unitSymbol = md.Link(md.Code(sigUnit.symbol), "#signal-units")
-// 		e.sigUnits[sigUnit.entityID] = sigUnit
-// 	}
-// 	resRow = append(resRow, unitSymbol)
-
-// 	desc := stdSig.desc
-// 	if len(desc) == 0 {
-// 		desc = "-"
-// 	}
-// 	resRow = append(resRow, desc)
-
-// 	return resRow
-// }
-
-// func (e *mdExporter) exportEnumSignal(enumSig *EnumSignal) (resRow []string) {
-// 	sigEnum := enumSig.enum
-// 	e.sigEnums[sigEnum.entityID] = sigEnum
-// 	resRow = append(resRow, e.getHeaderLink(sigEnum.name))
-
-// 	resRow = append(resRow, "0")
-// 	resRow = append(resRow, fmt.Sprintf("%d", sigEnum.maxIndex))
-// 	resRow = append(resRow, "-")
-
-// 	desc := enumSig.desc
-// 	if len(desc) == 0 {
-// 		desc = "-"
-// 	}
-// 	resRow = append(resRow, desc)
-
-// 	return resRow
-// }
-
-// func (e *mdExporter) exportMultiplexerSignal(muxSig *MultiplexerSignal) (resRows [][]string) {
-// 	muxSigRow := []string{}
-// 	muxSigRow = append(muxSigRow, md.Code("multiplexer"))
-
-// 	muxSigRow = append(muxSigRow, "0")
-// 	muxSigRow = append(muxSigRow, fmt.Sprintf("%d", muxSig.groupCount))
-// 	muxSigRow = append(muxSigRow, "-")
-
-// 	desc := muxSig.desc
-// 	if len(desc) == 0 {
-// 		desc = "-"
-// 	}
-// 	muxSigRow = append(muxSigRow, desc)
-
-// 	resRows = append(resRows, muxSigRow)
-
-// 	for groupID, group := range muxSig.GetSignalGroups() {
-// 		tmpCol := fmt.Sprintf("- %d -", groupID)
-// 		resRows = append(resRows, []string{tmpCol, tmpCol, tmpCol, tmpCol, tmpCol, tmpCol, tmpCol, tmpCol})
-
-// 		for _, tmpSig := range group {
-// 			sigRows := e.exportSignal(tmpSig)
-// 			for _, tmpSigRow := range sigRows {
-// 				resRows = append(resRows, tmpSigRow)
-// 			}
-// 		}
-// 	}
-
-// 	return resRows
-// }
-
-// func (e *mdExporter) exportSignalTypes(sigTypes []*SignalType) {
-// 	e.w.H2("Signal Types")
-// 	e.w.PlainText("The list of all the signal types used in the network.").LF()
-
-// 	sigTypeTable := md.TableSet{
-// 		Header: []string{"Name", "Size", "Kind", "Signed", "Min", "Max", "Scale", "Offset", "Description"},
-// 		Rows:   [][]string{},
-// 	}
-// 	for _, tmpSigType := range sigTypes {
-// 		desc := "-"
-// 		if len(tmpSigType.desc) > 0 {
-// 			desc = tmpSigType.desc
-// 		}
-
-// 		sizeStr := fmt.Sprintf("%d", tmpSigType.size)
-// 		signedStr := fmt.Sprintf("%t", tmpSigType.signed)
-// 		minStr := fmt.Sprintf("%g", tmpSigType.min)
-// 		maxStr := fmt.Sprintf("%g", tmpSigType.max)
-// 		scaleStr := fmt.Sprintf("%g", tmpSigType.scale)
-// 		offsetStr := fmt.Sprintf("%g", tmpSigType.offset)
-
-// 		row := []string{tmpSigType.name, sizeStr, md.Code(tmpSigType.kind.String()), md.Code(signedStr), minStr, maxStr, scaleStr, offsetStr, desc}
-// 		sigTypeTable.Rows = append(sigTypeTable.Rows, row)
-// 	}
-// 	e.w.CustomTable(sigTypeTable, md.TableOptions{AutoWrapText: false, AutoFormatHeaders: false})
-// }
-
-// func (e *mdExporter) exportSignalUnits(sigUnits []*SignalUnit) {
-// 	e.w.H2("Signal Units")
-// 	e.w.PlainText("The list of all the signal units used in the network.").LF()
-
-// 	sigUnitTable := md.TableSet{
-// 		Header: []string{"Name", "Kind", "Symbol", "Description"},
-// 		Rows:   [][]string{},
-// 	}
-// 	for _, tmpSigUnit := range sigUnits {
-// 		desc := "-"
-// 		if len(tmpSigUnit.desc) > 0 {
-// 			desc = tmpSigUnit.desc
-// 		}
-
-// 		row := []string{tmpSigUnit.name, tmpSigUnit.kind.String(), tmpSigUnit.symbol, desc}
-// 		sigUnitTable.Rows = append(sigUnitTable.Rows, row)
-// 	}
-// 	e.w.CustomTable(sigUnitTable, md.TableOptions{AutoWrapText: false, AutoFormatHeaders: false})
-// }
-
-// func (e *mdExporter) exportSignalEnum(sigEnum *SignalEnum) {
-// 	e.w.HorizontalRule()
-// 	e.w.H4(sigEnum.name)
-
-// 	if len(sigEnum.desc) > 0 {
-// 		e.w.PlainText(sigEnum.desc).LF()
-// 		e.w.HorizontalRule()
-// 	}
-
-// 	valueTable := md.TableSet{
-// 		Header: []string{"Name", "Index", "Description"},
-// 		Rows:   [][]string{},
-// 	}
-// 	for _, tmpVal := range sigEnum.Values() {
-// 		desc := "-"
-// 		if len(tmpVal.desc) > 0 {
-// 			desc = tmpVal.desc
-// 		}
-// 		row := []string{tmpVal.name, fmt.Sprintf("%d", tmpVal.index), desc}
-// 		valueTable.Rows = append(valueTable.Rows, row)
-// 	}
-// 	e.w.CustomTable(valueTable, md.TableOptions{AutoWrapText: false, AutoFormatHeaders: false})
-// }
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ExportToMarkdown exports the given [Network] to a markdown document.
+// It writes the markdown document to the given [io.Writer].
+//
+// The document contains a table of contents with the buses, the nodes and
+// the messages of the network, a section for each bus, node and message,
+// and the appendices with the signal types, units and enums used by the signals.
+// The signals of a multiplexed layer are listed in a table for each layout ID.
+func ExportToMarkdown(network *Network, w io.Writer) error {
+	exporter := newMDExporter(w)
+	exporter.exportNetwork(network)
+	return exporter.err
+}
+
+const (
+	mdSignalTypesHeader = "Signal Types"
+	mdSignalUnitsHeader = "Signal Units"
+	mdSignalEnumsHeader = "Signal Enums"
+)
+
+type mdExporter struct {
+	w   io.Writer
+	err error
+
+	// anchors maps the buses, the node interfaces, the messages and the enums
+	// to the link of their header
+	anchors   map[any]string
+	slugCount map[string]int
+
+	sigTypes []*SignalType
+	sigUnits []*SignalUnit
+	sigEnums []*SignalEnum
+
+	seenEntities map[EntityID]bool
+}
+
+func newMDExporter(w io.Writer) *mdExporter {
+	return &mdExporter{
+		w: w,
+
+		anchors:   make(map[any]string),
+		slugCount: make(map[string]int),
+
+		seenEntities: make(map[EntityID]bool),
+	}
+}
+
+func (e *mdExporter) println(format string, a ...any) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format+"\n", a...)
+}
+
+func (e *mdExporter) newLine() {
+	e.println("")
+}
+
+func (e *mdExporter) header(level int, text string) {
+	e.println("%s %s", strings.Repeat("#", level), text)
+	e.newLine()
+}
+
+func (e *mdExporter) paragraph(text string) {
+	if len(text) == 0 {
+		return
+	}
+	e.println("%s", text)
+	e.newLine()
+}
+
+func (e *mdExporter) horizontalRule() {
+	e.println("---")
+	e.newLine()
+}
+
+func (e *mdExporter) table(header []string, rows [][]string) {
+	e.println("| %s |", strings.Join(header, " | "))
+
+	separators := make([]string, len(header))
+	for idx := range separators {
+		separators[idx] = "---"
+	}
+	e.println("| %s |", strings.Join(separators, " | "))
+
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for idx, cell := range row {
+			cells[idx] = e.escapeCell(cell)
+		}
+		e.println("| %s |", strings.Join(cells, " | "))
+	}
+
+	e.newLine()
+}
+
+func (e *mdExporter) escapeCell(cell string) string {
+	if len(cell) == 0 {
+		return "-"
+	}
+	cell = strings.ReplaceAll(cell, "|", `\|`)
+	return strings.ReplaceAll(cell, "\n", "<br>")
+}
+
+func (e *mdExporter) code(text string) string {
+	return "`" + text + "`"
+}
+
+func (e *mdExporter) bold(text string) string {
+	return "**" + text + "**"
+}
+
+func (e *mdExporter) link(text, anchor string) string {
+	return fmt.Sprintf("[%s](#%s)", text, anchor)
+}
+
+func (e *mdExporter) hex(val uint64) string {
+	return "0x" + strings.ToUpper(strconv.FormatUint(val, 16))
+}
+
+// addAnchor generates the anchor of a header in the same way GitHub does,
+// so a duplicated header gets a numeric suffix.
+// The headers must be added in the order they appear in the document.
+func (e *mdExporter) addAnchor(key any, headerText string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(headerText) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+
+	slug := b.String()
+	anchor := slug
+	if count := e.slugCount[slug]; count > 0 {
+		anchor = fmt.Sprintf("%s-%d", slug, count)
+	}
+	e.slugCount[slug]++
+
+	if key != nil {
+		e.anchors[key] = anchor
+	}
+
+	return anchor
+}
+
+// collect visits the network in the same order of the exporter in order to
+// generate the anchors of the headers and to collect the entities of the appendices.
+func (e *mdExporter) collect(net *Network) {
+	e.addAnchor(nil, net.name)
+
+	for _, bus := range net.Buses() {
+		e.addAnchor(bus, bus.name)
+
+		for _, nodeInt := range bus.NodeInterfaces() {
+			e.addAnchor(nodeInt, nodeInt.node.name)
+
+			for _, msg := range nodeInt.SentMessages() {
+				e.addAnchor(msg, msg.name)
+				e.collectLayout(msg.layout)
+			}
+		}
+	}
+
+	slices.SortFunc(e.sigTypes, func(a, b *SignalType) int {
+		return cmp.Or(strings.Compare(a.name, b.name), cmp.Compare(a.entityID, b.entityID))
+	})
+	slices.SortFunc(e.sigUnits, func(a, b *SignalUnit) int {
+		return cmp.Or(strings.Compare(a.name, b.name), cmp.Compare(a.entityID, b.entityID))
+	})
+	slices.SortFunc(e.sigEnums, func(a, b *SignalEnum) int {
+		return cmp.Or(strings.Compare(a.name, b.name), cmp.Compare(a.entityID, b.entityID))
+	})
+
+	if len(e.sigTypes) > 0 {
+		e.addAnchor(mdSignalTypesHeader, mdSignalTypesHeader)
+	}
+
+	if len(e.sigUnits) > 0 {
+		e.addAnchor(mdSignalUnitsHeader, mdSignalUnitsHeader)
+	}
+
+	if len(e.sigEnums) > 0 {
+		e.addAnchor(mdSignalEnumsHeader, mdSignalEnumsHeader)
+		for _, sigEnum := range e.sigEnums {
+			e.addAnchor(sigEnum, sigEnum.name)
+		}
+	}
+}
+
+func (e *mdExporter) collectLayout(layout *SignalLayout) {
+	for _, sig := range layout.Signals() {
+		switch sig.Kind() {
+		case SignalKindStandard:
+			stdSig, err := sig.ToStandard()
+			if err != nil {
+				panic(err)
+			}
+
+			if sigType := stdSig.typ; !e.seenEntities[sigType.entityID] {
+				e.seenEntities[sigType.entityID] = true
+				e.sigTypes = append(e.sigTypes, sigType)
+			}
+
+			if sigUnit := stdSig.unit; sigUnit != nil && !e.seenEntities[sigUnit.entityID] {
+				e.seenEntities[sigUnit.entityID] = true
+				e.sigUnits = append(e.sigUnits, sigUnit)
+			}
+
+		case SignalKindEnum:
+			enumSig, err := sig.ToEnum()
+			if err != nil {
+				panic(err)
+			}
+
+			if sigEnum := enumSig.enum; !e.seenEntities[sigEnum.entityID] {
+				e.seenEntities[sigEnum.entityID] = true
+				e.sigEnums = append(e.sigEnums, sigEnum)
+			}
+
+		case SignalKindMuxor:
+			muxLayer, ok := layout.muxLayers.Get(sig.EntityID())
+			if !ok {
+				continue
+			}
+
+			for _, muxLayout := range muxLayer.iterLayouts() {
+				e.collectLayout(muxLayout)
+			}
+		}
+	}
+}
+
+func (e *mdExporter) exportNetwork(net *Network) {
+	e.collect(net)
+
+	e.println("> [!IMPORTANT]")
+	e.println("> This markdown document is generated by [acmelib](https://github.com/squadracorsepolito/acmelib)")
+	e.newLine()
+
+	e.header(1, net.name)
+
+	if len(net.desc) > 0 {
+		e.paragraph(net.desc)
+		e.horizontalRule()
+	}
+
+	e.exportTOC(net)
+
+	for _, bus := range net.Buses() {
+		e.exportBus(bus)
+	}
+
+	e.exportSignalTypes()
+	e.exportSignalUnits()
+	e.exportSignalEnums()
+}
+
+func (e *mdExporter) exportTOC(net *Network) {
+	for _, bus := range net.Buses() {
+		e.println("- %s", e.link(bus.name, e.anchors[bus]))
+
+		for _, nodeInt := range bus.NodeInterfaces() {
+			e.println("  - %s", e.link(nodeInt.node.name, e.anchors[nodeInt]))
+
+			for _, msg := range nodeInt.SentMessages() {
+				e.println("    - %s", e.link(msg.name, e.anchors[msg]))
+			}
+		}
+	}
+
+	for _, appendix := range []string{mdSignalTypesHeader, mdSignalUnitsHeader, mdSignalEnumsHeader} {
+		if anchor, ok := e.anchors[appendix]; ok {
+			e.println("- %s", e.link(appendix, anchor))
+		}
+	}
+
+	e.newLine()
+}
+
+func (e *mdExporter) exportBus(bus *Bus) {
+	e.header(2, bus.name)
+
+	if len(bus.desc) > 0 {
+		e.paragraph(bus.desc)
+		e.horizontalRule()
+	}
+
+	e.println("- Type: %s", e.bold(bus.typ.String()))
+
+	baudrateStr := "-"
+	if bus.baudrate != 0 {
+		baudrateStr = e.bold(strconv.Itoa(bus.baudrate)) + " bps"
+	}
+	e.println("- Baudrate: %s", baudrateStr)
+
+	if bus.typ == BusTypeCANFD && bus.dataBaudrate != 0 {
+		e.println("- Data Baudrate: %s bps", e.bold(strconv.Itoa(bus.dataBaudrate)))
+	}
+
+	e.newLine()
+
+	for _, nodeInt := range bus.NodeInterfaces() {
+		e.exportNodeInterface(nodeInt)
+	}
+}
+
+func (e *mdExporter) exportNodeInterface(nodeInt *NodeInterface) {
+	node := nodeInt.node
+
+	e.horizontalRule()
+	e.header(3, node.name)
+
+	if len(node.desc) > 0 {
+		e.paragraph(node.desc)
+		e.horizontalRule()
+	}
+
+	e.println("- Node ID: %s (dec), %s (hex)", e.bold(strconv.FormatUint(uint64(node.id), 10)), e.bold(e.hex(uint64(node.id))))
+	e.println("- Interface: %s", e.bold(strconv.Itoa(nodeInt.number)))
+	e.newLine()
+
+	msgs := nodeInt.SentMessages()
+	if len(msgs) == 0 {
+		return
+	}
+
+	rows := [][]string{}
+	for _, msg := range msgs {
+		cycleTimeStr := "-"
+		if msg.cycleTime > 0 {
+			cycleTimeStr = fmt.Sprintf("%d ms", msg.cycleTime)
+		}
+
+		rows = append(rows, []string{
+			e.link(msg.name, e.anchors[msg]),
+			e.code(e.hex(uint64(msg.GetCANID()))),
+			strconv.Itoa(msg.sizeByte),
+			cycleTimeStr,
+			msg.sendType.String(),
+		})
+	}
+	e.table([]string{"Message", "CAN-ID", "Size (bytes)", "Cycle Time", "Send Type"}, rows)
+
+	for _, msg := range msgs {
+		e.exportMessage(msg)
+	}
+}
+
+func (e *mdExporter) exportMessage(msg *Message) {
+	e.horizontalRule()
+	e.header(4, msg.name)
+
+	if len(msg.desc) > 0 {
+		e.paragraph(msg.desc)
+		e.horizontalRule()
+	}
+
+	canID := msg.GetCANID()
+	prefixStr := "(static)"
+	if !msg.hasStaticCANID {
+		prefixStr = "(generated)"
+	}
+	e.println("- CAN-ID %s: %s (dec), %s (hex)", prefixStr, e.bold(strconv.FormatUint(uint64(canID), 10)), e.bold(e.hex(uint64(canID))))
+
+	if !msg.hasStaticCANID {
+		e.println("- Message ID: %s (dec), %s (hex)", e.bold(strconv.FormatUint(uint64(msg.id), 10)), e.bold(e.hex(uint64(msg.id))))
+	}
+
+	e.println("- CAN-ID Format: %s", e.bold(msg.canIDFormat.String()))
+	e.println("- Size: %s bytes", e.bold(strconv.Itoa(msg.sizeByte)))
+
+	cycleTimeStr := "-"
+	if msg.cycleTime > 0 {
+		cycleTimeStr = e.bold(strconv.Itoa(msg.cycleTime)) + " ms"
+	}
+	e.println("- Cycle Time: %s", cycleTimeStr)
+	e.println("- Send Type: %s", e.bold(msg.sendType.String()))
+
+	receivers := []string{}
+	for _, recInt := range msg.Receivers() {
+		receivers = append(receivers, e.link(recInt.node.name, e.anchors[recInt]))
+	}
+	recStr := "-"
+	if len(receivers) > 0 {
+		recStr = strings.Join(receivers, ", ")
+	}
+	e.println("- Receivers: %s", recStr)
+	e.newLine()
+
+	e.exportSignalLayout(msg.layout, "")
+}
+
+// exportSignalLayout exports the signals of the layout and then the multiplexed layers.
+// The path describes the values of the muxor signals that select the layout.
+func (e *mdExporter) exportSignalLayout(layout *SignalLayout, path string) {
+	sigs := layout.Signals()
+	if len(sigs) == 0 {
+		return
+	}
+
+	rows := [][]string{}
+	for _, sig := range sigs {
+		rows = append(rows, e.exportSignal(sig))
+	}
+	e.table([]string{"Name", "Start Bit", "Size", "Endianness", "Type", "Min", "Max", "Unit", "Enum", "Description"}, rows)
+
+	for _, muxLayer := range layout.MultiplexedLayers() {
+		muxorName := muxLayer.muxor.name
+
+		for layoutID, muxLayout := range muxLayer.iterLayouts() {
+			if muxLayout.SignalCount() == 0 {
+				continue
+			}
+
+			layoutPath := fmt.Sprintf("%s = %d", e.code(muxorName), layoutID)
+			if len(path) > 0 {
+				layoutPath = path + " / " + layoutPath
+			}
+
+			e.paragraph(e.bold("Layout "+strconv.Itoa(layoutID)) + ": " + layoutPath)
+			e.exportSignalLayout(muxLayout, layoutPath)
+		}
+	}
+}
+
+func (e *mdExporter) exportSignal(sig Signal) []string {
+	row := []string{
+		sig.Name(),
+		strconv.Itoa(sig.StartPos()),
+		strconv.Itoa(sig.Size()),
+		sig.Endianness().String(),
+	}
+
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		sigType := stdSig.typ
+		unitStr := ""
+		if sigUnit := stdSig.unit; sigUnit != nil {
+			unitStr = e.link(e.code(sigUnit.symbol), e.anchors[mdSignalUnitsHeader])
+		}
+
+		row = append(row,
+			e.link(e.code(sigType.name), e.anchors[mdSignalTypesHeader]),
+			fmt.Sprintf("%g", sigType.min),
+			fmt.Sprintf("%g", sigType.max),
+			unitStr,
+			"",
+		)
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		sigEnum := enumSig.enum
+		row = append(row,
+			e.code("enum"),
+			"0",
+			strconv.Itoa(sigEnum.maxIndex),
+			"",
+			e.link(sigEnum.name, e.anchors[sigEnum]),
+		)
+
+	case SignalKindMuxor:
+		muxor, err := sig.ToMuxor()
+		if err != nil {
+			panic(err)
+		}
+
+		row = append(row,
+			e.code("muxor"),
+			"0",
+			strconv.Itoa(muxor.layoutCount-1),
+			"",
+			"",
+		)
+	}
+
+	return append(row, sig.Desc())
+}
+
+func (e *mdExporter) exportSignalTypes() {
+	if len(e.sigTypes) == 0 {
+		return
+	}
+
+	e.horizontalRule()
+	e.header(2, mdSignalTypesHeader)
+	e.paragraph("The list of all the signal types used in the network.")
+
+	rows := [][]string{}
+	for _, sigType := range e.sigTypes {
+		rows = append(rows, []string{
+			sigType.name,
+			e.code(sigType.kind.String()),
+			strconv.Itoa(sigType.size),
+			strconv.FormatBool(sigType.signed),
+			fmt.Sprintf("%g", sigType.min),
+			fmt.Sprintf("%g", sigType.max),
+			fmt.Sprintf("%g", sigType.scale),
+			fmt.Sprintf("%g", sigType.offset),
+			sigType.desc,
+		})
+	}
+	e.table([]string{"Name", "Kind", "Size", "Signed", "Min", "Max", "Scale", "Offset", "Description"}, rows)
+}
+
+func (e *mdExporter) exportSignalUnits() {
+	if len(e.sigUnits) == 0 {
+		return
+	}
+
+	e.horizontalRule()
+	e.header(2, mdSignalUnitsHeader)
+	e.paragraph("The list of all the signal units used in the network.")
+
+	rows := [][]string{}
+	for _, sigUnit := range e.sigUnits {
+		rows = append(rows, []string{
+			sigUnit.name,
+			e.code(sigUnit.kind.String()),
+			e.code(sigUnit.symbol),
+			sigUnit.desc,
+		})
+	}
+	e.table([]string{"Name", "Kind", "Symbol", "Description"}, rows)
+}
+
+func (e *mdExporter) exportSignalEnums() {
+	if len(e.sigEnums) == 0 {
+		return
+	}
+
+	e.horizontalRule()
+	e.header(2, mdSignalEnumsHeader)
+	e.paragraph("The list of all the signal enums used in the network.")
+
+	for _, sigEnum := range e.sigEnums {
+		e.header(3, sigEnum.name)
+		e.paragraph(sigEnum.desc)
+
+		values := slices.Clone(sigEnum.values)
+		slices.SortFunc(values, func(a, b *SignalEnumValue) int {
+			return cmp.Compare(a.index, b.index)
+		})
+
+		rows := [][]string{}
+		for _, val := range values {
+			rows = append(rows, []string{strconv.Itoa(val.index), val.name, val.desc})
+		}
+		e.table([]string{"Index", "Name", "Description"}, rows)
+	}
+}
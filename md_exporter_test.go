@@ -0,0 +1,60 @@
+package acmelib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportToMarkdown(t *testing.T) {
+	assert := assert.New(t)
+
+	tdNet := initNetwork(assert)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(ExportToMarkdown(tdNet.net, buf))
+
+	content := buf.String()
+	assert.True(strings.HasPrefix(content, "> [!IMPORTANT]\n"))
+	assert.Contains(content, "\n# network\n")
+
+	// table of contents
+	assert.Contains(content, "- [bus](#bus)\n  - [node_0](#node_0)\n    - [basic_message](#basic_message)\n")
+	assert.Contains(content, "- [Signal Types](#signal-types)\n- [Signal Units](#signal-units)\n- [Signal Enums](#signal-enums)\n")
+
+	// message table
+	assert.Contains(content, "| Message | CAN-ID | Size (bytes) | Cycle Time | Send Type |\n")
+	assert.Contains(content, "| [mux_message](#mux_message) | `0x10` | 8 | - | unset |\n")
+
+	// signal tables
+	assert.Contains(content, "| basic_signal_0 | 0 | 12 | little-endian | [`uint12_t`](#signal-types) | 0 | 4095 | - | - | - |\n")
+	assert.Contains(content, "| top_muxor | 0 | 8 | little-endian | `muxor` | 0 | 255 | - | - | - |\n")
+	assert.Contains(content, "- Receivers: [rec_node_0](#rec_node_0)\n")
+
+	// multiplexed layers
+	assert.Contains(content, "**Layout 0**: `top_muxor` = 0\n")
+	assert.Contains(content, "**Layout 255**: `top_muxor` = 1 / `top_inner_muxor` = 255\n")
+	assert.NotContains(content, "**Layout 3**: `top_muxor` = 3\n")
+
+	// appendices
+	assert.Contains(content, "\n## Signal Types\n")
+	assert.Contains(content, "| uint12_t | `integer` | 12 | false | 0 | 4095 | 1 | 0 | - |\n")
+	assert.Contains(content, "| voltage | `electrical` | `V` | - |\n")
+
+	// should generate the same document
+	expBuf := new(bytes.Buffer)
+	assert.NoError(ExportToMarkdown(tdNet.net, expBuf))
+	assert.Equal(content, expBuf.String())
+}
+
+func Test_mdExporter_addAnchor(t *testing.T) {
+	assert := assert.New(t)
+
+	e := newMDExporter(new(bytes.Buffer))
+	assert.Equal("signal-types", e.addAnchor(nil, "Signal Types"))
+	assert.Equal("engine_status", e.addAnchor(nil, "Engine_Status"))
+	assert.Equal("engine_status-1", e.addAnchor(nil, "engine_status"))
+	assert.Equal("speed-kmh", e.addAnchor(nil, "Speed (km/h)"))
+}
@@ -0,0 +1,312 @@
+package mdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+const (
+	// idBlockSize is the size of the identification block at the start of the file.
+	idBlockSize = 64
+	// blockHeaderSize is the size of the header of each block:
+	// id (4 bytes), reserved (4 bytes), length (u64) and link count (u64).
+	blockHeaderSize = 24
+
+	// formatVersion is the version of the MDF format written.
+	formatVersion = 410
+	// formatVersionStr is the version written in the identification block.
+	formatVersionStr = "4.10    "
+	// programID is the id of the program written in the identification block.
+	programID = "acmelib "
+)
+
+// Data types of the channels.
+const (
+	dataTypeUintLE    uint8 = 0
+	dataTypeIntLE     uint8 = 2
+	dataTypeFloatLE   uint8 = 4
+	dataTypeByteArray uint8 = 10
+)
+
+// Types of the channels.
+const (
+	channelTypeFixed  uint8 = 0
+	channelTypeMaster uint8 = 2
+)
+
+// Synchronization types of the channels.
+const (
+	syncTypeNone uint8 = 0
+	syncTypeTime uint8 = 1
+)
+
+// Flags of the channels.
+const (
+	channelFlagInvalBitValid  uint32 = 0x0002
+	channelFlagLimitRangeOK   uint32 = 0x0010
+	channelFlagBusEvent       uint32 = 0x0400
+	channelFlagDiscreteValues uint32 = 0x0040
+)
+
+// Flags of the channel groups.
+const (
+	channelGroupFlagBusEvent      uint16 = 0x0002
+	channelGroupFlagPlainBusEvent uint16 = 0x0004
+)
+
+// Types of the conversions.
+const (
+	conversionTypeLinear      uint8 = 1
+	conversionTypeValueToText uint8 = 7
+)
+
+// Types of the source information.
+const (
+	sourceTypeBus uint8 = 2
+	busTypeCAN    uint8 = 2
+)
+
+var le = binary.LittleEndian
+
+// blockBuilder builds the blocks of the metadata in memory.
+// The links of the blocks can be set after the blocks are added,
+// so the blocks can refer to the ones added later.
+type blockBuilder struct {
+	buf  *bytes.Buffer
+	base uint64
+}
+
+func newBlockBuilder(base uint64) *blockBuilder {
+	return &blockBuilder{
+		buf:  new(bytes.Buffer),
+		base: base,
+	}
+}
+
+// add adds a block with the given id, links and data, and it returns its offset.
+// The blocks are aligned to 8 bytes.
+func (bb *blockBuilder) add(id string, links []uint64, data []byte) uint64 {
+	offset := bb.base + uint64(bb.buf.Len())
+	length := blockHeaderSize + 8*len(links) + len(data)
+
+	header := make([]byte, blockHeaderSize)
+	copy(header[0:], "##"+id)
+	le.PutUint64(header[8:], uint64(length))
+	le.PutUint64(header[16:], uint64(len(links)))
+	bb.buf.Write(header)
+
+	for _, link := range links {
+		bb.buf.Write(le.AppendUint64(nil, link))
+	}
+
+	bb.buf.Write(data)
+
+	if padding := length % 8; padding != 0 {
+		bb.buf.Write(make([]byte, 8-padding))
+	}
+
+	return offset
+}
+
+// setLink sets the link with the given index of the block at the given offset.
+func (bb *blockBuilder) setLink(blockOffset uint64, linkIdx int, target uint64) {
+	pos := blockOffset - bb.base + blockHeaderSize + uint64(linkIdx)*8
+	le.PutUint64(bb.buf.Bytes()[pos:], target)
+}
+
+// addText adds a TX block with the given text and it returns its offset.
+// It returns a nil link if the text is empty.
+func (bb *blockBuilder) addText(text string) uint64 {
+	if len(text) == 0 {
+		return 0
+	}
+	return bb.add("TX", nil, append([]byte(text), 0))
+}
+
+// addMetadata adds a MD block with the given XML and it returns its offset.
+func (bb *blockBuilder) addMetadata(xml string) uint64 {
+	return bb.add("MD", nil, append([]byte(xml), 0))
+}
+
+// encodeIDBlock returns the identification block.
+func encodeIDBlock() []byte {
+	buf := make([]byte, idBlockSize)
+	copy(buf[0:], "MDF     ")
+	copy(buf[8:], formatVersionStr)
+	copy(buf[16:], programID)
+	le.PutUint16(buf[28:], formatVersion)
+	return buf
+}
+
+// channelBlock holds the fields of a CN block.
+type channelBlock struct {
+	name        string
+	unit        string
+	comment     string
+	typ         uint8
+	syncType    uint8
+	dataType    uint8
+	bitOffset   uint8
+	byteOffset  uint32
+	bitCount    uint32
+	flags       uint32
+	invalBitPos uint32
+	limitMin    float64
+	limitMax    float64
+	conversion  uint64
+	composition uint64
+}
+
+// Indexes of the links of the CN block.
+const (
+	cnLinkNext = iota
+)
+
+// addChannel adds a CN block and it returns its offset.
+func (bb *blockBuilder) addChannel(cn *channelBlock) uint64 {
+	name := bb.addText(cn.name)
+	unit := bb.addText(cn.unit)
+	comment := bb.addText(cn.comment)
+
+	links := []uint64{
+		0, // next channel
+		cn.composition,
+		name,
+		0, // source
+		cn.conversion,
+		0, // data
+		unit,
+		comment,
+	}
+
+	data := make([]byte, 72)
+	data[0] = cn.typ
+	data[1] = cn.syncType
+	data[2] = cn.dataType
+	data[3] = cn.bitOffset
+	le.PutUint32(data[4:], cn.byteOffset)
+	le.PutUint32(data[8:], cn.bitCount)
+	le.PutUint32(data[12:], cn.flags)
+	le.PutUint32(data[16:], cn.invalBitPos)
+	// precision, reserved and attachment count are zero
+	// while the value range is not known
+	le.PutUint64(data[40:], math.Float64bits(cn.limitMin))
+	le.PutUint64(data[48:], math.Float64bits(cn.limitMax))
+
+	return bb.add("CN", links, data)
+}
+
+// addChannels adds the given CN blocks linked in order,
+// and it returns the offset of the first one.
+func (bb *blockBuilder) addChannels(channels []*channelBlock) uint64 {
+	first := uint64(0)
+	prev := uint64(0)
+
+	for _, cn := range channels {
+		offset := bb.addChannel(cn)
+
+		if prev == 0 {
+			first = offset
+		} else {
+			bb.setLink(prev, cnLinkNext, offset)
+		}
+		prev = offset
+	}
+
+	return first
+}
+
+// addLinearConversion adds a CC block that converts the raw values
+// with the given scale and offset, and it returns its offset.
+func (bb *blockBuilder) addLinearConversion(scale, offset float64) uint64 {
+	links := []uint64{
+		0, // name
+		0, // unit
+		0, // comment
+		0, // inverse
+	}
+
+	data := make([]byte, 24+16)
+	data[0] = conversionTypeLinear
+	le.PutUint16(data[6:], 2)
+	le.PutUint64(data[24:], math.Float64bits(offset))
+	le.PutUint64(data[32:], math.Float64bits(scale))
+
+	return bb.add("CC", links, data)
+}
+
+// addValueToTextConversion adds a CC block that converts the given raw values
+// to the given texts, and it returns its offset.
+func (bb *blockBuilder) addValueToTextConversion(values []int, texts []string) uint64 {
+	links := []uint64{
+		0, // name
+		0, // unit
+		0, // comment
+		0, // inverse
+	}
+
+	for _, text := range texts {
+		links = append(links, bb.addText(text))
+	}
+	// the default text is not defined
+	links = append(links, 0)
+
+	data := make([]byte, 24, 24+8*len(values))
+	data[0] = conversionTypeValueToText
+	le.PutUint16(data[4:], uint16(len(texts)+1))
+	le.PutUint16(data[6:], uint16(len(values)))
+
+	for _, value := range values {
+		data = le.AppendUint64(data, math.Float64bits(float64(value)))
+	}
+
+	return bb.add("CC", links, data)
+}
+
+// addSource adds a SI block of a CAN bus with the given name and path,
+// and it returns its offset.
+func (bb *blockBuilder) addSource(name, path string) uint64 {
+	links := []uint64{
+		bb.addText(name),
+		bb.addText(path),
+		0, // comment
+	}
+
+	data := make([]byte, 8)
+	data[0] = sourceTypeBus
+	data[1] = busTypeCAN
+
+	return bb.add("SI", links, data)
+}
+
+// Indexes of the links of the CG block.
+const (
+	cgLinkNext = iota
+)
+
+// addChannelGroup adds a CG block and it returns its offset.
+func (bb *blockBuilder) addChannelGroup(cg *channelGroup, firstChannel, acqName, source, comment uint64, flags uint16, pathSeparator uint16) uint64 {
+	links := []uint64{
+		0, // next channel group
+		firstChannel,
+		acqName,
+		source,
+		0, // sample reduction
+		comment,
+	}
+
+	data := make([]byte, 32)
+	le.PutUint64(data[0:], cg.recordID)
+	le.PutUint16(data[16:], flags)
+	le.PutUint16(data[18:], pathSeparator)
+	le.PutUint32(data[24:], uint32(cg.dataBytes))
+	le.PutUint32(data[28:], uint32(cg.invalBytes))
+
+	cg.cgOffset = bb.add("CG", links, data)
+
+	return cg.cgOffset
+}
+
+// cgCycleCountPos is the position of the cycle count within a CG block.
+const cgCycleCountPos = blockHeaderSize + 6*8 + 8
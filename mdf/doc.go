@@ -0,0 +1,11 @@
+// Package mdf writes the frames of a bus as ASAM MDF 4.1 files (.mf4),
+// which can be opened by tools like asammdf and CANape.
+//
+// The frames are decoded with the messages of an [acmelib.Network]:
+// each message is stored in a channel group with a channel for each signal,
+// where the raw values are converted to physical values by the conversion rules
+// built from the [acmelib.SignalType] (scale and offset) and from the
+// [acmelib.SignalEnum] (value to text), and the units come from the [acmelib.SignalUnit].
+// Optionally, the frames are also stored as raw CAN_DataFrame records
+// as defined by the ASAM MDF bus logging specification.
+package mdf
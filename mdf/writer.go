@@ -0,0 +1,640 @@
+package mdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+const (
+	// busLoggingGroupName is the name of the channel group of the raw frames.
+	busLoggingGroupName = "CAN_DataFrame"
+	// busLoggingDataBytes is the size of the records of the raw frames,
+	// made of the timestamp and of the CAN_DataFrame structure.
+	busLoggingDataBytes = 8 + 72
+)
+
+// WriterOptions are the options of a [Writer].
+type WriterOptions struct {
+	// StartTime is the start time of the measurement written in the header.
+	// If it is zero, the timestamp of the first frame is used.
+	StartTime time.Time
+	// Channels associates the channel of the frames to the name of a bus,
+	// like in [acmelib.Network.NewChannelDecoder]. If it is nil, the frames
+	// of all the channels are decoded with all the messages of the network.
+	Channels map[string]string
+	// BusLogging reports whether the frames are also written as raw
+	// CAN_DataFrame records, including the ones that do not match any message.
+	BusLogging bool
+}
+
+// signalChannel is the channel of a signal in the records of a message.
+type signalChannel struct {
+	byteOffset  int
+	byteSize    int
+	invalBitPos int
+}
+
+// channelGroup is the channel group of a message or of the raw frames.
+type channelGroup struct {
+	recordID  uint64
+	cgOffset  uint64
+	dataBytes int
+	// invalBytes is the number of bytes of the invalidation bits
+	invalBytes int
+
+	cycleCount uint64
+	channels   map[acmelib.EntityID]*signalChannel
+}
+
+// Writer writes the frames as records of an MDF file.
+// The metadata (header, channel groups, channels and conversions)
+// are written by [NewWriter] and the records follow in a single data block
+// with a record id for each channel group. The number of records of each
+// channel group and the size of the data block are written by [Writer.Close],
+// which must be called at the end.
+//
+// The data frames are decoded by the decoder of the network, while the remote
+// and the error frames are skipped. Each record of a message holds the time
+// in seconds from the start of the measurement and the raw values of the signals.
+// The signals that are not decoded, like the ones of the inactive multiplexed layouts,
+// are marked as invalid.
+type Writer struct {
+	w    io.WriteSeeker
+	bw   *bufio.Writer
+	opts WriterOptions
+
+	decoder    *acmelib.Decoder
+	chDecoder  *acmelib.ChannelDecoder
+	groups     map[acmelib.EntityID]*channelGroup
+	busGroup   *channelGroup
+	recIDSize  int
+	dataOffset uint64
+	dataSize   uint64
+
+	channels    map[string]int
+	usedChannel map[int]bool
+}
+
+// NewWriter creates a new [Writer] that writes to the given writer the frames
+// of the buses of the given network. The options can be nil.
+// It writes the metadata of the file immediately.
+//
+// It returns:
+//   - [acmelib.NameError] if a bus of the options does not match any bus of the network.
+//   - [acmelib.CANIDError] if two messages decoded together have the same CAN-ID.
+func NewWriter(w io.WriteSeeker, network *acmelib.Network, opts *WriterOptions) (*Writer, error) {
+	if opts == nil {
+		opts = &WriterOptions{}
+	}
+
+	mw := &Writer{
+		w:    w,
+		bw:   bufio.NewWriter(w),
+		opts: *opts,
+
+		groups: make(map[acmelib.EntityID]*channelGroup),
+
+		channels:    make(map[string]int),
+		usedChannel: make(map[int]bool),
+	}
+
+	if opts.Channels != nil {
+		chDec, err := network.NewChannelDecoder(opts.Channels)
+		if err != nil {
+			return nil, err
+		}
+		mw.chDecoder = chDec
+	} else {
+		dec, err := network.NewDecoder()
+		if err != nil {
+			return nil, err
+		}
+		mw.decoder = dec
+	}
+
+	if err := mw.writeMetadata(network); err != nil {
+		return nil, err
+	}
+
+	return mw, nil
+}
+
+// getSlotSize returns the number of bytes used to store a raw value of the given size.
+func getSlotSize(size int) int {
+	switch {
+	case size <= 8:
+		return 1
+	case size <= 16:
+		return 2
+	case size <= 32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// getLayoutSignals appends the signals of the layout and of its multiplexed layers.
+// The signals shared by more multiplexed layouts are appended once.
+func getLayoutSignals(signals []acmelib.Signal, visited map[acmelib.EntityID]bool, layout *acmelib.SignalLayout) []acmelib.Signal {
+	for _, sig := range layout.Signals() {
+		if visited[sig.EntityID()] {
+			continue
+		}
+		visited[sig.EntityID()] = true
+		signals = append(signals, sig)
+	}
+
+	for _, muxLayer := range layout.MultiplexedLayers() {
+		for _, muxLayout := range muxLayer.Layouts() {
+			signals = getLayoutSignals(signals, visited, muxLayout)
+		}
+	}
+
+	return signals
+}
+
+// newSignalChannelBlock returns the channel block of the given signal.
+func newSignalChannelBlock(bb *blockBuilder, sig acmelib.Signal, byteOffset, invalBitPos int) *channelBlock {
+	cn := &channelBlock{
+		name:        sig.Name(),
+		comment:     sig.Desc(),
+		typ:         channelTypeFixed,
+		syncType:    syncTypeNone,
+		dataType:    dataTypeUintLE,
+		byteOffset:  uint32(byteOffset),
+		bitCount:    uint32(getSlotSize(sig.Size()) * 8),
+		flags:       channelFlagInvalBitValid,
+		invalBitPos: uint32(invalBitPos),
+	}
+
+	switch sig.Kind() {
+	case acmelib.SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		if unit := stdSig.Unit(); unit != nil {
+			cn.unit = unit.Symbol()
+		}
+
+		typ := stdSig.Type()
+		switch {
+		case typ.Kind() == acmelib.SignalTypeKindFloat:
+			cn.dataType = dataTypeFloatLE
+		case typ.Kind() == acmelib.SignalTypeKindFlag:
+			cn.flags |= channelFlagDiscreteValues
+		case typ.Signed():
+			cn.dataType = dataTypeIntLE
+		}
+
+		if typ.Scale() != 1 || typ.Offset() != 0 {
+			cn.conversion = bb.addLinearConversion(typ.Scale(), typ.Offset())
+		}
+
+		if typ.Min() < typ.Max() {
+			cn.flags |= channelFlagLimitRangeOK
+			cn.limitMin = typ.Min()
+			cn.limitMax = typ.Max()
+		}
+
+	case acmelib.SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		cn.flags |= channelFlagDiscreteValues
+
+		values := []int{}
+		texts := []string{}
+		for _, val := range enumSig.Enum().Values() {
+			values = append(values, val.Index())
+			texts = append(texts, val.Name())
+		}
+
+		if len(values) > 0 {
+			cn.conversion = bb.addValueToTextConversion(values, texts)
+		}
+
+	case acmelib.SignalKindMuxor:
+		cn.flags |= channelFlagDiscreteValues
+	}
+
+	return cn
+}
+
+// newTimeChannelBlock returns the master channel of a channel group.
+func newTimeChannelBlock(name string) *channelBlock {
+	return &channelBlock{
+		name:     name,
+		unit:     "s",
+		typ:      channelTypeMaster,
+		syncType: syncTypeTime,
+		dataType: dataTypeFloatLE,
+		bitCount: 64,
+	}
+}
+
+// addMessageGroup adds the channel group of the given message.
+func (mw *Writer) addMessageGroup(bb *blockBuilder, msg *acmelib.Message, recordID uint64, source uint64) (*channelGroup, uint64) {
+	signals := getLayoutSignals(nil, make(map[acmelib.EntityID]bool), msg.SignalLayout())
+
+	cg := &channelGroup{
+		recordID:   recordID,
+		dataBytes:  8,
+		invalBytes: (len(signals) + 7) / 8,
+		channels:   make(map[acmelib.EntityID]*signalChannel, len(signals)),
+	}
+
+	channelBlocks := []*channelBlock{newTimeChannelBlock("Timestamp")}
+	for idx, sig := range signals {
+		sigCh := &signalChannel{
+			byteOffset:  cg.dataBytes,
+			byteSize:    getSlotSize(sig.Size()),
+			invalBitPos: idx,
+		}
+		cg.channels[sig.EntityID()] = sigCh
+		cg.dataBytes += sigCh.byteSize
+
+		channelBlocks = append(channelBlocks, newSignalChannelBlock(bb, sig, sigCh.byteOffset, idx))
+	}
+
+	firstChannel := bb.addChannels(channelBlocks)
+	offset := bb.addChannelGroup(cg, firstChannel, bb.addText(msg.Name()), source, bb.addText(msg.Desc()), 0, 0)
+
+	return cg, offset
+}
+
+// addBusLoggingGroup adds the channel group of the raw frames,
+// as defined by the ASAM MDF bus logging specification.
+func (mw *Writer) addBusLoggingGroup(bb *blockBuilder, recordID uint64) (*channelGroup, uint64) {
+	cg := &channelGroup{
+		recordID:   recordID,
+		dataBytes:  busLoggingDataBytes,
+		invalBytes: 0,
+	}
+
+	field := func(name string, byteOffset, bitOffset, bitCount int) *channelBlock {
+		return &channelBlock{
+			name:       busLoggingGroupName + "." + name,
+			typ:        channelTypeFixed,
+			syncType:   syncTypeNone,
+			dataType:   dataTypeUintLE,
+			byteOffset: uint32(byteOffset),
+			bitOffset:  uint8(bitOffset),
+			bitCount:   uint32(bitCount),
+			flags:      channelFlagBusEvent,
+		}
+	}
+
+	dataBytes := field("DataBytes", 16, 0, 64*8)
+	dataBytes.dataType = dataTypeByteArray
+
+	firstField := bb.addChannels([]*channelBlock{
+		field("BusChannel", 8, 0, 8),
+		field("IDE", 9, 0, 1),
+		field("Dir", 9, 1, 1),
+		field("EDL", 9, 2, 1),
+		field("BRS", 9, 3, 1),
+		field("ESI", 9, 4, 1),
+		field("DLC", 10, 0, 4),
+		field("DataLength", 11, 0, 8),
+		field("ID", 12, 0, 29),
+		dataBytes,
+	})
+
+	firstChannel := bb.addChannels([]*channelBlock{
+		newTimeChannelBlock("Timestamp"),
+		{
+			name:        busLoggingGroupName,
+			typ:         channelTypeFixed,
+			syncType:    syncTypeNone,
+			dataType:    dataTypeByteArray,
+			byteOffset:  8,
+			bitCount:    72 * 8,
+			flags:       channelFlagBusEvent,
+			composition: firstField,
+		},
+	})
+
+	source := bb.addSource("CAN", "CAN")
+	offset := bb.addChannelGroup(cg, firstChannel, bb.addText(busLoggingGroupName), source, 0,
+		channelGroupFlagBusEvent|channelGroupFlagPlainBusEvent, '.')
+
+	return cg, offset
+}
+
+// Indexes of the links of the HD block.
+const (
+	hdLinkFirstDG = iota
+	hdLinkFirstFH
+)
+
+// dgLinkData is the index of the link of the DG block to the data block.
+const dgLinkData = 2
+
+// hdStartTimePos is the position of the start time within the file.
+const hdStartTimePos = idBlockSize + blockHeaderSize + 6*8
+
+// writeMetadata writes the metadata of the file and the header of the data block.
+func (mw *Writer) writeMetadata(network *acmelib.Network) error {
+	bb := newBlockBuilder(idBlockSize)
+
+	// the start time is written by Close
+	hd := bb.add("HD", make([]uint64, 6), make([]byte, 32))
+
+	fhData := make([]byte, 16)
+	le.PutUint64(fhData[0:], uint64(time.Now().UnixNano()))
+	fhComment := bb.addMetadata(fmt.Sprintf("<FHcomment><TX>%s</TX><tool_id>acmelib</tool_id>"+
+		"<tool_vendor>squadracorsepolito</tool_vendor><tool_version></tool_version></FHcomment>", network.Name()))
+	fh := bb.add("FH", []uint64{0, fhComment}, fhData)
+
+	recordID := uint64(1)
+	firstCG := uint64(0)
+	prevCG := uint64(0)
+	appendGroup := func(offset uint64) {
+		if prevCG == 0 {
+			firstCG = offset
+		} else {
+			bb.setLink(prevCG, cgLinkNext, offset)
+		}
+		prevCG = offset
+		recordID++
+	}
+
+	for _, bus := range network.Buses() {
+		source := bb.addSource(bus.Name(), network.Name()+"."+bus.Name())
+
+		for _, nodeInt := range bus.NodeInterfaces() {
+			for _, msg := range nodeInt.SentMessages() {
+				cg, offset := mw.addMessageGroup(bb, msg, recordID, source)
+				mw.groups[msg.EntityID()] = cg
+				appendGroup(offset)
+			}
+		}
+	}
+
+	if mw.opts.BusLogging {
+		cg, offset := mw.addBusLoggingGroup(bb, recordID)
+		mw.busGroup = cg
+		appendGroup(offset)
+	}
+
+	mw.recIDSize = 1
+	if recordID > math.MaxUint8 {
+		mw.recIDSize = 2
+	}
+
+	dgData := make([]byte, 8)
+	dgData[0] = uint8(mw.recIDSize)
+	dg := bb.add("DG", []uint64{0, firstCG, 0, 0}, dgData)
+
+	bb.setLink(hd, hdLinkFirstDG, dg)
+	bb.setLink(hd, hdLinkFirstFH, fh)
+
+	// the data block is the last one, so its size is written by Close
+	mw.dataOffset = bb.add("DT", nil, nil)
+	bb.setLink(dg, dgLinkData, mw.dataOffset)
+
+	if _, err := mw.bw.Write(encodeIDBlock()); err != nil {
+		return err
+	}
+
+	_, err := mw.bw.Write(bb.buf.Bytes())
+	return err
+}
+
+// getChannel returns the number of the given channel.
+func (mw *Writer) getChannel(channel string) int {
+	if ch, ok := mw.channels[channel]; ok {
+		return ch
+	}
+
+	ch, err := strconv.Atoi(channel)
+	if err != nil || ch <= 0 {
+		ch = 1
+		for mw.usedChannel[ch] {
+			ch++
+		}
+	}
+
+	mw.channels[channel] = ch
+	mw.usedChannel[ch] = true
+
+	return ch
+}
+
+// getTime returns the time of the given timestamp in seconds from the start time.
+func (mw *Writer) getTime(timestamp time.Time) float64 {
+	if mw.opts.StartTime.IsZero() {
+		mw.opts.StartTime = timestamp
+		if timestamp.IsZero() {
+			mw.opts.StartTime = time.Now()
+		}
+	}
+
+	if timestamp.IsZero() {
+		return 0
+	}
+
+	return timestamp.Sub(mw.opts.StartTime).Seconds()
+}
+
+// newRecord returns a record of the given channel group with the given time.
+func (mw *Writer) newRecord(cg *channelGroup, seconds float64) ([]byte, []byte) {
+	record := make([]byte, mw.recIDSize+cg.dataBytes+cg.invalBytes)
+
+	if mw.recIDSize == 1 {
+		record[0] = uint8(cg.recordID)
+	} else {
+		le.PutUint16(record, uint16(cg.recordID))
+	}
+
+	data := record[mw.recIDSize:]
+	le.PutUint64(data, math.Float64bits(seconds))
+
+	return record, data
+}
+
+// writeRecord writes the given record of the given channel group.
+func (mw *Writer) writeRecord(cg *channelGroup, record []byte) error {
+	if _, err := mw.bw.Write(record); err != nil {
+		return err
+	}
+
+	cg.cycleCount++
+	mw.dataSize += uint64(len(record))
+
+	return nil
+}
+
+// getDecoder returns the decoder of the channel of the given frame.
+func (mw *Writer) getDecoder(frame *acmelib.Frame) *acmelib.Decoder {
+	if mw.chDecoder == nil {
+		return mw.decoder
+	}
+
+	dec, err := mw.chDecoder.GetDecoder(frame.Channel)
+	if err != nil {
+		return nil
+	}
+	return dec
+}
+
+// WriteFrame writes the given frame. The frame is written as a record
+// of the message that matches its CAN-ID, and as a raw frame record
+// if the bus logging is enabled. The remote and the error frames are skipped.
+func (mw *Writer) WriteFrame(frame *acmelib.Frame) error {
+	if frame.IsRemote || frame.IsError {
+		return nil
+	}
+
+	seconds := mw.getTime(frame.Timestamp)
+
+	if mw.busGroup != nil {
+		if err := mw.writeBusLoggingRecord(frame, seconds); err != nil {
+			return err
+		}
+	}
+
+	dec := mw.getDecoder(frame)
+	if dec == nil {
+		return nil
+	}
+
+	// the frames with a different length are decoded anyway
+	msg, decodings, _ := dec.DecodeFrame(frame.CANID, frame.CANIDFormat(), frame.Data)
+	if msg == nil {
+		return nil
+	}
+
+	cg, ok := mw.groups[msg.EntityID()]
+	if !ok {
+		return nil
+	}
+
+	record, data := mw.newRecord(cg, seconds)
+	inval := data[cg.dataBytes:]
+
+	// all the signals are invalid until decoded
+	for _, sigCh := range cg.channels {
+		inval[sigCh.invalBitPos/8] |= 1 << (sigCh.invalBitPos % 8)
+	}
+
+	for _, sigDec := range decodings {
+		sigCh, ok := cg.channels[sigDec.Signal.EntityID()]
+		if !ok {
+			continue
+		}
+
+		slot := data[sigCh.byteOffset:]
+		switch sigCh.byteSize {
+		case 1:
+			slot[0] = uint8(sigDec.RawValue)
+		case 2:
+			le.PutUint16(slot, uint16(sigDec.RawValue))
+		case 4:
+			le.PutUint32(slot, uint32(sigDec.RawValue))
+		default:
+			le.PutUint64(slot, sigDec.RawValue)
+		}
+
+		inval[sigCh.invalBitPos/8] &^= 1 << (sigCh.invalBitPos % 8)
+	}
+
+	return mw.writeRecord(cg, record)
+}
+
+// writeBusLoggingRecord writes the given frame as a CAN_DataFrame record.
+func (mw *Writer) writeBusLoggingRecord(frame *acmelib.Frame, seconds float64) error {
+	record, data := mw.newRecord(mw.busGroup, seconds)
+
+	data[8] = uint8(mw.getChannel(frame.Channel))
+
+	flags := uint8(0)
+	if frame.IsExtended {
+		flags |= 0x01
+	}
+	if frame.Direction == acmelib.FrameDirectionTx {
+		flags |= 0x02
+	}
+	if frame.IsFD {
+		flags |= 0x04
+	}
+	if frame.BitRateSwitch {
+		flags |= 0x08
+	}
+	if frame.ErrorStateIndicator {
+		flags |= 0x10
+	}
+	data[9] = flags
+
+	// the length of a valid frame is always a valid size
+	dlc, _ := acmelib.SizeByteToDLC(len(frame.Data))
+	data[10] = uint8(dlc)
+	data[11] = uint8(len(frame.Data))
+	le.PutUint32(data[12:], uint32(frame.CANID))
+	copy(data[16:80], frame.Data)
+
+	return mw.writeRecord(mw.busGroup, record)
+}
+
+// WriteFrames writes the frames of the given iterator, like a log or an interface.
+// It stops at the first error of the iterator and it returns it.
+func (mw *Writer) WriteFrames(frames iter.Seq2[*acmelib.Frame, error]) error {
+	for frame, err := range frames {
+		if err != nil {
+			return err
+		}
+
+		if err := mw.WriteFrame(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close writes the start time, the number of records of each channel group
+// and the size of the data block. It does not close the underlying writer.
+func (mw *Writer) Close() error {
+	if err := mw.bw.Flush(); err != nil {
+		return err
+	}
+
+	if mw.opts.StartTime.IsZero() {
+		mw.opts.StartTime = time.Now()
+	}
+
+	patches := map[uint64][]byte{
+		hdStartTimePos:    le.AppendUint64(nil, uint64(mw.opts.StartTime.UnixNano())),
+		mw.dataOffset + 8: le.AppendUint64(nil, blockHeaderSize+mw.dataSize),
+	}
+
+	for _, cg := range mw.groups {
+		patches[cg.cgOffset+cgCycleCountPos] = le.AppendUint64(nil, cg.cycleCount)
+	}
+	if mw.busGroup != nil {
+		patches[mw.busGroup.cgOffset+cgCycleCountPos] = le.AppendUint64(nil, mw.busGroup.cycleCount)
+	}
+
+	for pos, value := range patches {
+		if _, err := mw.w.Seek(int64(pos), io.SeekStart); err != nil {
+			return err
+		}
+
+		if _, err := mw.w.Write(value); err != nil {
+			return err
+		}
+	}
+
+	_, err := mw.w.Seek(0, io.SeekEnd)
+	return err
+}
@@ -0,0 +1,395 @@
+package mdf
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBlock is a block read from an MDF file.
+type testBlock struct {
+	id    string
+	links []uint64
+	data  []byte
+}
+
+func readTestBlock(assert *assert.Assertions, file []byte, offset uint64) *testBlock {
+	assert.Zero(offset % 8)
+
+	length := le.Uint64(file[offset+8:])
+	linkCount := le.Uint64(file[offset+16:])
+
+	links := []uint64{}
+	for idx := range linkCount {
+		links = append(links, le.Uint64(file[offset+blockHeaderSize+idx*8:]))
+	}
+
+	return &testBlock{
+		id:    string(file[offset+2 : offset+4]),
+		links: links,
+		data:  file[offset+blockHeaderSize+linkCount*8 : offset+length],
+	}
+}
+
+func readTestText(assert *assert.Assertions, file []byte, offset uint64) string {
+	if offset == 0 {
+		return ""
+	}
+
+	block := readTestBlock(assert, file, offset)
+	assert.Equal("TX", block.id)
+
+	return string(bytes.TrimRight(block.data, "\x00"))
+}
+
+// testChannel is a channel read from an MDF file.
+type testChannel struct {
+	name        string
+	unit        string
+	dataType    uint8
+	byteOffset  uint32
+	bitCount    uint32
+	invalBitPos uint32
+	conversion  *testBlock
+	children    []*testChannel
+}
+
+func readTestChannels(assert *assert.Assertions, file []byte, offset uint64) []*testChannel {
+	channels := []*testChannel{}
+
+	for offset != 0 {
+		block := readTestBlock(assert, file, offset)
+		assert.Equal("CN", block.id)
+
+		cn := &testChannel{
+			name:        readTestText(assert, file, block.links[2]),
+			unit:        readTestText(assert, file, block.links[6]),
+			dataType:    block.data[2],
+			byteOffset:  le.Uint32(block.data[4:]),
+			bitCount:    le.Uint32(block.data[8:]),
+			invalBitPos: le.Uint32(block.data[16:]),
+		}
+
+		if block.links[4] != 0 {
+			cn.conversion = readTestBlock(assert, file, block.links[4])
+		}
+
+		if block.links[1] != 0 {
+			cn.children = readTestChannels(assert, file, block.links[1])
+		}
+
+		channels = append(channels, cn)
+		offset = block.links[0]
+	}
+
+	return channels
+}
+
+// testGroup is a channel group read from an MDF file.
+type testGroup struct {
+	name       string
+	recordID   uint64
+	cycleCount uint64
+	dataBytes  uint32
+	invalBytes uint32
+	channels   []*testChannel
+	records    [][]byte
+}
+
+// readTestFile reads the channel groups and the records of an MDF file.
+func readTestFile(assert *assert.Assertions, path string) (time.Time, []*testGroup) {
+	file, err := os.ReadFile(path)
+	assert.NoError(err)
+
+	assert.Equal("MDF     4.10    ", string(file[0:16]))
+
+	hd := readTestBlock(assert, file, idBlockSize)
+	assert.Equal("HD", hd.id)
+	startTime := time.Unix(0, int64(le.Uint64(hd.data)))
+
+	fh := readTestBlock(assert, file, hd.links[1])
+	assert.Equal("FH", fh.id)
+
+	dg := readTestBlock(assert, file, hd.links[0])
+	assert.Equal("DG", dg.id)
+	recIDSize := int(dg.data[0])
+
+	groups := []*testGroup{}
+	groupsByID := make(map[uint64]*testGroup)
+
+	for offset := dg.links[1]; offset != 0; {
+		block := readTestBlock(assert, file, offset)
+		assert.Equal("CG", block.id)
+
+		group := &testGroup{
+			name:       readTestText(assert, file, block.links[2]),
+			recordID:   le.Uint64(block.data[0:]),
+			cycleCount: le.Uint64(block.data[8:]),
+			dataBytes:  le.Uint32(block.data[24:]),
+			invalBytes: le.Uint32(block.data[28:]),
+			channels:   readTestChannels(assert, file, block.links[1]),
+		}
+
+		groups = append(groups, group)
+		groupsByID[group.recordID] = group
+		offset = block.links[0]
+	}
+
+	dt := readTestBlock(assert, file, dg.links[2])
+	assert.Equal("DT", dt.id)
+
+	for data := dt.data; len(data) > 0; {
+		recordID := uint64(data[0])
+		if recIDSize == 2 {
+			recordID = uint64(le.Uint16(data))
+		}
+
+		group, ok := groupsByID[recordID]
+		assert.True(ok)
+
+		size := recIDSize + int(group.dataBytes+group.invalBytes)
+		group.records = append(group.records, data[recIDSize:size])
+		data = data[size:]
+	}
+
+	return startTime, groups
+}
+
+func initTestNetwork(assert *assert.Assertions) *acmelib.Network {
+	net := acmelib.NewNetwork("net")
+	bus := acmelib.NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	node := acmelib.NewNode("node", 1, 1)
+	nodeInt := node.GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	// engine message with a scaled signal, a signed signal and an enum signal
+	engineMsg := acmelib.NewMessage("engine", 1, 4)
+	assert.NoError(engineMsg.SetStaticCANID(0x100))
+	assert.NoError(nodeInt.AddSentMessage(engineMsg))
+
+	rpmType, err := acmelib.NewDecimalSignalType("rpm_t", 16, false)
+	assert.NoError(err)
+	rpmType.SetScale(0.25)
+	rpmSig, err := acmelib.NewStandardSignal("rpm", rpmType)
+	assert.NoError(err)
+	rpmSig.SetUnit(acmelib.NewSignalUnit("rpm_u", acmelib.SignalUnitKindCustom, "rpm"))
+	assert.NoError(engineMsg.InsertSignal(rpmSig, 0))
+
+	tempType, err := acmelib.NewIntegerSignalType("temp_t", 8, true)
+	assert.NoError(err)
+	tempSig, err := acmelib.NewStandardSignal("temp", tempType)
+	assert.NoError(err)
+	assert.NoError(engineMsg.InsertSignal(tempSig, 16))
+
+	gears := acmelib.NewSignalEnum("gears")
+	_, err = gears.AddValue(0, "neutral")
+	assert.NoError(err)
+	_, err = gears.AddValue(1, "first")
+	assert.NoError(err)
+	gearSig, err := acmelib.NewEnumSignal("gear", gears)
+	assert.NoError(err)
+	assert.NoError(engineMsg.InsertSignal(gearSig, 24))
+
+	// multiplexed message with a signal in each layout
+	muxMsg := acmelib.NewMessage("mux_msg", 2, 2)
+	assert.NoError(muxMsg.SetStaticCANID(0x200))
+	assert.NoError(nodeInt.AddSentMessage(muxMsg))
+
+	muxor, err := acmelib.NewMuxorSignal("muxor", 2)
+	assert.NoError(err)
+	muxLayer, err := muxMsg.SignalLayout().AddMultiplexedLayer(muxor, 0)
+	assert.NoError(err)
+
+	byteType, err := acmelib.NewIntegerSignalType("byte_t", 8, false)
+	assert.NoError(err)
+	sigA, err := acmelib.NewStandardSignal("sig_a", byteType)
+	assert.NoError(err)
+	assert.NoError(muxLayer.InsertSignal(sigA, 8, 0))
+	sigB, err := acmelib.NewStandardSignal("sig_b", byteType)
+	assert.NoError(err)
+	assert.NoError(muxLayer.InsertSignal(sigB, 8, 1))
+
+	return net
+}
+
+func writeTestFile(assert *assert.Assertions, path string, net *acmelib.Network, opts *WriterOptions, frames []*acmelib.Frame) {
+	file, err := os.Create(path)
+	assert.NoError(err)
+	defer file.Close()
+
+	w, err := NewWriter(file, net, opts)
+	assert.NoError(err)
+
+	source := func(yield func(*acmelib.Frame, error) bool) {
+		for _, frame := range frames {
+			if !yield(frame, nil) {
+				return
+			}
+		}
+	}
+
+	assert.NoError(w.WriteFrames(source))
+	assert.NoError(w.Close())
+}
+
+func Test_Writer_WriteFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	net := initTestNetwork(assert)
+	startTime := time.Unix(1700000000, 0)
+
+	frames := []*acmelib.Frame{
+		{Timestamp: startTime.Add(10 * time.Millisecond), CANID: 0x100, Data: []byte{0x10, 0x00, 0xFE, 0x01}},
+		{Timestamp: startTime.Add(20 * time.Millisecond), CANID: 0x200, Data: []byte{0x00, 0xAA}},
+		{Timestamp: startTime.Add(30 * time.Millisecond), CANID: 0x200, Data: []byte{0x01, 0xBB}},
+		{Timestamp: startTime.Add(40 * time.Millisecond), CANID: 0x300, Data: []byte{0x01}},
+		{Timestamp: startTime.Add(50 * time.Millisecond), CANID: 0x100, IsRemote: true},
+	}
+
+	path := filepath.Join(t.TempDir(), "test.mf4")
+	writeTestFile(assert, path, net, nil, frames)
+
+	fileStartTime, groups := readTestFile(assert, path)
+
+	// should use the timestamp of the first frame as the start time
+	assert.Equal(frames[0].Timestamp.UnixNano(), fileStartTime.UnixNano())
+
+	// should create a channel group for each message
+	assert.Len(groups, 2)
+
+	engine := groups[0]
+	assert.Equal("engine", engine.name)
+	assert.Equal(uint64(1), engine.cycleCount)
+	assert.Equal(uint32(1), engine.invalBytes)
+
+	// should create a channel for each signal
+	names := []string{}
+	for _, cn := range engine.channels {
+		names = append(names, cn.name)
+	}
+	assert.Equal([]string{"Timestamp", "rpm", "temp", "gear"}, names)
+
+	// should create a linear conversion with the unit
+	rpm := engine.channels[1]
+	assert.Equal("rpm", rpm.unit)
+	assert.Equal(uint32(16), rpm.bitCount)
+	assert.Equal("CC", rpm.conversion.id)
+	assert.Equal(conversionTypeLinear, rpm.conversion.data[0])
+	assert.Equal(0.0, math.Float64frombits(le.Uint64(rpm.conversion.data[24:])))
+	assert.Equal(0.25, math.Float64frombits(le.Uint64(rpm.conversion.data[32:])))
+
+	// should store the signed signals as signed integers
+	temp := engine.channels[2]
+	assert.Equal(dataTypeIntLE, temp.dataType)
+	assert.Nil(temp.conversion)
+
+	// should create a value to text conversion for the enum signals
+	gear := engine.channels[3]
+	assert.Equal(conversionTypeValueToText, gear.conversion.data[0])
+	assert.Len(gear.conversion.links, 4+3)
+	assert.Equal(uint16(2), le.Uint16(gear.conversion.data[6:]))
+
+	// should write the time and the raw values
+	record := engine.records[0]
+	assert.Equal(0.0, math.Float64frombits(le.Uint64(record)))
+	assert.Equal(uint16(0x10), le.Uint16(record[rpm.byteOffset:]))
+	assert.Equal(int8(-2), int8(record[temp.byteOffset]))
+	assert.Equal(uint8(1), record[gear.byteOffset])
+	assert.Equal(uint8(0), record[engine.dataBytes])
+
+	// should mark the signals of the inactive layouts as invalid
+	mux := groups[1]
+	assert.Equal("mux_msg", mux.name)
+	assert.Equal(uint64(2), mux.cycleCount)
+	assert.Len(mux.channels, 4)
+
+	sigA := mux.channels[2]
+	sigB := mux.channels[3]
+	assert.Equal("sig_a", sigA.name)
+	assert.Equal("sig_b", sigB.name)
+
+	record = mux.records[0]
+	assert.Equal(0.01, math.Float64frombits(le.Uint64(record)))
+	assert.Equal(uint8(0xAA), record[sigA.byteOffset])
+	inval := record[mux.dataBytes]
+	assert.Zero(inval & (1 << sigA.invalBitPos))
+	assert.NotZero(inval & (1 << sigB.invalBitPos))
+
+	record = mux.records[1]
+	assert.Equal(uint8(0xBB), record[sigB.byteOffset])
+	inval = record[mux.dataBytes]
+	assert.NotZero(inval & (1 << sigA.invalBitPos))
+	assert.Zero(inval & (1 << sigB.invalBitPos))
+}
+
+func Test_Writer_busLogging(t *testing.T) {
+	assert := assert.New(t)
+
+	net := initTestNetwork(assert)
+	startTime := time.Unix(1700000000, 0)
+
+	frames := []*acmelib.Frame{
+		{Timestamp: startTime.Add(10 * time.Millisecond), Channel: "can0", CANID: 0x100, Data: []byte{0x10, 0x00, 0xFE, 0x01}},
+		{Timestamp: startTime.Add(20 * time.Millisecond), Channel: "can1", CANID: 0x100, Data: []byte{0x10, 0x00, 0xFE, 0x01}},
+		{
+			Timestamp: startTime.Add(30 * time.Millisecond), Channel: "can0", CANID: 0x18FEF100, IsExtended: true,
+			IsFD: true, BitRateSwitch: true, Direction: acmelib.FrameDirectionTx, Data: make([]byte, 12),
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "test.mf4")
+	writeTestFile(assert, path, net, &WriterOptions{
+		StartTime:  startTime,
+		Channels:   map[string]string{"can0": "bus"},
+		BusLogging: true,
+	}, frames)
+
+	fileStartTime, groups := readTestFile(assert, path)
+	assert.Equal(startTime.UnixNano(), fileStartTime.UnixNano())
+	assert.Len(groups, 3)
+
+	// should decode only the frames of the mapped channels
+	assert.Equal(uint64(1), groups[0].cycleCount)
+	assert.Equal(0.01, math.Float64frombits(le.Uint64(groups[0].records[0])))
+
+	// should write all the frames as raw records
+	busLogging := groups[2]
+	assert.Equal("CAN_DataFrame", busLogging.name)
+	assert.Equal(uint64(3), busLogging.cycleCount)
+
+	assert.Len(busLogging.channels, 2)
+	dataFrame := busLogging.channels[1]
+	assert.Equal("CAN_DataFrame", dataFrame.name)
+	assert.Equal(dataTypeByteArray, dataFrame.dataType)
+
+	fields := make(map[string]*testChannel)
+	for _, cn := range dataFrame.children {
+		fields[cn.name] = cn
+	}
+	assert.Len(fields, 10)
+
+	record := busLogging.records[1]
+	assert.Equal(uint8(2), record[fields["CAN_DataFrame.BusChannel"].byteOffset])
+	assert.Equal(uint32(0x100), le.Uint32(record[fields["CAN_DataFrame.ID"].byteOffset:]))
+	assert.Equal(uint8(4), record[fields["CAN_DataFrame.DataLength"].byteOffset])
+	assert.Equal([]byte{0x10, 0x00, 0xFE, 0x01}, record[16:20])
+
+	record = busLogging.records[2]
+	assert.Equal(uint8(1), record[fields["CAN_DataFrame.BusChannel"].byteOffset])
+	assert.Equal(uint32(0x18FEF100), le.Uint32(record[fields["CAN_DataFrame.ID"].byteOffset:]))
+	assert.Equal(uint8(0x01|0x02|0x04|0x08), record[9])
+	assert.Equal(uint8(9), record[fields["CAN_DataFrame.DLC"].byteOffset])
+	assert.Equal(uint8(12), record[fields["CAN_DataFrame.DataLength"].byteOffset])
+
+	// should return an error if a bus does not exist
+	_, err := NewWriter(nil, net, &WriterOptions{Channels: map[string]string{"can0": "unknown"}})
+	assert.ErrorIs(err, acmelib.ErrNotFound)
+}
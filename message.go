@@ -6,6 +6,7 @@ import (
 
 	"github.com/squadracorsepolito/acmelib/internal/collection"
 	"github.com/squadracorsepolito/acmelib/internal/stringer"
+	"github.com/squadracorsepolito/acmelib/isotp"
 )
 
 // MessageID rappresents the ID of a [Message].
@@ -15,6 +16,7 @@ type MessageID uint32
 // MessagePriority rappresents the priority of a [Message].
 // The priorities are very high, high, medium, and low.
 // The higher priority has the value 0 and the lower has 3.
+// The J1939 messages use the priorities from 0 to 7.
 type MessagePriority uint32
 
 const (
@@ -28,6 +30,21 @@ const (
 	MessagePriorityLow
 )
 
+func (mp MessagePriority) String() string {
+	switch mp {
+	case MessagePriorityVeryHigh:
+		return "very_high"
+	case MessagePriorityHigh:
+		return "high"
+	case MessagePriorityMedium:
+		return "medium"
+	case MessagePriorityLow:
+		return "low"
+	default:
+		return "unknown"
+	}
+}
+
 // MessageSendType rappresents the transition type of a [Message].
 type MessageSendType int
 
@@ -61,6 +78,34 @@ func (mst MessageSendType) String() string {
 	}
 }
 
+// MessageTransport is the transport protocol used to send a [Message]
+// whose payload does not fit in a single frame.
+type MessageTransport int
+
+const (
+	// MessageTransportNone defines a message sent within a single frame.
+	MessageTransportNone MessageTransport = iota
+	// MessageTransportJ1939TP defines a message sent with the J1939 transport protocol
+	// (BAM or CMDT), up to [J1939MaxTPSizeByte] bytes.
+	MessageTransportJ1939TP
+	// MessageTransportISOTP defines a message sent with the ISO-TP protocol (ISO 15765-2),
+	// up to [isotp.MaxSizeByte] bytes.
+	MessageTransportISOTP
+)
+
+func (mt MessageTransport) String() string {
+	switch mt {
+	case MessageTransportNone:
+		return "none"
+	case MessageTransportJ1939TP:
+		return "j1939_tp"
+	case MessageTransportISOTP:
+		return "iso_tp"
+	default:
+		return "unknown"
+	}
+}
+
 // Message is the representation of data sent by a node thought the bus.
 // It holds a list of signals that are contained in the message payload.
 type Message struct {
@@ -74,11 +119,15 @@ type Message struct {
 
 	layout *SignalLayout
 
-	sizeByte int
+	e2eProtection *E2EProtection
+
+	sizeByte  int
+	transport MessageTransport
 
 	id             MessageID
 	staticCANID    CANID
 	hasStaticCANID bool
+	canIDFormat    CANIDFormat
 
 	priority MessagePriority
 
@@ -100,11 +149,15 @@ func newMessageFromEntity(ent *entity, id MessageID, sizeByte int) *Message {
 		signals:     collection.NewMap[EntityID, Signal](),
 		signalNames: collection.NewMap[string, EntityID](),
 
-		sizeByte: sizeByte,
+		e2eProtection: nil,
+
+		sizeByte:  sizeByte,
+		transport: MessageTransportNone,
 
 		id:             id,
 		staticCANID:    0,
 		hasStaticCANID: false,
+		canIDFormat:    CANIDFormatStandard,
 
 		priority: MessagePriorityVeryHigh,
 
@@ -124,7 +177,8 @@ func newMessageFromEntity(ent *entity, id MessageID, sizeByte int) *Message {
 }
 
 // NewMessage creates a new [Message] with the given name, id and size in bytes.
-// By default a [MessagePriority] of [MessagePriorityVeryHigh] is used.
+// By default a [MessagePriority] of [MessagePriorityVeryHigh]
+// and a [CANIDFormat] of [CANIDFormatStandard] are used.
 func NewMessage(name string, id MessageID, sizeByte int) *Message {
 	return newMessageFromEntity(newEntity(name, EntityKindMessage), id, sizeByte)
 }
@@ -188,6 +242,14 @@ func (m *Message) stringify(s *stringer.Stringer) {
 		s.Write("message_id: %d\n", m.id)
 	}
 
+	if m.canIDFormat == CANIDFormatExtended {
+		s.Write("can_id_format: %s\n", m.canIDFormat)
+	}
+
+	if m.transport != MessageTransportNone {
+		s.Write("transport: %s\n", m.transport)
+	}
+
 	s.Write("priority: %d (very_high=0; low=3)\n", m.priority)
 	s.Write("size: %d bytes\n", m.sizeByte)
 
@@ -221,6 +283,13 @@ func (m *Message) stringify(s *stringer.Stringer) {
 	m.layout.stringify(s)
 	s.Unindent()
 
+	if m.e2eProtection != nil {
+		s.Write("e2e_protection:\n")
+		s.Indent()
+		m.e2eProtection.stringify(s)
+		s.Unindent()
+	}
+
 	m.withAttributes.stringify(s)
 }
 
@@ -243,6 +312,11 @@ func (m *Message) removeSignal(sig Signal) {
 	m.signalNames.Delete(sig.Name())
 	sig.setParentMsg(nil)
 	m.layout.delete(sig)
+
+	// the protection cannot work without one of its signals
+	if m.e2eProtection != nil && m.e2eProtection.usesSignal(sig) {
+		m.e2eProtection = nil
+	}
 }
 
 // UpdateName updates the name of the [Message].
@@ -271,7 +345,7 @@ func (m *Message) UpdateName(newName string) error {
 // It returns a [SizeError] if the new size is invalid.
 func (m *Message) UpdateSizeByte(newSizeByte int) error {
 	if m.hasSenderNodeInt() {
-		if err := m.senderNodeInt.verifyMessageSize(newSizeByte); err != nil {
+		if err := m.senderNodeInt.verifyMessageSize(newSizeByte, m.transport); err != nil {
 			return err
 		}
 	}
@@ -285,6 +359,29 @@ func (m *Message) UpdateSizeByte(newSizeByte int) error {
 	return nil
 }
 
+// SetTransport sets the [MessageTransport] of the [Message].
+// A transport protocol allows the message to be bigger than a single frame.
+//
+// It returns:
+//   - [SizeError] if the size of the message is invalid for the transport.
+//   - [ErrInvalidType] if the bus of the sender node does not support the transport.
+func (m *Message) SetTransport(transport MessageTransport) error {
+	if m.hasSenderNodeInt() {
+		if err := m.senderNodeInt.verifyMessageSize(m.sizeByte, transport); err != nil {
+			return m.errorf(err)
+		}
+	}
+
+	m.transport = transport
+
+	return nil
+}
+
+// Transport returns the [MessageTransport] of the [Message].
+func (m *Message) Transport() MessageTransport {
+	return m.transport
+}
+
 // SenderNodeInterface returns the [NodeInterface] that is responsible for sending the [Message].
 // If the [Message] is not sent by a [NodeInterface], it will return nil.
 func (m *Message) SenderNodeInterface() *NodeInterface {
@@ -476,7 +573,10 @@ func (m *Message) Receivers() []*NodeInterface {
 // UpdateID updates the id of the [Message].
 // It will also reset the static CAN-ID of the message.
 //
-// It returns a [MessageIDError] if the new id is invalid.
+// It returns:
+//   - [MessageIDError] if the new id is invalid.
+//   - [CANIDError] if the CAN-IDs calculated by the CAN-ID builder of the bus
+//     do not fit the format of the message.
 func (m *Message) UpdateID(newID MessageID) error {
 	if m.id == newID && !m.hasStaticCANID {
 		return nil
@@ -490,6 +590,11 @@ func (m *Message) UpdateID(newID MessageID) error {
 		}
 
 		if m.hasStaticCANID {
+			// the CAN-ID will be calculated by the CAN-ID builder
+			if err := nodeInt.verifyCANIDBuilderFormat(m.canIDFormat); err != nil {
+				return m.errorf(err)
+			}
+
 			nodeInt.sentMessageStaticCANIDs.Delete(m.staticCANID)
 
 			if nodeInt.hasParentBus() {
@@ -537,8 +642,13 @@ func (m *Message) GetCANID() CANID {
 
 // SetStaticCANID sets the static CAN-ID of the [Message].
 //
-// It returns a [CANIDError] if the given static CAN-ID is already used.
+// It returns a [CANIDError] if the given static CAN-ID is already used
+// or if it does not fit the [CANIDFormat] of the message.
 func (m *Message) SetStaticCANID(staticCANID CANID) error {
+	if err := m.canIDFormat.verifyCANID(staticCANID); err != nil {
+		return m.errorf(err)
+	}
+
 	if m.hasSenderNodeInt() {
 		nodeInt := m.senderNodeInt
 
@@ -572,11 +682,237 @@ func (m *Message) HasStaticCANID() bool {
 	return m.hasStaticCANID
 }
 
+// SetCANIDFormat sets the [CANIDFormat] of the [Message].
+//
+// It returns:
+//   - [CANIDError] if the static CAN-ID of the message does not fit the format,
+//     or if the CAN-IDs calculated by the CAN-ID builder of the bus do not fit the format.
+//   - [ErrInvalidType] if the bus of the sender node does not support the format.
+func (m *Message) SetCANIDFormat(format CANIDFormat) error {
+	if m.hasStaticCANID {
+		if err := format.verifyCANID(m.staticCANID); err != nil {
+			return m.errorf(err)
+		}
+	}
+
+	if m.hasSenderNodeInt() {
+		if err := m.senderNodeInt.verifyCANIDFormat(format); err != nil {
+			return m.errorf(err)
+		}
+
+		if !m.hasStaticCANID {
+			if err := m.senderNodeInt.verifyCANIDBuilderFormat(format); err != nil {
+				return m.errorf(err)
+			}
+		}
+	}
+
+	m.canIDFormat = format
+
+	return nil
+}
+
+// CANIDFormat returns the [CANIDFormat] of the [Message].
+func (m *Message) CANIDFormat() CANIDFormat {
+	return m.canIDFormat
+}
+
+// IsExtended returns whether the [Message] uses an extended (29 bits) CAN-ID.
+func (m *Message) IsExtended() bool {
+	return m.canIDFormat == CANIDFormatExtended
+}
+
+// SetE2EProtection sets the [E2EProtection] of the [Message].
+// The signals used by the protection must be standard signals
+// placed in the layout of the message, outside any multiplexed layer.
+// If the protection is nil, the message is no longer protected.
+// The protection is removed when one of its signals is deleted from the message.
+//
+// It returns an [ArgError] if the protection does not fit the message:
+//   - [ErrIsNil] if a required signal or the custom CRC is missing.
+//   - [ErrNotFound] if a signal is not in the layout of the message.
+//   - [SizeError] if the size of a signal does not match the profile,
+//     or if the width of the custom CRC is invalid.
+//   - [StartPosError] if the CRC signal is not byte aligned.
+//   - [ErrOutOfBounds] if the data id is too big for the profile.
+func (m *Message) SetE2EProtection(protection *E2EProtection) error {
+	if protection != nil {
+		if err := protection.verify(m); err != nil {
+			return m.errorf(err)
+		}
+	}
+
+	m.e2eProtection = protection
+
+	return nil
+}
+
+// E2EProtection returns the [E2EProtection] of the [Message],
+// or nil if the message is not protected.
+func (m *Message) E2EProtection() *E2EProtection {
+	return m.e2eProtection
+}
+
 // SignalLayout returns the [SignalLayout] of the [Message].
 func (m *Message) SignalLayout() *SignalLayout {
 	return m.layout
 }
 
+// EncodeValues encodes the given physical values, keyed by signal name,
+// into the data buffer. The message and its signals are not modified,
+// so it is safe to call it concurrently on the same [Message].
+// See [SignalLayout.EncodeValues] for the accepted values.
+//
+// If the message has an [E2EProtection], the CRC and the other signals
+// of the protection are filled automatically, while the value of the counter
+// is optional and it defaults to 0. Use an [E2ESender] to increment it.
+//
+// It returns:
+//   - [SizeError] if data is smaller than the message size.
+//   - [SignalValueError] if the value of a signal is missing or invalid.
+func (m *Message) EncodeValues(data []byte, values map[string]any) error {
+	if m.e2eProtection != nil {
+		if err := m.e2eProtection.encodeValues(m.layout, data, values, nil); err != nil {
+			return m.errorf(err)
+		}
+		return nil
+	}
+
+	if err := m.layout.EncodeValues(data, values); err != nil {
+		return m.errorf(err)
+	}
+	return nil
+}
+
+// EncodeFrame encodes the given physical values, keyed by signal name,
+// into a [Frame] with the CAN-ID and the format of the message, ready to be
+// sent to a [FrameSink]. See [Message.EncodeValues] for the accepted values.
+//
+// The frame is a CAN FD frame if the message is sent over a CAN FD bus
+// or if it is bigger than 8 bytes. In this case the payload is padded with zeros
+// to the next valid CAN FD size, and the bit rate switch is set
+// as in the sender bus.
+// A message bigger than 8 bytes that is sent over a classic CAN bus
+// or with a [MessageTransport] cannot be encoded into a single frame:
+// use [Message.EncodeISOTPFrames] or the J1939 transport protocol instead.
+//
+// It returns:
+//   - [SignalValueError] if the value of a signal is missing or invalid.
+//   - [SizeError] if the message does not fit a single frame.
+func (m *Message) EncodeFrame(values map[string]any) (*Frame, error) {
+	frame, err := m.newFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.EncodeValues(frame.Data, values); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+// verifySingleFrame checks whether the message can be sent within a single frame.
+//
+// It returns a [SizeError] if the message is bigger than 8 bytes
+// and it is sent over a classic CAN bus or with a transport protocol.
+func (m *Message) verifySingleFrame() error {
+	if m.sizeByte <= 8 {
+		return nil
+	}
+
+	if m.transport != MessageTransportNone {
+		return m.errorf(newSizeError(m.sizeByte, ErrTooBig))
+	}
+
+	if m.hasSenderNodeInt() && m.senderNodeInt.hasParentBus() && !m.isSentOverFD() {
+		return m.errorf(newSizeError(m.sizeByte, ErrTooBig))
+	}
+
+	return nil
+}
+
+// newFrame returns a [Frame] of the message with an empty payload.
+//
+// It returns a [SizeError] if the message does not fit a single frame.
+func (m *Message) newFrame() (*Frame, error) {
+	if err := m.verifySingleFrame(); err != nil {
+		return nil, err
+	}
+
+	isFD := m.sizeByte > 8 || m.isSentOverFD()
+
+	frameSize := m.sizeByte
+	if isFD {
+		for _, size := range dlcSizeBytes {
+			if size >= m.sizeByte {
+				frameSize = size
+				break
+			}
+		}
+
+		if frameSize < m.sizeByte {
+			return nil, m.errorf(newSizeError(m.sizeByte, ErrTooBig))
+		}
+	}
+
+	return &Frame{
+		Direction:     FrameDirectionTx,
+		CANID:         m.GetCANID(),
+		IsExtended:    m.IsExtended(),
+		IsFD:          isFD,
+		BitRateSwitch: isFD && m.isSentWithBitRateSwitch(),
+		Data:          make([]byte, frameSize),
+	}, nil
+}
+
+// isSentOverFD returns whether the message is sent over a CAN FD bus.
+func (m *Message) isSentOverFD() bool {
+	return m.hasSenderNodeInt() && m.senderNodeInt.hasParentBus() && m.senderNodeInt.parentBus.typ == BusTypeCANFD
+}
+
+// isSentWithBitRateSwitch returns whether the message is sent over a bus
+// with the bit rate switch enabled.
+func (m *Message) isSentWithBitRateSwitch() bool {
+	return m.hasSenderNodeInt() && m.senderNodeInt.hasParentBus() && m.senderNodeInt.parentBus.bitRateSwitch
+}
+
+// EncodeISOTPFrames encodes the given physical values, keyed by signal name,
+// and it segments the payload into ISO-TP frames.
+// If the given options are nil, the default ones are used,
+// with CAN FD frames of 64 bytes if the message is sent over a CAN FD bus.
+// See [Message.EncodeValues] for the accepted values and [isotp.Segment]
+// for the segmentation.
+//
+// It returns:
+//   - [SignalValueError] if the value of a signal is missing or invalid.
+//   - an isotp error if the payload cannot be segmented.
+func (m *Message) EncodeISOTPFrames(values map[string]any, opts *isotp.Options) ([][]byte, error) {
+	data := make([]byte, m.sizeByte)
+	if err := m.EncodeValues(data, values); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = m.defaultISOTPOptions()
+	}
+
+	frames, err := isotp.Segment(data, opts)
+	if err != nil {
+		return nil, m.errorf(err)
+	}
+
+	return frames, nil
+}
+
+// defaultISOTPOptions returns the ISO-TP options that fit the bus of the message.
+func (m *Message) defaultISOTPOptions() *isotp.Options {
+	if m.isSentOverFD() {
+		return isotp.FDOptions()
+	}
+	return isotp.DefaultOptions()
+}
+
 // AssignAttribute assigns the given attribute/value pair to the [Message].
 //
 // It returns an [ArgError] if the attribute is nil,
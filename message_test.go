@@ -193,3 +193,207 @@ func Test_Message_UpdateSizeByte(t *testing.T) {
 	// for a CAN2.0A bus
 	assert.Error(msg.UpdateSizeByte(9))
 }
+
+func Test_Message_EncodeValues(t *testing.T) {
+	assert := assert.New(t)
+
+	tdBasicMsg := initBasicMessage(assert)
+	msg := tdBasicMsg.message
+
+	values := map[string]any{
+		"basic_signal_0": 3073,
+		"basic_signal_1": 3073,
+		"basic_signal_2": 3073,
+	}
+
+	encData := make([]byte, 8)
+
+	// Missing signal
+	err := msg.EncodeValues(encData, values)
+	assert.ErrorIs(err, ErrIsMissing)
+	var entErr *EntityError
+	assert.ErrorAs(err, &entErr)
+	assert.Equal(msg.EntityID(), entErr.EntityID)
+
+	values["basic_signal_3"] = 3073
+	assert.NoError(msg.EncodeValues(encData, values))
+
+	expectedEncData := []byte{
+		0b00000001,
+		0b00011100,
+		0b11000000,
+		0b00000001,
+		0b00011100,
+		0b11000000,
+		0b00000000,
+		0b00000000,
+	}
+
+	assert.Equal(expectedEncData, encData)
+}
+
+func Test_Message_EncodeFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	tdBasicMsg := initBasicMessage(assert)
+	msg := tdBasicMsg.message
+
+	values := map[string]any{
+		"basic_signal_0": 3073,
+		"basic_signal_1": 3073,
+		"basic_signal_2": 3073,
+	}
+
+	// should return an error because a signal is missing
+	_, err := msg.EncodeFrame(values)
+	assert.ErrorIs(err, ErrIsMissing)
+
+	values["basic_signal_3"] = 3073
+	frame, err := msg.EncodeFrame(values)
+	assert.NoError(err)
+
+	assert.Equal(msg.GetCANID(), frame.CANID)
+	assert.Equal(FrameDirectionTx, frame.Direction)
+	assert.False(frame.IsExtended)
+	assert.False(frame.IsFD)
+	assert.Len(frame.Data, 8)
+	assert.Equal(byte(0b00000001), frame.Data[0])
+
+	// should pad the payload to the next CAN FD size
+	fdMsg := NewMessage("fd_msg", 2, 10)
+	assert.NoError(fdMsg.SetCANIDFormat(CANIDFormatExtended))
+
+	frame, err = fdMsg.EncodeFrame(nil)
+	assert.NoError(err)
+	assert.True(frame.IsExtended)
+	assert.True(frame.IsFD)
+	assert.False(frame.BitRateSwitch)
+	assert.Len(frame.Data, 12)
+
+	// should set the bit rate switch as in the sender bus
+	bus := NewBus("fd_bus")
+	bus.SetType(BusTypeCANFD)
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+	busMsg := NewMessage("bus_msg", 3, 8)
+	assert.NoError(nodeInt.AddSentMessage(busMsg))
+
+	frame, err = busMsg.EncodeFrame(nil)
+	assert.NoError(err)
+	assert.True(frame.IsFD)
+	assert.False(frame.BitRateSwitch)
+
+	bus.SetBitRateSwitch(true)
+	frame, err = busMsg.EncodeFrame(nil)
+	assert.NoError(err)
+	assert.True(frame.BitRateSwitch)
+
+	// should return an error because a classic CAN bus supports up to 8 bytes
+	canBus := NewBus("can_bus")
+	canBus.SetType(BusTypeCAN2B)
+	canNodeInt := NewNode("can_node", 0, 1).GetInterface(0)
+	assert.NoError(canBus.AddNodeInterface(canNodeInt))
+	tpMsg := NewMessage("tp_msg", 4, 20)
+	assert.NoError(tpMsg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(tpMsg.SetTransport(MessageTransportISOTP))
+	assert.NoError(canNodeInt.AddSentMessage(tpMsg))
+
+	_, err = tpMsg.EncodeFrame(nil)
+	assert.ErrorIs(err, ErrTooBig)
+	var sizeErr *SizeError
+	assert.ErrorAs(err, &sizeErr)
+	assert.Equal(20, sizeErr.Size)
+
+	// should return an error because the message must be segmented by the transport protocol
+	fdTPMsg := NewMessage("fd_tp_msg", 5, 20)
+	assert.NoError(fdTPMsg.SetTransport(MessageTransportISOTP))
+	assert.NoError(nodeInt.AddSentMessage(fdTPMsg))
+
+	_, err = fdTPMsg.EncodeFrame(nil)
+	assert.ErrorIs(err, ErrTooBig)
+}
+
+func Test_Message_SetCANIDFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	msg := NewMessage("msg", 1, 8)
+	assert.Equal(CANIDFormatStandard, msg.CANIDFormat())
+
+	// should return an error because the static CAN-ID exceeds 11 bits
+	assert.ErrorIs(msg.SetStaticCANID(0x18FF1234), ErrOutOfBounds)
+
+	assert.NoError(msg.SetCANIDFormat(CANIDFormatExtended))
+	assert.True(msg.IsExtended())
+	assert.NoError(msg.SetStaticCANID(0x18FF1234))
+	assert.Equal(CANID(0x18FF1234), msg.GetCANID())
+
+	// should return an error because the static CAN-ID exceeds 29 bits
+	assert.ErrorIs(msg.SetStaticCANID(0x20000000), ErrOutOfBounds)
+
+	// should return an error because the static CAN-ID does not fit the standard format
+	assert.ErrorIs(msg.SetCANIDFormat(CANIDFormatStandard), ErrOutOfBounds)
+
+	// should return an error because a CAN 2.0A bus does not support extended CAN-IDs
+	bus := NewBus("bus")
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+	assert.ErrorIs(nodeInt.AddSentMessage(msg), ErrInvalidType)
+
+	stdMsg := NewMessage("std_msg", 2, 8)
+	assert.NoError(nodeInt.AddSentMessage(stdMsg))
+	assert.ErrorIs(stdMsg.SetCANIDFormat(CANIDFormatExtended), ErrInvalidType)
+
+	// should add the extended message to a CAN 2.0B bus
+	bus.SetType(BusTypeCAN2B)
+	assert.NoError(nodeInt.AddSentMessage(msg))
+	assert.NoError(stdMsg.SetCANIDFormat(CANIDFormatExtended))
+
+	// should estimate the bits of the extended frames
+	bus.SetBaudrate(500_000)
+	assert.NoError(stdMsg.SetCANIDFormat(CANIDFormatStandard))
+	_, msgLoads, err := bus.EstimateLoad(10)
+	assert.NoError(err)
+	assert.Equal("msg", msgLoads[0].Message.Name())
+	assert.Equal(15700.0, msgLoads[0].BitsPerSec)
+	assert.Equal(13200.0, msgLoads[1].BitsPerSec)
+}
+
+func Test_Message_SetTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+	bus.SetBaudrate(250_000)
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	// should return an error because the message does not fit a frame
+	msg := NewMessage("msg", 1, 20)
+	assert.ErrorIs(nodeInt.AddSentMessage(msg), ErrTooBig)
+
+	assert.NoError(msg.SetTransport(MessageTransportJ1939TP))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+	assert.NoError(msg.UpdateSizeByte(J1939MaxTPSizeByte))
+	assert.ErrorIs(msg.UpdateSizeByte(J1939MaxTPSizeByte+1), ErrTooBig)
+
+	// should return an error because the message does not fit a frame anymore
+	assert.ErrorIs(msg.SetTransport(MessageTransportNone), ErrTooBig)
+
+	// should estimate the load of all the transport protocol frames
+	assert.NoError(msg.UpdateSizeByte(20))
+	_, msgLoads, err := bus.EstimateLoad(100)
+	assert.NoError(err)
+	assert.Equal(4*1320.0, msgLoads[0].BitsPerSec)
+
+	// should return an error because a CAN 2.0A bus does not support extended CAN-IDs
+	bus.SetType(BusTypeCAN2A)
+	assert.ErrorIs(bus.Validate(), ErrInvalidType)
+	bus.SetType(BusTypeCAN2B)
+
+	canBus := NewBus("can_bus")
+	canNodeInt := NewNode("can_node", 1, 1).GetInterface(0)
+	assert.NoError(canBus.AddNodeInterface(canNodeInt))
+	canMsg := NewMessage("can_msg", 1, 8)
+	assert.NoError(canNodeInt.AddSentMessage(canMsg))
+	assert.ErrorIs(canMsg.SetTransport(MessageTransportJ1939TP), ErrInvalidType)
+}
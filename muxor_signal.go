@@ -114,19 +114,49 @@ func (ms *MuxorSignal) UpdateStartPos(newStartPos int) error {
 //
 // It returns an [ArgError] if the given value is negative or out of bounds.
 func (ms *MuxorSignal) UpdateEncodedValue(value int) error {
-	if value < 0 {
-		return ms.errorf(newArgError("value", ErrIsNegative))
+	if err := ms.verifyLayoutID(value); err != nil {
+		return ms.errorf(newArgError("value", err))
 	}
 
-	if value >= ms.layoutCount {
-		return ms.errorf(newArgError("value", ErrOutOfBounds))
+	ms.encodedValue = uint64(value)
+
+	return nil
+}
+
+// verifyLayoutID checks if the given layout id can be selected by the muxor.
+//
+// It returns:
+//   - [ErrIsNegative] if the layout id is negative.
+//   - [ErrOutOfBounds] if the layout id is greater or equal to the layout count.
+func (ms *MuxorSignal) verifyLayoutID(layoutID int) error {
+	if layoutID < 0 {
+		return ErrIsNegative
 	}
 
-	ms.encodedValue = uint64(value)
+	if layoutID >= ms.layoutCount {
+		return ErrOutOfBounds
+	}
 
 	return nil
 }
 
+// encodeValue returns the raw value of the given layout id.
+//
+// It returns an [ErrInvalidType] if the value is not an integer,
+// or an [ErrIsNegative]/[ErrOutOfBounds] if the layout id is invalid.
+func (ms *MuxorSignal) encodeValue(value any) (uint64, error) {
+	layoutID, ok := valueToInt(value)
+	if !ok {
+		return 0, ErrInvalidType
+	}
+
+	if err := ms.verifyLayoutID(layoutID); err != nil {
+		return 0, err
+	}
+
+	return uint64(layoutID), nil
+}
+
 // ToSignal returns the signal itself.
 func (ms *MuxorSignal) ToSignal() (Signal, error) {
 	return ms, nil
@@ -124,6 +124,20 @@ func (n *Network) Buses() []*Bus {
 	return busSlice
 }
 
+// NewDecoder returns a [Decoder] for all the messages sent in all the buses of the network.
+// Use [Bus.NewDecoder] when the buses share the same CAN-IDs.
+//
+// It returns a [CANIDError] if two messages have the same CAN-ID and format.
+func (n *Network) NewDecoder() (*Decoder, error) {
+	dec := newDecoder()
+	for _, bus := range n.Buses() {
+		if err := dec.addBus(bus); err != nil {
+			return nil, err
+		}
+	}
+	return dec, nil
+}
+
 // ToNetwork returns the network itself.
 func (n *Network) ToNetwork() (*Network, error) {
 	return n, nil
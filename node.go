@@ -23,6 +23,8 @@ type Node struct {
 	interfaces []*NodeInterface
 	intErrNum  int
 
+	gatewayRoutes []*GatewayRoute
+
 	id             NodeID
 	interfaceCount int
 }
@@ -35,6 +37,8 @@ func newNodeFromEntity(ent *entity, id NodeID, intCount int) *Node {
 		interfaces: []*NodeInterface{},
 		intErrNum:  -1,
 
+		gatewayRoutes: []*GatewayRoute{},
+
 		id:             id,
 		interfaceCount: intCount,
 	}
@@ -77,6 +81,15 @@ func (n *Node) stringify(s *stringer.Stringer) {
 
 	s.Write("node_id: %s\n", n.id.String())
 
+	if len(n.gatewayRoutes) > 0 {
+		s.Write("gateway_routes:\n")
+		s.Indent()
+		for _, route := range n.gatewayRoutes {
+			s.Write("%s -> %s\n", route.sourceMessage.name, route.targetMessage.name)
+		}
+		s.Unindent()
+	}
+
 	n.withAttributes.stringify(s)
 }
 
@@ -110,9 +110,25 @@ func (ni *NodeInterface) verifyStaticCANID(staticCANID CANID) error {
 	return nil
 }
 
-func (ni *NodeInterface) verifyMessageSize(sizeByte int) error {
+func (ni *NodeInterface) verifyMessageSize(sizeByte int, transport MessageTransport) error {
 	if ni.hasParentBus() {
-		return ni.parentBus.verifyMessageSize(sizeByte)
+		return ni.parentBus.verifyMessageSize(sizeByte, transport)
+	}
+
+	return nil
+}
+
+func (ni *NodeInterface) verifyCANIDFormat(format CANIDFormat) error {
+	if ni.hasParentBus() {
+		return ni.parentBus.verifyCANIDFormat(format)
+	}
+
+	return nil
+}
+
+func (ni *NodeInterface) verifyCANIDBuilderFormat(format CANIDFormat) error {
+	if ni.hasParentBus() {
+		return ni.parentBus.verifyCANIDBuilderFormat(format)
 	}
 
 	return nil
@@ -139,9 +155,11 @@ func (ni *NodeInterface) removeReceivedMessage(msg *Message) {
 // It returns:
 //   - [ArgError] if the given message is nil.
 //   - [AddEntityError] that wraps a [NameError] if the message name is invalid.
-//   - [AddEntityError] that wraps a [CANIDError] if the message static can id is invalid.
+//   - [AddEntityError] that wraps a [CANIDError] if the message static can id is invalid,
+//     or if the CAN-IDs calculated by the CAN-ID builder do not fit the format of the message.
 //   - [AddEntityError] that wraps a [MessageIDError] if the message id is invalid.
 //   - [AddEntityError] that wraps a [MessageSizeError] if the message size is invalid.
+//   - [AddEntityError] that wraps an [ErrInvalidType] if the bus does not support the CAN-ID format of the message.
 func (ni *NodeInterface) AddSentMessage(message *Message) error {
 	if message == nil {
 		return newArgError("message", ErrIsNil)
@@ -157,7 +175,12 @@ func (ni *NodeInterface) AddSentMessage(message *Message) error {
 		return ni.errorf(addMsgErr)
 	}
 
-	if err := ni.verifyMessageSize(message.sizeByte); err != nil {
+	if err := ni.verifyMessageSize(message.sizeByte, message.transport); err != nil {
+		addMsgErr.Err = err
+		return ni.errorf(addMsgErr)
+	}
+
+	if err := ni.verifyCANIDFormat(message.canIDFormat); err != nil {
 		addMsgErr.Err = err
 		return ni.errorf(addMsgErr)
 	}
@@ -179,6 +202,12 @@ func (ni *NodeInterface) AddSentMessage(message *Message) error {
 			addMsgErr.Err = err
 			return ni.errorf(addMsgErr)
 		}
+
+		if err := ni.verifyCANIDBuilderFormat(message.canIDFormat); err != nil {
+			addMsgErr.Err = err
+			return ni.errorf(addMsgErr)
+		}
+
 		ni.sentMessageIDs.Set(message.id, message.entityID)
 	}
 
@@ -0,0 +1,249 @@
+package acmelib
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ResponseTimeOptions are the options of the response time analysis of a [Bus].
+type ResponseTimeOptions struct {
+	// DefaultCycleTime is the period in ms used for the messages without a cycle time.
+	DefaultCycleTime int
+	// DefaultJitter is the queuing jitter used for the messages
+	// that are not present in the Jitters map.
+	DefaultJitter time.Duration
+	// Jitters maps the entity id of a message to its queuing jitter,
+	// which is the maximum delay between the start of the period
+	// and the moment the message is queued for transmission.
+	Jitters map[EntityID]time.Duration
+	// Deadlines maps the entity id of a message to its deadline.
+	// The messages that are not present in the map have a deadline equal to their period.
+	Deadlines map[EntityID]time.Duration
+}
+
+// BusResponseTimeMessage is the result of the response time analysis of a [Message] in a [Bus].
+type BusResponseTimeMessage struct {
+	// Message is the examined message.
+	Message *Message
+	// CANID is the CAN-ID of the message, which determines its priority.
+	CANID CANID
+
+	// Period is the period of the message.
+	Period time.Duration
+	// Jitter is the queuing jitter of the message.
+	Jitter time.Duration
+	// Deadline is the deadline of the message.
+	Deadline time.Duration
+
+	// TransmissionTime is the time needed to send the message in the worst case scenario.
+	TransmissionTime time.Duration
+	// Blocking is the longest time the message can wait for the transmission
+	// of a lower priority frame that already won the arbitration.
+	Blocking time.Duration
+	// Interference is the time the message waits for the transmission
+	// of higher priority messages in its worst case instance.
+	Interference time.Duration
+	// ResponseTime is the worst case response time of the message,
+	// from the start of the period to the end of the transmission.
+	ResponseTime time.Duration
+
+	// Schedulable reports whether the response time is within the deadline.
+	Schedulable bool
+}
+
+// responseTimeTask holds the parameters of a message
+// used during the response time analysis, in nanoseconds.
+type responseTimeTask struct {
+	res *BusResponseTimeMessage
+
+	arbitrationKey uint64
+
+	period       float64
+	jitter       float64
+	deadline     float64
+	transmission float64
+	maxFrame     float64
+}
+
+// getArbitrationKey returns a key that sorts the CAN-IDs by arbitration order.
+// A standard CAN-ID wins against an extended one with the same base id,
+// because the SRR and IDE bits of the extended frame are recessive.
+func getArbitrationKey(canID CANID, format CANIDFormat) uint64 {
+	if format == CANIDFormatExtended {
+		baseID := uint64(canID>>18) & 0x7FF
+		return baseID<<19 | 1<<18 | uint64(canID)&0x3FFFF
+	}
+	return (uint64(canID) & 0x7FF) << 19
+}
+
+// AnalyzeResponseTimes performs the worst case response time analysis of the messages
+// sent in the bus, following the revised analysis of Davis, Burns, Bril and Lukkien (2007)
+// of the classic one by Tindell. It returns a slice of [BusResponseTimeMessage]
+// sorted from the highest to the lowest priority message.
+//
+// The priority of a message is given by the CAN-ID returned by [Message.GetCANID]
+// and its period by the cycle time. The transmission times take into account
+// the worst case bit stuffing (see [Bus.EstimateLoad]). The messages sent with
+// a transport protocol are analyzed as a single transmission of all their frames,
+// while only their longest frame can block a higher priority message.
+// If the bus does not have the baudrate set, it returns an empty slice.
+//
+// It returns an [ArgError] if the default cycle time of the options is invalid.
+func (b *Bus) AnalyzeResponseTimes(opts *ResponseTimeOptions) ([]*BusResponseTimeMessage, error) {
+	results := []*BusResponseTimeMessage{}
+
+	if opts == nil {
+		opts = &ResponseTimeOptions{}
+	}
+
+	if opts.DefaultCycleTime < 0 {
+		return results, newArgError("DefaultCycleTime", ErrIsNegative)
+	}
+
+	if b.baudrate == 0 {
+		return results, nil
+	}
+
+	bitTime := float64(time.Second) / float64(b.baudrate)
+
+	tasks := []*responseTimeTask{}
+	for tmpInt := range b.nodeInts.Values() {
+		for tmpMsg := range tmpInt.sentMessages.Values() {
+			cycleTime := tmpMsg.cycleTime
+			if cycleTime == 0 {
+				cycleTime = opts.DefaultCycleTime
+			}
+
+			if cycleTime == 0 {
+				return results, newArgError("DefaultCycleTime", ErrIsZero)
+			}
+
+			period := time.Duration(cycleTime) * time.Millisecond
+
+			jitter, ok := opts.Jitters[tmpMsg.entityID]
+			if !ok {
+				jitter = opts.DefaultJitter
+			}
+
+			deadline, ok := opts.Deadlines[tmpMsg.entityID]
+			if !ok {
+				deadline = period
+			}
+
+			canID := tmpMsg.GetCANID()
+
+			tasks = append(tasks, &responseTimeTask{
+				res: &BusResponseTimeMessage{
+					Message:  tmpMsg,
+					CANID:    canID,
+					Period:   period,
+					Jitter:   jitter,
+					Deadline: deadline,
+				},
+
+				arbitrationKey: getArbitrationKey(canID, tmpMsg.canIDFormat),
+
+				period:       float64(period),
+				jitter:       float64(jitter),
+				deadline:     float64(deadline),
+				transmission: b.estimateMessageBits(tmpMsg) * bitTime,
+				maxFrame:     b.estimateMaxFrameBits(tmpMsg) * bitTime,
+			})
+		}
+	}
+
+	slices.SortFunc(tasks, func(a, b *responseTimeTask) int {
+		if a.arbitrationKey != b.arbitrationKey {
+			if a.arbitrationKey < b.arbitrationKey {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.res.Message.name, b.res.Message.name)
+	})
+
+	for idx, task := range tasks {
+		analyzeResponseTime(task, tasks[:idx], tasks[idx+1:], bitTime)
+		results = append(results, task.res)
+	}
+
+	return results, nil
+}
+
+// analyzeResponseTime computes the worst case response time of the given task,
+// with the given higher and lower priority tasks.
+func analyzeResponseTime(task *responseTimeTask, higherTasks, lowerTasks []*responseTimeTask, bitTime float64) {
+	// the blocking is caused by the longest lower priority frame
+	blocking := 0.0
+	for _, lowerTask := range lowerTasks {
+		blocking = max(blocking, lowerTask.maxFrame)
+	}
+
+	// the utilization of the task and the higher priority ones must be lower than 1,
+	// otherwise the busy period is not bounded and only the first instance is analyzed
+	utilization := task.transmission / task.period
+	for _, higherTask := range higherTasks {
+		utilization += higherTask.transmission / higherTask.period
+	}
+	isBounded := utilization < 1
+
+	// the length of the priority level busy period gives
+	// the number of instances of the task that must be analyzed
+	instances := 1
+	if isBounded {
+		busyPeriod := task.transmission
+		for {
+			next := blocking + math.Ceil((busyPeriod+task.jitter)/task.period)*task.transmission
+			for _, higherTask := range higherTasks {
+				next += math.Ceil((busyPeriod+higherTask.jitter)/higherTask.period) * higherTask.transmission
+			}
+
+			if next <= busyPeriod {
+				break
+			}
+			busyPeriod = next
+		}
+
+		instances = int(math.Ceil((busyPeriod + task.jitter) / task.period))
+	}
+
+	responseTime := 0.0
+	interference := 0.0
+	for q := range instances {
+		ownTime := float64(q) * task.transmission
+
+		// the queuing delay of the instance
+		queuing := blocking + ownTime
+		for {
+			next := blocking + ownTime
+			for _, higherTask := range higherTasks {
+				next += math.Ceil((queuing+higherTask.jitter+bitTime)/higherTask.period) * higherTask.transmission
+			}
+
+			if next <= queuing {
+				break
+			}
+			queuing = next
+
+			// stop when the deadline is already missed, since the queuing delay
+			// may grow indefinitely when the bus is overloaded
+			if task.jitter+queuing-float64(q)*task.period+task.transmission > task.deadline {
+				break
+			}
+		}
+
+		instResponseTime := task.jitter + queuing - float64(q)*task.period + task.transmission
+		if instResponseTime > responseTime {
+			responseTime = instResponseTime
+			interference = queuing - blocking - ownTime
+		}
+	}
+
+	task.res.TransmissionTime = time.Duration(math.Round(task.transmission))
+	task.res.Blocking = time.Duration(math.Round(blocking))
+	task.res.Interference = time.Duration(math.Round(interference))
+	task.res.ResponseTime = time.Duration(math.Round(responseTime))
+	task.res.Schedulable = isBounded && responseTime <= task.deadline
+}
@@ -0,0 +1,91 @@
+package acmelib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Bus_AnalyzeResponseTimes(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	bus.SetBaudrate(125_000)
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	// all the frames are 132 bits long, so 1056 us at 125 kbit/s
+	msgA := NewMessage("msg_a", 1, 8)
+	msgA.SetCycleTime(10)
+	assert.NoError(nodeInt.AddSentMessage(msgA))
+	msgB := NewMessage("msg_b", 2, 8)
+	msgB.SetCycleTime(10)
+	assert.NoError(nodeInt.AddSentMessage(msgB))
+	msgC := NewMessage("msg_c", 3, 8)
+	assert.NoError(nodeInt.AddSentMessage(msgC))
+
+	// should return an error because msg_c does not have a cycle time
+	_, err := bus.AnalyzeResponseTimes(nil)
+	assert.ErrorIs(err, ErrIsZero)
+
+	_, err = bus.AnalyzeResponseTimes(&ResponseTimeOptions{DefaultCycleTime: -1})
+	assert.ErrorIs(err, ErrIsNegative)
+
+	results, err := bus.AnalyzeResponseTimes(&ResponseTimeOptions{DefaultCycleTime: 10})
+	assert.NoError(err)
+	assert.Len(results, 3)
+
+	frameTime := 1056 * time.Microsecond
+
+	expectedNames := []string{"msg_a", "msg_b", "msg_c"}
+	expectedBlockings := []time.Duration{frameTime, frameTime, 0}
+	expectedInterferences := []time.Duration{0, frameTime, 2 * frameTime}
+	for idx, res := range results {
+		assert.Equal(expectedNames[idx], res.Message.Name())
+		assert.Equal(10*time.Millisecond, res.Period)
+		assert.Equal(res.Period, res.Deadline)
+		assert.Equal(frameTime, res.TransmissionTime)
+		assert.Equal(expectedBlockings[idx], res.Blocking)
+		assert.Equal(expectedInterferences[idx], res.Interference)
+		assert.Equal(res.Blocking+res.Interference+res.TransmissionTime, res.ResponseTime)
+		assert.True(res.Schedulable)
+	}
+
+	// should add the jitter and use the given deadline
+	results, err = bus.AnalyzeResponseTimes(&ResponseTimeOptions{
+		DefaultCycleTime: 10,
+		Jitters:          map[EntityID]time.Duration{msgC.EntityID(): time.Millisecond},
+		Deadlines:        map[EntityID]time.Duration{msgC.EntityID(): 4 * time.Millisecond},
+	})
+	assert.NoError(err)
+	assert.Equal(time.Millisecond+3*frameTime, results[2].ResponseTime)
+	assert.False(results[2].Schedulable)
+
+	// should sort by CAN-ID and not by message id
+	assert.NoError(msgA.SetStaticCANID(0x100))
+	results, err = bus.AnalyzeResponseTimes(&ResponseTimeOptions{DefaultCycleTime: 10})
+	assert.NoError(err)
+	assert.Equal("msg_a", results[2].Message.Name())
+	assert.Equal(time.Duration(0), results[2].Blocking)
+
+	// should not be schedulable because the bus is overloaded
+	msgB.SetCycleTime(1)
+	results, err = bus.AnalyzeResponseTimes(&ResponseTimeOptions{DefaultCycleTime: 10})
+	assert.NoError(err)
+	for _, res := range results {
+		assert.False(res.Schedulable)
+	}
+}
+
+func Test_getArbitrationKey(t *testing.T) {
+	assert := assert.New(t)
+
+	// should give the priority to the standard frame with the same base id
+	std := getArbitrationKey(0x123, CANIDFormatStandard)
+	ext := getArbitrationKey(0x123<<18|0x3FFFF, CANIDFormatExtended)
+	extLow := getArbitrationKey(0x122<<18|0x3FFFF, CANIDFormatExtended)
+	assert.Less(std, ext)
+	assert.Less(extLow, std)
+}
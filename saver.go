@@ -10,6 +10,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -28,11 +29,9 @@ type SaveNetworkOptions struct {
 
 // SaveNetwork saves the given [Network] to the writers of the given [SaveNetworkOptions].
 //
-// It returns [ArgError] if all writers are nil.
+// It returns [ArgError] if all writers are nil, or if a gateway route
+// refers to a message or a signal that has been removed from the network.
 func SaveNetwork(network *Network, opts *SaveNetworkOptions) error {
-	saver := newSaver()
-	protoNet := saver.saveNetwork(network)
-
 	wWire := opts.WireWriter
 	wJSON := opts.JSONWriter
 	wText := opts.TextWriter
@@ -41,6 +40,12 @@ func SaveNetwork(network *Network, opts *SaveNetworkOptions) error {
 		return newArgError("wWire, wJSON, wText", ErrIsNil)
 	}
 
+	saver := newSaver()
+	protoNet, err := saver.saveNetwork(network)
+	if err != nil {
+		return err
+	}
+
 	if wWire != nil {
 		data, err := proto.Marshal(protoNet)
 		if err != nil {
@@ -80,6 +85,7 @@ func SaveNetwork(network *Network, opts *SaveNetworkOptions) error {
 type saver struct {
 	refCANIDBuilders map[EntityID]*CANIDBuilder
 	refNodes         map[EntityID]*Node
+	refMessages      map[EntityID]*Message
 	refSigTypes      map[EntityID]*SignalType
 	refSigUnits      map[EntityID]*SignalUnit
 	refSigEnums      map[EntityID]*SignalEnum
@@ -90,6 +96,7 @@ func newSaver() *saver {
 	return &saver{
 		refCANIDBuilders: make(map[EntityID]*CANIDBuilder),
 		refNodes:         make(map[EntityID]*Node),
+		refMessages:      make(map[EntityID]*Message),
 		refSigTypes:      make(map[EntityID]*SignalType),
 		refSigUnits:      make(map[EntityID]*SignalUnit),
 		refSigEnums:      make(map[EntityID]*SignalEnum),
@@ -136,7 +143,7 @@ func (s *saver) saveEntity(e *entity) *acmelibv2.Entity {
 	return pEnt
 }
 
-func (s *saver) saveNetwork(net *Network) *acmelibv2.Network {
+func (s *saver) saveNetwork(net *Network) (*acmelibv2.Network, error) {
 	pNet := new(acmelibv2.Network)
 
 	pNet.Entity = s.saveEntity(net.entity)
@@ -154,7 +161,11 @@ func (s *saver) saveNetwork(net *Network) *acmelibv2.Network {
 	nodes := maps.Values(s.refNodes)
 	slices.SortFunc(nodes, func(a, b *Node) int { return int(a.id - b.id) })
 	for _, node := range nodes {
-		pNet.Nodes = append(pNet.Nodes, s.saveNode(node))
+		pNode, err := s.saveNode(node)
+		if err != nil {
+			return nil, err
+		}
+		pNet.Nodes = append(pNet.Nodes, pNode)
 	}
 
 	sigTypes := maps.Values(s.refSigTypes)
@@ -181,7 +192,7 @@ func (s *saver) saveNetwork(net *Network) *acmelibv2.Network {
 		pNet.Attributes = append(pNet.Attributes, s.saveAttribute(att))
 	}
 
-	return pNet
+	return pNet, nil
 }
 
 func (s *saver) saveAttributeAssignments(attAss []*AttributeAssignment) []*acmelibv2.AttributeAssignment {
@@ -230,9 +241,16 @@ func (s *saver) saveBus(bus *Bus) *acmelibv2.Bus {
 	switch bus.typ {
 	case BusTypeCAN2A:
 		pBusType = acmelibv2.BusType_BUS_TYPE_CAN_2A
+	case BusTypeCANFD:
+		pBusType = acmelibv2.BusType_BUS_TYPE_CAN_FD
+	case BusTypeCAN2B:
+		pBusType = acmelibv2.BusType_BUS_TYPE_CAN_2B
 	}
 	pBus.Type = pBusType
 
+	pBus.DataBaudrate = uint32(bus.dataBaudrate)
+	pBus.BitRateSwitch = bus.bitRateSwitch
+
 	for _, nodeInt := range bus.NodeInterfaces() {
 		pBus.NodeInterfaces = append(pBus.NodeInterfaces, s.saveNodeInterface(nodeInt))
 	}
@@ -282,7 +300,7 @@ func (s *saver) saveCANIDBuilderOp(builderOp *CANIDBuilderOp) *acmelibv2.CANIDBu
 	return pBuilderOp
 }
 
-func (s *saver) saveNode(node *Node) *acmelibv2.Node {
+func (s *saver) saveNode(node *Node) (*acmelibv2.Node, error) {
 	pNode := new(acmelibv2.Node)
 
 	pNode.Entity = s.saveEntity(node.entity)
@@ -291,7 +309,59 @@ func (s *saver) saveNode(node *Node) *acmelibv2.Node {
 	pNode.NodeId = uint32(node.id)
 	pNode.InterfaceCount = uint32(node.interfaceCount)
 
-	return pNode
+	for _, route := range node.gatewayRoutes {
+		pRoute, err := s.saveGatewayRoute(route)
+		if err != nil {
+			return nil, node.errorf(err)
+		}
+		pNode.GatewayRoutes = append(pNode.GatewayRoutes, pRoute)
+	}
+
+	return pNode, nil
+}
+
+// saveGatewayRoute returns an error if a message or a signal of the route
+// has been removed from the network after the route was added.
+func (s *saver) saveGatewayRoute(route *GatewayRoute) (*acmelibv2.GatewayRoute, error) {
+	srcMsg := route.sourceMessage
+	tgtMsg := route.targetMessage
+
+	if _, ok := s.refMessages[srcMsg.entityID]; !ok {
+		return nil, newArgError("source_message", newNameError(srcMsg.name, ErrNotFound))
+	}
+
+	if _, ok := s.refMessages[tgtMsg.entityID]; !ok {
+		return nil, newArgError("target_message", newNameError(tgtMsg.name, ErrNotFound))
+	}
+
+	pRoute := new(acmelibv2.GatewayRoute)
+
+	pRoute.SourceMessageEntityId = srcMsg.entityID.String()
+	pRoute.TargetMessageEntityId = tgtMsg.entityID.String()
+
+	for _, mapping := range route.signalMappings {
+		srcSigID := mapping.sourceSignal.EntityID()
+		tgtSigID := mapping.targetSignal.EntityID()
+
+		if !srcMsg.signals.Has(srcSigID) {
+			return nil, newArgError("source_signal", newNameError(mapping.sourceSignal.Name(), ErrNotFound))
+		}
+
+		if !tgtMsg.signals.Has(tgtSigID) {
+			return nil, newArgError("target_signal", newNameError(mapping.targetSignal.Name(), ErrNotFound))
+		}
+
+		pRoute.SignalMappings = append(pRoute.SignalMappings, &acmelibv2.GatewaySignalMapping{
+			SourceSignalEntityId: srcSigID.String(),
+			TargetSignalEntityId: tgtSigID.String(),
+		})
+	}
+
+	if route.processingTime != 0 {
+		pRoute.ProcessingTime = durationpb.New(route.processingTime)
+	}
+
+	return pRoute, nil
 }
 
 func (s *saver) saveNodeInterface(nodeInt *NodeInterface) *acmelibv2.NodeInterface {
@@ -313,6 +383,8 @@ func (s *saver) saveNodeInterface(nodeInt *NodeInterface) *acmelibv2.NodeInterfa
 func (s *saver) saveMessage(msg *Message) *acmelibv2.Message {
 	pMsg := new(acmelibv2.Message)
 
+	s.refMessages[msg.entityID] = msg
+
 	pMsg.Entity = s.saveEntity(msg.entity)
 	pMsg.AttributeAssignments = s.saveAttributeAssignments(msg.AttributeAssignments())
 
@@ -324,6 +396,24 @@ func (s *saver) saveMessage(msg *Message) *acmelibv2.Message {
 	pMsg.StaticCanId = uint32(msg.staticCANID)
 	pMsg.HasStaticCanId = msg.hasStaticCANID
 
+	pCANIDFormat := acmelibv2.CANIDFormat_CANID_FORMAT_UNSPECIFIED
+	switch msg.canIDFormat {
+	case CANIDFormatStandard:
+		pCANIDFormat = acmelibv2.CANIDFormat_CANID_FORMAT_STANDARD
+	case CANIDFormatExtended:
+		pCANIDFormat = acmelibv2.CANIDFormat_CANID_FORMAT_EXTENDED
+	}
+	pMsg.CanIdFormat = pCANIDFormat
+
+	pTransport := acmelibv2.MessageTransport_MESSAGE_TRANSPORT_UNSPECIFIED
+	switch msg.transport {
+	case MessageTransportJ1939TP:
+		pTransport = acmelibv2.MessageTransport_MESSAGE_TRANSPORT_J1939_TP
+	case MessageTransportISOTP:
+		pTransport = acmelibv2.MessageTransport_MESSAGE_TRANSPORT_ISO_TP
+	}
+	pMsg.Transport = pTransport
+
 	pPriority := acmelibv2.MessagePriority_MESSAGE_PRIORITY_UNSPECIFIED
 	switch msg.priority {
 	case MessagePriorityVeryHigh:
@@ -334,6 +424,16 @@ func (s *saver) saveMessage(msg *Message) *acmelibv2.Message {
 		pPriority = acmelibv2.MessagePriority_MESSAGE_PRIORITY_MEDIUM
 	case MessagePriorityLow:
 		pPriority = acmelibv2.MessagePriority_MESSAGE_PRIORITY_LOW
+
+	// the J1939 priorities
+	case 4:
+		pPriority = acmelibv2.MessagePriority_MESSAGE_PRIORITY_4
+	case 5:
+		pPriority = acmelibv2.MessagePriority_MESSAGE_PRIORITY_5
+	case 6:
+		pPriority = acmelibv2.MessagePriority_MESSAGE_PRIORITY_6
+	case 7:
+		pPriority = acmelibv2.MessagePriority_MESSAGE_PRIORITY_7
 	}
 	pMsg.Priority = pPriority
 
@@ -362,9 +462,77 @@ func (s *saver) saveMessage(msg *Message) *acmelibv2.Message {
 		})
 	}
 
+	if msg.e2eProtection != nil {
+		pMsg.E2EProtection = s.saveE2EProtection(msg.e2eProtection)
+	}
+
 	return pMsg
 }
 
+func (s *saver) saveE2EProtection(prot *E2EProtection) *acmelibv2.E2EProtection {
+	pProt := new(acmelibv2.E2EProtection)
+
+	pProfile := acmelibv2.E2EProfile_E2E_PROFILE_UNSPECIFIED
+	switch prot.profile {
+	case E2EProfile1:
+		pProfile = acmelibv2.E2EProfile_E2E_PROFILE_1
+	case E2EProfile2:
+		pProfile = acmelibv2.E2EProfile_E2E_PROFILE_2
+	case E2EProfile4:
+		pProfile = acmelibv2.E2EProfile_E2E_PROFILE_4
+	case E2EProfile5:
+		pProfile = acmelibv2.E2EProfile_E2E_PROFILE_5
+	case E2EProfile11:
+		pProfile = acmelibv2.E2EProfile_E2E_PROFILE_11
+	case E2EProfileCustom:
+		pProfile = acmelibv2.E2EProfile_E2E_PROFILE_CUSTOM
+	}
+	pProt.Profile = pProfile
+
+	pProt.CrcSignalEntityId = prot.crcSignal.entityID.String()
+	pProt.CounterSignalEntityId = prot.counterSignal.entityID.String()
+
+	pProt.DataId = prot.dataID
+
+	pDataIDMode := acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_UNSPECIFIED
+	switch prot.dataIDMode {
+	case E2EDataIDModeBoth:
+		pDataIDMode = acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_BOTH
+	case E2EDataIDModeAlt:
+		pDataIDMode = acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_ALT
+	case E2EDataIDModeLow:
+		pDataIDMode = acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_LOW
+	case E2EDataIDModeNibble:
+		pDataIDMode = acmelibv2.E2EDataIDMode_E2E_DATA_ID_MODE_NIBBLE
+	}
+	pProt.DataIdMode = pDataIDMode
+
+	pProt.DataIdList = prot.dataIDList
+
+	if prot.dataIDSignal != nil {
+		pProt.DataIdSignalEntityId = prot.dataIDSignal.entityID.String()
+	}
+
+	if prot.lengthSignal != nil {
+		pProt.LengthSignalEntityId = prot.lengthSignal.entityID.String()
+	}
+
+	pProt.MaxDeltaCounter = prot.maxDeltaCounter
+
+	if prot.customCRC != nil {
+		pProt.CustomCrc = &acmelibv2.E2ECRCParams{
+			Width:      uint32(prot.customCRC.Width),
+			Polynomial: prot.customCRC.Polynomial,
+			Init:       prot.customCRC.Init,
+			XorOut:     prot.customCRC.XorOut,
+			RefIn:      prot.customCRC.RefIn,
+			RefOut:     prot.customCRC.RefOut,
+		}
+	}
+
+	return pProt
+}
+
 func (s *saver) saveSignalLayout(layout *SignalLayout) *acmelibv2.SignalLayout {
 	pLayout := new(acmelibv2.SignalLayout)
 
@@ -530,6 +698,8 @@ func (s *saver) saveSignalType(sigType *SignalType) *acmelibv2.SignalType {
 		pKind = acmelibv2.SignalTypeKind_SIGNAL_TYPE_KIND_INTEGER
 	case SignalTypeKindDecimal:
 		pKind = acmelibv2.SignalTypeKind_SIGNAL_TYPE_KIND_DECIMAL
+	case SignalTypeKindFloat:
+		pKind = acmelibv2.SignalTypeKind_SIGNAL_TYPE_KIND_FLOAT
 	}
 	pSigType.Kind = pKind
 
@@ -2,10 +2,12 @@ package acmelib
 
 import (
 	"cmp"
+	"math"
 	"slices"
 
 	"github.com/squadracorsepolito/acmelib/internal/collection"
 	"github.com/squadracorsepolito/acmelib/internal/stringer"
+	"github.com/squadracorsepolito/acmelib/isotp"
 )
 
 // SignalLayout represents the payload of a [Message]/[MultiplexedLayer]
@@ -418,7 +420,9 @@ func (sl *SignalLayout) verifySizeByte(sizeByte int) error {
 		return newSizeError(sizeByte, ErrIsNegative)
 	}
 
-	if sizeByte > 8 {
+	// The biggest payload is the one sent with a transport protocol,
+	// the bus is responsible for further checks
+	if sizeByte > max(J1939MaxTPSizeByte, isotp.MaxSizeByte) {
 		return newSizeError(sizeByte, ErrTooBig)
 	}
 
@@ -761,7 +765,9 @@ func (sl *SignalLayout) decodeStandardSignal(stdSig *StandardSignal, rawValue ui
 
 	sigType := stdSig.typ
 
-	if sigType.signed {
+	// Float raw values are the binary representation of the number,
+	// so they must not be sign extended
+	if sigType.signed && sigType.kind != SignalTypeKindFloat {
 		if rawValue&(1<<sigType.size-1) != 0 {
 			// extend sign of raw value
 			rawValue |= (1<<64 - 1) << sigType.size
@@ -790,6 +796,18 @@ func (sl *SignalLayout) decodeStandardSignal(stdSig *StandardSignal, rawValue ui
 		} else {
 			value = float64(rawValue)*sigType.scale + sigType.offset
 		}
+
+	case SignalTypeKindFloat:
+		valueType = SignalValueTypeFloat
+
+		var floatValue float64
+		if sigType.size == 32 {
+			floatValue = float64(math.Float32frombits(uint32(rawValue)))
+		} else {
+			floatValue = math.Float64frombits(rawValue)
+		}
+
+		value = floatValue*sigType.scale + sigType.offset
 	}
 
 	return newSignalDecoding(stdSig, rawValue, valueType, value, unit)
@@ -849,6 +867,7 @@ func (sl *SignalLayout) decodeCurrentSignal(decodings *[]*SignalDecoding, data [
 // When the layout contains multiplexed layers, it calls recursively the Decode method.
 // In that case, the decoded muxor signal are placed in the slice before
 // the multiplexed layer signals.
+// The signals that do not fit completely in the data are skipped.
 func (sl *SignalLayout) Decode(data []byte) []*SignalDecoding {
 	signalCount := sl.ibst.Size()
 
@@ -865,19 +884,18 @@ func (sl *SignalLayout) Decode(data []byte) []*SignalDecoding {
 	var currSig Signal
 	var rawValue uint64
 
+	// Set when a byte of the current signal is missing from the data
+	isTruncated := false
+
 	dataLen := len(data)
 
 	// Filters are sorted by entity id, so only adiacent filters belong to the same signal
 	for _, filter := range sl.filters {
-		if filter.byteIdx >= dataLen {
-			break
-		}
-
 		entID := filter.signal.EntityID()
 
 		// New signal to filter
 		if entID != prevEntID {
-			if currSig != nil {
+			if currSig != nil && !isTruncated {
 				sl.decodeCurrentSignal(&decodings, data, currSig, rawValue)
 			}
 
@@ -885,6 +903,13 @@ func (sl *SignalLayout) Decode(data []byte) []*SignalDecoding {
 			consumedBits = 0
 			currSig = filter.signal
 			rawValue = 0
+			isTruncated = false
+		}
+
+		// The signals that do not fit completely in the data are skipped
+		if filter.byteIdx >= dataLen {
+			isTruncated = true
+			continue
 		}
 
 		tmpData := uint64((data[filter.byteIdx] & filter.mask) >> filter.leftOffset)
@@ -902,7 +927,7 @@ func (sl *SignalLayout) Decode(data []byte) []*SignalDecoding {
 		rawValue |= tmpData
 	}
 
-	if currSig != nil {
+	if currSig != nil && !isTruncated {
 		sl.decodeCurrentSignal(&decodings, data, currSig, rawValue)
 	}
 
@@ -915,11 +940,20 @@ func (sl *SignalLayout) Decode(data []byte) []*SignalDecoding {
 // ------ //
 ////////////
 
-// encodeCurrentSignal encodes the signal with the given raw value.
+// signalRawValueGetter returns the raw value to encode for the given signal.
+type signalRawValueGetter func(sig Signal) (uint64, error)
+
+// encodeCurrentSignal encodes the signal with the raw value returned by the getter.
 // It takes the start and end index of the filters used to encode the signal.
-func (sl *SignalLayout) encodeCurrentSignal(encData []byte, sig Signal, filterStart, filterEnd int) {
+// It also calls recursively the encodeSignals method for encoding signals
+// contained into a multiplexed layer.
+func (sl *SignalLayout) encodeCurrentSignal(encData []byte, sig Signal, filterStart, filterEnd int, getRawValue signalRawValueGetter) error {
+	rawValue, err := getRawValue(sig)
+	if err != nil {
+		return err
+	}
+
 	consumedBits := 0
-	rawValue := sig.EncodedValue()
 
 	for i := filterStart; i <= filterEnd; i++ {
 		filter := sl.filters[i]
@@ -957,21 +991,27 @@ func (sl *SignalLayout) encodeCurrentSignal(encData []byte, sig Signal, filterSt
 		consumedBits += filter.length
 	}
 
-	if sig.Kind() == SignalKindMuxor {
-		muxorSig, err := sig.ToMuxor()
-		if err != nil {
-			panic(err)
-		}
-		layoutID := int(rawValue)
-		innerLayout := muxorSig.parentMuxLayer.GetLayout(layoutID)
+	if sig.Kind() != SignalKindMuxor {
+		return nil
+	}
+
+	muxorSig, err := sig.ToMuxor()
+	if err != nil {
+		panic(err)
+	}
 
-		innerLayout.encodeSignals(encData)
+	layoutID := int(rawValue)
+	innerLayout := muxorSig.parentMuxLayer.GetLayout(layoutID)
+	if innerLayout == nil {
+		return nil
 	}
+
+	return innerLayout.encodeSignals(encData, getRawValue)
 }
 
 // encodeSignals encodes the signals in the signal layout without
 // allocating a new slice of bytes.
-func (sl *SignalLayout) encodeSignals(encData []byte) {
+func (sl *SignalLayout) encodeSignals(encData []byte, getRawValue signalRawValueGetter) error {
 	prevEntID := EntityID("")
 	var currSig Signal
 	filterStart := 0
@@ -983,7 +1023,9 @@ func (sl *SignalLayout) encodeSignals(encData []byte) {
 		// New signal to encode
 		if entID != prevEntID {
 			if currSig != nil {
-				sl.encodeCurrentSignal(encData, currSig, filterStart, filterIdx-1)
+				if err := sl.encodeCurrentSignal(encData, currSig, filterStart, filterIdx-1, getRawValue); err != nil {
+					return err
+				}
 			}
 
 			prevEntID = entID
@@ -993,13 +1035,175 @@ func (sl *SignalLayout) encodeSignals(encData []byte) {
 	}
 
 	if currSig != nil {
-		sl.encodeCurrentSignal(encData, currSig, filterStart, len(sl.filters)-1)
+		return sl.encodeCurrentSignal(encData, currSig, filterStart, len(sl.filters)-1, getRawValue)
 	}
+
+	return nil
 }
 
 // Encode returns the encoded data for the given signal layout.
+// The raw values are taken from the encoded value of each signal.
 func (sl *SignalLayout) Encode() []byte {
 	encData := make([]byte, sl.sizeByte)
-	sl.encodeSignals(encData)
+
+	// The getter never fails, so the error can be ignored
+	_ = sl.encodeSignals(encData, func(sig Signal) (uint64, error) {
+		return sig.EncodedValue(), nil
+	})
+
 	return encData
 }
+
+// encodeSignalValue returns the raw value of the given signal from a physical value.
+func (sl *SignalLayout) encodeSignalValue(sig Signal, value any) (uint64, error) {
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		return stdSig.encodeValue(value)
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		return enumSig.encodeValue(value)
+
+	case SignalKindMuxor:
+		muxorSig, err := sig.ToMuxor()
+		if err != nil {
+			panic(err)
+		}
+
+		return muxorSig.encodeValue(value)
+	}
+
+	return 0, ErrInvalidType
+}
+
+// EncodeValues encodes the given physical values into the data buffer.
+// Unlike [SignalLayout.Encode], it does not read nor modify the encoded value
+// of the signals, so it is safe to call it concurrently on the same layout.
+//
+// The values map is keyed by signal name. The accepted values are:
+//   - any numeric type (or a bool for flags) for standard signals.
+//   - the name or the index of the enum value for enum signals.
+//   - the layout id for muxor signals.
+//
+// Only the signals of the layouts selected by the muxors need a value,
+// the other ones are ignored. The first SizeByte bytes of data are overwritten.
+//
+// It returns:
+//   - [SizeError] if data is smaller than the layout size.
+//   - [SignalValueError] if the value of a signal is missing or invalid.
+func (sl *SignalLayout) EncodeValues(data []byte, values map[string]any) error {
+	if len(data) < sl.sizeByte {
+		return newSizeError(len(data), ErrTooSmall)
+	}
+
+	encData := data[:sl.sizeByte]
+	clear(encData)
+
+	return sl.encodeSignals(encData, sl.valuesRawValueGetter(values))
+}
+
+// valuesRawValueGetter returns a getter that takes the raw value of a signal
+// from the physical value in the given map, keyed by signal name.
+func (sl *SignalLayout) valuesRawValueGetter(values map[string]any) signalRawValueGetter {
+	return func(sig Signal) (uint64, error) {
+		sigName := sig.Name()
+
+		value, ok := values[sigName]
+		if !ok {
+			return 0, newSignalValueError(sigName, ErrIsMissing)
+		}
+
+		rawValue, err := sl.encodeSignalValue(sig, value)
+		if err != nil {
+			return 0, newSignalValueError(sigName, err)
+		}
+
+		return rawValue, nil
+	}
+}
+
+// getSignalFilters returns the index of the first and the last filter
+// of the given signal, and whether the signal belongs to the layout.
+// Filters are sorted by entity id, so the ones of the signal are adiacent.
+func (sl *SignalLayout) getSignalFilters(sig Signal) (int, int, bool) {
+	entID := sig.EntityID()
+
+	start := -1
+	end := -1
+	for idx, filter := range sl.filters {
+		if filter.signal.EntityID() == entID {
+			if start < 0 {
+				start = idx
+			}
+			end = idx
+			continue
+		}
+
+		if start >= 0 {
+			break
+		}
+	}
+
+	return start, end, start >= 0
+}
+
+// encodeRawValue overwrites the bits of the given signal in the data
+// with the given raw value. The signal must not be a muxor.
+func (sl *SignalLayout) encodeRawValue(data []byte, sig Signal, rawValue uint64) {
+	start, end, ok := sl.getSignalFilters(sig)
+	if !ok {
+		return
+	}
+
+	for i := start; i <= end; i++ {
+		filter := sl.filters[i]
+		data[filter.byteIdx] &^= filter.mask
+	}
+
+	// The getter never fails, so the error can be ignored
+	_ = sl.encodeCurrentSignal(data, sig, start, end, func(_ Signal) (uint64, error) {
+		return rawValue, nil
+	})
+}
+
+// decodeRawValue returns the raw value of the given signal read from the data.
+func (sl *SignalLayout) decodeRawValue(data []byte, sig Signal) uint64 {
+	start, end, ok := sl.getSignalFilters(sig)
+	if !ok {
+		return 0
+	}
+
+	consumedBits := 0
+	var rawValue uint64
+
+	for i := start; i <= end; i++ {
+		filter := sl.filters[i]
+		if filter.byteIdx >= len(data) {
+			break
+		}
+
+		tmpData := uint64((data[filter.byteIdx] & filter.mask) >> filter.leftOffset)
+
+		// Little endian
+		if sig.Endianness() == EndiannessLittleEndian {
+			rawValue |= tmpData << consumedBits
+			consumedBits += filter.length
+			continue
+		}
+
+		// Big endian
+		rawValue <<= uint64(filter.length)
+		rawValue |= tmpData
+	}
+
+	return rawValue
+}
@@ -0,0 +1,391 @@
+package acmelib
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// signalLayoutLabels are the labels used to identify the signals in a rendered grid.
+const signalLayoutLabels = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// signalLayoutRenderColours is the list of colours used to fill the bits of the signals.
+var signalLayoutRenderColours = []string{
+	"#8dd3c7", "#ffffb3", "#bebada", "#fb8072", "#80b1d3", "#fdb462",
+	"#b3de69", "#fccde5", "#bc80bd", "#ccebc5", "#ffed6f", "#a6cee3",
+}
+
+const (
+	svgCellWidth    = 56
+	svgCellHeight   = 32
+	svgMarginLeft   = 64
+	svgTitleHeight  = 28
+	svgHeaderHeight = 20
+	svgLegendHeight = 18
+	svgPanelGap     = 24
+)
+
+// bitMarker describes the role of a bit of a signal in a rendered grid.
+type bitMarker int
+
+const (
+	// bitMarkerNext marks a bit followed by the next less significant bit on its right.
+	bitMarkerNext bitMarker = iota
+	// bitMarkerDown marks the last bit of a row of a big endian signal,
+	// the next less significant bit is in the byte below.
+	bitMarkerDown
+	// bitMarkerUp marks the last bit of a row of a little endian signal,
+	// the next less significant bit is in the byte above.
+	bitMarkerUp
+	bitMarkerMSB
+	bitMarkerLSB
+	bitMarkerMSBAndLSB
+)
+
+func (bm bitMarker) ascii() string {
+	switch bm {
+	case bitMarkerDown:
+		return "v"
+	case bitMarkerUp:
+		return "^"
+	case bitMarkerMSB:
+		return "M"
+	case bitMarkerLSB:
+		return "L"
+	case bitMarkerMSBAndLSB:
+		return "*"
+	default:
+		return ">"
+	}
+}
+
+func (bm bitMarker) svg() string {
+	switch bm {
+	case bitMarkerDown:
+		return "↓"
+	case bitMarkerUp:
+		return "↑"
+	case bitMarkerMSB:
+		return "MSB"
+	case bitMarkerLSB:
+		return "LSB"
+	case bitMarkerMSBAndLSB:
+		return "MSB/LSB"
+	default:
+		return "→"
+	}
+}
+
+type renderedBit struct {
+	signal Signal
+	label  string
+	colour string
+	marker bitMarker
+}
+
+type renderedLegendEntry struct {
+	signal Signal
+	label  string
+	colour string
+}
+
+// renderedPanel is the grid of a single layout.
+type renderedPanel struct {
+	title  string
+	bits   [][8]*renderedBit
+	legend []*renderedLegendEntry
+}
+
+type signalLayoutRenderer struct {
+	labels map[EntityID]int
+}
+
+func newSignalLayoutRenderer() *signalLayoutRenderer {
+	return &signalLayoutRenderer{
+		labels: make(map[EntityID]int),
+	}
+}
+
+// getLabel returns the label and the colour of the signal.
+// The same signal gets the same label in all the panels.
+func (r *signalLayoutRenderer) getLabel(sig Signal) (string, string) {
+	idx, ok := r.labels[sig.EntityID()]
+	if !ok {
+		idx = len(r.labels)
+		r.labels[sig.EntityID()] = idx
+	}
+
+	label := "#"
+	if idx < len(signalLayoutLabels) {
+		label = string(signalLayoutLabels[idx])
+	}
+
+	return label, signalLayoutRenderColours[idx%len(signalLayoutRenderColours)]
+}
+
+// renderPanels returns the panel of the layout followed by the panels
+// of all the layouts of its multiplexed layers. The empty layouts are skipped.
+// The path contains the values of the muxor signals that select the layout.
+func (r *signalLayoutRenderer) renderPanels(sl *SignalLayout, title, path string) []*renderedPanel {
+	panels := []*renderedPanel{r.renderPanel(sl, title)}
+
+	for _, muxLayer := range sl.MultiplexedLayers() {
+		for layoutID, layout := range muxLayer.iterLayouts() {
+			if layout.SignalCount() == 0 {
+				continue
+			}
+
+			layoutPath := fmt.Sprintf("%s = %d", muxLayer.muxor.name, layoutID)
+			if len(path) > 0 {
+				layoutPath = path + " / " + layoutPath
+			}
+
+			panels = append(panels, r.renderPanels(layout, layoutPath, layoutPath)...)
+		}
+	}
+
+	return panels
+}
+
+// renderLayout returns all the panels of the given layout.
+func (r *signalLayoutRenderer) renderLayout(sl *SignalLayout) []*renderedPanel {
+	switch {
+	case sl.fromMessage():
+		return r.renderPanels(sl, sl.parentMsg.name, "")
+	case sl.fromMultiplexedLayer():
+		path := fmt.Sprintf("%s = %d", sl.parentMuxLayer.muxor.name, sl.id)
+		return r.renderPanels(sl, path, path)
+	default:
+		return r.renderPanels(sl, "layout", "")
+	}
+}
+
+// renderPanel maps the filters of the layout to the bits of the grid.
+func (r *signalLayoutRenderer) renderPanel(sl *SignalLayout, title string) *renderedPanel {
+	panel := &renderedPanel{
+		title: title,
+		bits:  make([][8]*renderedBit, sl.sizeByte),
+	}
+
+	sigFilters := make(map[EntityID][]*SignalLayoutFilter)
+	for _, filter := range sl.filters {
+		sigID := filter.signal.EntityID()
+		sigFilters[sigID] = append(sigFilters[sigID], filter)
+	}
+
+	for _, sig := range sl.Signals() {
+		label, colour := r.getLabel(sig)
+		panel.legend = append(panel.legend, &renderedLegendEntry{signal: sig, label: label, colour: colour})
+
+		filters := sigFilters[sig.EntityID()]
+		isBigEndian := sig.Endianness() == EndiannessBigEndian
+
+		for idx, filter := range filters {
+			lowBit, highBit := -1, -1
+			for bit := range 8 {
+				if filter.mask&(1<<bit) == 0 {
+					continue
+				}
+
+				if lowBit == -1 {
+					lowBit = bit
+				}
+				highBit = bit
+
+				panel.bits[filter.byteIdx][bit] = &renderedBit{signal: sig, label: label, colour: colour, marker: bitMarkerNext}
+			}
+
+			if lowBit == -1 {
+				continue
+			}
+
+			row := &panel.bits[filter.byteIdx]
+
+			// The MSB is in the first byte for big endian signals
+			// and in the last byte for little endian ones
+			isFirst := idx == 0
+			isLast := idx == len(filters)-1
+			isMSBRow := (isBigEndian && isFirst) || (!isBigEndian && isLast)
+			isLSBRow := (isBigEndian && isLast) || (!isBigEndian && isFirst)
+
+			if !isLSBRow {
+				if isBigEndian {
+					row[lowBit].marker = bitMarkerDown
+				} else {
+					row[lowBit].marker = bitMarkerUp
+				}
+			}
+
+			if isMSBRow {
+				row[highBit].marker = bitMarkerMSB
+			}
+
+			if isLSBRow {
+				if row[lowBit].marker == bitMarkerMSB {
+					row[lowBit].marker = bitMarkerMSBAndLSB
+				} else {
+					row[lowBit].marker = bitMarkerLSB
+				}
+			}
+		}
+	}
+
+	return panel
+}
+
+func (r *signalLayoutRenderer) describeSignal(sig Signal) string {
+	return fmt.Sprintf("%s (%s) start %d, size %d, %s", sig.Name(), sig.Kind(), sig.StartPos(), sig.Size(), sig.Endianness())
+}
+
+// RenderASCII writes the bit grid of the signal layout as monospaced text
+// into the given [io.Writer].
+//
+// Each byte is a row of the grid with the most significant bit on the left,
+// and every signal is identified by a label that is explained in the legend below the grid.
+// The MSB and the LSB of a signal are marked with M and L (* if the signal has a single bit),
+// the other bits point to the next less significant bit: > for the bit on the right,
+// v for the byte below (big endian) and ^ for the byte above (little endian).
+// The free bits are marked with a dot.
+//
+// The grid of the layout is followed by a panel for each layout of its multiplexed layers,
+// the layouts without signals are skipped.
+func (sl *SignalLayout) RenderASCII(w io.Writer) error {
+	r := newSignalLayoutRenderer()
+
+	var b strings.Builder
+	for idx, panel := range r.renderLayout(sl) {
+		if idx > 0 {
+			b.WriteString("\n")
+		}
+		r.writeASCIIPanel(&b, panel)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r *signalLayoutRenderer) writeASCIIPanel(b *strings.Builder, panel *renderedPanel) {
+	separator := "     +" + strings.Repeat("----+", 8) + "\n"
+
+	fmt.Fprintf(b, "%s\n\n", panel.title)
+
+	header := "     "
+	for bit := 7; bit >= 0; bit-- {
+		header += fmt.Sprintf("  %d  ", bit)
+	}
+	b.WriteString(strings.TrimRight(header, " ") + "\n")
+	b.WriteString(separator)
+
+	for byteIdx, row := range panel.bits {
+		fmt.Fprintf(b, "%4d |", byteIdx)
+		for bit := 7; bit >= 0; bit-- {
+			cell := " .  "
+			if rb := row[bit]; rb != nil {
+				cell = fmt.Sprintf("%s %-2s", rb.label, rb.marker.ascii())
+			}
+			b.WriteString(cell + "|")
+		}
+		b.WriteString("\n")
+		b.WriteString(separator)
+	}
+
+	if len(panel.legend) > 0 {
+		b.WriteString("\n")
+	}
+	for _, entry := range panel.legend {
+		fmt.Fprintf(b, "  %s  %s\n", entry.label, r.describeSignal(entry.signal))
+	}
+}
+
+// RenderSVG writes the bit grid of the signal layout as an SVG image
+// into the given [io.Writer].
+//
+// Each byte is a row of the grid with the most significant bit on the left,
+// and the bits of every signal are filled with the colour shown in the legend below the grid.
+// The MSB and the LSB of a signal are marked, the other bits have an arrow
+// that points to the next less significant bit: → for the bit on the right,
+// ↓ for the byte below (big endian) and ↑ for the byte above (little endian).
+// The free bits only show their position.
+//
+// The grid of the layout is followed by a panel for each layout of its multiplexed layers,
+// the layouts without signals are skipped.
+func (sl *SignalLayout) RenderSVG(w io.Writer) error {
+	return newSignalLayoutRenderer().writeSVG(w, sl)
+}
+
+// writeSVG writes the SVG image of the given layout.
+// After the call, the labels and the colours of the signals
+// can be retrieved from the renderer.
+func (r *signalLayoutRenderer) writeSVG(w io.Writer, sl *SignalLayout) error {
+	panels := r.renderLayout(sl)
+
+	width := svgMarginLeft + 8*svgCellWidth + 1
+	height := 0
+	for _, panel := range panels {
+		height += r.getSVGPanelHeight(panel)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" font-size="11">`+"\n",
+		width, height, width, height)
+
+	y := 0
+	for _, panel := range panels {
+		r.writeSVGPanel(&b, panel, y)
+		y += r.getSVGPanelHeight(panel)
+	}
+
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r *signalLayoutRenderer) getSVGPanelHeight(panel *renderedPanel) int {
+	return svgTitleHeight + svgHeaderHeight + len(panel.bits)*svgCellHeight + len(panel.legend)*svgLegendHeight + svgPanelGap
+}
+
+func (r *signalLayoutRenderer) writeSVGPanel(b *strings.Builder, panel *renderedPanel, y int) {
+	fmt.Fprintf(b, `<g class="panel" transform="translate(0,%d)">`+"\n", y)
+	fmt.Fprintf(b, `<text x="0" y="18" font-size="14" font-weight="bold">%s</text>`+"\n", html.EscapeString(panel.title))
+
+	gridY := svgTitleHeight + svgHeaderHeight
+	for bit := range 8 {
+		x := svgMarginLeft + (7-bit)*svgCellWidth
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle">%d</text>`+"\n", x+svgCellWidth/2, gridY-6, bit)
+	}
+
+	for byteIdx, row := range panel.bits {
+		cellY := gridY + byteIdx*svgCellHeight
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">byte %d</text>`+"\n", svgMarginLeft-8, cellY+svgCellHeight/2+4, byteIdx)
+
+		for bit := range 8 {
+			x := svgMarginLeft + (7-bit)*svgCellWidth
+			pos := byteIdx*8 + bit
+
+			rb := row[bit]
+			if rb == nil {
+				fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="#fff" stroke="#bbb"/>`+"\n", x, cellY, svgCellWidth, svgCellHeight)
+				fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" fill="#bbb">%d</text>`+"\n", x+svgCellWidth/2, cellY+svgCellHeight/2+4, pos)
+				continue
+			}
+
+			fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#555"><title>%s</title></rect>`+"\n",
+				x, cellY, svgCellWidth, svgCellHeight, rb.colour, html.EscapeString(rb.signal.Name()))
+			fmt.Fprintf(b, `<text x="%d" y="%d" font-size="9" fill="#555">%d</text>`+"\n", x+3, cellY+10, pos)
+			fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle">%s %s</text>`+"\n",
+				x+svgCellWidth/2, cellY+svgCellHeight/2+8, rb.label, rb.marker.svg())
+		}
+	}
+
+	legendY := gridY + len(panel.bits)*svgCellHeight + 6
+	for idx, entry := range panel.legend {
+		entryY := legendY + idx*svgLegendHeight
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="12" height="12" fill="%s" stroke="#555"/>`+"\n", svgMarginLeft, entryY, entry.colour)
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s  %s</text>`+"\n",
+			svgMarginLeft+18, entryY+10, entry.label, html.EscapeString(r.describeSignal(entry.signal)))
+	}
+
+	b.WriteString("</g>\n")
+}
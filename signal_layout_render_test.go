@@ -0,0 +1,123 @@
+package acmelib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SignalLayout_RenderASCII(t *testing.T) {
+	assert := assert.New(t)
+
+	// little endian signals
+	basicMsg := initBasicMessage(assert)
+	res := new(strings.Builder)
+	assert.NoError(basicMsg.layout.RenderASCII(res))
+
+	expected := `basic_message
+
+       7    6    5    4    3    2    1    0
+     +----+----+----+----+----+----+----+----+
+   0 |A > |A > |A > |A > |A > |A > |A > |A L |
+     +----+----+----+----+----+----+----+----+
+   1 |B > |B > |B > |B L |A M |A > |A > |A ^ |
+     +----+----+----+----+----+----+----+----+
+   2 |B M |B > |B > |B > |B > |B > |B > |B ^ |
+     +----+----+----+----+----+----+----+----+
+   3 |C > |C > |C > |C > |C > |C > |C > |C L |
+     +----+----+----+----+----+----+----+----+
+   4 |D > |D > |D > |D L |C M |C > |C > |C ^ |
+     +----+----+----+----+----+----+----+----+
+   5 |D M |D > |D > |D > |D > |D > |D > |D ^ |
+     +----+----+----+----+----+----+----+----+
+   6 | .  | .  | .  | .  | .  | .  | .  | .  |
+     +----+----+----+----+----+----+----+----+
+   7 | .  | .  | .  | .  | .  | .  | .  | .  |
+     +----+----+----+----+----+----+----+----+
+
+  A  basic_signal_0 (standard) start 0, size 12, little-endian
+  B  basic_signal_1 (standard) start 12, size 12, little-endian
+  C  basic_signal_2 (standard) start 24, size 12, little-endian
+  D  basic_signal_3 (standard) start 36, size 12, little-endian
+`
+	assert.Equal(expected, res.String())
+
+	// big endian signals
+	bigEndianMsg := initBigEndianMessage(assert)
+	res.Reset()
+	assert.NoError(bigEndianMsg.layout.RenderASCII(res))
+	assert.Contains(res.String(), "   0 |A M |A > |A > |A > |A > |A > |A > |A v |\n")
+	assert.Contains(res.String(), "   1 |A > |A > |A > |A L |B M |B > |B > |B v |\n")
+	assert.Contains(res.String(), "   2 |B > |B > |B > |B > |B > |B > |B > |B L |\n")
+
+	// single bit signals
+	typedMsg := initTypedMessage(assert)
+	res.Reset()
+	assert.NoError(typedMsg.layout.RenderASCII(res))
+	assert.Contains(res.String(), "   0 | .  | .  | .  | .  | .  | .  | .  |A * |\n")
+
+	// should render a panel for each layout with signals
+	muxMsg := initMuxMessage(assert)
+	res.Reset()
+	assert.NoError(muxMsg.layout.RenderASCII(res))
+
+	titles := []string{}
+	for line := range strings.SplitSeq(res.String(), "\n") {
+		if strings.HasPrefix(line, "mux_message") || strings.Contains(line, " = ") {
+			titles = append(titles, line)
+		}
+	}
+	assert.Equal([]string{
+		"mux_message",
+		"top_muxor = 0",
+		"top_muxor = 1",
+		"top_muxor = 1 / top_inner_muxor = 0",
+		"top_muxor = 1 / top_inner_muxor = 255",
+		"top_muxor = 2",
+		"top_muxor = 255",
+		"bottom_muxor = 0",
+		"bottom_muxor = 1",
+		"bottom_muxor = 1 / bottom_inner_muxor = 0",
+		"bottom_muxor = 1 / bottom_inner_muxor = 255",
+		"bottom_muxor = 2",
+		"bottom_muxor = 255",
+	}, titles)
+
+	// should render a layout of a multiplexed layer with its path
+	res.Reset()
+	assert.NoError(muxMsg.layers.top.layer.GetLayout(1).RenderASCII(res))
+	assert.True(strings.HasPrefix(res.String(), "top_muxor = 1\n"))
+	assert.Contains(res.String(), "\ntop_muxor = 1 / top_inner_muxor = 255\n")
+}
+
+func Test_SignalLayout_RenderSVG(t *testing.T) {
+	assert := assert.New(t)
+
+	muxMsg := initMuxMessage(assert)
+	res := new(strings.Builder)
+	assert.NoError(muxMsg.layout.RenderSVG(res))
+
+	svg := res.String()
+	assert.True(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.True(strings.HasSuffix(svg, "</svg>\n"))
+	assert.Equal(13, strings.Count(svg, `<g class="panel"`))
+	assert.Contains(svg, `>top_muxor = 1 / top_inner_muxor = 0</text>`)
+	assert.Contains(svg, "<title>top_muxor</title>")
+	assert.Contains(svg, "MSB")
+	assert.Contains(svg, "LSB")
+	assert.Contains(svg, "top_signal_in_0 (standard) start 8, size 8, little-endian")
+
+	// should use the arrows of the endianness
+	bigEndianMsg := initBigEndianMessage(assert)
+	res.Reset()
+	assert.NoError(bigEndianMsg.layout.RenderSVG(res))
+	assert.Contains(res.String(), "A ↓</text>")
+	assert.NotContains(res.String(), "↑")
+
+	basicMsg := initBasicMessage(assert)
+	res.Reset()
+	assert.NoError(basicMsg.layout.RenderSVG(res))
+	assert.Contains(res.String(), "A ↑</text>")
+	assert.NotContains(res.String(), "↓")
+}
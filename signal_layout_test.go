@@ -476,6 +476,189 @@ func Test_SignalLayout_Encode(t *testing.T) {
 	assert.Equal(expectedEncData, layout.Encode())
 }
 
+func Test_SignalLayout_EncodeValues(t *testing.T) {
+	assert := assert.New(t)
+
+	// Typed message
+	tdTypedMsg := initTypedMessage(assert)
+	layout := tdTypedMsg.layout
+
+	expectedEncData := []byte{
+		0b00000001,
+		0b11000001,
+		0b11000001,
+		0b00000001,
+		0b11000000,
+		0b00000001,
+		0b11000000,
+	}
+
+	encData := make([]byte, 8)
+	assert.NoError(layout.EncodeValues(encData, map[string]any{
+		"flag_signal":         true,
+		"int_unsigned_signal": 193,
+		"int_signed_signal":   int8(-63),
+		"dec_unsigned_signal": 24677.0,
+		"dec_signed_signal":   float32(-8091),
+	}))
+	assert.Equal(expectedEncData, encData[:7])
+
+	// Check that the signals are left untouched
+	assert.Equal(uint64(0), tdTypedMsg.signals.intUnsigned.signal.EncodedValue())
+
+	// Enum message
+	tdEnumMsg := initEnumMessage(assert)
+	layout = tdEnumMsg.layout
+
+	expectedEncData = []byte{
+		0b00000001,
+		0b00000010,
+		0b01111111,
+		0b00000000,
+	}
+
+	encData = make([]byte, 4)
+	assert.NoError(layout.EncodeValues(encData, map[string]any{
+		"enum_signal_4_values":   "enum_value_1",
+		"enum_signal_8_values":   2,
+		"enum_signal_fixed_size": "enum_value_127",
+	}))
+	assert.Equal(expectedEncData, encData)
+
+	// Multiplexed message
+	tdMuxMsg := initMuxMessage(assert)
+	layout = tdMuxMsg.layout
+
+	expectedEncData = []byte{
+		0b00000001, // selecting layout 1 from top
+		0b11111111, // selecting layout 255 from top inner
+		0b11000001, // top inner in 255
+		0b00000001,
+		0b11000000,
+		0b11000001, // bottom in 0,2
+		0b11000001, // bottom in 0
+		0b00000000, // selecting layout 0 from bottom
+	}
+
+	values := map[string]any{
+		"top_muxor":                1,
+		"top_inner_muxor":          255,
+		"top_inner_signal_in_255":  193,
+		"base_signal":              49153,
+		"bottom_muxor":             0,
+		"bottom_signal_in_0_2":     193,
+		"bottom_signal_in_0":       193,
+		"bottom_signal_in_255":     1, // not selected, it is ignored
+		"top_inner_signal_in_0":    1, // not selected, it is ignored
+		"not_existing_signal_name": 1, // not in the layout, it is ignored
+	}
+
+	// The buffer is dirty on purpose
+	encData = []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	assert.NoError(layout.EncodeValues(encData, values))
+	assert.Equal(expectedEncData, encData[:8])
+	assert.Equal(byte(0xff), encData[8])
+
+	// Missing value
+	delete(values, "top_inner_signal_in_255")
+	err := layout.EncodeValues(encData, values)
+	assert.ErrorIs(err, ErrIsMissing)
+	var sigValErr *SignalValueError
+	assert.ErrorAs(err, &sigValErr)
+	assert.Equal("top_inner_signal_in_255", sigValErr.Name)
+
+	// Out of range value
+	values["top_inner_signal_in_255"] = 256
+	assert.ErrorIs(layout.EncodeValues(encData, values), ErrTooBig)
+	values["top_inner_signal_in_255"] = -1
+	assert.ErrorIs(layout.EncodeValues(encData, values), ErrTooSmall)
+
+	// Invalid type
+	values["top_inner_signal_in_255"] = "193"
+	assert.ErrorIs(layout.EncodeValues(encData, values), ErrInvalidType)
+	values["top_inner_signal_in_255"] = 193
+
+	// Invalid layout id
+	values["top_muxor"] = 256
+	assert.ErrorIs(layout.EncodeValues(encData, values), ErrOutOfBounds)
+	values["top_muxor"] = 1.5
+	assert.ErrorIs(layout.EncodeValues(encData, values), ErrInvalidType)
+	values["top_muxor"] = 1
+
+	// Invalid enum value
+	encData = make([]byte, 4)
+	assert.ErrorIs(tdEnumMsg.layout.EncodeValues(encData, map[string]any{
+		"enum_signal_4_values":   "enum_value_4",
+		"enum_signal_8_values":   2,
+		"enum_signal_fixed_size": 127,
+	}), ErrNotFound)
+
+	// Buffer too small
+	assert.ErrorIs(layout.EncodeValues(make([]byte, 7), values), ErrTooSmall)
+}
+
+func Test_SignalLayout_EncodeDecodeFloat(t *testing.T) {
+	assert := assert.New(t)
+
+	float32Type, err := NewFloatSignalType("float32_t", 32)
+	assert.NoError(err)
+	scaledFloat32Type, err := NewFloatSignalType("scaled_float32_t", 32)
+	assert.NoError(err)
+	scaledFloat32Type.SetScale(2)
+	scaledFloat32Type.SetOffset(10)
+
+	float32Sig, err := NewStandardSignal("float32_signal", float32Type)
+	assert.NoError(err)
+	scaledFloat32Sig, err := NewStandardSignal("scaled_float32_signal", scaledFloat32Type)
+	assert.NoError(err)
+	scaledFloat32Sig.SetEndianness(EndiannessBigEndian)
+
+	msg32 := NewMessage("float32_message", 1, 8)
+	assert.NoError(msg32.InsertSignal(float32Sig, 0))
+	assert.NoError(msg32.InsertSignal(scaledFloat32Sig, 32))
+
+	// 1.5 is 0x3fc00000, (-3 - 10) / 2 = -6.5 is 0xc0d00000
+	expectedEncData := []byte{0x00, 0x00, 0xc0, 0x3f, 0xc0, 0xd0, 0x00, 0x00}
+
+	encData := make([]byte, 8)
+	assert.NoError(msg32.EncodeValues(encData, map[string]any{
+		"float32_signal":        1.5,
+		"scaled_float32_signal": -3,
+	}))
+	assert.Equal(expectedEncData, encData)
+
+	assert.NoError(float32Sig.UpdateEncodedValue(1.5))
+	assert.NoError(scaledFloat32Sig.UpdateEncodedValue(-3))
+	assert.Equal(expectedEncData, msg32.layout.Encode())
+
+	decodings := msg32.layout.Decode(encData)
+	assert.Len(decodings, 2)
+	assert.Equal(SignalValueTypeFloat, decodings[0].ValueType)
+	assert.Equal(1.5, decodings[0].ValueAsFloat())
+	assert.Equal(uint64(0x3fc00000), decodings[0].RawValue)
+	assert.Equal(-3.0, decodings[1].ValueAsFloat())
+	assert.Equal(uint64(0xc0d00000), decodings[1].RawValue)
+
+	// Double precision
+	float64Type, err := NewFloatSignalType("float64_t", 64)
+	assert.NoError(err)
+	float64Sig, err := NewStandardSignal("float64_signal", float64Type)
+	assert.NoError(err)
+
+	msg64 := NewMessage("float64_message", 2, 8)
+	assert.NoError(msg64.InsertSignal(float64Sig, 0))
+
+	// -0.1 is 0xbfb999999999999a
+	expectedEncData = []byte{0x9a, 0x99, 0x99, 0x99, 0x99, 0x99, 0xb9, 0xbf}
+
+	assert.NoError(msg64.EncodeValues(encData, map[string]any{"float64_signal": -0.1}))
+	assert.Equal(expectedEncData, encData)
+
+	decodings = msg64.layout.Decode(encData)
+	assert.Len(decodings, 1)
+	assert.Equal(-0.1, decodings[0].ValueAsFloat())
+}
+
 func Benchmark_SignalLayout_Decode(b *testing.B) {
 	assert := assert.New(b)
 
@@ -561,3 +744,29 @@ func Benchmark_SignalLayout_EncodeMultiplexed(b *testing.B) {
 		layout.Encode()
 	}
 }
+
+func Benchmark_SignalLayout_EncodeValuesMultiplexed(b *testing.B) {
+	assert := assert.New(b)
+
+	tdMuxMsg := initMuxMessage(assert)
+	layout := tdMuxMsg.layout
+
+	values := map[string]any{
+		"top_muxor":               1,
+		"top_inner_muxor":         255,
+		"top_inner_signal_in_255": 193,
+		"base_signal":             49153,
+		"bottom_muxor":            0,
+		"bottom_signal_in_0_2":    193,
+		"bottom_signal_in_0":      193,
+	}
+
+	encData := make([]byte, 8)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for b.Loop() {
+		_ = layout.EncodeValues(encData, values)
+	}
+}
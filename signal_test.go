@@ -121,3 +121,23 @@ func Test_signal_updateSize(t *testing.T) {
 	assert.Equal(3, sigTopMuxor.Size())
 	assert.NoError(sigTopMuxor.verifyAndUpdateSize(sigTopMuxor, 8))
 }
+
+func Test_StandardSignal_UpdateEncodedValue(t *testing.T) {
+	assert := assert.New(t)
+
+	sigType, err := NewDecimalSignalType("dec_t", 8, true)
+	assert.NoError(err)
+	sigType.SetScale(0.5)
+
+	sig, err := NewStandardSignal("sig", sigType)
+	assert.NoError(err)
+
+	// should truncate the raw value
+	assert.NoError(sig.UpdateEncodedValue(1.9))
+	assert.Equal(uint64(3), sig.EncodedValue())
+
+	assert.NoError(sig.UpdateEncodedValue(-1.9))
+	assert.Equal(uint64(0xFFFFFFFFFFFFFFFD), sig.EncodedValue())
+
+	assert.Error(sig.UpdateEncodedValue(100))
+}
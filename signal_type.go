@@ -14,6 +14,9 @@ const (
 	SignalTypeKindInteger
 	// SignalTypeKindDecimal defines a signal of type float.
 	SignalTypeKindDecimal
+	// SignalTypeKindFloat defines a signal of type IEEE 754 float (32 or 64 bits).
+	// The raw value is interpreted as the binary representation of the float.
+	SignalTypeKindFloat
 )
 
 func (stk SignalTypeKind) String() string {
@@ -24,6 +27,8 @@ func (stk SignalTypeKind) String() string {
 		return "integer"
 	case SignalTypeKindDecimal:
 		return "decimal"
+	case SignalTypeKindFloat:
+		return "float"
 	default:
 		return "unknown"
 	}
@@ -112,6 +117,20 @@ func NewDecimalSignalType(name string, size int, signed bool) (*SignalType, erro
 	return newSignalType(name, SignalTypeKindDecimal, size, signed, float64(min), float64(max), 1, 0)
 }
 
+// NewFloatSignalType creates a new [SignalType] of kind [SignalTypeKindFloat]
+// with the given name and size.
+// The size must be 32 (single precision) or 64 (double precision).
+//
+// It returns an [ArgError] if the size is invalid.
+func NewFloatSignalType(name string, size int) (*SignalType, error) {
+	if size != 32 && size != 64 {
+		return nil, newArgError("size", ErrInvalidType)
+	}
+
+	min, max := getFloatMinMaxFromSize(size)
+	return newSignalType(name, SignalTypeKindFloat, size, true, min, max, 1, 0)
+}
+
 func (st *SignalType) stringify(s *stringer.Stringer) {
 	st.entity.stringify(s)
 
@@ -132,7 +151,12 @@ func (st *SignalType) String() string {
 }
 
 func (st *SignalType) genMinMax() {
-	min, max := getMinMaxFromSize(st.size, st.signed)
+	var min, max float64
+	if st.kind == SignalTypeKindFloat {
+		min, max = getFloatMinMaxFromSize(st.size)
+	} else {
+		min, max = getMinMaxFromSize(st.size, st.signed)
+	}
 
 	min *= st.scale
 	max *= st.scale
@@ -165,8 +189,9 @@ func (st *SignalType) Signed() bool {
 }
 
 // UpdateSigned updates the signed flag of the [SignalType].
+// Flag types are always unsigned and float types are always signed.
 func (st *SignalType) UpdateSigned(signed bool) {
-	if st.kind == SignalTypeKindFlag {
+	if st.kind == SignalTypeKindFlag || st.kind == SignalTypeKindFloat {
 		return
 	}
 
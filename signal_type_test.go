@@ -1,6 +1,7 @@
 package acmelib
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,3 +22,27 @@ func Test_SignalType(t *testing.T) {
 	assert.Equal(float64(-16283.5), sigType.Min())
 	assert.Equal(float64(16484), sigType.Max())
 }
+
+func Test_NewFloatSignalType(t *testing.T) {
+	assert := assert.New(t)
+
+	float32Type, err := NewFloatSignalType("float32_t", 32)
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFloat, float32Type.Kind())
+	assert.True(float32Type.Signed())
+	assert.Equal(-math.MaxFloat32, float32Type.Min())
+	assert.Equal(math.MaxFloat32, float32Type.Max())
+
+	// should not change the sign of a float type
+	float32Type.UpdateSigned(false)
+	assert.True(float32Type.Signed())
+
+	float64Type, err := NewFloatSignalType("float64_t", 64)
+	assert.NoError(err)
+	assert.Equal(64, float64Type.Size())
+	assert.Equal(math.MaxFloat64, float64Type.Max())
+
+	// should return an error because the size is not 32 or 64
+	_, err = NewFloatSignalType("float16_t", 16)
+	assert.ErrorIs(err, ErrInvalidType)
+}
@@ -0,0 +1,675 @@
+package acmelib
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SimulatorOptions are the options of a [Simulator].
+type SimulatorOptions struct {
+	// StartTime is the time of the start of the simulation, used to compute
+	// the timestamps of the frames sent by [Simulator.Advance].
+	// If it is zero, the time of the creation of the simulator is used.
+	StartTime time.Time
+	// Channel is the channel of the sent frames.
+	Channel string
+	// DefaultCycleTime is the period in ms used for the cyclic messages without a cycle time.
+	DefaultCycleTime int
+	// Repetitions is the number of times a message is sent again, spaced by its delay time,
+	// after it is triggered by a signal with repetition.
+	Repetitions int
+}
+
+// messageValues holds the current raw values of the signals of a [Message],
+// and the counter of its [E2EProtection], in order to encode its frames.
+// The signals without a raw value are encoded with their start value.
+type messageValues struct {
+	msg *Message
+
+	rawValues  map[EntityID]uint64
+	e2eCounter uint64
+}
+
+func newMessageValues(msg *Message) *messageValues {
+	return &messageValues{
+		msg: msg,
+
+		rawValues: make(map[EntityID]uint64),
+	}
+}
+
+// getRawValue returns the current raw value of the given signal.
+func (mv *messageValues) getRawValue(sig Signal) uint64 {
+	if rawValue, ok := mv.rawValues[sig.EntityID()]; ok {
+		return rawValue
+	}
+	return getStartRawValue(sig)
+}
+
+// encode returns the frame of the message with the current raw values.
+// When the message is protected, the counter is incremented at every call.
+func (mv *messageValues) encode() (*Frame, error) {
+	frame, err := mv.msg.newFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	getRawValue := func(sig Signal) (uint64, error) {
+		return mv.getRawValue(sig), nil
+	}
+
+	layout := mv.msg.layout
+
+	if prot := mv.msg.e2eProtection; prot != nil {
+		if err := prot.encodeRawValues(layout, frame.Data, getRawValue, mv.e2eCounter); err != nil {
+			return nil, mv.msg.errorf(err)
+		}
+
+		if mv.e2eCounter >= prot.maxCounter() {
+			mv.e2eCounter = 0
+		} else {
+			mv.e2eCounter++
+		}
+
+		return frame, nil
+	}
+
+	if err := layout.encodeSignals(frame.Data[:layout.sizeByte], getRawValue); err != nil {
+		return nil, mv.msg.errorf(err)
+	}
+
+	return frame, nil
+}
+
+// simulatedMessage holds the state of a [Message] sent by a [Simulator].
+// The times are relative to the start of the simulation.
+type simulatedMessage struct {
+	msg *Message
+
+	cycleTime   time.Duration
+	delayTime   time.Duration
+	isCyclic    bool
+	isIfActive  bool
+	isTriggered bool
+
+	*messageValues
+
+	signals         map[string]Signal
+	ifActiveSignals []Signal
+
+	isActive bool
+
+	hasNextCycle bool
+	nextCycle    time.Duration
+
+	hasTrigger  bool
+	triggerTime time.Duration
+	repetitions int
+
+	hasSent  bool
+	lastSent time.Duration
+}
+
+func newSimulatedMessage(msg *Message, opts *SimulatorOptions) (*simulatedMessage, error) {
+	// the transport protocols are not simulated,
+	// so every message must fit a single frame
+	if err := msg.verifySingleFrame(); err != nil {
+		return nil, err
+	}
+
+	sm := &simulatedMessage{
+		msg: msg,
+
+		delayTime: time.Duration(msg.delayTime) * time.Millisecond,
+
+		messageValues: newMessageValues(msg),
+
+		signals:         make(map[string]Signal),
+		ifActiveSignals: []Signal{},
+	}
+
+	switch msg.sendType {
+	case MessageSendTypeCyclic:
+		sm.isCyclic = true
+	case MessageSendTypeCyclicIfActive:
+		sm.isCyclic = true
+		sm.isIfActive = true
+	case MessageSendTypeCyclicAndTriggered:
+		sm.isCyclic = true
+		sm.isTriggered = true
+	case MessageSendTypeCyclicIfActiveAndTriggered:
+		sm.isCyclic = true
+		sm.isIfActive = true
+		sm.isTriggered = true
+	default:
+		sm.isTriggered = true
+	}
+
+	if sm.isCyclic {
+		cycleTime := msg.cycleTime
+		if cycleTime == 0 {
+			cycleTime = opts.DefaultCycleTime
+		}
+
+		if cycleTime <= 0 {
+			return nil, msg.errorf(newArgError("DefaultCycleTime", ErrIsZero))
+		}
+
+		sm.cycleTime = time.Duration(cycleTime) * time.Millisecond
+	}
+
+	sm.addLayoutSignals(msg.layout)
+
+	// the cyclic messages start after the start delay time,
+	// while the if active ones wait for an active signal
+	if sm.isCyclic && !sm.isIfActive {
+		sm.hasNextCycle = true
+		sm.nextCycle = time.Duration(msg.startDelayTime) * time.Millisecond
+	}
+
+	return sm, nil
+}
+
+// addLayoutSignals adds the signals of the given layout,
+// including the ones of its multiplexed layers.
+func (sm *simulatedMessage) addLayoutSignals(layout *SignalLayout) {
+	for _, sig := range layout.Signals() {
+		if _, ok := sm.signals[sig.Name()]; ok {
+			continue
+		}
+
+		sm.signals[sig.Name()] = sig
+
+		switch sig.SendType() {
+		case SignalSendTypeIfActive, SignalSendTypeIfActiveWithRepetition:
+			sm.ifActiveSignals = append(sm.ifActiveSignals, sig)
+		}
+	}
+
+	for _, muxLayer := range layout.MultiplexedLayers() {
+		for _, muxLayout := range muxLayer.Layouts() {
+			sm.addLayoutSignals(muxLayout)
+		}
+	}
+}
+
+// getStartRawValue returns the raw value of the start value of the given signal.
+func getStartRawValue(sig Signal) uint64 {
+	startValue := sig.StartValue()
+
+	if sig.Kind() == SignalKindStandard {
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		// Float types are encoded with their binary representation
+		if stdSig.typ.kind == SignalTypeKindFloat {
+			if stdSig.typ.size == 32 {
+				return uint64(math.Float32bits(float32(startValue)))
+			}
+			return math.Float64bits(startValue)
+		}
+	}
+
+	// Negative values are stored in two's complement,
+	// the bits exceeding the size are discarded by the encoder
+	return uint64(int64(math.Round(startValue)))
+}
+
+// updateActive updates whether the message is active,
+// which happens when an if active signal is different from its start value.
+// When the message becomes active, the cyclic transmission starts immediately.
+func (sm *simulatedMessage) updateActive(now time.Duration) {
+	isActive := false
+	for _, sig := range sm.ifActiveSignals {
+		if sm.getRawValue(sig) != getStartRawValue(sig) {
+			isActive = true
+			break
+		}
+	}
+
+	if isActive && !sm.isActive {
+		sm.hasNextCycle = true
+		sm.nextCycle = now
+	} else if !isActive {
+		sm.hasNextCycle = false
+	}
+
+	sm.isActive = isActive
+}
+
+// trigger requests a transmission of the message at the given time,
+// followed by the given number of repetitions.
+func (sm *simulatedMessage) trigger(now time.Duration, repetitions int) {
+	if !sm.hasTrigger || sm.triggerTime > now {
+		sm.hasTrigger = true
+		sm.triggerTime = now
+	}
+	sm.repetitions = max(sm.repetitions, repetitions)
+}
+
+// nextTime returns the time of the next transmission of the message,
+// which is delayed to respect the delay time from the previous one.
+// It returns false if no transmission is scheduled.
+func (sm *simulatedMessage) nextTime() (time.Duration, bool) {
+	var next time.Duration
+	hasNext := false
+
+	if sm.hasNextCycle {
+		next = sm.nextCycle
+		hasNext = true
+	}
+
+	if sm.hasTrigger && (!hasNext || sm.triggerTime < next) {
+		next = sm.triggerTime
+		hasNext = true
+	}
+
+	if hasNext && sm.hasSent {
+		next = max(next, sm.lastSent+sm.delayTime)
+	}
+
+	return next, hasNext
+}
+
+// sent updates the schedule of the message after a transmission at the given time.
+func (sm *simulatedMessage) sent(now time.Duration) {
+	sm.hasSent = true
+	sm.lastSent = now
+
+	if sm.hasNextCycle {
+		for sm.nextCycle <= now {
+			sm.nextCycle += sm.cycleTime
+		}
+	}
+
+	if sm.hasTrigger && sm.triggerTime <= now {
+		if sm.repetitions > 0 {
+			sm.repetitions--
+			sm.triggerTime = now + sm.delayTime
+		} else {
+			sm.hasTrigger = false
+		}
+	}
+}
+
+// Simulator simulates the transmission of the messages sent by a [NodeInterface]
+// or by all the node interfaces of a [Bus], by writing their frames to a [FrameSink],
+// like a SocketCAN interface or a log. It is built by [NodeInterface.NewSimulator]
+// or [Bus.NewSimulator].
+//
+// The signals start with their start value and they can be updated
+// by [Simulator.SetSignalValue]. The messages are sent according to their send type:
+//   - cyclic: every cycle time, starting after the start delay time.
+//   - cyclic if active: every cycle time, while at least one of the signals with an
+//     if active send type is different from its start value. The first frame is sent
+//     as soon as the message becomes active.
+//   - triggered (the "and triggered" send types and the unset one): when a signal
+//     with an on write send type is updated, when a signal with an on change send type
+//     changes its value or when a signal with an if active send type is updated
+//     with an active value. The signals "with repetition" send the message
+//     again for the number of repetitions of the options, spaced by the delay time.
+//     [Simulator.Trigger] requests a transmission explicitly.
+//
+// The delay time of a message is the minimum time between two of its transmissions,
+// so the transmissions requested before are delayed, and more requests are merged
+// into one. The frames due at the same time are sent in order of CAN-ID priority.
+//
+// The time of the simulation is either virtual, when it is moved forward
+// by [Simulator.Advance], or real, when the simulator is driven by [Simulator.Run].
+// The two methods must not be used at the same time.
+//
+// A Simulator can be used concurrently, so the signals can be updated
+// while [Simulator.Run] is running.
+type Simulator struct {
+	sink FrameSink
+	opts SimulatorOptions
+
+	mux       sync.Mutex
+	messages  []*simulatedMessage
+	msgByID   map[EntityID]*simulatedMessage
+	startTime time.Time
+	now       time.Duration
+	isRunning bool
+
+	wakeup chan struct{}
+}
+
+// NewSimulator creates a new [Simulator] for the messages sent by the [NodeInterface],
+// which writes the frames to the given sink. The options can be nil.
+//
+// It returns:
+//   - [ArgError] if the sink is nil or if the options are invalid.
+//   - [ErrIsZero] wrapped by an [ArgError] if a cyclic message does not have
+//     a cycle time and the options do not provide a default one.
+//   - [SizeError] if a message does not fit a single frame, like the ones
+//     bigger than 8 bytes sent with a [MessageTransport] (see [Message.EncodeFrame]).
+func (ni *NodeInterface) NewSimulator(sink FrameSink, opts *SimulatorOptions) (*Simulator, error) {
+	sim, err := newSimulator(sink, opts, ni.SentMessages())
+	if err != nil {
+		return nil, ni.errorf(err)
+	}
+	return sim, nil
+}
+
+// NewSimulator creates a new [Simulator] for the messages sent by all the
+// node interfaces of the [Bus], which writes the frames to the given sink.
+// The options can be nil. See [NodeInterface.NewSimulator] for the errors.
+func (b *Bus) NewSimulator(sink FrameSink, opts *SimulatorOptions) (*Simulator, error) {
+	messages := []*Message{}
+	for _, nodeInt := range b.NodeInterfaces() {
+		messages = append(messages, nodeInt.SentMessages()...)
+	}
+
+	sim, err := newSimulator(sink, opts, messages)
+	if err != nil {
+		return nil, b.errorf(err)
+	}
+	return sim, nil
+}
+
+func newSimulator(sink FrameSink, opts *SimulatorOptions, messages []*Message) (*Simulator, error) {
+	if sink == nil {
+		return nil, newArgError("sink", ErrIsNil)
+	}
+
+	if opts == nil {
+		opts = &SimulatorOptions{}
+	}
+
+	if opts.DefaultCycleTime < 0 {
+		return nil, newArgError("DefaultCycleTime", ErrIsNegative)
+	}
+
+	if opts.Repetitions < 0 {
+		return nil, newArgError("Repetitions", ErrIsNegative)
+	}
+
+	// the frames due at the same time are sent in order of priority
+	slices.SortStableFunc(messages, func(a, b *Message) int {
+		return cmp.Compare(getArbitrationKey(a.GetCANID(), a.canIDFormat), getArbitrationKey(b.GetCANID(), b.canIDFormat))
+	})
+
+	sim := &Simulator{
+		sink: sink,
+		opts: *opts,
+
+		messages:  make([]*simulatedMessage, 0, len(messages)),
+		msgByID:   make(map[EntityID]*simulatedMessage, len(messages)),
+		startTime: opts.StartTime,
+		now:       0,
+
+		wakeup: make(chan struct{}, 1),
+	}
+
+	if sim.startTime.IsZero() {
+		sim.startTime = time.Now()
+	}
+
+	for _, msg := range messages {
+		sm, err := newSimulatedMessage(msg, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		sim.messages = append(sim.messages, sm)
+		sim.msgByID[msg.entityID] = sm
+	}
+
+	return sim, nil
+}
+
+// Elapsed returns the time elapsed since the start of the simulation.
+func (s *Simulator) Elapsed() time.Duration {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.getNow()
+}
+
+// getNow returns the current time of the simulation.
+func (s *Simulator) getNow() time.Duration {
+	if s.isRunning {
+		return time.Since(s.startTime)
+	}
+	return s.now
+}
+
+// getMessage returns the state of the given message.
+func (s *Simulator) getMessage(msg *Message) (*simulatedMessage, error) {
+	if msg == nil {
+		return nil, newArgError("message", ErrIsNil)
+	}
+
+	sm, ok := s.msgByID[msg.entityID]
+	if !ok {
+		return nil, newArgError("message", ErrNotFound)
+	}
+
+	return sm, nil
+}
+
+// SetSignalValue updates the physical value of the signal with the given name
+// of the given message, and it triggers the transmission of the message
+// according to the send types. See [SignalLayout.EncodeValues] for the accepted values.
+//
+// It returns:
+//   - [ArgError] if the message is not sent by the simulator.
+//   - [SignalValueError] if the signal does not exist or if the value is invalid.
+func (s *Simulator) SetSignalValue(msg *Message, signalName string, value any) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	sm, err := s.getMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	sig, ok := sm.signals[signalName]
+	if !ok {
+		return msg.errorf(newSignalValueError(signalName, ErrNotFound))
+	}
+
+	rawValue, err := msg.layout.encodeSignalValue(sig, value)
+	if err != nil {
+		return msg.errorf(newSignalValueError(signalName, err))
+	}
+
+	prevRawValue := sm.getRawValue(sig)
+	sm.rawValues[sig.EntityID()] = rawValue
+
+	now := s.getNow()
+
+	if sm.isTriggered {
+		isChanged := rawValue != prevRawValue
+		isActive := rawValue != getStartRawValue(sig)
+
+		switch sig.SendType() {
+		case SignalSendTypeOnWrite:
+			sm.trigger(now, 0)
+		case SignalSendTypeOnWriteWithRepetition:
+			sm.trigger(now, s.opts.Repetitions)
+		case SignalSendTypeOnChange:
+			if isChanged {
+				sm.trigger(now, 0)
+			}
+		case SignalSendTypeOnChangeWithRepetition:
+			if isChanged {
+				sm.trigger(now, s.opts.Repetitions)
+			}
+		case SignalSendTypeIfActive:
+			if isActive {
+				sm.trigger(now, 0)
+			}
+		case SignalSendTypeIfActiveWithRepetition:
+			if isActive {
+				sm.trigger(now, s.opts.Repetitions)
+			}
+		}
+	}
+
+	if sm.isIfActive {
+		sm.updateActive(now)
+	}
+
+	s.wake()
+
+	return nil
+}
+
+// Trigger requests a transmission of the given message as soon as
+// its delay time allows it, regardless of its send type.
+//
+// It returns an [ArgError] if the message is not sent by the simulator.
+func (s *Simulator) Trigger(msg *Message) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	sm, err := s.getMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	sm.trigger(s.getNow(), 0)
+	s.wake()
+
+	return nil
+}
+
+// wake wakes up [Simulator.Run] after a change of the schedule.
+func (s *Simulator) wake() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// nextTime returns the time of the next transmission, and whether there is one.
+func (s *Simulator) nextTime() (time.Duration, bool) {
+	var next time.Duration
+	hasNext := false
+
+	for _, sm := range s.messages {
+		if t, ok := sm.nextTime(); ok && (!hasNext || t < next) {
+			next = t
+			hasNext = true
+		}
+	}
+
+	return next, hasNext
+}
+
+// sendUntil sends all the frames due until the given time, in order of time.
+func (s *Simulator) sendUntil(until time.Duration) error {
+	for {
+		var nextMsg *simulatedMessage
+		var next time.Duration
+
+		for _, sm := range s.messages {
+			t, ok := sm.nextTime()
+			if !ok || t > until {
+				continue
+			}
+
+			if nextMsg == nil || t < next {
+				nextMsg = sm
+				next = t
+			}
+		}
+
+		if nextMsg == nil {
+			return nil
+		}
+
+		frame, err := nextMsg.encode()
+		if err != nil {
+			return err
+		}
+
+		frame.Timestamp = s.startTime.Add(next)
+		frame.Channel = s.opts.Channel
+
+		nextMsg.sent(next)
+
+		if err := s.sink.WriteFrame(frame); err != nil {
+			return err
+		}
+	}
+}
+
+// Advance moves the virtual time of the simulation forward by the given duration,
+// and it sends all the frames due in the meantime, with the timestamps
+// relative to the start time of the options.
+//
+// It returns:
+//   - [ArgError] if the duration is negative.
+//   - the error of the sink or of the encoding of a message, which stops the simulation
+//     at the time of the failed transmission.
+func (s *Simulator) Advance(d time.Duration) error {
+	if d < 0 {
+		return newArgError("d", ErrIsNegative)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if err := s.sendUntil(s.now + d); err != nil {
+		return err
+	}
+
+	s.now += d
+
+	return nil
+}
+
+// Run drives the simulation with the real clock, starting from the current
+// time of the simulation, until the given context is done.
+// The frames are sent when they are due and their timestamps are the current time.
+//
+// It returns the error of the context when it is done,
+// or the error of the sink or of the encoding of a message.
+func (s *Simulator) Run(ctx context.Context) error {
+	s.mux.Lock()
+	s.startTime = time.Now().Add(-s.now)
+	s.isRunning = true
+	s.mux.Unlock()
+
+	defer func() {
+		s.mux.Lock()
+		s.now = time.Since(s.startTime)
+		s.isRunning = false
+		s.mux.Unlock()
+	}()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		s.mux.Lock()
+		err := s.sendUntil(time.Since(s.startTime))
+		next, hasNext := s.nextTime()
+		wait := next - time.Since(s.startTime)
+		s.mux.Unlock()
+
+		if err != nil {
+			return err
+		}
+
+		timer.Stop()
+		if hasNext {
+			timer.Reset(max(wait, 0))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wakeup:
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package acmelib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tdSimulator struct {
+	bus     *Bus
+	nodeInt *NodeInterface
+
+	cyclicMsg    *Message
+	triggeredMsg *Message
+	ifActiveMsg  *Message
+}
+
+func initSimulator(assert *assert.Assertions) *tdSimulator {
+	bus := NewBus("bus")
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	uint8Type, err := NewIntegerSignalType("uint8", 8, false)
+	assert.NoError(err)
+
+	// cyclic message with a start delay
+	cyclicMsg := NewMessage("cyclic_msg", 2, 1)
+	cyclicMsg.SetSendType(MessageSendTypeCyclic)
+	cyclicMsg.SetCycleTime(10)
+	cyclicMsg.SetStartDelayTime(5)
+	cyclicSig, err := NewStandardSignal("cyclic_sig", uint8Type)
+	assert.NoError(err)
+	cyclicSig.SetStartValue(7)
+	assert.NoError(cyclicMsg.InsertSignal(cyclicSig, 0))
+	assert.NoError(nodeInt.AddSentMessage(cyclicMsg))
+
+	// triggered message with a minimum delay of 20 ms
+	triggeredMsg := NewMessage("triggered_msg", 1, 2)
+	triggeredMsg.SetDelayTime(20)
+	onChangeSig, err := NewStandardSignal("on_change_sig", uint8Type)
+	assert.NoError(err)
+	onChangeSig.SetSendType(SignalSendTypeOnChange)
+	assert.NoError(triggeredMsg.InsertSignal(onChangeSig, 0))
+	onWriteSig, err := NewStandardSignal("on_write_sig", uint8Type)
+	assert.NoError(err)
+	onWriteSig.SetSendType(SignalSendTypeOnWriteWithRepetition)
+	assert.NoError(triggeredMsg.InsertSignal(onWriteSig, 8))
+	assert.NoError(nodeInt.AddSentMessage(triggeredMsg))
+
+	// cyclic if active message
+	ifActiveMsg := NewMessage("if_active_msg", 3, 1)
+	ifActiveMsg.SetSendType(MessageSendTypeCyclicIfActive)
+	ifActiveMsg.SetCycleTime(10)
+	ifActiveSig, err := NewStandardSignal("if_active_sig", uint8Type)
+	assert.NoError(err)
+	ifActiveSig.SetSendType(SignalSendTypeIfActive)
+	assert.NoError(ifActiveMsg.InsertSignal(ifActiveSig, 0))
+	assert.NoError(nodeInt.AddSentMessage(ifActiveMsg))
+
+	return &tdSimulator{
+		bus:     bus,
+		nodeInt: nodeInt,
+
+		cyclicMsg:    cyclicMsg,
+		triggeredMsg: triggeredMsg,
+		ifActiveMsg:  ifActiveMsg,
+	}
+}
+
+// getFrameTimes returns the times of the frames of the message with the given CAN-ID.
+func getFrameTimes(frames []*Frame, startTime time.Time, canID CANID) []time.Duration {
+	times := []time.Duration{}
+	for _, frame := range frames {
+		if frame.CANID == canID {
+			times = append(times, frame.Timestamp.Sub(startTime))
+		}
+	}
+	return times
+}
+
+func Test_NodeInterface_NewSimulator(t *testing.T) {
+	assert := assert.New(t)
+
+	tdSim := initSimulator(assert)
+	sink := &testFrameSink{}
+
+	// should return an error because the sink is nil
+	_, err := tdSim.nodeInt.NewSimulator(nil, nil)
+	assert.ErrorIs(err, ErrIsNil)
+
+	// should return an error because the options are invalid
+	_, err = tdSim.nodeInt.NewSimulator(sink, &SimulatorOptions{Repetitions: -1})
+	assert.ErrorIs(err, ErrIsNegative)
+
+	// should return an error because a cyclic message does not have a cycle time
+	tdSim.cyclicMsg.SetCycleTime(0)
+	_, err = tdSim.nodeInt.NewSimulator(sink, nil)
+	assert.ErrorIs(err, ErrIsZero)
+
+	_, err = tdSim.nodeInt.NewSimulator(sink, &SimulatorOptions{DefaultCycleTime: 10})
+	assert.NoError(err)
+
+	// should return an error because the message needs the transport protocol
+	tpMsg := NewMessage("tp_msg", 4, 20)
+	assert.NoError(tpMsg.SetTransport(MessageTransportISOTP))
+	assert.NoError(tdSim.nodeInt.AddSentMessage(tpMsg))
+	_, err = tdSim.bus.NewSimulator(sink, &SimulatorOptions{DefaultCycleTime: 10})
+	assert.ErrorIs(err, ErrTooBig)
+	var sizeErr *SizeError
+	assert.ErrorAs(err, &sizeErr)
+}
+
+func Test_Simulator_Advance(t *testing.T) {
+	assert := assert.New(t)
+
+	tdSim := initSimulator(assert)
+	sink := &testFrameSink{}
+	startTime := time.Unix(1000, 0)
+
+	sim, err := tdSim.bus.NewSimulator(sink, &SimulatorOptions{
+		StartTime:   startTime,
+		Channel:     "vcan0",
+		Repetitions: 1,
+	})
+	assert.NoError(err)
+
+	// should send the cyclic message after the start delay with the start value
+	assert.NoError(sim.Advance(30 * time.Millisecond))
+	assert.Equal(30*time.Millisecond, sim.Elapsed())
+	assert.Len(sink.frames, 3)
+
+	cyclicTimes := getFrameTimes(sink.frames, startTime, 2)
+	assert.Equal([]time.Duration{5 * time.Millisecond, 15 * time.Millisecond, 25 * time.Millisecond}, cyclicTimes)
+	assert.Equal([]byte{7}, sink.frames[0].Data)
+	assert.Equal("vcan0", sink.frames[0].Channel)
+	assert.Equal(FrameDirectionTx, sink.frames[0].Direction)
+
+	// should send the triggered message when the signal changes
+	sink.frames = nil
+	assert.NoError(sim.SetSignalValue(tdSim.triggeredMsg, "on_change_sig", 1))
+	assert.NoError(sim.Advance(5 * time.Millisecond))
+
+	// should not send the message because the signal does not change
+	assert.NoError(sim.SetSignalValue(tdSim.triggeredMsg, "on_change_sig", 1))
+	assert.NoError(sim.Advance(5 * time.Millisecond))
+
+	// should delay the transmission to respect the delay time
+	assert.NoError(sim.SetSignalValue(tdSim.triggeredMsg, "on_change_sig", 2))
+	assert.NoError(sim.Advance(30 * time.Millisecond))
+
+	triggeredTimes := getFrameTimes(sink.frames, startTime, 1)
+	assert.Equal([]time.Duration{30 * time.Millisecond, 50 * time.Millisecond}, triggeredTimes)
+	assert.Equal([]byte{2, 0}, sink.frames[len(sink.frames)-3].Data)
+
+	// should repeat the transmission for the signals with repetition
+	sink.frames = nil
+	assert.NoError(sim.SetSignalValue(tdSim.triggeredMsg, "on_write_sig", 3))
+	assert.NoError(sim.Advance(50 * time.Millisecond))
+
+	triggeredTimes = getFrameTimes(sink.frames, startTime, 1)
+	assert.Equal([]time.Duration{70 * time.Millisecond, 90 * time.Millisecond}, triggeredTimes)
+
+	// should send the if active message only while it is active
+	sink.frames = nil
+	assert.NoError(sim.SetSignalValue(tdSim.ifActiveMsg, "if_active_sig", 1))
+	assert.NoError(sim.Advance(25 * time.Millisecond))
+	assert.NoError(sim.SetSignalValue(tdSim.ifActiveMsg, "if_active_sig", 0))
+	assert.NoError(sim.Advance(25 * time.Millisecond))
+
+	ifActiveTimes := getFrameTimes(sink.frames, startTime, 3)
+	assert.Equal([]time.Duration{120 * time.Millisecond, 130 * time.Millisecond, 140 * time.Millisecond}, ifActiveTimes)
+
+	// should send the messages on request in order of priority
+	sink.frames = nil
+	assert.NoError(sim.Trigger(tdSim.cyclicMsg))
+	assert.NoError(sim.Trigger(tdSim.triggeredMsg))
+	assert.NoError(sim.Advance(0))
+	assert.Len(sink.frames, 2)
+	assert.Equal(CANID(1), sink.frames[0].CANID)
+	assert.Equal(CANID(2), sink.frames[1].CANID)
+
+	// should return an error because the message or the signal are invalid
+	assert.ErrorIs(sim.SetSignalValue(NewMessage("msg", 10, 1), "sig", 1), ErrNotFound)
+	assert.ErrorIs(sim.SetSignalValue(tdSim.triggeredMsg, "sig", 1), ErrNotFound)
+	assert.ErrorIs(sim.SetSignalValue(tdSim.triggeredMsg, "on_change_sig", 256), ErrTooBig)
+	assert.ErrorIs(sim.Advance(-time.Millisecond), ErrIsNegative)
+}
+
+func Test_Simulator_Run(t *testing.T) {
+	assert := assert.New(t)
+
+	tdSim := initSimulator(assert)
+	sink := &testFrameSink{}
+
+	sim, err := tdSim.nodeInt.NewSimulator(sink, nil)
+	assert.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// should send the cyclic message with the real clock
+	assert.ErrorIs(sim.Run(ctx), context.DeadlineExceeded)
+	assert.GreaterOrEqual(sim.Elapsed(), 50*time.Millisecond)
+
+	cyclicFrames := 0
+	for _, frame := range sink.frames {
+		if frame.CANID == 2 {
+			cyclicFrames++
+		}
+	}
+	assert.GreaterOrEqual(cyclicFrames, 3)
+	assert.LessOrEqual(cyclicFrames, 5)
+}
+
+func Test_Simulator_e2e(t *testing.T) {
+	assert := assert.New(t)
+
+	msg, crcSig, counterSig := initE2EMessage(assert, 8, 4)
+	assert.NoError(msg.SetE2EProtection(NewE2EProtection(E2EProfile1, crcSig, counterSig)))
+	msg.SetSendType(MessageSendTypeCyclic)
+	msg.SetCycleTime(10)
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	sink := &testFrameSink{}
+	sim, err := nodeInt.NewSimulator(sink, nil)
+	assert.NoError(err)
+
+	assert.NoError(sim.SetSignalValue(msg, "value", 1000))
+	assert.NoError(sim.Advance(25 * time.Millisecond))
+	assert.Len(sink.frames, 3)
+
+	// should fill the protection with an incrementing counter
+	receiver, err := msg.NewE2EReceiver()
+	assert.NoError(err)
+
+	assert.Equal(E2EStatusInitial, receiver.Check(sink.frames[0].Data).Status)
+	assert.Equal(E2EStatusOK, receiver.Check(sink.frames[1].Data).Status)
+	assert.Equal(E2EStatusOK, receiver.Check(sink.frames[2].Data).Status)
+}
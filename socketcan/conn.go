@@ -0,0 +1,105 @@
+package socketcan
+
+import (
+	"iter"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// Conn is a raw CAN socket bound to a SocketCAN interface.
+// It implements [acmelib.FrameSource] and [acmelib.FrameSink].
+//
+// The received frames hold the timestamp of the kernel, the name of the interface
+// as channel and the [acmelib.FrameDirectionTx] direction if they were sent
+// by a socket of the same host (and looped back by the interface).
+//
+// A Conn can be read and written by different goroutines at the same time.
+type Conn struct {
+	channel string
+	opts    Options
+	sock    *socket
+}
+
+// Dial opens a [Conn] on the interface with the given name (for example can0 or vcan0)
+// with the given options. The options can be nil.
+// SocketCAN is only supported on Linux.
+//
+// It returns:
+//   - [errors.ErrUnsupported] if SocketCAN is not supported by the system.
+//   - an error of the system if the socket cannot be opened or bound.
+func Dial(ifname string, opts *Options) (*Conn, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	sock, err := openSocket(ifname, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conn{
+		channel: ifname,
+		opts:    *opts,
+		sock:    sock,
+	}, nil
+}
+
+// Channel returns the name of the interface of the [Conn].
+func (c *Conn) Channel() string {
+	return c.channel
+}
+
+// Read waits for the next frame received by the interface that passes the options.
+// It returns [io.EOF] after the [Conn] is closed.
+//
+// It returns:
+//   - [ErrInvalidFrame] if the kernel returns a malformed frame.
+//   - [os.ErrDeadlineExceeded] if the deadline set by [Conn.SetReadDeadline] is exceeded.
+func (c *Conn) Read() (*acmelib.Frame, error) {
+	frame, err := c.sock.read()
+	if err != nil {
+		return nil, err
+	}
+
+	frame.Channel = c.channel
+
+	return frame, nil
+}
+
+// Frames returns an iterator over the received frames,
+// which stops when the [Conn] is closed or on the first error.
+func (c *Conn) Frames() iter.Seq2[*acmelib.Frame, error] {
+	return acmelib.ReadFrames(c)
+}
+
+// WriteFrame sends the given frame on the interface.
+//
+// It returns:
+//   - [ErrFDDisabled] if the frame is a CAN FD frame and the [Conn] is not enabled for CAN FD.
+//   - [ErrInvalidFrame] if the payload does not fit the frame.
+//   - an error of the system if the frame cannot be sent (for example when the
+//     transmit queue of the interface is full).
+func (c *Conn) WriteFrame(frame *acmelib.Frame) error {
+	if frame.IsFD && !c.opts.FD {
+		return ErrFDDisabled
+	}
+
+	buf, err := encodeFrame(frame)
+	if err != nil {
+		return err
+	}
+
+	return c.sock.write(buf)
+}
+
+// SetReadDeadline sets the deadline of the pending and future calls to [Conn.Read].
+// A zero value means that they do not time out.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.sock.setReadDeadline(t)
+}
+
+// Close closes the [Conn], which unblocks the pending calls to [Conn.Read].
+func (c *Conn) Close() error {
+	return c.sock.close()
+}
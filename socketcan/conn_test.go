@@ -0,0 +1,58 @@
+package socketcan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+// testInterface is the virtual interface used by the tests, which can be created with:
+//
+//	ip link add dev vcan0 type vcan && ip link set vcan0 mtu 72 up
+const testInterface = "vcan0"
+
+func Test_Conn_WriteFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	sender, err := Dial(testInterface, &Options{FD: true})
+	if err != nil {
+		t.Skipf("interface %s not available: %v", testInterface, err)
+	}
+	defer sender.Close()
+
+	receiver, err := Dial(testInterface, &Options{
+		FD:      true,
+		Filters: []Filter{NewFilter(0x10, acmelib.CANIDFormatStandard), NewFilter(0x20, acmelib.CANIDFormatExtended)},
+	})
+	assert.NoError(err)
+	defer receiver.Close()
+
+	frames := []*acmelib.Frame{
+		{CANID: 0x10, Data: []byte{1, 2, 3}},
+		{CANID: 0x11, Data: []byte{4}},
+		{CANID: 0x20, IsExtended: true, IsFD: true, BitRateSwitch: true, Data: make([]byte, 12)},
+	}
+	for _, frame := range frames {
+		assert.NoError(sender.WriteFrame(frame))
+	}
+
+	// should receive only the filtered frames
+	assert.NoError(receiver.SetReadDeadline(time.Now().Add(time.Second)))
+
+	frame, err := receiver.Read()
+	assert.NoError(err)
+	assert.Equal(acmelib.CANID(0x10), frame.CANID)
+	assert.Equal([]byte{1, 2, 3}, frame.Data)
+	assert.Equal(testInterface, frame.Channel)
+	assert.Equal(acmelib.FrameDirectionTx, frame.Direction)
+	assert.False(frame.Timestamp.IsZero())
+
+	frame, err = receiver.Read()
+	assert.NoError(err)
+	assert.Equal(acmelib.CANID(0x20), frame.CANID)
+	assert.True(frame.IsExtended)
+	assert.True(frame.IsFD)
+	assert.Len(frame.Data, 12)
+}
@@ -0,0 +1,14 @@
+// Package socketcan sends and receives frames through the CAN interfaces
+// of the Linux kernel (SocketCAN), like can0 or the virtual vcan0.
+//
+// A [Conn] is a raw CAN socket bound to an interface, which supports
+// the CAN 2.0 and the CAN FD frames, the kernel filters on the CAN-IDs
+// and the error frames selected by an [ErrorClass] mask.
+// It implements both [acmelib.FrameSource] and [acmelib.FrameSink], so the received
+// frames can be decoded by [acmelib.Decoder.DecodeFrames] (see [acmelib.ReadFrames])
+// and the frames built by [acmelib.Message.EncodeFrame] can be sent on the bus.
+//
+// A [VirtualBus] connects in memory the [VirtualConn]s opened on it,
+// with the same options and the same delivery rules of the kernel.
+// It can replace the interfaces in the tests and on the systems without SocketCAN.
+package socketcan
@@ -0,0 +1,12 @@
+package socketcan
+
+import "errors"
+
+var (
+	// ErrInvalidFrame is returned when a frame cannot be encoded
+	// or when the kernel returns a frame with an unexpected size.
+	ErrInvalidFrame = errors.New("invalid frame")
+	// ErrFDDisabled is returned when a CAN FD frame is sent
+	// on a connection that is not enabled for CAN FD.
+	ErrFDDisabled = errors.New("CAN FD disabled")
+)
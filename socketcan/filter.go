@@ -0,0 +1,94 @@
+package socketcan
+
+import (
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// Filter is a filter of the kernel on the CAN-IDs of the received frames.
+// A frame passes the filter when the bits selected by the mask
+// of its CAN-ID, flags included, are equal to the ones of the filter ID.
+type Filter struct {
+	// ID is the CAN-ID of the filter, with the flags of the kernel.
+	ID uint32
+	// Mask selects the bits of the CAN-ID that are compared.
+	Mask uint32
+	// Inverted reports whether the filter passes the frames that do not match.
+	Inverted bool
+}
+
+// NewFilter returns a [Filter] that passes only the data frames
+// with the given CAN-ID and format.
+func NewFilter(canID acmelib.CANID, format acmelib.CANIDFormat) Filter {
+	if format == acmelib.CANIDFormatExtended {
+		return Filter{
+			ID:   FlagExtended | uint32(canID)&MaskExtended,
+			Mask: FlagExtended | FlagRemote | MaskExtended,
+		}
+	}
+
+	return Filter{
+		ID:   uint32(canID) & MaskStandard,
+		Mask: FlagExtended | FlagRemote | MaskStandard,
+	}
+}
+
+// NewMessageFilters returns a [Filter] for each of the given messages,
+// which passes the data frames with the CAN-ID and the format of the message.
+func NewMessageFilters(messages ...*acmelib.Message) []Filter {
+	filters := make([]Filter, 0, len(messages))
+	for _, msg := range messages {
+		filters = append(filters, NewFilter(msg.GetCANID(), msg.CANIDFormat()))
+	}
+	return filters
+}
+
+// match returns whether the given CAN-ID, with the flags of the kernel,
+// passes the filter.
+func (f Filter) match(rawID uint32) bool {
+	matches := rawID&f.Mask == f.ID&f.Mask
+	if f.Inverted {
+		return !matches
+	}
+	return matches
+}
+
+// Options are the options of a connection.
+type Options struct {
+	// Filters are the filters on the CAN-IDs of the received data frames.
+	// A frame is received if it passes at least one filter.
+	// If they are empty, all the data frames are received.
+	Filters []Filter
+	// ErrorMask selects the classes of the received error frames.
+	// If it is zero, the error frames are not received.
+	ErrorMask ErrorClass
+	// FD reports whether the connection sends and receives CAN FD frames.
+	FD bool
+	// ReceiveOwn reports whether the connection receives the frames sent by itself.
+	ReceiveOwn bool
+}
+
+// accept returns whether the given frame is received
+// by a connection with the options.
+func (opts *Options) accept(frame *acmelib.Frame) bool {
+	if frame.IsFD && !opts.FD {
+		return false
+	}
+
+	rawID := getRawID(frame)
+
+	if frame.IsError {
+		return ErrorClass(rawID&MaskExtended)&opts.ErrorMask != 0
+	}
+
+	if len(opts.Filters) == 0 {
+		return true
+	}
+
+	for _, filter := range opts.Filters {
+		if filter.match(rawID) {
+			return true
+		}
+	}
+
+	return false
+}
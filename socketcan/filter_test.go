@@ -0,0 +1,68 @@
+package socketcan
+
+import (
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Filter_match(t *testing.T) {
+	assert := assert.New(t)
+
+	stdFilter := NewFilter(0x100, acmelib.CANIDFormatStandard)
+	extFilter := NewFilter(0x100, acmelib.CANIDFormatExtended)
+
+	stdFrame := &acmelib.Frame{CANID: 0x100}
+	extFrame := &acmelib.Frame{CANID: 0x100, IsExtended: true}
+	remoteFrame := &acmelib.Frame{CANID: 0x100, IsRemote: true}
+
+	// should match only the frames with the same CAN-ID and format
+	assert.True(stdFilter.match(getRawID(stdFrame)))
+	assert.False(stdFilter.match(getRawID(extFrame)))
+	assert.False(stdFilter.match(getRawID(remoteFrame)))
+	assert.False(stdFilter.match(getRawID(&acmelib.Frame{CANID: 0x101})))
+
+	assert.True(extFilter.match(getRawID(extFrame)))
+	assert.False(extFilter.match(getRawID(stdFrame)))
+
+	// should match the frames that do not match the inverted filter
+	stdFilter.Inverted = true
+	assert.False(stdFilter.match(getRawID(stdFrame)))
+	assert.True(stdFilter.match(getRawID(extFrame)))
+
+	// should match a range of CAN-IDs
+	rangeFilter := Filter{ID: 0x100, Mask: 0x700}
+	assert.True(rangeFilter.match(getRawID(&acmelib.Frame{CANID: 0x1FF})))
+	assert.False(rangeFilter.match(getRawID(&acmelib.Frame{CANID: 0x200})))
+}
+
+func Test_Options_accept(t *testing.T) {
+	assert := assert.New(t)
+
+	msg := acmelib.NewMessage("msg", 1, 8)
+	assert.NoError(msg.SetStaticCANID(0x10))
+
+	opts := &Options{Filters: NewMessageFilters(msg)}
+
+	// should accept only the filtered data frames
+	assert.True(opts.accept(&acmelib.Frame{CANID: 0x10}))
+	assert.False(opts.accept(&acmelib.Frame{CANID: 0x11}))
+
+	// should accept all the data frames without filters
+	assert.True((&Options{}).accept(&acmelib.Frame{CANID: 0x11}))
+
+	// should accept the CAN FD frames only if enabled
+	fdFrame := &acmelib.Frame{CANID: 0x10, IsFD: true, Data: make([]byte, 12)}
+	assert.False(opts.accept(fdFrame))
+	opts.FD = true
+	assert.True(opts.accept(fdFrame))
+
+	// should accept the error frames that match the error mask
+	errFrame := &acmelib.Frame{CANID: acmelib.CANID(ErrorClassBusOff), IsError: true}
+	assert.False(opts.accept(errFrame))
+	opts.ErrorMask = ErrorClassBusOff | ErrorClassController
+	assert.True(opts.accept(errFrame))
+	opts.ErrorMask = ErrorClassController
+	assert.False(opts.accept(errFrame))
+}
@@ -0,0 +1,193 @@
+package socketcan
+
+import (
+	"encoding/binary"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+const (
+	// frameSize is the size of the can_frame struct of the kernel.
+	frameSize = 16
+	// fdFrameSize is the size of the canfd_frame struct of the kernel.
+	fdFrameSize = 72
+
+	// maxDataLen is the maximum payload size of a CAN 2.0 frame.
+	maxDataLen = 8
+	// maxFDDataLen is the maximum payload size of a CAN FD frame.
+	maxFDDataLen = 64
+)
+
+// Flags and masks of the CAN-ID of the kernel frames.
+const (
+	// FlagExtended is the flag of the CAN-ID of the frames with an extended CAN-ID.
+	FlagExtended uint32 = 0x80000000
+	// FlagRemote is the flag of the CAN-ID of the remote frames.
+	FlagRemote uint32 = 0x40000000
+	// FlagError is the flag of the CAN-ID of the error frames.
+	FlagError uint32 = 0x20000000
+
+	// MaskStandard is the mask of a standard (11 bits) CAN-ID.
+	MaskStandard uint32 = 0x000007FF
+	// MaskExtended is the mask of an extended (29 bits) CAN-ID.
+	MaskExtended uint32 = 0x1FFFFFFF
+)
+
+// Flags of the CAN FD frames.
+const (
+	fdFlagBRS uint8 = 0x01
+	fdFlagESI uint8 = 0x02
+	fdFlagFDF uint8 = 0x04
+)
+
+// ErrorClass is the class of an error frame, as defined by the kernel.
+// Its values are bits, which can be combined to build the error mask of a connection.
+type ErrorClass uint32
+
+const (
+	// ErrorClassTxTimeout is the class of the TX timeout errors.
+	ErrorClassTxTimeout ErrorClass = 0x00000001
+	// ErrorClassLostArbitration is the class of the lost arbitration errors.
+	ErrorClassLostArbitration ErrorClass = 0x00000002
+	// ErrorClassController is the class of the errors of the controller.
+	ErrorClassController ErrorClass = 0x00000004
+	// ErrorClassProtocol is the class of the protocol violations.
+	ErrorClassProtocol ErrorClass = 0x00000008
+	// ErrorClassTransceiver is the class of the errors of the transceiver.
+	ErrorClassTransceiver ErrorClass = 0x00000010
+	// ErrorClassNoAck is the class of the missing acknowledgements.
+	ErrorClassNoAck ErrorClass = 0x00000020
+	// ErrorClassBusOff is the class of the bus off events.
+	ErrorClassBusOff ErrorClass = 0x00000040
+	// ErrorClassBusError is the class of the bus errors.
+	ErrorClassBusError ErrorClass = 0x00000080
+	// ErrorClassRestarted is the class of the restarts of the controller.
+	ErrorClassRestarted ErrorClass = 0x00000100
+
+	// ErrorClassAll selects all the error classes.
+	ErrorClassAll ErrorClass = ErrorClass(MaskExtended)
+)
+
+// getRawID returns the CAN-ID of the given frame with the flags of the kernel.
+func getRawID(frame *acmelib.Frame) uint32 {
+	if frame.IsError {
+		return FlagError | uint32(frame.CANID)&MaskExtended
+	}
+
+	if frame.IsExtended {
+		id := FlagExtended | uint32(frame.CANID)&MaskExtended
+		if frame.IsRemote {
+			id |= FlagRemote
+		}
+		return id
+	}
+
+	id := uint32(frame.CANID) & MaskStandard
+	if frame.IsRemote {
+		id |= FlagRemote
+	}
+	return id
+}
+
+// encodeFrame returns the given frame as a can_frame or a canfd_frame struct.
+//
+// It returns an [ErrInvalidFrame] if the payload does not fit the frame.
+func encodeFrame(frame *acmelib.Frame) ([]byte, error) {
+	dataLen := len(frame.Data)
+
+	if frame.IsFD {
+		if _, err := acmelib.SizeByteToDLC(dataLen); err != nil || dataLen > maxFDDataLen {
+			return nil, ErrInvalidFrame
+		}
+
+		buf := make([]byte, fdFrameSize)
+		binary.NativeEndian.PutUint32(buf[0:], getRawID(frame))
+		buf[4] = uint8(dataLen)
+
+		flags := fdFlagFDF
+		if frame.BitRateSwitch {
+			flags |= fdFlagBRS
+		}
+		if frame.ErrorStateIndicator {
+			flags |= fdFlagESI
+		}
+		buf[5] = flags
+
+		copy(buf[8:], frame.Data)
+
+		return buf, nil
+	}
+
+	if frame.IsRemote {
+		dataLen = frame.RemoteLength
+	}
+
+	if dataLen < 0 || dataLen > maxDataLen {
+		return nil, ErrInvalidFrame
+	}
+
+	buf := make([]byte, frameSize)
+	binary.NativeEndian.PutUint32(buf[0:], getRawID(frame))
+	buf[4] = uint8(dataLen)
+
+	if !frame.IsRemote {
+		copy(buf[8:], frame.Data)
+	}
+
+	return buf, nil
+}
+
+// decodeFrame returns the frame of the given can_frame or canfd_frame struct.
+//
+// It returns an [ErrInvalidFrame] if the size of the struct is not valid.
+func decodeFrame(buf []byte) (*acmelib.Frame, error) {
+	if len(buf) != frameSize && len(buf) != fdFrameSize {
+		return nil, ErrInvalidFrame
+	}
+
+	rawID := binary.NativeEndian.Uint32(buf[0:])
+	dataLen := int(buf[4])
+
+	frame := &acmelib.Frame{}
+
+	switch {
+	case rawID&FlagError != 0:
+		frame.IsError = true
+		frame.CANID = acmelib.CANID(rawID & MaskExtended)
+
+	case rawID&FlagExtended != 0:
+		frame.IsExtended = true
+		frame.CANID = acmelib.CANID(rawID & MaskExtended)
+
+	default:
+		frame.CANID = acmelib.CANID(rawID & MaskStandard)
+	}
+
+	if len(buf) == fdFrameSize {
+		if dataLen > maxFDDataLen {
+			return nil, ErrInvalidFrame
+		}
+
+		flags := buf[5]
+		frame.IsFD = true
+		frame.BitRateSwitch = flags&fdFlagBRS != 0
+		frame.ErrorStateIndicator = flags&fdFlagESI != 0
+		frame.Data = append([]byte{}, buf[8:8+dataLen]...)
+
+		return frame, nil
+	}
+
+	if dataLen > maxDataLen {
+		return nil, ErrInvalidFrame
+	}
+
+	if rawID&FlagRemote != 0 && !frame.IsError {
+		frame.IsRemote = true
+		frame.RemoteLength = dataLen
+		return frame, nil
+	}
+
+	frame.Data = append([]byte{}, buf[8:8+dataLen]...)
+
+	return frame, nil
+}
@@ -0,0 +1,72 @@
+package socketcan
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_encodeFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	frames := []*acmelib.Frame{
+		{CANID: 0x123, Data: []byte{0x01, 0x02, 0x03}},
+		{CANID: 0x18FF1234, IsExtended: true, Data: []byte{0xAA}},
+		{CANID: 0x456, IsRemote: true, RemoteLength: 4},
+		{CANID: 0x1ABCDE, IsExtended: true, IsRemote: true, RemoteLength: 8},
+		{CANID: acmelib.CANID(ErrorClassBusOff), IsError: true, Data: make([]byte, 8)},
+		{CANID: 0x7FF, IsFD: true, BitRateSwitch: true, Data: make([]byte, 12)},
+		{CANID: 0x1FFFFFFF, IsExtended: true, IsFD: true, ErrorStateIndicator: true, Data: make([]byte, 64)},
+	}
+
+	// should decode the same frames that were encoded
+	for _, frame := range frames {
+		buf, err := encodeFrame(frame)
+		assert.NoError(err)
+
+		decFrame, err := decodeFrame(buf)
+		assert.NoError(err)
+
+		if frame.IsRemote {
+			assert.Nil(decFrame.Data)
+		} else {
+			assert.Equal(frame.Data, decFrame.Data)
+		}
+		decFrame.Data = frame.Data
+		assert.Equal(frame, decFrame)
+	}
+
+	// should encode the flags of the kernel
+	buf, err := encodeFrame(frames[3])
+	assert.NoError(err)
+	assert.Len(buf, frameSize)
+	assert.Equal(FlagExtended|FlagRemote|0x1ABCDE, binary.NativeEndian.Uint32(buf))
+	assert.Equal(uint8(8), buf[4])
+
+	buf, err = encodeFrame(frames[5])
+	assert.NoError(err)
+	assert.Len(buf, fdFrameSize)
+	assert.Equal(fdFlagFDF|fdFlagBRS, buf[5])
+
+	// should return an error because the payload does not fit the frame
+	_, err = encodeFrame(&acmelib.Frame{CANID: 1, Data: make([]byte, 9)})
+	assert.ErrorIs(err, ErrInvalidFrame)
+	_, err = encodeFrame(&acmelib.Frame{CANID: 1, IsFD: true, Data: make([]byte, 10)})
+	assert.ErrorIs(err, ErrInvalidFrame)
+}
+
+func Test_decodeFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	// should return an error because the size of the struct is not valid
+	_, err := decodeFrame(make([]byte, 20))
+	assert.ErrorIs(err, ErrInvalidFrame)
+
+	// should return an error because the length is bigger than the payload
+	buf := make([]byte, frameSize)
+	buf[4] = 9
+	_, err = decodeFrame(buf)
+	assert.ErrorIs(err, ErrInvalidFrame)
+}
@@ -0,0 +1,166 @@
+//go:build linux
+
+package socketcan
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/squadracorsepolito/acmelib"
+	"golang.org/x/sys/unix"
+)
+
+// socket is a raw CAN socket, registered to the poller of the runtime
+// in order to support the deadlines and the unblocking on close.
+type socket struct {
+	file    *os.File
+	rawConn syscall.RawConn
+}
+
+// openSocket opens a raw CAN socket bound to the given interface
+// and it applies the given options.
+func openSocket(ifname string, opts *Options) (*socket, error) {
+	iface, err := net.InterfaceByName(ifname)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := unix.Socket(unix.AF_CAN, unix.SOCK_RAW|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, unix.CAN_RAW)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+
+	if err := setSocketOptions(fd, opts); err != nil {
+		unix.Close(fd)
+		return nil, os.NewSyscallError("setsockopt", err)
+	}
+
+	if err := unix.Bind(fd, &unix.SockaddrCAN{Ifindex: iface.Index}); err != nil {
+		unix.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+
+	file := os.NewFile(uintptr(fd), ifname)
+	rawConn, err := file.SyscallConn()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &socket{
+		file:    file,
+		rawConn: rawConn,
+	}, nil
+}
+
+func setSocketOptions(fd int, opts *Options) error {
+	if len(opts.Filters) > 0 {
+		filters := make([]unix.CanFilter, 0, len(opts.Filters))
+		for _, filter := range opts.Filters {
+			id := filter.ID
+			if filter.Inverted {
+				id |= unix.CAN_INV_FILTER
+			}
+			filters = append(filters, unix.CanFilter{Id: id, Mask: filter.Mask})
+		}
+
+		if err := unix.SetsockoptCanRawFilter(fd, unix.SOL_CAN_RAW, unix.CAN_RAW_FILTER, filters); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.SetsockoptInt(fd, unix.SOL_CAN_RAW, unix.CAN_RAW_ERR_FILTER, int(opts.ErrorMask)); err != nil {
+		return err
+	}
+
+	if opts.FD {
+		if err := unix.SetsockoptInt(fd, unix.SOL_CAN_RAW, unix.CAN_RAW_FD_FRAMES, 1); err != nil {
+			return err
+		}
+	}
+
+	if opts.ReceiveOwn {
+		if err := unix.SetsockoptInt(fd, unix.SOL_CAN_RAW, unix.CAN_RAW_RECV_OWN_MSGS, 1); err != nil {
+			return err
+		}
+	}
+
+	return unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_TIMESTAMPNS, 1)
+}
+
+func (s *socket) read() (*acmelib.Frame, error) {
+	buf := make([]byte, fdFrameSize)
+	oob := make([]byte, unix.CmsgSpace(int(unsafe.Sizeof(unix.Timespec{}))))
+
+	var n, oobn, flags int
+	var recvErr error
+
+	err := s.rawConn.Read(func(fd uintptr) bool {
+		n, oobn, flags, _, recvErr = unix.Recvmsg(int(fd), buf, oob, 0)
+		return recvErr != unix.EAGAIN
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrClosed) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	if recvErr != nil {
+		return nil, os.NewSyscallError("recvmsg", recvErr)
+	}
+
+	frame, err := decodeFrame(buf[:n])
+	if err != nil {
+		return nil, err
+	}
+
+	frame.Timestamp = parseTimestamp(oob[:oobn])
+	if flags&unix.MSG_DONTROUTE != 0 {
+		frame.Direction = acmelib.FrameDirectionTx
+	}
+
+	return frame, nil
+}
+
+// parseTimestamp returns the timestamp of the kernel held by the given control messages.
+// If it is missing, the current time is returned.
+func parseTimestamp(oob []byte) time.Time {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return time.Now()
+	}
+
+	for _, msg := range msgs {
+		if msg.Header.Level != unix.SOL_SOCKET || msg.Header.Type != unix.SCM_TIMESTAMPNS {
+			continue
+		}
+
+		if len(msg.Data) < int(unsafe.Sizeof(unix.Timespec{})) {
+			continue
+		}
+
+		ts := (*unix.Timespec)(unsafe.Pointer(&msg.Data[0]))
+		return time.Unix(ts.Unix())
+	}
+
+	return time.Now()
+}
+
+func (s *socket) write(buf []byte) error {
+	_, err := s.file.Write(buf)
+	return err
+}
+
+func (s *socket) setReadDeadline(t time.Time) error {
+	return s.file.SetReadDeadline(t)
+}
+
+func (s *socket) close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,33 @@
+//go:build !linux
+
+package socketcan
+
+import (
+	"errors"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// socket is not available without SocketCAN.
+type socket struct{}
+
+func openSocket(_ string, _ *Options) (*socket, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (s *socket) read() (*acmelib.Frame, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (s *socket) write(_ []byte) error {
+	return errors.ErrUnsupported
+}
+
+func (s *socket) setReadDeadline(_ time.Time) error {
+	return errors.ErrUnsupported
+}
+
+func (s *socket) close() error {
+	return errors.ErrUnsupported
+}
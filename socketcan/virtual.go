@@ -0,0 +1,218 @@
+package socketcan
+
+import (
+	"io"
+	"iter"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+)
+
+// virtualQueueSize is the number of frames that a [VirtualConn] can hold
+// before dropping the new ones, like the receive buffer of a socket.
+const virtualQueueSize = 1024
+
+// VirtualBus is an in-memory stand-in of a SocketCAN interface.
+// The frames sent by a [VirtualConn] opened on the bus are delivered
+// to the other connections that accept them, with the same rules of the kernel:
+//   - the data frames must pass the filters of the connection.
+//   - the error frames must match the error mask of the connection.
+//   - the CAN FD frames are delivered only to the connections enabled for CAN FD.
+//   - the sender receives its own frames only if enabled by the options.
+//
+// A VirtualBus can be used concurrently.
+type VirtualBus struct {
+	channel string
+
+	mux   sync.Mutex
+	conns map[*VirtualConn]struct{}
+}
+
+// NewVirtualBus creates a new [VirtualBus]. The given channel
+// is used as the channel of the frames, like the name of an interface.
+func NewVirtualBus(channel string) *VirtualBus {
+	return &VirtualBus{
+		channel: channel,
+		conns:   make(map[*VirtualConn]struct{}),
+	}
+}
+
+// Channel returns the channel of the [VirtualBus].
+func (vb *VirtualBus) Channel() string {
+	return vb.channel
+}
+
+// Open opens a [VirtualConn] on the bus with the given options.
+// The options can be nil.
+func (vb *VirtualBus) Open(opts *Options) *VirtualConn {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	vc := &VirtualConn{
+		bus:   vb,
+		opts:  *opts,
+		queue: make(chan *acmelib.Frame, virtualQueueSize),
+		done:  make(chan struct{}),
+	}
+
+	vb.mux.Lock()
+	vb.conns[vc] = struct{}{}
+	vb.mux.Unlock()
+
+	return vc
+}
+
+// InjectFrame delivers the given frame to all the connections of the bus
+// that accept it, as if it was sent by another node.
+// It is useful to simulate the error frames raised by the controller.
+func (vb *VirtualBus) InjectFrame(frame *acmelib.Frame) error {
+	if _, err := encodeFrame(frame); err != nil {
+		return err
+	}
+
+	vb.deliver(nil, frame)
+
+	return nil
+}
+
+// deliver delivers the given frame sent by the given connection.
+func (vb *VirtualBus) deliver(sender *VirtualConn, frame *acmelib.Frame) {
+	timestamp := time.Now()
+
+	vb.mux.Lock()
+	defer vb.mux.Unlock()
+
+	for vc := range vb.conns {
+		if vc == sender && !vc.opts.ReceiveOwn {
+			continue
+		}
+
+		if !vc.opts.accept(frame) {
+			continue
+		}
+
+		received := *frame
+		received.Timestamp = timestamp
+		received.Channel = vb.channel
+		received.Data = append([]byte{}, frame.Data...)
+
+		received.Direction = acmelib.FrameDirectionRx
+		if sender != nil {
+			received.Direction = acmelib.FrameDirectionTx
+		}
+
+		select {
+		case vc.queue <- &received:
+		default:
+			// the queue is full, so the frame is dropped
+		}
+	}
+}
+
+func (vb *VirtualBus) remove(vc *VirtualConn) {
+	vb.mux.Lock()
+	delete(vb.conns, vc)
+	vb.mux.Unlock()
+}
+
+// VirtualConn is a connection opened on a [VirtualBus].
+// It behaves like a [Conn] and it implements
+// [acmelib.FrameSource] and [acmelib.FrameSink].
+type VirtualConn struct {
+	bus  *VirtualBus
+	opts Options
+
+	queue chan *acmelib.Frame
+
+	closeOnce sync.Once
+	done      chan struct{}
+
+	deadlineMux sync.Mutex
+	deadline    time.Time
+}
+
+// Channel returns the channel of the bus of the [VirtualConn].
+func (vc *VirtualConn) Channel() string {
+	return vc.bus.channel
+}
+
+// Read waits for the next frame delivered by the bus.
+// It returns [io.EOF] after the [VirtualConn] is closed.
+//
+// It returns an [os.ErrDeadlineExceeded] if the deadline set by
+// [VirtualConn.SetReadDeadline] is exceeded.
+func (vc *VirtualConn) Read() (*acmelib.Frame, error) {
+	vc.deadlineMux.Lock()
+	deadline := vc.deadline
+	vc.deadlineMux.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case frame := <-vc.queue:
+		return frame, nil
+	case <-vc.done:
+		return nil, io.EOF
+	case <-timeout:
+		return nil, os.ErrDeadlineExceeded
+	}
+}
+
+// Frames returns an iterator over the received frames,
+// which stops when the [VirtualConn] is closed or on the first error.
+func (vc *VirtualConn) Frames() iter.Seq2[*acmelib.Frame, error] {
+	return acmelib.ReadFrames(vc)
+}
+
+// WriteFrame sends the given frame on the bus.
+//
+// It returns:
+//   - [ErrFDDisabled] if the frame is a CAN FD frame and the connection is not enabled for CAN FD.
+//   - [ErrInvalidFrame] if the payload does not fit the frame.
+//   - [os.ErrClosed] if the [VirtualConn] is closed.
+func (vc *VirtualConn) WriteFrame(frame *acmelib.Frame) error {
+	select {
+	case <-vc.done:
+		return os.ErrClosed
+	default:
+	}
+
+	if frame.IsFD && !vc.opts.FD {
+		return ErrFDDisabled
+	}
+
+	if _, err := encodeFrame(frame); err != nil {
+		return err
+	}
+
+	vc.bus.deliver(vc, frame)
+
+	return nil
+}
+
+// SetReadDeadline sets the deadline of the future calls to [VirtualConn.Read].
+// A zero value means that they do not time out.
+func (vc *VirtualConn) SetReadDeadline(t time.Time) error {
+	vc.deadlineMux.Lock()
+	vc.deadline = t
+	vc.deadlineMux.Unlock()
+	return nil
+}
+
+// Close closes the [VirtualConn] and it removes it from the bus.
+// It unblocks the pending calls to [VirtualConn.Read].
+func (vc *VirtualConn) Close() error {
+	vc.closeOnce.Do(func() {
+		vc.bus.remove(vc)
+		close(vc.done)
+	})
+	return nil
+}
@@ -0,0 +1,117 @@
+package socketcan
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/squadracorsepolito/acmelib"
+	"github.com/stretchr/testify/assert"
+)
+
+type testNetwork struct {
+	net *acmelib.Network
+	bus *acmelib.Bus
+	msg *acmelib.Message
+}
+
+func initTestNetwork(assert *assert.Assertions) *testNetwork {
+	net := acmelib.NewNetwork("net")
+	bus := acmelib.NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	node := acmelib.NewNode("node", 1, 1)
+	nodeInt := node.GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := acmelib.NewMessage("msg", 1, 8)
+	assert.NoError(msg.SetStaticCANID(0x10))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	typ, err := acmelib.NewIntegerSignalType("uint8", 8, false)
+	assert.NoError(err)
+	sig, err := acmelib.NewStandardSignal("sig", typ)
+	assert.NoError(err)
+	assert.NoError(msg.InsertSignal(sig, 8))
+
+	return &testNetwork{
+		net: net,
+		bus: bus,
+		msg: msg,
+	}
+}
+
+func Test_VirtualConn_WriteFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	tdNet := initTestNetwork(assert)
+
+	vBus := NewVirtualBus("vcan0")
+	sender := vBus.Open(&Options{ReceiveOwn: true})
+	receiver := vBus.Open(&Options{Filters: NewMessageFilters(tdNet.msg)})
+	defer sender.Close()
+
+	// should send the encoded message
+	frame, err := tdNet.msg.EncodeFrame(map[string]any{"sig": 42})
+	assert.NoError(err)
+	assert.NoError(sender.WriteFrame(frame))
+
+	// should not send the frames filtered out or unsupported
+	assert.NoError(sender.WriteFrame(&acmelib.Frame{CANID: 0x20, Data: make([]byte, 8)}))
+	assert.ErrorIs(sender.WriteFrame(&acmelib.Frame{CANID: 0x10, IsFD: true, Data: make([]byte, 12)}), ErrFDDisabled)
+
+	// should receive the own frames
+	ownFrame, err := sender.Read()
+	assert.NoError(err)
+	assert.Equal(acmelib.CANID(0x10), ownFrame.CANID)
+	assert.Equal("vcan0", ownFrame.Channel)
+	assert.Equal(acmelib.FrameDirectionTx, ownFrame.Direction)
+	assert.False(ownFrame.Timestamp.IsZero())
+
+	// should decode the received frames until the connection is closed
+	dec, err := tdNet.bus.NewDecoder()
+	assert.NoError(err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		receiver.Close()
+	}()
+
+	decodings := []*acmelib.FrameDecoding{}
+	for frameDec, err := range dec.DecodeFrames(receiver.Frames()) {
+		assert.NoError(err)
+		decodings = append(decodings, frameDec)
+	}
+
+	assert.Len(decodings, 1)
+	assert.Equal(tdNet.msg.EntityID(), decodings[0].Message.EntityID())
+	assert.Equal(uint64(42), decodings[0].Signals[0].RawValue)
+
+	// should return an error because the connection is closed
+	_, err = receiver.Read()
+	assert.ErrorIs(err, io.EOF)
+}
+
+func Test_VirtualBus_InjectFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	vBus := NewVirtualBus("vcan0")
+	conn := vBus.Open(&Options{ErrorMask: ErrorClassBusOff})
+	defer conn.Close()
+
+	// should deliver only the error frames that match the error mask
+	assert.NoError(vBus.InjectFrame(&acmelib.Frame{CANID: acmelib.CANID(ErrorClassController), IsError: true, Data: make([]byte, 8)}))
+	assert.NoError(vBus.InjectFrame(&acmelib.Frame{CANID: acmelib.CANID(ErrorClassBusOff), IsError: true, Data: make([]byte, 8)}))
+
+	frame, err := conn.Read()
+	assert.NoError(err)
+	assert.True(frame.IsError)
+	assert.Equal(acmelib.CANID(ErrorClassBusOff), frame.CANID)
+	assert.Equal(acmelib.FrameDirectionRx, frame.Direction)
+
+	// should time out because there are no more frames
+	assert.NoError(conn.SetReadDeadline(time.Now().Add(5 * time.Millisecond)))
+	_, err = conn.Read()
+	assert.ErrorIs(err, os.ErrDeadlineExceeded)
+}
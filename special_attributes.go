@@ -5,40 +5,63 @@ import "github.com/squadracorsepolito/acmelib/dbc"
 type specialAttributeType int
 
 const (
-	specialAttributeMsgCycleTime specialAttributeType = iota
+	specialAttributeBusType specialAttributeType = iota
+	specialAttributeBusBaudrate
+	specialAttributeBusDataBaudrate
+
+	specialAttributeMsgCycleTime
 	specialAttributeMsgDelayTime
 	specialAttributeMsgStartDelayTime
 	specialAttributeMsgSendType
+	specialAttributeMsgFrameFormat
+	specialAttributeMsgBitRateSwitch
 
 	specialAttributeSigStartValue
 	specialAttributeSigSendType
 )
 
 var specialAttributeNames = map[specialAttributeType]string{
+	specialAttributeBusType:         dbc.BusTypeName,
+	specialAttributeBusBaudrate:     dbc.BusBaudrateName,
+	specialAttributeBusDataBaudrate: dbc.BusDataBaudrateName,
+
 	specialAttributeMsgCycleTime:      dbc.MsgCycleTimeName,
 	specialAttributeMsgDelayTime:      dbc.MsgDelayTimeName,
 	specialAttributeMsgStartDelayTime: dbc.MsgStartDelayTimeName,
 	specialAttributeMsgSendType:       dbc.MsgSendTypeName,
+	specialAttributeMsgFrameFormat:    dbc.MsgFrameFormatName,
+	specialAttributeMsgBitRateSwitch:  dbc.MsgBitRateSwitchName,
 
 	specialAttributeSigStartValue: dbc.SigStartValueName,
 	specialAttributeSigSendType:   dbc.SigSendTypeName,
 }
 
 var specialAttributeTypes = map[string]specialAttributeType{
+	dbc.BusTypeName:         specialAttributeBusType,
+	dbc.BusBaudrateName:     specialAttributeBusBaudrate,
+	dbc.BusDataBaudrateName: specialAttributeBusDataBaudrate,
+
 	dbc.MsgCycleTimeName:      specialAttributeMsgCycleTime,
 	dbc.MsgDelayTimeName:      specialAttributeMsgDelayTime,
 	dbc.MsgStartDelayTimeName: specialAttributeMsgStartDelayTime,
 	dbc.MsgSendTypeName:       specialAttributeMsgSendType,
+	dbc.MsgFrameFormatName:    specialAttributeMsgFrameFormat,
+	dbc.MsgBitRateSwitchName:  specialAttributeMsgBitRateSwitch,
 
 	dbc.SigStartValueName: specialAttributeSigStartValue,
 	dbc.SigSendTypeName:   specialAttributeSigSendType,
 }
 
 var (
+	busTypeAtt            = NewStringAttribute(dbc.BusTypeName, dbc.BusTypeCAN)
+	busBaudrateAtt, _     = NewIntegerAttribute(dbc.BusBaudrateName, dbc.BusBaudrateMin, dbc.BusBaudrateMin, dbc.BusBaudrateMax)
+	busDataBaudrateAtt, _ = NewIntegerAttribute(dbc.BusDataBaudrateName, dbc.BusDataBaudrateMin, dbc.BusDataBaudrateMin, dbc.BusDataBaudrateMax)
+
 	msgCycleTimeAtt, _      = NewIntegerAttribute(dbc.MsgCycleTimeName, dbc.MsgCycleTimeMin, dbc.MsgCycleTimeMin, dbc.MsgCycleTimeMax)
 	msgDelayTimeAtt, _      = NewIntegerAttribute(dbc.MsgDelayTimeName, dbc.MsgDelayTimeMin, dbc.MsgDelayTimeMin, dbc.MsgDelayTimeMax)
 	msgStartDelayTimeAtt, _ = NewIntegerAttribute(dbc.MsgStartDelayTimeName, dbc.MsgStartDelayTimeMin, dbc.MsgStartDelayTimeMin, dbc.MsgStartDelayTimeMax)
 	msgSendTypeAtt, _       = NewEnumAttribute(dbc.MsgSendTypeName, dbc.MsgSendTypeValues...)
+	msgBitRateSwitchAtt, _  = NewEnumAttribute(dbc.MsgBitRateSwitchName, dbc.MsgBitRateSwitchValues...)
 
 	sigStartValueAtt, _ = NewFloatAttribute(dbc.SigStartValueName, dbc.SigStartValueMin, dbc.SigStartValueMin, dbc.SigStartValueMax)
 	sigSendTypeAtt, _   = NewEnumAttribute(dbc.SigSendTypeName, dbc.SigSendTypeValues...)
@@ -1,6 +1,8 @@
 package acmelib
 
 import (
+	"math"
+
 	"github.com/squadracorsepolito/acmelib/internal/stringer"
 )
 
@@ -131,20 +133,82 @@ func (ss *StandardSignal) UpdateStartPos(newStartPos int) error {
 }
 
 // UpdateEncodedValue updates the current physical value of the signal
-// and applies the type's offset and scale.
+// and applies the type's offset and scale. The resulting raw value is truncated.
 //
 // It returns an [ArgError] if the given value is invalid.
 func (ss *StandardSignal) UpdateEncodedValue(value float64) error {
+	rawValue, err := ss.getRawValue(value, false)
+	if err != nil {
+		return ss.errorf(newArgError("value", err))
+	}
+
+	ss.encodedValue = rawValue
+
+	return nil
+}
+
+// getRawValue returns the raw value of the given physical value
+// by applying the type's offset and scale.
+// If round is false, the raw value is truncated instead of rounded.
+//
+// It returns:
+//   - [ErrTooSmall] if the value is lower than the type's min.
+//   - [ErrTooBig] if the value is greater than the type's max.
+func (ss *StandardSignal) getRawValue(value float64, round bool) (uint64, error) {
 	if value < ss.typ.min {
-		return ss.errorf(newArgError("value", ErrTooSmall))
+		return 0, ErrTooSmall
 	} else if value > ss.typ.max {
-		return ss.errorf(newArgError("value", ErrTooBig))
+		return 0, ErrTooBig
 	}
 
 	value = (value - ss.typ.offset) / ss.typ.scale
-	ss.encodedValue = uint64(value)
 
-	return nil
+	// Float types are encoded with their binary representation
+	if ss.typ.kind == SignalTypeKindFloat {
+		if ss.typ.size == 32 {
+			return uint64(math.Float32bits(float32(value))), nil
+		}
+		return math.Float64bits(value), nil
+	}
+
+	if round {
+		value = math.Round(value)
+	} else {
+		value = math.Trunc(value)
+	}
+
+	// Negative values are stored in two's complement,
+	// the bits exceeding the size are discarded by the encoder
+	if value < 0 {
+		return uint64(int64(value)), nil
+	}
+
+	return uint64(value), nil
+}
+
+// encodeValue returns the raw value of the given physical value.
+// The value can be any numeric type, or a bool if the type is a flag.
+//
+// It returns an [ErrInvalidType] if the value cannot be converted,
+// or an [ErrTooSmall]/[ErrTooBig] if the value is out of range.
+func (ss *StandardSignal) encodeValue(value any) (uint64, error) {
+	if flag, ok := value.(bool); ok {
+		if ss.typ.kind != SignalTypeKindFlag {
+			return 0, ErrInvalidType
+		}
+
+		if flag {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	physValue, ok := valueToFloat64(value)
+	if !ok {
+		return 0, ErrInvalidType
+	}
+
+	return ss.getRawValue(physValue, true)
 }
 
 // ToSignal returns the signal itself.
@@ -0,0 +1,190 @@
+package sym
+
+import "fmt"
+
+// Location is the position in the SYM file of an AST entity.
+type Location struct {
+	Filename string
+	Line     int
+}
+
+func (l *Location) String() string {
+	return fmt.Sprintf("%s:%d", l.Filename, l.Line)
+}
+
+type withLocation struct {
+	loc *Location
+}
+
+// Location returns the position of the entity in the file.
+func (wl *withLocation) Location() *Location {
+	return wl.loc
+}
+
+// File is the AST of a SYM file.
+type File struct {
+	withLocation
+
+	FormatVersion string
+	Title         string
+
+	Enums    []*Enum
+	Messages []*Message
+}
+
+// Enum is an enumeration defined in the {ENUMS} section, like:
+//
+//	enum Gear(0="Park", 1="Reverse", 2="Neutral")
+type Enum struct {
+	withLocation
+
+	Name   string
+	Values []*EnumValue
+}
+
+// EnumValue is a value of an [Enum].
+type EnumValue struct {
+	Index int
+	Name  string
+}
+
+// Section is the section of the file that holds a [Message].
+type Section int
+
+const (
+	// SectionSendReceive defines the {SENDRECEIVE} section.
+	SectionSendReceive Section = iota
+	// SectionSend defines the {SEND} section.
+	SectionSend
+	// SectionReceive defines the {RECEIVE} section.
+	SectionReceive
+)
+
+func (s Section) String() string {
+	switch s {
+	case SectionSendReceive:
+		return "SENDRECEIVE"
+	case SectionSend:
+		return "SEND"
+	case SectionReceive:
+		return "RECEIVE"
+	default:
+		return "unknown"
+	}
+}
+
+// MessageType is the frame format of a [Message].
+type MessageType string
+
+const (
+	// MessageTypeStandard defines a CAN 2.0 frame with an 11 bit identifier.
+	MessageTypeStandard MessageType = "Standard"
+	// MessageTypeExtended defines a CAN 2.0 frame with a 29 bit identifier.
+	MessageTypeExtended MessageType = "Extended"
+	// MessageTypeFDStandard defines a CAN FD frame with an 11 bit identifier.
+	MessageTypeFDStandard MessageType = "FDStandard"
+	// MessageTypeFDExtended defines a CAN FD frame with a 29 bit identifier.
+	MessageTypeFDExtended MessageType = "FDExtended"
+)
+
+// IsExtended states whether the type has a 29 bit identifier.
+func (mt MessageType) IsExtended() bool {
+	return mt == MessageTypeExtended || mt == MessageTypeFDExtended
+}
+
+// IsFD states whether the type is a CAN FD frame.
+func (mt MessageType) IsFD() bool {
+	return mt == MessageTypeFDStandard || mt == MessageTypeFDExtended
+}
+
+// Message is a message of the {SEND}, {RECEIVE} or {SENDRECEIVE} sections, like:
+//
+//	[Engine]
+//	ID=100h
+//	DLC=8
+//	CycleTime=10
+//	Var=RPM unsigned 0,16 /u:rpm /f:0.25
+//
+// The sections of a multiplexed message without the identifier, the type
+// or the cycle time take them from the previous section with the same name.
+type Message struct {
+	withLocation
+
+	Section Section
+	Name    string
+	// Desc is taken from the comment lines just above the message.
+	Desc string
+
+	ID        uint32
+	Type      MessageType
+	Length    int
+	CycleTime int
+
+	Mux     *Mux
+	Signals []*Signal
+}
+
+// Mux is the multiplexor of a [Message], like:
+//
+//	Mux=Page 0,8 3h
+//
+// The message holds the signals sent when the multiplexor has the given value.
+type Mux struct {
+	withLocation
+
+	Name      string
+	StartBit  int
+	Size      int
+	Value     uint64
+	BigEndian bool
+}
+
+// ValueType is the type of the value of a [Signal].
+type ValueType string
+
+const (
+	// ValueTypeUnsigned defines an unsigned integer.
+	ValueTypeUnsigned ValueType = "unsigned"
+	// ValueTypeSigned defines a signed integer.
+	ValueTypeSigned ValueType = "signed"
+	// ValueTypeBit defines a single bit flag.
+	ValueTypeBit ValueType = "bit"
+	// ValueTypeFloat defines a 32 bit IEEE float.
+	ValueTypeFloat ValueType = "float"
+	// ValueTypeDouble defines a 64 bit IEEE float.
+	ValueTypeDouble ValueType = "double"
+	// ValueTypeChar defines an ASCII character.
+	ValueTypeChar ValueType = "char"
+	// ValueTypeString defines an ASCII string.
+	ValueTypeString ValueType = "string"
+	// ValueTypeRaw defines raw bytes.
+	ValueTypeRaw ValueType = "raw"
+)
+
+// Signal is a variable of a [Message], like:
+//
+//	Var=RPM unsigned 0,16 -m /u:rpm /f:0.25 /max:8000 /e:Gear // engine speed
+//
+// For the big endian (Motorola) signals, the start bit
+// is the position of the most significant bit, where the bits of
+// every byte are numbered from the most significant one.
+type Signal struct {
+	withLocation
+
+	Name      string
+	Type      ValueType
+	StartBit  int
+	Size      int
+	BigEndian bool
+
+	Unit   string
+	Factor float64
+	Offset float64
+	Min    *float64
+	Max    *float64
+	// Default is the raw value of the signal when it is not received.
+	Default  *float64
+	Enum     string
+	LongName string
+	Desc     string
+}
@@ -0,0 +1,16 @@
+// Package sym provides a parser and a writer for the PCAN symbol files (.sym),
+// the format used by PCAN-View and PCAN-Explorer of PEAK-System.
+// It is based on the version 6.0 of the format.
+//
+// The sections {ENUMS}, {SIGNALS}, {SEND}, {RECEIVE} and {SENDRECEIVE} are supported.
+// The signals referenced by the messages with the "Sig=" keyword are resolved by the parser,
+// so every [Message] of the [File] holds the complete definition of its signals.
+// A multiplexed message is described by more [Message] with the same name,
+// one for each value of the multiplexor.
+package sym
+
+// FileExtension is the extension of a SYM file.
+const FileExtension = ".sym"
+
+// FormatVersion is the version of the format written by the [Write] function.
+const FormatVersion = "6.0"
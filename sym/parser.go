@@ -0,0 +1,725 @@
+package sym
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parse parses the given [io.Reader] and returns the generated SYM [File]
+// AST from the reader.
+//
+// The unknown properties of the messages, like "Timeout", are skipped.
+func Parse(filename string, r io.Reader) (*File, error) {
+	parser := newParser(filename, r)
+	return parser.parse()
+}
+
+const (
+	sectionNone        = ""
+	sectionEnums       = "ENUMS"
+	sectionSignals     = "SIGNALS"
+	sectionSend        = "SEND"
+	sectionReceive     = "RECEIVE"
+	sectionSendReceive = "SENDRECEIVE"
+)
+
+type parser struct {
+	s *bufio.Scanner
+
+	filename string
+	line     int
+	currLine string
+
+	ast *File
+
+	section  string
+	comments []string
+	enumBuf  string
+	enumLine int
+
+	currMsg      *Message
+	currMsgHasID bool
+	prevMsgs     map[string]*Message
+
+	sigDefs map[string]*Signal
+}
+
+func newParser(filename string, r io.Reader) *parser {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	return &parser{
+		s: s,
+
+		filename: filename,
+
+		ast: new(File),
+
+		section: sectionNone,
+
+		prevMsgs: make(map[string]*Message),
+		sigDefs:  make(map[string]*Signal),
+	}
+}
+
+func (p *parser) getLocation() *Location {
+	return &Location{
+		Filename: p.filename,
+		Line:     p.line,
+	}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	return fmt.Errorf(`syntax error at %s:%d; %s: %q`, p.filename, p.line, msg, strings.TrimSpace(p.currLine))
+}
+
+func (p *parser) errorAt(loc *Location, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	return fmt.Errorf(`syntax error at %s; %s`, loc, msg)
+}
+
+func (p *parser) parse() (*File, error) {
+	p.ast.withLocation.loc = &Location{Filename: p.filename, Line: 1}
+
+	for p.s.Scan() {
+		p.line++
+		p.currLine = p.s.Text()
+
+		if err := p.parseLine(strings.TrimSpace(p.currLine)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.s.Err(); err != nil {
+		return nil, err
+	}
+
+	if p.enumBuf != "" {
+		return nil, p.errorf("unterminated enum")
+	}
+
+	if err := p.finishMessage(); err != nil {
+		return nil, err
+	}
+
+	return p.ast, nil
+}
+
+func (p *parser) parseLine(line string) error {
+	// an enum can span more lines
+	if p.enumBuf != "" {
+		return p.parseEnum(line)
+	}
+
+	switch {
+	case line == "":
+		p.comments = nil
+		return nil
+
+	case strings.HasPrefix(line, "//"):
+		p.comments = append(p.comments, strings.TrimSpace(line[2:]))
+		return nil
+
+	case strings.HasPrefix(line, "{"):
+		p.comments = nil
+		return p.parseSection(line)
+
+	case strings.HasPrefix(line, "["):
+		err := p.parseMessageHeader(line)
+		p.comments = nil
+		return err
+	}
+
+	p.comments = nil
+
+	if p.section == sectionEnums {
+		return p.parseEnum(line)
+	}
+
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return p.errorf("expected a property")
+	}
+
+	return p.parseProperty(strings.TrimSpace(key), strings.TrimSpace(value))
+}
+
+func (p *parser) parseSection(line string) error {
+	if !strings.HasSuffix(line, "}") {
+		return p.errorf(`expected "}"`)
+	}
+
+	if err := p.finishMessage(); err != nil {
+		return err
+	}
+
+	section := strings.ToUpper(strings.TrimSpace(line[1 : len(line)-1]))
+	switch section {
+	case sectionEnums, sectionSignals, sectionSend, sectionReceive, sectionSendReceive:
+		p.section = section
+		return nil
+	}
+
+	return p.errorf("unknown section")
+}
+
+func (p *parser) isMessageSection() bool {
+	return p.section == sectionSend || p.section == sectionReceive || p.section == sectionSendReceive
+}
+
+func (p *parser) parseMessageHeader(line string) error {
+	if !p.isMessageSection() {
+		return p.errorf("message outside of a message section")
+	}
+
+	if !strings.HasSuffix(line, "]") {
+		return p.errorf(`expected "]"`)
+	}
+
+	name := strings.TrimSpace(line[1 : len(line)-1])
+	if name == "" {
+		return p.errorf("expected message name")
+	}
+
+	if err := p.finishMessage(); err != nil {
+		return err
+	}
+
+	msg := &Message{
+		Name: name,
+		Desc: strings.Join(p.comments, "\n"),
+		Type: MessageTypeStandard,
+	}
+	msg.withLocation.loc = p.getLocation()
+
+	switch p.section {
+	case sectionSend:
+		msg.Section = SectionSend
+	case sectionReceive:
+		msg.Section = SectionReceive
+	default:
+		msg.Section = SectionSendReceive
+	}
+
+	// the sections of a multiplexed message share the same properties
+	p.currMsgHasID = false
+	if prevMsg, ok := p.prevMsgs[name]; ok {
+		msg.ID = prevMsg.ID
+		msg.Type = prevMsg.Type
+		msg.Length = prevMsg.Length
+		msg.CycleTime = prevMsg.CycleTime
+		p.currMsgHasID = true
+	}
+
+	p.currMsg = msg
+
+	return nil
+}
+
+func (p *parser) finishMessage() error {
+	msg := p.currMsg
+	if msg == nil {
+		return nil
+	}
+	p.currMsg = nil
+
+	if !p.currMsgHasID {
+		return p.errorAt(msg.loc, "missing ID of message %q", msg.Name)
+	}
+
+	p.prevMsgs[msg.Name] = msg
+	p.ast.Messages = append(p.ast.Messages, msg)
+
+	return nil
+}
+
+func (p *parser) parseProperty(key, value string) error {
+	switch p.section {
+	case sectionNone:
+		return p.parseHeaderProperty(key, value)
+
+	case sectionSignals:
+		if key == "Sig" {
+			return p.parseSignalDefinition(value)
+		}
+		return nil
+	}
+
+	msg := p.currMsg
+	if msg == nil {
+		return p.errorf("property outside of a message")
+	}
+
+	value, comment := splitComment(value)
+
+	switch key {
+	case "ID":
+		if strings.Contains(value, "-") {
+			return p.errorf("ID ranges are not supported")
+		}
+
+		id, err := parseNumber(value)
+		if err != nil {
+			return p.errorf("invalid ID")
+		}
+		msg.ID = uint32(id)
+		p.currMsgHasID = true
+
+	case "Type":
+		msgType := MessageType(value)
+		switch msgType {
+		case MessageTypeStandard, MessageTypeExtended, MessageTypeFDStandard, MessageTypeFDExtended:
+			msg.Type = msgType
+		default:
+			return p.errorf("invalid message type")
+		}
+
+	case "DLC", "Len":
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 0 {
+			return p.errorf("invalid length")
+		}
+		msg.Length = length
+
+	case "CycleTime":
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return p.errorf("expected cycle time")
+		}
+
+		cycleTime, err := strconv.Atoi(fields[0])
+		if err != nil || cycleTime < 0 {
+			return p.errorf("invalid cycle time")
+		}
+		msg.CycleTime = cycleTime
+
+	case "Mux":
+		if msg.Mux != nil {
+			return p.errorf("duplicated multiplexor")
+		}
+
+		mux, err := p.parseMux(value)
+		if err != nil {
+			return err
+		}
+		msg.Mux = mux
+
+	case "Var":
+		sig, err := p.parseVar(value, comment)
+		if err != nil {
+			return err
+		}
+		msg.Signals = append(msg.Signals, sig)
+
+	case "Sig":
+		sig, err := p.parseSignalRef(value, comment)
+		if err != nil {
+			return err
+		}
+		msg.Signals = append(msg.Signals, sig)
+	}
+
+	return nil
+}
+
+func (p *parser) parseHeaderProperty(key, value string) error {
+	value, _ = splitComment(value)
+
+	switch key {
+	case "FormatVersion":
+		p.ast.FormatVersion = value
+	case "Title":
+		p.ast.Title = unquote(value)
+	}
+
+	return nil
+}
+
+func (p *parser) parseEnum(line string) error {
+	if p.enumBuf == "" {
+		p.enumLine = p.line
+	} else {
+		p.enumBuf += " "
+	}
+	p.enumBuf += line
+
+	text, _ := splitComment(p.enumBuf)
+	if !strings.HasSuffix(text, ")") {
+		// the enum continues on the next line
+		if !strings.HasPrefix(text, "enum") {
+			p.enumBuf = ""
+			return p.errorf(`expected "enum"`)
+		}
+		return nil
+	}
+	p.enumBuf = ""
+
+	name, body, ok := strings.Cut(strings.TrimPrefix(text, "enum"), "(")
+	if !ok || !strings.HasPrefix(text, "enum") {
+		return p.errorf("invalid enum")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return p.errorf("expected enum name")
+	}
+
+	enum := &Enum{Name: name}
+	enum.withLocation.loc = &Location{Filename: p.filename, Line: p.enumLine}
+
+	for _, item := range splitOutsideQuotes(strings.TrimSuffix(body, ")"), ',') {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		idxStr, valName, ok := strings.Cut(item, "=")
+		if !ok {
+			return p.errorf("invalid enum value")
+		}
+
+		idx, err := parseNumber(strings.TrimSpace(idxStr))
+		if err != nil {
+			return p.errorf("invalid enum index")
+		}
+
+		enum.Values = append(enum.Values, &EnumValue{
+			Index: int(idx),
+			Name:  unquote(strings.TrimSpace(valName)),
+		})
+	}
+
+	p.ast.Enums = append(p.ast.Enums, enum)
+
+	return nil
+}
+
+func (p *parser) parseMux(value string) (*Mux, error) {
+	tokens := tokenize(value)
+	if len(tokens) < 3 {
+		return nil, p.errorf("expected multiplexor name, position and value")
+	}
+
+	startBit, size, err := p.parsePosition(tokens[1])
+	if err != nil {
+		return nil, err
+	}
+
+	muxValue, err := parseNumber(tokens[2])
+	if err != nil {
+		return nil, p.errorf("invalid multiplexor value")
+	}
+
+	mux := &Mux{
+		Name:     tokens[0],
+		StartBit: startBit,
+		Size:     size,
+		Value:    muxValue,
+	}
+	mux.withLocation.loc = p.getLocation()
+
+	for _, tok := range tokens[3:] {
+		if tok == "-m" {
+			mux.BigEndian = true
+		}
+	}
+
+	return mux, nil
+}
+
+// parsePosition parses the "start,size" position of a signal.
+func (p *parser) parsePosition(value string) (int, int, error) {
+	startStr, sizeStr, ok := strings.Cut(value, ",")
+	if !ok {
+		return 0, 0, p.errorf("expected start bit and size")
+	}
+
+	startBit, err := strconv.Atoi(startStr)
+	if err != nil || startBit < 0 {
+		return 0, 0, p.errorf("invalid start bit")
+	}
+
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil || size <= 0 {
+		return 0, 0, p.errorf("invalid size")
+	}
+
+	return startBit, size, nil
+}
+
+func (p *parser) newSignal(name, typ, desc string) (*Signal, error) {
+	valType := ValueType(typ)
+	switch valType {
+	case ValueTypeUnsigned, ValueTypeSigned, ValueTypeBit, ValueTypeFloat,
+		ValueTypeDouble, ValueTypeChar, ValueTypeString, ValueTypeRaw:
+	default:
+		return nil, p.errorf("invalid signal type")
+	}
+
+	sig := &Signal{
+		Name:   name,
+		Type:   valType,
+		Factor: 1,
+		Desc:   desc,
+	}
+	sig.withLocation.loc = p.getLocation()
+
+	return sig, nil
+}
+
+// parseVar parses a signal defined inside a message, like "Name type start,size [options]".
+func (p *parser) parseVar(value, comment string) (*Signal, error) {
+	tokens := tokenize(value)
+	if len(tokens) < 3 {
+		return nil, p.errorf("expected signal name, type and position")
+	}
+
+	sig, err := p.newSignal(tokens[0], tokens[1], comment)
+	if err != nil {
+		return nil, err
+	}
+
+	startBit, size, err := p.parsePosition(tokens[2])
+	if err != nil {
+		return nil, err
+	}
+	sig.StartBit = startBit
+	sig.Size = size
+
+	if err := p.parseSignalOptions(sig, tokens[3:]); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// parseSignalDefinition parses a signal of the {SIGNALS} section, like "Name type size [options]".
+func (p *parser) parseSignalDefinition(value string) error {
+	value, comment := splitComment(value)
+
+	tokens := tokenize(value)
+	if len(tokens) < 3 {
+		return p.errorf("expected signal name, type and size")
+	}
+
+	name := tokens[0]
+	if _, ok := p.sigDefs[name]; ok {
+		return p.errorf("duplicated signal")
+	}
+
+	sig, err := p.newSignal(name, tokens[1], comment)
+	if err != nil {
+		return err
+	}
+
+	size, err := strconv.Atoi(tokens[2])
+	if err != nil || size <= 0 {
+		return p.errorf("invalid size")
+	}
+	sig.Size = size
+
+	if err := p.parseSignalOptions(sig, tokens[3:]); err != nil {
+		return err
+	}
+
+	p.sigDefs[name] = sig
+
+	return nil
+}
+
+// parseSignalRef parses a reference to a signal of the {SIGNALS} section, like "Name start".
+func (p *parser) parseSignalRef(value, comment string) (*Signal, error) {
+	tokens := tokenize(value)
+	if len(tokens) < 2 {
+		return nil, p.errorf("expected signal name and start bit")
+	}
+
+	def, ok := p.sigDefs[tokens[0]]
+	if !ok {
+		return nil, p.errorf("undefined signal")
+	}
+
+	startBit, err := strconv.Atoi(tokens[1])
+	if err != nil || startBit < 0 {
+		return nil, p.errorf("invalid start bit")
+	}
+
+	sig := *def
+	sig.StartBit = startBit
+	sig.withLocation.loc = p.getLocation()
+
+	if comment != "" {
+		sig.Desc = comment
+	}
+
+	if err := p.parseSignalOptions(&sig, tokens[2:]); err != nil {
+		return nil, err
+	}
+
+	return &sig, nil
+}
+
+func (p *parser) parseSignalOptions(sig *Signal, tokens []string) error {
+	for _, tok := range tokens {
+		if strings.HasPrefix(tok, "-") {
+			// only the byte order is relevant, the other flags are about the display
+			if tok == "-m" {
+				sig.BigEndian = true
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(tok, "/") {
+			return p.errorf("unexpected token %q", tok)
+		}
+
+		key, value, ok := strings.Cut(tok[1:], ":")
+		if !ok {
+			return p.errorf("invalid option %q", tok)
+		}
+		value = unquote(value)
+
+		parseFloat := func() (*float64, error) {
+			val, err := parseFloatNumber(value)
+			if err != nil {
+				return nil, p.errorf("invalid value of option %q", key)
+			}
+			return &val, nil
+		}
+
+		switch key {
+		case "u":
+			sig.Unit = value
+
+		case "f":
+			val, err := parseFloat()
+			if err != nil {
+				return err
+			}
+			sig.Factor = *val
+
+		case "o":
+			val, err := parseFloat()
+			if err != nil {
+				return err
+			}
+			sig.Offset = *val
+
+		case "min":
+			val, err := parseFloat()
+			if err != nil {
+				return err
+			}
+			sig.Min = val
+
+		case "max":
+			val, err := parseFloat()
+			if err != nil {
+				return err
+			}
+			sig.Max = val
+
+		case "d":
+			val, err := parseFloat()
+			if err != nil {
+				return err
+			}
+			sig.Default = val
+
+		case "e":
+			sig.Enum = value
+
+		case "ln":
+			sig.LongName = value
+		}
+	}
+
+	return nil
+}
+
+// parseNumber parses a decimal number or an hex number with the "h" suffix.
+func parseNumber(value string) (uint64, error) {
+	if hexStr, ok := strings.CutSuffix(strings.ToLower(value), "h"); ok {
+		return strconv.ParseUint(hexStr, 16, 64)
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func parseFloatNumber(value string) (float64, error) {
+	if strings.HasSuffix(strings.ToLower(value), "h") {
+		val, err := parseNumber(value)
+		return float64(val), err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// splitComment splits the given value from its trailing comment.
+func splitComment(value string) (string, string) {
+	inQuotes := false
+	for idx := 0; idx < len(value)-1; idx++ {
+		switch {
+		case value[idx] == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && value[idx] == '/' && value[idx+1] == '/':
+			return strings.TrimSpace(value[:idx]), strings.TrimSpace(value[idx+2:])
+		}
+	}
+	return strings.TrimSpace(value), ""
+}
+
+func splitOutsideQuotes(value string, sep byte) []string {
+	parts := []string{}
+	inQuotes := false
+	start := 0
+
+	for idx := 0; idx < len(value); idx++ {
+		switch {
+		case value[idx] == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && value[idx] == sep:
+			parts = append(parts, value[start:idx])
+			start = idx + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
+
+// tokenize splits the given value by spaces, except the ones between quotes.
+func tokenize(value string) []string {
+	tokens := []string{}
+	inQuotes := false
+	var tok strings.Builder
+
+	for _, r := range value {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			tok.WriteRune(r)
+		case !inQuotes && (r == ' ' || r == '\t'):
+			if tok.Len() > 0 {
+				tokens = append(tokens, tok.String())
+				tok.Reset()
+			}
+		default:
+			tok.WriteRune(r)
+		}
+	}
+
+	if tok.Len() > 0 {
+		tokens = append(tokens, tok.String())
+	}
+
+	return tokens
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
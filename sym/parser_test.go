@@ -0,0 +1,130 @@
+package sym
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.sym")
+	assert.NoError(err)
+	defer file.Close()
+
+	ast, err := Parse("sample.sym", file)
+	assert.NoError(err)
+
+	assert.Equal("6.0", ast.FormatVersion)
+	assert.Equal("Powertrain bus", ast.Title)
+
+	// should parse the enums that span more lines
+	assert.Len(ast.Enums, 2)
+	gear := ast.Enums[0]
+	assert.Equal("Gear", gear.Name)
+	assert.Equal(5, gear.Location().Line)
+	assert.Len(gear.Values, 4)
+	assert.Equal(3, gear.Values[3].Index)
+	assert.Equal("Drive", gear.Values[3].Name)
+
+	assert.Len(ast.Messages, 4)
+
+	// should inherit the properties of the previous section of a multiplexed message
+	diag0 := ast.Messages[0]
+	diag1 := ast.Messages[1]
+	assert.Equal(SectionSend, diag0.Section)
+	assert.Equal("Diagnostic", diag1.Name)
+	assert.Equal(uint32(0x1ABCDEF), diag1.ID)
+	assert.Equal(MessageTypeExtended, diag1.Type)
+	assert.Equal(100, diag1.CycleTime)
+	assert.Equal(uint64(0), diag0.Mux.Value)
+	assert.Equal(uint64(1), diag1.Mux.Value)
+	assert.Equal(2, diag1.Mux.Size)
+	assert.Equal(ValueTypeFloat, diag1.Signals[1].Type)
+
+	// should resolve the signals of the {SIGNALS} section
+	logger := ast.Messages[2]
+	assert.Equal(SectionReceive, logger.Section)
+	status := logger.Signals[0]
+	assert.Equal("Status", status.Name)
+	assert.Equal(0, status.StartBit)
+	assert.Equal(4, status.Size)
+	assert.Equal("OnOff", status.Enum)
+	assert.Equal("Status of the logger", status.Desc)
+	assert.Equal(31, logger.Location().Line)
+
+	engine := ast.Messages[3]
+	assert.Equal(SectionSendReceive, engine.Section)
+	assert.Equal("Engine status", engine.Desc)
+	assert.Equal(uint32(0x100), engine.ID)
+	assert.Equal(8, engine.Length)
+	assert.Len(engine.Signals, 5)
+
+	rpm := engine.Signals[0]
+	assert.Equal("rpm", rpm.Unit)
+	assert.Equal(0.25, rpm.Factor)
+	assert.Equal(8000.0, *rpm.Max)
+	assert.Equal("Engine speed", rpm.Desc)
+
+	temperature := engine.Signals[1]
+	assert.Equal(ValueTypeSigned, temperature.Type)
+	assert.Equal("deg C", temperature.Unit)
+	assert.Equal(-40.0, temperature.Offset)
+	assert.Equal(40.0, *temperature.Default)
+	assert.Nil(temperature.Min)
+
+	pressure := engine.Signals[4]
+	assert.True(pressure.BigEndian)
+	assert.Equal(32, pressure.StartBit)
+}
+
+func Test_Parse_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "unknown section",
+			content: "{UNKNOWN}",
+			errMsg:  "syntax error at test.sym:1; unknown section",
+		},
+		{
+			name:    "message outside of a section",
+			content: "[Engine]",
+			errMsg:  "message outside of a message section",
+		},
+		{
+			name:    "missing ID",
+			content: "{SEND}\n[Engine]\nDLC=8",
+			errMsg:  `syntax error at test.sym:2; missing ID of message "Engine"`,
+		},
+		{
+			name:    "invalid signal type",
+			content: "{SEND}\n[Engine]\nID=100h\nVar=RPM number 0,16",
+			errMsg:  "syntax error at test.sym:4; invalid signal type",
+		},
+		{
+			name:    "undefined signal",
+			content: "{SEND}\n[Engine]\nID=100h\nSig=RPM 0",
+			errMsg:  "undefined signal",
+		},
+		{
+			name:    "unterminated enum",
+			content: "{ENUMS}\nenum Gear(0=\"Park\",",
+			errMsg:  "unterminated enum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("test.sym", strings.NewReader(tt.content))
+			assert.ErrorContains(err, tt.errMsg)
+		})
+	}
+}
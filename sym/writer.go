@@ -0,0 +1,193 @@
+package sym
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Write writes the given SYM file into the [io.Writer].
+// The messages are written into the section they belong to,
+// in the order {SEND}, {RECEIVE} and {SENDRECEIVE}.
+// All the signals are written inside the messages with the "Var=" keyword.
+func Write(w io.Writer, ast *File) error {
+	symWriter := newWriter(w)
+	symWriter.writeFile(ast)
+	return symWriter.err
+}
+
+type writer struct {
+	f   io.Writer
+	err error
+}
+
+func newWriter(w io.Writer) *writer {
+	return &writer{
+		f: w,
+	}
+}
+
+func (w *writer) println(format string, a ...any) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.f, format+"\n", a...)
+}
+
+func (w *writer) newLine() {
+	w.println("")
+}
+
+func (w *writer) formatDouble(val float64) string {
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+func (w *writer) quote(val string) string {
+	return `"` + strings.ReplaceAll(val, `"`, `'`) + `"`
+}
+
+func (w *writer) writeFile(ast *File) {
+	version := ast.FormatVersion
+	if version == "" {
+		version = FormatVersion
+	}
+	w.println("FormatVersion=%s // Do not edit this line!", version)
+
+	if ast.Title != "" {
+		w.println("Title=%s", w.quote(ast.Title))
+	}
+
+	if len(ast.Enums) > 0 {
+		w.newLine()
+		w.println("{ENUMS}")
+		for _, enum := range ast.Enums {
+			w.writeEnum(enum)
+		}
+	}
+
+	for _, section := range []Section{SectionSend, SectionReceive, SectionSendReceive} {
+		msgs := []*Message{}
+		for _, msg := range ast.Messages {
+			if msg.Section == section {
+				msgs = append(msgs, msg)
+			}
+		}
+
+		if len(msgs) == 0 {
+			continue
+		}
+
+		w.newLine()
+		w.println("{%s}", section)
+
+		for _, msg := range msgs {
+			w.writeMessage(msg)
+		}
+	}
+}
+
+func (w *writer) writeEnum(enum *Enum) {
+	values := make([]string, 0, len(enum.Values))
+	for _, val := range enum.Values {
+		values = append(values, fmt.Sprintf("%d=%s", val.Index, w.quote(val.Name)))
+	}
+	w.println("enum %s(%s)", enum.Name, strings.Join(values, ", "))
+}
+
+func (w *writer) writeMessage(msg *Message) {
+	w.newLine()
+
+	if msg.Desc != "" {
+		for line := range strings.SplitSeq(msg.Desc, "\n") {
+			w.println("// %s", line)
+		}
+	}
+
+	w.println("[%s]", msg.Name)
+
+	if msg.Type.IsExtended() {
+		w.println("ID=%08Xh", msg.ID)
+	} else {
+		w.println("ID=%03Xh", msg.ID)
+	}
+
+	if msg.Type != "" && msg.Type != MessageTypeStandard {
+		w.println("Type=%s", msg.Type)
+	}
+
+	if msg.Length > 8 {
+		w.println("Len=%d", msg.Length)
+	} else {
+		w.println("DLC=%d", msg.Length)
+	}
+
+	if msg.CycleTime > 0 {
+		w.println("CycleTime=%d", msg.CycleTime)
+	}
+
+	if mux := msg.Mux; mux != nil {
+		line := fmt.Sprintf("Mux=%s %d,%d %Xh", mux.Name, mux.StartBit, mux.Size, mux.Value)
+		if mux.BigEndian {
+			line += " -m"
+		}
+		w.println("%s", line)
+	}
+
+	for _, sig := range msg.Signals {
+		w.writeSignal(sig)
+	}
+}
+
+func (w *writer) writeSignal(sig *Signal) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Var=%s %s %d,%d", sig.Name, sig.Type, sig.StartBit, sig.Size)
+
+	if sig.BigEndian {
+		b.WriteString(" -m")
+	}
+
+	if sig.Unit != "" {
+		unit := sig.Unit
+		if strings.ContainsAny(unit, " \t") {
+			unit = w.quote(unit)
+		}
+		fmt.Fprintf(&b, " /u:%s", unit)
+	}
+
+	if sig.Factor != 1 {
+		fmt.Fprintf(&b, " /f:%s", w.formatDouble(sig.Factor))
+	}
+
+	if sig.Offset != 0 {
+		fmt.Fprintf(&b, " /o:%s", w.formatDouble(sig.Offset))
+	}
+
+	if sig.Min != nil {
+		fmt.Fprintf(&b, " /min:%s", w.formatDouble(*sig.Min))
+	}
+
+	if sig.Max != nil {
+		fmt.Fprintf(&b, " /max:%s", w.formatDouble(*sig.Max))
+	}
+
+	if sig.Default != nil {
+		fmt.Fprintf(&b, " /d:%s", w.formatDouble(*sig.Default))
+	}
+
+	if sig.Enum != "" {
+		fmt.Fprintf(&b, " /e:%s", sig.Enum)
+	}
+
+	if sig.LongName != "" {
+		fmt.Fprintf(&b, " /ln:%s", w.quote(sig.LongName))
+	}
+
+	if sig.Desc != "" {
+		// the description is a single line comment
+		fmt.Fprintf(&b, " // %s", strings.Join(strings.Fields(sig.Desc), " "))
+	}
+
+	w.println("%s", b.String())
+}
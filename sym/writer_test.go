@@ -0,0 +1,65 @@
+package sym
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Write(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.sym")
+	assert.NoError(err)
+	defer file.Close()
+
+	ast, err := Parse("sample.sym", file)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(Write(buf, ast))
+
+	content := buf.String()
+	assert.Contains(content, "FormatVersion=6.0 // Do not edit this line!\n")
+	assert.Contains(content, `enum Gear(0="Park", 1="Reverse", 2="Neutral", 3="Drive")`)
+	assert.Contains(content, "ID=01ABCDEFh\nType=Extended\nDLC=8\nCycleTime=100\nMux=Page 0,2 1h\n")
+	assert.Contains(content, `Var=Temperature signed 16,8 /u:"deg C" /o:-40 /d:40`)
+	assert.Contains(content, "Var=Status unsigned 0,4 /e:OnOff // Status of the logger")
+	assert.Contains(content, "// Engine status\n[Engine]\nID=100h\n")
+
+	// should parse the same file
+	expAST, err := Parse("sample.sym", bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(ast.Title, expAST.Title)
+	assert.Len(expAST.Enums, len(ast.Enums))
+	for idx, enum := range ast.Enums {
+		assert.Equal(enum.Name, expAST.Enums[idx].Name)
+		assert.Equal(enum.Values, expAST.Enums[idx].Values)
+	}
+	assert.Len(expAST.Messages, len(ast.Messages))
+
+	for idx, msg := range ast.Messages {
+		expMsg := expAST.Messages[idx]
+		assert.Equal(msg.Name, expMsg.Name)
+		assert.Equal(msg.Section, expMsg.Section)
+		assert.Equal(msg.ID, expMsg.ID)
+		assert.Equal(msg.Type, expMsg.Type)
+		assert.Equal(msg.Length, expMsg.Length)
+		assert.Equal(msg.CycleTime, expMsg.CycleTime)
+		assert.Equal(msg.Desc, expMsg.Desc)
+		assert.Len(expMsg.Signals, len(msg.Signals))
+
+		if msg.Mux != nil {
+			assert.Equal(msg.Mux.Value, expMsg.Mux.Value)
+		}
+
+		for sigIdx, sig := range msg.Signals {
+			expSig := expMsg.Signals[sigIdx]
+			sig.loc = nil
+			expSig.loc = nil
+			assert.Equal(sig, expSig)
+		}
+	}
+}
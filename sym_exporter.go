@@ -0,0 +1,284 @@
+package acmelib
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/squadracorsepolito/acmelib/sym"
+)
+
+// ExportSYMNetwork exports the given [Network] to PCAN symbol files.
+// It will create a directory with the given base path and network name.
+// Into the directory, it will create a SYM file for each [Bus] of the network.
+func ExportSYMNetwork(network *Network, basePath string) error {
+	netName := clearSpaces(network.name)
+	dirPath := netName
+	if len(basePath) > 0 {
+		dirPath = filepath.Join(basePath, netName)
+	}
+
+	if err := os.MkdirAll(dirPath, 0666); err != nil {
+		return err
+	}
+
+	for _, bus := range network.Buses() {
+		f, err := os.Create(filepath.Join(dirPath, clearSpaces(bus.name)+sym.FileExtension))
+		if err != nil {
+			return err
+		}
+
+		err = ExportSYMBus(f, bus)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportSYMBus exports the given [Bus] to a PCAN symbol file.
+// It writes the content of the result SYM file into the [io.Writer].
+//
+// The symbol files do not describe the nodes, so all the messages
+// are written into the {SENDRECEIVE} section ordered by CAN-ID.
+// Each layout of the multiplexed layer of a message is written as
+// a section of the message, that repeats the signals outside the layer.
+//
+// It returns an [ErrInvalidType] wrapped by a [NameError] if a message
+// has more than one multiplexed layer or a nested one, because the format
+// supports only one multiplexor per message.
+func ExportSYMBus(w io.Writer, bus *Bus) error {
+	exp := newSYMExporter()
+
+	symFile, err := exp.exportBus(bus)
+	if err != nil {
+		return err
+	}
+
+	return sym.Write(w, symFile)
+}
+
+type symExporter struct {
+	symFile *sym.File
+
+	enumNames map[string]bool
+	sigEnums  map[EntityID]string
+}
+
+func newSYMExporter() *symExporter {
+	return &symExporter{
+		symFile: new(sym.File),
+
+		enumNames: make(map[string]bool),
+		sigEnums:  make(map[EntityID]string),
+	}
+}
+
+func (e *symExporter) exportBus(bus *Bus) (*sym.File, error) {
+	e.symFile.FormatVersion = sym.FormatVersion
+
+	e.symFile.Title = bus.name
+	if bus.desc != "" {
+		e.symFile.Title = bus.desc
+	}
+
+	msgs := []*Message{}
+	for _, nodeInt := range bus.NodeInterfaces() {
+		msgs = append(msgs, nodeInt.SentMessages()...)
+	}
+	slices.SortStableFunc(msgs, func(a, b *Message) int {
+		return cmp.Compare(a.GetCANID(), b.GetCANID())
+	})
+
+	for _, msg := range msgs {
+		if err := e.exportMessage(bus, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.symFile, nil
+}
+
+func (e *symExporter) exportMessage(bus *Bus, msg *Message) error {
+	symMsg := &sym.Message{
+		Section:   sym.SectionSendReceive,
+		Name:      clearSpaces(msg.name),
+		Desc:      msg.desc,
+		ID:        uint32(msg.GetCANID()),
+		Type:      e.getMessageType(bus, msg),
+		Length:    msg.sizeByte,
+		CycleTime: msg.cycleTime,
+	}
+
+	staticSignals := []*sym.Signal{}
+	var muxLayer *MultiplexedLayer
+	for sig := range msg.layout.ibst.InOrder() {
+		if sig.Kind() != SignalKindMuxor {
+			staticSignals = append(staticSignals, e.exportSignal(sig))
+			continue
+		}
+
+		layer, ok := msg.layout.muxLayers.Get(sig.EntityID())
+		if !ok {
+			continue
+		}
+
+		// only one multiplexor per message is supported
+		if muxLayer != nil {
+			return msg.errorf(newNameError(sig.Name(), ErrInvalidType))
+		}
+		muxLayer = layer
+	}
+
+	if muxLayer == nil {
+		symMsg.Signals = staticSignals
+		e.symFile.Messages = append(e.symFile.Messages, symMsg)
+		return nil
+	}
+
+	muxor := muxLayer.muxor
+	for layoutID, layout := range muxLayer.iterLayouts() {
+		if layout.SignalCount() == 0 && layoutID > 0 {
+			continue
+		}
+
+		section := *symMsg
+		section.Mux = &sym.Mux{
+			Name:      clearSpaces(muxor.name),
+			StartBit:  muxor.StartPos(),
+			Size:      muxor.Size(),
+			Value:     uint64(layoutID),
+			BigEndian: muxor.Endianness() == EndiannessBigEndian,
+		}
+
+		section.Signals = slices.Clone(staticSignals)
+		for sig := range layout.ibst.InOrder() {
+			if sig.Kind() == SignalKindMuxor {
+				return msg.errorf(newNameError(sig.Name(), ErrInvalidType))
+			}
+			section.Signals = append(section.Signals, e.exportSignal(sig))
+		}
+
+		e.symFile.Messages = append(e.symFile.Messages, &section)
+	}
+
+	return nil
+}
+
+func (e *symExporter) getMessageType(bus *Bus, msg *Message) sym.MessageType {
+	isExtended := msg.canIDFormat == CANIDFormatExtended
+
+	if bus.typ == BusTypeCANFD {
+		if isExtended {
+			return sym.MessageTypeFDExtended
+		}
+		return sym.MessageTypeFDStandard
+	}
+
+	if isExtended {
+		return sym.MessageTypeExtended
+	}
+	return sym.MessageTypeStandard
+}
+
+func (e *symExporter) exportSignal(sig Signal) *sym.Signal {
+	symSig := &sym.Signal{
+		Name:      clearSpaces(sig.Name()),
+		Type:      sym.ValueTypeUnsigned,
+		StartBit:  sig.StartPos(),
+		Size:      sig.Size(),
+		BigEndian: sig.Endianness() == EndiannessBigEndian,
+		Factor:    1,
+		Desc:      sig.Desc(),
+	}
+
+	if startValue := sig.StartValue(); startValue != 0 {
+		symSig.Default = &startValue
+	}
+
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+		e.exportStandardSignal(symSig, stdSig)
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+		symSig.Enum = e.exportEnum(enumSig.enum)
+	}
+
+	return symSig
+}
+
+func (e *symExporter) exportStandardSignal(symSig *sym.Signal, stdSig *StandardSignal) {
+	sigType := stdSig.typ
+
+	switch {
+	case sigType.kind == SignalTypeKindFlag:
+		symSig.Type = sym.ValueTypeBit
+		return
+
+	case sigType.kind == SignalTypeKindFloat && sigType.size == 64:
+		symSig.Type = sym.ValueTypeDouble
+
+	case sigType.kind == SignalTypeKindFloat:
+		symSig.Type = sym.ValueTypeFloat
+
+	case sigType.signed:
+		symSig.Type = sym.ValueTypeSigned
+	}
+
+	if stdSig.unit != nil {
+		symSig.Unit = stdSig.unit.symbol
+	}
+
+	symSig.Factor = sigType.scale
+	symSig.Offset = sigType.offset
+
+	min := sigType.min
+	max := sigType.max
+	symSig.Min = &min
+	symSig.Max = &max
+}
+
+// exportEnum adds the given enum to the {ENUMS} section if it is not already there,
+// and it returns the name used in the file.
+func (e *symExporter) exportEnum(sigEnum *SignalEnum) string {
+	if name, ok := e.sigEnums[sigEnum.entityID]; ok {
+		return name
+	}
+
+	baseName := clearSpaces(sigEnum.name)
+	name := baseName
+	for suffix := 1; e.enumNames[name]; suffix++ {
+		name = fmt.Sprintf("%s_%d", baseName, suffix)
+	}
+	e.enumNames[name] = true
+	e.sigEnums[sigEnum.entityID] = name
+
+	values := slices.Clone(sigEnum.values)
+	slices.SortFunc(values, func(a, b *SignalEnumValue) int {
+		return cmp.Compare(a.index, b.index)
+	})
+
+	symEnum := &sym.Enum{Name: name}
+	for _, val := range values {
+		symEnum.Values = append(symEnum.Values, &sym.EnumValue{
+			Index: val.index,
+			Name:  val.name,
+		})
+	}
+	e.symFile.Enums = append(e.symFile.Enums, symEnum)
+
+	return name
+}
@@ -0,0 +1,109 @@
+package acmelib
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportSYMBus(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := importSYMTestFile(assert)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(ExportSYMBus(buf, bus))
+
+	content := buf.String()
+	assert.Contains(content, "Title=\"Powertrain bus\"\n")
+	assert.Contains(content, "{SENDRECEIVE}\n")
+	assert.NotContains(content, "{SEND}\n")
+	assert.Contains(content, `enum Gear(0="Park", 1="Reverse", 2="Neutral", 3="Drive")`)
+	assert.Contains(content, "[Diagnostic]\nID=01ABCDEFh\nType=Extended\nDLC=8\nCycleTime=100\nMux=Page 0,2 1h\n")
+	assert.Contains(content, "Var=Running bit 27,1\n")
+	assert.Contains(content, "Var=Pressure unsigned 32,16 -m /f:0.1 /min:0 /max:6553.5\n")
+
+	// should import the same bus
+	expBus, err := ImportSYMFile(symTestFile, bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(bus.Desc(), expBus.Desc())
+	assert.Equal(bus.Type(), expBus.Type())
+
+	nodeInt := bus.NodeInterfaces()[0]
+	expNodeInt := expBus.NodeInterfaces()[0]
+
+	msgs := nodeInt.SentMessages()
+	expMsgs := expNodeInt.SentMessages()
+	assert.Len(expMsgs, len(msgs))
+
+	for idx, msg := range msgs {
+		expMsg := expMsgs[idx]
+		assert.Equal(msg.Name(), expMsg.Name())
+		assert.Equal(msg.GetCANID(), expMsg.GetCANID())
+		assert.Equal(msg.CANIDFormat(), expMsg.CANIDFormat())
+		assert.Equal(msg.SizeByte(), expMsg.SizeByte())
+		assert.Equal(msg.CycleTime(), expMsg.CycleTime())
+		assert.Equal(msg.Desc(), expMsg.Desc())
+		assertEqualKCDLayouts(assert, msg.SignalLayout(), expMsg.SignalLayout())
+	}
+
+	// should write the same file
+	expBuf := new(bytes.Buffer)
+	assert.NoError(ExportSYMBus(expBuf, expBus))
+	assert.Equal(content, expBuf.String())
+}
+
+func Test_ExportSYMNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open(symTestFile)
+	assert.NoError(err)
+	defer file.Close()
+
+	bus, err := ImportSYMFile("powertrain", file)
+	assert.NoError(err)
+
+	net := NewNetwork("test network")
+	assert.NoError(net.AddBus(bus))
+
+	basePath := t.TempDir()
+	assert.NoError(ExportSYMNetwork(net, basePath))
+
+	_, err = os.Stat(filepath.Join(basePath, "test_network", "powertrain.sym"))
+	assert.NoError(err)
+}
+
+func Test_ExportSYMBus_MultiplexedLayers(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := NewBus("bus")
+	nodeInt := NewNode("node", 0, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := NewMessage("msg", 1, 8)
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	muxor, err := NewMuxorSignal("muxor", 2)
+	assert.NoError(err)
+	muxLayer, err := msg.SignalLayout().AddMultiplexedLayer(muxor, 0)
+	assert.NoError(err)
+	assert.NoError(ExportSYMBus(new(bytes.Buffer), bus))
+
+	// should return an error because the nested multiplexed layer cannot be written
+	innerMuxor, err := NewMuxorSignal("inner_muxor", 2)
+	assert.NoError(err)
+	_, err = muxLayer.GetLayout(0).AddMultiplexedLayer(innerMuxor, 8)
+	assert.NoError(err)
+	assert.ErrorIs(ExportSYMBus(new(bytes.Buffer), bus), ErrInvalidType)
+	assert.NoError(muxLayer.GetLayout(0).DeleteMultiplexedLayer(innerMuxor.EntityID()))
+
+	// should return an error because the message has two multiplexed layers
+	otherMuxor, err := NewMuxorSignal("other_muxor", 2)
+	assert.NoError(err)
+	_, err = msg.SignalLayout().AddMultiplexedLayer(otherMuxor, 16)
+	assert.NoError(err)
+	assert.ErrorIs(ExportSYMBus(new(bytes.Buffer), bus), ErrInvalidType)
+}
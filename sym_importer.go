@@ -0,0 +1,388 @@
+package acmelib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/squadracorsepolito/acmelib/sym"
+)
+
+// ImportSYMFile imports a PCAN symbol file passed as [io.Reader] and converts it
+// to a [Bus]. The given filename will be used as the name of the bus,
+// and the title of the file, if different, as its description.
+//
+// The file does not describe the nodes, so all the messages of the {SEND}, {RECEIVE}
+// and {SENDRECEIVE} sections are sent by the placeholder node used by the DBC files.
+// The sections of a multiplexed message become a [MultiplexedLayer], where
+// the multiplexor value of each section is a layout. The signals repeated
+// in all the sections are inserted into the message outside the layer.
+// The char, string and raw signals are imported as unsigned integers.
+func ImportSYMFile(filename string, r io.Reader) (*Bus, error) {
+	symFile, err := sym.Parse(filename, r)
+	if err != nil {
+		return nil, err
+	}
+	importer := newSYMImporter()
+	return importer.importFile(symFile)
+}
+
+type symFileLocator interface {
+	Location() *sym.Location
+}
+
+type symImporter struct {
+	bus       *Bus
+	dummyNode *NodeInterface
+
+	flagSigType *SignalType
+	signalTypes map[string]*SignalType
+	signalUnits map[string]*SignalUnit
+	signalEnums map[string]*SignalEnum
+}
+
+func newSYMImporter() *symImporter {
+	return &symImporter{
+		flagSigType: NewFlagSignalType("flag_t"),
+		signalTypes: make(map[string]*SignalType),
+		signalUnits: make(map[string]*SignalUnit),
+		signalEnums: make(map[string]*SignalEnum),
+	}
+}
+
+func (i *symImporter) errorf(symLoc symFileLocator, err error) error {
+	return fmt.Errorf("%s : %w", symLoc.Location(), err)
+}
+
+func (i *symImporter) importFile(symFile *sym.File) (*Bus, error) {
+	filename := symFile.Location().Filename
+
+	bus := NewBus(filename)
+	i.bus = bus
+
+	if symFile.Title != filename {
+		bus.SetDesc(symFile.Title)
+	}
+
+	i.importBusType(symFile.Messages)
+
+	for _, symEnum := range symFile.Enums {
+		if err := i.importEnum(symEnum); err != nil {
+			return nil, err
+		}
+	}
+
+	dummyNodeInt := NewNode(dbc.DummyNode, 1024, 1).Interfaces()[0]
+	if err := bus.AddNodeInterface(dummyNodeInt); err != nil {
+		return nil, i.errorf(symFile, err)
+	}
+	i.dummyNode = dummyNodeInt
+
+	// the sections of a multiplexed message have the same name
+	msgNames := []string{}
+	msgSections := make(map[string][]*sym.Message)
+	for _, symMsg := range symFile.Messages {
+		if _, ok := msgSections[symMsg.Name]; !ok {
+			msgNames = append(msgNames, symMsg.Name)
+		}
+		msgSections[symMsg.Name] = append(msgSections[symMsg.Name], symMsg)
+	}
+
+	for _, msgName := range msgNames {
+		if err := i.importMessage(msgSections[msgName]); err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// importBusType sets the type of the bus by looking at the type and at the length
+// of the messages. It must be called before importing the messages because
+// the type of the bus limits the size and the CAN-ID format of the messages.
+func (i *symImporter) importBusType(symMsgs []*sym.Message) {
+	for _, symMsg := range symMsgs {
+		if symMsg.Type.IsFD() || symMsg.Length > 8 {
+			i.bus.SetType(BusTypeCANFD)
+			return
+		}
+	}
+
+	for _, symMsg := range symMsgs {
+		if symMsg.Type.IsExtended() {
+			i.bus.SetType(BusTypeCAN2B)
+			return
+		}
+	}
+}
+
+func (i *symImporter) importEnum(symEnum *sym.Enum) error {
+	if _, ok := i.signalEnums[symEnum.Name]; ok {
+		return i.errorf(symEnum, newNameError(symEnum.Name, ErrIsDuplicated))
+	}
+
+	sigEnum := NewSignalEnum(symEnum.Name)
+	for _, symVal := range symEnum.Values {
+		if _, err := sigEnum.AddValue(symVal.Index, symVal.Name); err != nil {
+			return i.errorf(symEnum, err)
+		}
+	}
+
+	i.signalEnums[symEnum.Name] = sigEnum
+
+	return nil
+}
+
+func (i *symImporter) importMessage(sections []*sym.Message) error {
+	symMsg := sections[0]
+
+	msg := NewMessage(symMsg.Name, MessageID(symMsg.ID), symMsg.Length)
+	msg.SetDesc(symMsg.Desc)
+	msg.SetCycleTime(symMsg.CycleTime)
+
+	if symMsg.Type.IsExtended() {
+		if err := msg.SetCANIDFormat(CANIDFormatExtended); err != nil {
+			return i.errorf(symMsg, err)
+		}
+	}
+
+	if err := msg.SetStaticCANID(CANID(symMsg.ID)); err != nil {
+		return i.errorf(symMsg, err)
+	}
+
+	if symMsg.Mux == nil {
+		if len(sections) > 1 {
+			return i.errorf(sections[1], newNameError(symMsg.Name, ErrIsDuplicated))
+		}
+
+		for _, symSig := range symMsg.Signals {
+			sig, err := i.importSignal(symSig)
+			if err != nil {
+				return err
+			}
+
+			if err := msg.InsertSignal(sig, symSig.StartBit); err != nil {
+				return i.errorf(symSig, err)
+			}
+		}
+	} else if err := i.importMultiplexedSections(msg, sections); err != nil {
+		return err
+	}
+
+	if err := i.dummyNode.AddSentMessage(msg); err != nil {
+		return i.errorf(symMsg, err)
+	}
+
+	return nil
+}
+
+func (i *symImporter) importMultiplexedSections(msg *Message, sections []*sym.Message) error {
+	symMux := sections[0].Mux
+
+	muxor, err := NewMuxorSignal(symMux.Name, getValueFromSize(symMux.Size))
+	if err != nil {
+		return i.errorf(symMux, err)
+	}
+
+	if symMux.BigEndian {
+		muxor.SetEndianness(EndiannessBigEndian)
+	}
+
+	muxLayer, err := msg.layout.AddMultiplexedLayer(muxor, symMux.StartBit)
+	if err != nil {
+		return i.errorf(symMux, err)
+	}
+
+	// the same signal in more sections is inserted once
+	type muxedSignal struct {
+		symSig    *sym.Signal
+		layoutIDs []int
+	}
+
+	muxedSignals := []*muxedSignal{}
+	muxedSignalNames := make(map[string]*muxedSignal)
+
+	for _, section := range sections {
+		mux := section.Mux
+		if mux == nil {
+			return i.errorf(section, errors.New("missing a muxor signal"))
+		}
+
+		if mux.StartBit != symMux.StartBit || mux.Size != symMux.Size {
+			return i.errorf(mux, fmt.Errorf("muxor signal %q : %w", mux.Name, ErrIsDifferent))
+		}
+
+		layoutID := int(mux.Value)
+		for _, symSig := range section.Signals {
+			if muxedSig, ok := muxedSignalNames[symSig.Name]; ok {
+				if !slices.Contains(muxedSig.layoutIDs, layoutID) {
+					muxedSig.layoutIDs = append(muxedSig.layoutIDs, layoutID)
+				}
+				continue
+			}
+
+			muxedSig := &muxedSignal{
+				symSig:    symSig,
+				layoutIDs: []int{layoutID},
+			}
+			muxedSignals = append(muxedSignals, muxedSig)
+			muxedSignalNames[symSig.Name] = muxedSig
+		}
+	}
+
+	for _, muxedSig := range muxedSignals {
+		sig, err := i.importSignal(muxedSig.symSig)
+		if err != nil {
+			return err
+		}
+
+		// a signal sent with every value of the muxor is not multiplexed
+		if len(sections) > 1 && len(muxedSig.layoutIDs) == len(sections) {
+			if err := msg.InsertSignal(sig, muxedSig.symSig.StartBit); err != nil {
+				return i.errorf(muxedSig.symSig, err)
+			}
+			continue
+		}
+
+		if err := muxLayer.InsertSignal(sig, muxedSig.symSig.StartBit, muxedSig.layoutIDs...); err != nil {
+			return i.errorf(muxedSig.symSig, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *symImporter) importSignal(symSig *sym.Signal) (Signal, error) {
+	var sig Signal
+
+	if symSig.Enum != "" {
+		sigEnum, ok := i.signalEnums[symSig.Enum]
+		if !ok {
+			return nil, i.errorf(symSig, newNameError(symSig.Enum, ErrNotFound))
+		}
+
+		enumSig, err := NewEnumSignal(symSig.Name, sigEnum)
+		if err != nil {
+			return nil, i.errorf(symSig, err)
+		}
+
+		// If the size of the signal is bigger than the size of the enum,
+		// set the size of the enum as fixed with the given size
+		if enumSig.Size() < symSig.Size {
+			sigEnum.SetFixedSize(true)
+			if err := sigEnum.UpdateSize(symSig.Size); err != nil {
+				return nil, i.errorf(symSig, err)
+			}
+		}
+
+		sig = enumSig
+
+	} else {
+		sigType, err := i.importSignalType(symSig)
+		if err != nil {
+			return nil, err
+		}
+
+		stdSig, err := NewStandardSignal(symSig.Name, sigType)
+		if err != nil {
+			return nil, i.errorf(symSig, err)
+		}
+
+		if symbol := symSig.Unit; symbol != "" {
+			sigUnit, ok := i.signalUnits[symbol]
+			if !ok {
+				sigUnit = NewSignalUnit(symbol, SignalUnitKindCustom, symbol)
+				i.signalUnits[symbol] = sigUnit
+			}
+			stdSig.SetUnit(sigUnit)
+		}
+
+		sig = stdSig
+	}
+
+	sig.SetDesc(symSig.Desc)
+
+	if symSig.BigEndian {
+		sig.SetEndianness(EndiannessBigEndian)
+	}
+
+	if symSig.Default != nil {
+		sig.SetStartValue(*symSig.Default)
+	}
+
+	return sig, nil
+}
+
+func (i *symImporter) importSignalType(symSig *sym.Signal) (*SignalType, error) {
+	size := symSig.Size
+	signed := symSig.Type == sym.ValueTypeSigned
+	isFloat := symSig.Type == sym.ValueTypeFloat || symSig.Type == sym.ValueTypeDouble
+
+	if size == 1 && !signed && symSig.Factor == 1 && symSig.Offset == 0 && symSig.Min == nil && symSig.Max == nil {
+		return i.flagSigType, nil
+	}
+
+	// the limits of the physical values
+	var min, max float64
+	if isFloat {
+		min, max = getFloatMinMaxFromSize(size)
+	} else {
+		rawMin, rawMax := getMinMaxFromSize(size, signed)
+		min = rawMin*symSig.Factor + symSig.Offset
+		max = rawMax*symSig.Factor + symSig.Offset
+		if min > max {
+			min, max = max, min
+		}
+	}
+
+	if symSig.Min != nil {
+		min = *symSig.Min
+	}
+	if symSig.Max != nil {
+		max = *symSig.Max
+	}
+
+	signStr := "u"
+	if isFloat {
+		signStr = "f"
+	} else if signed {
+		signStr = "s"
+	}
+	sigTypeKey := fmt.Sprintf("%s%d_%g-%g_%g_%g", signStr, size, min, max, symSig.Factor, symSig.Offset)
+
+	if sigType, ok := i.signalTypes[sigTypeKey]; ok {
+		return sigType, nil
+	}
+
+	var sigType *SignalType
+	if isFloat {
+		floatSigType, err := NewFloatSignalType(sigTypeKey, size)
+		if err != nil {
+			return nil, i.errorf(symSig, err)
+		}
+		sigType = floatSigType
+	} else if isDecimal(symSig.Factor) || isDecimal(symSig.Offset) || isDecimal(min) || isDecimal(max) {
+		decSigType, err := NewDecimalSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(symSig, err)
+		}
+		sigType = decSigType
+	} else {
+		intSigType, err := NewIntegerSignalType(sigTypeKey, size, signed)
+		if err != nil {
+			return nil, i.errorf(symSig, err)
+		}
+		sigType = intSigType
+	}
+
+	sigType.SetScale(symSig.Factor)
+	sigType.SetOffset(symSig.Offset)
+	sigType.SetMin(min)
+	sigType.SetMax(max)
+
+	i.signalTypes[sigTypeKey] = sigType
+
+	return sigType, nil
+}
@@ -0,0 +1,161 @@
+package acmelib
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib/dbc"
+	"github.com/stretchr/testify/assert"
+)
+
+const symTestFile = "testdata/sample.sym"
+
+func importSYMTestFile(assert *assert.Assertions) *Bus {
+	file, err := os.Open(symTestFile)
+	assert.NoError(err)
+	defer file.Close()
+
+	bus, err := ImportSYMFile(symTestFile, file)
+	assert.NoError(err)
+
+	return bus
+}
+
+func Test_ImportSYMFile(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := importSYMTestFile(assert)
+	assert.Equal(symTestFile, bus.Name())
+	assert.Equal("Powertrain bus", bus.Desc())
+	assert.Equal(BusTypeCAN2B, bus.Type())
+
+	// should send all the messages from the dummy node
+	assert.Len(bus.NodeInterfaces(), 1)
+	dummyInt, err := bus.GetNodeInterfaceByNodeName(dbc.DummyNode)
+	assert.NoError(err)
+	assert.Len(dummyInt.SentMessages(), 3)
+
+	// engine message
+	engine, err := dummyInt.GetSentMessageByName("Engine")
+	assert.NoError(err)
+	assert.Equal(CANID(0x100), engine.GetCANID())
+	assert.Equal(8, engine.SizeByte())
+	assert.Equal(10, engine.CycleTime())
+	assert.Equal("Engine status", engine.Desc())
+
+	rpm, err := engine.GetSignalByName("RPM")
+	assert.NoError(err)
+	rpmStd, err := rpm.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindDecimal, rpmStd.Type().Kind())
+	assert.Equal(0.25, rpmStd.Type().Scale())
+	assert.Equal(8000.0, rpmStd.Type().Max())
+	assert.Equal("rpm", rpmStd.Unit().Symbol())
+	assert.Equal("Engine speed", rpm.Desc())
+
+	temperature, err := engine.GetSignalByName("Temperature")
+	assert.NoError(err)
+	tempStd, err := temperature.ToStandard()
+	assert.NoError(err)
+	assert.True(tempStd.Type().Signed())
+	assert.Equal(-168.0, tempStd.Type().Min())
+	assert.Equal(87.0, tempStd.Type().Max())
+	assert.Equal(40.0, temperature.StartValue())
+
+	gear, err := engine.GetSignalByName("Gear")
+	assert.NoError(err)
+	gearEnum, err := gear.ToEnum()
+	assert.NoError(err)
+	assert.Equal("Gear", gearEnum.Enum().Name())
+	assert.Equal(3, gear.Size())
+
+	running, err := engine.GetSignalByName("Running")
+	assert.NoError(err)
+	runningStd, err := running.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFlag, runningStd.Type().Kind())
+
+	pressure, err := engine.GetSignalByName("Pressure")
+	assert.NoError(err)
+	assert.Equal(EndiannessBigEndian, pressure.Endianness())
+	assert.Equal(32, pressure.StartPos())
+
+	// logger message
+	logger, err := dummyInt.GetSentMessageByName("Logger")
+	assert.NoError(err)
+	assert.Equal(2, logger.SizeByte())
+	status, err := logger.GetSignalByName("Status")
+	assert.NoError(err)
+	statusEnum, err := status.ToEnum()
+	assert.NoError(err)
+	assert.Equal(4, statusEnum.Size())
+	assert.Equal("Status of the logger", status.Desc())
+
+	// diagnostic message
+	diag, err := dummyInt.GetSentMessageByName("Diagnostic")
+	assert.NoError(err)
+	assert.Equal(CANIDFormatExtended, diag.CANIDFormat())
+	assert.Equal(CANID(0x1ABCDEF), diag.GetCANID())
+	assert.Equal(100, diag.CycleTime())
+
+	// should insert the signal of all the sections outside the layer
+	fault, err := diag.GetSignalByName("Fault")
+	assert.NoError(err)
+	assert.Equal(63, fault.StartPos())
+	assert.Nil(fault.ParentMuxLayer())
+
+	muxLayers := diag.SignalLayout().MultiplexedLayers()
+	assert.Len(muxLayers, 1)
+	muxLayer := muxLayers[0]
+	assert.Equal("Page", muxLayer.Muxor().Name())
+	assert.Equal(4, muxLayer.GetLayoutCount())
+	assert.Equal("Voltage", muxLayer.GetLayout(0).Signals()[0].Name())
+	assert.Equal("Current", muxLayer.GetLayout(1).Signals()[0].Name())
+	assert.Equal(0, muxLayer.GetLayout(2).SignalCount())
+
+	current, err := muxLayer.GetSignalByName("Current")
+	assert.NoError(err)
+	currentStd, err := current.ToStandard()
+	assert.NoError(err)
+	assert.Equal(SignalTypeKindFloat, currentStd.Type().Kind())
+	assert.Equal(32, currentStd.Size())
+}
+
+func Test_ImportSYMFile_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "syntax error",
+			content: "{UNKNOWN}",
+			errMsg:  "syntax error at test.sym:1",
+		},
+		{
+			name:    "undefined enum",
+			content: "{SEND}\n[Engine]\nID=100h\nDLC=8\nVar=Gear unsigned 0,3 /e:Gear",
+			errMsg:  "test.sym:5 : ",
+		},
+		{
+			name:    "overlapping signals",
+			content: "{SEND}\n[Engine]\nID=100h\nDLC=8\nVar=RPM unsigned 0,16\nVar=Speed unsigned 8,16",
+			errMsg:  "test.sym:6 : ",
+		},
+		{
+			name:    "duplicated message",
+			content: "{SEND}\n[Engine]\nID=100h\nDLC=8\n[Engine]\nDLC=8",
+			errMsg:  "test.sym:5 : ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportSYMFile("test.sym", strings.NewReader(tt.content))
+			assert.ErrorContains(err, tt.errMsg)
+		})
+	}
+}
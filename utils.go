@@ -57,10 +57,84 @@ func getMinMaxFromSize(size int, signed bool) (float64, float64) {
 	return 0, float64(max)
 }
 
+func getFloatMinMaxFromSize(size int) (float64, float64) {
+	if size == 32 {
+		return -math.MaxFloat32, math.MaxFloat32
+	}
+	return -math.MaxFloat64, math.MaxFloat64
+}
+
 func isDecimal(val float64) bool {
 	return math.Mod(val, 1.0) != 0
 }
 
+// valueToFloat64 converts a numeric value to a float64.
+// It returns false if the value is not a number.
+func valueToFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+// valueToInt converts a numeric value to an int.
+// It returns false if the value is not a number or if it is a decimal number.
+func valueToInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int8:
+		return int(v), true
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint:
+		return int(v), true
+	case uint8:
+		return int(v), true
+	case uint16:
+		return int(v), true
+	case uint32:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	}
+
+	f, ok := valueToFloat64(value)
+	if !ok || isDecimal(f) {
+		return 0, false
+	}
+
+	return int(f), true
+}
+
 func clearSpaces(str string) string {
 	return strings.ReplaceAll(strings.TrimSpace(str), " ", "_")
 }
@@ -0,0 +1,557 @@
+package acmelib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/squadracorsepolito/acmelib/yamldef"
+)
+
+// LoadNetworkYAML loads the YAML definition of a network passed as [io.Reader]
+// and returns a [Network]. The given filename is used in the locations of the errors.
+//
+// The entity ids are taken from the given [yamldef.EntityIDs], usually read from
+// the sidecar file with [yamldef.ReadEntityIDs], by the path of the entities.
+// The ids of the entities that are missing, or all of them if the entity ids are nil,
+// are generated.
+//
+// The entities are created and added with the functions of the model,
+// so the definition is validated as a network built by hand.
+// The errors are wrapped with the location of the definition that caused them.
+func LoadNetworkYAML(filename string, r io.Reader, entityIDs yamldef.EntityIDs) (*Network, error) {
+	def, err := yamldef.Parse(filename, r)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := newYAMLLoader(entityIDs)
+	return loader.loadNetwork(def)
+}
+
+// yamlPath returns the path of an entity used by the [yamldef.EntityIDs].
+func yamlPath(elems ...string) string {
+	return strings.Join(elems, "/")
+}
+
+// parseYAMLValue returns the value whose string representation is equal to str,
+// or the default value if str is empty.
+func parseYAMLValue[T fmt.Stringer](field, str string, defValue T, values ...T) (T, error) {
+	if str == "" {
+		return defValue, nil
+	}
+
+	for _, val := range values {
+		if val.String() == str {
+			return val, nil
+		}
+	}
+
+	return defValue, newArgError(field, ErrInvalidValue)
+}
+
+type yamlDefinition interface {
+	Location() *yamldef.Location
+}
+
+type yamlLoader struct {
+	entityIDs yamldef.EntityIDs
+
+	nodes    map[string]*Node
+	sigTypes map[string]*SignalType
+	sigUnits map[string]*SignalUnit
+	sigEnums map[string]*SignalEnum
+}
+
+func newYAMLLoader(entityIDs yamldef.EntityIDs) *yamlLoader {
+	return &yamlLoader{
+		entityIDs: entityIDs,
+
+		nodes:    make(map[string]*Node),
+		sigTypes: make(map[string]*SignalType),
+		sigUnits: make(map[string]*SignalUnit),
+		sigEnums: make(map[string]*SignalEnum),
+	}
+}
+
+func (l *yamlLoader) errorf(def yamlDefinition, err error) error {
+	return fmt.Errorf("%s : %w", def.Location(), err)
+}
+
+// setEntityID sets the entity id of the given path, if present.
+// It must be called before the entity is referenced by other entities.
+func (l *yamlLoader) setEntityID(ent *entity, path string) {
+	if id, ok := l.entityIDs[path]; ok && id != "" {
+		ent.entityID = EntityID(id)
+	}
+}
+
+func (l *yamlLoader) loadNetwork(def *yamldef.Network) (*Network, error) {
+	net := NewNetwork(def.Name)
+	net.SetDesc(def.Desc)
+	l.setEntityID(net.entity, "network")
+
+	for _, typDef := range def.Types {
+		if _, ok := l.sigTypes[typDef.Name]; ok {
+			return nil, l.errorf(typDef, newNameError(typDef.Name, ErrIsDuplicated))
+		}
+
+		sigType, err := l.loadSignalType(typDef)
+		if err != nil {
+			return nil, l.errorf(typDef, err)
+		}
+		l.sigTypes[typDef.Name] = sigType
+	}
+
+	for _, unitDef := range def.Units {
+		if _, ok := l.sigUnits[unitDef.Name]; ok {
+			return nil, l.errorf(unitDef, newNameError(unitDef.Name, ErrIsDuplicated))
+		}
+
+		sigUnit, err := l.loadSignalUnit(unitDef)
+		if err != nil {
+			return nil, l.errorf(unitDef, err)
+		}
+		l.sigUnits[unitDef.Name] = sigUnit
+	}
+
+	for _, enumDef := range def.Enums {
+		if _, ok := l.sigEnums[enumDef.Name]; ok {
+			return nil, l.errorf(enumDef, newNameError(enumDef.Name, ErrIsDuplicated))
+		}
+
+		sigEnum, err := l.loadSignalEnum(enumDef)
+		if err != nil {
+			return nil, err
+		}
+		l.sigEnums[enumDef.Name] = sigEnum
+	}
+
+	for _, nodeDef := range def.Nodes {
+		if _, ok := l.nodes[nodeDef.Name]; ok {
+			return nil, l.errorf(nodeDef, newNameError(nodeDef.Name, ErrIsDuplicated))
+		}
+		l.nodes[nodeDef.Name] = l.loadNode(nodeDef)
+	}
+
+	for _, busDef := range def.Buses {
+		if err := l.loadBus(net, busDef); err != nil {
+			return nil, err
+		}
+	}
+
+	return net, nil
+}
+
+func (l *yamlLoader) loadSignalType(def *yamldef.SignalType) (*SignalType, error) {
+	var sigType *SignalType
+
+	switch def.Kind {
+	case SignalTypeKindFlag.String():
+		sigType = NewFlagSignalType(def.Name)
+
+	case SignalTypeKindInteger.String():
+		intSigType, err := NewIntegerSignalType(def.Name, def.Size, def.Signed)
+		if err != nil {
+			return nil, err
+		}
+		sigType = intSigType
+
+	case SignalTypeKindDecimal.String():
+		decSigType, err := NewDecimalSignalType(def.Name, def.Size, def.Signed)
+		if err != nil {
+			return nil, err
+		}
+		sigType = decSigType
+
+	case SignalTypeKindFloat.String():
+		floatSigType, err := NewFloatSignalType(def.Name, def.Size)
+		if err != nil {
+			return nil, err
+		}
+		sigType = floatSigType
+
+	default:
+		return nil, newArgError("kind", ErrInvalidValue)
+	}
+
+	sigType.SetDesc(def.Desc)
+	l.setEntityID(sigType.entity, yamlPath("types", def.Name))
+
+	// the scale and the offset generate the limits,
+	// so they must be set before the limits
+	if def.Scale != nil {
+		sigType.SetScale(*def.Scale)
+	}
+	if def.Offset != 0 {
+		sigType.SetOffset(def.Offset)
+	}
+
+	if def.Min != nil {
+		sigType.SetMin(*def.Min)
+	}
+	if def.Max != nil {
+		sigType.SetMax(*def.Max)
+	}
+
+	return sigType, nil
+}
+
+func (l *yamlLoader) loadSignalUnit(def *yamldef.SignalUnit) (*SignalUnit, error) {
+	kind, err := parseYAMLValue("kind", def.Kind, SignalUnitKindCustom,
+		SignalUnitKindCustom, SignalUnitKindTemperature, SignalUnitKindElectrical, SignalUnitKindPower)
+	if err != nil {
+		return nil, err
+	}
+
+	sigUnit := NewSignalUnit(def.Name, kind, def.Symbol)
+	sigUnit.SetDesc(def.Desc)
+	l.setEntityID(sigUnit.entity, yamlPath("units", def.Name))
+
+	return sigUnit, nil
+}
+
+func (l *yamlLoader) loadSignalEnum(def *yamldef.SignalEnum) (*SignalEnum, error) {
+	sigEnum := NewSignalEnum(def.Name)
+	sigEnum.SetDesc(def.Desc)
+	l.setEntityID(sigEnum.entity, yamlPath("enums", def.Name))
+
+	if def.Size > 0 {
+		sigEnum.SetFixedSize(true)
+		if err := sigEnum.UpdateSize(def.Size); err != nil {
+			return nil, l.errorf(def, err)
+		}
+	}
+
+	for _, valDef := range def.Values {
+		val, err := sigEnum.AddValue(valDef.Index, valDef.Name)
+		if err != nil {
+			return nil, l.errorf(valDef, err)
+		}
+		val.SetDesc(valDef.Desc)
+	}
+
+	return sigEnum, nil
+}
+
+func (l *yamlLoader) loadNode(def *yamldef.Node) *Node {
+	intCount := def.Interfaces
+	if intCount == 0 {
+		intCount = 1
+	}
+
+	node := NewNode(def.Name, NodeID(def.ID), intCount)
+	node.SetDesc(def.Desc)
+	l.setEntityID(node.entity, yamlPath("nodes", def.Name))
+
+	return node
+}
+
+func (l *yamlLoader) loadBus(net *Network, def *yamldef.Bus) error {
+	busPath := yamlPath("buses", def.Name)
+
+	bus := NewBus(def.Name)
+	bus.SetDesc(def.Desc)
+	l.setEntityID(bus.entity, busPath)
+
+	typ, err := parseYAMLValue("type", def.Type, BusTypeCAN2A, BusTypeCAN2A, BusTypeCAN2B, BusTypeCANFD)
+	if err != nil {
+		return l.errorf(def, err)
+	}
+
+	bus.SetType(typ)
+	bus.SetBaudrate(def.Baudrate)
+	bus.SetDataBaudrate(def.DataBaudrate)
+	bus.SetBitRateSwitch(def.BitRateSwitch)
+
+	if err := net.AddBus(bus); err != nil {
+		return l.errorf(def, err)
+	}
+
+	// the node interfaces are added before the messages,
+	// because a message can be received by any node of the bus
+	nodeInts := make([]*NodeInterface, 0, len(def.Nodes))
+	for _, nodeIntDef := range def.Nodes {
+		node, ok := l.nodes[nodeIntDef.Node]
+		if !ok {
+			return l.errorf(nodeIntDef, newNameError(nodeIntDef.Node, ErrNotFound))
+		}
+
+		nodeInt := node.GetInterface(nodeIntDef.Interface)
+		if nodeInt == nil {
+			return l.errorf(nodeIntDef, node.errorf(newIndexError(nodeIntDef.Interface, ErrOutOfBounds)))
+		}
+
+		if err := bus.AddNodeInterface(nodeInt); err != nil {
+			return l.errorf(nodeIntDef, err)
+		}
+
+		nodeInts = append(nodeInts, nodeInt)
+	}
+
+	for idx, nodeIntDef := range def.Nodes {
+		nodePath := yamlPath(busPath, "nodes", nodeIntDef.Node)
+
+		for _, msgDef := range nodeIntDef.Messages {
+			if err := l.loadMessage(nodeInts[idx], nodePath, msgDef); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *yamlLoader) loadMessage(sender *NodeInterface, nodePath string, def *yamldef.Message) error {
+	msgPath := yamlPath(nodePath, "messages", def.Name)
+
+	msg := NewMessage(def.Name, MessageID(def.ID), def.Size)
+	msg.SetDesc(def.Desc)
+	l.setEntityID(msg.entity, msgPath)
+
+	format, err := parseYAMLValue("can_id_format", def.CANIDFormat, CANIDFormatStandard,
+		CANIDFormatStandard, CANIDFormatExtended)
+	if err != nil {
+		return l.errorf(def, err)
+	}
+
+	if err := msg.SetCANIDFormat(format); err != nil {
+		return l.errorf(def, err)
+	}
+
+	if def.CANID != nil {
+		if def.ID != 0 {
+			return l.errorf(def, errors.New("a message with a static CAN-ID cannot have an id"))
+		}
+
+		if err := msg.SetStaticCANID(CANID(*def.CANID)); err != nil {
+			return l.errorf(def, err)
+		}
+	}
+
+	transport, err := parseYAMLValue("transport", def.Transport, MessageTransportNone,
+		MessageTransportNone, MessageTransportJ1939TP, MessageTransportISOTP)
+	if err != nil {
+		return l.errorf(def, err)
+	}
+
+	if err := msg.SetTransport(transport); err != nil {
+		return l.errorf(def, err)
+	}
+
+	// the J1939 messages use the priorities up to 7
+	if def.Priority < 0 || def.Priority > 7 {
+		return l.errorf(def, newArgError("priority", ErrOutOfBounds))
+	}
+
+	sendType, err := parseYAMLValue("send_type", def.SendType, MessageSendTypeUnset,
+		MessageSendTypeUnset, MessageSendTypeCyclic, MessageSendTypeCyclicIfActive,
+		MessageSendTypeCyclicAndTriggered, MessageSendTypeCyclicIfActiveAndTriggered)
+	if err != nil {
+		return l.errorf(def, err)
+	}
+
+	msg.SetPriority(MessagePriority(def.Priority))
+	msg.SetCycleTime(def.CycleTime)
+	msg.SetSendType(sendType)
+	msg.SetDelayTime(def.DelayTime)
+	msg.SetStartDelayTime(def.StartDelayTime)
+
+	if err := l.loadSignals(msg, msgPath, def.Signals); err != nil {
+		return err
+	}
+
+	if err := sender.AddSentMessage(msg); err != nil {
+		return l.errorf(def, err)
+	}
+
+	for _, recName := range def.Receivers {
+		receiver, err := sender.parentBus.GetNodeInterfaceByNodeName(recName)
+		if err != nil {
+			return l.errorf(def, err)
+		}
+
+		if err := msg.AddReceiver(receiver); err != nil {
+			return l.errorf(def, err)
+		}
+	}
+
+	return nil
+}
+
+func (l *yamlLoader) loadSignals(msg *Message, msgPath string, defs []*yamldef.Signal) error {
+	for _, def := range defs {
+		if len(def.Layouts) > 0 {
+			return l.errorf(def, errors.New("the layouts can be set only for the signals of a muxor"))
+		}
+
+		sig, err := l.newSignal(msgPath, def)
+		if err != nil {
+			return err
+		}
+
+		if def.Muxor == nil {
+			if err := msg.InsertSignal(sig, def.Start); err != nil {
+				return l.errorf(def, err)
+			}
+			continue
+		}
+
+		muxor, err := sig.ToMuxor()
+		if err != nil {
+			return l.errorf(def, err)
+		}
+
+		muxLayer, err := msg.layout.AddMultiplexedLayer(muxor, def.Start)
+		if err != nil {
+			return l.errorf(def, err)
+		}
+
+		if err := l.loadMultiplexedSignals(muxLayer, msgPath, def.Muxor.Signals); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *yamlLoader) loadMultiplexedSignals(muxLayer *MultiplexedLayer, msgPath string, defs []*yamldef.Signal) error {
+	for _, def := range defs {
+		sig, err := l.newSignal(msgPath, def)
+		if err != nil {
+			return err
+		}
+
+		if def.Muxor == nil {
+			if err := muxLayer.InsertSignal(sig, def.Start, def.Layouts...); err != nil {
+				return l.errorf(def, err)
+			}
+			continue
+		}
+
+		// a nested muxor is only present in the layout of its layer
+		if len(def.Layouts) != 1 {
+			return l.errorf(def, errors.New("a nested muxor must be in exactly one layout"))
+		}
+
+		muxor, err := sig.ToMuxor()
+		if err != nil {
+			return l.errorf(def, err)
+		}
+
+		layoutID := def.Layouts[0]
+		if err := muxLayer.verifyLayoutID(layoutID); err != nil {
+			return l.errorf(def, muxor.errorf(err))
+		}
+
+		nestedLayer, err := muxLayer.GetLayout(layoutID).AddMultiplexedLayer(muxor, def.Start)
+		if err != nil {
+			return l.errorf(def, err)
+		}
+
+		if err := l.loadMultiplexedSignals(nestedLayer, msgPath, def.Muxor.Signals); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newSignal creates the signal of the definition, that is a muxor signal if it has a muxor.
+func (l *yamlLoader) newSignal(msgPath string, def *yamldef.Signal) (Signal, error) {
+	kindCount := 0
+	if def.Type != "" {
+		kindCount++
+	}
+	if def.Enum != "" {
+		kindCount++
+	}
+	if def.Muxor != nil {
+		kindCount++
+	}
+
+	if kindCount != 1 {
+		return nil, l.errorf(def, errors.New("a signal must have exactly one of type, enum and muxor"))
+	}
+
+	if def.Unit != "" && def.Type == "" {
+		return nil, l.errorf(def, errors.New("a signal can have a unit only with a type"))
+	}
+
+	endianness, err := parseYAMLValue("endianness", def.Endianness, EndiannessLittleEndian,
+		EndiannessLittleEndian, EndiannessBigEndian)
+	if err != nil {
+		return nil, l.errorf(def, err)
+	}
+
+	sendType, err := parseYAMLValue("send_type", def.SendType, SignalSendTypeUnset,
+		SignalSendTypeUnset, SignalSendTypeCyclic, SignalSendTypeOnWrite, SignalSendTypeOnWriteWithRepetition,
+		SignalSendTypeOnChange, SignalSendTypeOnChangeWithRepetition,
+		SignalSendTypeIfActive, SignalSendTypeIfActiveWithRepetition)
+	if err != nil {
+		return nil, l.errorf(def, err)
+	}
+
+	var sig Signal
+
+	switch {
+	case def.Type != "":
+		sigType, ok := l.sigTypes[def.Type]
+		if !ok {
+			return nil, l.errorf(def, newNameError(def.Type, ErrNotFound))
+		}
+
+		base := newSignal(def.Name, SignalKindStandard)
+		l.setEntityID(base.entity, yamlPath(msgPath, "signals", def.Name))
+
+		stdSig, err := newStandardSignalFromBase(base, sigType)
+		if err != nil {
+			return nil, l.errorf(def, err)
+		}
+
+		if def.Unit != "" {
+			sigUnit, ok := l.sigUnits[def.Unit]
+			if !ok {
+				return nil, l.errorf(def, newNameError(def.Unit, ErrNotFound))
+			}
+			stdSig.SetUnit(sigUnit)
+		}
+
+		sig = stdSig
+
+	case def.Enum != "":
+		sigEnum, ok := l.sigEnums[def.Enum]
+		if !ok {
+			return nil, l.errorf(def, newNameError(def.Enum, ErrNotFound))
+		}
+
+		base := newSignal(def.Name, SignalKindEnum)
+		l.setEntityID(base.entity, yamlPath(msgPath, "signals", def.Name))
+
+		enumSig, err := newEnumSignalFromBase(base, sigEnum)
+		if err != nil {
+			return nil, l.errorf(def, err)
+		}
+
+		sig = enumSig
+
+	default:
+		base := newSignal(def.Name, SignalKindMuxor)
+		l.setEntityID(base.entity, yamlPath(msgPath, "signals", def.Name))
+
+		muxor, err := newMuxorSignalFromBase(base, def.Muxor.LayoutCount)
+		if err != nil {
+			return nil, l.errorf(def.Muxor, err)
+		}
+
+		sig = muxor
+	}
+
+	sig.SetDesc(def.Desc)
+	sig.SetEndianness(endianness)
+	sig.SetSendType(sendType)
+	sig.SetStartValue(def.StartValue)
+
+	return sig, nil
+}
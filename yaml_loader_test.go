@@ -0,0 +1,238 @@
+package acmelib
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/squadracorsepolito/acmelib/yamldef"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlTestFile = "testdata/sample.yaml"
+
+func loadYAMLTestFile(assert *assert.Assertions, entityIDs yamldef.EntityIDs) *Network {
+	file, err := os.Open(yamlTestFile)
+	assert.NoError(err)
+	defer file.Close()
+
+	net, err := LoadNetworkYAML(yamlTestFile, file, entityIDs)
+	assert.NoError(err)
+
+	return net
+}
+
+func Test_LoadNetworkYAML(t *testing.T) {
+	assert := assert.New(t)
+
+	net := loadYAMLTestFile(assert, nil)
+	assert.Equal("vehicle", net.Name())
+	assert.Equal("Vehicle network", net.Desc())
+
+	buses := net.Buses()
+	assert.Len(buses, 1)
+	bus := buses[0]
+	assert.Equal("powertrain", bus.Name())
+	assert.Equal(BusTypeCAN2B, bus.Type())
+	assert.Equal(500000, bus.Baudrate())
+
+	nodeInts := bus.NodeInterfaces()
+	assert.Len(nodeInts, 3)
+	assert.Equal("ecu", nodeInts[0].Node().Name())
+	assert.Equal(NodeID(1), nodeInts[0].Node().ID())
+	assert.Equal("Data logger", nodeInts[2].Node().Desc())
+
+	msgs := nodeInts[0].SentMessages()
+	assert.Len(msgs, 2)
+
+	engine := msgs[0]
+	assert.Equal("engine", engine.Name())
+	assert.Equal("Engine status", engine.Desc())
+	assert.Equal(MessageID(16), engine.ID())
+	assert.False(engine.HasStaticCANID())
+	assert.Equal(100, engine.CycleTime())
+	assert.Equal(MessageSendTypeCyclic, engine.SendType())
+	assert.Len(engine.Receivers(), 2)
+	assert.Len(engine.Signals(), 4)
+
+	rpm, err := engine.GetSignalByName("rpm")
+	assert.NoError(err)
+	assert.Equal("Engine speed", rpm.Desc())
+	rpmStd, err := rpm.ToStandard()
+	assert.NoError(err)
+	assert.Equal("rpm_t", rpmStd.Type().Name())
+	assert.Equal(0.25, rpmStd.Type().Scale())
+	assert.Equal(0.0, rpmStd.Type().Min())
+	assert.Equal(8000.0, rpmStd.Type().Max())
+	assert.Equal("rpm", rpmStd.Unit().Symbol())
+
+	temp, err := engine.GetSignalByName("temperature")
+	assert.NoError(err)
+	tempStd, err := temp.ToStandard()
+	assert.NoError(err)
+	assert.Equal(-168.0, tempStd.Type().Min())
+	assert.Equal(87.0, tempStd.Type().Max())
+	assert.Equal(SignalUnitKindTemperature, tempStd.Unit().Kind())
+
+	gear, err := engine.GetSignalByName("gear")
+	assert.NoError(err)
+	gearEnum, err := gear.ToEnum()
+	assert.NoError(err)
+	assert.Equal(4, gearEnum.Size())
+	assert.Len(gearEnum.Enum().Values(), 4)
+	assert.Equal("Forward gear", gearEnum.Enum().GetValue(3).Desc())
+
+	pressure, err := engine.GetSignalByName("pressure")
+	assert.NoError(err)
+	assert.Equal(EndiannessBigEndian, pressure.Endianness())
+	assert.Equal(32, pressure.StartPos())
+
+	diag := msgs[1]
+	assert.Equal("diagnostic", diag.Name())
+	assert.True(diag.HasStaticCANID())
+	assert.Equal(CANID(0x1ABCDEF), diag.GetCANID())
+	assert.Equal(CANIDFormatExtended, diag.CANIDFormat())
+	assert.Len(diag.Receivers(), 1)
+
+	muxLayers := diag.SignalLayout().MultiplexedLayers()
+	assert.Len(muxLayers, 1)
+	muxLayer := muxLayers[0]
+	assert.Equal("page", muxLayer.Muxor().Name())
+	assert.Equal(4, muxLayer.GetLayoutCount())
+	assert.Len(muxLayer.GetLayout(0).Signals(), 1)
+	assert.Len(muxLayer.GetLayout(1).Signals(), 2)
+	assert.Len(muxLayer.GetLayout(2).Signals(), 1)
+	assert.Len(muxLayer.GetLayout(3).Signals(), 0)
+
+	// should share the types
+	counter, err := diag.GetSignalByName("counter")
+	assert.NoError(err)
+	errorCode, err := muxLayer.GetSignalByName("error_code")
+	assert.NoError(err)
+	counterStd, err := counter.ToStandard()
+	assert.NoError(err)
+	errorCodeStd, err := errorCode.ToStandard()
+	assert.NoError(err)
+	assert.Same(counterStd.Type(), errorCodeStd.Type())
+}
+
+func Test_LoadNetworkYAML_EntityIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	entityIDs := yamldef.EntityIDs{
+		"network":         "net_id",
+		"types/counter_t": "counter_id",
+		"nodes/ecu":       "ecu_id",
+		"buses/powertrain/nodes/ecu/messages/engine":                        "engine_id",
+		"buses/powertrain/nodes/ecu/messages/diagnostic/signals/error_code": "error_code_id",
+	}
+
+	net := loadYAMLTestFile(assert, entityIDs)
+	assert.Equal(EntityID("net_id"), net.EntityID())
+
+	nodeInt := net.Buses()[0].NodeInterfaces()[0]
+	assert.Equal(EntityID("ecu_id"), nodeInt.Node().EntityID())
+
+	msgs := nodeInt.SentMessages()
+	assert.Equal(EntityID("engine_id"), msgs[0].EntityID())
+
+	errorCode, err := msgs[1].SignalLayout().MultiplexedLayers()[0].GetSignalByName("error_code")
+	assert.NoError(err)
+	assert.Equal(EntityID("error_code_id"), errorCode.EntityID())
+
+	errorCodeStd, err := errorCode.ToStandard()
+	assert.NoError(err)
+	assert.Equal(EntityID("counter_id"), errorCodeStd.Type().EntityID())
+	assert.Equal(2, errorCodeStd.Type().ReferenceCount())
+
+	// should generate the missing ones
+	assert.NotEmpty(msgs[1].EntityID())
+	assert.NotEqual(msgs[0].EntityID(), msgs[1].EntityID())
+}
+
+func Test_LoadNetworkYAML_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	const header = "name: vehicle\n" +
+		"types:\n" +
+		"  - name: u8\n" +
+		"    kind: integer\n" +
+		"    size: 8\n" +
+		"nodes:\n" +
+		"  - name: ecu\n" +
+		"    id: 1\n" +
+		"  - name: logger\n" +
+		"    id: 1\n" +
+		"buses:\n" +
+		"  - name: body\n" +
+		"    nodes:\n" +
+		"      - node: ecu\n" +
+		"        messages:\n" +
+		"          - name: status\n" +
+		"            id: 1\n" +
+		"            size: 2\n"
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "syntax error",
+			content: "name: vehicle\nbuses:\n  - name: body\n    speed: 500000\n",
+			errMsg:  `syntax error at test.yaml:4:5; unknown field "speed"`,
+		},
+		{
+			name:    "invalid kind",
+			content: "name: vehicle\ntypes:\n  - name: u8\n    kind: unsigned\n",
+			errMsg:  `test.yaml:3:5 : argument error; name:"kind" : invalid value`,
+		},
+		{
+			name:    "duplicated type",
+			content: "name: vehicle\ntypes:\n  - name: f\n    kind: flag\n  - name: f\n    kind: flag\n",
+			errMsg:  `test.yaml:5:5 : name error; name:"f" : is duplicated`,
+		},
+		{
+			name:    "unknown type",
+			content: header + "            signals:\n              - name: a\n                start: 0\n                type: u16\n",
+			errMsg:  `test.yaml:20:17 : name error; name:"u16" : not found`,
+		},
+		{
+			name:    "signals overlap",
+			content: header + "            signals:\n              - name: a\n                start: 0\n                type: u8\n              - name: b\n                start: 4\n                type: u8\n",
+			errMsg:  "test.yaml:23:17 : message error",
+		},
+		{
+			name:    "signal without type",
+			content: header + "            signals:\n              - name: a\n                start: 0\n",
+			errMsg:  "test.yaml:20:17 : a signal must have exactly one of type, enum and muxor",
+		},
+		{
+			name:    "duplicated node id",
+			content: header + "      - node: logger\n",
+			errMsg:  "test.yaml:19:9 : network error",
+		},
+		{
+			name:    "unknown receiver",
+			content: header + "            receivers: [dashboard]\n",
+			errMsg:  "test.yaml:16:13 : network error",
+		},
+		{
+			name:    "invalid priority",
+			content: header + "            priority: 8\n",
+			errMsg:  `test.yaml:16:13 : argument error; name:"priority" : out of bounds`,
+		},
+		{
+			name:    "invalid interface",
+			content: "name: vehicle\nnodes:\n  - name: ecu\n    id: 1\nbuses:\n  - name: body\n    nodes:\n      - node: ecu\n        interface: 1\n",
+			errMsg:  "test.yaml:8:9 : node error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadNetworkYAML("test.yaml", strings.NewReader(tt.content), nil)
+			assert.ErrorContains(err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,401 @@
+package acmelib
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/squadracorsepolito/acmelib/yamldef"
+)
+
+// SaveNetworkYAML saves the given [Network] as a YAML definition into the [io.Writer].
+// It returns the entity ids of the saved entities, that can be written to the
+// sidecar file with [yamldef.WriteEntityIDs] and given back to [LoadNetworkYAML].
+//
+// The output is stable: the types, units and enums are sorted by name, the nodes by id,
+// and the buses, messages and signals follow the order of the model.
+// The values equal to the defaults of the model are omitted.
+// The shared entities with the same name are saved with a numeric suffix,
+// since they are referred by name.
+//
+// The attributes, the CAN-ID builders, the gateway routes
+// and the E2E protections are not part of the definition.
+func SaveNetworkYAML(w io.Writer, network *Network) (yamldef.EntityIDs, error) {
+	saver := newYAMLSaver()
+	def := saver.saveNetwork(network)
+
+	if err := yamldef.Write(w, def); err != nil {
+		return nil, err
+	}
+
+	return saver.entityIDs, nil
+}
+
+// yamlNames assigns a unique name to the entities referred by name in the definition.
+type yamlNames[T Entity] struct {
+	entities []T
+	names    map[EntityID]string
+	used     map[string]bool
+}
+
+func newYAMLNames[T Entity]() *yamlNames[T] {
+	return &yamlNames[T]{
+		entities: []T{},
+		names:    make(map[EntityID]string),
+		used:     make(map[string]bool),
+	}
+}
+
+// get returns the name of the entity, and it assigns one if the entity is new.
+func (yn *yamlNames[T]) get(ent T) string {
+	if name, ok := yn.names[ent.EntityID()]; ok {
+		return name
+	}
+
+	name := ent.Name()
+	for idx := 2; yn.used[name]; idx++ {
+		name = fmt.Sprintf("%s_%d", ent.Name(), idx)
+	}
+
+	yn.entities = append(yn.entities, ent)
+	yn.names[ent.EntityID()] = name
+	yn.used[name] = true
+
+	return name
+}
+
+// sorted returns the entities sorted by the given function.
+func (yn *yamlNames[T]) sorted(cmpFn func(a, b T) int) []T {
+	return slices.SortedStableFunc(slices.Values(yn.entities), cmpFn)
+}
+
+// byName compares the entities by their assigned name.
+func (yn *yamlNames[T]) byName(a, b T) int {
+	return strings.Compare(yn.names[a.EntityID()], yn.names[b.EntityID()])
+}
+
+type yamlSaver struct {
+	entityIDs yamldef.EntityIDs
+
+	nodes    *yamlNames[*Node]
+	sigTypes *yamlNames[*SignalType]
+	sigUnits *yamlNames[*SignalUnit]
+	sigEnums *yamlNames[*SignalEnum]
+}
+
+func newYAMLSaver() *yamlSaver {
+	return &yamlSaver{
+		entityIDs: make(yamldef.EntityIDs),
+
+		nodes:    newYAMLNames[*Node](),
+		sigTypes: newYAMLNames[*SignalType](),
+		sigUnits: newYAMLNames[*SignalUnit](),
+		sigEnums: newYAMLNames[*SignalEnum](),
+	}
+}
+
+func (s *yamlSaver) saveEntityID(ent Entity, path string) {
+	s.entityIDs[path] = ent.EntityID().String()
+}
+
+func (s *yamlSaver) saveNetwork(net *Network) *yamldef.Network {
+	s.saveEntityID(net, "network")
+
+	def := &yamldef.Network{
+		Name: net.name,
+		Desc: net.desc,
+	}
+
+	// the buses are saved first, since they collect the shared entities
+	for _, bus := range net.Buses() {
+		def.Buses = append(def.Buses, s.saveBus(bus))
+	}
+
+	for _, sigType := range s.sigTypes.sorted(s.sigTypes.byName) {
+		def.Types = append(def.Types, s.saveSignalType(sigType))
+	}
+
+	for _, sigUnit := range s.sigUnits.sorted(s.sigUnits.byName) {
+		def.Units = append(def.Units, s.saveSignalUnit(sigUnit))
+	}
+
+	for _, sigEnum := range s.sigEnums.sorted(s.sigEnums.byName) {
+		def.Enums = append(def.Enums, s.saveSignalEnum(sigEnum))
+	}
+
+	sortedNodes := s.nodes.sorted(func(a, b *Node) int {
+		return cmp.Or(cmp.Compare(a.id, b.id), s.nodes.byName(a, b))
+	})
+	for _, node := range sortedNodes {
+		def.Nodes = append(def.Nodes, s.saveNode(node))
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveSignalType(sigType *SignalType) *yamldef.SignalType {
+	name := s.sigTypes.get(sigType)
+	s.saveEntityID(sigType, yamlPath("types", name))
+
+	def := &yamldef.SignalType{
+		Name:   name,
+		Desc:   sigType.desc,
+		Kind:   sigType.kind.String(),
+		Offset: sigType.offset,
+	}
+
+	switch sigType.kind {
+	case SignalTypeKindInteger, SignalTypeKindDecimal:
+		def.Size = sigType.size
+		def.Signed = sigType.signed
+	case SignalTypeKindFloat:
+		def.Size = sigType.size
+	}
+
+	if sigType.scale != 1 {
+		scale := sigType.scale
+		def.Scale = &scale
+	}
+
+	// the limits are omitted when they are the ones
+	// generated from the size, the scale and the offset
+	genType := sigType.Clone()
+	genType.genMinMax()
+
+	if sigType.min != genType.min {
+		min := sigType.min
+		def.Min = &min
+	}
+	if sigType.max != genType.max {
+		max := sigType.max
+		def.Max = &max
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveSignalUnit(sigUnit *SignalUnit) *yamldef.SignalUnit {
+	name := s.sigUnits.get(sigUnit)
+	s.saveEntityID(sigUnit, yamlPath("units", name))
+
+	def := &yamldef.SignalUnit{
+		Name:   name,
+		Desc:   sigUnit.desc,
+		Symbol: sigUnit.symbol,
+	}
+
+	if sigUnit.kind != SignalUnitKindCustom {
+		def.Kind = sigUnit.kind.String()
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveSignalEnum(sigEnum *SignalEnum) *yamldef.SignalEnum {
+	name := s.sigEnums.get(sigEnum)
+	s.saveEntityID(sigEnum, yamlPath("enums", name))
+
+	def := &yamldef.SignalEnum{
+		Name: name,
+		Desc: sigEnum.desc,
+	}
+
+	if sigEnum.fixedSize {
+		def.Size = sigEnum.size
+	}
+
+	for _, val := range sigEnum.Values() {
+		def.Values = append(def.Values, &yamldef.EnumValue{
+			Index: val.index,
+			Name:  val.name,
+			Desc:  val.desc,
+		})
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveNode(node *Node) *yamldef.Node {
+	name := s.nodes.get(node)
+	s.saveEntityID(node, yamlPath("nodes", name))
+
+	def := &yamldef.Node{
+		Name: name,
+		Desc: node.desc,
+		ID:   uint32(node.id),
+	}
+
+	if node.interfaceCount != 1 {
+		def.Interfaces = node.interfaceCount
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveBus(bus *Bus) *yamldef.Bus {
+	busPath := yamlPath("buses", bus.name)
+	s.saveEntityID(bus, busPath)
+
+	def := &yamldef.Bus{
+		Name:          bus.name,
+		Desc:          bus.desc,
+		Baudrate:      bus.baudrate,
+		DataBaudrate:  bus.dataBaudrate,
+		BitRateSwitch: bus.bitRateSwitch,
+	}
+
+	if bus.typ != BusTypeCAN2A {
+		def.Type = bus.typ.String()
+	}
+
+	for _, nodeInt := range bus.NodeInterfaces() {
+		nodeName := s.nodes.get(nodeInt.node)
+		nodePath := yamlPath(busPath, "nodes", nodeName)
+
+		nodeIntDef := &yamldef.NodeInterface{
+			Node:      nodeName,
+			Interface: nodeInt.number,
+		}
+
+		for _, msg := range nodeInt.SentMessages() {
+			nodeIntDef.Messages = append(nodeIntDef.Messages, s.saveMessage(nodePath, msg))
+		}
+
+		def.Nodes = append(def.Nodes, nodeIntDef)
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveMessage(nodePath string, msg *Message) *yamldef.Message {
+	msgPath := yamlPath(nodePath, "messages", msg.name)
+	s.saveEntityID(msg, msgPath)
+
+	def := &yamldef.Message{
+		Name:           msg.name,
+		Desc:           msg.desc,
+		Size:           msg.sizeByte,
+		CycleTime:      msg.cycleTime,
+		DelayTime:      msg.delayTime,
+		StartDelayTime: msg.startDelayTime,
+	}
+
+	if msg.hasStaticCANID {
+		canID := yamldef.CANID(msg.staticCANID)
+		def.CANID = &canID
+	} else {
+		def.ID = uint32(msg.id)
+	}
+
+	if msg.canIDFormat != CANIDFormatStandard {
+		def.CANIDFormat = msg.canIDFormat.String()
+	}
+
+	if msg.transport != MessageTransportNone {
+		def.Transport = msg.transport.String()
+	}
+
+	def.Priority = int(msg.priority)
+
+	if msg.sendType != MessageSendTypeUnset {
+		def.SendType = msg.sendType.String()
+	}
+
+	for _, rec := range msg.Receivers() {
+		def.Receivers = append(def.Receivers, s.nodes.get(rec.node))
+	}
+	slices.Sort(def.Receivers)
+
+	for _, sig := range msg.layout.Signals() {
+		sigDef := s.saveSignal(msgPath, sig)
+
+		if muxLayer, ok := msg.layout.muxLayers.Get(sig.EntityID()); ok {
+			sigDef.Muxor = s.saveMultiplexedLayer(msgPath, muxLayer)
+		}
+
+		def.Signals = append(def.Signals, sigDef)
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveMultiplexedLayer(msgPath string, muxLayer *MultiplexedLayer) *yamldef.Muxor {
+	def := &yamldef.Muxor{
+		LayoutCount: muxLayer.GetLayoutCount(),
+	}
+
+	// the signals present in more layouts are saved once
+	saved := make(map[EntityID]bool)
+	for layoutID, layout := range muxLayer.iterLayouts() {
+		for _, sig := range layout.Signals() {
+			if saved[sig.EntityID()] {
+				continue
+			}
+			saved[sig.EntityID()] = true
+
+			sigDef := s.saveSignal(msgPath, sig)
+
+			// the nested muxors are only present in the layout of their layer
+			if nestedLayer, ok := layout.muxLayers.Get(sig.EntityID()); ok {
+				sigDef.Layouts = []int{layoutID}
+				sigDef.Muxor = s.saveMultiplexedLayer(msgPath, nestedLayer)
+
+				def.Signals = append(def.Signals, sigDef)
+				continue
+			}
+
+			layoutIDs, ok := muxLayer.singalLayoutIDs.Get(sig.EntityID())
+			if ok && len(layoutIDs) < muxLayer.GetLayoutCount() {
+				sigDef.Layouts = slices.Sorted(slices.Values(layoutIDs))
+			}
+
+			def.Signals = append(def.Signals, sigDef)
+		}
+	}
+
+	return def
+}
+
+func (s *yamlSaver) saveSignal(msgPath string, sig Signal) *yamldef.Signal {
+	s.saveEntityID(sig, yamlPath(msgPath, "signals", sig.Name()))
+
+	def := &yamldef.Signal{
+		Name:       sig.Name(),
+		Desc:       sig.Desc(),
+		Start:      sig.StartPos(),
+		StartValue: sig.StartValue(),
+	}
+
+	if sig.Endianness() != EndiannessLittleEndian {
+		def.Endianness = sig.Endianness().String()
+	}
+
+	if sig.SendType() != SignalSendTypeUnset {
+		def.SendType = sig.SendType().String()
+	}
+
+	switch sig.Kind() {
+	case SignalKindStandard:
+		stdSig, err := sig.ToStandard()
+		if err != nil {
+			panic(err)
+		}
+
+		def.Type = s.sigTypes.get(stdSig.typ)
+		if stdSig.unit != nil {
+			def.Unit = s.sigUnits.get(stdSig.unit)
+		}
+
+	case SignalKindEnum:
+		enumSig, err := sig.ToEnum()
+		if err != nil {
+			panic(err)
+		}
+
+		def.Enum = s.sigEnums.get(enumSig.enum)
+	}
+
+	return def
+}
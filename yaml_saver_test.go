@@ -0,0 +1,158 @@
+package acmelib
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SaveNetworkYAML(t *testing.T) {
+	assert := assert.New(t)
+
+	net := loadYAMLTestFile(assert, nil)
+
+	buf := new(bytes.Buffer)
+	entityIDs, err := SaveNetworkYAML(buf, net)
+	assert.NoError(err)
+
+	// should write the same definition of the sample file
+	expContent, err := os.ReadFile(yamlTestFile)
+	assert.NoError(err)
+	assert.Equal(string(expContent), buf.String())
+
+	assert.Equal(net.EntityID().String(), entityIDs["network"])
+
+	engine, err := net.Buses()[0].NodeInterfaces()[0].GetSentMessageByName("engine")
+	assert.NoError(err)
+	assert.Equal(engine.EntityID().String(), entityIDs["buses/powertrain/nodes/ecu/messages/engine"])
+
+	rpm, err := engine.GetSignalByName("rpm")
+	assert.NoError(err)
+	assert.Equal(rpm.EntityID().String(), entityIDs["buses/powertrain/nodes/ecu/messages/engine/signals/rpm"])
+}
+
+func Test_SaveNetworkYAML_RoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	net := initNetwork(assert).net
+
+	buf := new(bytes.Buffer)
+	entityIDs, err := SaveNetworkYAML(buf, net)
+	assert.NoError(err)
+
+	// should load the same network with the same entity ids
+	expNet, err := LoadNetworkYAML("network.yaml", bytes.NewReader(buf.Bytes()), entityIDs)
+	assert.NoError(err)
+	assert.Equal(net.EntityID(), expNet.EntityID())
+
+	buses := net.Buses()
+	expBuses := expNet.Buses()
+	assert.Len(expBuses, len(buses))
+
+	for busIdx, bus := range buses {
+		expBus := expBuses[busIdx]
+		assert.Equal(bus.EntityID(), expBus.EntityID())
+		assert.Equal(bus.Type(), expBus.Type())
+
+		nodeInts := bus.NodeInterfaces()
+		expNodeInts := expBus.NodeInterfaces()
+		assert.Len(expNodeInts, len(nodeInts))
+
+		for nodeIdx, nodeInt := range nodeInts {
+			expNodeInt := expNodeInts[nodeIdx]
+			assert.Equal(nodeInt.Node().EntityID(), expNodeInt.Node().EntityID())
+			assert.Equal(nodeInt.Number(), expNodeInt.Number())
+
+			msgs := nodeInt.SentMessages()
+			expMsgs := expNodeInt.SentMessages()
+			assert.Len(expMsgs, len(msgs))
+
+			for msgIdx, msg := range msgs {
+				expMsg := expMsgs[msgIdx]
+				assert.Equal(msg.EntityID(), expMsg.EntityID())
+				assert.Equal(msg.GetCANID(), expMsg.GetCANID())
+				assert.Equal(msg.Priority(), expMsg.Priority())
+				assert.Len(expMsg.Receivers(), len(msg.Receivers()))
+				assertEqualKCDLayouts(assert, msg.SignalLayout(), expMsg.SignalLayout())
+			}
+		}
+	}
+
+	// should write the same definition
+	expBuf := new(bytes.Buffer)
+	expEntityIDs, err := SaveNetworkYAML(expBuf, expNet)
+	assert.NoError(err)
+	assert.Equal(buf.String(), expBuf.String())
+	assert.Equal(entityIDs, expEntityIDs)
+}
+
+func Test_SaveNetworkYAML_J1939Priority(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("net")
+	bus := NewBus("bus")
+	bus.SetType(BusTypeCAN2B)
+	assert.NoError(net.AddBus(bus))
+
+	nodeInt := NewNode("node", 1, 1).GetInterface(0)
+	assert.NoError(bus.AddNodeInterface(nodeInt))
+
+	msg := NewMessage("msg", 1, 8)
+	msg.SetPriority(6)
+	assert.NoError(msg.SetCANIDFormat(CANIDFormatExtended))
+	assert.NoError(nodeInt.AddSentMessage(msg))
+
+	buf := new(bytes.Buffer)
+	_, err := SaveNetworkYAML(buf, net)
+	assert.NoError(err)
+	assert.Contains(buf.String(), "priority: 6\n")
+
+	// should load the same priority
+	expNet, err := LoadNetworkYAML("net.yaml", bytes.NewReader(buf.Bytes()), nil)
+	assert.NoError(err)
+	expMsg := expNet.Buses()[0].NodeInterfaces()[0].SentMessages()[0]
+	assert.Equal(MessagePriority(6), expMsg.Priority())
+}
+
+func Test_SaveNetworkYAML_DuplicatedNames(t *testing.T) {
+	assert := assert.New(t)
+
+	net := NewNetwork("net")
+	bus := NewBus("bus")
+	assert.NoError(net.AddBus(bus))
+
+	node := NewNode("node", 1, 1)
+	assert.NoError(bus.AddNodeInterface(node.GetInterface(0)))
+
+	msg := NewMessage("msg", 1, 2)
+	assert.NoError(node.GetInterface(0).AddSentMessage(msg))
+
+	// two different types with the same name
+	for idx, name := range []string{"a", "b"} {
+		sigType, err := NewIntegerSignalType("int_t", 4+idx, false)
+		assert.NoError(err)
+		sig, err := NewStandardSignal(name, sigType)
+		assert.NoError(err)
+		assert.NoError(msg.InsertSignal(sig, idx*8))
+	}
+
+	buf := new(bytes.Buffer)
+	_, err := SaveNetworkYAML(buf, net)
+	assert.NoError(err)
+	assert.Contains(buf.String(), "  - name: int_t\n    kind: integer\n    size: 4\n")
+	assert.Contains(buf.String(), "  - name: int_t_2\n    kind: integer\n    size: 5\n")
+
+	// should load the renamed types
+	expNet, err := LoadNetworkYAML("net.yaml", bytes.NewReader(buf.Bytes()), nil)
+	assert.NoError(err)
+
+	expMsg, err := expNet.Buses()[0].NodeInterfaces()[0].GetSentMessageByName("msg")
+	assert.NoError(err)
+	expSig, err := expMsg.GetSignalByName("b")
+	assert.NoError(err)
+	expStdSig, err := expSig.ToStandard()
+	assert.NoError(err)
+	assert.Equal("int_t_2", expStdSig.Type().Name())
+}
@@ -0,0 +1,192 @@
+package yamldef
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Location is the position in the YAML file of a definition.
+type Location struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+func (l *Location) String() string {
+	return fmt.Sprintf("%s:%d:%d", l.Filename, l.Line, l.Column)
+}
+
+type withLocation struct {
+	loc *Location
+}
+
+// Location returns the position of the definition in the file.
+func (wl *withLocation) Location() *Location {
+	return wl.loc
+}
+
+// CANID is a CAN-ID, written as an hexadecimal number.
+type CANID uint32
+
+// MarshalYAML implements the [yaml.Marshaler] interface.
+func (id CANID) MarshalYAML() (any, error) {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!int",
+		Value: fmt.Sprintf("0x%X", uint32(id)),
+	}, nil
+}
+
+// Network is the definition of a network, the root of the file.
+type Network struct {
+	withLocation `yaml:"-"`
+
+	Name string `yaml:"name"`
+	Desc string `yaml:"desc,omitempty"`
+
+	Types []*SignalType `yaml:"types,omitempty"`
+	Units []*SignalUnit `yaml:"units,omitempty"`
+	Enums []*SignalEnum `yaml:"enums,omitempty"`
+
+	Nodes []*Node `yaml:"nodes,omitempty"`
+	Buses []*Bus  `yaml:"buses,omitempty"`
+}
+
+// SignalType is the definition of a signal type shared by the signals.
+// The kind is one of flag, integer, decimal and float.
+// If the scale is missing it is 1, and if the limits are missing
+// they are calculated from the size, the scale and the offset.
+type SignalType struct {
+	withLocation `yaml:"-"`
+
+	Name   string   `yaml:"name"`
+	Desc   string   `yaml:"desc,omitempty"`
+	Kind   string   `yaml:"kind"`
+	Size   int      `yaml:"size,omitempty"`
+	Signed bool     `yaml:"signed,omitempty"`
+	Scale  *float64 `yaml:"scale,omitempty"`
+	Offset float64  `yaml:"offset,omitempty"`
+	Min    *float64 `yaml:"min,omitempty"`
+	Max    *float64 `yaml:"max,omitempty"`
+}
+
+// SignalUnit is the definition of a signal unit shared by the signals.
+// The kind is one of custom, temperature, electrical and power, and if it is missing it is custom.
+type SignalUnit struct {
+	withLocation `yaml:"-"`
+
+	Name   string `yaml:"name"`
+	Desc   string `yaml:"desc,omitempty"`
+	Kind   string `yaml:"kind,omitempty"`
+	Symbol string `yaml:"symbol,omitempty"`
+}
+
+// SignalEnum is the definition of a signal enum shared by the signals.
+// If the size is set, the enum has a fixed size.
+type SignalEnum struct {
+	withLocation `yaml:"-"`
+
+	Name   string       `yaml:"name"`
+	Desc   string       `yaml:"desc,omitempty"`
+	Size   int          `yaml:"size,omitempty"`
+	Values []*EnumValue `yaml:"values,omitempty"`
+}
+
+// EnumValue is a value of a [SignalEnum].
+type EnumValue struct {
+	withLocation `yaml:"-"`
+
+	Index int    `yaml:"index"`
+	Name  string `yaml:"name"`
+	Desc  string `yaml:"desc,omitempty"`
+}
+
+// Node is the definition of a node. If the number of interfaces is missing it is 1.
+type Node struct {
+	withLocation `yaml:"-"`
+
+	Name       string `yaml:"name"`
+	Desc       string `yaml:"desc,omitempty"`
+	ID         uint32 `yaml:"id"`
+	Interfaces int    `yaml:"interfaces,omitempty"`
+}
+
+// Bus is the definition of a bus. The type is one of CAN_2.0A, CAN_2.0B and CAN_FD,
+// and if it is missing it is CAN_2.0A.
+type Bus struct {
+	withLocation `yaml:"-"`
+
+	Name          string           `yaml:"name"`
+	Desc          string           `yaml:"desc,omitempty"`
+	Type          string           `yaml:"type,omitempty"`
+	Baudrate      int              `yaml:"baudrate,omitempty"`
+	DataBaudrate  int              `yaml:"data_baudrate,omitempty"`
+	BitRateSwitch bool             `yaml:"bit_rate_switch,omitempty"`
+	Nodes         []*NodeInterface `yaml:"nodes,omitempty"`
+}
+
+// NodeInterface is an interface of a node connected to a [Bus],
+// with the messages sent through it.
+type NodeInterface struct {
+	withLocation `yaml:"-"`
+
+	Node      string     `yaml:"node"`
+	Interface int        `yaml:"interface,omitempty"`
+	Messages  []*Message `yaml:"messages,omitempty"`
+}
+
+// Message is the definition of a message. A message has either an id,
+// used by the bus to build the CAN-ID, or a static CAN-ID.
+// The priority is a number from 0 (the highest) to 7.
+// The receivers are the names of the nodes connected to the same bus.
+type Message struct {
+	withLocation `yaml:"-"`
+
+	Name           string    `yaml:"name"`
+	Desc           string    `yaml:"desc,omitempty"`
+	ID             uint32    `yaml:"id,omitempty"`
+	CANID          *CANID    `yaml:"can_id,omitempty"`
+	CANIDFormat    string    `yaml:"can_id_format,omitempty"`
+	Size           int       `yaml:"size"`
+	Transport      string    `yaml:"transport,omitempty"`
+	Priority       int       `yaml:"priority,omitempty"`
+	CycleTime      int       `yaml:"cycle_time,omitempty"`
+	SendType       string    `yaml:"send_type,omitempty"`
+	DelayTime      int       `yaml:"delay_time,omitempty"`
+	StartDelayTime int       `yaml:"start_delay_time,omitempty"`
+	Receivers      []string  `yaml:"receivers,omitempty,flow"`
+	Signals        []*Signal `yaml:"signals,omitempty"`
+}
+
+// Signal is the definition of a signal. Exactly one of the type, the enum
+// and the muxor must be set, and the unit can be set only with the type.
+// The start position is the one of the least significant bit, also for the big endian signals.
+//
+// The layouts are set only for the signals of a [Muxor], and they are the ids of the layouts
+// that contain the signal. If they are missing, the signal is in all the layouts.
+type Signal struct {
+	withLocation `yaml:"-"`
+
+	Name       string  `yaml:"name"`
+	Desc       string  `yaml:"desc,omitempty"`
+	Start      int     `yaml:"start"`
+	Endianness string  `yaml:"endianness,omitempty"`
+	SendType   string  `yaml:"send_type,omitempty"`
+	StartValue float64 `yaml:"start_value,omitempty"`
+	Layouts    []int   `yaml:"layouts,omitempty,flow"`
+
+	Type  string `yaml:"type,omitempty"`
+	Unit  string `yaml:"unit,omitempty"`
+	Enum  string `yaml:"enum,omitempty"`
+	Muxor *Muxor `yaml:"muxor,omitempty"`
+}
+
+// Muxor defines the multiplexed layer selected by a muxor [Signal].
+// A nested muxor must be in exactly one layout.
+type Muxor struct {
+	withLocation `yaml:"-"`
+
+	LayoutCount int       `yaml:"layout_count"`
+	Signals     []*Signal `yaml:"signals,omitempty"`
+}
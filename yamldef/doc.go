@@ -0,0 +1,42 @@
+// Package yamldef provides a parser and a writer for the YAML definition of a network,
+// a concise format meant to be written by hand and reviewed in pull requests.
+//
+// The entities are written by name: the buses list the nodes connected to them,
+// the nodes list the messages they send, and the signals refer to the shared types,
+// units and enums by their name. The entity ids are not part of the definition,
+// they can be kept in a separate file with [ReadEntityIDs] and [WriteEntityIDs].
+//
+// An example of a definition is:
+//
+//	name: vehicle
+//	types:
+//	  - name: rpm_t
+//	    kind: decimal
+//	    size: 16
+//	    scale: 0.25
+//	units:
+//	  - name: rpm
+//	    symbol: rpm
+//	nodes:
+//	  - name: ecu
+//	    id: 1
+//	buses:
+//	  - name: powertrain
+//	    baudrate: 500000
+//	    nodes:
+//	      - node: ecu
+//	        messages:
+//	          - name: engine
+//	            id: 16
+//	            size: 8
+//	            signals:
+//	              - name: rpm
+//	                start: 0
+//	                type: rpm_t
+//	                unit: rpm
+//
+// The values that are equal to the defaults of the model can be omitted.
+package yamldef
+
+// FileExtension is the extension of the YAML definition files.
+const FileExtension = ".yaml"
@@ -0,0 +1,38 @@
+package yamldef
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EntityIDs maps the paths of the entities of a network to their entity ids.
+// A path is made of the names of the entity and of its parents, separated by slashes,
+// like "buses/powertrain/nodes/ecu/messages/engine/signals/rpm".
+//
+// It is kept in a separate file, so that the entity ids are not lost
+// when the definition is saved and loaded again.
+type EntityIDs map[string]string
+
+// ReadEntityIDs reads the entity ids written by [WriteEntityIDs] from the [io.Reader].
+func ReadEntityIDs(filename string, r io.Reader) (EntityIDs, error) {
+	ids := make(EntityIDs)
+	if err := yaml.NewDecoder(r).Decode(&ids); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s : %w", filename, err)
+	}
+	return ids, nil
+}
+
+// WriteEntityIDs writes the entity ids into the [io.Writer] as a YAML mapping
+// ordered by path.
+func WriteEntityIDs(w io.Writer, ids EntityIDs) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(map[string]string(ids)); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
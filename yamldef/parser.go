@@ -0,0 +1,280 @@
+package yamldef
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse parses the YAML definition of a network from the [io.Reader].
+// The given filename is used in the locations of the definitions and of the errors.
+//
+// The unknown and duplicated fields, the values of the wrong type
+// and the missing names are reported with their line and column.
+func Parse(filename string, r io.Reader) (*Network, error) {
+	root := new(yaml.Node)
+	if err := yaml.NewDecoder(r).Decode(root); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s : empty file", filename)
+		}
+		return nil, fmt.Errorf("%s : %w", filename, err)
+	}
+
+	net := new(Network)
+	if err := root.Decode(net); err != nil {
+		var synErr *syntaxError
+		if errors.As(err, &synErr) {
+			synErr.loc.Filename = filename
+			return nil, synErr
+		}
+		return nil, fmt.Errorf("%s : %w", filename, err)
+	}
+
+	setFilename(net, filename)
+
+	return net, nil
+}
+
+type syntaxError struct {
+	loc *Location
+	msg string
+}
+
+func newSyntaxError(node *yaml.Node, format string, args ...any) *syntaxError {
+	return &syntaxError{
+		loc: newLocation(node),
+		msg: fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %s; %s", e.loc, e.msg)
+}
+
+func newLocation(node *yaml.Node) *Location {
+	return &Location{Line: node.Line, Column: node.Column}
+}
+
+// decodeMapping decodes the fields of the mapping node into the struct pointed by v,
+// that is matched by the yaml tags of the struct fields.
+// Unlike the decoder of the yaml package, it returns the position of the invalid values.
+func decodeMapping(node *yaml.Node, v any) error {
+	if node.Kind != yaml.MappingNode {
+		return newSyntaxError(node, "expected a mapping")
+	}
+
+	structVal := reflect.ValueOf(v).Elem()
+	structType := structVal.Type()
+
+	fields := make(map[string]reflect.Value)
+	for idx := range structType.NumField() {
+		tag := structType.Field(idx).Tag.Get("yaml")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = structVal.Field(idx)
+	}
+
+	decoded := make(map[string]bool)
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		keyNode := node.Content[idx]
+		valNode := node.Content[idx+1]
+		key := keyNode.Value
+
+		field, ok := fields[key]
+		if !ok {
+			return newSyntaxError(keyNode, "unknown field %q", key)
+		}
+
+		if decoded[key] {
+			return newSyntaxError(keyNode, "duplicated field %q", key)
+		}
+		decoded[key] = true
+
+		// the empty items of a list would be decoded as nil
+		if valNode.Kind == yaml.SequenceNode {
+			for _, itemNode := range valNode.Content {
+				if itemNode.Tag == "!!null" {
+					return newSyntaxError(itemNode, "empty item of field %q", key)
+				}
+			}
+		}
+
+		if err := valNode.Decode(field.Addr().Interface()); err != nil {
+			var synErr *syntaxError
+			if errors.As(err, &synErr) {
+				return err
+			}
+			return newSyntaxError(valNode, "invalid value %q of field %q", valNode.Value, key)
+		}
+	}
+
+	return nil
+}
+
+func requireName(node *yaml.Node, name string) error {
+	if name == "" {
+		return newSyntaxError(node, "missing field \"name\"")
+	}
+	return nil
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (n *Network) UnmarshalYAML(node *yaml.Node) error {
+	type plain Network
+	n.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(n)); err != nil {
+		return err
+	}
+	return requireName(node, n.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (st *SignalType) UnmarshalYAML(node *yaml.Node) error {
+	type plain SignalType
+	st.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(st)); err != nil {
+		return err
+	}
+	return requireName(node, st.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (su *SignalUnit) UnmarshalYAML(node *yaml.Node) error {
+	type plain SignalUnit
+	su.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(su)); err != nil {
+		return err
+	}
+	return requireName(node, su.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (se *SignalEnum) UnmarshalYAML(node *yaml.Node) error {
+	type plain SignalEnum
+	se.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(se)); err != nil {
+		return err
+	}
+	return requireName(node, se.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (ev *EnumValue) UnmarshalYAML(node *yaml.Node) error {
+	type plain EnumValue
+	ev.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(ev)); err != nil {
+		return err
+	}
+	return requireName(node, ev.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (n *Node) UnmarshalYAML(node *yaml.Node) error {
+	type plain Node
+	n.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(n)); err != nil {
+		return err
+	}
+	return requireName(node, n.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (b *Bus) UnmarshalYAML(node *yaml.Node) error {
+	type plain Bus
+	b.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(b)); err != nil {
+		return err
+	}
+	return requireName(node, b.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (ni *NodeInterface) UnmarshalYAML(node *yaml.Node) error {
+	type plain NodeInterface
+	ni.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(ni)); err != nil {
+		return err
+	}
+	if ni.Node == "" {
+		return newSyntaxError(node, "missing field \"node\"")
+	}
+	return nil
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (m *Message) UnmarshalYAML(node *yaml.Node) error {
+	type plain Message
+	m.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(m)); err != nil {
+		return err
+	}
+	return requireName(node, m.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (s *Signal) UnmarshalYAML(node *yaml.Node) error {
+	type plain Signal
+	s.loc = newLocation(node)
+	if err := decodeMapping(node, (*plain)(s)); err != nil {
+		return err
+	}
+	return requireName(node, s.Name)
+}
+
+// UnmarshalYAML implements the [yaml.Unmarshaler] interface.
+func (m *Muxor) UnmarshalYAML(node *yaml.Node) error {
+	type plain Muxor
+	m.loc = newLocation(node)
+	return decodeMapping(node, (*plain)(m))
+}
+
+// setFilename sets the filename of the locations of all the definitions.
+func setFilename(net *Network, filename string) {
+	net.loc.Filename = filename
+
+	for _, typ := range net.Types {
+		typ.loc.Filename = filename
+	}
+
+	for _, unit := range net.Units {
+		unit.loc.Filename = filename
+	}
+
+	for _, enum := range net.Enums {
+		enum.loc.Filename = filename
+		for _, val := range enum.Values {
+			val.loc.Filename = filename
+		}
+	}
+
+	for _, node := range net.Nodes {
+		node.loc.Filename = filename
+	}
+
+	for _, bus := range net.Buses {
+		bus.loc.Filename = filename
+		for _, nodeInt := range bus.Nodes {
+			nodeInt.loc.Filename = filename
+			for _, msg := range nodeInt.Messages {
+				msg.loc.Filename = filename
+				setSignalsFilename(msg.Signals, filename)
+			}
+		}
+	}
+}
+
+func setSignalsFilename(signals []*Signal, filename string) {
+	for _, sig := range signals {
+		sig.loc.Filename = filename
+		if sig.Muxor != nil {
+			sig.Muxor.loc.Filename = filename
+			setSignalsFilename(sig.Muxor.Signals, filename)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package yamldef
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.yaml")
+	assert.NoError(err)
+	defer file.Close()
+
+	net, err := Parse("sample.yaml", file)
+	assert.NoError(err)
+
+	assert.Equal("vehicle", net.Name)
+	assert.Equal("Vehicle network", net.Desc)
+	assert.Equal(&Location{Filename: "sample.yaml", Line: 1, Column: 1}, net.Location())
+
+	assert.Len(net.Types, 6)
+	rpmType := net.Types[3]
+	assert.Equal("rpm_t", rpmType.Name)
+	assert.Equal("decimal", rpmType.Kind)
+	assert.Equal(0.25, *rpmType.Scale)
+	assert.Equal(8000.0, *rpmType.Max)
+	assert.Nil(rpmType.Min)
+	assert.Equal(-40.0, net.Types[4].Offset)
+	assert.Equal(&Location{Filename: "sample.yaml", Line: 14, Column: 5}, rpmType.Location())
+
+	assert.Len(net.Units, 3)
+	assert.Equal("temperature", net.Units[0].Kind)
+
+	assert.Len(net.Enums, 1)
+	gear := net.Enums[0]
+	assert.Equal(4, gear.Size)
+	assert.Len(gear.Values, 4)
+	assert.Equal("Forward gear", gear.Values[3].Desc)
+
+	assert.Len(net.Nodes, 3)
+	assert.Equal(uint32(3), net.Nodes[2].ID)
+
+	assert.Len(net.Buses, 1)
+	bus := net.Buses[0]
+	assert.Equal("CAN_2.0B", bus.Type)
+	assert.Equal(500000, bus.Baudrate)
+	assert.Len(bus.Nodes, 3)
+
+	ecu := bus.Nodes[0]
+	assert.Equal("ecu", ecu.Node)
+	assert.Len(ecu.Messages, 2)
+
+	engine := ecu.Messages[0]
+	assert.Equal(uint32(16), engine.ID)
+	assert.Nil(engine.CANID)
+	assert.Equal([]string{"dashboard", "logger"}, engine.Receivers)
+	assert.Len(engine.Signals, 4)
+	assert.Equal("big-endian", engine.Signals[3].Endianness)
+	assert.Equal("gear", engine.Signals[2].Enum)
+
+	diag := ecu.Messages[1]
+	assert.Equal(CANID(0x1ABCDEF), *diag.CANID)
+	assert.Equal("extended", diag.CANIDFormat)
+	page := diag.Signals[0]
+	assert.Equal(4, page.Muxor.LayoutCount)
+	assert.Len(page.Muxor.Signals, 3)
+	errorCode := page.Muxor.Signals[2]
+	assert.Equal([]int{1, 2}, errorCode.Layouts)
+	assert.Equal("sample.yaml", errorCode.Location().Filename)
+}
+
+func Test_Parse_Errors(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "empty file",
+			content: "",
+			errMsg:  "test.yaml : empty file",
+		},
+		{
+			name:    "invalid yaml",
+			content: "name: [vehicle",
+			errMsg:  "test.yaml : yaml: line 1:",
+		},
+		{
+			name:    "unknown field",
+			content: "name: vehicle\nnodes:\n  - name: ecu\n    node_id: 1\n",
+			errMsg:  `syntax error at test.yaml:4:5; unknown field "node_id"`,
+		},
+		{
+			name:    "duplicated field",
+			content: "name: vehicle\nname: other\n",
+			errMsg:  `syntax error at test.yaml:2:1; duplicated field "name"`,
+		},
+		{
+			name:    "invalid value",
+			content: "name: vehicle\ntypes:\n  - name: u8\n    kind: integer\n    size: eight\n",
+			errMsg:  `syntax error at test.yaml:5:11; invalid value "eight" of field "size"`,
+		},
+		{
+			name:    "missing name",
+			content: "name: vehicle\nbuses:\n  - baudrate: 500000\n",
+			errMsg:  `syntax error at test.yaml:3:5; missing field "name"`,
+		},
+		{
+			name:    "empty item",
+			content: "name: vehicle\nbuses:\n  -\n",
+			errMsg:  `syntax error at test.yaml:3:4; empty item of field "buses"`,
+		},
+		{
+			name:    "not a mapping",
+			content: "name: vehicle\nnodes: ecu\n",
+			errMsg:  `syntax error at test.yaml:2:8; invalid value "ecu" of field "nodes"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("test.yaml", strings.NewReader(tt.content))
+			assert.ErrorContains(err, tt.errMsg)
+		})
+	}
+}
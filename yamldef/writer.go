@@ -0,0 +1,21 @@
+package yamldef
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Write writes the YAML definition of the network into the [io.Writer].
+// The fields are written in the order they are declared,
+// and the empty ones are omitted.
+func Write(w io.Writer, net *Network) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(net); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
@@ -0,0 +1,65 @@
+package yamldef
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Write(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := os.Open("testdata/sample.yaml")
+	assert.NoError(err)
+	defer file.Close()
+
+	net, err := Parse("sample.yaml", file)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(Write(buf, net))
+
+	content := buf.String()
+	assert.True(strings.HasPrefix(content, "name: vehicle\ndesc: Vehicle network\ntypes:\n  - name: counter_t\n    kind: integer\n"))
+	assert.Contains(content, "            receivers: [dashboard, logger]\n")
+	assert.Contains(content, "                      layouts: [1, 2]\n")
+	assert.Contains(content, "      - node: logger\n")
+	assert.Contains(content, "            can_id: 0x1ABCDEF\n")
+	assert.NotContains(content, "desc: \"\"")
+
+	// should parse the same definition
+	expNet, err := Parse("sample.yaml", bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+
+	expBuf := new(bytes.Buffer)
+	assert.NoError(Write(expBuf, expNet))
+	assert.Equal(content, expBuf.String())
+}
+
+func Test_WriteEntityIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	ids := EntityIDs{
+		"nodes/ecu":       "node_id",
+		"buses/body":      "bus_id",
+		"types/counter_t": "type_id",
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(WriteEntityIDs(buf, ids))
+
+	// should be ordered by path
+	assert.Equal("buses/body: bus_id\nnodes/ecu: node_id\ntypes/counter_t: type_id\n", buf.String())
+
+	expIDs, err := ReadEntityIDs("ids.yaml", bytes.NewReader(buf.Bytes()))
+	assert.NoError(err)
+	assert.Equal(ids, expIDs)
+
+	// should read an empty file
+	expIDs, err = ReadEntityIDs("ids.yaml", strings.NewReader(""))
+	assert.NoError(err)
+	assert.Empty(expIDs)
+}